@@ -46,6 +46,7 @@ mod namespace;
 mod netlink;
 mod network;
 mod pci;
+mod ps;
 pub mod random;
 mod sandbox;
 mod signal;
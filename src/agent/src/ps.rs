@@ -0,0 +1,123 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+use std::collections::HashMap;
+use std::fs;
+use std::path::Path;
+
+use anyhow::{Context, Result};
+use protobuf::RepeatedField;
+use protocols::agent::GuestProcess;
+use rustjail::container::LinuxContainer;
+
+const NSEC_PER_SEC: u64 = 1_000_000_000;
+
+// list_processes returns all the processes of the container cgroup, the
+// ones started by the container processes included.
+pub fn list_processes(ctr: &LinuxContainer) -> Result<Vec<GuestProcess>> {
+    let pids = ctr
+        .cgroup_manager
+        .as_ref()
+        .get_pids()
+        .context("get container pids")?;
+
+    let tps = procfs::ticks_per_second()? as u64;
+    let users = container_users(ctr);
+    let exec_ids: HashMap<i32, &str> = ctr
+        .processes
+        .values()
+        .filter(|p| p.exec_id != ctr.id)
+        .map(|p| (p.pid, p.exec_id.as_str()))
+        .collect();
+
+    let mut processes = Vec::new();
+    for pid in pids {
+        // processes can exit while they are listed
+        let proc = match procfs::process::Process::new(pid) {
+            Ok(proc) => proc,
+            Err(_) => continue,
+        };
+
+        let mut p = GuestProcess::new();
+        p.set_pid(pid);
+        p.set_ppid(proc.stat.ppid);
+        p.set_state(proc.stat.state.to_string());
+        p.set_uid(proc.owner);
+        p.set_cpu_time((proc.stat.utime + proc.stat.stime) * NSEC_PER_SEC / tps);
+        p.set_start_time(proc.stat.starttime * NSEC_PER_SEC / tps);
+        p.set_rss(proc.stat.rss_bytes() as u64);
+
+        // kernel threads and zombies have no command line
+        let args = match proc.cmdline() {
+            Ok(args) if !args.is_empty() => args,
+            _ => vec![format!("[{}]", proc.stat.comm)],
+        };
+        p.set_args(RepeatedField::from_vec(args));
+
+        if let Some(user) = users.get(&proc.owner) {
+            p.set_user(user.clone());
+        }
+        if let Some(exec_id) = exec_ids.get(&pid) {
+            p.set_exec_id(exec_id.to_string());
+        }
+
+        processes.push(p);
+    }
+
+    processes.sort_by_key(|p| p.pid);
+
+    Ok(processes)
+}
+
+// container_users returns the user names of the container, as defined by
+// its /etc/passwd file.
+fn container_users(ctr: &LinuxContainer) -> HashMap<u32, String> {
+    let root = match ctr.config.spec.as_ref().and_then(|s| s.root.as_ref()) {
+        Some(root) => root.path.clone(),
+        None => return HashMap::new(),
+    };
+
+    fs::read_to_string(Path::new(&root).join("etc/passwd"))
+        .map(|passwd| parse_passwd(&passwd))
+        .unwrap_or_default()
+}
+
+fn parse_passwd(passwd: &str) -> HashMap<u32, String> {
+    let mut users = HashMap::new();
+
+    for line in passwd.lines() {
+        let fields: Vec<&str> = line.split(':').collect();
+        if fields.len() < 3 || fields[0].is_empty() {
+            continue;
+        }
+
+        if let Ok(uid) = fields[2].parse::<u32>() {
+            users.entry(uid).or_insert_with(|| fields[0].to_string());
+        }
+    }
+
+    users
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_parse_passwd() {
+        let passwd = "root:x:0:0:root:/root:/bin/sh\n\
+                      # comment\n\
+                      \n\
+                      daemon:x:1:1::/:/sbin/nologin\n\
+                      toor:x:0:0::/root:/bin/sh\n\
+                      broken:x:uid:1::/:/bin/sh\n";
+
+        let users = parse_passwd(passwd);
+
+        assert_eq!(users.len(), 2);
+        assert_eq!(users.get(&0), Some(&"root".to_string()));
+        assert_eq!(users.get(&1), Some(&"daemon".to_string()));
+    }
+}
@@ -24,9 +24,9 @@ use oci::{LinuxNamespace, Root, Spec};
 use protobuf::{Message, RepeatedField, SingularPtrField};
 use protocols::agent::{
     AddSwapRequest, AgentDetails, CopyFileRequest, GetIPTablesRequest, GetIPTablesResponse,
-    GuestDetailsResponse, Interfaces, Metrics, OOMEvent, ReadStreamResponse, Routes,
-    SetIPTablesRequest, SetIPTablesResponse, StatsContainerResponse, VolumeStatsRequest,
-    WaitProcessResponse, WriteStreamResponse,
+    GuestDetailsResponse, Interfaces, ListProcessesResponse, Metrics, OOMEvent,
+    ReadStreamResponse, Routes, SetIPTablesRequest, SetIPTablesResponse, StatsContainerResponse,
+    VolumeStatsRequest, WaitProcessResponse, WriteStreamResponse,
 };
 use protocols::csi::{VolumeCondition, VolumeStatsResponse, VolumeUsage, VolumeUsage_Unit};
 use protocols::empty::Empty;
@@ -59,6 +59,7 @@ use crate::mount::{add_storages, baremount, STORAGE_HANDLER_LIST};
 use crate::namespace::{NSTYPEIPC, NSTYPEPID, NSTYPEUTS};
 use crate::network::setup_guest_dns;
 use crate::pci;
+use crate::ps;
 use crate::random;
 use crate::sandbox::Sandbox;
 use crate::version::{AGENT_VERSION, API_VERSION};
@@ -884,6 +885,33 @@ impl agent_ttrpc::AgentService for AgentService {
             .map_err(|e| ttrpc_error!(ttrpc::Code::INTERNAL, e))
     }
 
+    async fn list_processes(
+        &self,
+        ctx: &TtrpcContext,
+        req: protocols::agent::ListProcessesRequest,
+    ) -> ttrpc::Result<ListProcessesResponse> {
+        trace_rpc_call!(ctx, "list_processes", req);
+        is_allowed!(req);
+        let cid = req.container_id;
+        let s = Arc::clone(&self.sandbox);
+        let mut sandbox = s.lock().await;
+
+        let ctr = sandbox.get_container(&cid).ok_or_else(|| {
+            ttrpc_error!(
+                ttrpc::Code::INVALID_ARGUMENT,
+                "invalid container id".to_string(),
+            )
+        })?;
+
+        let processes =
+            ps::list_processes(ctr).map_err(|e| ttrpc_error!(ttrpc::Code::INTERNAL, e))?;
+
+        let mut resp = ListProcessesResponse::new();
+        resp.set_processes(RepeatedField::from_vec(processes));
+
+        Ok(resp)
+    }
+
     async fn pause_container(
         &self,
         ctx: &TtrpcContext,
@@ -6,7 +6,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	shimapi "github.com/containerd/containerd/runtime/v2/shim"
@@ -21,6 +23,23 @@ func shimConfig(config *shimapi.Config) {
 	config.NoSubreaper = true
 }
 
+// shimAction returns the action containerd runs the shim binary with,
+// start or delete, or an empty string for the shim daemon.
+func shimAction(args []string) string {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Bool("debug", false, "")
+	fs.Bool("v", false, "")
+	for _, name := range []string{"namespace", "id", "socket", "bundle", "address", "publish-binary"} {
+		fs.String(name, "", "")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return ""
+	}
+
+	return fs.Arg(0)
+}
+
 func main() {
 
 	if len(os.Args) == 2 && os.Args[1] == "--version" {
@@ -28,5 +47,11 @@ func main() {
 		os.Exit(0)
 	}
 
-	shimapi.Run(types.DefaultKataRuntimeName, shim.New, shimConfig)
+	// Only the shim daemon recovers a sandbox left running by a previous
+	// instance of the shim.
+	opts := shim.Options{
+		Recover: shimAction(os.Args) == "",
+	}
+
+	shimapi.Run(types.DefaultKataRuntimeName, shim.NewWithOptions(opts), shimConfig)
 }
@@ -127,6 +127,11 @@ func main() {
 			desc:    "Get sandbox agent URL.",
 			handler: km.GetAgentURL,
 		},
+		{
+			path:    "/events",
+			desc:    "Stream the events of all Kata Containers sandboxes as server-sent events.",
+			handler: km.ServeEvents,
+		},
 		{
 			path:    "/debug/vars",
 			desc:    "Golang pprof `/debug/vars` endpoint for kata runtime shim process.",
@@ -8,11 +8,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/gogo/protobuf/types"
@@ -20,6 +22,7 @@ import (
 	pb "github.com/kata-containers/kata-containers/src/runtime/protocols/cache"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	vf "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory/template"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 	"golang.org/x/sys/unix"
@@ -31,6 +34,10 @@ var factorySubCmds = []cli.Command{
 	initFactoryCommand,
 	destroyFactoryCommand,
 	statusFactoryCommand,
+	listFactoryCommand,
+	createFactoryCommand,
+	verifyFactoryCommand,
+	deleteFactoryCommand,
 }
 
 var factoryCLICommand = cli.Command{
@@ -60,14 +67,23 @@ func (s *cacheServer) Config(ctx context.Context, empty *types.Empty) (*pb.GrpcV
 		if err != nil {
 			return nil, err
 		}
+		jsonVMConfig.Pools = s.factory.HasPools()
 	}
 
 	return jsonVMConfig, nil
 }
 
-// GetBaseVM requests a paused VM and convert it to gRPC protocol.
-func (s *cacheServer) GetBaseVM(ctx context.Context, empty *types.Empty) (*pb.GrpcVM, error) {
+// GetBaseVM requests a paused VM for the requested config, or for the base
+// factory config if none is set, and convert it to gRPC protocol.
+func (s *cacheServer) GetBaseVM(ctx context.Context, req *pb.GrpcVMConfig) (*pb.GrpcVM, error) {
 	config := s.factory.Config()
+	if len(req.Data) > 0 {
+		reqConfig, err := vc.GrpcToVMConfig(req)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert JSON to VMConfig")
+		}
+		config = *reqConfig
+	}
 
 	vm, err := s.factory.GetBaseVM(ctx, config)
 	if err != nil {
@@ -96,6 +112,7 @@ func (s *cacheServer) Status(ctx context.Context, empty *types.Empty) (*pb.GrpcS
 	stat := pb.GrpcStatus{
 		Pid:      int64(os.Getpid()),
 		Vmstatus: s.factory.GetVMStatus(),
+		Pools:    s.factory.GetPoolStatus(),
 	}
 	return &stat, nil
 }
@@ -160,10 +177,15 @@ var initFactoryCommand = cli.Command{
 		}
 
 		factoryConfig := vf.Config{
-			Template:     runtimeConfig.FactoryConfig.Template,
-			TemplatePath: runtimeConfig.FactoryConfig.TemplatePath,
-			Cache:        runtimeConfig.FactoryConfig.VMCacheNumber,
-			VMCache:      runtimeConfig.FactoryConfig.VMCacheNumber > 0,
+			Template:          runtimeConfig.FactoryConfig.Template,
+			TemplatePath:      runtimeConfig.FactoryConfig.TemplatePath,
+			TemplateStorePath: runtimeConfig.FactoryConfig.TemplateStorePath,
+			Cache:             runtimeConfig.FactoryConfig.VMCacheNumber,
+			CacheMax:          runtimeConfig.FactoryConfig.VMCacheMaxNumber,
+			CacheMaxPools:     runtimeConfig.FactoryConfig.VMCacheMaxPools,
+			CacheMemoryBudget: runtimeConfig.FactoryConfig.VMCacheMemoryBudget,
+			CacheIdleTimeout:  time.Duration(runtimeConfig.FactoryConfig.VMCacheIdleTimeout) * time.Second,
+			VMCache:           runtimeConfig.FactoryConfig.VMCacheNumber > 0,
 			VMConfig: vc.VMConfig{
 				HypervisorType:   runtimeConfig.HypervisorType,
 				HypervisorConfig: runtimeConfig.HypervisorConfig,
@@ -249,8 +271,9 @@ var destroyFactoryCommand = cli.Command{
 			time.Sleep(time.Second)
 		} else if runtimeConfig.FactoryConfig.Template {
 			factoryConfig := vf.Config{
-				Template:     true,
-				TemplatePath: runtimeConfig.FactoryConfig.TemplatePath,
+				Template:          true,
+				TemplatePath:      runtimeConfig.FactoryConfig.TemplatePath,
+				TemplateStorePath: runtimeConfig.FactoryConfig.TemplateStorePath,
 				VMConfig: vc.VMConfig{
 					HypervisorType:   runtimeConfig.HypervisorType,
 					HypervisorConfig: runtimeConfig.HypervisorConfig,
@@ -299,13 +322,18 @@ var statusFactoryCommand = cli.Command{
 					for _, vs := range status.Vmstatus {
 						fmt.Fprintf(defaultOutputFile, "VM pid = %d Cpu = %d Memory = %dMiB\n", vs.Pid, vs.Cpu, vs.Memory)
 					}
+					for _, ps := range status.Pools {
+						fmt.Fprintf(defaultOutputFile, "Pool %s Hypervisor = %s Kernel = %s Image = %s Ready = %d/%d Hits = %d Misses = %d Expired = %d\n",
+							ps.Id, ps.Hypervisor, ps.Kernel, ps.Image, ps.Ready, ps.Target, ps.Hits, ps.Misses, ps.Expired)
+					}
 				}
 			}
 		}
 		if runtimeConfig.FactoryConfig.Template {
 			factoryConfig := vf.Config{
-				Template:     true,
-				TemplatePath: runtimeConfig.FactoryConfig.TemplatePath,
+				Template:          true,
+				TemplatePath:      runtimeConfig.FactoryConfig.TemplatePath,
+				TemplateStorePath: runtimeConfig.FactoryConfig.TemplateStorePath,
 				VMConfig: vc.VMConfig{
 					HypervisorType:   runtimeConfig.HypervisorType,
 					HypervisorConfig: runtimeConfig.HypervisorConfig,
@@ -325,3 +353,172 @@ var statusFactoryCommand = cli.Command{
 		return nil
 	},
 }
+
+// templateStoreConfig returns the runtime configuration, and fails if no
+// template store is configured.
+func templateStoreConfig(c *cli.Context) (oci.RuntimeConfig, error) {
+	runtimeConfig, ok := c.App.Metadata["runtimeConfig"].(oci.RuntimeConfig)
+	if !ok {
+		return runtimeConfig, errors.New("invalid runtime config")
+	}
+
+	if runtimeConfig.FactoryConfig.TemplateStorePath == "" {
+		return runtimeConfig, errors.New("template_store_path is not set in the factory configuration")
+	}
+
+	return runtimeConfig, nil
+}
+
+func templateVMConfig(runtimeConfig oci.RuntimeConfig) vc.VMConfig {
+	return vc.VMConfig{
+		HypervisorType:   runtimeConfig.HypervisorType,
+		HypervisorConfig: runtimeConfig.HypervisorConfig,
+		AgentConfig:      runtimeConfig.AgentConfig,
+	}
+}
+
+var listFactoryCommand = cli.Command{
+	Name:  "list",
+	Usage: "list the VM templates of the template store",
+	Action: func(c *cli.Context) error {
+		runtimeConfig, err := templateStoreConfig(c)
+		if err != nil {
+			return err
+		}
+
+		templates, err := template.NewStore(runtimeConfig.FactoryConfig.TemplateStorePath).List()
+		if err != nil {
+			return err
+		}
+
+		// the key cannot be computed if the assets are missing, no
+		// template is current then
+		current, _ := template.ConfigKey(templateVMConfig(runtimeConfig))
+
+		return formatTemplates(defaultOutputFile, templates, current)
+	},
+}
+
+// formatTemplates writes the templates as a table, marking the template
+// of the current configuration.
+func formatTemplates(w io.Writer, templates []*template.Metadata, current string) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tVERSION\tCREATED\tHYPERVISOR\tMEMORY\tKERNEL\tIMAGE\tCURRENT")
+	for _, t := range templates {
+		image := t.Initrd
+		if image == "" {
+			image = t.Image
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%dMiB\t%s\t%s\t%t\n",
+			t.ID, t.Version, t.Created.Format(time.RFC3339), t.Hypervisor, t.MemorySize, t.Kernel, image, t.ID == current)
+	}
+
+	return tw.Flush()
+}
+
+var createFactoryCommand = cli.Command{
+	Name:  "create",
+	Usage: "create the VM template of the kata-runtime configuration and save it to the template store",
+	Action: func(c *cli.Context) error {
+		ctx, err := cliContextToContext(c)
+		if err != nil {
+			return err
+		}
+
+		runtimeConfig, err := templateStoreConfig(c)
+		if err != nil {
+			return err
+		}
+
+		factoryConfig := vf.Config{
+			Template:          true,
+			TemplatePath:      runtimeConfig.FactoryConfig.TemplatePath,
+			TemplateStorePath: runtimeConfig.FactoryConfig.TemplateStorePath,
+			VMConfig:          templateVMConfig(runtimeConfig),
+		}
+
+		// reuse the template of the configuration if it already exists
+		if _, err := vf.NewFactory(ctx, factoryConfig, true); err != nil {
+			kataLog.WithError(err).Info("no VM template to reuse, creating a new one")
+			if _, err := vf.NewFactory(ctx, factoryConfig, false); err != nil {
+				return err
+			}
+		}
+
+		md, err := template.Save(factoryConfig.VMConfig, factoryConfig.TemplatePath, factoryConfig.TemplateStorePath)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(defaultOutputFile, "VM template %s created\n", md.ID)
+		return nil
+	},
+}
+
+var verifyFactoryCommand = cli.Command{
+	Name:      "verify",
+	Usage:     "verify the checksums of VM templates of the template store",
+	ArgsUsage: "[template-id...]",
+	Action: func(c *cli.Context) error {
+		runtimeConfig, err := templateStoreConfig(c)
+		if err != nil {
+			return err
+		}
+
+		store := template.NewStore(runtimeConfig.FactoryConfig.TemplateStorePath)
+
+		ids := c.Args()
+		if len(ids) == 0 {
+			templates, err := store.List()
+			if err != nil {
+				return err
+			}
+			for _, t := range templates {
+				ids = append(ids, t.ID)
+			}
+		}
+
+		failed := 0
+		for _, id := range ids {
+			if err := store.Verify(id); err != nil {
+				fmt.Fprintf(defaultOutputFile, "%s: %v\n", id, err)
+				failed++
+				continue
+			}
+			fmt.Fprintf(defaultOutputFile, "%s: OK\n", id)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d VM templates failed verification", failed)
+		}
+		return nil
+	},
+}
+
+var deleteFactoryCommand = cli.Command{
+	Name:      "delete",
+	Usage:     "delete VM templates from the template store",
+	ArgsUsage: "template-id...",
+	Action: func(c *cli.Context) error {
+		runtimeConfig, err := templateStoreConfig(c)
+		if err != nil {
+			return err
+		}
+
+		if c.NArg() == 0 {
+			return errors.New("missing template id")
+		}
+
+		store := template.NewStore(runtimeConfig.FactoryConfig.TemplateStorePath)
+		for _, id := range c.Args() {
+			if err := store.Delete(id); err != nil {
+				return err
+			}
+			fmt.Fprintf(defaultOutputFile, "VM template %s deleted\n", id)
+		}
+
+		return nil
+	},
+}
@@ -6,15 +6,18 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli"
 
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory/template"
 )
 
 const testDisabledAsNonRoot = "Test disabled as requires root privileges"
@@ -148,3 +151,77 @@ func TestFactoryCLIFunctionStatus(t *testing.T) {
 	err = fn(ctx)
 	assert.Nil(err)
 }
+
+func TestFactoryCLIFunctionTemplateStore(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir := t.TempDir()
+
+	runtimeConfig, err := newTestRuntimeConfig(tmpdir, true)
+	assert.NoError(err)
+
+	ctx := createCLIContext(flag.NewFlagSet("", 0))
+	ctx.App.Name = "foo"
+	ctx.App.Metadata["runtimeConfig"] = runtimeConfig
+
+	commands := []cli.Command{listFactoryCommand, createFactoryCommand, verifyFactoryCommand, deleteFactoryCommand}
+
+	// No template store
+	for _, command := range commands {
+		fn, ok := command.Action.(func(context *cli.Context) error)
+		assert.True(ok)
+		assert.Error(fn(ctx), command.Name)
+	}
+
+	runtimeConfig.FactoryConfig.TemplateStorePath = tmpdir
+	ctx.App.Metadata["runtimeConfig"] = runtimeConfig
+
+	fn, ok := listFactoryCommand.Action.(func(context *cli.Context) error)
+	assert.True(ok)
+	assert.NoError(fn(ctx))
+
+	// Nothing to verify
+	fn, ok = verifyFactoryCommand.Action.(func(context *cli.Context) error)
+	assert.True(ok)
+	assert.NoError(fn(ctx))
+
+	// Nothing to delete
+	fn, ok = deleteFactoryCommand.Action.(func(context *cli.Context) error)
+	assert.True(ok)
+	assert.Error(fn(ctx))
+}
+
+func TestFormatTemplates(t *testing.T) {
+	assert := assert.New(t)
+
+	created := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	templates := []*template.Metadata{
+		{
+			ID:         "aaaa",
+			Version:    1,
+			Created:    created,
+			Hypervisor: vc.QemuHypervisor,
+			Kernel:     "/usr/share/kata-containers/vmlinux.container",
+			Initrd:     "/usr/share/kata-containers/kata-containers-initrd.img",
+			MemorySize: 2048,
+		},
+		{
+			ID:         "bbbb",
+			Version:    1,
+			Created:    created,
+			Hypervisor: vc.QemuHypervisor,
+			Kernel:     "/usr/share/kata-containers/vmlinux.container",
+			Image:      "/usr/share/kata-containers/kata-containers.img",
+			MemorySize: 2048,
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(formatTemplates(&buf, templates, "bbbb"))
+
+	expected := `ID    VERSION  CREATED               HYPERVISOR  MEMORY   KERNEL                                        IMAGE                                                  CURRENT
+aaaa  1        2026-10-19T12:00:00Z  qemu        2048MiB  /usr/share/kata-containers/vmlinux.container  /usr/share/kata-containers/kata-containers-initrd.img  false
+bbbb  1        2026-10-19T12:00:00Z  qemu        2048MiB  /usr/share/kata-containers/vmlinux.container  /usr/share/kata-containers/kata-containers.img         true
+`
+	assert.Equal(expected, buf.String())
+}
@@ -18,6 +18,7 @@ import "C"
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -28,8 +29,11 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/oci"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	"golang.org/x/sys/unix"
 )
 
 type kernelModule struct {
@@ -85,8 +89,21 @@ var (
 // variables rather than consts to allow tests to modify them
 var (
 	kvmDevice = "/dev/kvm"
+
+	procMaxUserNamespaces       = "/proc/sys/user/max_user_namespaces"
+	procUnprivilegedUsernsClone = "/proc/sys/kernel/unprivileged_userns_clone"
 )
 
+// rootlessLimits are the limits of the sandboxes of unprivileged users.
+var rootlessLimits = []string{
+	"QEMU and virtiofsd run as root of a user namespace, mapped to the user running the runtime",
+	"QEMU is the only supported hypervisor",
+	"the network is only connected through passt, with a lower throughput than a tap device",
+	"no VFIO, vDPA or host block device can be passed to the sandbox",
+	"the sandbox resources are only constrained by cgroups delegated to the user",
+	"no hugepages can be used for the guest memory",
+}
+
 // getCPUInfo returns details of the first CPU read from the specified cpuinfo file
 func getCPUInfo(cpuInfoFile string) (string, error) {
 	text, err := katautils.GetFileContents(cpuInfoFile)
@@ -332,6 +349,10 @@ var kataCheckCLICommand = cli.Command{
 			Name:  "only-list-releases",
 			Usage: "Only list newer available releases (non-root only)",
 		},
+		cli.BoolFlag{
+			Name:  "rootless",
+			Usage: "Only check if unprivileged users can run sandboxes and list the limits of their sandboxes",
+		},
 		cli.BoolFlag{
 			Name:  "strict, s",
 			Usage: "perform strict checking",
@@ -340,6 +361,10 @@ var kataCheckCLICommand = cli.Command{
 			Name:  "verbose, v",
 			Usage: "display the list of checks performed",
 		},
+		cli.BoolFlag{
+			Name:  "verify-assets",
+			Usage: "Only verify the signatures of the hypervisor assets against the asset trust store",
+		},
 	},
 	Description: fmt.Sprintf(`tests if system can run %s and version is current.
 
@@ -372,8 +397,17 @@ EXAMPLES:
 - List all available releases (includes pre-release versions):
 
   $ %s check --only-list-releases --include-all-releases
+
+- Verify the signatures of the hypervisor assets:
+
+  $ sudo %s check --verify-assets
+
+- Check if sandboxes can be run by the current, unprivileged, user:
+
+  $ %s check --rootless
 `,
 		katautils.PROJECT,
+		katautils.NAME,
 		noNetworkEnvVar,
 		katautils.NAME,
 		katautils.NAME,
@@ -381,6 +415,7 @@ EXAMPLES:
 		katautils.NAME,
 		katautils.NAME,
 		katautils.NAME,
+		katautils.NAME,
 	),
 
 	Action: func(context *cli.Context) error {
@@ -389,6 +424,24 @@ EXAMPLES:
 			kataLog.Logger.SetLevel(logrus.InfoLevel)
 		}
 
+		if context.Bool("verify-assets") {
+			runtimeConfig, ok := context.App.Metadata["runtimeConfig"].(oci.RuntimeConfig)
+			if !ok {
+				return errors.New("check: cannot determine runtime config")
+			}
+
+			return checkAssetSignatures(os.Stdout, runtimeConfig.HypervisorConfig)
+		}
+
+		if context.Bool("rootless") {
+			runtimeConfig, ok := context.App.Metadata["runtimeConfig"].(oci.RuntimeConfig)
+			if !ok {
+				return errors.New("check: cannot determine runtime config")
+			}
+
+			return checkRootless(os.Stdout, runtimeConfig)
+		}
+
 		if !context.Bool("no-network-checks") && os.Getenv(noNetworkEnvVar) == "" {
 			cmd := RelCmdCheck
 
@@ -446,6 +499,120 @@ EXAMPLES:
 	},
 }
 
+// checkAssetSignatures reports the signature verification status of each
+// asset of the hypervisor.
+func checkAssetSignatures(w io.Writer, config vc.HypervisorConfig) error {
+	if config.AssetTrustStore == "" {
+		return errors.New("no asset trust store is configured")
+	}
+
+	verifications, err := config.VerifyAssets()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, v := range verifications {
+		if v.Err != nil {
+			failed++
+			fmt.Fprintf(w, "FAIL %s %s: %v\n", v.Type, v.Path, v.Err)
+			continue
+		}
+
+		status := "verified"
+		if v.Cached {
+			status = "verified (cached)"
+		}
+		fmt.Fprintf(w, "OK   %s %s: %s by key %s, sha512 %s\n", v.Type, v.Path, status, v.Key, v.Hash)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d assets failed signature verification", failed, len(verifications))
+	}
+
+	return nil
+}
+
+// checkRootless reports whether the sandboxes of unprivileged users can be
+// run, e.g., by a rootless containerd, and the limits of these sandboxes.
+func checkRootless(w io.Writer, config oci.RuntimeConfig) error {
+	checks := []struct {
+		name  string
+		check func() (string, error)
+	}{
+		{"experimental", func() (string, error) {
+			if err := exp.Check(config.Experimental, exp.UnprivilegedSandbox); err != nil {
+				return "", err
+			}
+			return exp.UnprivilegedSandbox + " is enabled", nil
+		}},
+		{"user namespaces", checkUserNamespaces},
+		{"hypervisor", func() (string, error) {
+			if config.HypervisorType != vc.QemuHypervisor {
+				return "", fmt.Errorf("%s does not support rootless sandboxes", config.HypervisorType)
+			}
+			return string(config.HypervisorType), nil
+		}},
+		{"kvm", func() (string, error) {
+			if err := unix.Access(kvmDevice, unix.R_OK|unix.W_OK); err != nil {
+				return "", fmt.Errorf("%s is not accessible, the user must be in its group: %v", kvmDevice, err)
+			}
+			return kvmDevice + " is accessible", nil
+		}},
+		{"passt", func() (string, error) {
+			if config.PasstPath != "" {
+				return katautils.ResolvePath(config.PasstPath)
+			}
+			return exec.LookPath("passt")
+		}},
+	}
+
+	failed := 0
+	for _, c := range checks {
+		msg, err := c.check()
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "FAIL %s: %v\n", c.name, err)
+			continue
+		}
+		fmt.Fprintf(w, "OK   %s: %s\n", c.name, msg)
+	}
+
+	fmt.Fprintf(w, "\nLimits of rootless sandboxes:\n")
+	for _, limit := range rootlessLimits {
+		fmt.Fprintf(w, "  - %s\n", limit)
+	}
+	fmt.Fprintf(w, "  - the state of the sandboxes is stored in %s\n", filepath.Join(rootless.GetRootlessDir(), "run", "vc"))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rootless checks failed", failed, len(checks))
+	}
+
+	return nil
+}
+
+// checkUserNamespaces checks that unprivileged users can create user
+// namespaces.
+func checkUserNamespaces() (string, error) {
+	data, err := os.ReadFile(procMaxUserNamespaces)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(string(data)) == "0" {
+		return "", fmt.Errorf("user namespaces are disabled by %s", procMaxUserNamespaces)
+	}
+
+	// Some distributions restrict the user namespaces to the privileged
+	// users.
+	data, err = os.ReadFile(procUnprivilegedUsernsClone)
+	if err == nil && strings.TrimSpace(string(data)) == "0" {
+		return "", fmt.Errorf("unprivileged user namespaces are disabled by %s", procUnprivilegedUsernsClone)
+	}
+
+	return "unprivileged users can create user namespaces", nil
+}
+
 func genericArchKernelParamHandler(onVMM bool, fields logrus.Fields, msg string) bool {
 	param, ok := fields["parameter"].(string)
 	if !ok {
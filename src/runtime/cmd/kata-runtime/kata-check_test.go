@@ -7,6 +7,11 @@ package main
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"html/template"
@@ -19,7 +24,10 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/oci"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli"
@@ -875,3 +883,98 @@ func TestArchRequiredKernelModules(t *testing.T) {
 
 	assert.EqualValues(count, expectedCount)
 }
+
+func TestCheckAssetSignatures(t *testing.T) {
+	assert := assert.New(t)
+
+	orgCacheDir := types.AssetVerificationCacheDir
+	defer func() {
+		types.AssetVerificationCacheDir = orgCacheDir
+	}()
+	types.AssetVerificationCacheDir = t.TempDir()
+
+	dir := t.TempDir()
+	config := vc.HypervisorConfig{
+		KernelPath: filepath.Join(dir, "vmlinux"),
+	}
+	assert.NoError(os.WriteFile(config.KernelPath, []byte("kernel"), 0600))
+
+	var buf bytes.Buffer
+
+	// no trust store
+	assert.Error(checkAssetSignatures(&buf, config))
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(err)
+
+	config.AssetTrustStore = filepath.Join(dir, "trust")
+	assert.NoError(os.Mkdir(config.AssetTrustStore, 0700))
+	assert.NoError(os.WriteFile(filepath.Join(config.AssetTrustStore, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	assert.Error(checkAssetSignatures(&buf, config))
+	assert.Contains(buf.String(), "FAIL kernel "+config.KernelPath)
+
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte("kernel")))
+	assert.NoError(os.WriteFile(config.KernelPath+types.SignatureSuffix, []byte(sig), 0600))
+
+	buf.Reset()
+	assert.NoError(checkAssetSignatures(&buf, config))
+	assert.Contains(buf.String(), "OK   kernel "+config.KernelPath+": verified by key key.pem")
+
+	buf.Reset()
+	assert.NoError(checkAssetSignatures(&buf, config))
+	assert.Contains(buf.String(), "verified (cached)")
+}
+
+func TestCheckRootless(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+
+	savedKvmDevice := kvmDevice
+	savedMaxUserNamespaces := procMaxUserNamespaces
+	savedUnprivilegedUsernsClone := procUnprivilegedUsernsClone
+	defer func() {
+		kvmDevice = savedKvmDevice
+		procMaxUserNamespaces = savedMaxUserNamespaces
+		procUnprivilegedUsernsClone = savedUnprivilegedUsernsClone
+	}()
+
+	kvmDevice = filepath.Join(dir, "kvm")
+	procMaxUserNamespaces = filepath.Join(dir, "max_user_namespaces")
+	procUnprivilegedUsernsClone = filepath.Join(dir, "unprivileged_userns_clone")
+
+	passtPath := filepath.Join(dir, "passt")
+	assert.NoError(os.WriteFile(passtPath, []byte("#!/bin/sh\n"), 0700))
+	assert.NoError(os.WriteFile(kvmDevice, nil, 0600))
+	assert.NoError(os.WriteFile(procMaxUserNamespaces, []byte("63459\n"), 0600))
+
+	config := oci.RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		PasstPath:      passtPath,
+		Experimental:   []exp.Feature{*exp.Get(exp.UnprivilegedSandbox)},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(checkRootless(&buf, config))
+	assert.Contains(buf.String(), "OK   experimental: unprivileged_sandbox is enabled")
+	assert.Contains(buf.String(), "OK   user namespaces")
+	assert.Contains(buf.String(), "OK   passt: "+passtPath)
+	assert.Contains(buf.String(), "Limits of rootless sandboxes:")
+	assert.Contains(buf.String(), "stored in "+filepath.Join(dir, "run", "vc"))
+
+	// Debian like restriction of the user namespaces
+	assert.NoError(os.WriteFile(procUnprivilegedUsernsClone, []byte("0\n"), 0600))
+	config.HypervisorType = vc.FirecrackerHypervisor
+	config.Experimental = nil
+
+	buf.Reset()
+	err := checkRootless(&buf, config)
+	assert.EqualError(err, "3 of 5 rootless checks failed")
+	assert.Contains(buf.String(), `FAIL experimental: experimental feature "unprivileged_sandbox" is not enabled`)
+	assert.Contains(buf.String(), "FAIL user namespaces: unprivileged user namespaces are disabled")
+	assert.Contains(buf.String(), "FAIL hypervisor: firecracker does not support rootless sandboxes")
+}
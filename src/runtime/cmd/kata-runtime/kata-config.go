@@ -0,0 +1,200 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/oci"
+	"github.com/urfave/cli"
+)
+
+var kataConfigCLICommand = cli.Command{
+	Name:  "config",
+	Usage: "validate and show the runtime configuration",
+	Subcommands: []cli.Command{
+		configValidateCommand,
+		configShowCommand,
+	},
+	Action: func(context *cli.Context) {
+		cli.ShowSubcommandHelp(context)
+	},
+}
+
+var configValidateCommand = cli.Command{
+	Name:  "validate",
+	Usage: "check the configuration for unknown keys, invalid values, missing assets and conflicting options",
+	Action: func(context *cli.Context) error {
+		configPath, issues, err := katautils.ValidateConfiguration(context.GlobalString("kata-config"))
+		if err != nil {
+			return err
+		}
+
+		return printConfigIssues(defaultOutputFile, configPath, issues)
+	},
+}
+
+var configShowCommand = cli.Command{
+	Name:      "show",
+	Usage:     "show the configuration and where each value comes from",
+	UsageText: "show [--effective [--annotations <file>]] [--format toml|json]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "effective",
+			Usage: "apply the annotations and show the runtime configuration the keys resolve to, defaults included",
+		},
+		cli.StringFlag{
+			Name:  "annotations",
+			Usage: "JSON file of the annotations to apply, either an object or an OCI bundle config.json",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: "toml",
+			Usage: "output format: toml or json",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		format := context.String("format")
+		if format != "toml" && format != "json" {
+			return fmt.Errorf("unknown format %q", format)
+		}
+
+		if context.String("annotations") != "" && !context.Bool("effective") {
+			return errors.New("--annotations requires --effective")
+		}
+
+		shown, err := showConfig(context.GlobalString("kata-config"), context.Bool("effective"), context.String("annotations"))
+		if err != nil {
+			return err
+		}
+
+		return writeShownConfig(defaultOutputFile, shown, format)
+	},
+}
+
+// shownConfig is the output of the config show command.
+type shownConfig struct {
+	// Config is the TOML tables of the configuration files and, for the
+	// effective configuration, the keys the annotations override.
+	Config map[string]interface{} `toml:"config" json:"config"`
+
+	// Origins maps the TOML keys of Config to the file, or to
+	// katautils.AnnotationOrigin, they were set by. The keys which are not
+	// listed are defaults.
+	Origins map[string]string `toml:"origins" json:"origins"`
+
+	// Annotations are the annotations applied to the effective
+	// configuration which do not override a TOML key.
+	Annotations map[string]string `toml:"annotations,omitempty" json:"annotations,omitempty"`
+
+	// Runtime is, for the effective configuration, the runtime
+	// configuration Config resolves to, defaults included.
+	Runtime *oci.RuntimeConfig `toml:"runtime" json:"runtime,omitempty"`
+
+	ConfigPath string `toml:"config_path" json:"config_path"`
+}
+
+func showConfig(configPath string, effective bool, annotationsFile string) (*shownConfig, error) {
+	resolved, tables, origins, err := katautils.DecodeConfigFiles(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	shown := &shownConfig{
+		ConfigPath: resolved,
+		Origins:    origins,
+		Config:     tables,
+	}
+
+	if !effective {
+		return shown, nil
+	}
+
+	_, runtimeConfig, err := katautils.LoadConfiguration(resolved, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if annotationsFile != "" {
+		annotations, err := readAnnotations(annotationsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if runtimeConfig, err = oci.ApplyAnnotations(runtimeConfig, annotations); err != nil {
+			return nil, err
+		}
+
+		others, err := katautils.ApplyAnnotationTables(shown.Config, shown.Origins, annotations)
+		if err != nil {
+			return nil, err
+		}
+		if len(others) > 0 {
+			shown.Annotations = others
+		}
+	}
+
+	shown.Runtime = &runtimeConfig
+
+	return shown, nil
+}
+
+// readAnnotations reads the annotations of the JSON file path, either an
+// object of annotations or an OCI spec.
+func readAnnotations(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec struct {
+		Annotations map[string]string `json:"annotations"`
+	}
+	if err := json.Unmarshal(data, &spec); err == nil && spec.Annotations != nil {
+		return spec.Annotations, nil
+	}
+
+	var annotations map[string]string
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		return nil, fmt.Errorf("invalid annotations file %s: %v", path, err)
+	}
+
+	return annotations, nil
+}
+
+func writeShownConfig(w io.Writer, shown *shownConfig, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(shown)
+	}
+
+	return toml.NewEncoder(w).Encode(shown)
+}
+
+func printConfigIssues(w io.Writer, configPath string, issues []katautils.ConfigIssue) error {
+	errorCount := 0
+	for _, issue := range issues {
+		if !issue.Warning {
+			errorCount++
+		}
+		fmt.Fprintln(w, issue)
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("configuration %s is invalid: %d error(s)", configPath, errorCount)
+	}
+
+	fmt.Fprintf(w, "configuration %s is valid\n", configPath)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	orgVHostVSockDevicePath := utils.VHostVSockDevicePath
+	defer func() {
+		utils.VHostVSockDevicePath = orgVHostVSockDevicePath
+	}()
+	utils.VHostVSockDevicePath = "/dev/null"
+
+	dir := t.TempDir()
+	for _, asset := range []string{"qemu", "vmlinux", "image"} {
+		assert.NoError(os.WriteFile(filepath.Join(dir, asset), []byte(asset), 0600))
+	}
+
+	configPath := filepath.Join(dir, "configuration.toml")
+	assert.NoError(os.WriteFile(configPath, []byte(fmt.Sprintf(`
+[hypervisor.qemu]
+path = "%s/qemu"
+kernel = "%s/vmlinux"
+image = "%s/image"
+shared_fs = "virtio-9p"
+enable_annotations = ["disable_image_nvdimm"]
+`, dir, dir, dir)), 0600))
+
+	shown, err := showConfig(configPath, false, "")
+	assert.NoError(err)
+	assert.Equal(configPath, shown.ConfigPath)
+	assert.Equal(configPath, shown.Origins["hypervisor.qemu.kernel"])
+	assert.Nil(shown.Runtime)
+
+	annotationsFile := filepath.Join(dir, "config.json")
+	assert.NoError(os.WriteFile(annotationsFile, []byte(fmt.Sprintf(`{"ociVersion": "1.0.2", "annotations": {"%s": "true"}}`, vcAnnotations.DisableImageNvdimm)), 0600))
+
+	shown, err = showConfig(configPath, true, annotationsFile)
+	assert.NoError(err)
+	assert.Equal(katautils.AnnotationOrigin, shown.Origins["hypervisor.qemu.disable_image_nvdimm"])
+	assert.Equal(true, shown.Config["hypervisor"].(map[string]interface{})["qemu"].(map[string]interface{})["disable_image_nvdimm"])
+	assert.Empty(shown.Annotations)
+
+	assert.NotNil(shown.Runtime)
+	assert.True(shown.Runtime.HypervisorConfig.DisableImageNvdimm)
+	assert.Equal(filepath.Join(dir, "vmlinux"), shown.Runtime.HypervisorConfig.KernelPath)
+
+	for _, format := range []string{"toml", "json"} {
+		var buf bytes.Buffer
+		assert.NoError(writeShownConfig(&buf, shown, format))
+		assert.Contains(buf.String(), "disable_image_nvdimm")
+	}
+
+	// annotations which are not enabled are rejected
+	assert.NoError(os.WriteFile(annotationsFile, []byte(fmt.Sprintf(`{"%s": "/usr/bin/qemu"}`, vcAnnotations.HypervisorPath)), 0600))
+	_, err = showConfig(configPath, true, annotationsFile)
+	assert.Error(err)
+}
+
+func TestReadAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "annotations.json")
+
+	for _, data := range []string{
+		`{"foo": "bar"}`,
+		`{"ociVersion": "1.0.2", "annotations": {"foo": "bar"}}`,
+	} {
+		assert.NoError(os.WriteFile(path, []byte(data), 0600))
+		annotations, err := readAnnotations(path)
+		assert.NoError(err)
+		assert.Equal(map[string]string{"foo": "bar"}, annotations)
+	}
+
+	assert.NoError(os.WriteFile(path, []byte(`["foo"]`), 0600))
+	_, err := readAnnotations(path)
+	assert.Error(err)
+}
+
+func TestPrintConfigIssues(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	err := printConfigIssues(&buf, "/etc/kata-containers/configuration.toml", []katautils.ConfigIssue{
+		{Key: "factory.vm_cache_number", Message: "warning", Warning: true},
+	})
+	assert.NoError(err)
+	assert.Contains(buf.String(), "is valid")
+
+	buf.Reset()
+	issues := []katautils.ConfigIssue{
+		{File: "/etc/kata-containers/configuration.toml", Key: "hypervisor.qemu.foo", Message: "unknown key"},
+	}
+	err = printConfigIssues(&buf, "/etc/kata-containers/configuration.toml", issues)
+	assert.Error(err)
+	assert.Equal("error: /etc/kata-containers/configuration.toml: hypervisor.qemu.foo: unknown key\n", buf.String())
+}
@@ -29,7 +29,7 @@ import (
 //
 // XXX: Increment for every change to the output format
 // (meaning any change to the EnvInfo type).
-const formatVersion = "1.0.26"
+const formatVersion = "1.0.27"
 
 // MetaInfo stores information on the format of the output itself
 type MetaInfo struct {
@@ -74,16 +74,17 @@ type RuntimeConfigInfo struct {
 
 // RuntimeInfo stores runtime details.
 type RuntimeInfo struct {
-	Config              RuntimeConfigInfo
-	Path                string
-	GuestSeLinuxLabel   string
-	Experimental        []exp.Feature
-	Version             RuntimeVersionInfo
-	Debug               bool
-	Trace               bool
-	DisableGuestSeccomp bool
-	DisableNewNetNs     bool
-	SandboxCgroupOnly   bool
+	Config                RuntimeConfigInfo
+	Path                  string
+	GuestSeLinuxLabel     string
+	Experimental          []exp.Feature
+	AvailableExperimental []exp.Feature
+	Version               RuntimeVersionInfo
+	Debug                 bool
+	Trace                 bool
+	DisableGuestSeccomp   bool
+	DisableNewNetNs       bool
+	SandboxCgroupOnly     bool
 }
 
 type VersionInfo struct {
@@ -178,16 +179,17 @@ func getRuntimeInfo(configFile string, config oci.RuntimeConfig) RuntimeInfo {
 	runtimePath, _ := os.Executable()
 
 	return RuntimeInfo{
-		Debug:               config.Debug,
-		Trace:               config.Trace,
-		Version:             runtimeVersion,
-		Config:              runtimeConfig,
-		Path:                runtimePath,
-		DisableNewNetNs:     config.DisableNewNetNs,
-		SandboxCgroupOnly:   config.SandboxCgroupOnly,
-		Experimental:        config.Experimental,
-		DisableGuestSeccomp: config.DisableGuestSeccomp,
-		GuestSeLinuxLabel:   config.GuestSeLinuxLabel,
+		Debug:                 config.Debug,
+		Trace:                 config.Trace,
+		Version:               runtimeVersion,
+		Config:                runtimeConfig,
+		Path:                  runtimePath,
+		DisableNewNetNs:       config.DisableNewNetNs,
+		SandboxCgroupOnly:     config.SandboxCgroupOnly,
+		Experimental:          config.Experimental,
+		AvailableExperimental: exp.Supported(),
+		DisableGuestSeccomp:   config.DisableGuestSeccomp,
+		GuestSeLinuxLabel:     config.GuestSeLinuxLabel,
 	}
 }
 
@@ -20,6 +20,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
 	vcUtils "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/urfave/cli"
@@ -310,10 +311,12 @@ func getExpectedRuntimeDetails(config oci.RuntimeConfig, configFile string) Runt
 		Config: RuntimeConfigInfo{
 			Path: configFile,
 		},
-		Path:            runtimePath,
-		Debug:           config.Debug,
-		Trace:           config.Trace,
-		DisableNewNetNs: config.DisableNewNetNs,
+		Path:                  runtimePath,
+		Debug:                 config.Debug,
+		Trace:                 config.Trace,
+		DisableNewNetNs:       config.DisableNewNetNs,
+		Experimental:          config.Experimental,
+		AvailableExperimental: exp.Supported(),
 	}
 }
 
@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	containerdshim "github.com/kata-containers/kata-containers/src/runtime/pkg/containerd-shim-v2"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/utils/shimclient"
+	"github.com/urfave/cli"
+)
+
+// freezeTimeout leaves the guest the time to inflate the memory balloon.
+const freezeTimeout = 30 * time.Second
+
+var kataFreezeCLICommand = cli.Command{
+	Name:      "freeze",
+	Usage:     "pause all the containers of a sandbox and stop the vCPUs of its VM",
+	UsageText: "freeze [--reclaim-memory] <sandbox id>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "reclaim-memory",
+			Usage: "balloon the guest memory down to the freeze_memory of the hypervisor",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		sandboxID := context.Args().First()
+		if err := katautils.VerifyContainerID(sandboxID); err != nil {
+			return err
+		}
+
+		encoded, err := json.Marshal(containerdshim.FreezeRequest{
+			ReclaimMemory: context.Bool("reclaim-memory"),
+		})
+		if err != nil {
+			return err
+		}
+
+		return shimclient.DoPut(sandboxID, freezeTimeout, containerdshim.FreezeUrl, "application/json", encoded)
+	},
+}
+
+var kataThawCLICommand = cli.Command{
+	Name:      "thaw",
+	Usage:     "thaw a frozen sandbox",
+	UsageText: "thaw <sandbox id>",
+	Action: func(context *cli.Context) error {
+		sandboxID := context.Args().First()
+		if err := katautils.VerifyContainerID(sandboxID); err != nil {
+			return err
+		}
+
+		return shimclient.DoPut(sandboxID, freezeTimeout, containerdshim.ThawUrl, "application/json", nil)
+	},
+}
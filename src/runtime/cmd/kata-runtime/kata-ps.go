@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	containerdshim "github.com/kata-containers/kata-containers/src/runtime/pkg/containerd-shim-v2"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/utils/shimclient"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+	"github.com/urfave/cli"
+)
+
+var kataPsCLICommand = cli.Command{
+	Name:      "ps",
+	Usage:     "list the processes running in a container, as seen from the guest",
+	UsageText: "ps [--json] <sandbox id> <container id>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "print the processes as JSON",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		sandboxID := context.Args().Get(0)
+		containerID := context.Args().Get(1)
+
+		if err := katautils.VerifyContainerID(sandboxID); err != nil {
+			return err
+		}
+		if err := katautils.VerifyContainerID(containerID); err != nil {
+			return err
+		}
+
+		body, err := shimclient.DoGet(sandboxID, defaultTimeout,
+			fmt.Sprintf("%s?%s=%s", containerdshim.ProcessesUrl, containerdshim.ContainerIDKey, url.QueryEscape(containerID)))
+		if err != nil {
+			return err
+		}
+
+		if context.Bool("json") {
+			fmt.Println(string(body))
+			return nil
+		}
+
+		var processes []*grpc.GuestProcess
+		if err := json.Unmarshal(body, &processes); err != nil {
+			return err
+		}
+
+		return formatProcesses(os.Stdout, processes)
+	},
+}
+
+// formatProcesses writes processes as a table similar to the ps(1) one.
+func formatProcesses(w io.Writer, processes []*grpc.GuestProcess) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "PID\tPPID\tUSER\tSTAT\tTIME\tRSS\tEXEC\tCMD")
+	for _, p := range processes {
+		user := p.User
+		if user == "" {
+			user = fmt.Sprint(p.Uid)
+		}
+
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			p.Pid, p.Ppid, user, p.State, formatCPUTime(p.CpuTime), p.Rss/1024, p.ExecId, strings.Join(p.Args, " "))
+	}
+
+	return tw.Flush()
+}
+
+// formatCPUTime formats a CPU time in nanoseconds as [DD-]HH:MM:SS.
+func formatCPUTime(ns uint64) string {
+	d := time.Duration(ns)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+
+	t := fmt.Sprintf("%02d:%02d:%02d", d/time.Hour, (d%time.Hour)/time.Minute, (d%time.Minute)/time.Second)
+	if days > 0 {
+		t = fmt.Sprintf("%d-%s", days, t)
+	}
+
+	return t
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCPUTime(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("00:00:00", formatCPUTime(0))
+	assert.Equal("01:02:03", formatCPUTime(uint64(time.Hour+2*time.Minute+3*time.Second)))
+	assert.Equal("2-00:00:01", formatCPUTime(uint64(48*time.Hour+time.Second)))
+}
+
+func TestFormatProcesses(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	err := formatProcesses(&buf, []*grpc.GuestProcess{
+		{Pid: 1, Ppid: 0, User: "root", State: "S", Rss: 4096 * 1024, Args: []string{"sleep", "infinity"}},
+		{Pid: 12, Ppid: 1, Uid: 1000, State: "R", ExecId: "exec-1", Args: []string{"top"}},
+	})
+	assert.NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(lines, 3)
+	assert.Equal([]string{"PID", "PPID", "USER", "STAT", "TIME", "RSS", "EXEC", "CMD"}, strings.Fields(lines[0]))
+	assert.Equal([]string{"1", "0", "root", "S", "00:00:00", "4096", "sleep", "infinity"}, strings.Fields(lines[1]))
+	assert.Equal([]string{"12", "1", "1000", "R", "00:00:00", "0", "exec-1", "top"}, strings.Fields(lines[2]))
+}
@@ -11,6 +11,7 @@ import (
 	"net/url"
 
 	containerdshim "github.com/kata-containers/kata-containers/src/runtime/pkg/containerd-shim-v2"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	volume "github.com/kata-containers/kata-containers/src/runtime/pkg/direct-volume"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/utils/shimclient"
 
@@ -22,12 +23,14 @@ var volumeSubCmds = []cli.Command{
 	removeCommand,
 	statsCommand,
 	resizeCommand,
+	rateLimitCommand,
 }
 
 var (
-	mountInfo  string
-	volumePath string
-	size       uint64
+	mountInfo   string
+	volumePath  string
+	size        uint64
+	rateLimiter config.BlockRateLimiter
 )
 
 var kataVolumeCommand = cli.Command{
@@ -41,7 +44,7 @@ var kataVolumeCommand = cli.Command{
 
 var addCommand = cli.Command{
 	Name:  "add",
-	Usage: "add a direct assigned block volume device to the Kata Containers runtime",
+	Usage: "add a direct assigned block device, image file, vhost-user-blk or NFS volume to the Kata Containers runtime",
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:        "volume-path",
@@ -55,7 +58,7 @@ var addCommand = cli.Command{
 		},
 	},
 	Action: func(c *cli.Context) error {
-		if err := volume.Add(volumePath, mountInfo); err != nil {
+		if err := Add(volumePath, mountInfo); err != nil {
 			return cli.NewExitError(err.Error(), 1)
 		}
 		return nil
@@ -124,6 +127,61 @@ var resizeCommand = cli.Command{
 	},
 }
 
+var rateLimitCommand = cli.Command{
+	Name:  "rate-limit",
+	Usage: "update the I/O limits of a direct assigned block volume",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:        "volume-path",
+			Usage:       "the target volume path the volume is published to",
+			Destination: &volumePath,
+		},
+		cli.Int64Flag{
+			Name:        "bw-max-rate",
+			Usage:       "the bandwidth limit in bits/sec, 0 means unlimited",
+			Destination: &rateLimiter.BwMaxRate,
+		},
+		cli.Int64Flag{
+			Name:        "bw-one-time-burst",
+			Usage:       "the bandwidth burst in bits",
+			Destination: &rateLimiter.BwOneTimeBurst,
+		},
+		cli.Int64Flag{
+			Name:        "ops-max-rate",
+			Usage:       "the operations limit in ops/sec, 0 means unlimited",
+			Destination: &rateLimiter.OpsMaxRate,
+		},
+		cli.Int64Flag{
+			Name:        "ops-one-time-burst",
+			Usage:       "the operations burst",
+			Destination: &rateLimiter.OpsOneTimeBurst,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if err := RateLimit(volumePath, rateLimiter); err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		return nil
+	},
+}
+
+// Add checks that the device of a direct volume matches its type, and
+// records the volume for the Kata Containers runtime.
+func Add(volumePath string, mountInfo string) error {
+	var info volume.MountInfo
+	if err := json.Unmarshal([]byte(mountInfo), &info); err != nil {
+		return err
+	}
+	if err := info.Validate(); err != nil {
+		return err
+	}
+	if err := info.CheckDevice(); err != nil {
+		return fmt.Errorf("invalid %s volume: %w", info.Type(), err)
+	}
+
+	return volume.Add(volumePath, mountInfo)
+}
+
 // Stats retrieves the filesystem stats of the direct volume inside the guest.
 func Stats(volumePath string) ([]byte, error) {
 	sandboxId, err := volume.GetSandboxIdForVolume(volumePath)
@@ -165,3 +223,25 @@ func Resize(volumePath string, size uint64) error {
 	}
 	return shimclient.DoPost(sandboxId, defaultTimeout, containerdshim.DirectVolumeResizeUrl, "application/json", encoded)
 }
+
+// RateLimit updates the I/O limits of a direct volume.
+func RateLimit(volumePath string, rateLimiter config.BlockRateLimiter) error {
+	sandboxId, err := volume.GetSandboxIdForVolume(volumePath)
+	if err != nil {
+		return err
+	}
+	volumeMountInfo, err := volume.VolumeMountInfo(volumePath)
+	if err != nil {
+		return err
+	}
+
+	rateLimitReq := containerdshim.RateLimitRequest{
+		VolumePath:  volumeMountInfo.Device,
+		RateLimiter: rateLimiter,
+	}
+	encoded, err := json.Marshal(rateLimitReq)
+	if err != nil {
+		return err
+	}
+	return shimclient.DoPost(sandboxId, defaultTimeout, containerdshim.DirectVolumeRateLimitUrl, "application/json", encoded)
+}
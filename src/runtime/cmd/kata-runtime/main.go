@@ -126,6 +126,10 @@ var runtimeCommands = []cli.Command{
 	factoryCLICommand,
 	kataVolumeCommand,
 	kataIPTablesCommand,
+	kataPsCLICommand,
+	kataConfigCLICommand,
+	kataFreezeCLICommand,
+	kataThawCLICommand,
 }
 
 // runtimeBeforeSubcommands is the function to run before command-line
@@ -292,6 +296,12 @@ func beforeSubcommands(c *cli.Context) error {
 		}
 	}
 
+	if c.NArg() >= 1 && c.Args()[0] == kataConfigCLICommand.Name {
+		// the config command loads the configuration itself, so that
+		// invalid configurations can be validated
+		return nil
+	}
+
 	configFile, runtimeConfig, err = katautils.LoadConfiguration(c.GlobalString("kata-config"), ignoreConfigLogs)
 	if err != nil {
 		fatal(err)
@@ -16,6 +16,7 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 
 	containerd_types "github.com/containerd/containerd/api/types"
@@ -38,6 +39,8 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/oci"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
+	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
 )
 
@@ -134,12 +137,25 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 			}
 		}()
 
+		// The experimental features of the sandbox gate what the shim
+		// does for it, e.g., if an unprivileged user can create it.
+		if s.config.Experimental, err = oci.ExperimentalFeatures(*ociSpec, *s.config); err != nil {
+			return nil, err
+		}
+
 		katautils.HandleFactory(ctx, vci, s.config)
-		rootless.SetRootless(s.config.HypervisorConfig.Rootless)
-		if rootless.IsRootless() {
-			if err := configureNonRootHypervisor(s.config, r.ID); err != nil {
+		if unprivilegedSandbox(s.config) {
+			rootless.SetRootless(true)
+			if err := configureUnprivilegedHypervisor(s.config, ociSpec, r.ID); err != nil {
 				return nil, err
 			}
+		} else {
+			rootless.SetRootless(s.config.HypervisorConfig.Rootless)
+			if rootless.IsRootless() {
+				if err := configureNonRootHypervisor(s.config, r.ID); err != nil {
+					return nil, err
+				}
+			}
 		}
 
 		// Pass service's context instead of local ctx to CreateSandbox(), since local
@@ -181,7 +197,7 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 			}
 		}()
 
-		_, err = katautils.CreateContainer(ctx, s.sandbox, *ociSpec, rootFs, r.ID, bundlePath, disableOutput, runtimeConfig.DisableGuestEmptyDir)
+		_, err = katautils.CreateContainer(ctx, s.sandbox, *ociSpec, rootFs, r.ID, bundlePath, disableOutput, runtimeConfig.DisableGuestEmptyDir, runtimeConfig.GuestHooks.OCIHooks)
 		if err != nil {
 			return nil, err
 		}
@@ -255,6 +271,7 @@ func loadRuntimeConfig(s *service, r *taskAPI.CreateTaskRequest, anno map[string
 	// For the unit test, the config will be predefined
 	if s.config == nil {
 		s.config = &runtimeConfig
+		s.configPath = configPath
 	}
 
 	return &runtimeConfig, nil
@@ -273,6 +290,10 @@ func checkAndMount(s *service, r *taskAPI.CreateTaskRequest) (bool, error) {
 		if katautils.IsBlockDevice(m.Source) && !s.config.HypervisorConfig.DisableBlockDeviceUse {
 			return false, nil
 		}
+		// Plug the filesystem image file directly instead of loop mounting it.
+		if vc.IsImageFileRootfs(m.Type, m.Source) && !s.config.HypervisorConfig.DisableBlockDeviceUse {
+			return false, nil
+		}
 		if m.Type == vc.NydusRootFSType {
 			// if kata + nydus, do not mount
 			return false, nil
@@ -309,6 +330,59 @@ func doMount(mounts []*containerd_types.Mount, rootfs string) error {
 	return nil
 }
 
+// unprivilegedSandbox returns whether the sandbox is run as an unprivileged
+// sandbox, which is opted in with the unprivileged_sandbox experimental
+// feature. Otherwise, a runtime run by an unprivileged user, e.g., in a user
+// namespace, keeps honouring the rootless flag of the configuration.
+func unprivilegedSandbox(runtimeConfig *oci.RuntimeConfig) bool {
+	return exp.Enabled(runtimeConfig.Experimental, exp.UnprivilegedSandbox) && rootless.IsUnprivileged()
+}
+
+// configureUnprivilegedHypervisor configures the hypervisor of a sandbox
+// created by an unprivileged user, e.g., by a rootless containerd. No user
+// can be created for the hypervisor, it runs in a user namespace instead,
+// and the network can only be connected through passt. The sandboxes using
+// what an unprivileged user cannot, hugepages or host devices, are rejected.
+func configureUnprivilegedHypervisor(runtimeConfig *oci.RuntimeConfig, ociSpec *specs.Spec, sandboxId string) error {
+	if runtimeConfig.HypervisorType != vc.QemuHypervisor {
+		return fmt.Errorf("unprivileged sandboxes are only supported by QEMU, not %s", runtimeConfig.HypervisorType)
+	}
+
+	hugePages := runtimeConfig.HypervisorConfig.HugePages
+	if value, ok := ociSpec.Annotations[vcAnnotations.HugePages]; ok {
+		hugePages, _ = strconv.ParseBool(value)
+	}
+	if hugePages {
+		return fmt.Errorf("unprivileged sandboxes cannot use hugepages")
+	}
+
+	if ociSpec.Linux != nil {
+		for _, d := range ociSpec.Linux.Devices {
+			if d.Type == "b" || strings.HasPrefix(d.Path, "/dev/vfio/") {
+				return fmt.Errorf("unprivileged sandboxes cannot be passed the host device %s", d.Path)
+			}
+		}
+	}
+
+	runtimeConfig.HypervisorConfig.Rootless = true
+	runtimeConfig.HypervisorConfig.UserNamespace = true
+
+	if runtimeConfig.InterNetworkModel != vc.NetXConnectPasstModel {
+		shimLog.WithFields(logrus.Fields{
+			"internetworking_model": runtimeConfig.InterNetworkModel.GetModel(),
+			"sandbox_id":            sandboxId,
+		}).Info("the network of an unprivileged sandbox is connected through passt")
+		runtimeConfig.InterNetworkModel = vc.NetXConnectPasstModel
+	}
+
+	// the container rootfs cannot be a host block device either
+	runtimeConfig.HypervisorConfig.DisableBlockDeviceUse = true
+
+	// The state of the sandbox is stored in the runtime directory of the
+	// user, see the rootlessfs persist driver.
+	return os.MkdirAll(rootless.GetRootlessDir(), vc.DirMode)
+}
+
 func configureNonRootHypervisor(runtimeConfig *oci.RuntimeConfig, sandboxId string) error {
 	userName, err := utils.CreateVmmUser()
 	if err != nil {
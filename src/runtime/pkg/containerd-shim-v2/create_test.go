@@ -21,9 +21,12 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/oci"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
 	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
 )
 
@@ -424,3 +427,80 @@ func TestCreateLoadRuntimeConfig(t *testing.T) {
 	_, err = loadRuntimeConfig(s, r, anno)
 	assert.NoError(err)
 }
+
+func TestConfigureUnprivilegedHypervisor(t *testing.T) {
+	assert := assert.New(t)
+
+	runtimeDir := path.Join(t.TempDir(), "user")
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+
+	runtimeConfig := &oci.RuntimeConfig{
+		HypervisorType:    vc.QemuHypervisor,
+		InterNetworkModel: vc.NetXConnectTCFilterModel,
+	}
+
+	ociSpec := &specs.Spec{
+		Annotations: map[string]string{},
+		Linux:       &specs.Linux{},
+	}
+
+	err := configureUnprivilegedHypervisor(runtimeConfig, ociSpec, testSandboxID)
+	assert.NoError(err)
+
+	// No user is created, the hypervisor runs in a user namespace.
+	assert.True(runtimeConfig.HypervisorConfig.Rootless)
+	assert.True(runtimeConfig.HypervisorConfig.UserNamespace)
+	assert.Empty(runtimeConfig.HypervisorConfig.User)
+	assert.Equal(vc.NetXConnectPasstModel, runtimeConfig.InterNetworkModel)
+	assert.True(runtimeConfig.HypervisorConfig.DisableBlockDeviceUse)
+	assert.DirExists(runtimeDir)
+
+	// hugepages cannot be used
+	runtimeConfig.HypervisorConfig.HugePages = true
+	assert.Error(configureUnprivilegedHypervisor(runtimeConfig, ociSpec, testSandboxID))
+
+	runtimeConfig.HypervisorConfig.HugePages = false
+	ociSpec.Annotations[vcAnnotations.HugePages] = "true"
+	assert.Error(configureUnprivilegedHypervisor(runtimeConfig, ociSpec, testSandboxID))
+	delete(ociSpec.Annotations, vcAnnotations.HugePages)
+
+	// nor host devices be passed
+	for _, d := range []specs.LinuxDevice{
+		{Path: "/dev/vfio/12", Type: "c"},
+		{Path: "/dev/sdb", Type: "b"},
+	} {
+		ociSpec.Linux.Devices = []specs.LinuxDevice{d}
+		assert.Error(configureUnprivilegedHypervisor(runtimeConfig, ociSpec, testSandboxID))
+	}
+
+	runtimeConfig = &oci.RuntimeConfig{
+		HypervisorType: vc.FirecrackerHypervisor,
+	}
+	err = configureUnprivilegedHypervisor(runtimeConfig, ociSpec, testSandboxID)
+	assert.Error(err)
+	assert.False(runtimeConfig.HypervisorConfig.UserNamespace)
+}
+
+func TestUnprivilegedSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	savedIsUnprivileged := rootless.IsUnprivileged
+	defer func() {
+		rootless.IsUnprivileged = savedIsUnprivileged
+	}()
+
+	unprivileged := true
+	rootless.IsUnprivileged = func() bool {
+		return unprivileged
+	}
+
+	// Unprivileged sandboxes are opted in.
+	runtimeConfig := &oci.RuntimeConfig{}
+	assert.False(unprivilegedSandbox(runtimeConfig))
+
+	runtimeConfig.Experimental = []exp.Feature{*exp.Get(exp.UnprivilegedSandbox)}
+	assert.True(unprivilegedSandbox(runtimeConfig))
+
+	unprivileged = false
+	assert.False(unprivilegedSandbox(runtimeConfig))
+}
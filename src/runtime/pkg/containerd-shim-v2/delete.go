@@ -28,11 +28,12 @@ func deleteContainer(ctx context.Context, s *service, c *container) error {
 	}
 
 	// Run post-stop OCI hooks.
-	if err := katautils.PostStopHooks(ctx, *c.spec, s.sandbox.ID(), c.bundle); err != nil {
+	if err := katautils.PostStopHooks(ctx, katautils.HostHooks(*c.spec, s.guestOCIHooks()), c.id, c.bundle); err != nil {
 		// log warning and continue, as defined in oci runtime spec
 		// https://github.com/opencontainers/runtime-spec/blob/master/runtime.md#lifecycle
 		shimLog.WithError(err).Warn("Failed to run post-stop hooks")
 	}
+	katautils.ClearHookResults(c.id)
 
 	if c.mounted {
 		rootfs := path.Join(c.bundle, "rootfs")
@@ -41,6 +42,10 @@ func deleteContainer(ctx context.Context, s *service, c *container) error {
 		}
 	}
 
+	if c.cType.IsSandbox() {
+		removeShimState(c.bundle)
+	}
+
 	delete(s.containers, c.id)
 
 	return nil
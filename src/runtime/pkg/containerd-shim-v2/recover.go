@@ -0,0 +1,423 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/api/types/task"
+	cdshim "github.com/containerd/containerd/runtime/v2/shim"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/pkg/errors"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+// shimStateFile is the file, in the sandbox bundle, holding the state of
+// the shim needed to recover the sandbox.
+const shimStateFile = "shim-state.json"
+
+// shimState is the part of the shim state that cannot be rebuilt from the
+// sandbox persisted by virtcontainers.
+type shimState struct {
+	ConfigPath string           `json:"config_path,omitempty"`
+	Containers []containerState `json:"containers"`
+}
+
+type containerState struct {
+	ID       string           `json:"id"`
+	Bundle   string           `json:"bundle"`
+	Type     vc.ContainerType `json:"type"`
+	Stdin    string           `json:"stdin,omitempty"`
+	Stdout   string           `json:"stdout,omitempty"`
+	Stderr   string           `json:"stderr,omitempty"`
+	Execs    []execState      `json:"execs,omitempty"`
+	Terminal bool             `json:"terminal,omitempty"`
+	Mounted  bool             `json:"mounted,omitempty"`
+
+	// ExitStatus and ExitedAt are set once the container has exited.
+	ExitStatus uint32     `json:"exit_status,omitempty"`
+	ExitedAt   *time.Time `json:"exited_at,omitempty"`
+}
+
+type execState struct {
+	Cmds *types.Cmd `json:"cmds"`
+	ID   string     `json:"id"`
+
+	// ProcessID is the agent process id, set once the exec is started.
+	ProcessID string `json:"process_id,omitempty"`
+
+	Stdin    string `json:"stdin,omitempty"`
+	Stdout   string `json:"stdout,omitempty"`
+	Stderr   string `json:"stderr,omitempty"`
+	Terminal bool   `json:"terminal,omitempty"`
+
+	// ExitStatus and ExitedAt are set once the process has exited.
+	ExitStatus int32      `json:"exit_status,omitempty"`
+	ExitedAt   *time.Time `json:"exited_at,omitempty"`
+}
+
+// isLifecycleEvent returns true if evt is sent at one of the points of
+// the lifecycle of the containers and processes where the shim state
+// changes: creation, start, exit and deletion.
+func isLifecycleEvent(evt interface{}) bool {
+	switch evt.(type) {
+	case *eventstypes.TaskCreate, *eventstypes.TaskStart, *eventstypes.TaskExit, *eventstypes.TaskDelete,
+		*eventstypes.TaskExecAdded, *eventstypes.TaskExecStarted:
+		return true
+	}
+
+	return false
+}
+
+// saveState saves the shim state to the sandbox bundle, to be able to
+// recover the sandbox if the shim exits unexpectedly. It is called when
+// the lifecycle events are sent, with the service lock held.
+func (s *service) saveState() {
+	sc, ok := s.containers[s.id]
+	if !ok {
+		return
+	}
+
+	state := shimState{
+		ConfigPath: s.configPath,
+	}
+
+	for _, c := range s.containers {
+		cs := containerState{
+			ID:       c.id,
+			Bundle:   c.bundle,
+			Type:     c.cType,
+			Stdin:    c.stdin,
+			Stdout:   c.stdout,
+			Stderr:   c.stderr,
+			Terminal: c.terminal,
+			Mounted:  c.mounted,
+		}
+		if c.status == task.StatusStopped {
+			exitedAt := c.exitTime
+			cs.ExitStatus = c.exit
+			cs.ExitedAt = &exitedAt
+		}
+
+		for id, e := range c.execs {
+			es := execState{
+				ID:        id,
+				ProcessID: e.id,
+				Cmds:      e.cmds,
+				Stdin:     e.tty.stdin,
+				Stdout:    e.tty.stdout,
+				Stderr:    e.tty.stderr,
+				Terminal:  e.tty.terminal,
+			}
+			if e.status == task.StatusStopped {
+				exitedAt := e.exitTime
+				es.ExitStatus = e.exitCode
+				es.ExitedAt = &exitedAt
+			}
+			cs.Execs = append(cs.Execs, es)
+		}
+		sort.Slice(cs.Execs, func(i, j int) bool {
+			return cs.Execs[i].ID < cs.Execs[j].ID
+		})
+
+		state.Containers = append(state.Containers, cs)
+	}
+	sort.Slice(state.Containers, func(i, j int) bool {
+		return state.Containers[i].ID < state.Containers[j].ID
+	})
+
+	if err := writeShimState(sc.bundle, &state); err != nil {
+		shimLog.WithError(err).Warn("failed to save shim state, the sandbox cannot be recovered")
+	}
+}
+
+func writeShimState(bundle string, state *shimState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	// write the state atomically, a partial state cannot be recovered
+	path := filepath.Join(bundle, shimStateFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func readShimState(bundle string) (*shimState, error) {
+	data, err := os.ReadFile(filepath.Join(bundle, shimStateFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var state shimState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// lockShimState takes the lock serializing the recovery of the sandbox of
+// bundle with the cleanup containerd runs once the shim which created the
+// sandbox has exited, and returns the function releasing it.
+func lockShimState(bundle string) (func(), error) {
+	f, err := os.OpenFile(filepath.Join(bundle, shimStateFile+".lock"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+func removeShimState(bundle string) {
+	if err := os.Remove(filepath.Join(bundle, shimStateFile)); err != nil && !os.IsNotExist(err) {
+		shimLog.WithError(err).Warn("failed to remove shim state")
+	}
+}
+
+// shimRunning returns true if the shim which pid is saved in the bundle is
+// still running.
+func shimRunning(bundle string) bool {
+	data, err := os.ReadFile(filepath.Join(bundle, "shim.pid"))
+	if err != nil {
+		return false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 || pid == os.Getpid() {
+		return false
+	}
+
+	return syscall.Kill(pid, 0) == nil
+}
+
+// recover re-adopts the sandbox left running by a previous instance of the
+// shim, if any. containerd can then reconnect to the shim and manage the
+// sandbox as if the shim never exited.
+func (s *service) recover() {
+	bundle, err := os.Getwd()
+	if err != nil {
+		return
+	}
+
+	unlock, err := lockShimState(bundle)
+	if err != nil {
+		shimLog.WithError(err).Error("failed to lock shim state, cannot recover sandbox")
+		return
+	}
+	defer unlock()
+
+	state, err := readShimState(bundle)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			shimLog.WithError(err).Error("failed to read shim state, cannot recover sandbox")
+		}
+		return
+	}
+
+	if shimRunning(bundle) {
+		shimLog.Warn("sandbox shim is still running, not recovering sandbox")
+		return
+	}
+
+	shimLog.Info("recovering sandbox")
+
+	_, runtimeConfig, err := katautils.LoadConfiguration(state.ConfigPath, false)
+	if err != nil {
+		shimLog.WithError(err).Error("failed to load runtime configuration, cannot recover sandbox")
+		return
+	}
+	s.config = &runtimeConfig
+	s.configPath = state.ConfigPath
+
+	if err := recoverSandbox(s.ctx, s, state); err != nil {
+		shimLog.WithError(err).Error("failed to recover sandbox")
+		return
+	}
+
+	if err := cdshim.WritePidFile(filepath.Join(bundle, "shim.pid"), os.Getpid()); err != nil {
+		shimLog.WithError(err).Warn("failed to write shim pid file")
+	}
+
+	shimLog.WithField("containers", len(s.containers)).Info("sandbox recovered")
+}
+
+// recoverSandbox re-adopts the sandbox with the runtime configuration of
+// the service, and resumes the monitoring of its containers.
+func recoverSandbox(ctx context.Context, s *service, state *shimState) error {
+	rootless.SetRootless(s.config.HypervisorConfig.Rootless)
+
+	sandbox, err := vci.RecoverSandbox(ctx, s.id)
+	if err != nil {
+		return err
+	}
+	s.sandbox = sandbox
+
+	pid, err := s.sandbox.GetHypervisorPid()
+	if err != nil {
+		return err
+	}
+	s.hpid = uint32(pid)
+
+	for _, cs := range state.Containers {
+		c, err := recoverContainer(s, cs)
+		if err != nil {
+			return err
+		}
+		s.containers[c.id] = c
+	}
+
+	sc, ok := s.containers[s.id]
+	if !ok {
+		return errors.Errorf("no sandbox container %s in shim state", s.id)
+	}
+
+	s.monitor, err = s.sandbox.Monitor(ctx)
+	if err != nil {
+		return err
+	}
+	go watchSandbox(ctx, s)
+	go watchOOMEvents(ctx, s)
+
+	for _, c := range s.containers {
+		if err := resumeContainer(ctx, s, c); err != nil {
+			return err
+		}
+	}
+
+	if defaultStartManagementServerFunc != nil {
+		defaultStartManagementServerFunc(s, ctx, sc.spec)
+	}
+
+	return nil
+}
+
+// recoverContainer rebuilds a container from its saved state and from the
+// state of its virtcontainers counterpart.
+func recoverContainer(s *service, cs containerState) (*container, error) {
+	spec, err := compatoci.ParseConfigJSON(cs.Bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &taskAPI.CreateTaskRequest{
+		ID:       cs.ID,
+		Bundle:   cs.Bundle,
+		Stdin:    cs.Stdin,
+		Stdout:   cs.Stdout,
+		Stderr:   cs.Stderr,
+		Terminal: cs.Terminal,
+	}
+	c, err := newContainer(s, r, cs.Type, &spec, cs.Mounted)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.sandbox.StatusContainer(cs.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cs.ExitedAt != nil:
+		c.status = task.StatusStopped
+		c.exit = cs.ExitStatus
+		c.exitTime = *cs.ExitedAt
+		c.exitCh <- cs.ExitStatus
+	case status.State.State == types.StateRunning:
+		// the agent keeps the exit status of the container until it is
+		// waited for, even if it exited while the shim was not running
+		c.status = task.StatusRunning
+	case status.State.State == types.StatePaused:
+		c.status = task.StatusPaused
+	case status.State.State == types.StateStopped:
+		// the previous shim exited before saving the exit status
+		c.status = task.StatusStopped
+		c.exit = exitCode255
+		c.exitTime = time.Now()
+		c.exitCh <- exitCode255
+	}
+
+	for _, es := range cs.Execs {
+		e := &exec{
+			container: c,
+			cmds:      es.Cmds,
+			tty: &tty{
+				stdin:    es.Stdin,
+				stdout:   es.Stdout,
+				stderr:   es.Stderr,
+				terminal: es.Terminal,
+			},
+			id:          es.ProcessID,
+			exitCode:    exitCode255,
+			exitIOch:    make(chan struct{}),
+			stdinCloser: make(chan struct{}),
+			exitCh:      make(chan uint32, 1),
+			status:      task.StatusCreated,
+		}
+		switch {
+		case es.ExitedAt != nil:
+			e.status = task.StatusStopped
+			e.exitCode = es.ExitStatus
+			e.exitTime = *es.ExitedAt
+			e.exitCh <- uint32(es.ExitStatus)
+		case e.id != "":
+			e.status = task.StatusRunning
+		}
+		c.execs[es.ID] = e
+	}
+
+	return c, nil
+}
+
+// resumeContainer reattaches the IO streams of the processes of a recovered
+// container and waits for them to exit again.
+func resumeContainer(ctx context.Context, s *service, c *container) error {
+	if c.status == task.StatusRunning || c.status == task.StatusPaused {
+		if err := attachContainerIO(ctx, s, c); err != nil {
+			return err
+		}
+		go wait(ctx, s, c, "")
+	}
+
+	for execID, e := range c.execs {
+		if e.status != task.StatusRunning {
+			continue
+		}
+		if err := attachExecIO(ctx, s, c, e, execID); err != nil {
+			return err
+		}
+		go wait(ctx, s, c, execID)
+	}
+
+	return nil
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/api/types/task"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/stretchr/testify/assert"
+
+	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+func newTestRecoverService(t *testing.T) (*service, string) {
+	assert := assert.New(t)
+
+	_, bundle, _ := ktu.SetupOCIConfigFile(t)
+
+	s, err := newService(testSandboxID)
+	assert.NoError(err)
+	s.events = nil
+	s.configPath = "/etc/kata/configuration.toml"
+
+	c, err := newContainer(s, &taskAPI.CreateTaskRequest{
+		ID:     testSandboxID,
+		Bundle: bundle,
+		Stdout: "/run/stdout",
+	}, vc.PodSandbox, nil, true)
+	assert.NoError(err)
+	s.containers[c.id] = c
+
+	c.execs["exec"] = &exec{
+		container: c,
+		cmds:      &types.Cmd{Args: []string{"sh"}},
+		tty:       &tty{stdin: "/run/exec-stdin", terminal: true},
+		id:        "exec-process",
+	}
+
+	return s, bundle
+}
+
+func TestSaveShimState(t *testing.T) {
+	assert := assert.New(t)
+
+	s, bundle := newTestRecoverService(t)
+
+	s.saveState()
+
+	state, err := readShimState(bundle)
+	assert.NoError(err)
+	assert.Equal(&shimState{
+		ConfigPath: "/etc/kata/configuration.toml",
+		Containers: []containerState{
+			{
+				ID:      testSandboxID,
+				Bundle:  bundle,
+				Type:    vc.PodSandbox,
+				Stdout:  "/run/stdout",
+				Mounted: true,
+				Execs: []execState{
+					{
+						ID:        "exec",
+						ProcessID: "exec-process",
+						Cmds:      &types.Cmd{Args: []string{"sh"}},
+						Stdin:     "/run/exec-stdin",
+						Terminal:  true,
+					},
+				},
+			},
+		},
+	}, state)
+
+	// the exit status of the processes is saved once they exit
+	exitedAt := time.Unix(1700000000, 0).UTC()
+	c := s.containers[testSandboxID]
+	c.status = task.StatusStopped
+	c.exit = 137
+	c.exitTime = exitedAt
+	c.execs["exec"].status = task.StatusStopped
+	c.execs["exec"].exitCode = 2
+	c.execs["exec"].exitTime = exitedAt
+	s.saveState()
+
+	state, err = readShimState(bundle)
+	assert.NoError(err)
+	assert.Equal(uint32(137), state.Containers[0].ExitStatus)
+	assert.Equal(exitedAt, state.Containers[0].ExitedAt.UTC())
+	assert.Equal(int32(2), state.Containers[0].Execs[0].ExitStatus)
+	assert.Equal(exitedAt, state.Containers[0].Execs[0].ExitedAt.UTC())
+
+	// the state is only saved while the sandbox container exists
+	removeShimState(bundle)
+	delete(s.containers, testSandboxID)
+	s.saveState()
+
+	_, err = readShimState(bundle)
+	assert.True(os.IsNotExist(err))
+}
+
+func TestRecoverContainer(t *testing.T) {
+	assert := assert.New(t)
+
+	s, bundle := newTestRecoverService(t)
+	s.sandbox = &vcmock.Sandbox{MockID: testSandboxID}
+
+	s.saveState()
+	state, err := readShimState(bundle)
+	assert.NoError(err)
+
+	c, err := recoverContainer(s, state.Containers[0])
+	assert.NoError(err)
+
+	assert.Equal(testSandboxID, c.id)
+	assert.Equal(bundle, c.bundle)
+	assert.Equal(vc.PodSandbox, c.cType)
+	assert.Equal("/run/stdout", c.stdout)
+	assert.True(c.mounted)
+	assert.NotNil(c.spec)
+
+	// the mock sandbox does not report any container state
+	assert.Equal(task.StatusCreated, c.status)
+
+	e, err := c.getExec("exec")
+	assert.NoError(err)
+	assert.Equal("exec-process", e.id)
+	assert.Equal([]string{"sh"}, e.cmds.Args)
+	assert.True(e.tty.terminal)
+	assert.Equal(task.StatusRunning, e.status)
+
+	_, err = recoverContainer(s, containerState{ID: "missing", Bundle: t.TempDir()})
+	assert.Error(err)
+
+	// the processes which exited before the shim did keep their status
+	exitedAt := time.Unix(1700000000, 0)
+	state.Containers[0].ExitStatus = 137
+	state.Containers[0].ExitedAt = &exitedAt
+	state.Containers[0].Execs[0].ExitStatus = 2
+	state.Containers[0].Execs[0].ExitedAt = &exitedAt
+
+	c, err = recoverContainer(s, state.Containers[0])
+	assert.NoError(err)
+	assert.Equal(task.StatusStopped, c.status)
+	assert.Equal(uint32(137), c.exit)
+	assert.Equal(uint32(137), <-c.exitCh)
+
+	e, err = c.getExec("exec")
+	assert.NoError(err)
+	assert.Equal(task.StatusStopped, e.status)
+	assert.Equal(int32(2), e.exitCode)
+	assert.Equal(uint32(2), <-e.exitCh)
+}
+
+func TestSaveStateOnLifecycleEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	s, bundle := newTestRecoverService(t)
+
+	s.send(&eventstypes.TaskOOM{ContainerID: testSandboxID})
+	_, err := readShimState(bundle)
+	assert.True(os.IsNotExist(err))
+
+	s.send(&eventstypes.TaskExecAdded{ContainerID: testSandboxID, ExecID: "exec"})
+	_, err = readShimState(bundle)
+	assert.NoError(err)
+}
+
+func TestCleanupRecoveredSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	s, bundle := newTestRecoverService(t)
+	s.rootCtx = context.Background()
+	s.saveState()
+	assert.NoError(os.RemoveAll(filepath.Join(bundle, "rootfs")))
+
+	cwd, err := os.Getwd()
+	assert.NoError(err)
+	assert.NoError(os.Chdir(bundle))
+	defer os.Chdir(cwd)
+
+	cleaned := false
+	testingImpl.CleanupContainerFunc = func(ctx context.Context, sandboxID, containerID string, force bool) error {
+		cleaned = true
+		return nil
+	}
+	defer func() {
+		testingImpl.CleanupContainerFunc = nil
+	}()
+
+	// a restarted shim recovered the sandbox
+	pidFile := filepath.Join(bundle, "shim.pid")
+	assert.NoError(os.WriteFile(pidFile, []byte(fmt.Sprintf("%d", os.Getppid())), 0600))
+
+	_, err = s.Cleanup(context.Background())
+	assert.NoError(err)
+	assert.False(cleaned)
+	_, err = readShimState(bundle)
+	assert.NoError(err)
+
+	// nobody recovered the sandbox
+	assert.NoError(os.Remove(pidFile))
+
+	_, err = s.Cleanup(context.Background())
+	assert.NoError(err)
+	assert.True(cleaned)
+	_, err = readShimState(bundle)
+	assert.True(os.IsNotExist(err))
+}
+
+func TestLockShimState(t *testing.T) {
+	assert := assert.New(t)
+
+	bundle := t.TempDir()
+	unlock, err := lockShimState(bundle)
+	assert.NoError(err)
+
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := lockShimState(bundle)
+		assert.NoError(err)
+		close(locked)
+		unlock()
+	}()
+
+	select {
+	case <-locked:
+		assert.Fail("shim state locked twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	<-locked
+}
+
+func TestRecoverSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	s, bundle := newTestRecoverService(t)
+	delete(s.containers[testSandboxID].execs, "exec")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.ctx = ctx
+
+	runtimeConfig, err := newTestRuntimeConfig(t.TempDir(), true)
+	assert.NoError(err)
+
+	s.saveState()
+	state, err := readShimState(bundle)
+	assert.NoError(err)
+
+	startManagementServer := defaultStartManagementServerFunc
+	defaultStartManagementServerFunc = nil
+	defer func() {
+		defaultStartManagementServerFunc = startManagementServer
+		testingImpl.RecoverSandboxFunc = nil
+	}()
+
+	testingImpl.RecoverSandboxFunc = func(ctx context.Context, sandboxID string) (vc.VCSandbox, error) {
+		return nil, fmt.Errorf("sandbox %s is not running", sandboxID)
+	}
+
+	recovered, err := newService(testSandboxID)
+	assert.NoError(err)
+	recovered.events = nil
+	recovered.ctx = ctx
+	recovered.config = &runtimeConfig
+
+	err = recoverSandbox(ctx, recovered, state)
+	assert.Error(err)
+	assert.Nil(recovered.sandbox)
+
+	testingImpl.RecoverSandboxFunc = func(ctx context.Context, sandboxID string) (vc.VCSandbox, error) {
+		return &vcmock.Sandbox{MockID: sandboxID}, nil
+	}
+
+	err = recoverSandbox(ctx, recovered, state)
+	assert.NoError(err)
+	assert.NotNil(recovered.sandbox)
+	assert.Contains(recovered.containers, testSandboxID)
+}
+
+func TestShimRunning(t *testing.T) {
+	assert := assert.New(t)
+
+	bundle := t.TempDir()
+	pidFile := filepath.Join(bundle, "shim.pid")
+
+	assert.False(shimRunning(bundle))
+
+	for _, d := range []struct {
+		pid     string
+		running bool
+	}{
+		{"", false},
+		{"foo", false},
+		{"-1", false},
+		{fmt.Sprintf("%d", os.Getpid()), false},
+		{fmt.Sprintf("%d\n", os.Getppid()), true},
+	} {
+		assert.NoError(os.WriteFile(pidFile, []byte(d.pid), 0600))
+		assert.Equal(d.running, shimRunning(bundle), "pid %q", d.pid)
+	}
+}
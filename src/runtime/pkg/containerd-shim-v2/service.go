@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	sysexec "os/exec"
+	"path/filepath"
 	goruntime "runtime"
 	"sync"
 	"syscall"
@@ -70,8 +71,27 @@ var shimLog = logrus.WithFields(logrus.Fields{
 	"name":   "containerd-shim-v2",
 })
 
+// Options are the options of the shim service.
+type Options struct {
+	// Recover the sandbox left running by a previous instance of the
+	// shim, before serving the requests from containerd.
+	Recover bool
+}
+
 // New returns a new shim service that can be used via GRPC
 func New(ctx context.Context, id string, publisher cdshim.Publisher, shutdown func()) (cdshim.Shim, error) {
+	return newShimService(ctx, id, publisher, shutdown, Options{})
+}
+
+// NewWithOptions returns the function creating a new shim service with
+// the given options.
+func NewWithOptions(opts Options) cdshim.Init {
+	return func(ctx context.Context, id string, publisher cdshim.Publisher, shutdown func()) (cdshim.Shim, error) {
+		return newShimService(ctx, id, publisher, shutdown, opts)
+	}
+}
+
+func newShimService(ctx context.Context, id string, publisher cdshim.Publisher, shutdown func(), opts Options) (cdshim.Shim, error) {
 	shimLog = shimLog.WithFields(logrus.Fields{
 		"sandbox": id,
 		"pid":     os.Getpid(),
@@ -80,8 +100,7 @@ func New(ctx context.Context, id string, publisher cdshim.Publisher, shutdown fu
 	// it will output into stdio, from which containerd would like
 	// to get the shim's socket address.
 	logrus.SetOutput(io.Discard)
-	opts := ctx.Value(cdshim.OptsKey{}).(cdshim.Opts)
-	if !opts.Debug {
+	if !ctx.Value(cdshim.OptsKey{}).(cdshim.Opts).Debug {
 		logrus.SetLevel(logrus.WarnLevel)
 	}
 	vci.SetLogger(ctx, shimLog)
@@ -108,6 +127,10 @@ func New(ctx context.Context, id string, publisher cdshim.Publisher, shutdown fu
 	forwarder := s.newEventsForwarder(ctx, publisher)
 	go forwarder.forward()
 
+	if opts.Recover {
+		s.recover()
+	}
+
 	return s, nil
 }
 
@@ -131,6 +154,10 @@ type service struct {
 
 	config *oci.RuntimeConfig
 
+	// configPath is the path of the runtime configuration file, if not
+	// the default one.
+	configPath string
+
 	monitor chan error
 	ec      chan exit
 
@@ -277,6 +304,10 @@ func (s *service) StartShim(ctx context.Context, opts cdshim.StartOpts) (_ strin
 }
 
 func (s *service) send(evt interface{}) {
+	if isLifecycleEvent(evt) {
+		s.saveState()
+	}
+
 	// for unit test, it will not initialize s.events
 	if s.events != nil {
 		s.events <- evt
@@ -284,6 +315,10 @@ func (s *service) send(evt interface{}) {
 }
 
 func (s *service) sendL(evt interface{}) {
+	if isLifecycleEvent(evt) {
+		s.saveState()
+	}
+
 	s.eventSendMu.Lock()
 	if s.events != nil {
 		s.events <- evt
@@ -353,21 +388,32 @@ func (s *service) Cleanup(ctx context.Context) (_ *taskAPI.DeleteResponse, err e
 		return nil, err
 	}
 
-	switch containerType {
-	case vc.PodSandbox, vc.SingleContainer:
-		err = cleanupContainer(spanCtx, s.id, s.id, path)
-		if err != nil {
+	sandboxID, sandboxBundle := s.id, path
+	if containerType == vc.PodContainer {
+		if sandboxID, err = oci.SandboxID(ociSpec); err != nil {
 			return nil, err
 		}
-	case vc.PodContainer:
-		sandboxID, err := oci.SandboxID(ociSpec)
-		if err != nil {
+		// containerd keeps the bundles of the tasks side by side
+		sandboxBundle = filepath.Join(filepath.Dir(path), sandboxID)
+	}
+
+	// A shim restarted after the exit of the one containerd cleans up after
+	// may have recovered the sandbox, which is then its own to delete.
+	unlock, err := lockShimState(sandboxBundle)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if _, err := readShimState(sandboxBundle); err == nil && shimRunning(sandboxBundle) {
+		shimLog.WithField("container", s.id).Info("sandbox recovered by a running shim, skipping cleanup")
+	} else {
+		if err = cleanupContainer(spanCtx, sandboxID, s.id, path); err != nil {
 			return nil, err
 		}
 
-		err = cleanupContainer(spanCtx, sandboxID, s.id, path)
-		if err != nil {
-			return nil, err
+		if containerType.IsSandbox() {
+			removeShimState(path)
 		}
 	}
 
@@ -829,7 +875,7 @@ func (s *service) Kill(ctx context.Context, r *taskAPI.KillRequest) (_ *ptypes.E
 func (s *service) Pids(ctx context.Context, r *taskAPI.PidsRequest) (_ *taskAPI.PidsResponse, err error) {
 	shimLog.WithField("container", r.ID).Debug("Pids() start")
 	defer shimLog.WithField("container", r.ID).Debug("Pids() end")
-	span, _ := katatrace.Trace(s.rootCtx, shimLog, "Pids", shimTracingTags)
+	span, spanCtx := katatrace.Trace(s.rootCtx, shimLog, "Pids", shimTracingTags)
 	defer span.End()
 
 	var processes []*task.ProcessInfo
@@ -840,10 +886,36 @@ func (s *service) Pids(ctx context.Context, r *taskAPI.PidsRequest) (_ *taskAPI.
 		rpcDurationsHistogram.WithLabelValues("pids").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	pInfo := task.ProcessInfo{
-		Pid: s.hpid,
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.getContainer(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	guestProcesses, err := s.sandbox.ListProcesses(spanCtx, c.id)
+	if err != nil {
+		// Agents without process listing support, only report
+		// the hypervisor process.
+		shimLog.WithError(err).WithField("container", r.ID).Warn("failed to list guest processes")
+
+		return &taskAPI.PidsResponse{
+			Processes: []*task.ProcessInfo{{Pid: s.hpid}},
+		}, nil
+	}
+
+	for _, p := range guestProcesses {
+		info, err := typeurl.MarshalAny(p)
+		if err != nil {
+			return nil, err
+		}
+
+		processes = append(processes, &task.ProcessInfo{
+			Pid:  uint32(p.Pid),
+			Info: info,
+		})
 	}
-	processes = append(processes, &pInfo)
 
 	return &taskAPI.PidsResponse{
 		Processes: processes,
@@ -1144,3 +1216,9 @@ func (s *service) getContainerStatus(containerID string) (task.Status, error) {
 
 	return status, nil
 }
+
+// guestOCIHooks returns whether the prestart, poststart and poststop OCI
+// hooks of the containers are run by the agent, in the guest.
+func (s *service) guestOCIHooks() bool {
+	return s.config != nil && s.config.GuestHooks.OCIHooks
+}
@@ -12,8 +12,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/containerd/containerd/namespaces"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/typeurl"
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,3 +75,64 @@ func TestServiceCreate(t *testing.T) {
 		}
 	}
 }
+
+func TestServicePids(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		hpid:       42,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	reqCreate := &taskAPI.CreateTaskRequest{
+		ID: testContainerID,
+	}
+
+	var err error
+	s.containers[testContainerID], err = newContainer(s, reqCreate, "", nil, true)
+	assert.NoError(err)
+
+	ctx := namespaces.WithNamespace(context.Background(), "UnitTest")
+
+	// unknown container
+	_, err = s.Pids(ctx, &taskAPI.PidsRequest{ID: "unknown"})
+	assert.Error(err)
+
+	// guest processes
+	sandbox.ListProcessesFunc = func(contID string) ([]*grpc.GuestProcess, error) {
+		return []*grpc.GuestProcess{
+			{Pid: 1, Args: []string{"sh"}},
+			{Pid: 7, Ppid: 1, ExecId: "exec", Args: []string{"top"}},
+		}, nil
+	}
+
+	resp, err := s.Pids(ctx, &taskAPI.PidsRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Len(resp.Processes, 2)
+	assert.Equal(uint32(7), resp.Processes[1].Pid)
+
+	v, err := typeurl.UnmarshalAny(resp.Processes[1].Info)
+	assert.NoError(err)
+	p, ok := v.(*grpc.GuestProcess)
+	assert.True(ok)
+	assert.Equal("exec", p.ExecId)
+	assert.Equal([]string{"top"}, p.Args)
+
+	// the hypervisor process is returned when the agent cannot list the
+	// guest processes
+	sandbox.ListProcessesFunc = func(contID string) ([]*grpc.GuestProcess, error) {
+		return nil, fmt.Errorf("unimplemented")
+	}
+
+	resp, err = s.Pids(ctx, &taskAPI.PidsRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Len(resp.Processes, 1)
+	assert.Equal(uint32(42), resp.Processes[0].Pid)
+	assert.Nil(resp.Processes[0].Info)
+}
@@ -21,9 +21,14 @@ import (
 
 	"google.golang.org/grpc/codes"
 
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/api/types/task"
 	cdshim "github.com/containerd/containerd/runtime/v2/shim"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	mutils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
 	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/prometheus/client_golang/prometheus"
@@ -33,13 +38,24 @@ import (
 )
 
 const (
-	DirectVolumePathKey   = "path"
-	AgentUrl              = "/agent-url"
-	DirectVolumeStatUrl   = "/direct-volume/stats"
-	DirectVolumeResizeUrl = "/direct-volume/resize"
-	IPTablesUrl           = "/iptables"
-	IP6TablesUrl          = "/ip6tables"
-	MetricsUrl            = "/metrics"
+	DirectVolumePathKey      = "path"
+	ContainerIDKey           = "container"
+	AgentUrl                 = "/agent-url"
+	DirectVolumeStatUrl      = "/direct-volume/stats"
+	DirectVolumeResizeUrl    = "/direct-volume/resize"
+	DirectVolumeRateLimitUrl = "/direct-volume/rate-limit"
+	IPTablesUrl              = "/iptables"
+	IP6TablesUrl             = "/ip6tables"
+	MetricsUrl               = "/metrics"
+	EventsUrl                = "/events"
+	ProcessesUrl             = "/processes"
+	HooksUrl                 = "/hooks"
+	FreezeUrl                = "/freeze"
+	ThawUrl                  = "/thaw"
+
+	// EventStreamContentType is the content type of the server-sent
+	// events stream served on EventsUrl.
+	EventStreamContentType = "text/event-stream"
 )
 
 var (
@@ -52,6 +68,15 @@ type ResizeRequest struct {
 	Size       uint64
 }
 
+type RateLimitRequest struct {
+	VolumePath  string
+	RateLimiter config.BlockRateLimiter
+}
+
+type FreezeRequest struct {
+	ReclaimMemory bool
+}
+
 // agentURL returns URL for agent
 func (s *service) agentURL(w http.ResponseWriter, r *http.Request) {
 	url, err := s.sandbox.GetAgentURL()
@@ -199,6 +224,225 @@ func (s *service) serveVolumeResize(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(""))
 }
 
+func (s *service) serveVolumeRateLimit(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		shimMgtLog.WithError(err).Error("failed to read request body")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	var rateLimitReq RateLimitRequest
+	err = json.Unmarshal(body, &rateLimitReq)
+	if err != nil {
+		shimMgtLog.WithError(err).Error("failed to unmarshal the http request body")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	err = s.sandbox.UpdateVolumeRateLimiter(context.Background(), rateLimitReq.VolumePath, &rateLimitReq.RateLimiter)
+	if err != nil {
+		shimMgtLog.WithError(err).WithField("volume-path", rateLimitReq.VolumePath).Error("failed to update the volume rate limiter")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write([]byte(""))
+}
+
+// serveProcesses returns the processes running in a container of the
+// sandbox as a JSON list.
+func (s *service) serveProcesses(w http.ResponseWriter, r *http.Request) {
+	containerID := r.URL.Query().Get(ContainerIDKey)
+	if containerID == "" {
+		msg := fmt.Sprintf("Required parameter %s not found", ContainerIDKey)
+		shimMgtLog.Info(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	processes, err := s.sandbox.ListProcesses(context.Background(), containerID)
+	if err != nil {
+		shimMgtLog.WithError(err).WithField("container", containerID).Error("failed to list the container processes")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	buf, err := json.Marshal(processes)
+	if err != nil {
+		shimMgtLog.WithError(err).Error("failed to marshal the container processes")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write(buf)
+}
+
+// serveHooks returns the results of the OCI hooks run on the host for a
+// container.
+func (s *service) serveHooks(w http.ResponseWriter, r *http.Request) {
+	containerID := r.URL.Query().Get(ContainerIDKey)
+	if containerID == "" {
+		msg := fmt.Sprintf("Required parameter %s not found", ContainerIDKey)
+		shimMgtLog.Info(msg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(msg))
+		return
+	}
+
+	s.mu.Lock()
+	_, ok := s.containers[containerID]
+	s.mu.Unlock()
+	if !ok {
+		msg := fmt.Sprintf("container %s not found", containerID)
+		shimMgtLog.Info(msg)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(msg))
+		return
+	}
+
+	buf, err := json.Marshal(katautils.HookResults(containerID))
+	if err != nil {
+		shimMgtLog.WithError(err).Error("failed to marshal the hook results")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write(buf)
+}
+
+// serveFreeze freezes the sandbox: its containers are paused and the vCPUs
+// of the VM are stopped.
+func (s *service) serveFreeze(w http.ResponseWriter, r *http.Request) {
+	if err := exp.Check(s.config.Experimental, exp.SandboxFreeze); err != nil {
+		shimMgtLog.WithError(err).Error("failed to freeze the sandbox")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		shimMgtLog.WithError(err).Error("failed to read request body")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	var freezeReq FreezeRequest
+	err = json.Unmarshal(body, &freezeReq)
+	if err != nil {
+		shimMgtLog.WithError(err).Error("failed to unmarshal the http request body")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.sandbox.Freeze(context.Background(), freezeReq.ReclaimMemory)
+	s.updateContainerStatuses()
+	if err != nil {
+		shimMgtLog.WithError(err).Error("failed to freeze the sandbox")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write([]byte(""))
+}
+
+// serveThaw thaws a sandbox frozen by serveFreeze.
+func (s *service) serveThaw(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.sandbox.Thaw(context.Background())
+	s.updateContainerStatuses()
+	if err != nil {
+		shimMgtLog.WithError(err).Error("failed to thaw the sandbox")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write([]byte(""))
+}
+
+// updateContainerStatuses refreshes the status of the containers after
+// they were paused or resumed by the sandbox, and sends the matching task
+// events. It must be called with s.mu held.
+func (s *service) updateContainerStatuses() {
+	for _, c := range s.containers {
+		status, err := s.getContainerStatus(c.id)
+		if err != nil || status == c.status {
+			continue
+		}
+
+		previous := c.status
+		c.status = status
+
+		switch {
+		case previous == task.StatusRunning && status == task.StatusPaused:
+			s.send(&eventstypes.TaskPaused{
+				ContainerID: c.id,
+			})
+		case previous == task.StatusPaused && status == task.StatusRunning:
+			s.send(&eventstypes.TaskResumed{
+				ContainerID: c.id,
+			})
+		}
+	}
+}
+
+// serveEvents streams the sandbox events as server-sent events until the
+// client goes away or the sandbox is deleted.
+func (s *service) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("streaming is not supported"))
+		return
+	}
+
+	events, unsubscribe := s.sandbox.SubscribeEvents()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", EventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := WriteEvent(w, e); err != nil {
+				shimMgtLog.WithError(err).Warn("failed to write sandbox event")
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// WriteEvent writes e to w in the server-sent events format, with the
+// event type as SSE event name and its JSON encoding as data.
+func WriteEvent(w io.Writer, e vc.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+	return err
+}
+
 func (s *service) ip6TablesHandler(w http.ResponseWriter, r *http.Request) {
 	s.genericIPTablesHandler(w, r, true)
 }
@@ -265,8 +509,14 @@ func (s *service) startManagementServer(ctx context.Context, ociSpec *specs.Spec
 	m.Handle(AgentUrl, http.HandlerFunc(s.agentURL))
 	m.Handle(DirectVolumeStatUrl, http.HandlerFunc(s.serveVolumeStats))
 	m.Handle(DirectVolumeResizeUrl, http.HandlerFunc(s.serveVolumeResize))
+	m.Handle(DirectVolumeRateLimitUrl, http.HandlerFunc(s.serveVolumeRateLimit))
 	m.Handle(IPTablesUrl, http.HandlerFunc(s.ipTablesHandler))
 	m.Handle(IP6TablesUrl, http.HandlerFunc(s.ip6TablesHandler))
+	m.Handle(EventsUrl, http.HandlerFunc(s.serveEvents))
+	m.Handle(ProcessesUrl, http.HandlerFunc(s.serveProcesses))
+	m.Handle(HooksUrl, http.HandlerFunc(s.serveHooks))
+	m.Handle(FreezeUrl, http.HandlerFunc(s.serveFreeze))
+	m.Handle(ThawUrl, http.HandlerFunc(s.serveThaw))
 	s.mountPprofHandle(m, ociSpec)
 
 	// register shim metrics
@@ -6,13 +6,21 @@
 package containerdshim
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/oci"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -61,3 +69,179 @@ go_threads 23
 	body = rr.Body.String()
 	assert.Equal(true, len(strings.Split(body, "\n")) > 0)
 }
+
+func TestServeEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	sandbox.SubscribeEventsFunc = func() (<-chan vc.Event, func()) {
+		ch := make(chan vc.Event, 1)
+		ch <- vc.Event{
+			Type:        vc.EventGuestOOM,
+			SandboxID:   testSandboxID,
+			ContainerID: testContainerID,
+		}
+		close(ch)
+		return ch, func() {}
+	}
+
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, EventsUrl, nil)
+
+	s.serveEvents(rr, r)
+	assert.Equal(200, rr.Code, "response code should be 200")
+	assert.Equal(EventStreamContentType, rr.Header().Get("Content-Type"))
+
+	body := rr.Body.String()
+	assert.True(strings.HasPrefix(body, "event: guest-oom\ndata: {"))
+	assert.True(strings.HasSuffix(body, "}\n\n"))
+	assert.Contains(body, `"container":"`+testContainerID+`"`)
+}
+
+func TestServeProcesses(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+	sandbox.ListProcessesFunc = func(contID string) ([]*grpc.GuestProcess, error) {
+		if contID != testContainerID {
+			return nil, fmt.Errorf("container %s not found", contID)
+		}
+		return []*grpc.GuestProcess{{Pid: 1, Args: []string{"sh"}}}, nil
+	}
+
+	s := &service{
+		id:      testSandboxID,
+		sandbox: sandbox,
+	}
+
+	// missing container parameter
+	rr := httptest.NewRecorder()
+	s.serveProcesses(rr, httptest.NewRequest(http.MethodGet, ProcessesUrl, nil))
+	assert.Equal(http.StatusBadRequest, rr.Code)
+
+	// unknown container
+	rr = httptest.NewRecorder()
+	s.serveProcesses(rr, httptest.NewRequest(http.MethodGet, ProcessesUrl+"?"+ContainerIDKey+"=unknown", nil))
+	assert.Equal(http.StatusInternalServerError, rr.Code)
+
+	rr = httptest.NewRecorder()
+	s.serveProcesses(rr, httptest.NewRequest(http.MethodGet, ProcessesUrl+"?"+ContainerIDKey+"="+testContainerID, nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var processes []*grpc.GuestProcess
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &processes))
+	assert.Len(processes, 1)
+	assert.Equal([]string{"sh"}, processes[0].Args)
+}
+
+func TestServeHooks(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &service{
+		id:         testSandboxID,
+		containers: map[string]*container{testContainerID: {}},
+	}
+
+	spec := specs.Spec{
+		Hooks: &specs.Hooks{
+			Poststart: []specs.Hook{{Path: "/bin/true", Args: []string{"true"}}},
+		},
+	}
+	assert.NoError(katautils.PostStartHooks(context.Background(), spec, testContainerID, ""))
+	defer katautils.ClearHookResults(testContainerID)
+
+	// missing container parameter
+	rr := httptest.NewRecorder()
+	s.serveHooks(rr, httptest.NewRequest(http.MethodGet, HooksUrl, nil))
+	assert.Equal(http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	s.serveHooks(rr, httptest.NewRequest(http.MethodGet, HooksUrl+"?"+ContainerIDKey+"=unknown", nil))
+	assert.Equal(http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	s.serveHooks(rr, httptest.NewRequest(http.MethodGet, HooksUrl+"?"+ContainerIDKey+"="+testContainerID, nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var results []katautils.HookResult
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &results))
+	assert.Len(results, 1)
+	assert.Equal("/bin/true", results[0].Path)
+	assert.Empty(results[0].Error)
+}
+
+func TestServeFreezeThaw(t *testing.T) {
+	assert := assert.New(t)
+
+	var reclaimed bool
+	frozen := false
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+	sandbox.FreezeFunc = func(reclaimMemory bool) error {
+		if frozen {
+			return fmt.Errorf("sandbox %s is already frozen", testSandboxID)
+		}
+		frozen = true
+		reclaimed = reclaimMemory
+		return nil
+	}
+	sandbox.ThawFunc = func() error {
+		if !frozen {
+			return fmt.Errorf("sandbox %s is not frozen", testSandboxID)
+		}
+		frozen = false
+		return nil
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+		config:     &oci.RuntimeConfig{},
+	}
+
+	// freezing is an experimental feature
+	rr := httptest.NewRecorder()
+	s.serveFreeze(rr, httptest.NewRequest(http.MethodPut, FreezeUrl, strings.NewReader(`{}`)))
+	assert.Equal(http.StatusForbidden, rr.Code)
+	assert.False(frozen)
+
+	s.config.Experimental = []exp.Feature{*exp.Get(exp.SandboxFreeze)}
+
+	// invalid request body
+	rr = httptest.NewRecorder()
+	s.serveFreeze(rr, httptest.NewRequest(http.MethodPut, FreezeUrl, strings.NewReader("{")))
+	assert.Equal(http.StatusInternalServerError, rr.Code)
+	assert.False(frozen)
+
+	rr = httptest.NewRecorder()
+	s.serveFreeze(rr, httptest.NewRequest(http.MethodPut, FreezeUrl, strings.NewReader(`{"ReclaimMemory": true}`)))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.True(frozen)
+	assert.True(reclaimed)
+
+	rr = httptest.NewRecorder()
+	s.serveFreeze(rr, httptest.NewRequest(http.MethodPut, FreezeUrl, strings.NewReader(`{}`)))
+	assert.Equal(http.StatusInternalServerError, rr.Code)
+
+	rr = httptest.NewRecorder()
+	s.serveThaw(rr, httptest.NewRequest(http.MethodPut, ThawUrl, nil))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.False(frozen)
+
+	rr = httptest.NewRecorder()
+	s.serveThaw(rr, httptest.NewRequest(http.MethodPut, ThawUrl, nil))
+	assert.Equal(http.StatusInternalServerError, rr.Code)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/containerd/containerd/api/types/task"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 )
 
 func startContainer(ctx context.Context, s *service, c *container) (retErr error) {
@@ -34,6 +35,20 @@ func startContainer(ctx context.Context, s *service, c *container) (retErr error
 		return err
 	}
 
+	// The OCI hooks see the hypervisor, which runs the container, as the
+	// container process.
+	if hid, err := s.sandbox.GetHypervisorPid(); err == nil {
+		ctx = context.WithValue(ctx, vc.HypervisorPidKey{}, hid)
+	}
+
+	// Run start container OCI hooks, before the container process runs.
+	err := katautils.EnterNetNS(s.sandbox.GetNetNs(), func() error {
+		return katautils.StartContainerHooks(ctx, *c.spec, c.id, c.bundle)
+	})
+	if err != nil {
+		return err
+	}
+
 	if c.cType.IsSandbox() {
 		err := s.sandbox.Start(ctx)
 		if err != nil {
@@ -57,8 +72,8 @@ func startContainer(ctx context.Context, s *service, c *container) (retErr error
 	}
 
 	// Run post-start OCI hooks.
-	err := katautils.EnterNetNS(s.sandbox.GetNetNs(), func() error {
-		return katautils.PostStartHooks(ctx, *c.spec, s.sandbox.ID(), c.bundle)
+	err = katautils.EnterNetNS(s.sandbox.GetNetNs(), func() error {
+		return katautils.PostStartHooks(ctx, katautils.HostHooks(*c.spec, s.guestOCIHooks()), c.id, c.bundle)
 	})
 	if err != nil {
 		// log warning and continue, as defined in oci runtime spec
@@ -68,6 +83,18 @@ func startContainer(ctx context.Context, s *service, c *container) (retErr error
 
 	c.status = task.StatusRunning
 
+	if err := attachContainerIO(ctx, s, c); err != nil {
+		return err
+	}
+
+	go wait(ctx, s, c, "")
+
+	return nil
+}
+
+// attachContainerIO copies the IO streams of the container process from and
+// to the ones given by containerd.
+func attachContainerIO(ctx context.Context, s *service, c *container) error {
 	stdin, stdout, stderr, err := s.sandbox.IOStream(c.id, c.id)
 	if err != nil {
 		return err
@@ -92,8 +119,6 @@ func startContainer(ctx context.Context, s *service, c *container) (retErr error
 		close(c.stdinCloser)
 	}
 
-	go wait(ctx, s, c, "")
-
 	return nil
 }
 
@@ -135,16 +160,28 @@ func startExec(ctx context.Context, s *service, containerID, execID string) (e *
 		}
 	}
 
+	if err := attachExecIO(ctx, s, c, execs, execID); err != nil {
+		return nil, err
+	}
+
+	go wait(ctx, s, c, execID)
+
+	return execs, nil
+}
+
+// attachExecIO copies the IO streams of an exec process from and to the ones
+// given by containerd.
+func attachExecIO(ctx context.Context, s *service, c *container, execs *exec, execID string) error {
 	stdin, stdout, stderr, err := s.sandbox.IOStream(c.id, execs.id)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	execs.stdinPipe = stdin
 
 	tty, err := newTtyIO(ctx, s.namespace, execs.id, execs.tty.stdin, execs.tty.stdout, execs.tty.stderr, execs.tty.terminal)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	execs.ttyio = tty
 
@@ -153,7 +190,5 @@ func startExec(ctx context.Context, s *service, containerID, execID string) (e *
 		"exec":      execID,
 	}), execs.exitIOch, execs.stdinCloser, tty, stdin, stdout, stderr)
 
-	go wait(ctx, s, c, execID)
-
-	return execs, nil
+	return nil
 }
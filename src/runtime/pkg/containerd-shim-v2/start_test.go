@@ -13,9 +13,11 @@ import (
 	"github.com/containerd/containerd/namespaces"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
 
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -48,8 +50,10 @@ func TestStartStartSandboxSuccess(t *testing.T) {
 		ctx:        namespaces.WithNamespace(context.Background(), "UnitTest"),
 	}
 
+	// the shim state is saved in the bundle of the sandbox
 	reqCreate := &taskAPI.CreateTaskRequest{
-		ID: testSandboxID,
+		ID:     testSandboxID,
+		Bundle: t.TempDir(),
 	}
 	s.containers[testSandboxID], err = newContainer(s, reqCreate, vc.PodSandbox, nil, false)
 	assert.NoError(err)
@@ -177,3 +181,58 @@ func TestStartStartContainerSucess(t *testing.T) {
 	_, err = s.Start(ctx, reqStart)
 	assert.NoError(err)
 }
+
+func TestStartContainerHookFailure(t *testing.T) {
+	assert := assert.New(t)
+	defer katautils.ClearHookResults(testContainerID)
+	var err error
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	sandbox.StatusContainerFunc = func(contID string) (vc.ContainerStatus, error) {
+		return vc.ContainerStatus{
+			ID: testContainerID,
+			Annotations: map[string]string{
+				vcAnnotations.ContainerTypeKey: string(vc.PodContainer),
+			},
+		}, nil
+	}
+
+	started := false
+	sandbox.StartContainerFunc = func(contID string) (vc.VCContainer, error) {
+		started = true
+		return &vcmock.Container{}, nil
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+		ctx:        namespaces.WithNamespace(context.Background(), "UnitTest"),
+	}
+
+	spec := &specs.Spec{
+		Hooks: &specs.Hooks{
+			StartContainer: []specs.Hook{{Path: "/bin/false", Args: []string{"false"}}},
+		},
+	}
+
+	reqCreate := &taskAPI.CreateTaskRequest{
+		ID: testContainerID,
+	}
+	s.containers[testContainerID], err = newContainer(s, reqCreate, vc.PodContainer, spec, false)
+	assert.NoError(err)
+
+	reqStart := &taskAPI.StartRequest{
+		ID: testContainerID,
+	}
+
+	// The container process must not run when a start container hook
+	// fails.
+	ctx := namespaces.WithNamespace(context.Background(), "UnitTest")
+	_, err = s.Start(ctx, reqStart)
+	assert.Error(err)
+	assert.False(started)
+}
@@ -31,6 +31,9 @@ const (
 	// DeviceGeneric is a generic device type
 	DeviceGeneric DeviceType = "generic"
 
+	// DeviceVDPA is the vhost-vdpa device type
+	DeviceVDPA DeviceType = "vdpa"
+
 	//VhostUserSCSI - SCSI based vhost-user type
 	VhostUserSCSI = "vhost-user-scsi-pci"
 
@@ -150,12 +153,44 @@ type DeviceInfo struct {
 	// for a nvdimm device in the guest.
 	Pmem bool
 
+	// BackingFile means HostPath is not a host device but an image file
+	// or a vhost-user socket, used as is to back the device. Such devices
+	// have no major and minor numbers and are identified by HostPath.
+	BackingFile bool
+
 	// If applicable, should this device be considered RO
 	ReadOnly bool
 
 	// ColdPlug specifies whether the device must be cold plugged (true)
 	// or hot plugged (false).
 	ColdPlug bool
+
+	// RateLimiter sets the I/O limits of a block device. When nil, the
+	// hypervisor wide disk rate limiter settings apply.
+	RateLimiter *BlockRateLimiter `json:",omitempty"`
+}
+
+// BlockRateLimiter represents the I/O limits of a block device.
+// A zero value means no limit.
+type BlockRateLimiter struct {
+	// BwMaxRate is the bandwidth limit, in bits per second
+	BwMaxRate int64
+
+	// BwOneTimeBurst is the bandwidth allowed on top of BwMaxRate
+	// during a one second burst, in bits
+	BwOneTimeBurst int64
+
+	// OpsMaxRate is the operations limit, in operations per second
+	OpsMaxRate int64
+
+	// OpsOneTimeBurst is the number of operations allowed on top of
+	// OpsMaxRate during a one second burst
+	OpsOneTimeBurst int64
+}
+
+// IsEmpty returns true if the rate limiter sets no limit at all.
+func (rl *BlockRateLimiter) IsEmpty() bool {
+	return rl == nil || (rl.BwMaxRate == 0 && rl.OpsMaxRate == 0)
 }
 
 // BlockDrive represents a block storage drive which may be used in case the storage
@@ -186,6 +221,10 @@ type BlockDrive struct {
 	// DevNo identifies the css bus id for virtio-blk-ccw
 	DevNo string
 
+	// ThrottleGroup is the hypervisor throttle group limiting the I/O
+	// of the drive, if any.
+	ThrottleGroup string
+
 	// PCIPath is the PCI path used to identify the slot at which the drive is attached.
 	PCIPath vcTypes.PciPath
 
@@ -204,6 +243,10 @@ type BlockDrive struct {
 
 	// This block device is for swap
 	Swap bool
+
+	// RateLimiter sets the I/O limits of the drive. When nil, the
+	// hypervisor wide disk rate limiter settings apply.
+	RateLimiter *BlockRateLimiter `json:",omitempty"`
 }
 
 // VFIOMode indicates e behaviour mode for handling devices in the VM
@@ -287,6 +330,35 @@ type VFIODev struct {
 	IsPCIe bool
 }
 
+// VDPADeviceType is the virtio device class of a vDPA device
+type VDPADeviceType string
+
+const (
+	// VDPABlock is a vDPA block device
+	VDPABlock VDPADeviceType = "block"
+
+	// VDPANet is a vDPA network device
+	VDPANet VDPADeviceType = "net"
+)
+
+// VDPADev represents a vhost-vdpa device used for hotplugging
+type VDPADev struct {
+	// ID is used to identify this device in the hypervisor options.
+	ID string
+
+	// HostPath is the vhost-vdpa character device on the host,
+	// e.g. /dev/vhost-vdpa-0
+	HostPath string
+
+	// Type is the virtio device class of the device. The devices saved
+	// before it was recorded have none, they are block devices.
+	Type VDPADeviceType `json:",omitempty"`
+
+	// PCIPath is the PCI path used to identify the slot at which
+	// the device is attached in the guest.
+	PCIPath vcTypes.PciPath
+}
+
 // RNGDev represents a random number generator device
 type RNGDev struct {
 	// ID is used to identify the device in the hypervisor options.
@@ -485,6 +557,9 @@ type DeviceState struct {
 	// VFIODev is specific VFIO device driver
 	VFIODevs []*VFIODev `json:",omitempty"`
 
+	// VDPADev is specific for vhost-vdpa device driver
+	VDPADev *VDPADev `json:",omitempty"`
+
 	RefCount    uint
 	AttachCount uint
 
@@ -68,3 +68,17 @@ func TestGetSysDevPathImpl(t *testing.T) {
 	assert.Contains(path, expectedFormat)
 	assert.Contains(path, "block")
 }
+
+func TestBlockRateLimiterIsEmpty(t *testing.T) {
+	var rl *BlockRateLimiter
+	assert.True(t, rl.IsEmpty())
+
+	rl = &BlockRateLimiter{BwOneTimeBurst: 10, OpsOneTimeBurst: 10}
+	assert.True(t, rl.IsEmpty())
+
+	rl.BwMaxRate = 10
+	assert.False(t, rl.IsEmpty())
+
+	rl = &BlockRateLimiter{OpsMaxRate: 10}
+	assert.False(t, rl.IsEmpty())
+}
@@ -73,6 +73,8 @@ func (device *BlockDevice) Attach(ctx context.Context, devReceiver api.DeviceRec
 		Index:    index,
 		Pmem:     device.DeviceInfo.Pmem,
 		ReadOnly: device.DeviceInfo.ReadOnly,
+
+		RateLimiter: device.DeviceInfo.RateLimiter,
 	}
 
 	if fs, ok := device.DeviceInfo.DriverOptions[config.FsTypeOpt]; ok {
@@ -0,0 +1,177 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// vhostVDPAGetDeviceID is the VHOST_VDPA_GET_DEVICE_ID ioctl request,
+// _IOR(VHOST_VIRTIO, 0x70, __u32).
+const vhostVDPAGetDeviceID = 0x8004af70
+
+// The virtio device ids of the vDPA device classes, from the virtio
+// specification.
+const (
+	virtioIDNet   = 1
+	virtioIDBlock = 2
+)
+
+// GetVDPADeviceTypeFunc is function pointer used to mock GetVDPADeviceType
+// in tests.
+var GetVDPADeviceTypeFunc = GetVDPADeviceType
+
+// GetVDPADeviceType returns the class of the vhost-vdpa device hostPath. The
+// virtio device id is not exposed in sysfs for the vDPA devices bound to
+// vhost_vdpa, it is queried from the vhost-vdpa device itself.
+func GetVDPADeviceType(hostPath string) (config.VDPADeviceType, error) {
+	f, err := os.Open(hostPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	id, err := unix.IoctlGetUint32(int(f.Fd()), vhostVDPAGetDeviceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the virtio device id of %s: %v", hostPath, err)
+	}
+
+	return vdpaDeviceTypeFromVirtioID(id)
+}
+
+func vdpaDeviceTypeFromVirtioID(id uint32) (config.VDPADeviceType, error) {
+	switch id {
+	case virtioIDNet:
+		return config.VDPANet, nil
+	case virtioIDBlock:
+		return config.VDPABlock, nil
+	}
+
+	return "", fmt.Errorf("unsupported vDPA device class, virtio device id %d", id)
+}
+
+// VDPADevice is a vhost-vdpa based device, e.g. a hardware vDPA block or
+// network device or one created through the vdpa_sim_blk kernel module.
+type VDPADevice struct {
+	*GenericDevice
+	VDPADev *config.VDPADev
+}
+
+// NewVDPADevice creates a new vhost-vdpa device based on DeviceInfo
+func NewVDPADevice(devInfo *config.DeviceInfo) *VDPADevice {
+	return &VDPADevice{
+		GenericDevice: &GenericDevice{
+			ID:         devInfo.ID,
+			DeviceInfo: devInfo,
+		},
+	}
+}
+
+// Attach is standard interface of api.Device, it's used to add device to some
+// DeviceReceiver
+func (device *VDPADevice) Attach(ctx context.Context, devReceiver api.DeviceReceiver) (err error) {
+	skip, err := device.bumpAttachCount(true)
+	if err != nil {
+		return err
+	}
+	if skip {
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			device.bumpAttachCount(false)
+		}
+	}()
+
+	devType, err := GetVDPADeviceTypeFunc(device.DeviceInfo.HostPath)
+	if err != nil {
+		return err
+	}
+
+	vdpaDev := &config.VDPADev{
+		ID:       utils.MakeNameID("vdpa", device.DeviceInfo.ID, maxDevIDSize),
+		HostPath: device.DeviceInfo.HostPath,
+		Type:     devType,
+	}
+
+	deviceLogger().WithFields(logrus.Fields{
+		"device": vdpaDev.HostPath,
+		"ID":     vdpaDev.ID,
+		"type":   vdpaDev.Type,
+	}).Info("Attaching vhost-vdpa device")
+
+	device.VDPADev = vdpaDev
+	if err = devReceiver.HotplugAddDevice(ctx, device, config.DeviceVDPA); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Detach is standard interface of api.Device, it's used to remove device from some
+// DeviceReceiver
+func (device *VDPADevice) Detach(ctx context.Context, devReceiver api.DeviceReceiver) (err error) {
+	skip, err := device.bumpAttachCount(false)
+	if err != nil {
+		return err
+	}
+	if skip {
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			device.bumpAttachCount(true)
+		}
+	}()
+
+	deviceLogger().WithField("device", device.DeviceInfo.HostPath).Info("Unplugging vhost-vdpa device")
+
+	if err = devReceiver.HotplugRemoveDevice(ctx, device, config.DeviceVDPA); err != nil {
+		deviceLogger().WithError(err).Error("Failed to unplug vhost-vdpa device")
+		return err
+	}
+
+	return nil
+}
+
+// DeviceType is standard interface of api.Device, it returns device type
+func (device *VDPADevice) DeviceType() config.DeviceType {
+	return config.DeviceVDPA
+}
+
+// GetDeviceInfo returns device information used for creating
+func (device *VDPADevice) GetDeviceInfo() interface{} {
+	return device.VDPADev
+}
+
+// Save converts Device to DeviceState
+func (device *VDPADevice) Save() config.DeviceState {
+	ds := device.GenericDevice.Save()
+	ds.Type = string(device.DeviceType())
+	ds.VDPADev = device.VDPADev
+
+	return ds
+}
+
+// Load loads DeviceState and converts it to specific device
+func (device *VDPADevice) Load(ds config.DeviceState) {
+	device.GenericDevice = &GenericDevice{}
+	device.GenericDevice.Load(ds)
+	device.VDPADev = ds.VDPADev
+}
+
+// It should implement GetAttachCount() and DeviceID() as api.Device implementation
+// here it shares function from *GenericDevice so we don't need duplicate codes
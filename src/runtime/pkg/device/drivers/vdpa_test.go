@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package drivers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVDPADeviceTypeFromVirtioID(t *testing.T) {
+	assert := assert.New(t)
+
+	devType, err := vdpaDeviceTypeFromVirtioID(virtioIDNet)
+	assert.NoError(err)
+	assert.Equal(config.VDPANet, devType)
+
+	devType, err = vdpaDeviceTypeFromVirtioID(virtioIDBlock)
+	assert.NoError(err)
+	assert.Equal(config.VDPABlock, devType)
+
+	// virtio-console
+	_, err = vdpaDeviceTypeFromVirtioID(3)
+	assert.Error(err)
+}
+
+func TestVDPADeviceAttach(t *testing.T) {
+	assert := assert.New(t)
+
+	savedFunc := GetVDPADeviceTypeFunc
+	defer func() {
+		GetVDPADeviceTypeFunc = savedFunc
+	}()
+
+	path := "/dev/vhost-vdpa-0"
+	GetVDPADeviceTypeFunc = func(hostPath string) (config.VDPADeviceType, error) {
+		assert.Equal(path, hostPath)
+		return config.VDPANet, nil
+	}
+
+	device := NewVDPADevice(&config.DeviceInfo{
+		ID:       "vdpa",
+		HostPath: path,
+		DevType:  "c",
+	})
+
+	devReceiver := &api.MockDeviceReceiver{}
+	assert.NoError(device.Attach(context.Background(), devReceiver))
+
+	vdpaDev, ok := device.GetDeviceInfo().(*config.VDPADev)
+	assert.True(ok)
+	assert.Equal(path, vdpaDev.HostPath)
+	assert.Equal(config.VDPANet, vdpaDev.Type)
+	assert.NotEmpty(vdpaDev.ID)
+
+	ds := device.Save()
+	assert.Equal(string(config.DeviceVDPA), ds.Type)
+	assert.Equal(vdpaDev, ds.VDPADev)
+
+	assert.NoError(device.Detach(context.Background(), devReceiver))
+}
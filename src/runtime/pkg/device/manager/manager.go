@@ -85,11 +85,23 @@ func (dm *deviceManager) findDeviceByMajorMinor(major, minor int64) api.Device {
 	return nil
 }
 
+// findDeviceByHostPath finds the device backed by the image file or socket
+// hostPath.
+func (dm *deviceManager) findDeviceByHostPath(hostPath string) api.Device {
+	for _, dev := range dm.devices {
+		if dev.GetHostPath() == hostPath {
+			return dev
+		}
+	}
+	return nil
+}
+
 // createDevice creates one device based on DeviceInfo
 func (dm *deviceManager) createDevice(devInfo config.DeviceInfo) (dev api.Device, err error) {
 	// pmem device may points to block devices or raw files,
+	// and devices backed by files use their file as is,
 	// do not change its HostPath.
-	if !devInfo.Pmem {
+	if !devInfo.Pmem && !devInfo.BackingFile {
 		path, err := config.GetHostPathFunc(devInfo, dm.vhostUserStoreEnabled, dm.vhostUserStorePath)
 		if err != nil {
 			return nil, err
@@ -103,7 +115,11 @@ func (dm *deviceManager) createDevice(devInfo config.DeviceInfo) (dev api.Device
 		}
 	}()
 
-	if existingDev := dm.findDeviceByMajorMinor(devInfo.Major, devInfo.Minor); existingDev != nil {
+	if devInfo.BackingFile {
+		if existingDev := dm.findDeviceByHostPath(devInfo.HostPath); existingDev != nil {
+			return existingDev, nil
+		}
+	} else if existingDev := dm.findDeviceByMajorMinor(devInfo.Major, devInfo.Minor); existingDev != nil {
 		return existingDev, nil
 	}
 
@@ -114,6 +130,8 @@ func (dm *deviceManager) createDevice(devInfo config.DeviceInfo) (dev api.Device
 	}
 	if isVFIO(devInfo.HostPath) {
 		return drivers.NewVFIODevice(&devInfo), nil
+	} else if isVDPA(devInfo.HostPath) {
+		return drivers.NewVDPADevice(&devInfo), nil
 	} else if isVhostUserBlk(devInfo) {
 		if devInfo.DriverOptions == nil {
 			devInfo.DriverOptions = make(map[string]string)
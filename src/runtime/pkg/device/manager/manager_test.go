@@ -8,6 +8,7 @@ package manager
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -90,6 +91,51 @@ func TestNewDevice(t *testing.T) {
 	assert.Equal(t, vfioDev.DeviceInfo.GID, uint32(2))
 }
 
+func TestNewBackingFileDevice(t *testing.T) {
+	dm := &deviceManager{
+		blockDriver: config.VirtioBlock,
+		devices:     make(map[string]api.Device),
+	}
+
+	dir := t.TempDir()
+	deviceInfo := config.DeviceInfo{
+		HostPath:      filepath.Join(dir, "volume1.img"),
+		ContainerPath: "/data1",
+		DevType:       "b",
+		Major:         -1,
+		Minor:         -1,
+		BackingFile:   true,
+	}
+
+	device, err := dm.NewDevice(deviceInfo)
+	assert.Nil(t, err)
+	_, ok := device.(*drivers.BlockDevice)
+	assert.True(t, ok)
+	// the image file is used as is
+	assert.Equal(t, deviceInfo.HostPath, device.GetHostPath())
+
+	// the same image file gives the same device
+	same, err := dm.NewDevice(deviceInfo)
+	assert.Nil(t, err)
+	assert.Equal(t, device.DeviceID(), same.DeviceID())
+
+	// another image file gives another device, despite the major and minor numbers
+	deviceInfo.HostPath = filepath.Join(dir, "volume2.img")
+	deviceInfo.ContainerPath = "/data2"
+	other, err := dm.NewDevice(deviceInfo)
+	assert.Nil(t, err)
+	assert.NotEqual(t, device.DeviceID(), other.DeviceID())
+
+	// vhost-user-blk sockets give vhost-user-blk devices
+	deviceInfo.HostPath = filepath.Join(dir, "vhost-user-blk.sock")
+	deviceInfo.Major = config.VhostUserBlkMajor
+	socket, err := dm.NewDevice(deviceInfo)
+	assert.Nil(t, err)
+	_, ok = socket.(*drivers.VhostUserBlkDevice)
+	assert.True(t, ok)
+	assert.Equal(t, deviceInfo.HostPath, socket.GetHostPath())
+}
+
 func TestAttachVFIODevice(t *testing.T) {
 	dm := &deviceManager{
 		blockDriver: config.VirtioBlock,
@@ -172,6 +218,55 @@ func TestAttachGenericDevice(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAttachVDPADevice(t *testing.T) {
+	dm := &deviceManager{
+		blockDriver: config.VirtioBlock,
+		devices:     make(map[string]api.Device),
+	}
+	path := "/dev/vhost-vdpa-0"
+	deviceInfo := config.DeviceInfo{
+		HostPath:      path,
+		ContainerPath: path,
+		DevType:       "c",
+	}
+
+	savedFunc := drivers.GetVDPADeviceTypeFunc
+	defer func() {
+		drivers.GetVDPADeviceTypeFunc = savedFunc
+	}()
+
+	drivers.GetVDPADeviceTypeFunc = func(hostPath string) (config.VDPADeviceType, error) {
+		if hostPath != path {
+			return "", fmt.Errorf("unexpected vhost-vdpa device %s", hostPath)
+		}
+		return config.VDPABlock, nil
+	}
+
+	device, err := dm.NewDevice(deviceInfo)
+	assert.Nil(t, err)
+	vdpaDevice, ok := device.(*drivers.VDPADevice)
+	assert.True(t, ok)
+
+	devReceiver := &api.MockDeviceReceiver{}
+	err = device.Attach(context.Background(), devReceiver)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), vdpaDevice.GetAttachCount())
+
+	vdpaDev, ok := vdpaDevice.GetDeviceInfo().(*config.VDPADev)
+	assert.True(t, ok)
+	assert.Equal(t, path, vdpaDev.HostPath)
+	assert.Equal(t, config.VDPABlock, vdpaDev.Type)
+	assert.NotEmpty(t, vdpaDev.ID)
+
+	ds := vdpaDevice.Save()
+	assert.Equal(t, string(config.DeviceVDPA), ds.Type)
+	assert.Equal(t, vdpaDev, ds.VDPADev)
+
+	err = device.Detach(context.Background(), devReceiver)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(0), vdpaDevice.GetAttachCount())
+}
+
 func TestAttachBlockDevice(t *testing.T) {
 	dm := &deviceManager{
 		blockDriver: config.VirtioBlock,
@@ -15,6 +15,8 @@ import (
 
 const (
 	vfioPath = "/dev/vfio/"
+
+	vhostVDPAPathPrefix = "/dev/vhost-vdpa-"
 )
 
 // isVFIO checks if the device provided is a vfio group.
@@ -31,6 +33,11 @@ func isVFIO(hostPath string) bool {
 	return false
 }
 
+// isVDPA checks if the device provided is a vhost-vdpa character device.
+func isVDPA(hostPath string) bool {
+	return strings.HasPrefix(hostPath, vhostVDPAPathPrefix) && len(hostPath) > len(vhostVDPAPathPrefix)
+}
+
 // isBlock checks if the device is a block device.
 func isBlock(devInfo config.DeviceInfo) bool {
 	return devInfo.DevType == "b"
@@ -36,6 +36,26 @@ func TestIsVFIO(t *testing.T) {
 	}
 }
 
+func TestIsVDPA(t *testing.T) {
+	type testData struct {
+		path     string
+		expected bool
+	}
+
+	data := []testData{
+		{"/dev/vhost-vdpa-0", true},
+		{"/dev/vhost-vdpa-12", true},
+		{"/dev/vhost-vdpa-", false},
+		{"/dev/vhost-net", false},
+		{"/dev/vfio/1", false},
+	}
+
+	for _, d := range data {
+		isVDPA := isVDPA(d.path)
+		assert.Equal(t, d.expected, isVDPA)
+	}
+}
+
 func TestIsBlock(t *testing.T) {
 	type testData struct {
 		devType  string
@@ -12,11 +12,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const (
 	mountInfoFileName = "mountInfo.json"
 
+	// BlockVolumeType is a host block device, passed to the guest as is.
+	// It is the default volume type.
+	BlockVolumeType = "block"
+	// FileVolumeType is a pre-formatted image file, on a local or on a
+	// network host filesystem, passed to the guest as a block device.
+	FileVolumeType = "file"
+	// VhostUserBlkVolumeType is the socket of a vhost-user-blk backend,
+	// SPDK for instance, passed to the guest as a vhost-user block device.
+	VhostUserBlkVolumeType = "vhost-user-blk"
+	// NFSVolumeType is an NFS export, server:/path, mounted on the host
+	// and passed to the guest through the shared filesystem.
+	NFSVolumeType = "nfs"
+
 	FSGroupMetadataKey             = "fsGroup"
 	FSGroupChangePolicyMetadataKey = "fsGroupChangePolicy"
 )
@@ -39,13 +53,14 @@ const (
 
 var kataDirectVolumeRootPath = "/run/kata-containers/shared/direct-volumes"
 
-// MountInfo contains the information needed by Kata to consume a host block device and mount it as a filesystem inside the guest VM.
+// MountInfo contains the information needed by Kata to consume a host block device, image file,
+// vhost-user-blk socket or NFS export and mount it as a filesystem inside the guest VM.
 type MountInfo struct {
-	// The type of the volume (ie. block)
+	// The type of the volume (ie. block, file, vhost-user-blk or nfs), block if empty.
 	VolumeType string `json:"volume-type"`
-	// The device backing the volume.
+	// The device, image file, vhost-user-blk socket or NFS export backing the volume.
 	Device string `json:"device"`
-	// The filesystem type to be mounted on the volume.
+	// The filesystem type to be mounted on the volume, nfs or nfs4 for NFS volumes.
 	FsType string `json:"fstype"`
 	// Additional metadata to pass to the agent regarding this volume.
 	Metadata map[string]string `json:"metadata,omitempty"`
@@ -53,6 +68,81 @@ type MountInfo struct {
 	Options []string `json:"options,omitempty"`
 }
 
+// Type returns the type of the volume.
+func (m *MountInfo) Type() string {
+	if m.VolumeType == "" {
+		return BlockVolumeType
+	}
+	return m.VolumeType
+}
+
+// Validate checks that the mount info describes a volume of a known type,
+// with a device.
+func (m *MountInfo) Validate() error {
+	switch m.Type() {
+	case BlockVolumeType, FileVolumeType, VhostUserBlkVolumeType, NFSVolumeType:
+	default:
+		return fmt.Errorf("unsupported volume type %q", m.VolumeType)
+	}
+
+	if m.Device == "" {
+		return errors.New("the volume device is missing")
+	}
+
+	if m.Type() == NFSVolumeType {
+		switch m.FsType {
+		case "", "nfs", "nfs4":
+		default:
+			return fmt.Errorf("unsupported filesystem type %q for a nfs volume", m.FsType)
+		}
+		if server, export, ok := strings.Cut(m.Device, ":"); !ok || server == "" || !filepath.IsAbs(export) {
+			return fmt.Errorf("%s is not a nfs export, expected server:/path", m.Device)
+		}
+	}
+
+	return nil
+}
+
+// NFSFsType returns the filesystem type used to mount a NFS volume.
+func (m *MountInfo) NFSFsType() string {
+	if m.FsType == "" {
+		return "nfs"
+	}
+	return m.FsType
+}
+
+// CheckDevice checks that the device of the volume exists and matches the
+// volume type. NFS exports are only reachable once mounted, so they are not
+// checked.
+func (m *MountInfo) CheckDevice() error {
+	if m.Type() == NFSVolumeType {
+		return nil
+	}
+
+	info, err := os.Stat(m.Device)
+	if err != nil {
+		return err
+	}
+
+	mode := info.Mode()
+	switch m.Type() {
+	case BlockVolumeType:
+		if mode&os.ModeDevice == 0 || mode&os.ModeCharDevice != 0 {
+			return fmt.Errorf("%s is not a block device", m.Device)
+		}
+	case FileVolumeType:
+		if !mode.IsRegular() {
+			return fmt.Errorf("%s is not a regular file", m.Device)
+		}
+	case VhostUserBlkVolumeType:
+		if mode&os.ModeSocket == 0 {
+			return fmt.Errorf("%s is not a socket", m.Device)
+		}
+	}
+
+	return nil
+}
+
 // Add writes the mount info of a direct volume into a filesystem path known to Kata Container.
 func Add(volumePath string, mountInfo string) error {
 	volumeDir := filepath.Join(kataDirectVolumeRootPath, b64.URLEncoding.EncodeToString([]byte(volumePath)))
@@ -73,6 +163,9 @@ func Add(volumePath string, mountInfo string) error {
 	if err := json.Unmarshal([]byte(mountInfo), &deserialized); err != nil {
 		return err
 	}
+	if err := deserialized.Validate(); err != nil {
+		return err
+	}
 
 	return os.WriteFile(filepath.Join(volumeDir, mountInfoFileName), []byte(mountInfo), 0600)
 }
@@ -9,6 +9,7 @@ import (
 	b64 "encoding/base64"
 	"encoding/json"
 	"errors"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
@@ -92,3 +93,63 @@ func TestRecordSandboxIdNoMountInfoFile(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, os.ErrNotExist))
 }
+
+func TestMountInfoValidate(t *testing.T) {
+	for _, d := range []struct {
+		mountInfo MountInfo
+		valid     bool
+	}{
+		{MountInfo{Device: "/dev/sda", FsType: "ext4"}, true},
+		{MountInfo{VolumeType: BlockVolumeType, Device: "/dev/sda", FsType: "ext4"}, true},
+		{MountInfo{VolumeType: FileVolumeType, Device: "/mnt/nfs/volume.img", FsType: "xfs"}, true},
+		{MountInfo{VolumeType: VhostUserBlkVolumeType, Device: "/run/spdk/vhost-blk.sock", FsType: "ext4"}, true},
+		{MountInfo{VolumeType: "nbd", Device: "/dev/nbd0", FsType: "ext4"}, false},
+		{MountInfo{VolumeType: FileVolumeType, FsType: "ext4"}, false},
+		{MountInfo{VolumeType: FileVolumeType, Device: "/mnt/nfs/volume.img"}, true},
+		{MountInfo{Device: "/dev/sda"}, true},
+		{MountInfo{VolumeType: NFSVolumeType, Device: "nfs.example.com:/exports/volume"}, true},
+		{MountInfo{VolumeType: NFSVolumeType, Device: "10.0.0.1:/exports/volume", FsType: "nfs4"}, true},
+		{MountInfo{VolumeType: NFSVolumeType, Device: "nfs.example.com:/exports/volume", FsType: "ext4"}, false},
+		{MountInfo{VolumeType: NFSVolumeType, Device: "/exports/volume"}, false},
+		{MountInfo{VolumeType: NFSVolumeType, Device: ":/exports/volume"}, false},
+		{MountInfo{VolumeType: NFSVolumeType, Device: "nfs.example.com:exports"}, false},
+	} {
+		err := d.mountInfo.Validate()
+		assert.Equal(t, d.valid, err == nil, "%+v: %v", d.mountInfo, err)
+	}
+
+	// invalid mount infos are not added
+	kataDirectVolumeRootPath = t.TempDir()
+	assert.Error(t, Add("/a/b/c", "{}"))
+	_, err := VolumeMountInfo("/a/b/c")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestMountInfoCheckDevice(t *testing.T) {
+	dir := t.TempDir()
+
+	image := filepath.Join(dir, "volume.img")
+	assert.Nil(t, os.WriteFile(image, nil, 0600))
+
+	socket := filepath.Join(dir, "vhost-blk.sock")
+	l, err := net.Listen("unix", socket)
+	assert.Nil(t, err)
+	defer l.Close()
+
+	for _, d := range []struct {
+		mountInfo MountInfo
+		valid     bool
+	}{
+		{MountInfo{VolumeType: FileVolumeType, Device: image}, true},
+		{MountInfo{VolumeType: FileVolumeType, Device: dir}, false},
+		{MountInfo{VolumeType: FileVolumeType, Device: filepath.Join(dir, "missing.img")}, false},
+		{MountInfo{VolumeType: VhostUserBlkVolumeType, Device: socket}, true},
+		{MountInfo{VolumeType: VhostUserBlkVolumeType, Device: image}, false},
+		{MountInfo{VolumeType: BlockVolumeType, Device: image}, false},
+		{MountInfo{Device: socket}, false},
+		{MountInfo{VolumeType: NFSVolumeType, Device: "nfs.example.com:/exports/volume"}, true},
+	} {
+		err := d.mountInfo.CheckDevice()
+		assert.Equal(t, d.valid, err == nil, "%+v: %v", d.mountInfo, err)
+	}
+}
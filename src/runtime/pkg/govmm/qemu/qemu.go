@@ -111,6 +111,9 @@ const (
 	// VfioPCI is the vfio driver with PCI transport.
 	VfioPCI DeviceDriver = "vfio-pci"
 
+	// VhostVDPADevicePCI is the generic vhost-vdpa device driver with PCI transport.
+	VhostVDPADevicePCI DeviceDriver = "vhost-vdpa-device-pci"
+
 	// VfioCCW is the vfio driver with CCW transport.
 	VfioCCW DeviceDriver = "vfio-ccw"
 
@@ -128,6 +131,18 @@ const (
 
 	// SpaprTPMProxy is used for enabling guest to run in secure mode on ppc64le.
 	SpaprTPMProxy DeviceDriver = "spapr-tpm-proxy"
+
+	// TPMCRB is the TPM Command Response Buffer interface device driver.
+	TPMCRB DeviceDriver = "tpm-crb"
+
+	// TPMTIS is the TPM Interface Specification ISA device driver.
+	TPMTIS DeviceDriver = "tpm-tis"
+
+	// TPMTISDevice is the TPM Interface Specification sysbus device driver.
+	TPMTISDevice DeviceDriver = "tpm-tis-device"
+
+	// TPMSpapr is the TPM device driver for pseries machines.
+	TPMSpapr DeviceDriver = "tpm-spapr"
 )
 
 func isDimmSupported(config *Config) bool {
@@ -222,6 +237,9 @@ const (
 	// MemoryBackendFile represents a guest memory mapped file.
 	MemoryBackendFile ObjectType = "memory-backend-file"
 
+	// MemoryBackendRAM represents a guest anonymous memory.
+	MemoryBackendRAM ObjectType = "memory-backend-ram"
+
 	// MemoryBackendEPC represents a guest memory backend EPC for SGX.
 	MemoryBackendEPC ObjectType = "memory-backend-epc"
 
@@ -739,6 +757,13 @@ const (
 
 	// VHOSTUSER is a vhost-user port (socket)
 	VHOSTUSER NetDeviceType = "vhostuser"
+
+	// VHOSTVDPA is a vhost-vdpa character device backed by a vDPA parent
+	VHOSTVDPA NetDeviceType = "vhost-vdpa"
+
+	// STREAM is a stream socket connected to a userspace network backend,
+	// e.g., passt.
+	STREAM NetDeviceType = "stream"
 )
 
 // QemuNetdevParam converts to the QEMU -netdev parameter notation
@@ -766,6 +791,10 @@ func (n NetDeviceType) QemuNetdevParam(netdev *NetDevice, config *Config) string
 			log.Fatal("vhost-user devices are not supported on IBM Z")
 		}
 		return "vhost-user" // -netdev type=vhost-user (no device)
+	case VHOSTVDPA:
+		return "vhost-vdpa" // -netdev type=vhost-vdpa -device virtio-net-pci
+	case STREAM:
+		return "stream" // -netdev type=stream -device virtio-net-pci
 	default:
 		return ""
 
@@ -799,6 +828,10 @@ func (n NetDeviceType) QemuDeviceParam(netdev *NetDevice, config *Config) Device
 			log.Fatal("vhost-user devices are not supported on IBM Z")
 		}
 		return "" // -netdev type=vhost-user (no device)
+	case VHOSTVDPA:
+		device = "virtio-net" // -netdev type=vhost-vdpa -device virtio-net-pci
+	case STREAM:
+		device = "virtio-net" // -netdev type=stream -device virtio-net-pci
 	default:
 		return ""
 	}
@@ -850,6 +883,13 @@ type NetDevice struct {
 	// VHost enables virtio device emulation from the host kernel instead of from qemu.
 	VHost bool
 
+	// VhostDev is the vhost-vdpa character device backing a VHOSTVDPA netdev.
+	VhostDev string
+
+	// SocketPath is the UNIX socket of the userspace network backend a
+	// STREAM netdev connects to.
+	SocketPath string
+
 	// MACAddress is the networking device interface MAC address.
 	MACAddress string
 
@@ -876,6 +916,14 @@ var VirtioNetTransport = map[VirtioTransport]string{
 
 // Valid returns true if the NetDevice structure is valid and complete.
 func (netdev NetDevice) Valid() bool {
+	if netdev.Type == VHOSTVDPA {
+		return netdev.ID != "" && netdev.VhostDev != ""
+	}
+
+	if netdev.Type == STREAM {
+		return netdev.ID != "" && netdev.SocketPath != ""
+	}
+
 	if netdev.ID == "" || netdev.IFName == "" {
 		return false
 	}
@@ -972,6 +1020,19 @@ func (netdev NetDevice) QemuNetdevParams(config *Config) []string {
 	netdevParams = append(netdevParams, netdevType)
 	netdevParams = append(netdevParams, fmt.Sprintf("id=%s", netdev.ID))
 
+	if netdev.Type == VHOSTVDPA {
+		// the vhost-vdpa device carries the datapath, there is no tap interface
+		netdevParams = append(netdevParams, fmt.Sprintf("vhostdev=%s", netdev.VhostDev))
+		return netdevParams
+	}
+
+	if netdev.Type == STREAM {
+		// the userspace backend carries the datapath, QEMU connects to it
+		netdevParams = append(netdevParams, "server=off", "addr.type=unix")
+		netdevParams = append(netdevParams, fmt.Sprintf("addr.path=%s", netdev.SocketPath))
+		return netdevParams
+	}
+
 	if netdev.VHost {
 		netdevParams = append(netdevParams, "vhost=on")
 		if len(netdev.VhostFDs) > 0 {
@@ -1212,6 +1273,24 @@ type BlockDevice struct {
 	// ReadOnly sets the block device in readonly mode
 	ReadOnly bool
 
+	// DevID is the id of the guest device, for QMP to refer to it. The
+	// device is left without an id when empty.
+	DevID string
+
+	// ThrottleGroup is the throttle group the block device I/O is
+	// limited by. When the device is cold plugged, the group is created
+	// along with it, with ThrottleLimits.
+	ThrottleGroup string
+
+	// ThrottleLimits are the limits of ThrottleGroup when the device is
+	// cold plugged.
+	ThrottleLimits ThrottleLimits
+
+	// ThrottledNodeName is the node name of the block node limited by
+	// ThrottleGroup, so that snapshots and block jobs can refer to it.
+	// The node is left unnamed when empty.
+	ThrottledNodeName string
+
 	// Transport is the virtio transport for this device.
 	Transport VirtioTransport
 }
@@ -1243,6 +1322,9 @@ func (blkdev BlockDevice) QemuParams(config *Config) []string {
 	if s := blkdev.Transport.disableModern(config, blkdev.DisableModern); s != "" {
 		deviceParams = append(deviceParams, s)
 	}
+	if blkdev.DevID != "" {
+		deviceParams = append(deviceParams, fmt.Sprintf("id=%s", blkdev.DevID))
+	}
 	deviceParams = append(deviceParams, fmt.Sprintf("drive=%s", blkdev.ID))
 	if !blkdev.SCSI {
 		deviceParams = append(deviceParams, "scsi=off")
@@ -1276,6 +1358,14 @@ func (blkdev BlockDevice) QemuParams(config *Config) []string {
 		blkParams = append(blkParams, "readonly=on")
 	}
 
+	if blkdev.ThrottleGroup != "" {
+		// The drive joins the group created by -object and sets
+		// its limits, the group can then be updated through QMP.
+		qemuParams = append(qemuParams, "-object", fmt.Sprintf("throttle-group,id=%s", blkdev.ThrottleGroup))
+		blkParams = append(blkParams, fmt.Sprintf("throttling.group=%s", blkdev.ThrottleGroup))
+		blkParams = append(blkParams, blkdev.ThrottleLimits.driveParams()...)
+	}
+
 	qemuParams = append(qemuParams, "-device")
 	qemuParams = append(qemuParams, strings.Join(deviceParams, ","))
 
@@ -2173,6 +2263,44 @@ func (v RngDevice) deviceName(config *Config) string {
 	return RngDeviceTransport[v.Transport]
 }
 
+// TPMDevice represents a TPM device backed by an external TPM emulator.
+type TPMDevice struct {
+	// ID is the TPM backend ID.
+	ID string
+
+	// Path is the path to the control socket of the TPM emulator.
+	Path string
+
+	// Driver is the TPM frontend device driver.
+	Driver DeviceDriver
+}
+
+// Valid returns true if the TPMDevice structure is valid and complete.
+func (t TPMDevice) Valid() bool {
+	return t.ID != "" && t.Path != "" && t.Driver != ""
+}
+
+// QemuParams returns the qemu parameters built out of the TPMDevice.
+func (t TPMDevice) QemuParams(config *Config) []string {
+	var qemuParams []string
+
+	charID := "char" + t.ID
+
+	//-chardev socket,id=chartpm0,path=/run/vc/vm/id/swtpm.sock
+	qemuParams = append(qemuParams, "-chardev")
+	qemuParams = append(qemuParams, fmt.Sprintf("socket,id=%s,path=%s", charID, t.Path))
+
+	//-tpmdev emulator,id=tpm0,chardev=chartpm0
+	qemuParams = append(qemuParams, "-tpmdev")
+	qemuParams = append(qemuParams, fmt.Sprintf("emulator,id=%s,chardev=%s", t.ID, charID))
+
+	//-device tpm-crb,tpmdev=tpm0
+	qemuParams = append(qemuParams, "-device")
+	qemuParams = append(qemuParams, fmt.Sprintf("%s,tpmdev=%s", t.Driver, t.ID))
+
+	return qemuParams
+}
+
 // BalloonDevice represents a memory balloon device.
 // nolint: govet
 type BalloonDevice struct {
@@ -2246,6 +2374,124 @@ func (b BalloonDevice) deviceName(config *Config) string {
 	return BalloonDeviceTransport[b.Transport]
 }
 
+// VirtioMemDevice represents a virtio-mem device, plugging and unplugging
+// the memory of its backend to and from the guest by blocks of BlockSize.
+// The guest is requested to plug RequestedSize bytes of memory, which can
+// be updated at runtime with QMP.ExecuteVirtioMemSetRequestedSize.
+// nolint: govet
+type VirtioMemDevice struct {
+	// ID is the device ID.
+	ID string
+
+	// MemDev is the ID of the memory backend object of the device.
+	MemDev string
+
+	// MemPath is the file backing the memory. The memory is anonymous
+	// when empty.
+	MemPath string
+
+	// Share maps the memory as shared.
+	Share bool
+
+	// Size is the size in bytes of the memory backend, the most memory
+	// the device can plug.
+	Size uint64
+
+	// RequestedSize is the memory in bytes the guest is requested to plug.
+	RequestedSize uint64
+
+	// BlockSize is the granularity in bytes of the memory plugged. QEMU
+	// picks it when 0.
+	BlockSize uint64
+
+	// Bus is the bus the device is plugged to.
+	Bus string
+
+	// Addr is the address of the device on the bus.
+	Addr string
+
+	// ROMFile specifies the ROM file being used for this device.
+	ROMFile string
+
+	// Transport is the virtio transport for this device.
+	Transport VirtioTransport
+}
+
+// VirtioMemTransport is a map of the virtio-mem device name that
+// corresponds to each transport. virtio-mem is only available on PCI.
+var VirtioMemTransport = map[VirtioTransport]string{
+	TransportPCI: "virtio-mem-pci",
+}
+
+// memoryBackend returns the type of the memory backend of the device.
+func (dev VirtioMemDevice) memoryBackend() ObjectType {
+	if dev.MemPath != "" {
+		return MemoryBackendFile
+	}
+	return MemoryBackendRAM
+}
+
+// QemuParams returns the qemu parameters built out of the VirtioMemDevice.
+func (dev VirtioMemDevice) QemuParams(config *Config) []string {
+	var objectParams []string
+	var deviceParams []string
+
+	objectParams = append(objectParams, string(dev.memoryBackend()))
+	objectParams = append(objectParams, fmt.Sprintf("id=%s", dev.MemDev))
+	objectParams = append(objectParams, fmt.Sprintf("size=%d", dev.Size))
+	if dev.MemPath != "" {
+		objectParams = append(objectParams, fmt.Sprintf("mem-path=%s", dev.MemPath))
+	}
+	if dev.Share {
+		objectParams = append(objectParams, "share=on")
+	}
+
+	deviceParams = append(deviceParams, dev.deviceName(config))
+	deviceParams = append(deviceParams, fmt.Sprintf("id=%s", dev.ID))
+	deviceParams = append(deviceParams, fmt.Sprintf("memdev=%s", dev.MemDev))
+	deviceParams = append(deviceParams, fmt.Sprintf("requested-size=%d", dev.RequestedSize))
+	if dev.BlockSize != 0 {
+		deviceParams = append(deviceParams, fmt.Sprintf("block-size=%d", dev.BlockSize))
+	}
+	if dev.Bus != "" {
+		deviceParams = append(deviceParams, fmt.Sprintf("bus=%s", dev.Bus))
+	}
+	if dev.Addr != "" {
+		deviceParams = append(deviceParams, fmt.Sprintf("addr=%s", dev.Addr))
+	}
+	if dev.ROMFile != "" {
+		deviceParams = append(deviceParams, fmt.Sprintf("romfile=%s", dev.ROMFile))
+	}
+
+	return []string{
+		"-object", strings.Join(objectParams, ","),
+		"-device", strings.Join(deviceParams, ","),
+	}
+}
+
+// Valid returns true if the VirtioMemDevice structure is valid and complete.
+func (dev VirtioMemDevice) Valid() bool {
+	if dev.ID == "" || dev.MemDev == "" || dev.Size == 0 {
+		return false
+	}
+
+	if dev.RequestedSize > dev.Size {
+		return false
+	}
+
+	return dev.deviceName(nil) != ""
+}
+
+// deviceName returns the QEMU device name for the current combination of
+// driver and transport.
+func (dev VirtioMemDevice) deviceName(config *Config) string {
+	if dev.Transport == "" {
+		dev.Transport = dev.Transport.defaultTransport(config)
+	}
+
+	return VirtioMemTransport[dev.Transport]
+}
+
 // IommuDev represents a Intel IOMMU Device
 type IommuDev struct {
 	Intremap    bool
@@ -2426,6 +2672,26 @@ type Memory struct {
 	// Path is the file path of the memory device. It points to a local
 	// file path used by FileBackedMem.
 	Path string
+
+	// NUMANodes splits the guest memory and CPUs across several guest
+	// NUMA nodes. The node sizes must add up to Size. When empty, the
+	// guest gets a single NUMA node backed by the whole memory.
+	NUMANodes []NUMANode
+}
+
+// NUMANode is the guest NUMA node configuration structure.
+type NUMANode struct {
+	// Size is the amount of memory backing the node. It should be
+	// suffixed with M or G for sizes in megabytes or gigabytes respectively.
+	Size string
+
+	// HostNodes is the set of host NUMA nodes the node memory is bound
+	// to, e.g. "0" or "0-1". The memory is not bound when empty.
+	HostNodes string
+
+	// CPUs is the list of guest CPU ranges assigned to the node,
+	// e.g. []string{"0-1", "4"}.
+	CPUs []string
 }
 
 // Kernel is the guest kernel configuration structure.
@@ -2584,6 +2850,12 @@ type Config struct {
 	// Supplementary group IDs.
 	Groups []uint32
 
+	// SysProcAttr, when set, holds the attributes qemu is launched with
+	// rather than Uid, Gid and Groups, e.g., to run qemu in a new user
+	// namespace when the user launching qemu has no privileges to change
+	// its IDs.
+	SysProcAttr *syscall.SysProcAttr
+
 	// Name is the qemu guest name
 	Name string
 
@@ -2649,6 +2921,10 @@ type Config struct {
 	// LogFile is the -D parameter
 	LogFile string
 
+	// JSONSyntax generates the -device, -object and -blockdev parameters
+	// of the JSONDevice devices and of the I/O threads in JSON.
+	JSONSyntax bool
+
 	qemuParams []string
 }
 
@@ -2742,6 +3018,11 @@ func (config *Config) appendDevices(logger QMPLog) {
 			continue
 		}
 
+		if jsonDev, ok := d.(JSONDevice); ok && config.JSONSyntax {
+			config.qemuParams = append(config.qemuParams, jsonDev.QemuJSONParams(config)...)
+			continue
+		}
+
 		config.qemuParams = append(config.qemuParams, d.QemuParams(config)...)
 	}
 }
@@ -2864,21 +3145,14 @@ func (config *Config) appendKernel() {
 	}
 }
 
-func (config *Config) appendMemoryKnobs() {
-	if config.Memory.Size == "" {
-		return
-	}
-	var objMemParam, numaMemParam string
-	dimmName := "dimm1"
+func (config *Config) memoryBackendParam(id, size string) string {
+	var objMemParam string
 	if config.Knobs.HugePages {
-		objMemParam = "memory-backend-file,id=" + dimmName + ",size=" + config.Memory.Size + ",mem-path=/dev/hugepages"
-		numaMemParam = "node,memdev=" + dimmName
+		objMemParam = "memory-backend-file,id=" + id + ",size=" + size + ",mem-path=/dev/hugepages"
 	} else if config.Knobs.FileBackedMem && config.Memory.Path != "" {
-		objMemParam = "memory-backend-file,id=" + dimmName + ",size=" + config.Memory.Size + ",mem-path=" + config.Memory.Path
-		numaMemParam = "node,memdev=" + dimmName
+		objMemParam = "memory-backend-file,id=" + id + ",size=" + size + ",mem-path=" + config.Memory.Path
 	} else {
-		objMemParam = "memory-backend-ram,id=" + dimmName + ",size=" + config.Memory.Size
-		numaMemParam = "node,memdev=" + dimmName
+		objMemParam = "memory-backend-ram,id=" + id + ",size=" + size
 	}
 
 	if config.Knobs.MemShared {
@@ -2887,6 +3161,56 @@ func (config *Config) appendMemoryKnobs() {
 	if config.Knobs.MemPrealloc {
 		objMemParam += ",prealloc=on"
 	}
+
+	return objMemParam
+}
+
+func (config *Config) appendNUMANodes() {
+	for i, node := range config.Memory.NUMANodes {
+		dimmName := fmt.Sprintf("dimm%d", i+1)
+
+		objMemParam := config.memoryBackendParam(dimmName, node.Size)
+		if node.HostNodes != "" {
+			objMemParam += ",host-nodes=" + node.HostNodes + ",policy=bind"
+		}
+
+		numaMemParams := []string{"node", fmt.Sprintf("nodeid=%d", i)}
+		for _, cpus := range node.CPUs {
+			numaMemParams = append(numaMemParams, "cpus="+cpus)
+		}
+		numaMemParams = append(numaMemParams, "memdev="+dimmName)
+
+		config.qemuParams = append(config.qemuParams, "-object")
+		config.qemuParams = append(config.qemuParams, objMemParam)
+		config.qemuParams = append(config.qemuParams, "-numa")
+		config.qemuParams = append(config.qemuParams, strings.Join(numaMemParams, ","))
+	}
+}
+
+// checkNUMANodes checks that the guest NUMA nodes, if any, can be created:
+// they are not silently replaced by a flat topology.
+func (config *Config) checkNUMANodes() error {
+	if len(config.Memory.NUMANodes) > 0 && !isDimmSupported(config) {
+		return fmt.Errorf("guest NUMA nodes are not supported on %s with the %s machine", runtime.GOARCH, config.Machine.Type)
+	}
+
+	return nil
+}
+
+func (config *Config) appendMemoryKnobs() {
+	if config.Memory.Size == "" {
+		return
+	}
+
+	if len(config.Memory.NUMANodes) > 0 && isDimmSupported(config) {
+		config.appendNUMANodes()
+		return
+	}
+
+	dimmName := "dimm1"
+	objMemParam := config.memoryBackendParam(dimmName, config.Memory.Size)
+	numaMemParam := "node,memdev=" + dimmName
+
 	config.qemuParams = append(config.qemuParams, "-object")
 	config.qemuParams = append(config.qemuParams, objMemParam)
 
@@ -2945,8 +3269,17 @@ func (config *Config) appendBios() {
 
 func (config *Config) appendIOThreads() {
 	for _, t := range config.IOThreads {
-		if t.ID != "" {
-			config.qemuParams = append(config.qemuParams, "-object")
+		if t.ID == "" {
+			continue
+		}
+
+		config.qemuParams = append(config.qemuParams, "-object")
+		if config.JSONSyntax {
+			config.qemuParams = append(config.qemuParams, jsonParam(map[string]interface{}{
+				"qom-type": "iothread",
+				"id":       t.ID,
+			}))
+		} else {
 			config.qemuParams = append(config.qemuParams, fmt.Sprintf("iothread,id=%s", t.ID))
 		}
 	}
@@ -3007,6 +3340,10 @@ func (config *Config) appendFwCfg(logger QMPLog) {
 // will be returned if the launch succeeds.  Otherwise a string containing
 // the contents of stderr + a Go error object will be returned.
 func LaunchQemu(config Config, logger QMPLog) (string, error) {
+	if err := config.checkNUMANodes(); err != nil {
+		return "", err
+	}
+
 	config.appendName()
 	config.appendUUID()
 	config.appendMachine()
@@ -3037,15 +3374,24 @@ func LaunchQemu(config Config, logger QMPLog) (string, error) {
 		ctx = context.Background()
 	}
 
-	attr := syscall.SysProcAttr{}
-	attr.Credential = &syscall.Credential{
-		Uid:    config.Uid,
-		Gid:    config.Gid,
-		Groups: config.Groups,
+	return LaunchCustomQemu(ctx, config.Path, config.qemuParams,
+		config.fds, config.sysProcAttr(), logger)
+}
+
+// sysProcAttr returns the attributes of the qemu process, the IDs it runs
+// as.
+func (config *Config) sysProcAttr() *syscall.SysProcAttr {
+	if config.SysProcAttr != nil {
+		return config.SysProcAttr
 	}
 
-	return LaunchCustomQemu(ctx, config.Path, config.qemuParams,
-		config.fds, &attr, logger)
+	return &syscall.SysProcAttr{
+		Credential: &syscall.Credential{
+			Uid:    config.Uid,
+			Gid:    config.Gid,
+			Groups: config.Groups,
+		},
+	}
 }
 
 // LaunchCustomQemu can be used to launch a new qemu instance.
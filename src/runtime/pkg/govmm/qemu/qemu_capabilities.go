@@ -0,0 +1,354 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Capabilities are the features supported by a QEMU binary, as described by
+// its QMP schema and its QOM types. They are probed from a running instance
+// of the binary by ProbeCapabilities and can be saved, for the next
+// instances of the binary to be configured before they are launched.
+//
+// Checking the capabilities, rather than the QEMU version, keeps working
+// with the QEMU builds backporting or disabling features.
+// nolint: govet
+type Capabilities struct {
+	// Binary, Size and ModTime identify the QEMU binary the capabilities
+	// were probed from. They are set by Save.
+	Binary  string    `json:"binary"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod-time"`
+
+	// Schema is the QMP schema of the binary.
+	Schema []SchemaInfo `json:"schema"`
+
+	// Devices are the device drivers of the binary, with the properties
+	// of the ones probed.
+	Devices map[string][]string `json:"devices"`
+
+	once     sync.Once
+	commands map[string]*SchemaInfo
+	events   map[string]*SchemaInfo
+	types    map[string]*SchemaInfo
+}
+
+// ProbeCapabilities probes the capabilities of the QEMU instance q. The
+// properties of the device drivers devices are probed, when the instance
+// supports them.
+func ProbeCapabilities(ctx context.Context, q *QMP, devices []string) (*Capabilities, error) {
+	schema, err := q.ExecQueryQmpSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := q.ExecuteQOMListTypes(ctx, "device", false)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Capabilities{
+		Schema:  schema,
+		Devices: make(map[string][]string),
+	}
+
+	for _, t := range types {
+		c.Devices[t.Name] = nil
+	}
+
+	for _, d := range devices {
+		if _, ok := c.Devices[d]; !ok {
+			continue
+		}
+
+		props, err := q.ExecuteDeviceListProperties(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+
+		names := []string{}
+		for _, p := range props {
+			names = append(names, p.Name)
+		}
+		c.Devices[d] = names
+	}
+
+	return c, nil
+}
+
+// LoadCapabilities returns the capabilities of the QEMU binary saved to
+// path. nil is returned when there are none, or when the binary changed
+// since they were saved.
+func LoadCapabilities(path, binary string) (*Capabilities, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c Capabilities
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	st, err := os.Stat(binary)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Binary != binary || c.Size != st.Size() || !c.ModTime.Equal(st.ModTime()) {
+		return nil, nil
+	}
+
+	return &c, nil
+}
+
+// Save saves the capabilities of the QEMU binary to path, for
+// LoadCapabilities to return them until the binary changes.
+func (c *Capabilities) Save(path, binary string) error {
+	st, err := os.Stat(binary)
+	if err != nil {
+		return err
+	}
+
+	c.Binary = binary
+	c.Size = st.Size()
+	c.ModTime = st.ModTime()
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	// Written then renamed, for a concurrent LoadCapabilities not to
+	// read a partial file. Each Save writes its own temporary file, as
+	// several shims may probe the same binary at once.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (c *Capabilities) index() {
+	c.once.Do(func() {
+		c.commands = make(map[string]*SchemaInfo)
+		c.events = make(map[string]*SchemaInfo)
+		c.types = make(map[string]*SchemaInfo)
+
+		for i := range c.Schema {
+			info := &c.Schema[i]
+			switch info.MetaType {
+			case "command":
+				c.commands[info.Name] = info
+			case "event":
+				c.events[info.Name] = info
+			default:
+				c.types[info.Name] = info
+			}
+		}
+	})
+}
+
+// HasCommand returns true if the QMP command name is supported.
+func (c *Capabilities) HasCommand(name string) bool {
+	c.index()
+	_, ok := c.commands[name]
+	return ok
+}
+
+// HasEvent returns true if the QMP event name is supported.
+func (c *Capabilities) HasEvent(name string) bool {
+	c.index()
+	_, ok := c.events[name]
+	return ok
+}
+
+// hasCommandFeature returns true if the QMP command name has the special
+// feature feature.
+func (c *Capabilities) hasCommandFeature(name, feature string) bool {
+	c.index()
+	cmd, ok := c.commands[name]
+	if !ok {
+		return false
+	}
+
+	return hasString(cmd.Features, feature)
+}
+
+// variantMembers returns the members of the arguments of the QMP command
+// name, a union of variants selected by their tag member, when the tag is
+// value.
+func (c *Capabilities) variantMembers(name, tag, value string) ([]SchemaMember, bool) {
+	c.index()
+	cmd, ok := c.commands[name]
+	if !ok {
+		return nil, false
+	}
+
+	args, ok := c.types[cmd.ArgType]
+	if !ok || args.Tag != tag {
+		return nil, false
+	}
+
+	for _, v := range args.Variants {
+		if v.Case != value {
+			continue
+		}
+
+		members := append([]SchemaMember{}, args.Members...)
+		if variant, ok := c.types[v.Type]; ok {
+			members = append(members, variant.Members...)
+		}
+		return members, true
+	}
+
+	return nil, false
+}
+
+// hasMemberValue returns true if the member member of members is an enum
+// with the value value.
+func (c *Capabilities) hasMemberValue(members []SchemaMember, member, value string) bool {
+	for _, m := range members {
+		if m.Name != member {
+			continue
+		}
+
+		enum, ok := c.types[m.Type]
+		if !ok || enum.MetaType != "enum" {
+			return false
+		}
+
+		if hasString(enum.Values, value) {
+			return true
+		}
+		for _, v := range enum.Members {
+			if v.Name == value {
+				return true
+			}
+		}
+		return false
+	}
+
+	return false
+}
+
+// HasObject returns true if the QOM objects of type qomType can be created.
+func (c *Capabilities) HasObject(qomType string) bool {
+	_, ok := c.variantMembers("object-add", "qom-type", qomType)
+	return ok
+}
+
+// HasObjectProperty returns true if the QOM objects of type qomType have the
+// property prop.
+func (c *Capabilities) HasObjectProperty(qomType, prop string) bool {
+	members, _ := c.variantMembers("object-add", "qom-type", qomType)
+	return hasMember(members, prop)
+}
+
+// HasBlockdevDriver returns true if the block nodes of driver driver, e.g.,
+// qcow2 or throttle, can be added.
+func (c *Capabilities) HasBlockdevDriver(driver string) bool {
+	_, ok := c.variantMembers("blockdev-add", "driver", driver)
+	return ok
+}
+
+// HasBlockdevOption returns true if the block nodes of driver driver have
+// the option option.
+func (c *Capabilities) HasBlockdevOption(driver, option string) bool {
+	members, _ := c.variantMembers("blockdev-add", "driver", driver)
+	return hasMember(members, option)
+}
+
+// HasBlockdevOptionValue returns true if the option option of the block
+// nodes of driver driver can be value, e.g., if the aio option of the file
+// driver can be io_uring.
+func (c *Capabilities) HasBlockdevOptionValue(driver, option, value string) bool {
+	members, _ := c.variantMembers("blockdev-add", "driver", driver)
+	return c.hasMemberValue(members, option, value)
+}
+
+// HasNetdev returns true if the network backends of type netdevType, e.g.,
+// stream, can be added.
+func (c *Capabilities) HasNetdev(netdevType string) bool {
+	_, ok := c.variantMembers("netdev_add", "type", netdevType)
+	return ok
+}
+
+// HasDevice returns true if the devices of driver driver can be added.
+func (c *Capabilities) HasDevice(driver string) bool {
+	_, ok := c.Devices[driver]
+	return ok
+}
+
+// HasDeviceProperty returns true if the devices of driver driver have the
+// property prop. The properties of the devices which were not probed are
+// unknown.
+func (c *Capabilities) HasDeviceProperty(driver, prop string) bool {
+	return hasString(c.Devices[driver], prop)
+}
+
+// JSONSyntax returns true if the -device, -object and -blockdev parameters
+// can be written in JSON, see JSONDevice.
+func (c *Capabilities) JSONSyntax() bool {
+	// The JSON -device parameter is advertised by the json-cli feature
+	// of device_add, the JSON -object parameter requires the object-add
+	// arguments to be described by the schema.
+	if !c.hasCommandFeature("device_add", "json-cli") {
+		return false
+	}
+
+	c.index()
+	cmd, ok := c.commands["object-add"]
+	if !ok {
+		return false
+	}
+	if args, ok := c.types[cmd.ArgType]; !ok || args.Tag != "qom-type" {
+		return false
+	}
+
+	return c.HasCommand("blockdev-add")
+}
+
+func hasString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMember(members []SchemaMember, name string) bool {
+	for _, m := range members {
+		if m.Name == name {
+			return true
+		}
+	}
+	return false
+}
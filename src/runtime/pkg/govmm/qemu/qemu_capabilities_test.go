@@ -0,0 +1,221 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCapabilitiesSchema is an excerpt of the QMP schema of QEMU 7.2, where
+// the names of the types are masked.
+func testCapabilitiesSchema() []SchemaInfo {
+	return []SchemaInfo{
+		{MetaType: "command", Name: "device_add", ArgType: "0", RetType: "1", Features: []string{"json-cli", "json-cli-hotplug"}},
+		{MetaType: "command", Name: "object-add", ArgType: "2", RetType: "1"},
+		{MetaType: "command", Name: "blockdev-add", ArgType: "3", RetType: "1"},
+		{MetaType: "command", Name: "netdev_add", ArgType: "12", RetType: "1"},
+		{MetaType: "event", Name: "DEVICE_DELETED", ArgType: "4"},
+		{MetaType: "object", Name: "2", Tag: "qom-type",
+			Members:  []SchemaMember{{Name: "qom-type", Type: "5"}, {Name: "id", Type: "str"}},
+			Variants: []SchemaVariant{{Case: "iothread", Type: "6"}, {Case: "memory-backend-file", Type: "7"}}},
+		{MetaType: "object", Name: "3", Tag: "driver",
+			Members:  []SchemaMember{{Name: "driver", Type: "8"}, {Name: "node-name", Type: "str"}},
+			Variants: []SchemaVariant{{Case: "file", Type: "9"}, {Case: "qcow2", Type: "10"}}},
+		{MetaType: "object", Name: "6", Members: []SchemaMember{{Name: "poll-max-ns", Type: "int"}}},
+		{MetaType: "object", Name: "7", Members: []SchemaMember{{Name: "mem-path", Type: "str"}, {Name: "size", Type: "int"}}},
+		{MetaType: "object", Name: "9", Members: []SchemaMember{{Name: "filename", Type: "str"}, {Name: "aio", Type: "11"}}},
+		{MetaType: "object", Name: "10", Members: []SchemaMember{{Name: "file", Type: "str"}}},
+		{MetaType: "enum", Name: "11", Values: []string{"threads", "native", "io_uring"},
+			Members: []SchemaMember{{Name: "threads"}, {Name: "native"}, {Name: "io_uring"}}},
+		{MetaType: "object", Name: "12", Tag: "type",
+			Members:  []SchemaMember{{Name: "id", Type: "str"}, {Name: "type", Type: "13"}},
+			Variants: []SchemaVariant{{Case: "tap", Type: "14"}, {Case: "stream", Type: "15"}}},
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	c := &Capabilities{
+		Schema: testCapabilitiesSchema(),
+		Devices: map[string][]string{
+			"virtio-blk-pci": {"drive", "serial", "num-queues"},
+			"nvdimm":         nil,
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		check    bool
+		expected bool
+	}{
+		{"command", c.HasCommand("blockdev-add"), true},
+		{"unknown command", c.HasCommand("blockdev-reopen"), false},
+		{"event", c.HasEvent("DEVICE_DELETED"), true},
+		{"object", c.HasObject("iothread"), true},
+		{"unknown object", c.HasObject("memory-backend-memfd"), false},
+		{"object property", c.HasObjectProperty("memory-backend-file", "mem-path"), true},
+		{"object base property", c.HasObjectProperty("iothread", "id"), true},
+		{"unknown object property", c.HasObjectProperty("iothread", "mem-path"), false},
+		{"blockdev driver", c.HasBlockdevDriver("qcow2"), true},
+		{"unknown blockdev driver", c.HasBlockdevDriver("nbd"), false},
+		{"blockdev option", c.HasBlockdevOption("file", "aio"), true},
+		{"blockdev option value", c.HasBlockdevOptionValue("file", "aio", "io_uring"), true},
+		{"unknown blockdev option value", c.HasBlockdevOptionValue("file", "aio", "posix"), false},
+		{"not enum blockdev option", c.HasBlockdevOptionValue("file", "filename", "io_uring"), false},
+		{"netdev", c.HasNetdev("stream"), true},
+		{"unknown netdev", c.HasNetdev("vhost-vdpa"), false},
+		{"device", c.HasDevice("nvdimm"), true},
+		{"unknown device", c.HasDevice("virtio-mem-pci"), false},
+		{"device property", c.HasDeviceProperty("virtio-blk-pci", "num-queues"), true},
+		{"unprobed device property", c.HasDeviceProperty("nvdimm", "memdev"), false},
+		{"JSON syntax", c.JSONSyntax(), true},
+	} {
+		if tc.check != tc.expected {
+			t.Errorf("Unexpected %s capability. Expected %v found %v", tc.name, tc.expected, tc.check)
+		}
+	}
+}
+
+// Checks that the JSON syntax is not used with a QEMU older than 6.2, where
+// device_add has no json-cli feature.
+func TestCapabilitiesNoJSONSyntax(t *testing.T) {
+	schema := testCapabilitiesSchema()
+	schema[0].Features = nil
+
+	c := &Capabilities{Schema: schema}
+	if c.JSONSyntax() {
+		t.Fatal("Unexpected JSON syntax support")
+	}
+}
+
+// Checks that the capabilities are probed from a running QEMU.
+//
+// We start a QMPLoop answering the schema, the device types and the
+// properties of virtio-blk-pci.
+//
+// The properties of the probed devices supported should be returned, the
+// ones not supported should not be queried.
+func TestProbeCapabilities(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("query-qmp-schema", nil, "return", testCapabilitiesSchema())
+	buf.AddCommand("qom-list-types", nil, "return", []interface{}{
+		map[string]interface{}{"name": "virtio-blk-pci", "parent": "virtio-blk-pci-base"},
+		map[string]interface{}{"name": "nvdimm", "parent": "pc-dimm"},
+	})
+	buf.AddCommand("device-list-properties", nil, "return", []interface{}{
+		map[string]interface{}{"name": "drive", "type": "str"},
+		map[string]interface{}{"name": "num-queues", "type": "uint16"},
+	})
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	c, err := ProbeCapabilities(context.Background(), q, []string{"virtio-blk-pci", "virtio-mem-pci"})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+
+	if !c.HasDevice("nvdimm") || !c.HasDeviceProperty("virtio-blk-pci", "num-queues") || c.HasDevice("virtio-mem-pci") {
+		t.Fatalf("Unexpected devices %v", c.Devices)
+	}
+	if !c.JSONSyntax() {
+		t.Fatal("Expected JSON syntax support")
+	}
+}
+
+// Checks that saved capabilities are loaded until the binary changes.
+func TestCapabilitiesSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "qemu-system-x86_64")
+	path := filepath.Join(dir, "caps", "qemu.json")
+
+	if err := os.WriteFile(binary, []byte("qemu"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadCapabilities(path, binary)
+	if err != nil || c != nil {
+		t.Fatalf("Unexpected capabilities %v before saving them: %v", c, err)
+	}
+
+	saved := &Capabilities{
+		Schema:  testCapabilitiesSchema(),
+		Devices: map[string][]string{"nvdimm": nil},
+	}
+	if err := saved.Save(path, binary); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	c, err = LoadCapabilities(path, binary)
+	if err != nil || c == nil {
+		t.Fatalf("Unexpected error %v loading the capabilities", err)
+	}
+	if !c.JSONSyntax() || !c.HasDevice("nvdimm") {
+		t.Fatalf("Unexpected capabilities %v", c)
+	}
+
+	// An updated binary is probed again.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(binary, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = LoadCapabilities(path, binary)
+	if err != nil || c != nil {
+		t.Fatalf("Unexpected capabilities %v of an updated binary: %v", c, err)
+	}
+}
+
+// Checks that concurrent saves of the capabilities do not clash.
+func TestCapabilitiesSaveConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "qemu-system-x86_64")
+	path := filepath.Join(dir, "caps", "qemu.json")
+
+	if err := os.WriteFile(binary, []byte("qemu"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := &Capabilities{Schema: testCapabilitiesSchema()}
+			errs <- c.Save(path, binary)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+	}
+
+	c, err := LoadCapabilities(path, binary)
+	if err != nil || c == nil {
+		t.Fatalf("Unexpected error %v loading the capabilities", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Unexpected temporary files left %v", entries)
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONDevice is a Device able to describe itself with the JSON syntax of the
+// -device, -object and -blockdev parameters. Unlike the legacy syntax, the
+// JSON syntax is typed and parsed by QEMU the same way as the QMP commands.
+// Capabilities.JSONSyntax tells whether a QEMU binary supports it.
+type JSONDevice interface {
+	Device
+
+	// QemuJSONParams returns the qemu parameters built out of the device,
+	// the -device, -object and -blockdev ones in JSON. The other ones,
+	// e.g., -netdev or -chardev, keep the legacy syntax.
+	QemuJSONParams(config *Config) []string
+}
+
+// jsonParam returns the JSON form of the properties of a -device, -object or
+// -blockdev parameter.
+func jsonParam(props map[string]interface{}) string {
+	// Maps of basic types are always marshalled.
+	data, _ := json.Marshal(props)
+	return string(data)
+}
+
+// QemuJSONParams returns the qemu parameters built out of this Object device,
+// the -device and -object ones in JSON.
+func (object Object) QemuJSONParams(config *Config) []string {
+	var qemuParams []string
+
+	objectProps := map[string]interface{}{
+		"qom-type": string(object.Type),
+		"id":       object.ID,
+	}
+	var deviceProps map[string]interface{}
+
+	switch object.Type {
+	case MemoryBackendFile:
+		objectProps["mem-path"] = object.MemPath
+		objectProps["size"] = object.Size
+
+		deviceProps = map[string]interface{}{
+			"driver": string(object.Driver),
+			"id":     object.DeviceID,
+			"memdev": object.ID,
+		}
+
+		if object.ReadOnly {
+			objectProps["readonly"] = true
+			deviceProps["unarmed"] = true
+		}
+	case MemoryBackendEPC:
+		objectProps["size"] = object.Size
+		if object.Prealloc {
+			objectProps["prealloc"] = true
+		}
+	case TDXGuest:
+		if object.Debug {
+			objectProps["debug"] = true
+		}
+		deviceProps = map[string]interface{}{
+			"driver": string(object.Driver),
+			"id":     object.DeviceID,
+			"file":   object.File,
+		}
+		if object.FirmwareVolume != "" {
+			deviceProps["config-firmware-volume"] = object.FirmwareVolume
+		}
+	case SEVGuest, SNPGuest:
+		objectProps["cbitpos"] = object.CBitPos
+		objectProps["reduced-phys-bits"] = object.ReducedPhysBits
+		objectProps["policy"] = object.SevPolicy
+		if object.SevCertFilePath != "" {
+			objectProps["dh-cert-file"] = object.SevCertFilePath
+		}
+		if object.SevSessionFilePath != "" {
+			objectProps["session-file"] = object.SevSessionFilePath
+		}
+		if object.SevKernelHashes {
+			objectProps["kernel-hashes"] = true
+		}
+	case PEFGuest:
+		deviceProps = map[string]interface{}{
+			"driver":    string(object.Driver),
+			"id":        object.DeviceID,
+			"host-path": object.File,
+		}
+	}
+
+	if deviceProps != nil {
+		qemuParams = append(qemuParams, "-device", jsonParam(deviceProps))
+	}
+
+	qemuParams = append(qemuParams, "-object", jsonParam(objectProps))
+
+	if object.Type == SEVGuest || object.Type == SNPGuest {
+		// The OVMF firmware is a pflash drive, which has no -blockdev
+		// equivalent.
+		qemuParams = append(qemuParams, "-drive", "if=pflash,format=raw,readonly=on,file="+object.File)
+	}
+
+	return qemuParams
+}
+
+// QemuJSONParams returns the qemu parameters built out of this network
+// device, the -device one in JSON.
+func (netdev NetDevice) QemuJSONParams(config *Config) []string {
+	var qemuParams []string
+
+	// Macvtap can only be connected via fds
+	if (netdev.Type == MACVTAP) && (len(netdev.FDs) == 0) {
+		return nil // implicit error
+	}
+
+	if netdev.Type.QemuNetdevParam(&netdev, config) != "" {
+		if netdevParams := netdev.QemuNetdevParams(config); netdevParams != nil {
+			qemuParams = append(qemuParams, "-netdev", strings.Join(netdevParams, ","))
+		}
+	}
+
+	driver := netdev.Type.QemuDeviceParam(&netdev, config)
+	if driver == "" {
+		return qemuParams
+	}
+
+	props := map[string]interface{}{
+		"driver": string(driver),
+		"netdev": netdev.ID,
+		"mac":    netdev.MACAddress,
+	}
+
+	if netdev.Bus != "" {
+		props["bus"] = netdev.Bus
+	}
+
+	if netdev.Addr != "" {
+		addr, err := strconv.Atoi(netdev.Addr)
+		if err == nil && addr >= 0 {
+			props["addr"] = fmt.Sprintf("%x", addr)
+		}
+	}
+
+	if netdev.Transport.isVirtioPCI(config) {
+		props["disable-modern"] = netdev.DisableModern
+	}
+
+	if len(netdev.FDs) > 0 {
+		props["mq"] = true
+		if netdev.Transport.isVirtioPCI(config) {
+			// See mqParameter for the number of vectors.
+			props["vectors"] = len(netdev.FDs)*2 + 2
+		}
+	}
+
+	if netdev.Transport.isVirtioPCI(config) && netdev.ROMFile != "" {
+		props["romfile"] = netdev.ROMFile
+	}
+
+	if netdev.Transport.isVirtioCCW(config) {
+		if config.Knobs.IOMMUPlatform {
+			props["iommu_platform"] = true
+		}
+		props["devno"] = netdev.DevNo
+	}
+
+	return append(qemuParams, "-device", jsonParam(props))
+}
+
+// QemuJSONParams returns the qemu parameters built out of this block device,
+// a -device and a -blockdev in JSON. The -blockdev is the equivalent of a
+// -drive with no interface, block devices with another interface keep the
+// legacy syntax.
+func (blkdev BlockDevice) QemuJSONParams(config *Config) []string {
+	if blkdev.Interface != "" && blkdev.Interface != NoInterface {
+		return blkdev.QemuParams(config)
+	}
+
+	deviceProps := map[string]interface{}{
+		"driver": blkdev.deviceName(config),
+		"drive":  blkdev.ID,
+		"serial": blkdev.ID,
+	}
+
+	if blkdev.DevID != "" {
+		deviceProps["id"] = blkdev.DevID
+	}
+
+	if blkdev.Transport.isVirtioPCI(config) {
+		deviceProps["disable-modern"] = blkdev.DisableModern
+	}
+
+	if !blkdev.SCSI {
+		deviceProps["scsi"] = false
+	}
+
+	if !blkdev.WCE {
+		deviceProps["config-wce"] = false
+	}
+
+	if blkdev.Transport.isVirtioPCI(config) && blkdev.ROMFile != "" {
+		deviceProps["romfile"] = blkdev.ROMFile
+	}
+
+	if blkdev.Transport.isVirtioCCW(config) {
+		deviceProps["devno"] = blkdev.DevNo
+	}
+
+	if blkdev.ShareRW {
+		deviceProps["share-rw"] = true
+	}
+
+	fileProps := map[string]interface{}{
+		"driver":   "file",
+		"filename": blkdev.File,
+	}
+	if blkdev.AIO != "" {
+		fileProps["aio"] = string(blkdev.AIO)
+	}
+
+	format := string(blkdev.Format)
+	if format == "" {
+		format = "raw"
+	}
+
+	blockdevProps := map[string]interface{}{
+		"driver":    format,
+		"node-name": blkdev.ID,
+		"file":      fileProps,
+	}
+	if blkdev.ReadOnly {
+		blockdevProps["read-only"] = true
+		fileProps["read-only"] = true
+	}
+
+	var qemuParams []string
+
+	if blkdev.ThrottleGroup != "" {
+		qemuParams = append(qemuParams, "-object", jsonParam(map[string]interface{}{
+			"qom-type": "throttle-group",
+			"id":       blkdev.ThrottleGroup,
+			"limits":   blkdev.ThrottleLimits.qmpArgs(),
+		}))
+
+		delete(blockdevProps, "node-name")
+		if blkdev.ThrottledNodeName != "" {
+			blockdevProps["node-name"] = blkdev.ThrottledNodeName
+		}
+
+		// The guest device is attached to a throttle filter node, the
+		// same way as the devices added through QMP.
+		blockdevProps = map[string]interface{}{
+			"driver":         "throttle",
+			"node-name":      blkdev.ID,
+			"throttle-group": blkdev.ThrottleGroup,
+			"file":           blockdevProps,
+		}
+		if blkdev.ReadOnly {
+			blockdevProps["read-only"] = true
+		}
+	}
+
+	return append(qemuParams,
+		"-device", jsonParam(deviceProps),
+		"-blockdev", jsonParam(blockdevProps),
+	)
+}
+
+// QemuJSONParams returns the qemu parameters built out of this vhostuser
+// device, the -device one in JSON.
+func (vhostuserDev VhostUserDevice) QemuJSONParams(config *Config) []string {
+	driver := vhostuserDev.deviceName(config)
+	if driver == "" {
+		return nil
+	}
+
+	qemuParams := []string{
+		"-chardev",
+		fmt.Sprintf("socket,id=%s,path=%s", vhostuserDev.CharDevID, vhostuserDev.SocketPath),
+	}
+
+	props := map[string]interface{}{
+		"driver":  driver,
+		"chardev": vhostuserDev.CharDevID,
+	}
+
+	switch vhostuserDev.VhostUserType {
+	case VhostUserNet:
+		qemuParams = append(qemuParams, "-netdev",
+			fmt.Sprintf("type=vhost-user,id=%s,chardev=%s,vhostforce", vhostuserDev.TypeDevID, vhostuserDev.CharDevID))
+
+		// The chardev is the one of the netdev.
+		delete(props, "chardev")
+		props["netdev"] = vhostuserDev.TypeDevID
+		props["mac"] = vhostuserDev.Address
+	case VhostUserSCSI:
+		props["id"] = vhostuserDev.TypeDevID
+	case VhostUserBlk:
+		props["logical_block_size"] = 4096
+		props["size"] = 512 << 20
+	case VhostUserFS:
+		props["tag"] = vhostuserDev.Tag
+		queueSize := uint32(1024)
+		if vhostuserDev.QueueSize != 0 {
+			queueSize = vhostuserDev.QueueSize
+		}
+		props["queue-size"] = queueSize
+		if vhostuserDev.CacheSize != 0 {
+			props["cache-size"] = uint64(vhostuserDev.CacheSize) << 20
+		}
+		if vhostuserDev.SharedVersions {
+			props["versiontable"] = "/dev/shm/fuse_shared_versions"
+		}
+		if vhostuserDev.Transport.isVirtioCCW(config) {
+			if config.Knobs.IOMMUPlatform {
+				props["iommu_platform"] = true
+			}
+			props["devno"] = vhostuserDev.DevNo
+		}
+	default:
+		return nil
+	}
+
+	if vhostuserDev.Transport.isVirtioPCI(config) && vhostuserDev.ROMFile != "" {
+		props["romfile"] = vhostuserDev.ROMFile
+	}
+
+	return append(qemuParams, "-device", jsonParam(props))
+}
+
+// QemuJSONParams returns the qemu parameters built out of this virtio-mem
+// device, the -object and -device ones in JSON.
+func (dev VirtioMemDevice) QemuJSONParams(config *Config) []string {
+	objectProps := map[string]interface{}{
+		"qom-type": string(dev.memoryBackend()),
+		"id":       dev.MemDev,
+		"size":     dev.Size,
+	}
+	if dev.MemPath != "" {
+		objectProps["mem-path"] = dev.MemPath
+	}
+	if dev.Share {
+		objectProps["share"] = true
+	}
+
+	deviceProps := map[string]interface{}{
+		"driver":         dev.deviceName(config),
+		"id":             dev.ID,
+		"memdev":         dev.MemDev,
+		"requested-size": dev.RequestedSize,
+	}
+	if dev.BlockSize != 0 {
+		deviceProps["block-size"] = dev.BlockSize
+	}
+	if dev.Bus != "" {
+		deviceProps["bus"] = dev.Bus
+	}
+	if dev.Addr != "" {
+		deviceProps["addr"] = dev.Addr
+	}
+	if dev.ROMFile != "" {
+		deviceProps["romfile"] = dev.ROMFile
+	}
+
+	// The backend must be created before the device using it.
+	return []string{
+		"-object", jsonParam(objectProps),
+		"-device", jsonParam(deviceProps),
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"os"
+	"testing"
+)
+
+func testAppendJSON(structure interface{}, expected string, t *testing.T) {
+	config := Config{JSONSyntax: true}
+	testConfigAppend(&config, structure, expected, t)
+}
+
+var deviceBlockJSONString = `-device {"config-wce":false,"disable-modern":true,"drive":"hd0","driver":"virtio-blk-pci","romfile":"efi-virtio.rom","scsi":false,"serial":"hd0","share-rw":true} ` +
+	`-blockdev {"driver":"qcow2","file":{"aio":"threads","driver":"file","filename":"/var/lib/vm.img","read-only":true},"node-name":"hd0","read-only":true}`
+
+func TestAppendJSONDeviceBlock(t *testing.T) {
+	blkdev := BlockDevice{
+		Driver:        VirtioBlock,
+		ID:            "hd0",
+		File:          "/var/lib/vm.img",
+		AIO:           Threads,
+		Format:        QCOW2,
+		Interface:     NoInterface,
+		DisableModern: true,
+		ROMFile:       "efi-virtio.rom",
+		ShareRW:       true,
+		ReadOnly:      true,
+		Transport:     TransportPCI,
+	}
+	testAppendJSON(blkdev, deviceBlockJSONString, t)
+
+	// Only the drives with no interface have a -blockdev equivalent.
+	blkdev.Interface = SCSI
+	testAppendJSON(blkdev, "-device virtio-blk-pci,disable-modern=true,drive=hd0,scsi=off,config-wce=off,romfile=efi-virtio.rom,share-rw=on,serial=hd0 "+
+		"-drive id=hd0,file=/var/lib/vm.img,aio=threads,format=qcow2,if=scsi,readonly=on", t)
+}
+
+func TestAppendJSONDeviceBlockThrottled(t *testing.T) {
+	blkdev := BlockDevice{
+		Driver:            VirtioBlock,
+		ID:                "hd0",
+		DevID:             "virtio-hd0",
+		File:              "/var/lib/vm.img",
+		AIO:               Threads,
+		Format:            "raw",
+		Interface:         NoInterface,
+		Transport:         TransportPCI,
+		ThrottleGroup:     "throttle-hd0",
+		ThrottledNodeName: "fmt-hd0",
+		ThrottleLimits:    ThrottleLimits{BpsTotal: 1 << 20, IopsTotal: 100},
+	}
+	testAppendJSON(blkdev, `-object {"id":"throttle-hd0","limits":{"bps-total":1048576,"bps-total-max":0,"iops-total":100,"iops-total-max":0},"qom-type":"throttle-group"} `+
+		`-device {"config-wce":false,"disable-modern":false,"drive":"hd0","driver":"virtio-blk-pci","id":"virtio-hd0","scsi":false,"serial":"hd0"} `+
+		`-blockdev {"driver":"throttle","file":{"driver":"raw","file":{"aio":"threads","driver":"file","filename":"/var/lib/vm.img"},"node-name":"fmt-hd0"},"node-name":"hd0","throttle-group":"throttle-hd0"}`, t)
+}
+
+var deviceNetworkJSONString = `-netdev tap,id=tap0,vhost=on,fds=3:4 ` +
+	`-device {"addr":"ff","bus":"/pci-bus/pcie.0","disable-modern":false,"driver":"virtio-net-pci","mac":"01:02:de:ad:be:ef","mq":true,"netdev":"tap0","vectors":6}`
+
+func TestAppendJSONDeviceNetwork(t *testing.T) {
+	foo, err := os.CreateTemp(t.TempDir(), "govmm-qemu-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer foo.Close()
+	bar, err := os.CreateTemp(t.TempDir(), "govmm-qemu-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bar.Close()
+
+	netdev := NetDevice{
+		Driver:     VirtioNet,
+		Type:       TAP,
+		ID:         "tap0",
+		IFName:     "ceth0",
+		FDs:        []*os.File{foo, bar},
+		VHost:      true,
+		MACAddress: "01:02:de:ad:be:ef",
+		Bus:        "/pci-bus/pcie.0",
+		Addr:       "255",
+		Transport:  TransportPCI,
+	}
+	testAppendJSON(netdev, deviceNetworkJSONString, t)
+}
+
+var deviceVhostUserFSJSONString = `-chardev socket,id=char0,path=/tmp/vhost-fs.sock ` +
+	`-device {"cache-size":1073741824,"chardev":"char0","driver":"vhost-user-fs-pci","queue-size":1024,"tag":"kataShared"}`
+
+var deviceVhostUserNetJSONString = `-chardev socket,id=char1,path=/tmp/vhost-net.sock ` +
+	`-netdev type=vhost-user,id=net1,chardev=char1,vhostforce ` +
+	`-device {"driver":"virtio-net-pci","mac":"00:11:22:33:44:55","netdev":"net1"}`
+
+func TestAppendJSONDeviceVhostUser(t *testing.T) {
+	fsDevice := VhostUserDevice{
+		SocketPath:    "/tmp/vhost-fs.sock",
+		CharDevID:     "char0",
+		Tag:           "kataShared",
+		CacheSize:     1024,
+		VhostUserType: VhostUserFS,
+		Transport:     TransportPCI,
+	}
+	testAppendJSON(fsDevice, deviceVhostUserFSJSONString, t)
+
+	netDevice := VhostUserDevice{
+		SocketPath:    "/tmp/vhost-net.sock",
+		CharDevID:     "char1",
+		TypeDevID:     "net1",
+		Address:       "00:11:22:33:44:55",
+		VhostUserType: VhostUserNet,
+		Transport:     TransportPCI,
+	}
+	testAppendJSON(netDevice, deviceVhostUserNetJSONString, t)
+}
+
+var deviceNVDIMMJSONString = `-device {"driver":"nvdimm","id":"nv0","memdev":"mem0","unarmed":true} ` +
+	`-object {"id":"mem0","mem-path":"/root","qom-type":"memory-backend-file","readonly":true,"size":65536}`
+
+func TestAppendJSONDeviceNVDIMM(t *testing.T) {
+	object := Object{
+		Driver:   NVDIMM,
+		Type:     MemoryBackendFile,
+		DeviceID: "nv0",
+		ID:       "mem0",
+		MemPath:  "/root",
+		Size:     1 << 16,
+		ReadOnly: true,
+	}
+
+	testAppendJSON(object, deviceNVDIMMJSONString, t)
+}
+
+func TestAppendJSONIOThread(t *testing.T) {
+	testAppendJSON(IOThread{ID: "iothread1"}, `-object {"id":"iothread1","qom-type":"iothread"}`, t)
+}
+
+// Checks that the devices with no JSON form keep the legacy syntax.
+func TestAppendJSONLegacyDevice(t *testing.T) {
+	vsock := VSOCKDevice{
+		ID:            "vhost-vsock-pci0",
+		ContextID:     4,
+		DisableModern: true,
+		Transport:     TransportPCI,
+	}
+
+	testAppendJSON(vsock, "-device vhost-vsock-pci,disable-modern=true,id=vhost-vsock-pci0,guest-cid=4", t)
+}
+
+var deviceVirtioMemJSONString = `-object {"id":"virtiomem","mem-path":"/dev/shm","qom-type":"memory-backend-file","share":true,"size":4294967296} ` +
+	`-device {"addr":"2","bus":"pci-bridge-0","driver":"virtio-mem-pci","id":"virtiomem0","memdev":"virtiomem","requested-size":1073741824}`
+
+func TestAppendJSONDeviceVirtioMem(t *testing.T) {
+	dev := VirtioMemDevice{
+		ID:            "virtiomem0",
+		MemDev:        "virtiomem",
+		MemPath:       "/dev/shm",
+		Share:         true,
+		Size:          4 << 30,
+		RequestedSize: 1 << 30,
+		Bus:           "pci-bridge-0",
+		Addr:          "2",
+		Transport:     TransportPCI,
+	}
+
+	testAppendJSON(dev, deviceVirtioMemJSONString, t)
+}
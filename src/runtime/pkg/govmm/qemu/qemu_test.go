@@ -10,6 +10,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"syscall"
 	"testing"
 )
 
@@ -219,6 +220,48 @@ func TestAppendDeviceNetworkMq(t *testing.T) {
 	testAppend(netdev, deviceNetworkStringMq, t)
 }
 
+func TestAppendDeviceNetworkVhostVDPA(t *testing.T) {
+	netdev := NetDevice{
+		Driver:        VirtioNet,
+		Type:          VHOSTVDPA,
+		ID:            "vdpa0",
+		VhostDev:      "/dev/vhost-vdpa-0",
+		MACAddress:    "01:02:de:ad:be:ef",
+		DisableModern: true,
+		ROMFile:       romfile,
+	}
+
+	if netdev.Transport.isVirtioPCI(nil) {
+		netdev.Bus = "/pci-bus/pcie.0"
+		netdev.Addr = "255"
+	} else if netdev.Transport.isVirtioCCW(nil) {
+		netdev.DevNo = DevNo
+	}
+
+	testAppend(netdev, deviceNetworkVhostVDPAString, t)
+}
+
+var deviceNetworkStreamString = "-netdev stream,id=passt0,server=off,addr.type=unix,addr.path=/run/user/1000/passt.socket " +
+	"-device driver=virtio-net-pci,netdev=passt0,mac=01:02:de:ad:be:ef,disable-modern=false"
+
+func TestAppendDeviceNetworkStream(t *testing.T) {
+	netdev := NetDevice{
+		Driver:     VirtioNet,
+		Type:       STREAM,
+		ID:         "passt0",
+		SocketPath: "/run/user/1000/passt.socket",
+		MACAddress: "01:02:de:ad:be:ef",
+		Transport:  TransportPCI,
+	}
+
+	testAppend(netdev, deviceNetworkStreamString, t)
+
+	netdev.SocketPath = ""
+	if netdev.Valid() {
+		t.Fatalf("stream network device should be not valid without socket")
+	}
+}
+
 var deviceLegacySerialString = "-serial chardev:tlserial0"
 
 func TestAppendLegacySerial(t *testing.T) {
@@ -256,6 +299,36 @@ func TestAppendDeviceSerial(t *testing.T) {
 	testAppend(sdev, deviceSerialString, t)
 }
 
+var deviceTPMString = "-chardev socket,id=chartpm0,path=/tmp/swtpm.sock -tpmdev emulator,id=tpm0,chardev=chartpm0 -device tpm-crb,tpmdev=tpm0"
+
+func TestAppendDeviceTPM(t *testing.T) {
+	tpmdev := TPMDevice{
+		ID:     "tpm0",
+		Path:   "/tmp/swtpm.sock",
+		Driver: TPMCRB,
+	}
+
+	testAppend(tpmdev, deviceTPMString, t)
+}
+
+func TestTPMDeviceValid(t *testing.T) {
+	tpmdev := TPMDevice{}
+	if tpmdev.Valid() {
+		t.Fatalf("TPM device %v should not be valid", tpmdev)
+	}
+
+	tpmdev.ID = "tpm0"
+	tpmdev.Path = "/tmp/swtpm.sock"
+	if tpmdev.Valid() {
+		t.Fatalf("TPM device %v without driver should not be valid", tpmdev)
+	}
+
+	tpmdev.Driver = TPMTIS
+	if !tpmdev.Valid() {
+		t.Fatalf("TPM device %v should be valid", tpmdev)
+	}
+}
+
 var deviceSerialPortString = "-device virtserialport,chardev=char0,id=channel0,name=channel.0 -chardev socket,id=char0,path=/tmp/char.sock,server=on,wait=off"
 
 func TestAppendDeviceSerialPort(t *testing.T) {
@@ -294,6 +367,24 @@ func TestAppendDeviceBlock(t *testing.T) {
 	testAppend(blkdev, deviceBlockString, t)
 }
 
+func TestAppendDeviceBlockThrottled(t *testing.T) {
+	blkdev := BlockDevice{
+		Driver:         VirtioBlock,
+		ID:             "hd0",
+		DevID:          "virtio-hd0",
+		File:           "/var/lib/vm.img",
+		AIO:            Threads,
+		Format:         "raw",
+		Interface:      NoInterface,
+		Transport:      TransportPCI,
+		ThrottleGroup:  "throttle-hd0",
+		ThrottleLimits: ThrottleLimits{BpsTotal: 1 << 20, IopsTotal: 100},
+	}
+	testAppend(blkdev, "-object throttle-group,id=throttle-hd0 "+
+		"-device virtio-blk-pci,disable-modern=false,id=virtio-hd0,drive=hd0,scsi=off,config-wce=off,serial=hd0 "+
+		"-drive id=hd0,file=/var/lib/vm.img,aio=threads,format=raw,if=none,throttling.group=throttle-hd0,throttling.bps-total=1048576,throttling.iops-total=100", t)
+}
+
 func TestAppendDeviceVFIO(t *testing.T) {
 	vfioDevice := VFIODevice{
 		BDF:      "02:10.0",
@@ -419,6 +510,49 @@ func TestVirtioBalloonValid(t *testing.T) {
 	}
 }
 
+var deviceVirtioMemString = "-object memory-backend-ram,id=virtiomem,size=4294967296 " +
+	"-device virtio-mem-pci,id=virtiomem0,memdev=virtiomem,requested-size=0,block-size=2097152"
+
+func TestAppendDeviceVirtioMem(t *testing.T) {
+	dev := VirtioMemDevice{
+		ID:        "virtiomem0",
+		MemDev:    "virtiomem",
+		Size:      4 << 30,
+		BlockSize: 2 << 20,
+		Transport: TransportPCI,
+	}
+
+	testAppend(dev, deviceVirtioMemString, t)
+}
+
+func TestVirtioMemValid(t *testing.T) {
+	dev := VirtioMemDevice{
+		ID:        "virtiomem0",
+		MemDev:    "virtiomem",
+		Transport: TransportPCI,
+	}
+
+	if dev.Valid() {
+		t.Fatalf("virtio-mem device should be not valid when Size is 0")
+	}
+
+	dev.Size = 1 << 30
+	dev.RequestedSize = 2 << 30
+	if dev.Valid() {
+		t.Fatalf("virtio-mem device should be not valid when RequestedSize is larger than Size")
+	}
+
+	dev.RequestedSize = 1 << 30
+	if !dev.Valid() {
+		t.Fatalf("virtio-mem device should be valid")
+	}
+
+	dev.Transport = TransportCCW
+	if dev.Valid() {
+		t.Fatalf("virtio-mem device should be not valid on CCW")
+	}
+}
+
 func TestAppendDeviceSCSIController(t *testing.T) {
 	scsiCon := SCSIController{
 		ID:      "foo",
@@ -632,6 +766,62 @@ func TestAppendMemoryFileBackedMemPrealloc(t *testing.T) {
 	testConfigAppend(conf, knobs, memString+" "+knobsString, t)
 }
 
+func TestAppendMemoryNUMANodes(t *testing.T) {
+	conf := &Config{
+		Memory: Memory{
+			Size:   "3G",
+			Slots:  8,
+			MaxMem: "6G",
+			NUMANodes: []NUMANode{
+				{
+					Size:      "2048M",
+					HostNodes: "0",
+					CPUs:      []string{"0-1", "4-5"},
+				},
+				{
+					Size: "1024M",
+					CPUs: []string{"2-3", "6-7"},
+				},
+			},
+		},
+	}
+	memString := "-m 3G,slots=8,maxmem=6G"
+	testConfigAppend(conf, conf.Memory, memString, t)
+
+	knobs := Knobs{
+		MemPrealloc: true,
+	}
+
+	knobsString := "-object memory-backend-ram,id=dimm1,size=3G,prealloc=on -machine memory-backend=dimm1"
+	if isDimmSupported(nil) {
+		knobsString = "-object memory-backend-ram,id=dimm1,size=2048M,prealloc=on,host-nodes=0,policy=bind " +
+			"-numa node,nodeid=0,cpus=0-1,cpus=4-5,memdev=dimm1 " +
+			"-object memory-backend-ram,id=dimm2,size=1024M,prealloc=on " +
+			"-numa node,nodeid=1,cpus=2-3,cpus=6-7,memdev=dimm2"
+	}
+
+	testConfigAppend(conf, knobs, memString+" "+knobsString, t)
+}
+
+func TestLaunchQemuNUMANodesUnsupported(t *testing.T) {
+	conf := Config{
+		Machine: Machine{
+			Type: MachineTypeMicrovm,
+		},
+		Memory: Memory{
+			Size: "2G",
+			NUMANodes: []NUMANode{
+				{Size: "1024M", CPUs: []string{"0"}},
+				{Size: "1024M", CPUs: []string{"1"}},
+			},
+		},
+	}
+
+	if _, err := LaunchQemu(conf, nil); err == nil {
+		t.Fatal("Expected an error launching a microvm with guest NUMA nodes")
+	}
+}
+
 func TestNoRebootKnob(t *testing.T) {
 	conf := &Config{}
 
@@ -1221,3 +1411,18 @@ func TestLoaderDevice(t *testing.T) {
 		testAppend(tc.dev, tc.out, t)
 	}
 }
+
+func TestConfigSysProcAttr(t *testing.T) {
+	config := Config{Uid: 1000, Gid: 1000, Groups: []uint32{36}}
+
+	attr := config.sysProcAttr()
+	if !reflect.DeepEqual(attr.Credential, &syscall.Credential{Uid: 1000, Gid: 1000, Groups: []uint32{36}}) {
+		t.Fatalf("Unexpected attributes %+v", attr)
+	}
+
+	// Attributes set by the caller win over the IDs.
+	config.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if attr := config.sysProcAttr(); attr != config.SysProcAttr {
+		t.Fatalf("Unexpected attributes %+v", attr)
+	}
+}
@@ -76,6 +76,11 @@ type QMPConfig struct {
 
 	// specify the capacity of buffer used by receive QMP response.
 	MaxCapacity int
+
+	// RecordPath is the file the QMP session is appended to when set,
+	// the messages QEMU sent and the commands sent to QEMU, so that it
+	// can be replayed with StartQMPReplay.
+	RecordPath string
 }
 
 type qmpEventFilter struct {
@@ -129,6 +134,8 @@ type QMP struct {
 	connectedCh    chan<- *QMPVersion
 	disconnectedCh chan struct{}
 	version        *QMPVersion
+	subscriptions  qmpEventSubscriptions
+	recorder       *qmpRecorder
 }
 
 // QMPVersion contains the version number and the capabailities of a QEMU
@@ -247,6 +254,59 @@ type MigrationStatus struct {
 type SchemaInfo struct {
 	MetaType string `json:"meta-type"`
 	Name     string `json:"name"`
+
+	// ArgType and RetType are the types of the arguments and of the
+	// result of a command, ArgType the type of the data of an event.
+	ArgType string `json:"arg-type,omitempty"`
+	RetType string `json:"ret-type,omitempty"`
+
+	// Members are the members of an object or of an alternate, or the
+	// values of an enum.
+	Members []SchemaMember `json:"members,omitempty"`
+
+	// Values are the values of an enum.
+	Values []string `json:"values,omitempty"`
+
+	// Tag is the member of an object union telling its variant.
+	Tag string `json:"tag,omitempty"`
+
+	// Variants are the variants of an object union.
+	Variants []SchemaVariant `json:"variants,omitempty"`
+
+	// ElementType is the type of the elements of an array.
+	ElementType string `json:"element-type,omitempty"`
+
+	// Features are the special features of the entity, e.g., deprecated.
+	Features []string `json:"features,omitempty"`
+}
+
+// SchemaMember is a member of an object, an alternate or an enum of the QMP
+// wire ABI.
+type SchemaMember struct {
+	Name     string   `json:"name,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Features []string `json:"features,omitempty"`
+}
+
+// SchemaVariant is the variant of an object union for a value of its tag.
+type SchemaVariant struct {
+	Case string `json:"case"`
+	Type string `json:"type"`
+}
+
+// ObjectTypeInfo describes a QOM type, as returned by qom-list-types.
+type ObjectTypeInfo struct {
+	Name     string `json:"name"`
+	Abstract bool   `json:"abstract,omitempty"`
+	Parent   string `json:"parent,omitempty"`
+}
+
+// ObjectPropertyInfo describes a property of a QOM type, as returned by
+// device-list-properties.
+type ObjectPropertyInfo struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
 }
 
 // StatusInfo represents guest running status
@@ -290,6 +350,7 @@ func (q *QMP) readLoop(fromVMCh chan<- []byte) {
 		sendLine := make([]byte, len(line))
 		copy(sendLine, line)
 
+		q.recorder.record(QMPRecordReceived, sendLine)
 		fromVMCh <- sendLine
 	}
 	q.cfg.Logger.Infof("scanner return error: %v", scanner.Err())
@@ -330,20 +391,22 @@ func (q *QMP) processQMPEvent(cmdQueue *list.List, name interface{}, data interf
 		}
 	}
 
-	if q.cfg.EventCh != nil {
-		ev := QMPEvent{
-			Name: strname,
-			Data: eventData,
-		}
-		if timestamp != nil {
-			timestamp, ok := timestamp.(map[string]interface{})
-			if ok {
-				seconds, _ := timestamp["seconds"].(float64)
-				microseconds, _ := timestamp["microseconds"].(float64)
-				ev.Timestamp = time.Unix(int64(seconds), int64(microseconds))
-			}
+	ev := QMPEvent{
+		Name: strname,
+		Data: eventData,
+	}
+	if timestamp != nil {
+		timestamp, ok := timestamp.(map[string]interface{})
+		if ok {
+			seconds, _ := timestamp["seconds"].(float64)
+			microseconds, _ := timestamp["microseconds"].(float64)
+			ev.Timestamp = time.Unix(int64(seconds), int64(microseconds))
 		}
+	}
+
+	q.subscriptions.dispatch(ev, q.cfg.Logger)
 
+	if q.cfg.EventCh != nil {
 		q.cfg.EventCh <- ev
 	}
 }
@@ -453,6 +516,7 @@ func (q *QMP) writeNextQMPCommand(cmdQueue *list.List) {
 		}
 		cmdQueue.Remove(cmdEl)
 	}
+	q.recorder.record(QMPRecordSent, encodedCmd)
 	encodedCmd = append(encodedCmd, '\n')
 	if unixConn, ok := q.conn.(*net.UnixConn); ok && len(cmd.oob) > 0 {
 		_, _, err = unixConn.WriteMsgUnix(encodedCmd, cmd.oob, nil)
@@ -565,9 +629,11 @@ func (q *QMP) mainLoop() {
 		if q.cfg.EventCh != nil {
 			close(q.cfg.EventCh)
 		}
+		q.subscriptions.closeAll()
 		/* #nosec */
 		_ = q.conn.Close()
 		<-fromVMCh
+		q.recorder.close()
 		failOutstandingCommands(cmdQueue)
 		close(q.disconnectedCh)
 	}()
@@ -631,6 +697,15 @@ func startQMPLoop(conn io.ReadWriteCloser, cfg QMPConfig,
 		connectedCh:    connectedCh,
 		disconnectedCh: disconnectedCh,
 	}
+
+	if cfg.RecordPath != "" {
+		recorder, err := newQMPRecorder(cfg.RecordPath, cfg.Logger)
+		if err != nil {
+			cfg.Logger.Warningf("Unable to record the QMP session to %s: %v", cfg.RecordPath, err)
+		}
+		q.recorder = recorder
+	}
+
 	go q.mainLoop()
 	return q
 }
@@ -798,11 +873,128 @@ func (q *QMP) blockdevAddBaseArgs(driver string, blockDevice *BlockDevice) map[s
 		},
 	}
 
+	if blockDevice.ThrottleGroup != "" {
+		if blockDevice.ThrottledNodeName != "" {
+			blockdevArgs["node-name"] = blockDevice.ThrottledNodeName
+		}
+
+		// The guest device is attached to a throttle filter node
+		// sitting on top of the raw node.
+		blockdevArgs = map[string]interface{}{
+			"driver":         "throttle",
+			"throttle-group": blockDevice.ThrottleGroup,
+			"read-only":      blockDevice.ReadOnly,
+			"file":           blockdevArgs,
+		}
+	}
+
 	blockdevArgs["node-name"] = blockDevice.ID
 
 	return blockdevArgs
 }
 
+// ThrottleLimits represents the I/O limits of a throttle group.
+// A zero value means no limit.
+type ThrottleLimits struct {
+	// BpsTotal is the total bandwidth limit, in bytes per second
+	BpsTotal int64
+
+	// BpsTotalMax is the total bandwidth allowed during bursts, in bytes per second
+	BpsTotalMax int64
+
+	// IopsTotal is the total I/O operations limit, per second
+	IopsTotal int64
+
+	// IopsTotalMax is the total I/O operations allowed during bursts, per second
+	IopsTotalMax int64
+}
+
+// qmpArgs returns the ThrottleLimits QAPI struct. All the limits are set so
+// that updating a group also clears the limits that are not used anymore.
+func (limits ThrottleLimits) qmpArgs() map[string]interface{} {
+	return map[string]interface{}{
+		"bps-total":      limits.BpsTotal,
+		"bps-total-max":  limits.BpsTotalMax,
+		"iops-total":     limits.IopsTotal,
+		"iops-total-max": limits.IopsTotalMax,
+	}
+}
+
+// driveParams returns the -drive throttling parameters of the limits that
+// are set.
+func (limits ThrottleLimits) driveParams() []string {
+	var params []string
+
+	for _, l := range []struct {
+		name  string
+		value int64
+	}{
+		{"bps-total", limits.BpsTotal},
+		{"bps-total-max", limits.BpsTotalMax},
+		{"iops-total", limits.IopsTotal},
+		{"iops-total-max", limits.IopsTotalMax},
+	} {
+		if l.value > 0 {
+			params = append(params, fmt.Sprintf("throttling.%s=%d", l.name, l.value))
+		}
+	}
+
+	return params
+}
+
+// ExecuteThrottleGroupAdd adds a throttle group to a QEMU instance using
+// the object-add command. id is the id of the group, it must be a valid
+// QMP identifier. Block devices join the group through their ThrottleGroup.
+func (q *QMP) ExecuteThrottleGroupAdd(ctx context.Context, id string, limits ThrottleLimits) error {
+	args := map[string]interface{}{
+		"qom-type": "throttle-group",
+		"id":       id,
+		"limits":   limits.qmpArgs(),
+	}
+
+	return q.executeCommand(ctx, "object-add", args, nil)
+}
+
+// ExecuteThrottleGroupSet updates the limits of the throttle group id,
+// taking effect immediately for all the block devices of the group.
+func (q *QMP) ExecuteThrottleGroupSet(ctx context.Context, id string, limits ThrottleLimits) error {
+	args := map[string]interface{}{
+		"path":     id,
+		"property": "limits",
+		"value":    limits.qmpArgs(),
+	}
+
+	return q.executeCommand(ctx, "qom-set", args, nil)
+}
+
+// ExecuteBlockSetIOThrottle sets the I/O limits of the guest device
+// devID, for the block devices that are not part of a throttle group.
+func (q *QMP) ExecuteBlockSetIOThrottle(ctx context.Context, devID string, limits ThrottleLimits) error {
+	args := map[string]interface{}{
+		"id":       devID,
+		"bps":      limits.BpsTotal,
+		"bps_rd":   0,
+		"bps_wr":   0,
+		"iops":     limits.IopsTotal,
+		"iops_rd":  0,
+		"iops_wr":  0,
+		"bps_max":  limits.BpsTotalMax,
+		"iops_max": limits.IopsTotalMax,
+	}
+
+	return q.executeCommand(ctx, "block_set_io_throttle", args, nil)
+}
+
+// ExecuteObjectDel deletes the object id from a QEMU instance using
+// the object-del command.
+func (q *QMP) ExecuteObjectDel(ctx context.Context, id string) error {
+	args := map[string]interface{}{
+		"id": id,
+	}
+
+	return q.executeCommand(ctx, "object-del", args, nil)
+}
+
 // ExecuteBlockdevAdd sends a blockdev-add to the QEMU instance.  device is the
 // path of the device to add, e.g., /dev/rdb0, and blockdevID is an identifier
 // used to name the device.  As this identifier will be passed directly to QMP,
@@ -813,6 +1005,15 @@ func (q *QMP) ExecuteBlockdevAdd(ctx context.Context, blockDevice *BlockDevice)
 	return q.executeCommand(ctx, "blockdev-add", args, nil)
 }
 
+// ExecuteBlockdevAddWithDriver has one more parameter driver than
+// ExecuteBlockdevAdd. Parameter driver can set the driver of block device,
+// file for an image file for instance.
+func (q *QMP) ExecuteBlockdevAddWithDriver(ctx context.Context, driver string, blockDevice *BlockDevice) error {
+	args := q.blockdevAddBaseArgs(driver, blockDevice)
+
+	return q.executeCommand(ctx, "blockdev-add", args, nil)
+}
+
 // ExecuteBlockdevAddWithCache has two more parameters direct and noFlush
 // than ExecuteBlockdevAdd.
 // They are cache-related options for block devices that are described in
@@ -845,6 +1046,18 @@ func (q *QMP) ExecuteBlockdevAddWithDriverCache(ctx context.Context, driver stri
 	return q.executeCommand(ctx, "blockdev-add", blockdevArgs, nil)
 }
 
+// ExecuteBlockResize resizes the block node nodeName, added with
+// ExecuteBlockdevAdd, to size bytes. The guest is notified of the new
+// capacity of the device.
+func (q *QMP) ExecuteBlockResize(ctx context.Context, nodeName string, size int64) error {
+	args := map[string]interface{}{
+		"node-name": nodeName,
+		"size":      size,
+	}
+
+	return q.executeCommand(ctx, "block_resize", args, nil)
+}
+
 // ExecuteDeviceAdd adds the guest portion of a device to a QEMU instance
 // using the device_add command.  blockdevID should match the blockdevID passed
 // to a previous call to ExecuteBlockdevAdd.  devID is the id of the device to
@@ -1005,6 +1218,41 @@ func (q *QMP) ExecuteNetdevAddByFds(ctx context.Context, netdevType, netdevID st
 	return q.executeCommand(ctx, "netdev_add", args, nil)
 }
 
+// ExecuteNetdevAddByVhostVDPA adds a Net device backed by a vhost-vdpa
+// character device to a QEMU instance using the netdev_add command.
+// netdevID is the id of the device to add. Must be valid QMP identifier.
+func (q *QMP) ExecuteNetdevAddByVhostVDPA(ctx context.Context, netdevID, vhostdev string) error {
+	args := map[string]interface{}{
+		"type":     "vhost-vdpa",
+		"id":       netdevID,
+		"vhostdev": vhostdev,
+	}
+
+	return q.executeCommand(ctx, "netdev_add", args, nil)
+}
+
+// ExecuteNetdevAddByStream adds a Net device connected to the stream UNIX
+// socket of a userspace network backend, e.g., passt, to a QEMU instance
+// using the netdev_add command. netdevID is the id of the device to add.
+// The stream netdevs require QEMU 7.2 or later.
+func (q *QMP) ExecuteNetdevAddByStream(ctx context.Context, netdevID, socketPath string) error {
+	if q.version != nil && (q.version.Major < 7 || (q.version.Major == 7 && q.version.Minor < 2)) {
+		return fmt.Errorf("stream netdevs require qemu version 7.2 or later, this is qemu (%d.%d)", q.version.Major, q.version.Minor)
+	}
+
+	args := map[string]interface{}{
+		"type":   "stream",
+		"id":     netdevID,
+		"server": false,
+		"addr": map[string]interface{}{
+			"type": "unix",
+			"path": socketPath,
+		},
+	}
+
+	return q.executeCommand(ctx, "netdev_add", args, nil)
+}
+
 // ExecuteNetdevDel deletes a Net device from a QEMU instance
 // using the netdev_del command. netdevID is the id of the device to delete.
 func (q *QMP) ExecuteNetdevDel(ctx context.Context, netdevID string) error {
@@ -1096,6 +1344,17 @@ func (q *QMP) ExecuteDeviceDel(ctx context.Context, devID string) error {
 	return q.executeCommand(ctx, "device_del", args, filter)
 }
 
+// ExecuteDeviceDelNoWait sends a device_del command for the device devID,
+// without waiting for the guest to release it. The device is only removed
+// once QEMU emits a DEVICE_DELETED event for devID: callers subscribe to it
+// with DeviceDeletedFilter before calling this method.
+func (q *QMP) ExecuteDeviceDelNoWait(ctx context.Context, devID string) error {
+	args := map[string]interface{}{
+		"id": devID,
+	}
+	return q.executeCommand(ctx, "device_del", args, nil)
+}
+
 // ExecutePCIDeviceAdd is the PCI version of ExecuteDeviceAdd. This function can be used
 // to hot plug PCI devices on PCI(E) bridges, unlike ExecuteDeviceAdd this function receive the
 // device address on its parent bus. bus is optional. queues specifies the number of queues of
@@ -1222,6 +1481,26 @@ func (q *QMP) ExecutePCIVFIOMediatedDeviceAdd(ctx context.Context, devID, sysfsd
 	return q.executeCommand(ctx, "device_add", args, nil)
 }
 
+// ExecutePCIVhostVDPADeviceAdd adds a generic vhost-vdpa device to a QEMU instance using the device_add command.
+// devID is the id of the device to add. Must be valid QMP identifier. vhostdev is the vhost-vdpa
+// character device on the host. Both bus and addr are optional. If they are both set to be empty,
+// the system will pick up an empty slot on root bus.
+func (q *QMP) ExecutePCIVhostVDPADeviceAdd(ctx context.Context, devID, vhostdev, addr, bus string) error {
+	args := map[string]interface{}{
+		"id":       devID,
+		"driver":   VhostVDPADevicePCI,
+		"vhostdev": vhostdev,
+	}
+
+	if bus != "" {
+		args["bus"] = bus
+	}
+	if addr != "" {
+		args["addr"] = addr
+	}
+	return q.executeCommand(ctx, "device_add", args, nil)
+}
+
 // ExecuteAPVFIOMediatedDeviceAdd adds a VFIO mediated AP device to a QEMU instance using the device_add command.
 func (q *QMP) ExecuteAPVFIOMediatedDeviceAdd(ctx context.Context, sysfsdev string) error {
 	args := map[string]interface{}{
@@ -1491,6 +1770,34 @@ func (q *QMP) ExecuteBalloon(ctx context.Context, bytes uint64) error {
 	return q.executeCommand(ctx, "balloon", args, nil)
 }
 
+// BalloonInfo is the result of the query-balloon command.
+type BalloonInfo struct {
+	// Actual is the memory, in bytes, the guest is left with by the
+	// balloon.
+	Actual int64 `json:"actual"`
+}
+
+// ExecuteQueryBalloon returns the memory the guest is left with by the
+// balloon.
+func (q *QMP) ExecuteQueryBalloon(ctx context.Context) (BalloonInfo, error) {
+	response, err := q.executeCommandWithResponse(ctx, "query-balloon", nil, nil, nil)
+	if err != nil {
+		return BalloonInfo{}, err
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return BalloonInfo{}, fmt.Errorf("unable to extract balloon information: %v", err)
+	}
+
+	var info BalloonInfo
+	if err = json.Unmarshal(data, &info); err != nil {
+		return BalloonInfo{}, fmt.Errorf("unable to convert json to balloon information: %v", err)
+	}
+
+	return info, nil
+}
+
 // ExecutePCIVSockAdd adds a vhost-vsock-pci bus
 // disableModern indicates if virtio version 1.0 should be replaced by the
 // former version 0.9, as there is a KVM bug that occurs when using virtio
@@ -1619,6 +1926,60 @@ func (q *QMP) ExecQueryQmpSchema(ctx context.Context) ([]SchemaInfo, error) {
 	return schemaInfo, nil
 }
 
+// ExecuteQOMListTypes returns the QOM types implementing the type
+// implements, e.g., device, all of them when empty. The abstract types are
+// only returned when abstract is true.
+func (q *QMP) ExecuteQOMListTypes(ctx context.Context, implements string, abstract bool) ([]ObjectTypeInfo, error) {
+	args := map[string]interface{}{
+		"abstract": abstract,
+	}
+	if implements != "" {
+		args["implements"] = implements
+	}
+
+	response, err := q.executeCommandWithResponse(ctx, "qom-list-types", args, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract QOM types information: %v", err)
+	}
+
+	var types []ObjectTypeInfo
+	if err = json.Unmarshal(data, &types); err != nil {
+		return nil, fmt.Errorf("unable to convert json to QOM types information: %v", err)
+	}
+
+	return types, nil
+}
+
+// ExecuteDeviceListProperties returns the properties of the device driver
+// typename.
+func (q *QMP) ExecuteDeviceListProperties(ctx context.Context, typename string) ([]ObjectPropertyInfo, error) {
+	args := map[string]interface{}{
+		"typename": typename,
+	}
+
+	response, err := q.executeCommandWithResponse(ctx, "device-list-properties", args, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract device properties information: %v", err)
+	}
+
+	var props []ObjectPropertyInfo
+	if err = json.Unmarshal(data, &props); err != nil {
+		return nil, fmt.Errorf("unable to convert json to device properties information: %v", err)
+	}
+
+	return props, nil
+}
+
 // ExecuteQueryStatus queries guest status
 func (q *QMP) ExecuteQueryStatus(ctx context.Context) (StatusInfo, error) {
 	response, err := q.executeCommandWithResponse(ctx, "query-status", nil, nil, nil)
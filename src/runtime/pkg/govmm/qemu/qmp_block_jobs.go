@@ -0,0 +1,377 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Statuses of a QMP job, as reported by query-jobs and JOB_STATUS_CHANGE
+// events.
+const (
+	JobStatusCreated   = "created"
+	JobStatusRunning   = "running"
+	JobStatusPaused    = "paused"
+	JobStatusReady     = "ready"
+	JobStatusStandby   = "standby"
+	JobStatusWaiting   = "waiting"
+	JobStatusPending   = "pending"
+	JobStatusAborting  = "aborting"
+	JobStatusConcluded = "concluded"
+	JobStatusNull      = "null"
+)
+
+// BlockJobSync is the part of the source image a block job copies.
+type BlockJobSync string
+
+const (
+	// BlockJobSyncFull copies the whole image, backing chain included.
+	BlockJobSyncFull BlockJobSync = "full"
+
+	// BlockJobSyncTop only copies the top image of the chain.
+	BlockJobSyncTop BlockJobSync = "top"
+)
+
+// JobInfo is the status of a job, as reported by query-jobs.
+type JobInfo struct {
+	// ID is the id of the job.
+	ID string `json:"id"`
+	// Type is the type of the job, e.g., mirror or commit.
+	Type string `json:"type"`
+	// Status is the status of the job, e.g., running or concluded.
+	Status string `json:"status"`
+	// CurrentProgress and TotalProgress are the progress of the job, in
+	// an arbitrary unit. TotalProgress may change while the job runs.
+	CurrentProgress int64 `json:"current-progress"`
+	TotalProgress   int64 `json:"total-progress"`
+	// Error is set when the job concluded with a failure.
+	Error string `json:"error,omitempty"`
+}
+
+// Progress returns the completion of the job, between 0 and 1.
+func (j JobInfo) Progress() float64 {
+	if j.TotalProgress <= 0 {
+		if j.Status == JobStatusConcluded {
+			return 1
+		}
+		return 0
+	}
+
+	return float64(j.CurrentProgress) / float64(j.TotalProgress)
+}
+
+// BlockInfo is a block device of the QEMU instance, as reported by
+// query-block.
+type BlockInfo struct {
+	// Device is the name of the block backend, empty for the block
+	// backends of the devices using a block node.
+	Device string `json:"device"`
+	// QDev is the id or the QOM path of the device using the block
+	// backend.
+	QDev string `json:"qdev"`
+	// Inserted is the top block node of the block backend, nil without
+	// medium.
+	Inserted *BlockNodeInfo `json:"inserted,omitempty"`
+}
+
+// BlockNodeInfo is a block node, as reported by query-block.
+type BlockNodeInfo struct {
+	// NodeName is the name of the block node.
+	NodeName string `json:"node-name"`
+	// File is the file opened by the block node, the one of its child
+	// for filter nodes.
+	File string `json:"file"`
+	// Driver is the format or the filter of the block node.
+	Driver string `json:"drv"`
+	// ReadOnly tells if the block node is read only.
+	ReadOnly bool `json:"ro"`
+}
+
+// ExecuteQueryBlock returns the block devices of the QEMU instance.
+func (q *QMP) ExecuteQueryBlock(ctx context.Context) ([]BlockInfo, error) {
+	response, err := q.executeCommandWithResponse(ctx, "query-block", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract block information: %v", err)
+	}
+
+	var blocks []BlockInfo
+	if err = json.Unmarshal(data, &blocks); err != nil {
+		return nil, fmt.Errorf("unable to convert json to block information: %v", err)
+	}
+
+	return blocks, nil
+}
+
+// ExecuteBlockdevCreateFile starts the job jobID creating the file filename
+// of size bytes on the host. The job must be dismissed once concluded.
+func (q *QMP) ExecuteBlockdevCreateFile(ctx context.Context, jobID, filename string, size int64) error {
+	args := map[string]interface{}{
+		"job-id": jobID,
+		"options": map[string]interface{}{
+			"driver":   "file",
+			"filename": filename,
+			"size":     size,
+		},
+	}
+
+	return q.executeCommand(ctx, "blockdev-create", args, nil)
+}
+
+// ExecuteBlockdevCreateQcow2 starts the job jobID formatting the block node
+// fileNode as a qcow2 image of size bytes. The job must be dismissed once
+// concluded.
+func (q *QMP) ExecuteBlockdevCreateQcow2(ctx context.Context, jobID, fileNode string, size int64) error {
+	args := map[string]interface{}{
+		"job-id": jobID,
+		"options": map[string]interface{}{
+			"driver": "qcow2",
+			"file":   fileNode,
+			"size":   size,
+		},
+	}
+
+	return q.executeCommand(ctx, "blockdev-create", args, nil)
+}
+
+// ExecuteBlockdevAddFile adds the block node nodeName, opening the file
+// filename on the host.
+func (q *QMP) ExecuteBlockdevAddFile(ctx context.Context, nodeName, filename string) error {
+	args := map[string]interface{}{
+		"driver":    "file",
+		"node-name": nodeName,
+		"filename":  filename,
+	}
+
+	return q.executeCommand(ctx, "blockdev-add", args, nil)
+}
+
+// ExecuteBlockdevAddOverlay adds the qcow2 block node nodeName on top of
+// the block node fileNode, without backing image, so that it can be used as
+// the overlay of ExecuteBlockdevSnapshot.
+func (q *QMP) ExecuteBlockdevAddOverlay(ctx context.Context, nodeName, fileNode string) error {
+	args := map[string]interface{}{
+		"driver":    "qcow2",
+		"node-name": nodeName,
+		"file":      fileNode,
+		"backing":   nil,
+	}
+
+	return q.executeCommand(ctx, "blockdev-add", args, nil)
+}
+
+// ExecuteBlockdevSnapshot takes an external snapshot of the block node
+// node: overlay, added with ExecuteBlockdevAddOverlay, becomes the active
+// image with node as its backing image. node is not written to anymore
+// until overlay is committed into it.
+func (q *QMP) ExecuteBlockdevSnapshot(ctx context.Context, node, overlay string) error {
+	args := map[string]interface{}{
+		"node":    node,
+		"overlay": overlay,
+	}
+
+	return q.executeCommand(ctx, "blockdev-snapshot", args, nil)
+}
+
+// ExecuteBlockdevMirror starts the job jobID copying the block device
+// device to the block node target, then mirroring the writes to device
+// until the job is completed or cancelled. The job is ready once target
+// is in sync with device. speed limits the job in bytes per second, 0
+// means unlimited. The job must be dismissed once concluded.
+func (q *QMP) ExecuteBlockdevMirror(ctx context.Context, jobID, device, target string, sync BlockJobSync, speed int64) error {
+	args := map[string]interface{}{
+		"job-id":       jobID,
+		"device":       device,
+		"target":       target,
+		"sync":         string(sync),
+		"auto-dismiss": false,
+	}
+	if speed > 0 {
+		args["speed"] = speed
+	}
+
+	return q.executeCommand(ctx, "blockdev-mirror", args, nil)
+}
+
+// ExecuteBlockdevBackup starts the job jobID copying the block node or
+// device device, as it is when the job starts, to the block node target.
+// The guest keeps writing to device while the job runs, the data it
+// overwrites is copied first. target must have the size of device. speed
+// limits the job in bytes per second, 0 means unlimited. The job must be
+// dismissed once concluded.
+func (q *QMP) ExecuteBlockdevBackup(ctx context.Context, jobID, device, target string, sync BlockJobSync, speed int64) error {
+	args := map[string]interface{}{
+		"job-id":       jobID,
+		"device":       device,
+		"target":       target,
+		"sync":         string(sync),
+		"auto-dismiss": false,
+	}
+	if speed > 0 {
+		args["speed"] = speed
+	}
+
+	return q.executeCommand(ctx, "blockdev-backup", args, nil)
+}
+
+// ExecuteBlockStream starts the job jobID copying the data of the backing
+// chain of the block device device into its top image, down to the block
+// node baseNode excluded. The whole chain is streamed when baseNode is
+// empty. speed limits the job in bytes per second, 0 means unlimited. The
+// job must be dismissed once concluded.
+func (q *QMP) ExecuteBlockStream(ctx context.Context, jobID, device, baseNode string, speed int64) error {
+	args := map[string]interface{}{
+		"job-id":       jobID,
+		"device":       device,
+		"auto-dismiss": false,
+	}
+	if baseNode != "" {
+		args["base-node"] = baseNode
+	}
+	if speed > 0 {
+		args["speed"] = speed
+	}
+
+	return q.executeCommand(ctx, "block-stream", args, nil)
+}
+
+// ExecuteBlockCommit starts the job jobID merging the images of the backing
+// chain of the block device device, from the block node topNode down to the
+// block node baseNode, into baseNode. When topNode is the active image, the
+// job is ready once baseNode is in sync and must be completed to pivot the
+// device to baseNode. speed limits the job in bytes per second, 0 means
+// unlimited. The job must be dismissed once concluded.
+func (q *QMP) ExecuteBlockCommit(ctx context.Context, jobID, device, topNode, baseNode string, speed int64) error {
+	args := map[string]interface{}{
+		"job-id":       jobID,
+		"device":       device,
+		"top-node":     topNode,
+		"base-node":    baseNode,
+		"auto-dismiss": false,
+	}
+	if speed > 0 {
+		args["speed"] = speed
+	}
+
+	return q.executeCommand(ctx, "block-commit", args, nil)
+}
+
+// ExecuteQueryJobs returns the status of the jobs of the QEMU instance.
+func (q *QMP) ExecuteQueryJobs(ctx context.Context) ([]JobInfo, error) {
+	response, err := q.executeCommandWithResponse(ctx, "query-jobs", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract jobs information: %v", err)
+	}
+
+	var jobs []JobInfo
+	if err = json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("unable to convert json to jobs information: %v", err)
+	}
+
+	return jobs, nil
+}
+
+// ExecuteQueryJob returns the status of the job jobID.
+func (q *QMP) ExecuteQueryJob(ctx context.Context, jobID string) (JobInfo, error) {
+	jobs, err := q.ExecuteQueryJobs(ctx)
+	if err != nil {
+		return JobInfo{}, err
+	}
+
+	for _, job := range jobs {
+		if job.ID == jobID {
+			return job, nil
+		}
+	}
+
+	return JobInfo{}, fmt.Errorf("job %s not found", jobID)
+}
+
+// ExecuteJobComplete completes the ready job jobID, e.g., pivots the device
+// of a mirror job to its target.
+func (q *QMP) ExecuteJobComplete(ctx context.Context, jobID string) error {
+	args := map[string]interface{}{
+		"id": jobID,
+	}
+
+	return q.executeCommand(ctx, "job-complete", args, nil)
+}
+
+// ExecuteJobCancel cancels the job jobID. A ready mirror job cancelled
+// leaves a consistent copy of its device in its target.
+func (q *QMP) ExecuteJobCancel(ctx context.Context, jobID string) error {
+	args := map[string]interface{}{
+		"id": jobID,
+	}
+
+	return q.executeCommand(ctx, "job-cancel", args, nil)
+}
+
+// ExecuteJobDismiss removes the concluded job jobID.
+func (q *QMP) ExecuteJobDismiss(ctx context.Context, jobID string) error {
+	args := map[string]interface{}{
+		"id": jobID,
+	}
+
+	return q.executeCommand(ctx, "job-dismiss", args, nil)
+}
+
+// WaitForJobStatus waits until the job jobID reaches status, or concludes,
+// until ctx is done. The status of the job is polled every interval, and
+// each time the job changes status, and passed to progress when not nil.
+// An error is returned when the job concluded with a failure.
+func (q *QMP) WaitForJobStatus(ctx context.Context, jobID, status string, interval time.Duration, progress func(JobInfo)) (JobInfo, error) {
+	sub := q.SubscribeEvents(JobStatusChangeFilter(jobID))
+	defer sub.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job, err := q.ExecuteQueryJob(ctx, jobID)
+		if err != nil {
+			return JobInfo{}, err
+		}
+
+		if progress != nil {
+			progress(job)
+		}
+
+		if job.Status == JobStatusConcluded {
+			if job.Error != "" {
+				return job, fmt.Errorf("job %s failed: %s", jobID, job.Error)
+			}
+			return job, nil
+		}
+
+		if job.Status == status {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case _, ok := <-sub.Events():
+			if !ok {
+				return job, errors.New("QMP connection lost while waiting for job")
+			}
+		case <-ticker.C:
+		}
+	}
+}
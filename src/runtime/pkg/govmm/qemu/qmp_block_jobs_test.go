@@ -0,0 +1,207 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Checks that the block job commands are correctly sent.
+//
+// We start a QMPLoop, create and add a qcow2 overlay, snapshot a block
+// node, start mirror, backup, stream and commit jobs, complete, cancel and dismiss
+// them and stop the loop.
+func TestQMPBlockJobCommands(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	for _, name := range []string{"blockdev-create", "blockdev-create", "blockdev-add", "blockdev-add",
+		"blockdev-snapshot", "blockdev-mirror", "blockdev-backup", "block-stream", "block-commit",
+		"job-complete", "job-cancel", "job-dismiss"} {
+		buf.AddCommand(name, nil, "return", nil)
+	}
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+	ctx := context.Background()
+
+	for _, f := range []func() error{
+		func() error { return q.ExecuteBlockdevCreateFile(ctx, "create0", "/tmp/overlay.qcow2", 0) },
+		func() error { return q.ExecuteBlockdevCreateQcow2(ctx, "create1", "overlay-file", 1<<30) },
+		func() error { return q.ExecuteBlockdevAddFile(ctx, "overlay-file", "/tmp/overlay.qcow2") },
+		func() error { return q.ExecuteBlockdevAddOverlay(ctx, "overlay", "overlay-file") },
+		func() error { return q.ExecuteBlockdevSnapshot(ctx, "drive0", "overlay") },
+		func() error { return q.ExecuteBlockdevMirror(ctx, "mirror0", "drive0", "target", BlockJobSyncFull, 0) },
+		func() error { return q.ExecuteBlockdevBackup(ctx, "backup0", "drive0", "target", BlockJobSyncFull, 0) },
+		func() error { return q.ExecuteBlockStream(ctx, "stream0", "drive0", "", 1<<20) },
+		func() error { return q.ExecuteBlockCommit(ctx, "commit0", "drive0", "overlay", "drive0-raw", 0) },
+		func() error { return q.ExecuteJobComplete(ctx, "commit0") },
+		func() error { return q.ExecuteJobCancel(ctx, "mirror0") },
+		func() error { return q.ExecuteJobDismiss(ctx, "commit0") },
+	} {
+		if err := f(); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the jobs and their progress are correctly returned.
+func TestQMPExecuteQueryJobs(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	jobs := []interface{}{
+		map[string]interface{}{
+			"id":               "mirror0",
+			"type":             "mirror",
+			"status":           "running",
+			"current-progress": 256,
+			"total-progress":   1024,
+		},
+	}
+	buf.AddCommand("query-jobs", nil, "return", jobs)
+	buf.AddCommand("query-jobs", nil, "return", jobs)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	info, err := q.ExecuteQueryJobs(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(info) != 1 || info[0].ID != "mirror0" || info[0].Type != "mirror" ||
+		info[0].Status != JobStatusRunning {
+		t.Fatalf("Unexpected jobs %v", info)
+	}
+	if info[0].Progress() != 0.25 {
+		t.Fatalf("Unexpected job progress %v", info[0].Progress())
+	}
+
+	_, err = q.ExecuteQueryJob(context.Background(), "commit0")
+	if err == nil {
+		t.Fatal("Expected an error querying an unknown job")
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the block devices and their top block node are correctly
+// returned.
+func TestQMPExecuteQueryBlock(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	blocks := []interface{}{
+		map[string]interface{}{
+			"device": "",
+			"qdev":   "virtio-drive0",
+			"inserted": map[string]interface{}{
+				"node-name": "drive0",
+				"file":      "/tmp/drive0.img",
+				"drv":       "raw",
+				"ro":        false,
+			},
+		},
+		map[string]interface{}{
+			"device": "cdrom0",
+			"qdev":   "/machine/peripheral-anon/device[0]",
+		},
+	}
+	buf.AddCommand("query-block", nil, "return", blocks)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	info, err := q.ExecuteQueryBlock(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(info) != 2 || info[0].QDev != "virtio-drive0" || info[0].Inserted == nil ||
+		info[0].Inserted.NodeName != "drive0" || info[0].Inserted.File != "/tmp/drive0.img" ||
+		info[1].Device != "cdrom0" || info[1].Inserted != nil {
+		t.Fatalf("Unexpected block devices %v", info)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+func TestJobInfoProgress(t *testing.T) {
+	for _, tc := range []struct {
+		job      JobInfo
+		expected float64
+	}{
+		{JobInfo{Status: JobStatusCreated}, 0},
+		{JobInfo{Status: JobStatusRunning, CurrentProgress: 3, TotalProgress: 4}, 0.75},
+		{JobInfo{Status: JobStatusConcluded}, 1},
+	} {
+		if progress := tc.job.Progress(); progress != tc.expected {
+			t.Errorf("Unexpected progress of %v. Expected %v found %v", tc.job, tc.expected, progress)
+		}
+	}
+}
+
+// Checks that waiting for a job reports its progress until it reaches the
+// expected status.
+//
+// We start a QMPLoop with a job running, then ready.
+//
+// WaitForJobStatus should report the progress of the job twice and return
+// once the job is ready.
+func TestQMPWaitForJobStatus(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("query-jobs", nil, "return", []interface{}{
+		map[string]interface{}{"id": "mirror0", "type": "mirror", "status": "running"},
+	})
+	buf.AddCommand("query-jobs", nil, "return", []interface{}{
+		map[string]interface{}{"id": "mirror0", "type": "mirror", "status": "ready"},
+	})
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	var reported []JobInfo
+	job, err := q.WaitForJobStatus(context.Background(), "mirror0", JobStatusReady, 10*time.Millisecond, func(job JobInfo) {
+		reported = append(reported, job)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if job.Status != JobStatusReady || len(reported) != 2 {
+		t.Fatalf("Unexpected job %v, reported %v", job, reported)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that waiting for a job that concluded with a failure returns an
+// error.
+func TestQMPWaitForJobStatusFailure(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("query-jobs", nil, "return", []interface{}{
+		map[string]interface{}{"id": "commit0", "type": "commit", "status": "concluded", "error": "No space left on device"},
+	})
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	job, err := q.WaitForJobStatus(context.Background(), "commit0", JobStatusConcluded, time.Second, nil)
+	if err == nil {
+		t.Fatal("Expected an error waiting for a failed job")
+	}
+	if job.Error != "No space left on device" {
+		t.Fatalf("Unexpected job %v", job)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
@@ -0,0 +1,367 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// Names of the QMP events decoded by the qemu package.
+const (
+	// EventDeviceDeleted is emitted when the guest released an unplugged
+	// device.
+	EventDeviceDeleted = "DEVICE_DELETED"
+
+	// EventBalloonChange is emitted when the guest changed the size of
+	// the memory balloon.
+	EventBalloonChange = "BALLOON_CHANGE"
+
+	// EventGuestPanicked is emitted when the guest kernel panicked.
+	EventGuestPanicked = "GUEST_PANICKED"
+
+	// EventBlockJobCompleted is emitted when a block job completed,
+	// successfully or not.
+	EventBlockJobCompleted = "BLOCK_JOB_COMPLETED"
+
+	// EventMigration is emitted when the status of a migration changed.
+	EventMigration = "MIGRATION"
+
+	// EventJobStatusChange is emitted when the status of a job changed.
+	EventJobStatusChange = "JOB_STATUS_CHANGE"
+
+	// EventMemoryDeviceSizeChange is emitted when the guest changed the
+	// memory plugged by a virtio-mem device.
+	EventMemoryDeviceSizeChange = "MEMORY_DEVICE_SIZE_CHANGE"
+)
+
+// qmpEventSubscriptionSize is the number of events a subscription buffers
+// before dropping the next ones.
+const qmpEventSubscriptionSize = 32
+
+// DeviceDeletedEvent is the data of a DEVICE_DELETED event.
+type DeviceDeletedEvent struct {
+	// Device is the id of the device, empty for devices without id.
+	Device string `json:"device"`
+	// Path is the QOM path of the device.
+	Path string `json:"path"`
+}
+
+// BalloonChangeEvent is the data of a BALLOON_CHANGE event.
+type BalloonChangeEvent struct {
+	// Actual is the size of the guest memory in bytes, the memory
+	// taken by the balloon excluded.
+	Actual int64 `json:"actual"`
+}
+
+// GuestPanickedEvent is the data of a GUEST_PANICKED event.
+type GuestPanickedEvent struct {
+	// Action is what QEMU does about the panic, e.g., pause or poweroff.
+	Action string `json:"action"`
+	// Info is the information about the panic reported by the guest,
+	// if any.
+	Info map[string]interface{} `json:"info,omitempty"`
+}
+
+// BlockJobCompletedEvent is the data of a BLOCK_JOB_COMPLETED event.
+type BlockJobCompletedEvent struct {
+	// Type is the type of the job, e.g., stream or mirror.
+	Type string `json:"type"`
+	// Device is the id of the job.
+	Device string `json:"device"`
+	Len    int64  `json:"len"`
+	Offset int64  `json:"offset"`
+	Speed  int64  `json:"speed"`
+	// Error is set when the job failed.
+	Error string `json:"error,omitempty"`
+}
+
+// MigrationEvent is the data of a MIGRATION event.
+type MigrationEvent struct {
+	// Status is the new status of the migration, e.g., active or
+	// completed.
+	Status string `json:"status"`
+}
+
+// JobStatusChangeEvent is the data of a JOB_STATUS_CHANGE event.
+type JobStatusChangeEvent struct {
+	// ID is the id of the job.
+	ID string `json:"id"`
+	// Status is the new status of the job, e.g., ready or concluded.
+	Status string `json:"status"`
+}
+
+// MemoryDeviceSizeChangeEvent is the data of a MEMORY_DEVICE_SIZE_CHANGE
+// event.
+type MemoryDeviceSizeChangeEvent struct {
+	// ID is the id of the device, empty for devices without id.
+	ID string `json:"id,omitempty"`
+	// Size is the memory in bytes plugged by the device.
+	Size uint64 `json:"size"`
+	// QOMPath is the QOM path of the device.
+	QOMPath string `json:"qom-path"`
+}
+
+// Decode decodes the data of the event into v, usually a pointer to the
+// structure of the event, e.g., a DeviceDeletedEvent for a DEVICE_DELETED
+// event.
+func (e QMPEvent) Decode(v interface{}) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// Payload returns the data of the event decoded into the structure of the
+// event, e.g., a *DeviceDeletedEvent for a DEVICE_DELETED event. The data
+// of the events the qemu package does not decode is returned as is.
+func (e QMPEvent) Payload() (interface{}, error) {
+	var v interface{}
+
+	switch e.Name {
+	case EventDeviceDeleted:
+		v = &DeviceDeletedEvent{}
+	case EventBalloonChange:
+		v = &BalloonChangeEvent{}
+	case EventGuestPanicked:
+		v = &GuestPanickedEvent{}
+	case EventBlockJobCompleted:
+		v = &BlockJobCompletedEvent{}
+	case EventMigration:
+		v = &MigrationEvent{}
+	case EventJobStatusChange:
+		v = &JobStatusChangeEvent{}
+	case EventMemoryDeviceSizeChange:
+		v = &MemoryDeviceSizeChangeEvent{}
+	default:
+		return e.Data, nil
+	}
+
+	if err := e.Decode(v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// QMPEventFilter selects the events delivered to a subscription.
+type QMPEventFilter struct {
+	// Names are the names of the selected events. All the events are
+	// selected when empty.
+	Names []string
+
+	// Match further selects the events, by their data for instance.
+	Match func(QMPEvent) bool
+}
+
+func (f QMPEventFilter) selects(ev QMPEvent) bool {
+	if len(f.Names) > 0 {
+		found := false
+		for _, name := range f.Names {
+			if name == ev.Name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return f.Match == nil || f.Match(ev)
+}
+
+// DeviceDeletedFilter selects the DEVICE_DELETED event of the device devID.
+func DeviceDeletedFilter(devID string) QMPEventFilter {
+	return QMPEventFilter{
+		Names: []string{EventDeviceDeleted},
+		Match: func(ev QMPEvent) bool {
+			return ev.Data["device"] == devID
+		},
+	}
+}
+
+// BlockJobCompletedFilter selects the BLOCK_JOB_COMPLETED event of the job
+// jobID.
+func BlockJobCompletedFilter(jobID string) QMPEventFilter {
+	return QMPEventFilter{
+		Names: []string{EventBlockJobCompleted},
+		Match: func(ev QMPEvent) bool {
+			return ev.Data["device"] == jobID
+		},
+	}
+}
+
+// JobStatusChangeFilter selects the JOB_STATUS_CHANGE events of the job
+// jobID.
+func JobStatusChangeFilter(jobID string) QMPEventFilter {
+	return QMPEventFilter{
+		Names: []string{EventJobStatusChange},
+		Match: func(ev QMPEvent) bool {
+			return ev.Data["id"] == jobID
+		},
+	}
+}
+
+// MemoryDeviceSizeChangeFilter selects the MEMORY_DEVICE_SIZE_CHANGE events
+// of the memory device devID.
+func MemoryDeviceSizeChangeFilter(devID string) QMPEventFilter {
+	return QMPEventFilter{
+		Names: []string{EventMemoryDeviceSizeChange},
+		Match: func(ev QMPEvent) bool {
+			return ev.Data["id"] == devID
+		},
+	}
+}
+
+// MigrationFilter selects the MIGRATION events of the given statuses, or of
+// all the statuses when none is given.
+func MigrationFilter(statuses ...string) QMPEventFilter {
+	return QMPEventFilter{
+		Names: []string{EventMigration},
+		Match: func(ev QMPEvent) bool {
+			if len(statuses) == 0 {
+				return true
+			}
+			for _, status := range statuses {
+				if ev.Data["status"] == status {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// QMPEventSubscription receives the events selected by its filter, from its
+// creation until it is closed or the connection to QMP is lost.
+type QMPEventSubscription struct {
+	subscriptions *qmpEventSubscriptions
+	filter        QMPEventFilter
+	ch            chan QMPEvent
+}
+
+// Events returns the channel the events are delivered on. The channel is
+// closed when the subscription is closed or when the connection to QMP is
+// lost.
+func (s *QMPEventSubscription) Events() <-chan QMPEvent {
+	return s.ch
+}
+
+// Wait waits for the next event of the subscription, until ctx is done.
+func (s *QMPEventSubscription) Wait(ctx context.Context) (QMPEvent, error) {
+	select {
+	case ev, ok := <-s.ch:
+		if !ok {
+			return QMPEvent{}, errors.New("QMP event subscription closed")
+		}
+		return ev, nil
+	case <-ctx.Done():
+		return QMPEvent{}, ctx.Err()
+	}
+}
+
+// Close stops the delivery of the events and closes the channel of the
+// subscription. It can be called more than once.
+func (s *QMPEventSubscription) Close() {
+	s.subscriptions.remove(s)
+}
+
+// SubscribeEvents subscribes to the events selected by filter. The
+// delivery of the events never blocks the QMP instance: the events a
+// subscriber is too slow to receive are dropped once its buffer is full.
+// The events which must not be missed are received on QMPConfig.EventCh
+// instead, which blocks the QMP instance until they are.
+//
+// The subscription must be closed once the events are not needed anymore.
+func (q *QMP) SubscribeEvents(filter QMPEventFilter) *QMPEventSubscription {
+	s := &QMPEventSubscription{
+		subscriptions: &q.subscriptions,
+		filter:        filter,
+		ch:            make(chan QMPEvent, qmpEventSubscriptionSize),
+	}
+
+	q.subscriptions.add(s)
+
+	return s
+}
+
+// WaitForEvent waits for the next event selected by filter, until ctx is
+// done. The events emitted before the call are missed: to wait for an
+// event caused by a command, subscribe to the event with SubscribeEvents
+// before executing the command instead.
+func (q *QMP) WaitForEvent(ctx context.Context, filter QMPEventFilter) (QMPEvent, error) {
+	s := q.SubscribeEvents(filter)
+	defer s.Close()
+
+	return s.Wait(ctx)
+}
+
+// qmpEventSubscriptions are the event subscriptions of a QMP instance.
+type qmpEventSubscriptions struct {
+	sync.Mutex
+	subscriptions map[*QMPEventSubscription]struct{}
+	closed        bool
+}
+
+func (s *qmpEventSubscriptions) add(sub *QMPEventSubscription) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		close(sub.ch)
+		return
+	}
+
+	if s.subscriptions == nil {
+		s.subscriptions = make(map[*QMPEventSubscription]struct{})
+	}
+	s.subscriptions[sub] = struct{}{}
+}
+
+func (s *qmpEventSubscriptions) remove(sub *QMPEventSubscription) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.subscriptions[sub]; ok {
+		delete(s.subscriptions, sub)
+		close(sub.ch)
+	}
+}
+
+func (s *qmpEventSubscriptions) dispatch(ev QMPEvent, logger QMPLog) {
+	s.Lock()
+	defer s.Unlock()
+
+	for sub := range s.subscriptions {
+		if !sub.filter.selects(ev) {
+			continue
+		}
+
+		select {
+		case sub.ch <- ev:
+		default:
+			logger.Warningf("Dropping QMP event %s, the subscriber is not receiving its events", ev.Name)
+		}
+	}
+}
+
+// closeAll closes the subscriptions when the connection to QMP is lost.
+func (s *qmpEventSubscriptions) closeAll() {
+	s.Lock()
+	defer s.Unlock()
+
+	for sub := range s.subscriptions {
+		close(sub.ch)
+	}
+	s.subscriptions = nil
+	s.closed = true
+}
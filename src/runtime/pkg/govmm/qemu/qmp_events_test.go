@@ -0,0 +1,274 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func waitQMPEvent(t *testing.T, s *QMPEventSubscription) QMPEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	ev, err := s.Wait(ctx)
+	if err != nil {
+		t.Fatalf("Timed out waiting for event: %v", err)
+	}
+	return ev
+}
+
+// Checks that the events are delivered to the subscriptions selecting
+// them.
+//
+// We subscribe to all the events and to the DEVICE_DELETED event of a
+// device, then the VM emits the DEVICE_DELETED events of two devices and a
+// BALLOON_CHANGE event.
+//
+// The first subscription should get all the events, in order, the second
+// one only the event of its device.
+func TestQMPSubscribeEvents(t *testing.T) {
+	const device = "device_" + volumeUUID
+
+	var wg sync.WaitGroup
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddEvent(EventDeviceDeleted, time.Millisecond*100,
+		map[string]interface{}{
+			"device": "other_device",
+			"path":   "/machine/peripheral/other_device",
+		}, nil)
+	buf.AddEvent(EventDeviceDeleted, time.Millisecond*100,
+		map[string]interface{}{
+			"device": device,
+			"path":   "/machine/peripheral/" + device,
+		}, nil)
+	buf.AddEvent(EventBalloonChange, time.Millisecond*100,
+		map[string]interface{}{
+			"actual": 1073741824,
+		}, nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	all := q.SubscribeEvents(QMPEventFilter{})
+	defer all.Close()
+	deleted := q.SubscribeEvents(DeviceDeletedFilter(device))
+	defer deleted.Close()
+
+	buf.startEventLoop(&wg)
+
+	for _, name := range []string{EventDeviceDeleted, EventDeviceDeleted, EventBalloonChange} {
+		if ev := waitQMPEvent(t, all); ev.Name != name {
+			t.Errorf("Unexpected event. Expected %s found %s", name, ev.Name)
+		}
+	}
+
+	var data DeviceDeletedEvent
+	if err := waitQMPEvent(t, deleted).Decode(&data); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if data.Device != device {
+		t.Errorf("Unexpected device. Expected %s found %s", device, data.Device)
+	}
+
+	select {
+	case ev := <-deleted.Events():
+		t.Errorf("Unexpected event %v", ev)
+	default:
+	}
+
+	wg.Wait()
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the data of the events is decoded into their structures.
+func TestQMPEventPayload(t *testing.T) {
+	for _, tc := range []struct {
+		event    QMPEvent
+		expected interface{}
+	}{
+		{
+			QMPEvent{Name: EventDeviceDeleted, Data: map[string]interface{}{"device": "virtio-drive", "path": "/machine/peripheral/virtio-drive"}},
+			&DeviceDeletedEvent{Device: "virtio-drive", Path: "/machine/peripheral/virtio-drive"},
+		},
+		{
+			QMPEvent{Name: EventBalloonChange, Data: map[string]interface{}{"actual": float64(536870912)}},
+			&BalloonChangeEvent{Actual: 536870912},
+		},
+		{
+			QMPEvent{Name: EventGuestPanicked, Data: map[string]interface{}{"action": "pause"}},
+			&GuestPanickedEvent{Action: "pause"},
+		},
+		{
+			QMPEvent{Name: EventBlockJobCompleted, Data: map[string]interface{}{"type": "mirror", "device": "job0", "len": float64(4096), "offset": float64(4096), "speed": float64(0)}},
+			&BlockJobCompletedEvent{Type: "mirror", Device: "job0", Len: 4096, Offset: 4096},
+		},
+		{
+			QMPEvent{Name: EventMigration, Data: map[string]interface{}{"status": "completed"}},
+			&MigrationEvent{Status: "completed"},
+		},
+		{
+			QMPEvent{Name: EventJobStatusChange, Data: map[string]interface{}{"id": "job0", "status": "ready"}},
+			&JobStatusChangeEvent{ID: "job0", Status: "ready"},
+		},
+		{
+			QMPEvent{Name: EventMemoryDeviceSizeChange, Data: map[string]interface{}{"id": "virtiomem0", "size": float64(1073741824), "qom-path": "/machine/peripheral/virtiomem0"}},
+			&MemoryDeviceSizeChangeEvent{ID: "virtiomem0", Size: 1073741824, QOMPath: "/machine/peripheral/virtiomem0"},
+		},
+		{
+			QMPEvent{Name: "RESUME"},
+			map[string]interface{}(nil),
+		},
+	} {
+		payload, err := tc.event.Payload()
+		if err != nil {
+			t.Errorf("Unexpected error decoding %s: %v", tc.event.Name, err)
+			continue
+		}
+		if !reflect.DeepEqual(payload, tc.expected) {
+			t.Errorf("Unexpected %s payload. Expected %v found %v", tc.event.Name, tc.expected, payload)
+		}
+	}
+
+	_, err := QMPEvent{Name: EventBalloonChange, Data: map[string]interface{}{"actual": "many"}}.Payload()
+	if err == nil {
+		t.Error("Expected an error decoding an invalid event")
+	}
+}
+
+// Checks that the filters select the events by name and data.
+func TestQMPEventFilters(t *testing.T) {
+	completed := QMPEvent{Name: EventBlockJobCompleted, Data: map[string]interface{}{"device": "job0"}}
+	migrated := QMPEvent{Name: EventMigration, Data: map[string]interface{}{"status": "completed"}}
+	ready := QMPEvent{Name: EventJobStatusChange, Data: map[string]interface{}{"id": "job0", "status": "ready"}}
+	resized := QMPEvent{Name: EventMemoryDeviceSizeChange, Data: map[string]interface{}{"id": "virtiomem0", "size": float64(0)}}
+
+	for _, tc := range []struct {
+		filter   QMPEventFilter
+		event    QMPEvent
+		expected bool
+	}{
+		{QMPEventFilter{}, completed, true},
+		{QMPEventFilter{Names: []string{EventMigration, EventBlockJobCompleted}}, completed, true},
+		{QMPEventFilter{Names: []string{EventMigration}}, completed, false},
+		{BlockJobCompletedFilter("job0"), completed, true},
+		{BlockJobCompletedFilter("job1"), completed, false},
+		{MigrationFilter(), migrated, true},
+		{MigrationFilter("failed", "completed"), migrated, true},
+		{MigrationFilter("failed"), migrated, false},
+		{DeviceDeletedFilter("job0"), completed, false},
+		{JobStatusChangeFilter("job0"), ready, true},
+		{JobStatusChangeFilter("job1"), ready, false},
+		{JobStatusChangeFilter("job0"), completed, false},
+		{MemoryDeviceSizeChangeFilter("virtiomem0"), resized, true},
+		{MemoryDeviceSizeChangeFilter("virtiomem1"), resized, false},
+	} {
+		if selected := tc.filter.selects(tc.event); selected != tc.expected {
+			t.Errorf("Unexpected selection of %v by %v. Expected %v", tc.event, tc.filter.Names, tc.expected)
+		}
+	}
+}
+
+// Checks that waiting for an event times out with its context.
+//
+// We start a QMPLoop and wait for a MIGRATION event the VM never emits.
+//
+// WaitForEvent should return the error of the context.
+func TestQMPWaitForEventTimeout(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	_, err := q.WaitForEvent(ctx, MigrationFilter("completed"))
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, found %v", context.DeadlineExceeded, err)
+	}
+
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the subscriptions are closed when the connection to QMP is
+// lost.
+//
+// We subscribe to the events, then the connection to the VM is lost.
+//
+// Waiting on the subscription should fail, and so should the subscriptions
+// made after the connection is lost.
+func TestQMPEventSubscriptionLostConnection(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	s := q.SubscribeEvents(QMPEventFilter{})
+	close(buf.forceFail)
+	<-disconnectedCh
+
+	if _, err := s.Wait(context.Background()); err == nil {
+		t.Error("Expected an error waiting on a closed subscription")
+	}
+	s.Close()
+
+	if _, err := q.WaitForEvent(context.Background(), QMPEventFilter{}); err == nil {
+		t.Error("Expected an error waiting for an event after the connection was lost")
+	}
+}
+
+// Checks that device_del can be sent without waiting for the device to be
+// released.
+//
+// We subscribe to the DEVICE_DELETED event of the device, send the
+// device_del command, then the VM emits the event.
+//
+// ExecuteDeviceDelNoWait should return as soon as the command succeeded
+// and the event should be delivered to the subscription.
+func TestQMPExecuteDeviceDelNoWait(t *testing.T) {
+	const device = "device_" + volumeUUID
+
+	var wg sync.WaitGroup
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("device_del", nil, "return", nil)
+	buf.AddEvent(EventDeviceDeleted, time.Millisecond*200,
+		map[string]interface{}{
+			"device": device,
+		}, nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	deleted := q.SubscribeEvents(DeviceDeletedFilter(device))
+	defer deleted.Close()
+
+	if err := q.ExecuteDeviceDelNoWait(context.Background(), device); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	buf.startEventLoop(&wg)
+	if ev := waitQMPEvent(t, deleted); ev.Data["device"] != device {
+		t.Errorf("Unexpected event %v", ev)
+	}
+
+	wg.Wait()
+	q.Shutdown()
+	<-disconnectedCh
+}
@@ -0,0 +1,325 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"sync"
+)
+
+// Directions of the messages of a recorded QMP session.
+const (
+	// QMPRecordConnected starts a new session, it has no message.
+	QMPRecordConnected = "connected"
+
+	// QMPRecordReceived is a message QEMU sent: the greeting, a command
+	// result or an event.
+	QMPRecordReceived = "received"
+
+	// QMPRecordSent is a command sent to QEMU.
+	QMPRecordSent = "sent"
+)
+
+// QMPRecordWildcard is the value matching any value of a command argument
+// in a recording. Recordings are edited to use it for the arguments that
+// change from one run to another, e.g., temporary paths.
+const QMPRecordWildcard = "*"
+
+// QMPRecord is a message of a recorded QMP session. A recording is a file
+// of QMPRecords, one JSON object per line, each session starting with a
+// QMPRecordConnected record.
+type QMPRecord struct {
+	Direction string          `json:"direction"`
+	Message   json.RawMessage `json:"message,omitempty"`
+}
+
+// qmpRecorder appends the messages of a QMP session to a recording.
+type qmpRecorder struct {
+	sync.Mutex
+	file   *os.File
+	logger QMPLog
+}
+
+func newQMPRecorder(path string, logger QMPLog) (*qmpRecorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &qmpRecorder{
+		file:   file,
+		logger: logger,
+	}
+	r.record(QMPRecordConnected, nil)
+
+	return r, nil
+}
+
+// record appends a message to the recording. A failure to record is only
+// logged, it does not affect the session.
+func (r *qmpRecorder) record(direction string, message []byte) {
+	if r == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	rec := QMPRecord{
+		Direction: direction,
+		Message:   bytes.TrimSpace(message),
+	}
+	data, err := json.Marshal(&rec)
+	if err != nil {
+		r.logger.Warningf("Unable to record QMP message %s: %v", string(message), err)
+		return
+	}
+
+	if _, err = r.file.Write(append(data, '\n')); err != nil {
+		r.logger.Warningf("Unable to record QMP message %s: %v", string(message), err)
+	}
+}
+
+func (r *qmpRecorder) close() {
+	if r == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	/* #nosec */
+	_ = r.file.Close()
+}
+
+// LoadQMPRecording reads the sessions of the recording path.
+func LoadQMPRecording(path string) ([][]QMPRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions [][]QMPRecord
+	for i, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var rec QMPRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("invalid QMP record at line %d of %s: %v", i+1, path, err)
+		}
+
+		switch rec.Direction {
+		case QMPRecordConnected:
+			sessions = append(sessions, []QMPRecord{})
+		case QMPRecordReceived, QMPRecordSent:
+			if len(sessions) == 0 {
+				return nil, fmt.Errorf("QMP record at line %d of %s is out of a session", i+1, path)
+			}
+			sessions[len(sessions)-1] = append(sessions[len(sessions)-1], rec)
+		default:
+			return nil, fmt.Errorf("invalid QMP record direction %q at line %d of %s", rec.Direction, i+1, path)
+		}
+	}
+
+	return sessions, nil
+}
+
+// QMPReplay serves recorded QMP sessions on a unix socket, pretending to be
+// the QEMU instance they were recorded from. Each connection is served the
+// next session of the recording: the messages QEMU sent are sent back, the
+// commands the client sends must match the recorded ones.
+type QMPReplay struct {
+	sync.Mutex
+	listener net.Listener
+	conn     net.Conn
+	sessions [][]QMPRecord
+	served   int
+	err      error
+	done     chan struct{}
+}
+
+// StartQMPReplay starts serving the sessions of the recording path on the
+// unix socket socket, for QMPStart to connect to.
+func StartQMPReplay(socket, path string) (*QMPReplay, error) {
+	sessions, err := LoadQMPRecording(path)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &QMPReplay{
+		listener: listener,
+		sessions: sessions,
+		done:     make(chan struct{}),
+	}
+	go r.serve()
+
+	return r, nil
+}
+
+func (r *QMPReplay) serve() {
+	defer close(r.done)
+
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		r.Lock()
+		if r.served >= len(r.sessions) {
+			r.fail(fmt.Errorf("unexpected QMP connection, the %d sessions were replayed", len(r.sessions)))
+			r.Unlock()
+			/* #nosec */
+			_ = conn.Close()
+			continue
+		}
+		session := r.sessions[r.served]
+		index := r.served
+		r.served++
+		r.conn = conn
+		r.Unlock()
+
+		err = replayQMPSession(conn, session)
+
+		r.Lock()
+		if err != nil {
+			r.fail(fmt.Errorf("session %d: %v", index, err))
+		}
+		r.conn = nil
+		r.Unlock()
+
+		/* #nosec */
+		_ = conn.Close()
+	}
+}
+
+// fail records the first replay error. r must be locked.
+func (r *QMPReplay) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Close stops serving the recording. It returns an error when a command did
+// not match the recording or when sessions of the recording were not
+// replayed.
+func (r *QMPReplay) Close() error {
+	/* #nosec */
+	_ = r.listener.Close()
+
+	r.Lock()
+	if r.conn != nil {
+		/* #nosec */
+		_ = r.conn.Close()
+	}
+	r.Unlock()
+
+	<-r.done
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.err == nil && r.served < len(r.sessions) {
+		r.err = fmt.Errorf("%d of the %d QMP sessions were replayed", r.served, len(r.sessions))
+	}
+
+	return r.err
+}
+
+func replayQMPSession(conn net.Conn, session []QMPRecord) error {
+	reader := bufio.NewReader(conn)
+
+	for i, rec := range session {
+		if rec.Direction == QMPRecordReceived {
+			if _, err := conn.Write(append([]byte(rec.Message), '\n')); err != nil {
+				return fmt.Errorf("unable to send message %d: %v", i, err)
+			}
+			continue
+		}
+
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return fmt.Errorf("expected command %s, connection closed: %v", string(rec.Message), err)
+		}
+
+		if err := matchQMPCommand(rec.Message, line); err != nil {
+			return fmt.Errorf("message %d: %v", i, err)
+		}
+	}
+
+	// The client may still close the connection, but it must not send
+	// any other command.
+	if line, err := reader.ReadBytes('\n'); err == nil {
+		return fmt.Errorf("unexpected command %s after the end of the session", string(bytes.TrimSpace(line)))
+	}
+
+	return nil
+}
+
+// matchQMPCommand checks that the command sent matches the recorded one.
+func matchQMPCommand(recorded, sent []byte) error {
+	var expected, got interface{}
+
+	if err := json.Unmarshal(recorded, &expected); err != nil {
+		return fmt.Errorf("invalid recorded command %s: %v", string(recorded), err)
+	}
+	if err := json.Unmarshal(sent, &got); err != nil {
+		return fmt.Errorf("invalid command %s: %v", string(bytes.TrimSpace(sent)), err)
+	}
+
+	if !matchQMPValue(expected, got) {
+		return fmt.Errorf("expected command %s, got %s", string(recorded), string(bytes.TrimSpace(sent)))
+	}
+
+	return nil
+}
+
+func matchQMPValue(expected, got interface{}) bool {
+	if expected == QMPRecordWildcard {
+		return true
+	}
+
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		got, ok := got.(map[string]interface{})
+		if !ok || len(got) != len(expected) {
+			return false
+		}
+		for key, value := range expected {
+			v, ok := got[key]
+			if !ok || !matchQMPValue(value, v) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		got, ok := got.([]interface{})
+		if !ok || len(got) != len(expected) {
+			return false
+		}
+		for i := range expected {
+			if !matchQMPValue(expected[i], got[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(expected, got)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const qmpTestRecording = `{"direction":"connected"}
+{"direction":"received","message":{"QMP":{"version":{"qemu":{"micro":0,"minor":2,"major":7},"package":""},"capabilities":["oob"]}}}
+{"direction":"sent","message":{"execute":"qmp_capabilities"}}
+{"direction":"received","message":{"return":{}}}
+{"direction":"sent","message":{"execute":"device_del","arguments":{"id":"virtio-drive0"}}}
+{"direction":"received","message":{"return":{}}}
+{"direction":"received","message":{"event":"DEVICE_DELETED","data":{"device":"virtio-drive0","path":"/machine/peripheral/virtio-drive0"},"timestamp":{"seconds":1,"microseconds":0}}}
+{"direction":"sent","message":{"execute":"blockdev-del","arguments":{"node-name":"*"}}}
+{"direction":"received","message":{"return":{}}}
+`
+
+func writeQMPTestRecording(t *testing.T, recording string) string {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(recording), 0600); err != nil {
+		t.Fatalf("Unable to write recording: %v", err)
+	}
+	return path
+}
+
+func startQMPTestReplay(t *testing.T, recording string) (*QMPReplay, string) {
+	socket := filepath.Join(t.TempDir(), "qmp.sock")
+	replay, err := StartQMPReplay(socket, writeQMPTestRecording(t, recording))
+	if err != nil {
+		t.Fatalf("Unable to start replay: %v", err)
+	}
+	return replay, socket
+}
+
+// Checks that a QMP session is recorded.
+//
+// We start a QMPLoop recording its session, execute a command and stop the
+// loop.
+//
+// The recording should hold one session with the greeting, the command and
+// its result, in order.
+func TestQMPRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("qmp_capabilities", nil, "return", nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}, RecordPath: path}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+	err := q.ExecuteQMPCapabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+
+	sessions, err := LoadQMPRecording(path)
+	if err != nil {
+		t.Fatalf("Unexpected error loading the recording: %v", err)
+	}
+	if len(sessions) != 1 || len(sessions[0]) != 3 {
+		t.Fatalf("Unexpected sessions %v", sessions)
+	}
+
+	for i, dir := range []string{QMPRecordReceived, QMPRecordSent, QMPRecordReceived} {
+		if sessions[0][i].Direction != dir {
+			t.Errorf("Unexpected direction of message %d. Expected %s found %s", i, dir, sessions[0][i].Direction)
+		}
+	}
+
+	var cmd map[string]interface{}
+	if err := json.Unmarshal(sessions[0][1].Message, &cmd); err != nil || cmd["execute"] != "qmp_capabilities" {
+		t.Fatalf("Unexpected recorded command %s", string(sessions[0][1].Message))
+	}
+}
+
+// Checks that a recorded session is replayed to QMPStart.
+//
+// We replay a session unplugging a device and run the same commands.
+//
+// The commands should succeed, the DEVICE_DELETED event should be
+// delivered and the whole recording should be replayed.
+func TestQMPReplay(t *testing.T) {
+	replay, socket := startQMPTestReplay(t, qmpTestRecording)
+
+	disconnectedCh := make(chan struct{})
+	ctx := context.Background()
+	q, version, err := QMPStart(ctx, socket, QMPConfig{Logger: qmpTestLogger{}}, disconnectedCh)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if version.Major != 7 || version.Minor != 2 {
+		t.Fatalf("Unexpected version %v", version)
+	}
+
+	if err := q.ExecuteQMPCapabilities(ctx); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := q.ExecuteDeviceDel(ctx, "virtio-drive0"); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := q.ExecuteBlockdevDel(ctx, "drive0"); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+
+	if err := replay.Close(); err != nil {
+		t.Fatalf("Unexpected replay error %v", err)
+	}
+}
+
+// Checks that a command that does not match the recording fails the
+// replay.
+func TestQMPReplayMismatch(t *testing.T) {
+	replay, socket := startQMPTestReplay(t, qmpTestRecording)
+
+	disconnectedCh := make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	q, _, err := QMPStart(ctx, socket, QMPConfig{Logger: qmpTestLogger{}}, disconnectedCh)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if err := q.ExecuteQMPCapabilities(ctx); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := q.ExecuteDeviceDel(ctx, "virtio-drive1"); err == nil {
+		t.Fatal("Expected an error executing a command out of the recording")
+	}
+	<-disconnectedCh
+
+	err = replay.Close()
+	if err == nil || !strings.Contains(err.Error(), "virtio-drive1") {
+		t.Fatalf("Unexpected replay error %v", err)
+	}
+}
+
+// Checks that a replay fails when sessions of the recording are not
+// replayed.
+func TestQMPReplayIncomplete(t *testing.T) {
+	replay, _ := startQMPTestReplay(t, qmpTestRecording)
+
+	if err := replay.Close(); err == nil {
+		t.Fatal("Expected an error closing an incomplete replay")
+	}
+}
+
+func TestLoadQMPRecordingInvalid(t *testing.T) {
+	for _, recording := range []string{
+		`{"direction":"sent","message":{"execute":"quit"}}`,
+		`{"direction":"unknown"}`,
+		`not json`,
+	} {
+		if _, err := LoadQMPRecording(writeQMPTestRecording(t, recording)); err == nil {
+			t.Errorf("Expected an error loading %s", recording)
+		}
+	}
+}
+
+func TestMatchQMPCommand(t *testing.T) {
+	for _, tc := range []struct {
+		recorded string
+		sent     string
+		match    bool
+	}{
+		{`{"execute":"quit"}`, `{"execute":"quit"}`, true},
+		{`{"execute":"quit"}`, `{"execute":"stop"}`, false},
+		{`{"execute":"blockdev-del","arguments":{"node-name":"*"}}`, `{"execute":"blockdev-del","arguments":{"node-name":"drive0"}}`, true},
+		{`{"execute":"blockdev-del","arguments":{"node-name":"*"}}`, `{"execute":"blockdev-del"}`, false},
+		{`{"execute":"balloon","arguments":{"value":1024}}`, `{"execute":"balloon","arguments":{"value":2048}}`, false},
+		{`{"execute":"x","arguments":{"fds":["a","*"]}}`, `{"execute":"x","arguments":{"fds":["a","b"]}}`, true},
+		{`{"execute":"x","arguments":{"fds":["a"]}}`, `{"execute":"x","arguments":{"fds":["a","b"]}}`, false},
+	} {
+		err := matchQMPCommand([]byte(tc.recorded), []byte(tc.sent))
+		if (err == nil) != tc.match {
+			t.Errorf("Unexpected match of %s against %s: %v", tc.sent, tc.recorded, err)
+		}
+	}
+}
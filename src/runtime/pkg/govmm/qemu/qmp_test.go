@@ -414,6 +414,172 @@ func TestQMPBlockdevAdd(t *testing.T) {
 	<-disconnectedCh
 }
 
+// Checks that a block device in a throttle group is added under a throttle
+// filter node.
+func TestQMPBlockdevAddWithThrottleGroup(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("blockdev-add", nil, "return", nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	q.version = checkVersion(t, connectedCh)
+	dev := BlockDevice{
+		ID:            fmt.Sprintf("drive_%s", volumeUUID),
+		File:          "/dev/rbd0",
+		ReadOnly:      true,
+		AIO:           Native,
+		ThrottleGroup: "throttle0",
+	}
+
+	args := q.blockdevAddBaseArgs("host_device", &dev)
+	if args["driver"] != "throttle" || args["throttle-group"] != "throttle0" ||
+		args["node-name"] != dev.ID || args["read-only"] != true {
+		t.Fatalf("Unexpected throttle node arguments %v", args)
+	}
+	file, ok := args["file"].(map[string]interface{})
+	if !ok || file["driver"] != "raw" || file["node-name"] != nil {
+		t.Fatalf("Unexpected raw node arguments %v", args["file"])
+	}
+
+	err := q.ExecuteBlockdevAdd(context.Background(), &dev)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the block node limited by a throttle group is named after
+// ThrottledNodeName.
+func TestQMPBlockdevAddThrottledNodeName(t *testing.T) {
+	q := &QMP{}
+	dev := BlockDevice{
+		ID:                "drive0",
+		File:              "/dev/rbd0",
+		AIO:               Native,
+		ThrottleGroup:     "throttle0",
+		ThrottledNodeName: "fmt-drive0",
+	}
+
+	args := q.blockdevAddBaseArgs("host_device", &dev)
+	file, ok := args["file"].(map[string]interface{})
+	if !ok || args["node-name"] != "drive0" || file["node-name"] != "fmt-drive0" {
+		t.Fatalf("Unexpected throttled node arguments %v", args)
+	}
+}
+
+// Checks that the throttle group commands are correctly sent.
+//
+// We start a QMPLoop, add a throttle group, update its limits, delete
+// it and stop the loop.
+func TestQMPThrottleGroup(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("object-add", nil, "return", nil)
+	buf.AddCommand("qom-set", nil, "return", nil)
+	buf.AddCommand("object-del", nil, "return", nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	q.version = checkVersion(t, connectedCh)
+
+	limits := ThrottleLimits{BpsTotal: 1 << 20, IopsTotal: 100}
+	expected := map[string]interface{}{
+		"bps-total":      int64(1 << 20),
+		"bps-total-max":  int64(0),
+		"iops-total":     int64(100),
+		"iops-total-max": int64(0),
+	}
+	if !reflect.DeepEqual(limits.qmpArgs(), expected) {
+		t.Fatalf("Unexpected limits %v, expected %v", limits.qmpArgs(), expected)
+	}
+
+	err := q.ExecuteThrottleGroupAdd(context.Background(), "throttle0", limits)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	limits.IopsTotal = 0
+	err = q.ExecuteThrottleGroupSet(context.Background(), "throttle0", limits)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	err = q.ExecuteObjectDel(context.Background(), "throttle0")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the block_set_io_throttle command is correctly sent.
+//
+// We start a QMPLoop, send the block_set_io_throttle command and stop the
+// loop.
+func TestQMPBlockSetIOThrottle(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("block_set_io_throttle", nil, "return", nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	q.version = checkVersion(t, connectedCh)
+	err := q.ExecuteBlockSetIOThrottle(context.Background(), "virtio-drive0", ThrottleLimits{IopsTotal: 100})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the block_resize command is correctly sent.
+//
+// We start a QMPLoop, send the block_resize command and stop the loop.
+func TestQMPBlockResize(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("block_resize", nil, "return", nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	q.version = checkVersion(t, connectedCh)
+	err := q.ExecuteBlockResize(context.Background(), fmt.Sprintf("drive_%s", volumeUUID), 1<<30)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the blockdev-add with a driver command is correctly sent.
+//
+// We start a QMPLoop, send the blockdev-add with a driver command and stop
+// the loop.
+//
+// The blockdev-add with a driver command should be correctly sent and the
+// QMP loop should exit gracefully.
+func TestQMPBlockdevAddWithDriver(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("blockdev-add", nil, "return", nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	q.version = checkVersion(t, connectedCh)
+	dev := BlockDevice{
+		ID:       fmt.Sprintf("drive_%s", volumeUUID),
+		File:     "/var/lib/volumes/disk.img",
+		ReadOnly: false,
+		AIO:      Threads,
+	}
+	err := q.ExecuteBlockdevAddWithDriver(context.Background(), "file", &dev)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
 // Checks that the blockdev-add with cache options command is correctly sent.
 //
 // We start a QMPLoop, send the blockdev-add with cache options
@@ -514,6 +680,82 @@ func TestQMPNetdevAddByFds(t *testing.T) {
 	<-disconnectedCh
 }
 
+// Checks that the netdev_add command for a vhost-vdpa device is correctly sent.
+//
+// We start a QMPLoop, send the netdev_add command and stop the loop.
+//
+// The netdev_add command should be correctly sent and the QMP loop should
+// exit gracefully.
+func TestQMPNetdevAddByVhostVDPA(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("netdev_add", map[string]interface{}{
+		"type":     "vhost-vdpa",
+		"id":       "vdpa0",
+		"vhostdev": "/dev/vhost-vdpa-0",
+	}, "return", nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	q.version = checkVersion(t, connectedCh)
+	err := q.ExecuteNetdevAddByVhostVDPA(context.Background(), "vdpa0", "/dev/vhost-vdpa-0")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the netdev_add command connecting a stream socket is correctly
+// sent.
+//
+// We start a QMPLoop, send the netdev_add command and stop the loop.
+//
+// The netdev_add command should be correctly sent and the QMP loop should
+// exit gracefully.
+func TestQMPNetdevAddByStream(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("netdev_add", map[string]interface{}{
+		"type":   "stream",
+		"id":     "passt0",
+		"server": false,
+		"addr": map[string]interface{}{
+			"type": "unix",
+			"path": "/run/user/1000/passt.socket",
+		},
+	}, "return", nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+	q.version = &QMPVersion{Major: 7, Minor: 2}
+	err := q.ExecuteNetdevAddByStream(context.Background(), "passt0", "/run/user/1000/passt.socket")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the netdev_add command of a stream netdev is not sent to a
+// QEMU older than 7.2.
+func TestQMPNetdevAddByStreamUnsupported(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+	q.version = &QMPVersion{Major: 7, Minor: 1}
+	err := q.ExecuteNetdevAddByStream(context.Background(), "passt0", "/run/user/1000/passt.socket")
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
 // Checks that the netdev_del command is correctly sent.
 //
 // We start a QMPLoop, send the netdev_del command and stop the loop.
@@ -1103,6 +1345,23 @@ func TestQMPPCIVFIOPCIeDeviceAdd(t *testing.T) {
 	<-disconnectedCh
 }
 
+func TestQMPPCIVhostVDPADeviceAdd(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("device_add", nil, "return", nil)
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+	devID := fmt.Sprintf("device_%s", volumeUUID)
+	err := q.ExecutePCIVhostVDPADeviceAdd(context.Background(), devID, "/dev/vhost-vdpa-0", "0x1", "rp0")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
 func TestQMPAPVFIOMediatedDeviceAdd(t *testing.T) {
 	connectedCh := make(chan *QMPVersion)
 	disconnectedCh := make(chan struct{})
@@ -1539,6 +1798,27 @@ func TestExecuteBalloon(t *testing.T) {
 	<-disconnectedCh
 }
 
+// Checks query-balloon
+func TestExecuteQueryBalloon(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("query-balloon", nil, "return", map[string]interface{}{"actual": 536870912})
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+	info, err := q.ExecuteQueryBalloon(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if info.Actual != 536870912 {
+		t.Fatalf("Unexpected balloon actual size %d", info.Actual)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
 func TestErrorDesc(t *testing.T) {
 	errDesc := "Somthing err messages"
 	errData := map[string]string{
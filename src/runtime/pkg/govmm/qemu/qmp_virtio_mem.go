@@ -0,0 +1,179 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// virtioMemType is the type of the virtio-mem devices reported by
+// query-memory-devices.
+const virtioMemType = "virtio-mem"
+
+// VirtioMemInfo is the status of a virtio-mem device, as reported by
+// query-memory-devices.
+// nolint: govet
+type VirtioMemInfo struct {
+	// ID is the device ID.
+	ID string `json:"id"`
+	// MemAddr is the guest physical address of the memory of the device.
+	MemAddr uint64 `json:"memaddr"`
+	// Node is the NUMA node of the memory.
+	Node int `json:"node"`
+	// RequestedSize is the memory in bytes the guest is requested to plug.
+	RequestedSize uint64 `json:"requested-size"`
+	// Size is the memory in bytes the guest plugged.
+	Size uint64 `json:"size"`
+	// MaxSize is the most memory in bytes the device can plug.
+	MaxSize uint64 `json:"max-size"`
+	// BlockSize is the granularity in bytes of the memory plugged.
+	BlockSize uint64 `json:"block-size"`
+	// MemDev is the QOM path of the memory backend of the device.
+	MemDev string `json:"memdev"`
+}
+
+// ExecuteVirtioMemDeviceAdd hotplugs the virtio-mem device dev, along with
+// its memory backend.
+func (q *QMP) ExecuteVirtioMemDeviceAdd(ctx context.Context, dev VirtioMemDevice) error {
+	if !dev.Valid() {
+		return fmt.Errorf("invalid virtio-mem device %s", dev.ID)
+	}
+
+	args := map[string]interface{}{
+		"qom-type": string(dev.memoryBackend()),
+		"id":       dev.MemDev,
+		"size":     dev.Size,
+	}
+	if dev.MemPath != "" {
+		args["mem-path"] = dev.MemPath
+	}
+	if dev.Share {
+		args["share"] = true
+	}
+
+	err := q.executeCommand(ctx, "object-add", args, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			q.cfg.Logger.Errorf("Unable to add virtio-mem device %s: %v", dev.ID, err)
+			if delErr := q.executeCommand(ctx, "object-del", map[string]interface{}{"id": dev.MemDev}, nil); delErr != nil {
+				q.cfg.Logger.Warningf("Unable to clean up memory object %s: %v", dev.MemDev, delErr)
+			}
+		}
+	}()
+
+	args = map[string]interface{}{
+		"driver":         dev.deviceName(nil),
+		"id":             dev.ID,
+		"memdev":         dev.MemDev,
+		"requested-size": dev.RequestedSize,
+	}
+	if dev.BlockSize != 0 {
+		args["block-size"] = dev.BlockSize
+	}
+	if dev.Bus != "" {
+		args["bus"] = dev.Bus
+	}
+	if dev.Addr != "" {
+		args["addr"] = dev.Addr
+	}
+	if dev.ROMFile != "" {
+		args["romfile"] = dev.ROMFile
+	}
+
+	err = q.executeCommand(ctx, "device_add", args, nil)
+
+	return err
+}
+
+// ExecuteVirtioMemSetRequestedSize requests the guest to resize the memory
+// plugged by the virtio-mem device devID to size bytes. The guest plugs or
+// unplugs the memory asynchronously, see WaitForVirtioMemSize.
+func (q *QMP) ExecuteVirtioMemSetRequestedSize(ctx context.Context, devID string, size uint64) error {
+	return q.ExecQomSet(ctx, devID, "requested-size", size)
+}
+
+// ExecuteQueryVirtioMem returns the status of the virtio-mem device devID.
+func (q *QMP) ExecuteQueryVirtioMem(ctx context.Context, devID string) (VirtioMemInfo, error) {
+	response, err := q.executeCommandWithResponse(ctx, "query-memory-devices", nil, nil, nil)
+	if err != nil {
+		return VirtioMemInfo{}, err
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return VirtioMemInfo{}, fmt.Errorf("unable to extract memory devices information: %v", err)
+	}
+
+	var devices []struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err = json.Unmarshal(data, &devices); err != nil {
+		return VirtioMemInfo{}, fmt.Errorf("unable to convert json to memory devices: %v", err)
+	}
+
+	for _, d := range devices {
+		if d.Type != virtioMemType {
+			continue
+		}
+
+		var info VirtioMemInfo
+		if err = json.Unmarshal(d.Data, &info); err != nil {
+			return VirtioMemInfo{}, fmt.Errorf("unable to convert json to virtio-mem information: %v", err)
+		}
+
+		if info.ID == devID {
+			return info, nil
+		}
+	}
+
+	return VirtioMemInfo{}, fmt.Errorf("virtio-mem device %s not found", devID)
+}
+
+// WaitForVirtioMemSize waits until the guest plugged size bytes of memory
+// of the virtio-mem device devID, until ctx is done. The status of the
+// device is polled every interval, and each time the guest resizes the
+// memory of the device.
+//
+// The guest may fail to unplug memory it is using, the status of the device
+// is returned along with the error of ctx in that case, for the caller to
+// know how much memory is actually plugged.
+func (q *QMP) WaitForVirtioMemSize(ctx context.Context, devID string, size uint64, interval time.Duration) (VirtioMemInfo, error) {
+	sub := q.SubscribeEvents(MemoryDeviceSizeChangeFilter(devID))
+	defer sub.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		info, err := q.ExecuteQueryVirtioMem(ctx, devID)
+		if err != nil {
+			return VirtioMemInfo{}, err
+		}
+
+		if info.Size == size {
+			return info, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return info, ctx.Err()
+		case _, ok := <-sub.Events():
+			if !ok {
+				return info, errors.New("QMP connection lost while waiting for virtio-mem device")
+			}
+		case <-ticker.C:
+		}
+	}
+}
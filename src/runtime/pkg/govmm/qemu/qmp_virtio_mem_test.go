@@ -0,0 +1,138 @@
+// Copyright contributors to the Virtual Machine Manager for Go project
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package qemu
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testVirtioMemDevices(size uint64) []interface{} {
+	return []interface{}{
+		map[string]interface{}{"type": "dimm", "data": map[string]interface{}{"id": "dimm0", "size": 1 << 30}},
+		map[string]interface{}{"type": "virtio-mem", "data": map[string]interface{}{
+			"id": "virtiomem0", "memaddr": 1 << 32, "node": 0, "requested-size": 1 << 30,
+			"size": size, "max-size": 4 << 30, "block-size": 2 << 20, "memdev": "/objects/virtiomem",
+		}},
+	}
+}
+
+// Checks that a virtio-mem device is hotplugged and resized.
+//
+// We start a QMPLoop, add a virtio-mem device, request it to plug 1GiB and
+// query its status.
+//
+// The status of the virtio-mem device should be returned, not the one of
+// the DIMM.
+func TestQMPVirtioMem(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("object-add", nil, "return", nil)
+	buf.AddCommand("device_add", nil, "return", nil)
+	buf.AddCommand("qom-set", nil, "return", nil)
+	buf.AddCommand("query-memory-devices", nil, "return", testVirtioMemDevices(512<<20))
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+	ctx := context.Background()
+
+	dev := VirtioMemDevice{
+		ID:        "virtiomem0",
+		MemDev:    "virtiomem",
+		Size:      4 << 30,
+		Transport: TransportPCI,
+	}
+	if err := q.ExecuteVirtioMemDeviceAdd(ctx, dev); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := q.ExecuteVirtioMemSetRequestedSize(ctx, "virtiomem0", 1<<30); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	info, err := q.ExecuteQueryVirtioMem(ctx, "virtiomem0")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if info.Size != 512<<20 || info.RequestedSize != 1<<30 || info.MaxSize != 4<<30 || info.BlockSize != 2<<20 {
+		t.Fatalf("Unexpected virtio-mem status %+v", info)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the status of an unknown virtio-mem device is an error.
+func TestQMPVirtioMemNotFound(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("query-memory-devices", nil, "return", testVirtioMemDevices(0))
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	if _, err := q.ExecuteQueryVirtioMem(context.Background(), "dimm0"); err == nil {
+		t.Fatal("Expected an error querying a DIMM as a virtio-mem device")
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the guest acknowledging a resize is waited for.
+//
+// We start a QMPLoop reporting the memory plugged by the guest growing
+// from 512MiB to 1GiB, with a MEMORY_DEVICE_SIZE_CHANGE event.
+//
+// WaitForVirtioMemSize should return once 1GiB is plugged.
+func TestQMPWaitForVirtioMemSize(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("query-memory-devices", nil, "return", testVirtioMemDevices(512<<20))
+	buf.AddCommand("query-memory-devices", nil, "return", testVirtioMemDevices(1<<30))
+	buf.AddEvent(EventMemoryDeviceSizeChange, 50*time.Millisecond,
+		map[string]interface{}{"id": "virtiomem0", "size": 1 << 30, "qom-path": "/machine/peripheral/virtiomem0"},
+		map[string]interface{}{"seconds": 1352167040730, "microseconds": 123456})
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	info, err := q.WaitForVirtioMemSize(context.Background(), "virtiomem0", 1<<30, time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if info.Size != 1<<30 {
+		t.Fatalf("Unexpected virtio-mem status %+v", info)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
+
+// Checks that the memory actually plugged is returned when the guest does
+// not reach the requested size in time.
+func TestQMPWaitForVirtioMemSizeTimeout(t *testing.T) {
+	connectedCh := make(chan *QMPVersion)
+	disconnectedCh := make(chan struct{})
+	buf := newQMPTestCommandBuffer(t)
+	buf.AddCommand("query-memory-devices", nil, "return", testVirtioMemDevices(512<<20))
+	cfg := QMPConfig{Logger: qmpTestLogger{}}
+	q := startQMPLoop(buf, cfg, connectedCh, disconnectedCh)
+	checkVersion(t, connectedCh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	info, err := q.WaitForVirtioMemSize(ctx, "virtiomem0", 0, time.Minute)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if info.Size != 512<<20 {
+		t.Fatalf("Unexpected virtio-mem status %+v", info)
+	}
+	q.Shutdown()
+	<-disconnectedCh
+}
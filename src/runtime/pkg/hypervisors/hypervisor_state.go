@@ -34,6 +34,8 @@ type HypervisorState struct {
 	UUID string
 	// clh sepcific: refer to 'virtcontainers/clh.go:CloudHypervisorState'
 	APISocket string
+	// acrn specific: refer to 'virtcontainers/acrn.go:AcrnState'
+	ApicID string
 
 	// Belows are qemu specific
 	// Refs: virtcontainers/qemu.go:QemuState
@@ -43,8 +45,11 @@ type HypervisorState struct {
 
 	HotpluggedMemory  int
 	VirtiofsDaemonPid int
+	SwtpmPid          int
 	Pid               int
 	PCIeRootPort      int
 
 	HotplugVFIOOnRootBus bool
+	// VirtioMem is nil in the states saved before it was recorded.
+	VirtioMem *bool
 }
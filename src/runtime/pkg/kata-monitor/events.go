@@ -0,0 +1,200 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	shim "github.com/kata-containers/kata-containers/src/runtime/pkg/containerd-shim-v2"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/utils/shimclient"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+)
+
+const (
+	// delay before reconnecting to the events stream of a sandbox
+	eventsRetryDelay = 5 * time.Second
+	// number of events buffered for each client of the node events stream
+	eventsChannelSize = 256
+	sseDataPrefix     = "data: "
+)
+
+// eventAggregator relays the events streamed by the shims of all the
+// sandboxes of the node to the kata-monitor clients.
+type eventAggregator struct {
+	subscribers map[chan vc.Event]struct{}
+	watched     map[string]context.CancelFunc
+	sync.Mutex
+}
+
+func newEventAggregator() *eventAggregator {
+	return &eventAggregator{
+		subscribers: make(map[chan vc.Event]struct{}),
+		watched:     make(map[string]context.CancelFunc),
+	}
+}
+
+// watch starts relaying the events of the sandbox sandboxID.
+func (ea *eventAggregator) watch(sandboxID string) {
+	ea.Lock()
+	defer ea.Unlock()
+
+	if _, ok := ea.watched[sandboxID]; ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ea.watched[sandboxID] = cancel
+
+	go ea.relay(ctx, sandboxID)
+}
+
+// unwatch stops relaying the events of the sandbox sandboxID.
+func (ea *eventAggregator) unwatch(sandboxID string) {
+	ea.Lock()
+	defer ea.Unlock()
+
+	if cancel, ok := ea.watched[sandboxID]; ok {
+		cancel()
+		delete(ea.watched, sandboxID)
+	}
+}
+
+// relay reads the events of a sandbox until ctx is cancelled, reconnecting
+// to the shim when the stream breaks.
+func (ea *eventAggregator) relay(ctx context.Context, sandboxID string) {
+	for {
+		err := ea.readEvents(ctx, sandboxID)
+		if ctx.Err() != nil {
+			return
+		}
+		monitorLog.WithError(err).WithField("sandbox", sandboxID).Debugf(
+			"sandbox events stream closed, retry in %s", eventsRetryDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryDelay):
+		}
+	}
+}
+
+func (ea *eventAggregator) readEvents(ctx context.Context, sandboxID string) error {
+	client, err := shimclient.BuildShimClient(sandboxID, defaultTimeout)
+	if err != nil {
+		return err
+	}
+	// the timeout only applies to connecting to the shim, the stream
+	// itself never ends while the sandbox is running.
+	client.Timeout = 0
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://shim"+shim.EventsUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return ea.relayStream(bufio.NewScanner(resp.Body))
+}
+
+// relayStream publishes the events read from a server-sent events stream.
+func (ea *eventAggregator) relayStream(scanner *bufio.Scanner) error {
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, sseDataPrefix) {
+			continue
+		}
+
+		var e vc.Event
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, sseDataPrefix)), &e); err != nil {
+			monitorLog.WithError(err).Warn("failed to decode sandbox event")
+			continue
+		}
+		ea.publish(e)
+	}
+
+	return scanner.Err()
+}
+
+func (ea *eventAggregator) publish(e vc.Event) {
+	ea.Lock()
+	defer ea.Unlock()
+
+	for ch := range ea.subscribers {
+		select {
+		case ch <- e:
+		default:
+			monitorLog.WithField("sandbox", e.SandboxID).Warn("events client is too slow, dropping event")
+		}
+	}
+}
+
+func (ea *eventAggregator) subscribe() (<-chan vc.Event, func()) {
+	ch := make(chan vc.Event, eventsChannelSize)
+
+	ea.Lock()
+	defer ea.Unlock()
+
+	ea.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		ea.Lock()
+		defer ea.Unlock()
+
+		delete(ea.subscribers, ch)
+	}
+}
+
+// ServeEvents streams the events of all the sandboxes of the node, or of
+// a single one if the sandbox parameter is set, as server-sent events.
+func (km *KataMonitor) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		commonServeError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	sandboxID := r.URL.Query().Get("sandbox")
+
+	events, unsubscribe := km.events.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", shim.EventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-events:
+			if sandboxID != "" && e.SandboxID != sandboxID {
+				continue
+			}
+			if err := shim.WriteEvent(w, e); err != nil {
+				monitorLog.WithError(err).Debug("failed to write event")
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventAggregatorRelayStream(t *testing.T) {
+	assert := assert.New(t)
+
+	ea := newEventAggregator()
+	events, unsubscribe := ea.subscribe()
+	defer unsubscribe()
+
+	stream := "event: guest-oom\n" +
+		"data: {\"type\":\"guest-oom\",\"sandbox\":\"sb1\",\"container\":\"c1\"}\n" +
+		"\n" +
+		"data: not json\n" +
+		"\n" +
+		"event: sandbox-stopped\n" +
+		"data: {\"type\":\"sandbox-stopped\",\"sandbox\":\"sb2\"}\n" +
+		"\n"
+
+	err := ea.relayStream(bufio.NewScanner(strings.NewReader(stream)))
+	assert.NoError(err)
+
+	assert.Len(events, 2)
+	e := <-events
+	assert.Equal(vc.EventGuestOOM, e.Type)
+	assert.Equal("sb1", e.SandboxID)
+	assert.Equal("c1", e.ContainerID)
+	e = <-events
+	assert.Equal(vc.EventSandboxStopped, e.Type)
+	assert.Equal("sb2", e.SandboxID)
+}
+
+func TestEventAggregatorWatch(t *testing.T) {
+	assert := assert.New(t)
+
+	ea := newEventAggregator()
+
+	ea.watch("sb1")
+	ea.watch("sb1")
+	assert.Len(ea.watched, 1)
+
+	ea.unwatch("sb1")
+	ea.unwatch("sb2")
+	assert.Len(ea.watched, 0)
+}
@@ -39,6 +39,7 @@ func SetLogger(logger *logrus.Entry) {
 // KataMonitor is monitor agent
 type KataMonitor struct {
 	sandboxCache    *sandboxCache
+	events          *eventAggregator
 	runtimeEndpoint string
 }
 
@@ -58,6 +59,7 @@ func NewKataMonitor(runtimeEndpoint string) (*KataMonitor, error) {
 			Mutex:     &sync.Mutex{},
 			sandboxes: make(map[string]sandboxCRIMetadata),
 		},
+		events: newEventAggregator(),
 	}
 
 	// register metrics
@@ -109,6 +111,7 @@ func (km *KataMonitor) startPodCacheUpdater() {
 	}
 	for _, sandbox := range sandboxList {
 		km.sandboxCache.putIfNotExists(sandbox, sandboxCRIMetadata{})
+		km.events.watch(sandbox)
 	}
 
 	monitorLog.Debug("initial sync of sbs directory completed")
@@ -135,6 +138,7 @@ func (km *KataMonitor) startPodCacheUpdater() {
 						"CREATE event but pod already present in the sandbox cache")
 				}
 				sandboxList = append(sandboxList, id)
+				km.events.watch(id)
 				monitorLog.WithField("pod", id).Info("sandbox cache: added pod")
 				if !cacheUpdateTimerIsSet {
 					cacheUpdateTimer.Reset(podCacheRefreshDelaySeconds * time.Second)
@@ -151,6 +155,7 @@ func (km *KataMonitor) startPodCacheUpdater() {
 						"REMOVE event but pod was missing from the sandbox cache")
 				}
 				sandboxList = removeFromSandboxList(sandboxList, id)
+				km.events.unwatch(id)
 				monitorLog.WithField("pod", id).Info("sandbox cache: removed pod")
 			}
 
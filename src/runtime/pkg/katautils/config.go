@@ -71,10 +71,15 @@ type image struct {
 }
 
 type factory struct {
-	TemplatePath    string `toml:"template_path"`
-	VMCacheEndpoint string `toml:"vm_cache_endpoint"`
-	VMCacheNumber   uint   `toml:"vm_cache_number"`
-	Template        bool   `toml:"enable_template"`
+	TemplatePath        string `toml:"template_path"`
+	TemplateStorePath   string `toml:"template_store_path"`
+	VMCacheEndpoint     string `toml:"vm_cache_endpoint"`
+	VMCacheMemoryBudget uint64 `toml:"vm_cache_memory_budget"`
+	VMCacheNumber       uint   `toml:"vm_cache_number"`
+	VMCacheMaxNumber    uint   `toml:"vm_cache_max_number"`
+	VMCacheMaxPools     uint   `toml:"vm_cache_max_pools"`
+	VMCacheIdleTimeout  uint   `toml:"vm_cache_idle_timeout"`
+	Template            bool   `toml:"enable_template"`
 }
 
 type hypervisor struct {
@@ -95,11 +100,14 @@ type hypervisor struct {
 	SharedFS                       string   `toml:"shared_fs"`
 	VirtioFSDaemon                 string   `toml:"virtio_fs_daemon"`
 	VirtioFSCache                  string   `toml:"virtio_fs_cache"`
+	SwtpmPath                      string   `toml:"swtpm_path"`
+	AssetTrustStore                string   `toml:"asset_trust_store"`
 	VhostUserStorePath             string   `toml:"vhost_user_store_path"`
 	FileBackedMemRootDir           string   `toml:"file_mem_backend"`
 	GuestHookPath                  string   `toml:"guest_hook_path"`
 	GuestMemoryDumpPath            string   `toml:"guest_memory_dump_path"`
 	SeccompSandbox                 string   `toml:"seccompsandbox"`
+	QMPRecordPath                  string   `toml:"qmp_record_path"`
 	GuestPreAttestationProxy       string   `toml:"guest_pre_attestation_proxy"`
 	GuestPreAttestationKeyset      string   `toml:"guest_pre_attestation_keyset"`
 	GuestPreAttestationSecretGuid  string   `toml:"guest_pre_attestation_secret_guid"`
@@ -137,6 +145,8 @@ type hypervisor struct {
 	DefaultBridges                 uint32   `toml:"default_bridges"`
 	Msize9p                        uint32   `toml:"msize_9p"`
 	PCIeRootPort                   uint32   `toml:"pcie_root_port"`
+	FreezeMemory                   uint32   `toml:"freeze_memory"`
+	DrivePoolSize                  uint32   `toml:"drive_pool_size"`
 	GuestPreAttestationGRPCTimeout uint32   `toml:"guest_pre_attestation_grpc_timeout"`
 	SEVGuestPolicy                 uint32   `toml:"sev_guest_policy"`
 	RemoteHypervisorTimeout        uint32   `toml:"remote_hypervisor_timeout"`
@@ -168,6 +178,9 @@ type hypervisor struct {
 	LegacySerial                   bool     `toml:"use_legacy_serial"`
 	GuestPreAttestation            bool     `toml:"guest_pre_attestation"`
 	EnableVCPUsPinning             bool     `toml:"enable_vcpus_pinning"`
+	EnableNUMA                     bool     `toml:"enable_numa"`
+	EnableVTPM                     bool     `toml:"enable_vtpm"`
+	EnableJSONSyntax               bool     `toml:"enable_json_syntax"`
 }
 
 type runtime struct {
@@ -177,8 +190,13 @@ type runtime struct {
 	JaegerPassword            string   `toml:"jaeger_password"`
 	VfioMode                  string   `toml:"vfio_mode"`
 	GuestSeLinuxLabel         string   `toml:"guest_selinux_label"`
+	PasstPath                 string   `toml:"passt_path"`
 	SandboxBindMounts         []string `toml:"sandbox_bind_mounts"`
 	Experimental              []string `toml:"experimental"`
+	GuestHookPathList         []string `toml:"valid_guest_hook_paths"`
+	GuestHookDeliveryPolicy   string   `toml:"guest_hook_delivery_failure_policy"`
+	GuestHookTimeout          int      `toml:"guest_hook_timeout"`
+	GuestOCIHooks             bool     `toml:"guest_oci_hooks"`
 	Tracing                   bool     `toml:"enable_tracing"`
 	DisableNewNetNs           bool     `toml:"disable_new_netns"`
 	DisableGuestSeccomp       bool     `toml:"disable_guest_seccomp"`
@@ -469,6 +487,14 @@ func (h hypervisor) defaultVirtioFSCache() string {
 	return h.VirtioFSCache
 }
 
+func (h hypervisor) drivePoolSize() (uint32, error) {
+	if h.DrivePoolSize > vc.MaxDrivePoolSize {
+		return 0, fmt.Errorf("Invalid drive pool size %d, the maximum is %d", h.DrivePoolSize, vc.MaxDrivePoolSize)
+	}
+
+	return h.DrivePoolSize, nil
+}
+
 func (h hypervisor) blockDeviceDriver() (string, error) {
 	supportedBlockDrivers := []string{config.VirtioSCSI, config.VirtioBlock, config.VirtioMmio, config.Nvdimm, config.VirtioBlockCCW}
 
@@ -670,6 +696,11 @@ func newFirecrackerHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		return vc.HypervisorConfig{}, err
 	}
 
+	drivePoolSize, err := h.drivePoolSize()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
 	rxRateLimiterMaxRate := h.getRxRateLimiterCfg()
 	txRateLimiterMaxRate := h.getTxRateLimiterCfg()
 
@@ -699,11 +730,13 @@ func newFirecrackerHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		EnableIOThreads:       h.EnableIOThreads,
 		DisableVhostNet:       true, // vhost-net backend is not supported in Firecracker
 		GuestHookPath:         h.guestHookPath(),
+		AssetTrustStore:       h.AssetTrustStore,
 		RxRateLimiterMaxRate:  rxRateLimiterMaxRate,
 		TxRateLimiterMaxRate:  txRateLimiterMaxRate,
 		EnableAnnotations:     h.EnableAnnotations,
 		DisableSeLinux:        h.DisableSeLinux,
 		DisableGuestSeLinux:   true, // Guest SELinux is not supported in Firecracker
+		DrivePoolSize:         drivePoolSize,
 	}, nil
 }
 
@@ -784,80 +817,91 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 	txRateLimiterMaxRate := h.getTxRateLimiterCfg()
 
 	return vc.HypervisorConfig{
-		HypervisorPath:                hypervisor,
-		HypervisorPathList:            h.HypervisorPathList,
-		KernelPath:                    kernel,
-		InitrdPath:                    initrd,
-		ImagePath:                     image,
-		FirmwarePath:                  firmware,
-		FirmwareVolumePath:            firmwareVolume,
-		PFlash:                        pflashes,
-		MachineAccelerators:           machineAccelerators,
-		CPUFeatures:                   cpuFeatures,
-		KernelParams:                  vc.DeserializeParams(strings.Fields(kernelParams)),
-		HypervisorMachineType:         machineType,
-		NumVCPUs:                      h.defaultVCPUs(),
-		DefaultMaxVCPUs:               h.defaultMaxVCPUs(),
-		MemorySize:                    h.defaultMemSz(),
-		MemSlots:                      h.defaultMemSlots(),
-		MemOffset:                     h.defaultMemOffset(),
-		DefaultMaxMemorySize:          h.defaultMaxMemSz(),
-		VirtioMem:                     h.VirtioMem,
-		EntropySource:                 h.GetEntropySource(),
-		EntropySourceList:             h.EntropySourceList,
-		DefaultBridges:                h.defaultBridges(),
-		DisableBlockDeviceUse:         h.DisableBlockDeviceUse,
-		SharedFS:                      sharedFS,
-		VirtioFSDaemon:                h.VirtioFSDaemon,
-		VirtioFSDaemonList:            h.VirtioFSDaemonList,
-		VirtioFSCacheSize:             h.VirtioFSCacheSize,
-		VirtioFSCache:                 h.defaultVirtioFSCache(),
-		VirtioFSQueueSize:             h.VirtioFSQueueSize,
-		VirtioFSExtraArgs:             h.VirtioFSExtraArgs,
-		MemPrealloc:                   h.MemPrealloc,
-		HugePages:                     h.HugePages,
-		IOMMU:                         h.IOMMU,
-		IOMMUPlatform:                 h.getIOMMUPlatform(),
-		FileBackedMemRootDir:          h.FileBackedMemRootDir,
-		FileBackedMemRootList:         h.FileBackedMemRootList,
-		Debug:                         h.Debug,
-		DisableNestingChecks:          h.DisableNestingChecks,
-		BlockDeviceDriver:             blockDriver,
-		BlockDeviceAIO:                blockAIO,
-		BlockDeviceCacheSet:           h.BlockDeviceCacheSet,
-		BlockDeviceCacheDirect:        h.BlockDeviceCacheDirect,
-		BlockDeviceCacheNoflush:       h.BlockDeviceCacheNoflush,
-		EnableIOThreads:               h.EnableIOThreads,
-		Msize9p:                       h.msize9p(),
-		DisableImageNvdimm:            h.DisableImageNvdimm,
-		HotplugVFIOOnRootBus:          h.HotplugVFIOOnRootBus,
-		PCIeRootPort:                  h.PCIeRootPort,
-		DisableVhostNet:               h.DisableVhostNet,
-		EnableVhostUserStore:          h.EnableVhostUserStore,
-		VhostUserStorePath:            h.vhostUserStorePath(),
-		VhostUserStorePathList:        h.VhostUserStorePathList,
-		SeccompSandbox:                h.SeccompSandbox,
-		GuestHookPath:                 h.guestHookPath(),
-		RxRateLimiterMaxRate:          rxRateLimiterMaxRate,
-		TxRateLimiterMaxRate:          txRateLimiterMaxRate,
-		EnableAnnotations:             h.EnableAnnotations,
-		GuestMemoryDumpPath:           h.GuestMemoryDumpPath,
-		GuestMemoryDumpPaging:         h.GuestMemoryDumpPaging,
-		ConfidentialGuest:             h.ConfidentialGuest,
-		SevSnpGuest:                   h.SevSnpGuest,
-		GuestSwap:                     h.GuestSwap,
-		Rootless:                      h.Rootless,
-		LegacySerial:                  h.LegacySerial,
-		DisableSeLinux:                h.DisableSeLinux,
-		GuestPreAttestation:           h.GuestPreAttestation,
-		GuestPreAttestationProxy:      h.GuestPreAttestationProxy,
-		GuestPreAttestationKeyset:     h.GuestPreAttestationKeyset,
-		GuestPreAttestationSecretGuid: h.GuestPreAttestationSecretGuid,
-		GuestPreAttestationSecretType: h.GuestPreAttestationSecretType,
-		SEVGuestPolicy:                h.SEVGuestPolicy,
-		SEVCertChainPath:              h.SEVCertChainPath,
-		EnableVCPUsPinning:            h.EnableVCPUsPinning,
-		DisableGuestSeLinux:           h.DisableGuestSeLinux,
+		HypervisorPath:                 hypervisor,
+		HypervisorPathList:             h.HypervisorPathList,
+		KernelPath:                     kernel,
+		InitrdPath:                     initrd,
+		ImagePath:                      image,
+		FirmwarePath:                   firmware,
+		FirmwareVolumePath:             firmwareVolume,
+		PFlash:                         pflashes,
+		MachineAccelerators:            machineAccelerators,
+		CPUFeatures:                    cpuFeatures,
+		KernelParams:                   vc.DeserializeParams(strings.Fields(kernelParams)),
+		HypervisorMachineType:          machineType,
+		NumVCPUs:                       h.defaultVCPUs(),
+		DefaultMaxVCPUs:                h.defaultMaxVCPUs(),
+		MemorySize:                     h.defaultMemSz(),
+		MemSlots:                       h.defaultMemSlots(),
+		MemOffset:                      h.defaultMemOffset(),
+		DefaultMaxMemorySize:           h.defaultMaxMemSz(),
+		VirtioMem:                      h.VirtioMem,
+		EntropySource:                  h.GetEntropySource(),
+		EntropySourceList:              h.EntropySourceList,
+		DefaultBridges:                 h.defaultBridges(),
+		DisableBlockDeviceUse:          h.DisableBlockDeviceUse,
+		SharedFS:                       sharedFS,
+		VirtioFSDaemon:                 h.VirtioFSDaemon,
+		VirtioFSDaemonList:             h.VirtioFSDaemonList,
+		VirtioFSCacheSize:              h.VirtioFSCacheSize,
+		VirtioFSCache:                  h.defaultVirtioFSCache(),
+		VirtioFSQueueSize:              h.VirtioFSQueueSize,
+		VirtioFSExtraArgs:              h.VirtioFSExtraArgs,
+		MemPrealloc:                    h.MemPrealloc,
+		HugePages:                      h.HugePages,
+		IOMMU:                          h.IOMMU,
+		IOMMUPlatform:                  h.getIOMMUPlatform(),
+		FileBackedMemRootDir:           h.FileBackedMemRootDir,
+		FileBackedMemRootList:          h.FileBackedMemRootList,
+		Debug:                          h.Debug,
+		DisableNestingChecks:           h.DisableNestingChecks,
+		BlockDeviceDriver:              blockDriver,
+		BlockDeviceAIO:                 blockAIO,
+		BlockDeviceCacheSet:            h.BlockDeviceCacheSet,
+		BlockDeviceCacheDirect:         h.BlockDeviceCacheDirect,
+		BlockDeviceCacheNoflush:        h.BlockDeviceCacheNoflush,
+		EnableIOThreads:                h.EnableIOThreads,
+		Msize9p:                        h.msize9p(),
+		DisableImageNvdimm:             h.DisableImageNvdimm,
+		HotplugVFIOOnRootBus:           h.HotplugVFIOOnRootBus,
+		PCIeRootPort:                   h.PCIeRootPort,
+		FreezeMemory:                   h.FreezeMemory,
+		DisableVhostNet:                h.DisableVhostNet,
+		EnableVhostUserStore:           h.EnableVhostUserStore,
+		VhostUserStorePath:             h.vhostUserStorePath(),
+		VhostUserStorePathList:         h.VhostUserStorePathList,
+		SeccompSandbox:                 h.SeccompSandbox,
+		QMPRecordPath:                  h.QMPRecordPath,
+		GuestHookPath:                  h.guestHookPath(),
+		AssetTrustStore:                h.AssetTrustStore,
+		RxRateLimiterMaxRate:           rxRateLimiterMaxRate,
+		TxRateLimiterMaxRate:           txRateLimiterMaxRate,
+		DiskRateLimiterBwMaxRate:       h.getDiskRateLimiterBwMaxRate(),
+		DiskRateLimiterBwOneTimeBurst:  h.getDiskRateLimiterBwOneTimeBurst(),
+		DiskRateLimiterOpsMaxRate:      h.getDiskRateLimiterOpsMaxRate(),
+		DiskRateLimiterOpsOneTimeBurst: h.getDiskRateLimiterOpsOneTimeBurst(),
+		EnableAnnotations:              h.EnableAnnotations,
+		GuestMemoryDumpPath:            h.GuestMemoryDumpPath,
+		GuestMemoryDumpPaging:          h.GuestMemoryDumpPaging,
+		ConfidentialGuest:              h.ConfidentialGuest,
+		SevSnpGuest:                    h.SevSnpGuest,
+		GuestSwap:                      h.GuestSwap,
+		Rootless:                       h.Rootless,
+		LegacySerial:                   h.LegacySerial,
+		DisableSeLinux:                 h.DisableSeLinux,
+		GuestPreAttestation:            h.GuestPreAttestation,
+		GuestPreAttestationProxy:       h.GuestPreAttestationProxy,
+		GuestPreAttestationKeyset:      h.GuestPreAttestationKeyset,
+		GuestPreAttestationSecretGuid:  h.GuestPreAttestationSecretGuid,
+		GuestPreAttestationSecretType:  h.GuestPreAttestationSecretType,
+		SEVGuestPolicy:                 h.SEVGuestPolicy,
+		SEVCertChainPath:               h.SEVCertChainPath,
+		EnableVCPUsPinning:             h.EnableVCPUsPinning,
+		EnableNUMA:                     h.EnableNUMA,
+		EnableVTPM:                     h.EnableVTPM,
+		SwtpmPath:                      h.SwtpmPath,
+		DisableGuestSeLinux:            h.DisableGuestSeLinux,
+		EnableJSONSyntax:               h.EnableJSONSyntax,
 	}, nil
 }
 
@@ -922,6 +966,7 @@ func newAcrnHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		BlockDeviceDriver:     blockDriver,
 		DisableVhostNet:       h.DisableVhostNet,
 		GuestHookPath:         h.guestHookPath(),
+		AssetTrustStore:       h.AssetTrustStore,
 		DisableSeLinux:        h.DisableSeLinux,
 		EnableAnnotations:     h.EnableAnnotations,
 		DisableGuestSeLinux:   true, // Guest SELinux is not supported in ACRN
@@ -1024,6 +1069,7 @@ func newClhHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		PCIeRootPort:                   h.PCIeRootPort,
 		DisableVhostNet:                true,
 		GuestHookPath:                  h.guestHookPath(),
+		AssetTrustStore:                h.AssetTrustStore,
 		VirtioFSExtraArgs:              h.VirtioFSExtraArgs,
 		SGXEPCSize:                     defaultSGXEPCSize,
 		EnableAnnotations:              h.EnableAnnotations,
@@ -1039,6 +1085,9 @@ func newClhHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		DiskRateLimiterBwOneTimeBurst:  h.getDiskRateLimiterBwOneTimeBurst(),
 		DiskRateLimiterOpsMaxRate:      h.getDiskRateLimiterOpsMaxRate(),
 		DiskRateLimiterOpsOneTimeBurst: h.getDiskRateLimiterOpsOneTimeBurst(),
+		EnableNUMA:                     h.EnableNUMA,
+		EnableVTPM:                     h.EnableVTPM,
+		SwtpmPath:                      h.SwtpmPath,
 	}, nil
 }
 
@@ -1063,6 +1112,7 @@ func newDragonballHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		MemSlots:        h.defaultMemSlots(),
 		EntropySource:   h.GetEntropySource(),
 		Debug:           h.Debug,
+		AssetTrustStore: h.AssetTrustStore,
 	}, nil
 }
 
@@ -1086,10 +1136,15 @@ func newFactoryConfig(f factory) (oci.FactoryConfig, error) {
 		f.VMCacheEndpoint = defaultVMCacheEndpoint
 	}
 	return oci.FactoryConfig{
-		Template:        f.Template,
-		TemplatePath:    f.TemplatePath,
-		VMCacheNumber:   f.VMCacheNumber,
-		VMCacheEndpoint: f.VMCacheEndpoint,
+		Template:            f.Template,
+		TemplatePath:        f.TemplatePath,
+		TemplateStorePath:   f.TemplateStorePath,
+		VMCacheNumber:       f.VMCacheNumber,
+		VMCacheMaxNumber:    f.VMCacheMaxNumber,
+		VMCacheMaxPools:     f.VMCacheMaxPools,
+		VMCacheMemoryBudget: f.VMCacheMemoryBudget,
+		VMCacheIdleTimeout:  f.VMCacheIdleTimeout,
+		VMCacheEndpoint:     f.VMCacheEndpoint,
 	}, nil
 }
 
@@ -1338,6 +1393,8 @@ func LoadConfiguration(configPath string, ignoreLogging bool) (resolvedConfigPat
 		}
 	}
 
+	config.PasstPath = tomlConf.Runtime.PasstPath
+
 	if tomlConf.Runtime.VfioMode != "" {
 		err = config.VfioMode.VFIOSetMode(tomlConf.Runtime.VfioMode)
 
@@ -1388,6 +1445,18 @@ func LoadConfiguration(configPath string, ignoreLogging bool) (resolvedConfigPat
 
 	config.DisableGuestEmptyDir = tomlConf.Runtime.DisableGuestEmptyDir
 
+	if tomlConf.Runtime.GuestHookTimeout < 0 {
+		return "", config, fmt.Errorf("Invalid guest hook timeout %d", tomlConf.Runtime.GuestHookTimeout)
+	}
+	config.GuestHooks.Timeout = tomlConf.Runtime.GuestHookTimeout
+	if tomlConf.Runtime.GuestHookDeliveryPolicy != "" {
+		if err := config.GuestHooks.DeliveryFailurePolicy.Set(tomlConf.Runtime.GuestHookDeliveryPolicy); err != nil {
+			return "", config, err
+		}
+	}
+	config.GuestHooks.OCIHooks = tomlConf.Runtime.GuestOCIHooks
+	config.GuestHookPathList = tomlConf.Runtime.GuestHookPathList
+
 	if err := checkConfig(config); err != nil {
 		return "", config, err
 	}
@@ -1427,14 +1496,9 @@ func decodeConfig(configPath string) (tomlConfig, string, error) {
 		err      error
 	)
 
-	if configPath == "" {
-		resolved, err = getDefaultConfigFile()
-	} else {
-		resolved, err = ResolvePath(configPath)
-	}
-
+	resolved, err = resolveConfigPath(configPath)
 	if err != nil {
-		return tomlConf, "", fmt.Errorf("Cannot find usable config file (%v)", err)
+		return tomlConf, "", err
 	}
 
 	configData, err := os.ReadFile(resolved)
@@ -1455,22 +1519,57 @@ func decodeConfig(configPath string) (tomlConfig, string, error) {
 	return tomlConf, resolved, nil
 }
 
-func decodeDropIns(mainConfigPath string, tomlConf *tomlConfig) error {
+// resolveConfigPath returns the resolved path of the configuration file,
+// the default one if configPath is empty.
+func resolveConfigPath(configPath string) (string, error) {
+	var (
+		resolved string
+		err      error
+	)
+
+	if configPath == "" {
+		resolved, err = getDefaultConfigFile()
+	} else {
+		resolved, err = ResolvePath(configPath)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("Cannot find usable config file (%v)", err)
+	}
+
+	return resolved, nil
+}
+
+// dropInFiles returns the paths of the drop-in files of the configuration
+// file, in the order they are applied.
+func dropInFiles(mainConfigPath string) ([]string, error) {
 	configDir := filepath.Dir(mainConfigPath)
 	dropInDir := filepath.Join(configDir, "config.d")
 
 	files, err := os.ReadDir(dropInDir)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return fmt.Errorf("error reading %q directory: %s", dropInDir, err)
+			return nil, fmt.Errorf("error reading %q directory: %s", dropInDir, err)
 		} else {
-			return nil
+			return nil, nil
 		}
 	}
 
+	var paths []string
 	for _, file := range files {
-		dropInFpath := filepath.Join(dropInDir, file.Name())
+		paths = append(paths, filepath.Join(dropInDir, file.Name()))
+	}
+
+	return paths, nil
+}
+
+func decodeDropIns(mainConfigPath string, tomlConf *tomlConfig) error {
+	files, err := dropInFiles(mainConfigPath)
+	if err != nil {
+		return err
+	}
 
+	for _, dropInFpath := range files {
 		err = updateFromDropIn(dropInFpath, tomlConf)
 		if err != nil {
 			return err
@@ -1661,6 +1760,10 @@ func checkFactoryConfig(config oci.RuntimeConfig) error {
 		if config.HypervisorType != vc.QemuHypervisor {
 			return errors.New("VM cache just support qemu")
 		}
+
+		if config.FactoryConfig.VMCacheMaxNumber > 0 && config.FactoryConfig.VMCacheMaxNumber < config.FactoryConfig.VMCacheNumber {
+			return errors.New("Factory option vm_cache_max_number must not be lower than vm_cache_number")
+		}
 	}
 
 	return nil
@@ -621,6 +621,7 @@ func TestNewQemuHypervisorConfig(t *testing.T) {
 		SharedFS:              "virtio-fs",
 		VirtioFSDaemon:        filepath.Join(dir, "virtiofsd"),
 		BlockDeviceAIO:        blockDeviceAIO,
+		QMPRecordPath:         filepath.Join(dir, "qmp.jsonl"),
 	}
 
 	files := []string{hypervisorPath, kernelPath, imagePath}
@@ -658,6 +659,10 @@ func TestNewQemuHypervisorConfig(t *testing.T) {
 		t.Errorf("Expected image path %v, got %v", hypervisor.Image, config.ImagePath)
 	}
 
+	if config.QMPRecordPath != hypervisor.QMPRecordPath {
+		t.Errorf("Expected QMP record path %v, got %v", hypervisor.QMPRecordPath, config.QMPRecordPath)
+	}
+
 	if config.DisableBlockDeviceUse != disableBlock {
 		t.Errorf("Expected value for disable block usage %v, got %v", disableBlock, config.DisableBlockDeviceUse)
 	}
@@ -1103,6 +1108,19 @@ func TestHypervisorDefaultsImage(t *testing.T) {
 	assert.Equal(p, "")
 }
 
+func TestHypervisorDefaultsDrivePoolSize(t *testing.T) {
+	assert := assert.New(t)
+
+	h := hypervisor{DrivePoolSize: 16}
+	size, err := h.drivePoolSize()
+	assert.NoError(err)
+	assert.Equal(uint32(16), size)
+
+	h.DrivePoolSize = vc.MaxDrivePoolSize + 1
+	_, err = h.drivePoolSize()
+	assert.Error(err)
+}
+
 func TestHypervisorDefaultsGuestHookPath(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1296,7 +1314,7 @@ func TestDefaultFirmwareVolume(t *testing.T) {
 	assert.NotEmpty(p)
 
 	// restore default firmware volume path
-	defaultFirmwarePath = oldDefaultFirmwareVolumePath
+	defaultFirmwareVolumePath = oldDefaultFirmwareVolumePath
 }
 
 func TestDefaultMachineAccelerators(t *testing.T) {
@@ -0,0 +1,522 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katautils
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/oci"
+	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+)
+
+// AnnotationOrigin is the origin of the keys set by annotations.
+const AnnotationOrigin = "annotation"
+
+// hypervisorAssetKeys are the hypervisor keys holding a path which must exist.
+var hypervisorAssetKeys = []string{
+	"path",
+	"jailer_path",
+	"ctlpath",
+	"kernel",
+	"initrd",
+	"image",
+	"firmware",
+	"firmware_volume",
+	"virtio_fs_daemon",
+	"asset_trust_store",
+}
+
+var hypervisorTableTypes = []string{
+	firecrackerHypervisorTableType,
+	clhHypervisorTableType,
+	qemuHypervisorTableType,
+	acrnHypervisorTableType,
+	dragonballHypervisorTableType,
+	remoteHypervisorTableType,
+}
+
+// ConfigIssue is a problem found in a configuration file.
+type ConfigIssue struct {
+	// File is the configuration file the issue was found in, if any.
+	File string `json:"file,omitempty"`
+
+	// Key is the TOML key the issue is about, if any.
+	Key string `json:"key,omitempty"`
+
+	Message string `json:"message"`
+
+	// Warning is true if the issue does not prevent the runtime from
+	// using the configuration.
+	Warning bool `json:"warning,omitempty"`
+}
+
+func (i ConfigIssue) String() string {
+	severity := "error"
+	if i.Warning {
+		severity = "warning"
+	}
+
+	location := i.File
+	if i.Key != "" {
+		if location != "" {
+			location += ": "
+		}
+		location += i.Key
+	}
+	if location != "" {
+		return fmt.Sprintf("%s: %s: %s", severity, location, i.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", severity, i.Message)
+}
+
+// ValidateConfiguration checks the configuration file at configPath, the
+// default one if empty, and its drop-in files for unknown keys, values of
+// the wrong type, nonexistent asset paths and conflicting options. It
+// returns the resolved path of the configuration file and the issues found.
+// An error is only returned if the configuration files cannot be read.
+func ValidateConfiguration(configPath string) (string, []ConfigIssue, error) {
+	resolved, err := resolveConfigPath(configPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dropIns, err := dropInFiles(resolved)
+	if err != nil {
+		return resolved, nil, err
+	}
+
+	var issues []ConfigIssue
+	for _, file := range append([]string{resolved}, dropIns...) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return resolved, nil, err
+		}
+
+		var raw map[string]interface{}
+		if _, err := toml.Decode(string(data), &raw); err != nil {
+			issues = append(issues, ConfigIssue{File: file, Message: err.Error()})
+			continue
+		}
+
+		issues = append(issues, checkTomlKeys(file, nil, raw, reflect.TypeOf(tomlConfig{}))...)
+	}
+
+	if hasErrors(issues) {
+		// the configuration cannot be decoded
+		return resolved, issues, nil
+	}
+
+	tomlConf, _, err := decodeConfig(resolved)
+	if err != nil {
+		return resolved, append(issues, ConfigIssue{File: resolved, Message: err.Error()}), nil
+	}
+
+	issues = append(issues, checkTomlConfig(tomlConf)...)
+
+	// the remaining checks are the ones of the runtime, which stops at the
+	// first issue, only report it if no other issue explains it.
+	if !hasErrors(issues) {
+		if _, _, err := LoadConfiguration(resolved, true); err != nil {
+			issues = append(issues, ConfigIssue{Message: err.Error()})
+		}
+	}
+
+	return resolved, issues, nil
+}
+
+func hasErrors(issues []ConfigIssue) bool {
+	for _, issue := range issues {
+		if !issue.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+// tomlField returns the field of the struct type t decoded from the TOML
+// key, as matched by the TOML decoder.
+func tomlField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if tag == key || (tag == "" && strings.EqualFold(field.Name, key)) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// tomlTypeName describes the TOML type decoded into t.
+func tomlTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a float"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "a table"
+	}
+}
+
+func sortedKeys(table map[string]interface{}) []string {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkTomlKeys checks that the TOML value, decoded from file at key, can
+// be decoded into type t.
+func checkTomlKeys(file string, key []string, value interface{}, t reflect.Type) []ConfigIssue {
+	name := strings.Join(key, ".")
+	mismatch := func() []ConfigIssue {
+		return []ConfigIssue{{
+			File:    file,
+			Key:     name,
+			Message: fmt.Sprintf("expected %s, got %v", tomlTypeName(t), value),
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return mismatch()
+		}
+
+		var issues []ConfigIssue
+		for _, k := range sortedKeys(table) {
+			subKey := append(append([]string{}, key...), k)
+
+			if t.Kind() == reflect.Map {
+				if name == "hypervisor" && !contains(hypervisorTableTypes, k) {
+					issues = append(issues, ConfigIssue{File: file, Key: strings.Join(subKey, "."), Message: "unknown hypervisor"})
+					continue
+				}
+				issues = append(issues, checkTomlKeys(file, subKey, table[k], t.Elem())...)
+				continue
+			}
+
+			field, ok := tomlField(t, k)
+			if !ok {
+				issues = append(issues, ConfigIssue{File: file, Key: strings.Join(subKey, "."), Message: "unknown key"})
+				continue
+			}
+			issues = append(issues, checkTomlKeys(file, subKey, table[k], field.Type)...)
+		}
+		return issues
+	case reflect.Slice, reflect.Array:
+		list := reflect.ValueOf(value)
+		if value == nil || list.Kind() != reflect.Slice {
+			return mismatch()
+		}
+
+		var issues []ConfigIssue
+		for i := 0; i < list.Len(); i++ {
+			issues = append(issues, checkTomlKeys(file, key, list.Index(i).Interface(), t.Elem())...)
+		}
+		return issues
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return mismatch()
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return mismatch()
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := value.(int64)
+		if !ok {
+			return mismatch()
+		}
+		if reflect.New(t).Elem().OverflowInt(i) {
+			return []ConfigIssue{{File: file, Key: name, Message: fmt.Sprintf("value %d is out of range", i)}}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := value.(int64)
+		if !ok {
+			return mismatch()
+		}
+		if i < 0 || reflect.New(t).Elem().OverflowUint(uint64(i)) {
+			return []ConfigIssue{{File: file, Key: name, Message: fmt.Sprintf("value %d is out of range", i)}}
+		}
+	case reflect.Float32, reflect.Float64:
+		switch value.(type) {
+		case float64, int64:
+		default:
+			return mismatch()
+		}
+	}
+
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTomlConfig checks the asset paths and the options of the decoded
+// configuration.
+func checkTomlConfig(tomlConf tomlConfig) []ConfigIssue {
+	var issues []ConfigIssue
+
+	if len(tomlConf.Hypervisor) == 0 {
+		issues = append(issues, ConfigIssue{Key: "hypervisor", Message: "no hypervisor is configured"})
+	} else if len(tomlConf.Hypervisor) > 1 {
+		issues = append(issues, ConfigIssue{Key: "hypervisor", Message: "several hypervisors are configured, only one of them is used"})
+	}
+
+	names := make([]string, 0, len(tomlConf.Hypervisor))
+	for name := range tomlConf.Hypervisor {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		h := tomlConf.Hypervisor[name]
+		prefix := "hypervisor." + name + "."
+
+		if name != remoteHypervisorTableType {
+			value := reflect.ValueOf(h)
+			for _, key := range hypervisorAssetKeys {
+				v, err := getValue(value, key)
+				if err != nil || v.String() == "" {
+					continue
+				}
+				if _, err := ResolvePath(v.String()); err != nil {
+					issues = append(issues, ConfigIssue{Key: prefix + key, Message: err.Error()})
+				}
+			}
+		}
+
+		if h.Image != "" && h.Initrd != "" {
+			issues = append(issues, ConfigIssue{Key: prefix + "image", Message: "image and initrd cannot be both set"})
+		}
+
+		if tomlConf.Factory.Template {
+			if h.Initrd == "" {
+				issues = append(issues, ConfigIssue{Key: "factory.enable_template", Message: "VM templating requires an initrd"})
+			}
+			if h.SharedFS == "virtio-fs" || h.SharedFS == "virtio-fs-nydus" {
+				issues = append(issues, ConfigIssue{Key: "factory.enable_template", Message: "VM templating does not support shared_fs " + h.SharedFS})
+			}
+		}
+	}
+
+	netModel := tomlConf.Runtime.InterNetworkModel
+	if netModel == "" {
+		netModel = defaultInterNetworkingModel
+	}
+	if tomlConf.Runtime.DisableNewNetNs && netModel != "none" {
+		issues = append(issues, ConfigIssue{Key: "runtime.disable_new_netns", Message: "disable_new_netns only works with the 'none' internetworking_model"})
+	}
+	if tomlConf.Runtime.PasstPath != "" {
+		if _, err := ResolvePath(tomlConf.Runtime.PasstPath); err != nil {
+			issues = append(issues, ConfigIssue{Key: "runtime.passt_path", Message: err.Error()})
+		}
+	}
+
+	if tomlConf.Factory.VMCacheNumber > 0 && tomlConf.Factory.Template {
+		issues = append(issues, ConfigIssue{Key: "factory.vm_cache_number", Message: "VMCache and VM templating are both enabled, VM templating is only used by the VMCache server", Warning: true})
+	}
+
+	return issues
+}
+
+// DecodeConfigFiles returns the resolved path of the configuration file at
+// configPath, the default one if empty, the TOML tables of the file merged
+// with the ones of its drop-in files, and the file each key was set by.
+func DecodeConfigFiles(configPath string) (string, map[string]interface{}, map[string]string, error) {
+	resolved, err := resolveConfigPath(configPath)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	dropIns, err := dropInFiles(resolved)
+	if err != nil {
+		return resolved, nil, nil, err
+	}
+
+	merged := make(map[string]interface{})
+	origins := make(map[string]string)
+	for _, file := range append([]string{resolved}, dropIns...) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return resolved, nil, nil, err
+		}
+
+		var raw map[string]interface{}
+		if _, err := toml.Decode(string(data), &raw); err != nil {
+			return resolved, nil, nil, fmt.Errorf("error decoding file %q: %s", file, err)
+		}
+
+		mergeTables(merged, raw, nil, file, origins)
+	}
+
+	return resolved, merged, origins, nil
+}
+
+// mergeTables merges the TOML table src into dst, recording the file the
+// values come from.
+func mergeTables(dst, src map[string]interface{}, key []string, file string, origins map[string]string) {
+	for k, v := range src {
+		subKey := append(append([]string{}, key...), k)
+
+		if table, ok := v.(map[string]interface{}); ok {
+			dstTable, ok := dst[k].(map[string]interface{})
+			if !ok {
+				dstTable = make(map[string]interface{})
+				dst[k] = dstTable
+			}
+			mergeTables(dstTable, table, subKey, file, origins)
+			continue
+		}
+
+		dst[k] = v
+		origins[strings.Join(subKey, ".")] = file
+	}
+}
+
+// ApplyAnnotationTables sets the keys of the TOML tables, as returned by
+// DecodeConfigFiles, which the annotations override and records
+// AnnotationOrigin as their origin. It returns the annotations which do not
+// override a configuration key.
+func ApplyAnnotationTables(tables map[string]interface{}, origins map[string]string, annotations map[string]string) (map[string]string, error) {
+	others := make(map[string]string)
+	for name, value := range annotations {
+		key, t, ok := annotationKey(tables, name)
+		if !ok {
+			others[name] = value
+			continue
+		}
+
+		v, err := annotationValue(name, value, t)
+		if err != nil {
+			return nil, err
+		}
+
+		setTableKey(tables, key, v)
+		origins[strings.Join(key, ".")] = AnnotationOrigin
+	}
+
+	return others, nil
+}
+
+// annotationKey returns the TOML key the annotation name overrides and the
+// type its value is decoded into.
+func annotationKey(tables map[string]interface{}, name string) ([]string, reflect.Type, bool) {
+	var (
+		key []string
+		t   reflect.Type
+	)
+
+	switch {
+	case strings.HasPrefix(name, vcAnnotations.KataAnnotationHypervisorPrefix):
+		hypervisorTable := subTableName(tables, "hypervisor")
+		if hypervisorTable == "" {
+			return nil, nil, false
+		}
+		key = []string{"hypervisor", hypervisorTable, strings.TrimPrefix(name, vcAnnotations.KataAnnotationHypervisorPrefix)}
+		t = reflect.TypeOf(hypervisor{})
+	case strings.HasPrefix(name, vcAnnotations.KataAnnotationAgentPrefix):
+		agentTable := subTableName(tables, "agent")
+		if agentTable == "" {
+			agentTable = "kata"
+		}
+		key = []string{"agent", agentTable, strings.TrimPrefix(name, vcAnnotations.KataAnnotationAgentPrefix)}
+		t = reflect.TypeOf(agent{})
+	case strings.HasPrefix(name, vcAnnotations.KataAnnotationRuntimePrefix):
+		key = []string{"runtime", strings.TrimPrefix(name, vcAnnotations.KataAnnotationRuntimePrefix)}
+		t = reflect.TypeOf(runtime{})
+	default:
+		return nil, nil, false
+	}
+
+	field, ok := tomlField(t, key[len(key)-1])
+	if !ok {
+		return nil, nil, false
+	}
+
+	return key, field.Type, true
+}
+
+// subTableName returns the name of the table of the table name, the first
+// one if there are several, or an empty string if there is none.
+func subTableName(tables map[string]interface{}, name string) string {
+	table, ok := tables[name].(map[string]interface{})
+	if !ok || len(table) == 0 {
+		return ""
+	}
+
+	return sortedKeys(table)[0]
+}
+
+// annotationValue decodes the value of the annotation name as the TOML
+// value of type t.
+func annotationValue(name, value string, t reflect.Type) (interface{}, error) {
+	var (
+		v   interface{}
+		err error
+	)
+
+	switch t.Kind() {
+	case reflect.Bool:
+		v, err = strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err = strconv.ParseInt(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		v, err = strconv.ParseFloat(value, 64)
+	case reflect.Slice:
+		if name == vcAnnotations.KernelModules {
+			v = strings.Split(value, oci.KernelModulesSeparator)
+		} else {
+			v = strings.Fields(value)
+		}
+	default:
+		v = value
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q of annotation %s: %v", value, name, err)
+	}
+
+	return v, nil
+}
+
+func setTableKey(tables map[string]interface{}, key []string, value interface{}) {
+	for _, k := range key[:len(key)-1] {
+		table, ok := tables[k].(map[string]interface{})
+		if !ok {
+			table = make(map[string]interface{})
+			tables[k] = table
+		}
+		tables = table
+	}
+
+	tables[key[len(key)-1]] = value
+}
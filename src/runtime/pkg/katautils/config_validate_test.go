@@ -0,0 +1,194 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katautils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// createValidateTestConfig writes a qemu configuration file with the extra
+// TOML appended, and the assets it refers to, to dir.
+func createValidateTestConfig(t *testing.T, dir, extra string) string {
+	for _, asset := range []string{"qemu", "vmlinux", "image"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, asset), []byte(asset), 0600))
+	}
+
+	config := fmt.Sprintf(`
+[hypervisor.qemu]
+path = "%s/qemu"
+kernel = "%s/vmlinux"
+image = "%s/image"
+shared_fs = "virtio-9p"
+%s
+`, dir, dir, dir, extra)
+
+	configPath := filepath.Join(dir, "configuration.toml")
+	assert.NoError(t, createConfig(configPath, config))
+
+	return configPath
+}
+
+func issueKeys(issues []ConfigIssue) []string {
+	var keys []string
+	for _, issue := range issues {
+		keys = append(keys, issue.Key)
+	}
+	return keys
+}
+
+func TestValidateConfiguration(t *testing.T) {
+	assert := assert.New(t)
+
+	orgVHostVSockDevicePath := utils.VHostVSockDevicePath
+	defer func() {
+		utils.VHostVSockDevicePath = orgVHostVSockDevicePath
+	}()
+	utils.VHostVSockDevicePath = "/dev/null"
+
+	dir := t.TempDir()
+	configPath := createValidateTestConfig(t, dir, "")
+
+	resolved, issues, err := ValidateConfiguration(configPath)
+	assert.NoError(err)
+	assert.Equal(configPath, resolved)
+	assert.Empty(issues)
+
+	// unknown keys and type mismatches, in drop-in files too
+	configPath = createValidateTestConfig(t, dir, `
+default_vcpus = "two"
+default_memory = -1
+no_such_key = true
+
+[hypervisor.kvm]
+path = "/usr/bin/kvm"
+
+[agent.kata]
+kernel_modules = [1]
+
+[runtime]
+internetworking_model = "tcfilter"
+`)
+	dropInDir := filepath.Join(dir, "config.d")
+	assert.NoError(os.MkdirAll(dropInDir, 0700))
+	assert.NoError(createConfig(filepath.Join(dropInDir, "10-debug.toml"), "[runtime]\nenable_debug = \"yes\"\n"))
+
+	_, issues, err = ValidateConfiguration(configPath)
+	assert.NoError(err)
+	assert.Equal([]string{
+		"agent.kata.kernel_modules",
+		"hypervisor.kvm",
+		"hypervisor.qemu.default_memory",
+		"hypervisor.qemu.default_vcpus",
+		"hypervisor.qemu.no_such_key",
+		"runtime.enable_debug",
+	}, issueKeys(issues))
+	assert.Equal(filepath.Join(dropInDir, "10-debug.toml"), issues[len(issues)-1].File)
+	assert.NoError(os.RemoveAll(dropInDir))
+
+	// missing assets and conflicting options
+	configPath = createValidateTestConfig(t, dir, fmt.Sprintf(`
+initrd = "%s/initrd"
+firmware = "%s/firmware"
+
+[factory]
+enable_template = true
+vm_cache_number = 1
+
+[runtime]
+disable_new_netns = true
+`, dir, dir))
+	assert.NoError(createEmptyFile(filepath.Join(dir, "initrd")))
+
+	_, issues, err = ValidateConfiguration(configPath)
+	assert.NoError(err)
+	assert.Equal([]string{
+		"hypervisor.qemu.firmware",
+		"hypervisor.qemu.image",
+		"runtime.disable_new_netns",
+		"factory.vm_cache_number",
+	}, issueKeys(issues))
+	assert.True(issues[3].Warning)
+
+	// syntax errors
+	assert.NoError(createConfig(configPath, "[hypervisor.qemu\n"))
+	_, issues, err = ValidateConfiguration(configPath)
+	assert.NoError(err)
+	assert.Len(issues, 1)
+	assert.Equal(configPath, issues[0].File)
+
+	_, _, err = ValidateConfiguration(filepath.Join(dir, "nonexistent.toml"))
+	assert.Error(err)
+}
+
+func TestDecodeConfigFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	configPath := createValidateTestConfig(t, dir, "default_vcpus = 1")
+
+	dropInDir := filepath.Join(dir, "config.d")
+	assert.NoError(os.MkdirAll(dropInDir, 0700))
+	dropIn := filepath.Join(dropInDir, "10-vcpus.toml")
+	assert.NoError(createConfig(dropIn, "[hypervisor.qemu]\ndefault_vcpus = 2\n"))
+
+	resolved, tables, origins, err := DecodeConfigFiles(configPath)
+	assert.NoError(err)
+	assert.Equal(configPath, resolved)
+
+	qemu := tables["hypervisor"].(map[string]interface{})["qemu"].(map[string]interface{})
+	assert.Equal(int64(2), qemu["default_vcpus"])
+	assert.Equal(filepath.Join(dir, "vmlinux"), qemu["kernel"])
+
+	assert.Equal(dropIn, origins["hypervisor.qemu.default_vcpus"])
+	assert.Equal(configPath, origins["hypervisor.qemu.kernel"])
+
+	assert.NoError(createConfig(dropIn, "[hypervisor.qemu\n"))
+	_, _, _, err = DecodeConfigFiles(configPath)
+	assert.Error(err)
+}
+
+func TestApplyAnnotationTables(t *testing.T) {
+	assert := assert.New(t)
+
+	tables := map[string]interface{}{
+		"hypervisor": map[string]interface{}{
+			"qemu": map[string]interface{}{
+				"default_vcpus": int64(1),
+			},
+		},
+	}
+	origins := map[string]string{"hypervisor.qemu.default_vcpus": "configuration.toml"}
+
+	others, err := ApplyAnnotationTables(tables, origins, map[string]string{
+		vcAnnotations.DefaultVCPUs:       "2",
+		vcAnnotations.DisableImageNvdimm: "true",
+		vcAnnotations.KernelModules:      "e1000e EEE=1;i915",
+		vcAnnotations.SandboxCgroupOnly:  "true",
+		vcAnnotations.KernelHash:         "abc",
+	})
+	assert.NoError(err)
+	assert.Equal(map[string]string{vcAnnotations.KernelHash: "abc"}, others)
+
+	qemu := tables["hypervisor"].(map[string]interface{})["qemu"].(map[string]interface{})
+	assert.Equal(int64(2), qemu["default_vcpus"])
+	assert.Equal(true, qemu["disable_image_nvdimm"])
+	assert.Equal([]string{"e1000e EEE=1", "i915"}, tables["agent"].(map[string]interface{})["kata"].(map[string]interface{})["kernel_modules"])
+	assert.Equal(true, tables["runtime"].(map[string]interface{})["sandbox_cgroup_only"])
+
+	for _, key := range []string{"hypervisor.qemu.default_vcpus", "hypervisor.qemu.disable_image_nvdimm", "agent.kata.kernel_modules", "runtime.sandbox_cgroup_only"} {
+		assert.Equal(AnnotationOrigin, origins[key], key)
+	}
+
+	_, err = ApplyAnnotationTables(tables, origins, map[string]string{vcAnnotations.DefaultVCPUs: "two"})
+	assert.Error(err)
+}
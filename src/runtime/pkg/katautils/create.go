@@ -66,10 +66,11 @@ func HandleFactory(ctx context.Context, vci vc.VC, runtimeConfig *oci.RuntimeCon
 		return
 	}
 	factoryConfig := vf.Config{
-		Template:        runtimeConfig.FactoryConfig.Template,
-		TemplatePath:    runtimeConfig.FactoryConfig.TemplatePath,
-		VMCache:         runtimeConfig.FactoryConfig.VMCacheNumber > 0,
-		VMCacheEndpoint: runtimeConfig.FactoryConfig.VMCacheEndpoint,
+		Template:          runtimeConfig.FactoryConfig.Template,
+		TemplatePath:      runtimeConfig.FactoryConfig.TemplatePath,
+		TemplateStorePath: runtimeConfig.FactoryConfig.TemplateStorePath,
+		VMCache:           runtimeConfig.FactoryConfig.VMCacheNumber > 0,
+		VMCacheEndpoint:   runtimeConfig.FactoryConfig.VMCacheEndpoint,
 		VMConfig: vc.VMConfig{
 			HypervisorType:   runtimeConfig.HypervisorType,
 			HypervisorConfig: runtimeConfig.HypervisorConfig,
@@ -162,14 +163,15 @@ func CreateSandbox(ctx context.Context, vci vc.VC, ociSpec specs.Spec, runtimeCo
 	ociSpec.Annotations["nerdctl/network-namespace"] = sandboxConfig.NetworkConfig.NetworkID
 	sandboxConfig.Annotations["nerdctl/network-namespace"] = ociSpec.Annotations["nerdctl/network-namespace"]
 
+	hostSpec := HostHooks(ociSpec, sandboxConfig.GuestHooks.OCIHooks)
 	sandbox, err := vci.CreateSandbox(ctx, sandboxConfig, func(ctx context.Context) error {
 		// Run pre-start OCI hooks, in the runtime namespace.
-		if err := PreStartHooks(ctx, ociSpec, containerID, bundlePath); err != nil {
+		if err := PreStartHooks(ctx, hostSpec, containerID, bundlePath); err != nil {
 			return err
 		}
 
 		// Run create runtime OCI hooks, in the runtime namespace.
-		if err := CreateRuntimeHooks(ctx, ociSpec, containerID, bundlePath); err != nil {
+		if err := CreateRuntimeHooks(ctx, hostSpec, containerID, bundlePath); err != nil {
 			return err
 		}
 
@@ -183,6 +185,18 @@ func CreateSandbox(ctx context.Context, vci vc.VC, ociSpec specs.Spec, runtimeCo
 	kataUtilsLogger = kataUtilsLogger.WithField("sandbox", sid)
 	katatrace.AddTags(span, "sandbox_id", sid)
 
+	// Run create container OCI hooks, now that the container has been
+	// created in the VM.
+	if err = runCreateContainerHooks(ctx, sandbox, ociSpec, containerID, bundlePath); err != nil {
+		if ex := sandbox.Stop(ctx, true); ex != nil {
+			kataUtilsLogger.WithError(ex).Warn("failed to stop sandbox")
+		}
+		if ex := sandbox.Delete(ctx); ex != nil {
+			kataUtilsLogger.WithError(ex).Warn("failed to delete sandbox")
+		}
+		return nil, vc.Process{}, err
+	}
+
 	containers := sandbox.GetAllContainers()
 	if len(containers) != 1 {
 		return nil, vc.Process{}, fmt.Errorf("BUG: Container list from sandbox is wrong, expecting only one container, found %d containers", len(containers))
@@ -221,7 +235,7 @@ func checkForFIPS(sandboxConfig *vc.SandboxConfig) error {
 }
 
 // CreateContainer create a container
-func CreateContainer(ctx context.Context, sandbox vc.VCSandbox, ociSpec specs.Spec, rootFs vc.RootFs, containerID, bundlePath string, disableOutput bool, disableGuestEmptyDir bool) (vc.Process, error) {
+func CreateContainer(ctx context.Context, sandbox vc.VCSandbox, ociSpec specs.Spec, rootFs vc.RootFs, containerID, bundlePath string, disableOutput bool, disableGuestEmptyDir bool, guestOCIHooks bool) (_ vc.Process, err error) {
 	var c vc.VCContainer
 
 	span, ctx := katatrace.Trace(ctx, nil, "CreateContainer", createTracingTags)
@@ -257,19 +271,56 @@ func CreateContainer(ctx context.Context, sandbox vc.VCSandbox, ociSpec specs.Sp
 		return vc.Process{}, err
 	}
 
+	// Remove the container if one of its OCI hooks fails, as the
+	// runtime spec requires.
+	defer func() {
+		if err != nil {
+			if _, ex := sandbox.DeleteContainer(ctx, containerID); ex != nil {
+				kataUtilsLogger.WithField("container", containerID).WithError(ex).Warn("failed to delete container")
+			}
+		}
+	}()
+
 	hid, err := sandbox.GetHypervisorPid()
 	if err != nil {
 		return vc.Process{}, err
 	}
 	ctx = context.WithValue(ctx, vc.HypervisorPidKey{}, hid)
 
-	// Run pre-start OCI hooks.
+	// Run pre-start and create runtime OCI hooks.
 	err = EnterNetNS(sandbox.GetNetNs(), func() error {
-		return PreStartHooks(ctx, ociSpec, containerID, bundlePath)
+		if err := PreStartHooks(ctx, HostHooks(ociSpec, guestOCIHooks), containerID, bundlePath); err != nil {
+			return err
+		}
+
+		return CreateRuntimeHooks(ctx, ociSpec, containerID, bundlePath)
 	})
 	if err != nil {
 		return vc.Process{}, err
 	}
 
+	// Run create container OCI hooks.
+	if err = runCreateContainerHooks(ctx, sandbox, ociSpec, containerID, bundlePath); err != nil {
+		return vc.Process{}, err
+	}
+
 	return c.Process(), nil
 }
+
+// runCreateContainerHooks runs the create container OCI hooks of a
+// container created in the sandbox, in the sandbox network namespace.
+func runCreateContainerHooks(ctx context.Context, sandbox vc.VCSandbox, ociSpec specs.Spec, containerID, bundlePath string) error {
+	if ociSpec.Hooks == nil || len(ociSpec.Hooks.CreateContainer) == 0 {
+		return nil
+	}
+
+	hid, err := sandbox.GetHypervisorPid()
+	if err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, vc.HypervisorPidKey{}, hid)
+
+	return EnterNetNS(sandbox.GetNetNs(), func() error {
+		return CreateContainerHooks(ctx, ociSpec, containerID, bundlePath)
+	})
+}
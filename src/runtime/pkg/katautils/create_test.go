@@ -354,7 +354,7 @@ func TestCreateContainerContainerConfigFail(t *testing.T) {
 	rootFs := vc.RootFs{Mounted: true}
 
 	for _, disableOutput := range []bool{true, false} {
-		_, err = CreateContainer(context.Background(), mockSandbox, spec, rootFs, testContainerID, bundlePath, disableOutput, false)
+		_, err = CreateContainer(context.Background(), mockSandbox, spec, rootFs, testContainerID, bundlePath, disableOutput, false, false)
 		assert.Error(err)
 		assert.False(vcmock.IsMockError(err))
 		assert.True(strings.Contains(err.Error(), containerType))
@@ -381,7 +381,7 @@ func TestCreateContainerFail(t *testing.T) {
 	rootFs := vc.RootFs{Mounted: true}
 
 	for _, disableOutput := range []bool{true, false} {
-		_, err = CreateContainer(context.Background(), mockSandbox, spec, rootFs, testContainerID, bundlePath, disableOutput, false)
+		_, err = CreateContainer(context.Background(), mockSandbox, spec, rootFs, testContainerID, bundlePath, disableOutput, false, false)
 		assert.Error(err)
 		assert.True(vcmock.IsMockError(err))
 	}
@@ -415,7 +415,55 @@ func TestCreateContainer(t *testing.T) {
 	rootFs := vc.RootFs{Mounted: true}
 
 	for _, disableOutput := range []bool{true, false} {
-		_, err = CreateContainer(context.Background(), mockSandbox, spec, rootFs, testContainerID, bundlePath, disableOutput, false)
+		_, err = CreateContainer(context.Background(), mockSandbox, spec, rootFs, testContainerID, bundlePath, disableOutput, false, false)
 		assert.NoError(err)
 	}
 }
+
+func TestCreateContainerHooks(t *testing.T) {
+	assert := assert.New(t)
+	defer ClearHookResults(testContainerID)
+
+	mockSandbox.CreateContainerFunc = func(containerConfig vc.ContainerConfig) (vc.VCContainer, error) {
+		return &vcmock.Container{}, nil
+	}
+
+	defer func() {
+		mockSandbox.CreateContainerFunc = nil
+	}()
+
+	_, bundlePath, _ := ktu.SetupOCIConfigFile(t)
+
+	spec, err := compatoci.ParseConfigJSON(bundlePath)
+	assert.NoError(err)
+
+	spec.Annotations = make(map[string]string)
+	spec.Annotations[testContainerTypeAnnotation] = testContainerTypeContainer
+	spec.Annotations[testSandboxIDAnnotation] = testSandboxID
+
+	logFile := filepath.Join(t.TempDir(), "hooks.log")
+	spec.Hooks = &specs.Hooks{
+		Prestart:        []specs.Hook{createLogHook(t, "prestart", logFile)},
+		CreateRuntime:   []specs.Hook{createLogHook(t, "createRuntime", logFile)},
+		CreateContainer: []specs.Hook{createLogHook(t, "createContainer", logFile)},
+		StartContainer:  []specs.Hook{createLogHook(t, "startContainer", logFile)},
+	}
+
+	rootFs := vc.RootFs{Mounted: true}
+
+	_, err = CreateContainer(context.Background(), mockSandbox, spec, rootFs, testContainerID, bundlePath, true, false, false)
+	assert.NoError(err)
+
+	// The start container hooks only run when the container is started.
+	names, states := readHookLog(t, logFile)
+	assert.Equal([]string{"prestart", "createRuntime", "createContainer"}, names)
+	for _, state := range states {
+		assert.Equal(testContainerID, state.ID)
+		assert.Equal(specs.StateCreating, state.Status)
+	}
+
+	// A failing hook fails the creation of the container.
+	spec.Hooks.CreateContainer = append(spec.Hooks.CreateContainer, specs.Hook{Path: "/bin/false", Args: []string{"false"}})
+	_, err = CreateContainer(context.Background(), mockSandbox, spec, rootFs, testContainerID, bundlePath, true, false, false)
+	assert.Error(err)
+}
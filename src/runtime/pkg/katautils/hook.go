@@ -11,13 +11,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
 	syscallWrapper "github.com/kata-containers/kata-containers/src/runtime/pkg/syscall"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 )
@@ -34,11 +38,192 @@ func hookLogger() *logrus.Entry {
 	return kataUtilsLogger.WithField("subsystem", "hook")
 }
 
-func runHook(ctx context.Context, spec specs.Spec, hook specs.Hook, cid, bundlePath string) error {
-	span, _ := katatrace.Trace(ctx, hookLogger(), "runHook", hookTracingTags)
-	defer span.End()
-	katatrace.AddTags(span, "path", hook.Path, "args", hook.Args)
+// hookStage is an OCI hook stage, with the status of the container in
+// the state given to the hooks of the stage.
+type hookStage struct {
+	name   string
+	status specs.ContainerState
+	hooks  func(*specs.Hooks) []specs.Hook
+}
+
+var (
+	prestartStage = hookStage{
+		name:   "pre-start",
+		status: specs.StateCreating,
+		hooks:  func(h *specs.Hooks) []specs.Hook { return h.Prestart },
+	}
+	createRuntimeStage = hookStage{
+		name:   "createRuntime",
+		status: specs.StateCreating,
+		hooks:  func(h *specs.Hooks) []specs.Hook { return h.CreateRuntime },
+	}
+	createContainerStage = hookStage{
+		name:   "createContainer",
+		status: specs.StateCreating,
+		hooks:  func(h *specs.Hooks) []specs.Hook { return h.CreateContainer },
+	}
+	startContainerStage = hookStage{
+		name:   "startContainer",
+		status: specs.StateCreated,
+		hooks:  func(h *specs.Hooks) []specs.Hook { return h.StartContainer },
+	}
+	poststartStage = hookStage{
+		name:   "post-start",
+		status: specs.StateRunning,
+		hooks:  func(h *specs.Hooks) []specs.Hook { return h.Poststart },
+	}
+	poststopStage = hookStage{
+		name:   "post-stop",
+		status: specs.StateStopped,
+		hooks:  func(h *specs.Hooks) []specs.Hook { return h.Poststop },
+	}
+)
+
+// HookResult is the result of an OCI hook run on the host.
+type HookResult struct {
+	Stage    string        `json:"stage"`
+	Path     string        `json:"path"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// hookResults are the results of the OCI hooks run for each container. They
+// are also stored under the runtime storage path, so that they are not lost
+// when the shim restarts.
+var hookResults = struct {
+	sync.Mutex
+	containers map[string][]HookResult
+}{
+	containers: make(map[string][]HookResult),
+}
+
+// hookResultsPath returns the file storing the hook results of a container.
+var hookResultsPath = func(cid string) (string, error) {
+	driver, err := persist.GetDriver()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(driver.RunStoragePath()), "hooks", cid+".json"), nil
+}
+
+func storeHookResults(cid string, results []HookResult) error {
+	path, err := hookResultsPath(cid)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func loadHookResults(cid string) ([]HookResult, error) {
+	path, err := hookResultsPath(cid)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var results []HookResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// containerHookResults returns the results of a container, loading them
+// from the disk if the shim restarted since the hooks were run. The caller
+// must hold the hookResults lock.
+func containerHookResults(cid string) []HookResult {
+	results, ok := hookResults.containers[cid]
+	if ok {
+		return results
+	}
+
+	results, err := loadHookResults(cid)
+	if err != nil {
+		hookLogger().WithError(err).WithField("container", cid).Warn("failed to load hook results")
+	}
+	if results != nil {
+		hookResults.containers[cid] = results
+	}
+
+	return results
+}
+
+func recordHookResult(cid string, result HookResult) {
+	hookResults.Lock()
+	defer hookResults.Unlock()
 
+	results := append(containerHookResults(cid), result)
+	hookResults.containers[cid] = results
+
+	if err := storeHookResults(cid, results); err != nil {
+		hookLogger().WithError(err).WithField("container", cid).Warn("failed to store hook results")
+	}
+}
+
+// HookResults returns the results of the OCI hooks run for a container, in
+// the order they were run.
+func HookResults(cid string) []HookResult {
+	hookResults.Lock()
+	defer hookResults.Unlock()
+
+	return append([]HookResult(nil), containerHookResults(cid)...)
+}
+
+// ClearHookResults forgets the results of the OCI hooks run for a
+// container, once it is deleted.
+func ClearHookResults(cid string) {
+	hookResults.Lock()
+	defer hookResults.Unlock()
+
+	delete(hookResults.containers, cid)
+
+	path, err := hookResultsPath(cid)
+	if err == nil {
+		err = os.Remove(path)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		hookLogger().WithError(err).WithField("container", cid).Warn("failed to remove hook results")
+	}
+}
+
+// hookState returns the state of the container given to the hooks on
+// their stdin. The pid is the one of the hypervisor, which runs the
+// container, if it is known.
+func hookState(ctx context.Context, spec specs.Spec, status specs.ContainerState, cid, bundlePath string) specs.State {
 	pid, ok := ctx.Value(vc.HypervisorPidKey{}).(int)
 	if !ok || pid == 0 {
 		hookLogger().Info("no hypervisor pid")
@@ -47,12 +232,24 @@ func runHook(ctx context.Context, spec specs.Spec, hook specs.Hook, cid, bundleP
 	}
 	hookLogger().Infof("hypervisor pid %v", pid)
 
-	state := specs.State{
+	return specs.State{
+		Version:     specs.Version,
+		ID:          cid,
+		Status:      status,
 		Pid:         pid,
 		Bundle:      bundlePath,
-		ID:          cid,
 		Annotations: spec.Annotations,
 	}
+}
+
+func runHook(ctx context.Context, state specs.State, hook specs.Hook) error {
+	span, _ := katatrace.Trace(ctx, hookLogger(), "runHook", hookTracingTags)
+	defer span.End()
+	katatrace.AddTags(span, "path", hook.Path, "args", hook.Args)
+
+	if hook.Timeout != nil && *hook.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout %d for hook %s", *hook.Timeout, hook.Path)
+	}
 
 	stateJSON, err := json.Marshal(state)
 	if err != nil {
@@ -94,22 +291,51 @@ func runHook(ctx context.Context, spec specs.Spec, hook specs.Hook, cid, bundleP
 				return err
 			}
 
-			return fmt.Errorf("Hook timeout")
+			return fmt.Errorf("Hook timeout: %s did not complete in %ds", hook.Path, *hook.Timeout)
 		}
 	}
 
 	return nil
 }
 
-func runHooks(ctx context.Context, spec specs.Spec, hooks []specs.Hook, cid, bundlePath, hookType string) error {
+// runHooks runs the hooks of stage in order, and stops at the first one
+// which fails.
+func runHooks(ctx context.Context, spec specs.Spec, stage hookStage, cid, bundlePath string) error {
+	// If no hook available, nothing needs to be done.
+	if spec.Hooks == nil {
+		return nil
+	}
+
+	hooks := stage.hooks(spec.Hooks)
+	if len(hooks) == 0 {
+		return nil
+	}
+
 	span, ctx := katatrace.Trace(ctx, hookLogger(), "runHooks", hookTracingTags)
-	katatrace.AddTags(span, "type", hookType)
+	katatrace.AddTags(span, "type", stage.name)
 	defer span.End()
 
+	state := hookState(ctx, spec, stage.status, cid, bundlePath)
+
 	for _, hook := range hooks {
-		if err := runHook(ctx, spec, hook, cid, bundlePath); err != nil {
+		start := time.Now()
+		err := runHook(ctx, state, hook)
+
+		result := HookResult{
+			Stage:    stage.name,
+			Path:     hook.Path,
+			Start:    start,
+			Duration: time.Since(start),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		recordHookResult(cid, result)
+
+		if err != nil {
 			hookLogger().WithFields(logrus.Fields{
-				"hook-type": hookType,
+				"hook-type": stage.name,
+				"container": cid,
 				"error":     err,
 			}).Error("hook error")
 
@@ -120,41 +346,53 @@ func runHooks(ctx context.Context, spec specs.Spec, hooks []specs.Hook, cid, bun
 	return nil
 }
 
-func CreateRuntimeHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
-	// If no hook available, nothing needs to be done.
-	if spec.Hooks == nil {
-		return nil
+// HostHooks returns spec without the prestart, poststart and poststop hooks
+// when guestOCIHooks is set, the agent running them in the guest.
+func HostHooks(spec specs.Spec, guestOCIHooks bool) specs.Spec {
+	if !guestOCIHooks || spec.Hooks == nil {
+		return spec
 	}
 
-	return runHooks(ctx, spec, spec.Hooks.CreateRuntime, cid, bundlePath, "createRuntime")
+	hooks := *spec.Hooks
+	hooks.Prestart = nil
+	hooks.Poststart = nil
+	hooks.Poststop = nil
+	spec.Hooks = &hooks
+
+	return spec
+}
+
+// CreateRuntimeHooks run the hooks once the runtime environment of the
+// container has been created
+func CreateRuntimeHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
+	return runHooks(ctx, spec, createRuntimeStage, cid, bundlePath)
+}
+
+// CreateContainerHooks run the hooks once the container has been created,
+// after the create runtime hooks
+func CreateContainerHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
+	return runHooks(ctx, spec, createContainerStage, cid, bundlePath)
 }
 
 // PreStartHooks run the hooks before start container
 func PreStartHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
-	// If no hook available, nothing needs to be done.
-	if spec.Hooks == nil {
-		return nil
-	}
+	return runHooks(ctx, spec, prestartStage, cid, bundlePath)
+}
 
-	return runHooks(ctx, spec, spec.Hooks.Prestart, cid, bundlePath, "pre-start")
+// StartContainerHooks run the hooks when the container is started, before
+// its process runs. The OCI runtime spec runs them in the container
+// namespaces, which live in the guest: they are run on the host instead,
+// in the network namespace of the sandbox, as the other hook stages.
+func StartContainerHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
+	return runHooks(ctx, spec, startContainerStage, cid, bundlePath)
 }
 
 // PostStartHooks run the hooks just after start container
 func PostStartHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
-	// If no hook available, nothing needs to be done.
-	if spec.Hooks == nil {
-		return nil
-	}
-
-	return runHooks(ctx, spec, spec.Hooks.Poststart, cid, bundlePath, "post-start")
+	return runHooks(ctx, spec, poststartStage, cid, bundlePath)
 }
 
 // PostStopHooks run the hooks after stop container
 func PostStopHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
-	// If no hook available, nothing needs to be done.
-	if spec.Hooks == nil {
-		return nil
-	}
-
-	return runHooks(ctx, spec, spec.Hooks.Poststop, cid, bundlePath, "post-stop")
+	return runHooks(ctx, spec, poststopStage, cid, bundlePath)
 }
@@ -8,10 +8,14 @@ package katautils
 
 import (
 	"context"
+	"encoding/json"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
 )
@@ -67,23 +71,24 @@ func TestRunHook(t *testing.T) {
 
 	// Run with timeout 0
 	hook := createHook(0)
-	err := runHook(ctx, spec, hook, testSandboxID, testBundlePath)
+	state := hookState(ctx, spec, specs.StateCreating, testSandboxID, testBundlePath)
+	err := runHook(ctx, state, hook)
 	assert.NoError(err)
 
 	// Run with timeout 1
 	hook = createHook(1)
-	err = runHook(ctx, spec, hook, testSandboxID, testBundlePath)
+	err = runHook(ctx, state, hook)
 	assert.NoError(err)
 
 	// Run timeout failure
 	hook = createHook(1)
 	hook.Args = append(hook.Args, "2")
-	err = runHook(ctx, spec, hook, testSandboxID, testBundlePath)
+	err = runHook(ctx, state, hook)
 	assert.Error(err)
 
 	// Failure due to wrong hook
 	hook = createWrongHook()
-	err = runHook(ctx, spec, hook, testSandboxID, testBundlePath)
+	err = runHook(ctx, state, hook)
 	assert.Error(err)
 }
 
@@ -93,6 +98,7 @@ func TestPreStartHooks(t *testing.T) {
 	}
 
 	assert := assert.New(t)
+	defer ClearHookResults(testSandboxID)
 	t.Cleanup(cleanMockHookLogFile)
 
 	ctx := context.Background()
@@ -136,6 +142,7 @@ func TestPostStartHooks(t *testing.T) {
 	}
 
 	assert := assert.New(t)
+	defer ClearHookResults(testSandboxID)
 	t.Cleanup(cleanMockHookLogFile)
 
 	ctx := context.Background()
@@ -179,6 +186,7 @@ func TestPostStopHooks(t *testing.T) {
 	}
 
 	assert := assert.New(t)
+	defer ClearHookResults(testSandboxID)
 
 	ctx := context.Background()
 	t.Cleanup(cleanMockHookLogFile)
@@ -215,3 +223,225 @@ func TestPostStopHooks(t *testing.T) {
 	err = PostStopHooks(ctx, spec, testSandboxID, testBundlePath)
 	assert.Error(err)
 }
+
+// createLogHook returns a hook which appends its name and the state it
+// receives to logFile.
+func createLogHook(t *testing.T, name, logFile string) specs.Hook {
+	path := filepath.Join(t.TempDir(), name)
+	script := "#!/bin/sh\necho \"" + name + " $(cat)\" >> " + logFile + "\n"
+	assert.NoError(t, os.WriteFile(path, []byte(script), 0700))
+
+	return specs.Hook{Path: path, Args: []string{name}}
+}
+
+// readHookLog returns the names of the hooks which ran, in order, and
+// the states they received.
+func readHookLog(t *testing.T, logFile string) ([]string, []specs.State) {
+	content, err := os.ReadFile(logFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	assert.NoError(t, err)
+
+	var names []string
+	var states []specs.State
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		name, stateJSON, _ := strings.Cut(line, " ")
+
+		var state specs.State
+		assert.NoError(t, json.Unmarshal([]byte(stateJSON), &state))
+
+		names = append(names, name)
+		states = append(states, state)
+	}
+
+	return names, states
+}
+
+func TestHookStages(t *testing.T) {
+	assert := assert.New(t)
+	defer ClearHookResults(testContainerID)
+
+	logFile := filepath.Join(t.TempDir(), "hooks.log")
+	spec := specs.Spec{
+		Annotations: map[string]string{"key": "value"},
+		Hooks: &specs.Hooks{
+			Prestart:        []specs.Hook{createLogHook(t, "prestart", logFile)},
+			CreateRuntime:   []specs.Hook{createLogHook(t, "createRuntime", logFile)},
+			CreateContainer: []specs.Hook{createLogHook(t, "createContainer", logFile)},
+			StartContainer:  []specs.Hook{createLogHook(t, "startContainer", logFile)},
+			Poststart:       []specs.Hook{createLogHook(t, "poststart", logFile)},
+			Poststop:        []specs.Hook{createLogHook(t, "poststop", logFile)},
+		},
+	}
+
+	ctx := context.WithValue(context.Background(), vc.HypervisorPidKey{}, 1234)
+	for _, run := range []func(context.Context, specs.Spec, string, string) error{
+		PreStartHooks,
+		CreateRuntimeHooks,
+		CreateContainerHooks,
+		StartContainerHooks,
+		PostStartHooks,
+		PostStopHooks,
+	} {
+		assert.NoError(run(ctx, spec, testContainerID, testBundlePath))
+	}
+
+	names, states := readHookLog(t, logFile)
+	assert.Equal([]string{"prestart", "createRuntime", "createContainer", "startContainer", "poststart", "poststop"}, names)
+
+	expectedStatus := []specs.ContainerState{
+		specs.StateCreating,
+		specs.StateCreating,
+		specs.StateCreating,
+		specs.StateCreated,
+		specs.StateRunning,
+		specs.StateStopped,
+	}
+	for i, state := range states {
+		assert.Equal(specs.State{
+			Version:     specs.Version,
+			ID:          testContainerID,
+			Status:      expectedStatus[i],
+			Pid:         1234,
+			Bundle:      testBundlePath,
+			Annotations: spec.Annotations,
+		}, state, names[i])
+	}
+}
+
+func TestRunHooksStopOnFailure(t *testing.T) {
+	assert := assert.New(t)
+	defer ClearHookResults(testContainerID)
+
+	logFile := filepath.Join(t.TempDir(), "hooks.log")
+	spec := specs.Spec{
+		Hooks: &specs.Hooks{
+			StartContainer: []specs.Hook{
+				createLogHook(t, "first", logFile),
+				{Path: "/bin/false", Args: []string{"false"}},
+				createLogHook(t, "last", logFile),
+			},
+		},
+	}
+
+	assert.Error(StartContainerHooks(context.Background(), spec, testContainerID, testBundlePath))
+
+	names, _ := readHookLog(t, logFile)
+	assert.Equal([]string{"first"}, names)
+}
+
+func TestRunHookTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	state := specs.State{ID: testContainerID}
+	timeout := 1
+
+	err := runHook(context.Background(), state, specs.Hook{
+		Path:    "/bin/sh",
+		Args:    []string{"sh", "-c", "sleep 10"},
+		Timeout: &timeout,
+	})
+	assert.Error(err)
+	assert.Contains(err.Error(), "Hook timeout")
+
+	timeout = 0
+	err = runHook(context.Background(), state, specs.Hook{Path: "/bin/true", Timeout: &timeout})
+	assert.Error(err)
+	assert.Contains(err.Error(), "invalid timeout")
+}
+
+func TestHostHooks(t *testing.T) {
+	assert := assert.New(t)
+
+	hook := specs.Hook{Path: "/bin/hook"}
+	spec := specs.Spec{
+		Hooks: &specs.Hooks{
+			Prestart:       []specs.Hook{hook},
+			CreateRuntime:  []specs.Hook{hook},
+			StartContainer: []specs.Hook{hook},
+			Poststart:      []specs.Hook{hook},
+			Poststop:       []specs.Hook{hook},
+		},
+	}
+
+	assert.Equal(spec, HostHooks(spec, false))
+
+	// The stages run by the agent are left to the guest, without
+	// changing the spec of the container.
+	host := HostHooks(spec, true)
+	assert.Equal(&specs.Hooks{
+		CreateRuntime:  []specs.Hook{hook},
+		StartContainer: []specs.Hook{hook},
+	}, host.Hooks)
+	assert.Len(spec.Hooks.Prestart, 1)
+
+	assert.Nil(HostHooks(specs.Spec{}, true).Hooks)
+}
+
+func TestHookResults(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := specs.Spec{
+		Hooks: &specs.Hooks{
+			Prestart: []specs.Hook{
+				{Path: "/bin/true", Args: []string{"true"}},
+				{Path: "/bin/false", Args: []string{"false"}},
+			},
+		},
+	}
+
+	cid := "hook-results"
+	defer ClearHookResults(cid)
+
+	assert.Error(PreStartHooks(context.Background(), spec, cid, testBundlePath))
+
+	results := HookResults(cid)
+	assert.Len(results, 2)
+	assert.Equal("pre-start", results[0].Stage)
+	assert.Equal("/bin/true", results[0].Path)
+	assert.Empty(results[0].Error)
+	assert.False(results[0].Start.IsZero())
+	assert.Equal("/bin/false", results[1].Path)
+	assert.NotEmpty(results[1].Error)
+
+	ClearHookResults(cid)
+	assert.Empty(HookResults(cid))
+}
+
+func TestHookResultsPersisted(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	savedPath := hookResultsPath
+	hookResultsPath = func(cid string) (string, error) {
+		return filepath.Join(dir, cid+".json"), nil
+	}
+	defer func() { hookResultsPath = savedPath }()
+
+	spec := specs.Spec{
+		Hooks: &specs.Hooks{
+			Poststart: []specs.Hook{{Path: "/bin/true", Args: []string{"true"}}},
+		},
+	}
+
+	cid := "hook-results-persisted"
+	defer ClearHookResults(cid)
+
+	assert.NoError(PostStartHooks(context.Background(), spec, cid, testBundlePath))
+	assert.FileExists(filepath.Join(dir, cid+".json"))
+
+	// Forget the results kept in memory, as a restarted shim would.
+	hookResults.Lock()
+	delete(hookResults.containers, cid)
+	hookResults.Unlock()
+
+	results := HookResults(cid)
+	assert.Len(results, 1)
+	assert.Equal("post-start", results[0].Stage)
+	assert.Equal("/bin/true", results[0].Path)
+
+	ClearHookResults(cid)
+	assert.NoFileExists(filepath.Join(dir, cid+".json"))
+	assert.Empty(HookResults(cid))
+}
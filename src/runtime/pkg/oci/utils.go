@@ -86,12 +86,32 @@ type FactoryConfig struct {
 	// TemplatePath specifies the path of template.
 	TemplatePath string
 
+	// TemplateStorePath specifies the path of the on disk store of
+	// templates. Templates are not persisted if empty.
+	TemplateStorePath string
+
 	// VMCacheEndpoint specifies the endpoint of transport VM from the VM cache server to runtime.
 	VMCacheEndpoint string
 
+	// VMCacheMemoryBudget specifies the memory, in MiB, all the VMs
+	// cached by VMCache can use. 0 means no limit.
+	VMCacheMemoryBudget uint64
+
 	// VMCacheNumber specifies the the number of caches of VMCache.
 	VMCacheNumber uint
 
+	// VMCacheMaxNumber specifies the number of VMs a VMCache pool can
+	// grow to on demand.
+	VMCacheMaxNumber uint
+
+	// VMCacheMaxPools specifies the number of VM configs VMCache keeps
+	// VMs for.
+	VMCacheMaxPools uint
+
+	// VMCacheIdleTimeout specifies the time, in seconds, after which an
+	// unused VM cached by VMCache is destroyed. 0 means never.
+	VMCacheIdleTimeout uint
+
 	// Template enables VM templating support in VM factory.
 	Template bool
 }
@@ -118,6 +138,10 @@ type RuntimeConfig struct {
 	//the container network interface
 	InterNetworkModel vc.NetInterworkingModel
 
+	//passt binary used by the passt internetworking model, looked up
+	//in PATH if empty
+	PasstPath string
+
 	//Determines how VFIO devices should be presented to the
 	//container
 	VfioMode config.VFIOModeType
@@ -154,6 +178,13 @@ type RuntimeConfig struct {
 
 	// Offload the CRI image management service to the Kata agent.
 	ServiceOffload bool
+
+	// Timeout and failure policy of the guest hooks, the hooks
+	// themselves come from the sandbox annotations.
+	GuestHooks vc.GuestHooksConfig
+
+	// Host executables the guest hooks annotation is allowed to use.
+	GuestHookPathList []string
 }
 
 // AddKernelParam allows the addition of new kernel parameters to an existing
@@ -343,6 +374,7 @@ func networkConfig(ocispec specs.Spec, config RuntimeConfig) (vc.NetworkConfig,
 		}
 	}
 	netConf.InterworkingModel = config.InterNetworkModel
+	netConf.PasstPath = config.PasstPath
 	netConf.DisableNewNetwork = config.DisableNewNetNs
 
 	return netConf, nil
@@ -496,6 +528,12 @@ func addHypervisorConfigOverrides(ocispec specs.Spec, config *vc.SandboxConfig,
 		return err
 	}
 
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.EnableVTPM).setBool(func(enableVTPM bool) {
+		config.HypervisorConfig.EnableVTPM = enableVTPM
+	}); err != nil {
+		return err
+	}
+
 	if err := newAnnotationConfiguration(ocispec, vcAnnotations.PCIeRootPort).setUint(func(pcieRootPort uint64) {
 		config.HypervisorConfig.PCIeRootPort = uint32(pcieRootPort)
 	}); err != nil {
@@ -663,6 +701,12 @@ func addHypervisorCPUOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConfig) e
 		return err
 	}
 
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.EnableNUMA).setBool(func(enableNUMA bool) {
+		sbConfig.HypervisorConfig.EnableNUMA = enableNUMA
+	}); err != nil {
+		return err
+	}
+
 	return newAnnotationConfiguration(ocispec, vcAnnotations.DefaultMaxVCPUs).setUintWithCheck(func(maxVCPUs uint64) error {
 		max := uint32(maxVCPUs)
 
@@ -711,6 +755,16 @@ func addHypervisorBlockOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConfig)
 		}
 	}
 
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.DrivePoolSize).setUintWithCheck(func(drivePoolSize uint64) error {
+		if drivePoolSize > vc.MaxDrivePoolSize {
+			return fmt.Errorf("Drive pool size %d specified in annotation %s is greater than the maximum %d", drivePoolSize, vcAnnotations.DrivePoolSize, vc.MaxDrivePoolSize)
+		}
+		sbConfig.HypervisorConfig.DrivePoolSize = uint32(drivePoolSize)
+		return nil
+	}); err != nil {
+		return err
+	}
+
 	if err := newAnnotationConfiguration(ocispec, vcAnnotations.DisableBlockDeviceUse).setBool(func(disableBlockDeviceUse bool) {
 		sbConfig.HypervisorConfig.DisableBlockDeviceUse = disableBlockDeviceUse
 	}); err != nil {
@@ -819,6 +873,36 @@ func addHypervisporNetworkOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConf
 	})
 }
 
+// ExperimentalFeatures returns the experimental features enabled for the
+// sandbox of ocispec: the ones of the configuration, along with the ones of
+// the experimental annotation. Like the hypervisor annotations, the
+// experimental annotation must be allowed by enable_annotations, by its base
+// name "experimental".
+func ExperimentalFeatures(ocispec specs.Spec, runtime RuntimeConfig) ([]exp.Feature, error) {
+	features := append([]exp.Feature{}, runtime.Experimental...)
+
+	value, ok := ocispec.Annotations[vcAnnotations.Experimental]
+	if !ok {
+		return features, nil
+	}
+
+	if !checkAnnotationNameIsValid(runtime.HypervisorConfig.EnableAnnotations, vcAnnotations.Experimental, vcAnnotations.KataAnnotationRuntimePrefix) {
+		return nil, fmt.Errorf("annotation %v is not enabled", vcAnnotations.Experimental)
+	}
+
+	for _, f := range strings.Fields(value) {
+		feature := exp.Get(f)
+		if feature == nil {
+			return nil, fmt.Errorf("Unsupported experimental feature %s specified in annotation %v", f, vcAnnotations.Experimental)
+		}
+		if !exp.Enabled(features, f) {
+			features = append(features, *feature)
+		}
+	}
+
+	return features, nil
+}
+
 func addRuntimeConfigOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConfig, runtime RuntimeConfig) error {
 
 	if err := newAnnotationConfiguration(ocispec, vcAnnotations.DisableGuestSeccomp).setBool(func(disableGuestSeccomp bool) {
@@ -833,17 +917,12 @@ func addRuntimeConfigOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConfig, r
 		return err
 	}
 
-	if value, ok := ocispec.Annotations[vcAnnotations.Experimental]; ok {
-		features := strings.Split(value, " ")
-		sbConfig.Experimental = []exp.Feature{}
-
-		for _, f := range features {
-			feature := exp.Get(f)
-			if feature == nil {
-				return fmt.Errorf("Unsupported experimental feature %s specified in annotation %v", f, vcAnnotations.Experimental)
-			}
-			sbConfig.Experimental = append(sbConfig.Experimental, *feature)
+	if _, ok := ocispec.Annotations[vcAnnotations.Experimental]; ok {
+		features, err := ExperimentalFeatures(ocispec, runtime)
+		if err != nil {
+			return err
 		}
+		sbConfig.Experimental = features
 	}
 
 	if err := newAnnotationConfiguration(ocispec, vcAnnotations.DisableNewNetNs).setBool(func(disableNewNetNs bool) {
@@ -868,6 +947,44 @@ func addRuntimeConfigOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConfig, r
 		}
 	}
 
+	return addGuestHooksOverrides(ocispec, sbConfig, runtime)
+}
+
+func addGuestHooksOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConfig, runtime RuntimeConfig) error {
+	if value, ok := ocispec.Annotations[vcAnnotations.GuestHooks]; ok {
+		if !checkAnnotationNameIsValid(runtime.HypervisorConfig.EnableAnnotations, vcAnnotations.GuestHooks, vcAnnotations.KataAnnotationRuntimePrefix) {
+			return fmt.Errorf("annotation %v is not enabled", vcAnnotations.GuestHooks)
+		}
+
+		var hooks specs.Hooks
+		if err := json.Unmarshal([]byte(value), &hooks); err != nil {
+			return fmt.Errorf("Error parsing annotation %v: %v", vcAnnotations.GuestHooks, err)
+		}
+
+		for _, stage := range [][]specs.Hook{hooks.Prestart, hooks.CreateRuntime, hooks.CreateContainer,
+			hooks.StartContainer, hooks.Poststart, hooks.Poststop} {
+			for _, h := range stage {
+				if !checkPathIsInGlobs(runtime.GuestHookPathList, h.Path) {
+					return fmt.Errorf("guest hook %v required from annotation is not valid", h.Path)
+				}
+			}
+		}
+
+		sbConfig.GuestHooks.Hooks = &hooks
+	}
+
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.GuestHookTimeout).setUint(func(timeout uint64) {
+		sbConfig.GuestHooks.Timeout = int(timeout)
+	}); err != nil {
+		return err
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.GuestHookDeliveryFailurePolicy]; ok {
+		if err := sbConfig.GuestHooks.DeliveryFailurePolicy.Set(value); err != nil {
+			return fmt.Errorf("%v in annotation %s", err, vcAnnotations.GuestHookDeliveryFailurePolicy)
+		}
+	}
+
 	return nil
 }
 
@@ -955,6 +1072,8 @@ func SandboxConfig(ocispec specs.Spec, runtime RuntimeConfig, bundlePath, cid st
 
 		Experimental: runtime.Experimental,
 
+		GuestHooks: runtime.GuestHooks,
+
 		ServiceOffload: runtime.ServiceOffload,
 	}
 
@@ -984,6 +1103,47 @@ func SandboxConfig(ocispec specs.Spec, runtime RuntimeConfig, bundlePath, cid st
 	return sandboxConfig, nil
 }
 
+// ApplyAnnotations returns the runtime configuration overridden by the
+// annotations, as a sandbox created with them would use it.
+func ApplyAnnotations(runtime RuntimeConfig, annotations map[string]string) (RuntimeConfig, error) {
+	ocispec := specs.Spec{Annotations: annotations}
+
+	sandboxConfig := vc.SandboxConfig{
+		HypervisorConfig: runtime.HypervisorConfig,
+		AgentConfig:      runtime.AgentConfig,
+		NetworkConfig: vc.NetworkConfig{
+			InterworkingModel: runtime.InterNetworkModel,
+			DisableNewNetwork: runtime.DisableNewNetNs,
+		},
+		Annotations:         map[string]string{},
+		VfioMode:            runtime.VfioMode,
+		SandboxCgroupOnly:   runtime.SandboxCgroupOnly,
+		DisableGuestSeccomp: runtime.DisableGuestSeccomp,
+		Experimental:        runtime.Experimental,
+	}
+
+	if err := addAnnotations(ocispec, &sandboxConfig, runtime); err != nil {
+		return RuntimeConfig{}, err
+	}
+
+	hc := &sandboxConfig.HypervisorConfig
+	if err := hc.AddCustomAssets(sandboxConfig.Annotations); err != nil {
+		return RuntimeConfig{}, err
+	}
+	hc.ApplyCustomAssets()
+
+	runtime.HypervisorConfig = sandboxConfig.HypervisorConfig
+	runtime.AgentConfig = sandboxConfig.AgentConfig
+	runtime.InterNetworkModel = sandboxConfig.NetworkConfig.InterworkingModel
+	runtime.DisableNewNetNs = sandboxConfig.NetworkConfig.DisableNewNetwork
+	runtime.VfioMode = sandboxConfig.VfioMode
+	runtime.SandboxCgroupOnly = sandboxConfig.SandboxCgroupOnly
+	runtime.DisableGuestSeccomp = sandboxConfig.DisableGuestSeccomp
+	runtime.Experimental = sandboxConfig.Experimental
+
+	return runtime, nil
+}
+
 // ContainerConfig converts an OCI compatible runtime configuration
 // file to a virtcontainers container configuration structure.
 func ContainerConfig(ocispec specs.Spec, bundlePath, cid string, detach bool) (vc.ContainerConfig, error) {
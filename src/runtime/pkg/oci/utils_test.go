@@ -23,6 +23,7 @@ import (
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
 	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	dockerAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations/dockershim"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
@@ -648,6 +649,7 @@ func TestAddHypervisorAnnotations(t *testing.T) {
 	ocispec.Annotations[vcAnnotations.BlockDeviceCacheSet] = "true"
 	ocispec.Annotations[vcAnnotations.BlockDeviceCacheDirect] = "true"
 	ocispec.Annotations[vcAnnotations.BlockDeviceCacheNoflush] = "true"
+	ocispec.Annotations[vcAnnotations.DrivePoolSize] = "16"
 	ocispec.Annotations[vcAnnotations.SharedFS] = "virtio-fs"
 	ocispec.Annotations[vcAnnotations.VirtioFSDaemon] = "/bin/false"
 	ocispec.Annotations[vcAnnotations.VirtioFSCache] = "/home/cache"
@@ -664,6 +666,8 @@ func TestAddHypervisorAnnotations(t *testing.T) {
 	ocispec.Annotations[vcAnnotations.IOMMUPlatform] = "true"
 	ocispec.Annotations[vcAnnotations.SGXEPC] = "64Mi"
 	ocispec.Annotations[vcAnnotations.UseLegacySerial] = "true"
+	ocispec.Annotations[vcAnnotations.EnableNUMA] = "true"
+	ocispec.Annotations[vcAnnotations.EnableVTPM] = "true"
 	// 10Mbit
 	ocispec.Annotations[vcAnnotations.RxRateLimiterMaxRate] = "10000000"
 	ocispec.Annotations[vcAnnotations.TxRateLimiterMaxRate] = "10000000"
@@ -686,6 +690,7 @@ func TestAddHypervisorAnnotations(t *testing.T) {
 	assert.Equal(config.HypervisorConfig.BlockDeviceCacheSet, true)
 	assert.Equal(config.HypervisorConfig.BlockDeviceCacheDirect, true)
 	assert.Equal(config.HypervisorConfig.BlockDeviceCacheNoflush, true)
+	assert.Equal(config.HypervisorConfig.DrivePoolSize, uint32(16))
 	assert.Equal(config.HypervisorConfig.SharedFS, "virtio-fs")
 	assert.Equal(config.HypervisorConfig.VirtioFSDaemon, "/bin/false")
 	assert.Equal(config.HypervisorConfig.VirtioFSCache, "/home/cache")
@@ -702,6 +707,8 @@ func TestAddHypervisorAnnotations(t *testing.T) {
 	assert.Equal(config.HypervisorConfig.IOMMUPlatform, true)
 	assert.Equal(config.HypervisorConfig.SGXEPCSize, int64(67108864))
 	assert.Equal(config.HypervisorConfig.LegacySerial, true)
+	assert.Equal(config.HypervisorConfig.EnableNUMA, true)
+	assert.Equal(config.HypervisorConfig.EnableVTPM, true)
 	assert.Equal(config.HypervisorConfig.RxRateLimiterMaxRate, uint64(10000000))
 	assert.Equal(config.HypervisorConfig.TxRateLimiterMaxRate, uint64(10000000))
 
@@ -719,6 +726,16 @@ func TestAddHypervisorAnnotations(t *testing.T) {
 	err = addAnnotations(ocispec, &config, runtimeConfig)
 	assert.Error(err)
 
+	// the drive pool size is bounded, and not truncated
+	ocispec.Annotations[vcAnnotations.DefaultMaxVCPUs] = "1"
+	for _, size := range []uint64{vc.MaxDrivePoolSize + 1, 1<<32 + 16} {
+		ocispec.Annotations[vcAnnotations.DrivePoolSize] = fmt.Sprintf("%d", size)
+		err = addAnnotations(ocispec, &config, runtimeConfig)
+		assert.Error(err)
+		assert.Contains(err.Error(), vcAnnotations.DrivePoolSize)
+	}
+	ocispec.Annotations[vcAnnotations.DrivePoolSize] = "16"
+
 	ocispec.Annotations[vcAnnotations.DefaultMaxVCPUs] = "1"
 	ocispec.Annotations[vcAnnotations.DefaultMemory] = fmt.Sprintf("%d", vc.MinHypervisorMemory+1)
 	assert.Error(err)
@@ -816,6 +833,136 @@ func TestAddRuntimeAnnotations(t *testing.T) {
 	assert.Equal(config.NetworkConfig.InterworkingModel, vc.NetXConnectMacVtapModel)
 }
 
+func TestExperimentalFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	ocispec := specs.Spec{
+		Annotations: make(map[string]string),
+	}
+
+	freeze := *exp.Get(exp.SandboxFreeze)
+	snapshot := *exp.Get(exp.VolumeSnapshot)
+
+	runtimeConfig := RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		Experimental:   []exp.Feature{freeze},
+	}
+
+	features, err := ExperimentalFeatures(ocispec, runtimeConfig)
+	assert.NoError(err)
+	assert.Equal([]exp.Feature{freeze}, features)
+
+	// the annotation must be enabled
+	ocispec.Annotations[vcAnnotations.Experimental] = "volume_snapshot  sandbox_freeze"
+	_, err = ExperimentalFeatures(ocispec, runtimeConfig)
+	assert.EqualError(err, "annotation io.katacontainers.config.runtime.experimental is not enabled")
+
+	config := vc.SandboxConfig{
+		Annotations: make(map[string]string),
+	}
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+
+	runtimeConfig.HypervisorConfig.EnableAnnotations = []string{"experimental"}
+	features, err = ExperimentalFeatures(ocispec, runtimeConfig)
+	assert.NoError(err)
+	assert.Equal([]exp.Feature{freeze, snapshot}, features)
+
+	assert.NoError(addAnnotations(ocispec, &config, runtimeConfig))
+	assert.Equal([]exp.Feature{freeze, snapshot}, config.Experimental)
+
+	ocispec.Annotations[vcAnnotations.Experimental] = "unknown"
+	_, err = ExperimentalFeatures(ocispec, runtimeConfig)
+	assert.Error(err)
+}
+
+func TestGuestHooksAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	hook := filepath.Join(t.TempDir(), "gpu-setup")
+	assert.NoError(os.WriteFile(hook, nil, 0700))
+
+	ocispec := specs.Spec{
+		Annotations: map[string]string{
+			vcAnnotations.GuestHooks:                     fmt.Sprintf(`{"prestart": [{"path": "%s", "timeout": 5}]}`, hook),
+			vcAnnotations.GuestHookTimeout:               "20",
+			vcAnnotations.GuestHookDeliveryFailurePolicy: "ignore",
+		},
+	}
+
+	runtimeConfig := RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		GuestHooks:     vc.GuestHooksConfig{Timeout: 10},
+	}
+	config := vc.SandboxConfig{
+		Annotations: make(map[string]string),
+		GuestHooks:  runtimeConfig.GuestHooks,
+	}
+
+	// the annotation must be enabled, and its paths valid
+	err := addAnnotations(ocispec, &config, runtimeConfig)
+	assert.EqualError(err, "annotation io.katacontainers.config.runtime.guest_hooks is not enabled")
+
+	runtimeConfig.HypervisorConfig.EnableAnnotations = []string{"guest_hooks"}
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+
+	runtimeConfig.GuestHookPathList = []string{filepath.Join(filepath.Dir(hook), "*")}
+	assert.NoError(addAnnotations(ocispec, &config, runtimeConfig))
+
+	timeout := 5
+	assert.Equal(vc.GuestHooksConfig{
+		Hooks: &specs.Hooks{
+			Prestart: []specs.Hook{{Path: hook, Timeout: &timeout}},
+		},
+		DeliveryFailurePolicy: vc.GuestHookIgnore,
+		Timeout:               20,
+	}, config.GuestHooks)
+
+	ocispec.Annotations[vcAnnotations.GuestHookDeliveryFailurePolicy] = "retry"
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+
+	ocispec.Annotations[vcAnnotations.GuestHooks] = "prestart"
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+}
+
+func TestApplyAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	kernel := filepath.Join(t.TempDir(), "vmlinux")
+	assert.NoError(os.WriteFile(kernel, []byte(""), fileMode))
+
+	runtimeConfig := RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		HypervisorConfig: vc.HypervisorConfig{
+			KernelPath:        "/usr/share/kata-containers/vmlinux",
+			EnableAnnotations: []string{".*"},
+		},
+		InterNetworkModel: vc.NetXConnectTCFilterModel,
+	}
+
+	annotations := map[string]string{
+		vcAnnotations.KernelPath:          kernel,
+		vcAnnotations.DisableImageNvdimm:  "true",
+		vcAnnotations.DisableGuestSeccomp: "true",
+		vcAnnotations.InterNetworkModel:   "macvtap",
+		vcAnnotations.AgentTrace:          "true",
+	}
+
+	effective, err := ApplyAnnotations(runtimeConfig, annotations)
+	assert.NoError(err)
+	assert.Equal(kernel, effective.HypervisorConfig.KernelPath)
+	assert.True(effective.HypervisorConfig.DisableImageNvdimm)
+	assert.True(effective.DisableGuestSeccomp)
+	assert.True(effective.AgentConfig.Trace)
+	assert.Equal(vc.NetXConnectMacVtapModel, effective.InterNetworkModel)
+
+	// the runtime configuration is not modified
+	assert.False(runtimeConfig.HypervisorConfig.DisableImageNvdimm)
+
+	runtimeConfig.HypervisorConfig.EnableAnnotations = nil
+	_, err = ApplyAnnotations(runtimeConfig, annotations)
+	assert.Error(err)
+}
+
 func TestRegexpContains(t *testing.T) {
 	assert := assert.New(t)
 
@@ -30,6 +30,7 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 type GrpcVMConfig struct {
 	Data                 []byte   `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
 	AgentConfig          []byte   `protobuf:"bytes,2,opt,name=AgentConfig,proto3" json:"AgentConfig,omitempty"`
+	Pools                bool     `protobuf:"varint,3,opt,name=Pools,proto3" json:"Pools,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -82,6 +83,13 @@ func (m *GrpcVMConfig) GetAgentConfig() []byte {
 	return nil
 }
 
+func (m *GrpcVMConfig) GetPools() bool {
+	if m != nil {
+		return m.Pools
+	}
+	return false
+}
+
 type GrpcVM struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Hypervisor           []byte   `protobuf:"bytes,2,opt,name=hypervisor,proto3" json:"hypervisor,omitempty"`
@@ -178,11 +186,12 @@ func (m *GrpcVM) GetCpuDelta() uint32 {
 }
 
 type GrpcStatus struct {
-	Pid                  int64           `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
-	Vmstatus             []*GrpcVMStatus `protobuf:"bytes,2,rep,name=vmstatus,proto3" json:"vmstatus,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Pid                  int64             `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Vmstatus             []*GrpcVMStatus   `protobuf:"bytes,2,rep,name=vmstatus,proto3" json:"vmstatus,omitempty"`
+	Pools                []*GrpcPoolStatus `protobuf:"bytes,3,rep,name=pools,proto3" json:"pools,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *GrpcStatus) Reset()         { *m = GrpcStatus{} }
@@ -232,6 +241,13 @@ func (m *GrpcStatus) GetVmstatus() []*GrpcVMStatus {
 	return nil
 }
 
+func (m *GrpcStatus) GetPools() []*GrpcPoolStatus {
+	if m != nil {
+		return m.Pools
+	}
+	return nil
+}
+
 type GrpcVMStatus struct {
 	Pid                  int64    `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
 	Cpu                  uint32   `protobuf:"varint,2,opt,name=cpu,proto3" json:"cpu,omitempty"`
@@ -295,41 +311,160 @@ func (m *GrpcVMStatus) GetMemory() uint32 {
 	return 0
 }
 
+type GrpcPoolStatus struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Hypervisor           string   `protobuf:"bytes,2,opt,name=hypervisor,proto3" json:"hypervisor,omitempty"`
+	Kernel               string   `protobuf:"bytes,3,opt,name=kernel,proto3" json:"kernel,omitempty"`
+	Image                string   `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	Ready                uint32   `protobuf:"varint,5,opt,name=ready,proto3" json:"ready,omitempty"`
+	Target               uint32   `protobuf:"varint,6,opt,name=target,proto3" json:"target,omitempty"`
+	Hits                 uint64   `protobuf:"varint,7,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses               uint64   `protobuf:"varint,8,opt,name=misses,proto3" json:"misses,omitempty"`
+	Expired              uint64   `protobuf:"varint,9,opt,name=expired,proto3" json:"expired,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GrpcPoolStatus) Reset()         { *m = GrpcPoolStatus{} }
+func (m *GrpcPoolStatus) String() string { return proto.CompactTextString(m) }
+func (*GrpcPoolStatus) ProtoMessage()    {}
+func (*GrpcPoolStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{4}
+}
+func (m *GrpcPoolStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GrpcPoolStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GrpcPoolStatus.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GrpcPoolStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GrpcPoolStatus.Merge(m, src)
+}
+func (m *GrpcPoolStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *GrpcPoolStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_GrpcPoolStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GrpcPoolStatus proto.InternalMessageInfo
+
+func (m *GrpcPoolStatus) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *GrpcPoolStatus) GetHypervisor() string {
+	if m != nil {
+		return m.Hypervisor
+	}
+	return ""
+}
+
+func (m *GrpcPoolStatus) GetKernel() string {
+	if m != nil {
+		return m.Kernel
+	}
+	return ""
+}
+
+func (m *GrpcPoolStatus) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *GrpcPoolStatus) GetReady() uint32 {
+	if m != nil {
+		return m.Ready
+	}
+	return 0
+}
+
+func (m *GrpcPoolStatus) GetTarget() uint32 {
+	if m != nil {
+		return m.Target
+	}
+	return 0
+}
+
+func (m *GrpcPoolStatus) GetHits() uint64 {
+	if m != nil {
+		return m.Hits
+	}
+	return 0
+}
+
+func (m *GrpcPoolStatus) GetMisses() uint64 {
+	if m != nil {
+		return m.Misses
+	}
+	return 0
+}
+
+func (m *GrpcPoolStatus) GetExpired() uint64 {
+	if m != nil {
+		return m.Expired
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*GrpcVMConfig)(nil), "cache.GrpcVMConfig")
 	proto.RegisterType((*GrpcVM)(nil), "cache.GrpcVM")
 	proto.RegisterType((*GrpcStatus)(nil), "cache.GrpcStatus")
 	proto.RegisterType((*GrpcVMStatus)(nil), "cache.GrpcVMStatus")
+	proto.RegisterType((*GrpcPoolStatus)(nil), "cache.GrpcPoolStatus")
 }
 
 func init() { proto.RegisterFile("cache.proto", fileDescriptor_5fca3b110c9bbf3a) }
 
 var fileDescriptor_5fca3b110c9bbf3a = []byte{
-	// 374 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0xcd, 0x0a, 0xda, 0x40,
-	0x10, 0x66, 0x13, 0x4d, 0x75, 0x8c, 0xa5, 0xdd, 0x82, 0x2c, 0x16, 0x42, 0xc8, 0x29, 0xa7, 0x08,
-	0x4a, 0x7b, 0xaf, 0x5a, 0x84, 0x52, 0x69, 0x1b, 0xa9, 0xf7, 0x18, 0xd7, 0x18, 0x30, 0xee, 0x92,
-	0x6c, 0xa4, 0x79, 0xb1, 0x3e, 0x43, 0x8f, 0x7d, 0x84, 0xe2, 0xa1, 0xcf, 0x51, 0x76, 0xb3, 0x86,
-	0x08, 0xcd, 0x6d, 0x66, 0xbe, 0x9f, 0xfd, 0x76, 0x06, 0x46, 0x71, 0x14, 0x9f, 0x69, 0xc0, 0x73,
-	0x26, 0x18, 0xee, 0xab, 0x66, 0xfa, 0x36, 0x61, 0x2c, 0xb9, 0xd0, 0x99, 0x1a, 0x1e, 0xca, 0xd3,
-	0x8c, 0x66, 0x5c, 0x54, 0x35, 0xc7, 0x5b, 0x83, 0xbd, 0xc9, 0x79, 0xbc, 0xdf, 0xae, 0xd8, 0xf5,
-	0x94, 0x26, 0x18, 0x43, 0x6f, 0x1d, 0x89, 0x88, 0x20, 0x17, 0xf9, 0x76, 0xa8, 0x6a, 0xec, 0xc2,
-	0xe8, 0x43, 0x42, 0xaf, 0xa2, 0xa6, 0x10, 0x43, 0x41, 0xed, 0x91, 0xf7, 0x13, 0x81, 0x55, 0xdb,
-	0xe0, 0x97, 0x60, 0xa4, 0x47, 0x25, 0x1f, 0x86, 0x46, 0x7a, 0xc4, 0x0e, 0xc0, 0xb9, 0xe2, 0x34,
-	0xbf, 0xa5, 0x05, 0xcb, 0xb5, 0xb6, 0x35, 0xc1, 0x53, 0x18, 0xf0, 0x9c, 0xfd, 0xa8, 0xbe, 0xa6,
-	0x47, 0x62, 0xba, 0xc8, 0x37, 0xc3, 0xa6, 0x6f, 0xb0, 0xef, 0xe1, 0x67, 0xd2, 0x53, 0x8e, 0x4d,
-	0x8f, 0x5f, 0x81, 0x19, 0xf3, 0x92, 0xf4, 0x5d, 0xe4, 0x8f, 0x43, 0x59, 0xe2, 0x09, 0x58, 0x19,
-	0xcd, 0x58, 0x5e, 0x11, 0x4b, 0x0d, 0x75, 0x27, 0x5d, 0x62, 0x5e, 0xae, 0xe9, 0x45, 0x44, 0xe4,
-	0x85, 0x42, 0x9a, 0xde, 0xfb, 0x02, 0x20, 0x73, 0xef, 0x44, 0x24, 0xca, 0x42, 0x7a, 0x72, 0x1d,
-	0xde, 0x0c, 0x65, 0x89, 0x67, 0x30, 0xb8, 0x65, 0x85, 0x42, 0x89, 0xe1, 0x9a, 0xfe, 0x68, 0xfe,
-	0x26, 0xa8, 0x57, 0x5c, 0x7f, 0xb7, 0x16, 0x86, 0x0d, 0xc9, 0xfb, 0xf4, 0xd8, 0x67, 0xa7, 0xa5,
-	0x0e, 0x6e, 0xfc, 0x2f, 0xb8, 0xd9, 0x0e, 0x3e, 0xff, 0x8b, 0xc0, 0x5e, 0xc9, 0xc7, 0x76, 0x72,
-	0x59, 0x31, 0xc5, 0xef, 0xc0, 0xd2, 0x67, 0x9a, 0x04, 0xf5, 0x51, 0x83, 0xc7, 0x51, 0x83, 0x8f,
-	0xf2, 0xa8, 0xd3, 0xe7, 0x74, 0x9a, 0x3c, 0x87, 0xe1, 0x86, 0x8a, 0x65, 0x54, 0xd0, 0xfd, 0xb6,
-	0x53, 0x39, 0x7e, 0x52, 0xe2, 0x05, 0x58, 0xfa, 0x07, 0x5d, 0x82, 0xd7, 0x2d, 0x81, 0xa6, 0xbe,
-	0x87, 0xde, 0xb7, 0x32, 0x15, 0x9d, 0x92, 0x8e, 0xf9, 0xd2, 0xfe, 0x75, 0x77, 0xd0, 0xef, 0xbb,
-	0x83, 0xfe, 0xdc, 0x1d, 0x74, 0xb0, 0x14, 0xba, 0xf8, 0x17, 0x00, 0x00, 0xff, 0xff, 0x40, 0x5e,
-	0x19, 0x6e, 0xcc, 0x02, 0x00, 0x00,
+	// 493 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x5f, 0x6b, 0xdb, 0x3e,
+	0x14, 0x45, 0x71, 0xe2, 0x26, 0x37, 0x69, 0xf9, 0xfd, 0xb4, 0x2d, 0x88, 0x0c, 0x82, 0xf1, 0x53,
+	0x60, 0xe0, 0x40, 0xcb, 0xf6, 0xbe, 0xb6, 0xa3, 0x30, 0x56, 0xe8, 0x54, 0xd6, 0x87, 0xbd, 0xb9,
+	0xb6, 0xea, 0x88, 0xc5, 0x91, 0x90, 0xe5, 0x52, 0xc3, 0x3e, 0xd7, 0x3e, 0xc3, 0x1e, 0xf7, 0x11,
+	0x46, 0xf6, 0x45, 0x86, 0xfe, 0xc4, 0x38, 0xd0, 0xc0, 0xde, 0x74, 0xce, 0x3d, 0x3a, 0xd2, 0xbd,
+	0xe7, 0xc2, 0x38, 0x4b, 0xb3, 0x15, 0x4b, 0xa4, 0x12, 0x5a, 0xe0, 0x81, 0x05, 0xb3, 0xd7, 0x85,
+	0x10, 0xc5, 0x9a, 0x2d, 0x2d, 0x79, 0x5f, 0x3f, 0x2c, 0x59, 0x29, 0x75, 0xe3, 0x34, 0xf1, 0x57,
+	0x98, 0x5c, 0x29, 0x99, 0xdd, 0x5d, 0x5f, 0x88, 0xcd, 0x03, 0x2f, 0x30, 0x86, 0xfe, 0x65, 0xaa,
+	0x53, 0x82, 0x22, 0xb4, 0x98, 0x50, 0x7b, 0xc6, 0x11, 0x8c, 0xdf, 0x17, 0x6c, 0xa3, 0x9d, 0x84,
+	0xf4, 0x6c, 0xa9, 0x4b, 0xe1, 0x97, 0x30, 0xb8, 0x11, 0x62, 0x5d, 0x91, 0x20, 0x42, 0x8b, 0x21,
+	0x75, 0x20, 0xfe, 0x81, 0x20, 0x74, 0xe6, 0xf8, 0x04, 0x7a, 0x3c, 0xb7, 0xa6, 0x23, 0xda, 0xe3,
+	0x39, 0x9e, 0x03, 0xac, 0x1a, 0xc9, 0xd4, 0x23, 0xaf, 0x84, 0xf2, 0x8e, 0x1d, 0x06, 0xcf, 0x60,
+	0x28, 0x95, 0x78, 0x6a, 0x6e, 0x78, 0x6e, 0x3d, 0x03, 0xda, 0xe2, 0xb6, 0xf6, 0x85, 0x7e, 0x22,
+	0x7d, 0xeb, 0xd8, 0x62, 0xfc, 0x1f, 0x04, 0x99, 0xac, 0xc9, 0x20, 0x42, 0x8b, 0x63, 0x6a, 0x8e,
+	0x78, 0x0a, 0x61, 0xc9, 0x4a, 0xa1, 0x1a, 0x12, 0x5a, 0xd2, 0x23, 0xe3, 0x92, 0xc9, 0xfa, 0x92,
+	0xad, 0x75, 0x4a, 0x8e, 0x6c, 0xa5, 0xc5, 0xf1, 0x77, 0x00, 0xf3, 0xef, 0x5b, 0x9d, 0xea, 0xba,
+	0x32, 0x9e, 0xd2, 0x7f, 0x3e, 0xa0, 0xe6, 0x88, 0x97, 0x30, 0x7c, 0x2c, 0x2b, 0x5b, 0x25, 0xbd,
+	0x28, 0x58, 0x8c, 0x4f, 0x5f, 0x24, 0x6e, 0xf0, 0xae, 0x5d, 0x77, 0x91, 0xb6, 0x22, 0xfc, 0x06,
+	0x06, 0xd2, 0xcf, 0xc7, 0xa8, 0x5f, 0x75, 0xd4, 0x66, 0x54, 0x5e, 0xef, 0x34, 0xf1, 0xc7, 0x5d,
+	0x24, 0x07, 0xdf, 0xf7, 0x5d, 0xf6, 0x9e, 0xeb, 0x32, 0xe8, 0x76, 0x19, 0x6f, 0x11, 0x9c, 0xec,
+	0xbf, 0xf2, 0x0f, 0x51, 0x8c, 0xf6, 0xa2, 0x98, 0x42, 0xf8, 0x8d, 0xa9, 0x0d, 0x5b, 0x5b, 0xeb,
+	0x11, 0xf5, 0xc8, 0x64, 0xce, 0xcb, 0xb4, 0x60, 0x3e, 0x03, 0x07, 0x0c, 0xab, 0x58, 0x9a, 0x37,
+	0x3e, 0x02, 0x07, 0x8c, 0x87, 0x4e, 0x55, 0xc1, 0xf4, 0x2e, 0x04, 0x87, 0xcc, 0xb6, 0xad, 0xb8,
+	0xae, 0x6c, 0x00, 0x7d, 0x6a, 0xcf, 0xb6, 0x15, 0x5e, 0x55, 0xac, 0x22, 0x43, 0xcb, 0x7a, 0x84,
+	0x09, 0x1c, 0xb1, 0x27, 0xc9, 0x15, 0xcb, 0xc9, 0xc8, 0x16, 0x76, 0xf0, 0xf4, 0x0f, 0x82, 0xc9,
+	0x85, 0x19, 0xe8, 0xad, 0xf9, 0x73, 0xc6, 0xf0, 0x5b, 0x08, 0xfd, 0x62, 0x4e, 0x13, 0xb7, 0xfc,
+	0xc9, 0x6e, 0xf9, 0x93, 0x0f, 0x66, 0xf9, 0x67, 0xfb, 0x79, 0x79, 0xf1, 0x12, 0x46, 0x57, 0x4c,
+	0x9f, 0xa7, 0x15, 0xbb, 0xbb, 0xc6, 0xcf, 0x29, 0x66, 0xc7, 0x7b, 0x24, 0x3e, 0x83, 0xd0, 0x0f,
+	0xf5, 0xd0, 0x3b, 0xff, 0x77, 0x2e, 0x78, 0xe9, 0x3b, 0xe8, 0x7f, 0xae, 0xb9, 0x3e, 0x78, 0xe5,
+	0x00, 0x7f, 0x3e, 0xf9, 0xb9, 0x9d, 0xa3, 0x5f, 0xdb, 0x39, 0xfa, 0xbd, 0x9d, 0xa3, 0xfb, 0xd0,
+	0x56, 0xcf, 0xfe, 0x0e, 0x00, 0x5b, 0x30, 0x91, 0x20, 0xf1, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -345,7 +480,7 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type CacheServiceClient interface {
 	Config(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*GrpcVMConfig, error)
-	GetBaseVM(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*GrpcVM, error)
+	GetBaseVM(ctx context.Context, in *GrpcVMConfig, opts ...grpc.CallOption) (*GrpcVM, error)
 	Status(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*GrpcStatus, error)
 	Quit(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*types.Empty, error)
 }
@@ -367,7 +502,7 @@ func (c *cacheServiceClient) Config(ctx context.Context, in *types.Empty, opts .
 	return out, nil
 }
 
-func (c *cacheServiceClient) GetBaseVM(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*GrpcVM, error) {
+func (c *cacheServiceClient) GetBaseVM(ctx context.Context, in *GrpcVMConfig, opts ...grpc.CallOption) (*GrpcVM, error) {
 	out := new(GrpcVM)
 	err := c.cc.Invoke(ctx, "/cache.CacheService/GetBaseVM", in, out, opts...)
 	if err != nil {
@@ -397,7 +532,7 @@ func (c *cacheServiceClient) Quit(ctx context.Context, in *types.Empty, opts ...
 // CacheServiceServer is the server API for CacheService service.
 type CacheServiceServer interface {
 	Config(context.Context, *types.Empty) (*GrpcVMConfig, error)
-	GetBaseVM(context.Context, *types.Empty) (*GrpcVM, error)
+	GetBaseVM(context.Context, *GrpcVMConfig) (*GrpcVM, error)
 	Status(context.Context, *types.Empty) (*GrpcStatus, error)
 	Quit(context.Context, *types.Empty) (*types.Empty, error)
 }
@@ -409,7 +544,7 @@ type UnimplementedCacheServiceServer struct {
 func (*UnimplementedCacheServiceServer) Config(ctx context.Context, req *types.Empty) (*GrpcVMConfig, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Config not implemented")
 }
-func (*UnimplementedCacheServiceServer) GetBaseVM(ctx context.Context, req *types.Empty) (*GrpcVM, error) {
+func (*UnimplementedCacheServiceServer) GetBaseVM(ctx context.Context, req *GrpcVMConfig) (*GrpcVM, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBaseVM not implemented")
 }
 func (*UnimplementedCacheServiceServer) Status(ctx context.Context, req *types.Empty) (*GrpcStatus, error) {
@@ -442,7 +577,7 @@ func _CacheService_Config_Handler(srv interface{}, ctx context.Context, dec func
 }
 
 func _CacheService_GetBaseVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(types.Empty)
+	in := new(GrpcVMConfig)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -454,7 +589,7 @@ func _CacheService_GetBaseVM_Handler(srv interface{}, ctx context.Context, dec f
 		FullMethod: "/cache.CacheService/GetBaseVM",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CacheServiceServer).GetBaseVM(ctx, req.(*types.Empty))
+		return srv.(CacheServiceServer).GetBaseVM(ctx, req.(*GrpcVMConfig))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -544,6 +679,16 @@ func (m *GrpcVMConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Pools {
+		i--
+		if m.Pools {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.AgentConfig) > 0 {
 		i -= len(m.AgentConfig)
 		copy(dAtA[i:], m.AgentConfig)
@@ -653,6 +798,20 @@ func (m *GrpcStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Pools) > 0 {
+		for iNdEx := len(m.Pools) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Pools[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintCache(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.Vmstatus) > 0 {
 		for iNdEx := len(m.Vmstatus) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -717,6 +876,86 @@ func (m *GrpcVMStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *GrpcPoolStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GrpcPoolStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GrpcPoolStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Expired != 0 {
+		i = encodeVarintCache(dAtA, i, uint64(m.Expired))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.Misses != 0 {
+		i = encodeVarintCache(dAtA, i, uint64(m.Misses))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Hits != 0 {
+		i = encodeVarintCache(dAtA, i, uint64(m.Hits))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.Target != 0 {
+		i = encodeVarintCache(dAtA, i, uint64(m.Target))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Ready != 0 {
+		i = encodeVarintCache(dAtA, i, uint64(m.Ready))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Image) > 0 {
+		i -= len(m.Image)
+		copy(dAtA[i:], m.Image)
+		i = encodeVarintCache(dAtA, i, uint64(len(m.Image)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Kernel) > 0 {
+		i -= len(m.Kernel)
+		copy(dAtA[i:], m.Kernel)
+		i = encodeVarintCache(dAtA, i, uint64(len(m.Kernel)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Hypervisor) > 0 {
+		i -= len(m.Hypervisor)
+		copy(dAtA[i:], m.Hypervisor)
+		i = encodeVarintCache(dAtA, i, uint64(len(m.Hypervisor)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintCache(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintCache(dAtA []byte, offset int, v uint64) int {
 	offset -= sovCache(v)
 	base := offset
@@ -742,6 +981,9 @@ func (m *GrpcVMConfig) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovCache(uint64(l))
 	}
+	if m.Pools {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -799,6 +1041,12 @@ func (m *GrpcStatus) Size() (n int) {
 			n += 1 + l + sovCache(uint64(l))
 		}
 	}
+	if len(m.Pools) > 0 {
+		for _, e := range m.Pools {
+			l = e.Size()
+			n += 1 + l + sovCache(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -826,6 +1074,49 @@ func (m *GrpcVMStatus) Size() (n int) {
 	return n
 }
 
+func (m *GrpcPoolStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovCache(uint64(l))
+	}
+	l = len(m.Hypervisor)
+	if l > 0 {
+		n += 1 + l + sovCache(uint64(l))
+	}
+	l = len(m.Kernel)
+	if l > 0 {
+		n += 1 + l + sovCache(uint64(l))
+	}
+	l = len(m.Image)
+	if l > 0 {
+		n += 1 + l + sovCache(uint64(l))
+	}
+	if m.Ready != 0 {
+		n += 1 + sovCache(uint64(m.Ready))
+	}
+	if m.Target != 0 {
+		n += 1 + sovCache(uint64(m.Target))
+	}
+	if m.Hits != 0 {
+		n += 1 + sovCache(uint64(m.Hits))
+	}
+	if m.Misses != 0 {
+		n += 1 + sovCache(uint64(m.Misses))
+	}
+	if m.Expired != 0 {
+		n += 1 + sovCache(uint64(m.Expired))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovCache(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -929,6 +1220,26 @@ func (m *GrpcVMConfig) Unmarshal(dAtA []byte) error {
 				m.AgentConfig = []byte{}
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pools", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Pools = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCache(dAtA[iNdEx:])
@@ -1258,6 +1569,40 @@ func (m *GrpcStatus) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pools", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCache
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCache
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Pools = append(m.Pools, &GrpcPoolStatus{})
+			if err := m.Pools[len(m.Pools)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCache(dAtA[iNdEx:])
@@ -1388,6 +1733,280 @@ func (m *GrpcVMStatus) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *GrpcPoolStatus) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCache
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GrpcPoolStatus: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GrpcPoolStatus: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCache
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCache
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hypervisor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCache
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCache
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hypervisor = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kernel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCache
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCache
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Kernel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Image", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCache
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCache
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Image = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ready", wireType)
+			}
+			m.Ready = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Ready |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			m.Target = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Target |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hits", wireType)
+			}
+			m.Hits = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Hits |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Misses", wireType)
+			}
+			m.Misses = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Misses |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Expired", wireType)
+			}
+			m.Expired = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCache
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Expired |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCache(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCache
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipCache(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
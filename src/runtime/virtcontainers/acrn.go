@@ -9,12 +9,16 @@ package virtcontainers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -25,6 +29,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	hv "github.com/kata-containers/kata-containers/src/runtime/pkg/hypervisors"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/uuid"
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
@@ -40,7 +45,10 @@ var acrnTracingTags = map[string]string{
 
 // AcrnState keeps track of VM UUID, PID.
 type AcrnState struct {
-	PID int
+	UUID string
+	// ApicID is the vCPU taken by getNextApicid for this VM.
+	ApicID string
+	PID    int
 }
 
 // Acrn is an Hypervisor interface implementation for the Linux acrn hypervisor.
@@ -53,11 +61,14 @@ type Acrn struct {
 	acrnConfig Config
 	config     HypervisorConfig
 	state      AcrnState
+	stopped    int32
 }
 
 const (
 	acrnConsoleSocket          = "console.sock"
 	acrnStopSandboxTimeoutSecs = 15
+	acrnMaxVMNameLen           = 15
+	acrnVMNamePrefix           = "sbx-"
 )
 
 // agnostic list of kernel parameters
@@ -130,6 +141,11 @@ func (a *Acrn) getNextApicid() (string, error) {
 		}
 	}
 
+	// The first VM can be created before any VM directory.
+	if err := os.MkdirAll(a.config.VMStorePath, DirMode); err != nil {
+		return "", err
+	}
+
 	currentIdx := prevIdx + 1
 	err = os.WriteFile(fileName, []byte(strconv.Itoa(currentIdx)), defaultFilePerms)
 	if err != nil {
@@ -285,31 +301,36 @@ func (a *Acrn) setConfig(config *HypervisorConfig) error {
 	return nil
 }
 
-// CreateVM is the VM creation
-func (a *Acrn) CreateVM(ctx context.Context, id string, network Network, hypervisorConfig *HypervisorConfig) error {
-	// Save the tracing context
-	a.ctx = ctx
-
-	span, ctx := katatrace.Trace(ctx, a.Logger(), "CreateVM", acrnTracingTags, map[string]string{"sandbox_id": a.id})
-	defer span.End()
-
-	if err := a.setup(ctx, id, hypervisorConfig); err != nil {
-		return err
+// acrnVMName returns the acrn-dm VM name for id. acrn limits VM names to
+// 15 characters, so longer ids (e.g. containerd sandbox ids or VM cache
+// UUIDs) are replaced by a digest of the id.
+func acrnVMName(id string) string {
+	name := acrnVMNamePrefix + id
+	if len(name) <= acrnMaxVMNameLen {
+		return name
 	}
 
+	sum := sha256.Sum256([]byte(id))
+	return acrnVMNamePrefix + hex.EncodeToString(sum[:])[:acrnMaxVMNameLen-len(acrnVMNamePrefix)]
+}
+
+// buildConfig builds the acrn-dm configuration from the hypervisor
+// configuration and the VM state. The UUID and vCPU of the VM are only
+// allocated once, so that restoring a sandbox keeps them.
+func (a *Acrn) buildConfig(ctx context.Context) (Config, error) {
 	memory, err := a.memoryTopology()
 	if err != nil {
-		return err
+		return Config{}, err
 	}
 
 	kernelPath, err := a.config.KernelAssetPath()
 	if err != nil {
-		return err
+		return Config{}, err
 	}
 
 	imagePath, err := a.config.ImageAssetPath()
 	if err != nil {
-		return err
+		return Config{}, err
 	}
 
 	kernel := Kernel{
@@ -320,38 +341,58 @@ func (a *Acrn) CreateVM(ctx context.Context, id string, network Network, hypervi
 
 	devices, err := a.buildDevices(ctx, imagePath)
 	if err != nil {
-		return err
+		return Config{}, err
 	}
 
 	acrnPath, err := a.acrnPath()
 	if err != nil {
-		return err
+		return Config{}, err
 	}
 
 	acrnctlPath, err := a.acrnctlPath()
 	if err != nil {
-		return err
+		return Config{}, err
 	}
 
-	vmName := fmt.Sprintf("sbx-%s", a.id)
-	if len(vmName) > 15 {
-		return fmt.Errorf("VM Name len is %d but ACRN supports max VM name len of 15.", len(vmName))
+	if a.state.UUID == "" {
+		a.state.UUID = uuid.Generate().String()
 	}
 
-	apicID, err := a.getNextApicid()
-	if err != nil {
-		return err
+	if a.state.ApicID == "" {
+		a.state.ApicID, err = a.getNextApicid()
+		if err != nil {
+			return Config{}, err
+		}
 	}
 
-	acrnConfig := Config{
+	return Config{
 		ACPIVirt: true,
 		Path:     acrnPath,
 		CtlPath:  acrnctlPath,
 		Memory:   memory,
 		Devices:  devices,
 		Kernel:   kernel,
-		Name:     vmName,
-		ApicID:   apicID,
+		Name:     acrnVMName(a.id),
+		UUID:     a.state.UUID,
+		ApicID:   a.state.ApicID,
+	}, nil
+}
+
+// CreateVM is the VM creation
+func (a *Acrn) CreateVM(ctx context.Context, id string, network Network, hypervisorConfig *HypervisorConfig) error {
+	// Save the tracing context
+	a.ctx = ctx
+
+	span, ctx := katatrace.Trace(ctx, a.Logger(), "CreateVM", acrnTracingTags, map[string]string{"sandbox_id": a.id})
+	defer span.End()
+
+	if err := a.setup(ctx, id, hypervisorConfig); err != nil {
+		return err
+	}
+
+	acrnConfig, err := a.buildConfig(ctx)
+	if err != nil {
+		return err
 	}
 
 	a.acrnConfig = acrnConfig
@@ -394,7 +435,7 @@ func (a *Acrn) StartVM(ctx context.Context, timeoutSecs int) error {
 	a.Logger().Error("StartVM: LaunchAcrn() function called")
 	PID, strErr, err = LaunchAcrn(a.acrnConfig, virtLog.WithField("subsystem", "acrn-dm"))
 	if err != nil {
-		return fmt.Errorf("%s", strErr)
+		return fmt.Errorf("failed to launch acrn-dm: %v: %s", err, strErr)
 	}
 	a.state.PID = PID
 
@@ -420,24 +461,14 @@ func (a *Acrn) waitVM(ctx context.Context, timeoutSecs int) error {
 	return nil
 }
 
-// StopVM will stop the Sandbox's VM.
-func (a *Acrn) StopVM(ctx context.Context, waitOnly bool) (err error) {
-	span, _ := katatrace.Trace(ctx, a.Logger(), "StopVM", acrnTracingTags, map[string]string{"sandbox_id": a.id})
-	defer span.End()
-
-	a.Logger().Info("Stopping acrn VM")
-
-	defer func() {
-		if err != nil {
-			a.Logger().Info("StopVM failed")
-		} else {
-			a.Logger().Info("acrn VM stopped")
-		}
-	}()
-
+// releaseApicid gives back the vCPU taken by getNextApicid.
+func (a *Acrn) releaseApicid() error {
 	fileName := filepath.Join(a.config.VMStorePath, "cpu_affinity_idx")
 	data, err := os.ReadFile(fileName)
-	if err != nil {
+	if os.IsNotExist(err) {
+		// No vCPU was taken, the VM was not even created.
+		return nil
+	} else if err != nil {
 		a.Logger().Error("Loading cpu affinity index from file failed!")
 		return err
 	}
@@ -455,7 +486,41 @@ func (a *Acrn) StopVM(ctx context.Context, waitOnly bool) (err error) {
 		return err
 	}
 
+	return nil
+}
+
+// StopVM will stop the Sandbox's VM.
+func (a *Acrn) StopVM(ctx context.Context, waitOnly bool) (err error) {
+	span, _ := katatrace.Trace(ctx, a.Logger(), "StopVM", acrnTracingTags, map[string]string{"sandbox_id": a.id})
+	defer span.End()
+
+	a.Logger().Info("Stopping acrn VM")
+	if atomic.LoadInt32(&a.stopped) != 0 {
+		a.Logger().Info("Already stopped")
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			a.Logger().Info("StopVM failed")
+		} else {
+			atomic.StoreInt32(&a.stopped, 1)
+			a.Logger().Info("acrn VM stopped")
+		}
+	}()
+
+	if a.state.ApicID != "" {
+		if err := a.releaseApicid(); err != nil {
+			return err
+		}
+		a.state.ApicID = ""
+	}
+
 	pid := a.state.PID
+	if pid == 0 {
+		a.Logger().Info("acrn VM was not started")
+		return nil
+	}
 
 	shutdownSignal := syscall.SIGINT
 
@@ -467,34 +532,45 @@ func (a *Acrn) StopVM(ctx context.Context, waitOnly bool) (err error) {
 	return utils.WaitLocalProcess(pid, acrnStopSandboxTimeoutSecs, shutdownSignal, a.Logger())
 }
 
+// blkrescan replaces the backend of the virtio-blk device created for
+// the drive index with file.
+func (a *Acrn) blkrescan(index int, file string) error {
+	slot, err := a.acrnConfig.blockDeviceSlot(index)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"blkrescan", a.acrnConfig.Name, fmt.Sprintf("%d,%s", slot, file)}
+
+	a.Logger().WithFields(logrus.Fields{
+		"args": args,
+		"path": a.acrnConfig.CtlPath,
+	}).Info("Rescanning block device with acrnctl")
+
+	/* #nosec */
+	out, err := exec.Command(a.acrnConfig.CtlPath, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("acrnctl %s failed: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
 func (a *Acrn) updateBlockDevice(drive *config.BlockDrive) error {
 	if drive.Swap {
 		return fmt.Errorf("Acrn doesn't support swap")
 	}
 
-	var err error
-	if drive.File == "" || drive.Index >= AcrnBlkDevPoolSz {
+	// Index 0 is the VM rootfs, the pool starts at index 1.
+	if drive.File == "" || drive.Index < 1 || drive.Index > AcrnBlkDevPoolSz {
 		return fmt.Errorf("Empty filepath or invalid drive index, Dive ID:%s, Drive Index:%d",
 			drive.ID, drive.Index)
 	}
 
-	slot := AcrnBlkdDevSlot[drive.Index]
-
 	//Explicitly set PCIPath to NULL, so that VirtPath can be used
 	drive.PCIPath = types.PciPath{}
 
-	args := []string{"blkrescan", a.acrnConfig.Name, fmt.Sprintf("%d,%s", slot, drive.File)}
-
-	a.Logger().WithFields(logrus.Fields{
-		"drive": drive,
-		"path":  a.config.HypervisorCtlPath,
-	}).Info("updateBlockDevice with acrnctl path")
-	cmd := exec.Command(a.config.HypervisorCtlPath, args...)
-	if err := cmd.Run(); err != nil {
-		a.Logger().WithError(err).Error("updating Block device with newFile path")
-	}
-
-	return err
+	return a.blkrescan(drive.Index, drive.File)
 }
 
 func (a *Acrn) HotplugAddDevice(ctx context.Context, devInfo interface{}, devType DeviceType) (interface{}, error) {
@@ -503,8 +579,12 @@ func (a *Acrn) HotplugAddDevice(ctx context.Context, devInfo interface{}, devTyp
 
 	switch devType {
 	case BlockDev:
+		drive, ok := devInfo.(*config.BlockDrive)
+		if !ok {
+			return nil, fmt.Errorf("HotplugAddDevice: invalid block device: %v", devInfo)
+		}
 		//The drive placeholder has to exist prior to Update
-		return nil, a.updateBlockDevice(devInfo.(*config.BlockDrive))
+		return nil, a.updateBlockDevice(drive)
 	default:
 		return nil, fmt.Errorf("HotplugAddDevice: unsupported device: devInfo:%v, deviceType%v",
 			devInfo, devType)
@@ -515,9 +595,22 @@ func (a *Acrn) HotplugRemoveDevice(ctx context.Context, devInfo interface{}, dev
 	span, _ := katatrace.Trace(ctx, a.Logger(), "HotplugRemoveDevice", acrnTracingTags, map[string]string{"sandbox_id": a.id})
 	defer span.End()
 
-	// Not supported. return success
-
-	return nil, nil
+	switch devType {
+	case BlockDev:
+		drive, ok := devInfo.(*config.BlockDrive)
+		if !ok {
+			return nil, fmt.Errorf("HotplugRemoveDevice: invalid block device: %v", devInfo)
+		}
+		if drive.Index < 1 || drive.Index > AcrnBlkDevPoolSz {
+			return nil, fmt.Errorf("Invalid drive index, Drive ID:%s, Drive Index:%d", drive.ID, drive.Index)
+		}
+		// The drives of the pool are not removed, their backend goes
+		// back to the dummy one until the next container rootfs.
+		return nil, a.blkrescan(drive.Index, "nodisk")
+	default:
+		return nil, fmt.Errorf("HotplugRemoveDevice: unsupported device: devInfo:%v, deviceType%v",
+			devInfo, devType)
+	}
 }
 
 func (a *Acrn) PauseVM(ctx context.Context) error {
@@ -624,12 +717,21 @@ func (a *Acrn) GetTotalMemoryMB(ctx context.Context) uint32 {
 	return a.config.MemorySize
 }
 
+// ResizeMemory keeps the boot memory, acrn can't hotplug guest memory.
 func (a *Acrn) ResizeMemory(ctx context.Context, reqMemMB uint32, memoryBlockSizeMB uint32, probe bool) (uint32, MemoryDevice, error) {
-	return 0, MemoryDevice{}, nil
+	return a.config.MemorySize, MemoryDevice{}, noGuestMemHotplugErr
 }
 
+// ResizeVCPUs keeps the boot vCPUs, acrn can't hotplug vCPUs.
 func (a *Acrn) ResizeVCPUs(ctx context.Context, reqVCPUs uint32) (currentVCPUs uint32, newVCPUs uint32, err error) {
-	return 0, 0, nil
+	if reqVCPUs != a.config.NumVCPUs {
+		a.Logger().WithFields(logrus.Fields{
+			"requested": reqVCPUs,
+			"vcpus":     a.config.NumVCPUs,
+		}).Warn("acrn can't resize vCPUs, cpu specifications cannot be guaranteed")
+	}
+
+	return a.config.NumVCPUs, a.config.NumVCPUs, nil
 }
 
 func (a *Acrn) Cleanup(ctx context.Context) error {
@@ -647,22 +749,51 @@ func (a *Acrn) GetVirtioFsPid() *int {
 	return nil
 }
 
+type acrnGrpc struct {
+	ID    string
+	State AcrnState
+}
+
 func (a *Acrn) fromGrpc(ctx context.Context, hypervisorConfig *HypervisorConfig, j []byte) error {
-	return errors.New("acrn is not supported by VM cache")
+	var ap acrnGrpc
+	err := json.Unmarshal(j, &ap)
+	if err != nil {
+		return err
+	}
+
+	a.ctx = ctx
+	a.state = ap.State
+	if err := a.setup(ctx, ap.ID, hypervisorConfig); err != nil {
+		return err
+	}
+
+	// The slots of the block device pool only depend on the devices
+	// built at creation time, rebuild them for the hotplug paths.
+	a.acrnConfig, err = a.buildConfig(ctx)
+	return err
 }
 
 func (a *Acrn) toGrpc(ctx context.Context) ([]byte, error) {
-	return nil, errors.New("acrn is not supported by VM cache")
+	ap := acrnGrpc{
+		ID:    a.id,
+		State: a.state,
+	}
+
+	return json.Marshal(&ap)
 }
 
 func (a *Acrn) Save() (s hv.HypervisorState) {
 	s.Pid = a.state.PID
+	s.UUID = a.state.UUID
+	s.ApicID = a.state.ApicID
 	s.Type = string(AcrnHypervisor)
 	return
 }
 
 func (a *Acrn) Load(s hv.HypervisorState) {
 	a.state.PID = s.Pid
+	a.state.UUID = s.UUID
+	a.state.ApicID = s.ApicID
 }
 
 func (a *Acrn) Check() error {
@@ -687,3 +818,19 @@ func (a *Acrn) GenerateSocket(id string) (interface{}, error) {
 func (a *Acrn) IsRateLimiterBuiltin() bool {
 	return false
 }
+
+func (a *Acrn) UpdateBlockRateLimiter(ctx context.Context, drive *config.BlockDrive, rateLimiter *config.BlockRateLimiter) error {
+	return errors.New("acrn does not support block rate limiters")
+}
+
+func (a *Acrn) ResizeBlockDevice(ctx context.Context, drive *config.BlockDrive, size uint64) error {
+	return errors.New("acrn does not support resizing block devices")
+}
+
+func (a *Acrn) ResizeBalloon(ctx context.Context, memMB uint32) error {
+	return errors.New("acrn does not support memory ballooning")
+}
+
+func (a *Acrn) SnapshotBlockDevice(ctx context.Context, drive *config.BlockDrive, snapshotPath string) error {
+	return errors.New("acrn does not support block device snapshots")
+}
@@ -94,13 +94,6 @@ var baselogger *logrus.Entry
 // devices are added and later replaced with container-rootfs.
 var AcrnBlkDevPoolSz = 8
 
-// AcrnBlkdDevSlot array provides translation between
-// the vitio-blk device index and slot it is currently
-// attached.
-// Allocating extra 1 to accommodate for VM rootfs
-// which is at driveIndex 0
-var AcrnBlkdDevSlot = make([]int, AcrnBlkDevPoolSz+1)
-
 // acrnKernelParamsNonDebug is a list of the default kernel
 // parameters that will be used in standard (non-debug) mode.
 var acrnKernelParamsNonDebug = []Param{
@@ -294,6 +287,9 @@ type Config struct {
 	// Name is the acrn guest name
 	Name string
 
+	// UUID is the acrn guest UUID
+	UUID string
+
 	// APICID to identify vCPU that will be assigned for this VM.
 	ApicID string
 
@@ -361,7 +357,6 @@ func (a *acrnArchBase) memoryTopology(memoryMb uint64) Memory {
 func (a *acrnArchBase) capabilities() types.Capabilities {
 	var caps types.Capabilities
 
-	caps.SetBlockDeviceSupport()
 	caps.SetBlockDeviceHotplugSupport()
 
 	return caps
@@ -496,17 +491,6 @@ func (blkdev BlockDevice) AcrnParams(slot int, config *Config) []string {
 	acrnParams = append(acrnParams, fmt.Sprintf("%d,%s,%s",
 		slot, device, blkdev.FilePath))
 
-	// Update the global array (BlkIndex<->slot)
-	// Used to identify slots for the hot-plugged virtio-blk devices
-	if blkdev.Index <= AcrnBlkDevPoolSz {
-		AcrnBlkdDevSlot[blkdev.Index] = slot
-	} else {
-		baselogger.WithFields(logrus.Fields{
-			"device": device,
-			"index":  blkdev.Index,
-		}).Info("Invalid index device")
-	}
-
 	return acrnParams
 }
 
@@ -557,7 +541,10 @@ func (config *Config) appendName() {
 	}
 }
 
-func (config *Config) appendDevices() {
+// walkDevices calls fn for every valid device, along with the PCI slot
+// acrn-dm attaches it to. The slots only depend on the device list, so
+// they can be computed again after a restore.
+func (config *Config) walkDevices(fn func(d Device, slot int)) {
 	slot := 0
 	for _, d := range config.Devices {
 		if !d.Valid() {
@@ -566,14 +553,42 @@ func (config *Config) appendDevices() {
 
 		if slot == acrnGVTgReservedSlot {
 			slot++ /*Slot 2 is assigned for GVT-g in acrn, so skip 2 */
-			baselogger.Info("Slot 2 is assigned for GVT-g in acrn, so skipping this slot")
-
 		}
-		config.acrnParams = append(config.acrnParams, d.AcrnParams(slot, config)...)
+		fn(d, slot)
 		slot++
 	}
 }
 
+func (config *Config) appendDevices() {
+	config.walkDevices(func(d Device, slot int) {
+		config.acrnParams = append(config.acrnParams, d.AcrnParams(slot, config)...)
+	})
+}
+
+// blockDeviceSlot returns the slot of the virtio-blk device created for
+// the drive index.
+func (config *Config) blockDeviceSlot(index int) (int, error) {
+	found := -1
+	config.walkDevices(func(d Device, slot int) {
+		if blkdev, ok := d.(BlockDevice); ok && blkdev.Index == index && found < 0 {
+			found = slot
+		}
+	})
+
+	if found < 0 {
+		return 0, fmt.Errorf("no virtio-blk device for drive index %d", index)
+	}
+
+	return found, nil
+}
+
+func (config *Config) appendUUID() {
+	if config.UUID != "" {
+		config.acrnParams = append(config.acrnParams, "-U")
+		config.acrnParams = append(config.acrnParams, config.UUID)
+	}
+}
+
 func (config *Config) appendACPI() {
 	if config.ACPIVirt {
 		config.acrnParams = append(config.acrnParams, "-A")
@@ -619,6 +634,7 @@ func (config *Config) appendKernel() {
 func LaunchAcrn(config Config, logger *logrus.Entry) (int, string, error) {
 	baselogger = logger
 	config.appendACPI()
+	config.appendUUID()
 	config.appendMemory()
 	config.appendDevices()
 	config.appendCPUAffinity()
@@ -659,6 +675,7 @@ func LaunchCustomAcrn(ctx context.Context, path string, params []string,
 		logger.Errorf("Unable to launch %s: %v", path, err)
 		errStr = stderr.String()
 		logger.Errorf("%s", errStr)
+		return 0, errStr, err
 	}
 	return cmd.Process.Pid, errStr, err
 }
@@ -82,7 +82,7 @@ func TestAcrnArchBaseCapabilities(t *testing.T) {
 	acrnArchBase := newAcrnArchBase()
 
 	c := acrnArchBase.capabilities()
-	assert.True(c.IsBlockDeviceSupported())
+	assert.False(c.IsBlockDeviceSupported())
 	assert.True(c.IsBlockDeviceHotplugSupported())
 	assert.False(c.IsFsSharingSupported())
 }
@@ -298,3 +298,29 @@ func TestAcrnArchBaseAppendNetwork(t *testing.T) {
 	devices = acrnArchBase.appendNetwork(devices, macvtapEp)
 	assert.Equal(expectedOut, devices)
 }
+
+func TestAcrnConfigBlockDeviceSlot(t *testing.T) {
+	assert := assert.New(t)
+
+	config := Config{
+		Devices: []Device{
+			BridgeDevice{Function: 0, Emul: acrnHostBridge},
+			LPCDevice{Function: 0, Emul: acrnLPCDev},
+			BlockDevice{FilePath: "/tmp/image.img", Index: 0},
+			BlockDevice{},
+			BlockDevice{FilePath: "nodisk", Index: 1},
+		},
+	}
+
+	// Slot 2 is reserved for GVT-g and invalid devices get no slot.
+	slot, err := config.blockDeviceSlot(0)
+	assert.NoError(err)
+	assert.Equal(3, slot)
+
+	slot, err = config.blockDeviceSlot(1)
+	assert.NoError(err)
+	assert.Equal(4, slot)
+
+	_, err = config.blockDeviceSlot(2)
+	assert.Error(err)
+}
@@ -10,8 +10,11 @@ package virtcontainers
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
@@ -36,6 +39,26 @@ func newAcrnConfig() HypervisorConfig {
 	}
 }
 
+// writeFakeAcrnDM writes an acrn-dm stand-in to path. It records its
+// command line to the returned log and runs until it is signalled.
+func writeFakeAcrnDM(t *testing.T, path string) string {
+	log := path + ".log"
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %s\nexec sleep 60\n", log)
+	assert.NoError(t, os.WriteFile(path, []byte(script), 0700))
+
+	return log
+}
+
+// writeFakeAcrnctl writes an acrnctl stand-in to path. It records its
+// command lines and exits with status.
+func writeFakeAcrnctl(t *testing.T, path string, status int) *fakeVMM {
+	log := path + ".log"
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %s\necho acrnctl exited with %d\nexit %d\n", log, status, status)
+	assert.NoError(t, os.WriteFile(path, []byte(script), 0700))
+
+	return &fakeVMM{log: log}
+}
+
 func testAcrnKernelParameters(t *testing.T, kernelParams []Param, debug bool) {
 	assert := assert.New(t)
 	acrnConfig := newAcrnConfig()
@@ -80,8 +103,9 @@ func TestAcrnCapabilities(t *testing.T) {
 	}
 
 	caps := a.Capabilities(a.ctx)
-	assert.True(caps.IsBlockDeviceSupported())
+	assert.False(caps.IsBlockDeviceSupported())
 	assert.True(caps.IsBlockDeviceHotplugSupported())
+	assert.False(caps.IsFsSharingSupported())
 }
 
 func testAcrnAddDevice(t *testing.T, devInfo interface{}, devType DeviceType, expected []Device) {
@@ -148,6 +172,9 @@ func TestAcrnHotplugUnsupportedDeviceType(t *testing.T) {
 
 	_, err := a.HotplugAddDevice(a.ctx, &MemoryDevice{0, 128, uint64(0), false}, FsDev)
 	assert.Error(err)
+
+	_, err = a.HotplugRemoveDevice(a.ctx, &MemoryDevice{0, 128, uint64(0), false}, MemoryDev)
+	assert.Error(err)
 }
 
 func TestAcrnUpdateBlockDeviceInvalidPath(t *testing.T) {
@@ -223,12 +250,11 @@ func TestAcrnCreateVM(t *testing.T) {
 	store, err := persist.GetDriver()
 	assert.NoError(err)
 
+	acrnConfig.VMStorePath = store.RunVMStoragePath()
+	acrnConfig.RunStorePath = store.RunStoragePath()
+
 	a := &Acrn{
 		store: store,
-		config: HypervisorConfig{
-			VMStorePath:  store.RunVMStoragePath(),
-			RunStorePath: store.RunStoragePath(),
-		},
 	}
 
 	sandbox := &Sandbox{
@@ -284,3 +310,196 @@ func TestAcrnSetConfig(t *testing.T) {
 
 	assert.Equal(a.config, config)
 }
+
+func TestAcrnVMName(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("sbx-testSandbox", acrnVMName("testSandbox"))
+
+	id := "4f3ab2b1c4b6a2f1a8e5d2c3b4a59687"
+	name := acrnVMName(id)
+	assert.Len(name, acrnMaxVMNameLen)
+	assert.True(strings.HasPrefix(name, acrnVMNamePrefix))
+	assert.Equal(name, acrnVMName(id))
+	assert.NotEqual(name, acrnVMName(id+"0"))
+}
+
+func TestAcrnResize(t *testing.T) {
+	assert := assert.New(t)
+
+	a := &Acrn{
+		config: newAcrnConfig(),
+	}
+
+	current, updated, err := a.ResizeVCPUs(context.Background(), a.config.NumVCPUs+2)
+	assert.NoError(err)
+	assert.Equal(a.config.NumVCPUs, current)
+	assert.Equal(a.config.NumVCPUs, updated)
+
+	memory, _, err := a.ResizeMemory(context.Background(), a.config.MemorySize*2, 128, false)
+	assert.Equal(noGuestMemHotplugErr, err)
+	assert.Equal(a.config.MemorySize, memory)
+}
+
+func newAcrnLifecycleConfig(t *testing.T) (HypervisorConfig, string, *fakeVMM) {
+	dir := t.TempDir()
+
+	conf := newAcrnConfig()
+	conf.HypervisorPath = filepath.Join(dir, "acrn-dm")
+	conf.HypervisorCtlPath = filepath.Join(dir, "acrnctl")
+	conf.VMStorePath = dir
+	conf.RunStorePath = dir
+
+	dmLog := writeFakeAcrnDM(t, conf.HypervisorPath)
+	ctl := writeFakeAcrnctl(t, conf.HypervisorCtlPath, 0)
+
+	return conf, dmLog, ctl
+}
+
+func TestAcrnLifecycle(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	id := "lifecycle"
+
+	conf, dmLog, ctl := newAcrnLifecycleConfig(t)
+	network, err := NewNetwork()
+	assert.NoError(err)
+
+	a := &Acrn{}
+	assert.NoError(a.CreateVM(ctx, id, network, &conf))
+	assert.NotEmpty(a.state.UUID)
+	assert.NotEmpty(a.state.ApicID)
+	assert.Equal("sbx-"+id, a.acrnConfig.Name)
+
+	assert.NoError(a.StartVM(ctx, 0))
+	assert.NotZero(a.state.PID)
+	assert.NoError(a.Check())
+
+	slot, err := a.acrnConfig.blockDeviceSlot(1)
+	assert.NoError(err)
+
+	var args string
+	assert.Eventually(func() bool {
+		out, err := os.ReadFile(dmLog)
+		args = string(out)
+		return err == nil && args != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(args, "-U "+a.state.UUID)
+	assert.Contains(args, "--cpu_affinity "+a.state.ApicID)
+	assert.Contains(args, fmt.Sprintf("-s %d,virtio-blk,nodisk", slot))
+	assert.True(strings.HasSuffix(strings.TrimSpace(args), a.acrnConfig.Name))
+
+	// Container rootfs hotplug replaces the backend of a pool drive.
+	disk := filepath.Join(t.TempDir(), "rootfs.img")
+	assert.NoError(os.WriteFile(disk, nil, 0600))
+	drive := &config.BlockDrive{
+		File:  disk,
+		ID:    "rootfs",
+		Index: 1,
+	}
+
+	_, err = a.HotplugAddDevice(ctx, drive, BlockDev)
+	assert.NoError(err)
+	_, err = a.HotplugRemoveDevice(ctx, drive, BlockDev)
+	assert.NoError(err)
+	assert.Equal([]string{
+		fmt.Sprintf("blkrescan %s %d,%s", a.acrnConfig.Name, slot, disk),
+		fmt.Sprintf("blkrescan %s %d,nodisk", a.acrnConfig.Name, slot),
+	}, ctl.requests())
+
+	// A restored sandbox keeps its UUID and vCPU and can still hotplug.
+	idxFile := filepath.Join(conf.VMStorePath, "cpu_affinity_idx")
+	idx, err := os.ReadFile(idxFile)
+	assert.NoError(err)
+
+	saved := a.Save()
+	restored := &Acrn{}
+	restored.Load(saved)
+	assert.NoError(restored.CreateVM(ctx, id, network, &conf))
+	assert.Equal(saved, restored.Save())
+	assert.Equal(a.acrnConfig.Name, restored.acrnConfig.Name)
+
+	restoredIdx, err := os.ReadFile(idxFile)
+	assert.NoError(err)
+	assert.Equal(idx, restoredIdx)
+
+	_, err = restored.HotplugAddDevice(ctx, drive, BlockDev)
+	assert.NoError(err)
+	assert.Len(ctl.requests(), 3)
+
+	// Same for a VM handed over by the VM cache.
+	j, err := a.toGrpc(ctx)
+	assert.NoError(err)
+
+	cached := &Acrn{}
+	assert.NoError(cached.fromGrpc(ctx, &conf, j))
+	assert.Equal(a.state, cached.state)
+	assert.Equal(a.acrnConfig.Name, cached.acrnConfig.Name)
+
+	_, err = cached.HotplugRemoveDevice(ctx, drive, BlockDev)
+	assert.NoError(err)
+	assert.Len(ctl.requests(), 4)
+
+	assert.NoError(a.StopVM(ctx, false))
+	assert.Empty(a.state.ApicID)
+}
+
+func TestAcrnStopVM(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	conf := newAcrnConfig()
+	conf.VMStorePath = t.TempDir()
+	idxFile := filepath.Join(conf.VMStorePath, "cpu_affinity_idx")
+
+	// The VM was not even created, no vCPU was taken.
+	a := &Acrn{config: conf}
+	assert.NoError(a.StopVM(ctx, false))
+	assert.NoFileExists(idxFile)
+
+	// acrn-dm failed to launch, the vCPU is given back once.
+	assert.NoError(os.WriteFile(idxFile, []byte("3"), 0600))
+	a = &Acrn{config: conf}
+	a.state.ApicID = "3"
+	assert.NoError(a.StopVM(ctx, false))
+	assert.NoError(a.StopVM(ctx, false))
+	assert.Empty(a.state.ApicID)
+
+	idx, err := os.ReadFile(idxFile)
+	assert.NoError(err)
+	assert.Equal("2", string(idx))
+
+	// acrn-dm cannot be executed.
+	pid, _, err := LaunchCustomAcrn(ctx, filepath.Join(t.TempDir(), "acrn-dm"), nil, virtLog)
+	assert.Error(err)
+	assert.Zero(pid)
+}
+
+func TestAcrnHotplugBlockDeviceError(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	conf, _, _ := newAcrnLifecycleConfig(t)
+	ctl := writeFakeAcrnctl(t, conf.HypervisorCtlPath, 1)
+	network, err := NewNetwork()
+	assert.NoError(err)
+
+	a := &Acrn{}
+	assert.NoError(a.CreateVM(ctx, "blkerror", network, &conf))
+
+	drive := &config.BlockDrive{
+		File:  "/tmp/test.img",
+		Index: AcrnBlkDevPoolSz,
+	}
+
+	_, err = a.HotplugAddDevice(ctx, drive, BlockDev)
+	assert.Error(err)
+	assert.Contains(err.Error(), "acrnctl exited with 1")
+	assert.Len(ctl.requests(), 1)
+
+	// The VM rootfs is not part of the pool.
+	drive.Index = 0
+	_, err = a.HotplugAddDevice(ctx, drive, BlockDev)
+	assert.Error(err)
+	assert.Len(ctl.requests(), 1)
+}
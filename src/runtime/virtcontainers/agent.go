@@ -133,6 +133,9 @@ type agent interface {
 	// statsContainer will tell the agent to get stats from a container related to a Sandbox
 	statsContainer(ctx context.Context, sandbox *Sandbox, c Container) (*ContainerStats, error)
 
+	// listProcesses will ask the agent to list the processes running in a container
+	listProcesses(ctx context.Context, c *Container) ([]*grpc.GuestProcess, error)
+
 	// pauseContainer will pause a container
 	pauseContainer(ctx context.Context, sandbox *Sandbox, c Container) error
 
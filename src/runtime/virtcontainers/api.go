@@ -113,6 +113,37 @@ func createSandboxFromConfig(ctx context.Context, sandboxConfig SandboxConfig, f
 	return s, nil
 }
 
+// RecoverSandbox is used by a restarted shimv2 to re-adopt a running sandbox.
+// RecoverSandbox loads the sandbox and its containers from the persisted state
+// and reconnects to the hypervisor and to the agent. It fails if the sandbox is
+// not running anymore.
+func RecoverSandbox(ctx context.Context, sandboxID string) (VCSandbox, error) {
+	span, ctx := katatrace.Trace(ctx, virtLog, "RecoverSandbox", apiTracingTags)
+	defer span.End()
+
+	if sandboxID == "" {
+		return nil, vcTypes.ErrNeedSandboxID
+	}
+
+	unlock, err := rwLockSandbox(sandboxID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	s, err := fetchSandbox(ctx, sandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reconnect(ctx); err != nil {
+		s.Release(ctx)
+		return nil, err
+	}
+
+	return s, nil
+}
+
 // CleanupContainer is used by shimv2 to stop and delete a container exclusively, once there is no container
 // in the sandbox left, do stop the sandbox and delete it. Those serial operations will be done exclusively by
 // locking the sandbox.
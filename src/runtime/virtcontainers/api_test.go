@@ -311,3 +311,48 @@ func TestCleanupContainer(t *testing.T) {
 		t.Fatal("sandbox dir should be deleted")
 	}
 }
+
+func TestRecoverSandbox(t *testing.T) {
+	if tc.NotValid(ktu.NeedRoot()) {
+		t.Skip(testDisabledAsNonRoot)
+	}
+
+	assert := assert.New(t)
+
+	_, err := RecoverSandbox(context.Background(), "")
+	assert.Error(err)
+
+	config := newTestSandboxConfigNoop()
+	ctx := WithNewAgentFunc(context.Background(), newMockAgent)
+
+	p, _, err := createAndStartSandbox(ctx, config)
+	assert.NoError(err)
+	assert.NotNil(p)
+
+	contConfig := newTestContainerConfigNoop(testContainerID)
+	_, err = p.CreateContainer(ctx, contConfig)
+	assert.NoError(err)
+	_, err = p.StartContainer(ctx, testContainerID)
+	assert.NoError(err)
+
+	// the shim exits without stopping the sandbox
+	assert.NoError(p.Release(ctx))
+
+	s, err := RecoverSandbox(ctx, p.ID())
+	assert.NoError(err)
+	assert.Equal(p.ID(), s.ID())
+	assert.Len(s.GetAllContainers(), 2)
+
+	status, err := s.StatusContainer(testContainerID)
+	assert.NoError(err)
+	assert.Equal(types.StateRunning, status.State.State)
+
+	assert.NoError(s.Stop(ctx, true))
+	assert.NoError(s.Release(ctx))
+
+	// a stopped sandbox cannot be recovered
+	_, err = RecoverSandbox(ctx, p.ID())
+	assert.Error(err)
+
+	assert.NoError(CleanupContainer(ctx, p.ID(), testContainerID, true))
+}
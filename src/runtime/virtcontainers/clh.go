@@ -81,6 +81,8 @@ const (
 	virtioFsSocket                         = "virtiofsd.sock"
 	defaultClhPath                         = "/usr/local/bin/cloud-hypervisor"
 	virtioFsCacheAlways                    = "always"
+	// A vDPA network device needs at least one rx/tx queue pair
+	clhVdpaNetNumQueues = 2
 )
 
 // Interface that hides the implementation of openAPI client
@@ -101,10 +103,14 @@ type clhClient interface {
 	BootVM(ctx context.Context) (*http.Response, error)
 	// Add/remove CPUs to/from the VM
 	VmResizePut(ctx context.Context, vmResize chclient.VmResize) (*http.Response, error)
+	// Resize a memory zone of the VM
+	VmResizeZonePut(ctx context.Context, vmResizeZone chclient.VmResizeZone) (*http.Response, error)
 	// Add VFIO PCI device to the VM
 	VmAddDevicePut(ctx context.Context, deviceConfig chclient.DeviceConfig) (chclient.PciDeviceInfo, *http.Response, error)
 	// Add a new disk device to the VM
 	VmAddDiskPut(ctx context.Context, diskConfig chclient.DiskConfig) (chclient.PciDeviceInfo, *http.Response, error)
+	// Add a new vDPA device to the VM
+	VmAddVdpaPut(ctx context.Context, vdpaConfig chclient.VdpaConfig) (chclient.PciDeviceInfo, *http.Response, error)
 	// Remove a device from the VM
 	VmRemoveDevicePut(ctx context.Context, vmRemoveDevice chclient.VmRemoveDevice) (*http.Response, error)
 }
@@ -138,6 +144,10 @@ func (c *clhClientApi) VmResizePut(ctx context.Context, vmResize chclient.VmResi
 	return c.ApiInternal.VmResizePut(ctx).VmResize(vmResize).Execute()
 }
 
+func (c *clhClientApi) VmResizeZonePut(ctx context.Context, vmResizeZone chclient.VmResizeZone) (*http.Response, error) {
+	return c.ApiInternal.VmResizeZonePut(ctx).VmResizeZone(vmResizeZone).Execute()
+}
+
 func (c *clhClientApi) VmAddDevicePut(ctx context.Context, deviceConfig chclient.DeviceConfig) (chclient.PciDeviceInfo, *http.Response, error) {
 	return c.ApiInternal.VmAddDevicePut(ctx).DeviceConfig(deviceConfig).Execute()
 }
@@ -146,6 +156,10 @@ func (c *clhClientApi) VmAddDiskPut(ctx context.Context, diskConfig chclient.Dis
 	return c.ApiInternal.VmAddDiskPut(ctx).DiskConfig(diskConfig).Execute()
 }
 
+func (c *clhClientApi) VmAddVdpaPut(ctx context.Context, vdpaConfig chclient.VdpaConfig) (chclient.PciDeviceInfo, *http.Response, error) {
+	return c.ApiInternal.VmAddVdpaPut(ctx).VdpaConfig(vdpaConfig).Execute()
+}
+
 func (c *clhClientApi) VmRemoveDevicePut(ctx context.Context, vmRemoveDevice chclient.VmRemoveDevice) (*http.Response, error) {
 	return c.ApiInternal.VmRemoveDevicePut(ctx).VmRemoveDevice(vmRemoveDevice).Execute()
 }
@@ -234,12 +248,14 @@ type CloudHypervisorState struct {
 	apiSocket         string
 	PID               int
 	VirtiofsDaemonPid int
+	SwtpmPid          int
 	state             clhState
 }
 
 func (s *CloudHypervisorState) reset() {
 	s.PID = 0
 	s.VirtiofsDaemonPid = 0
+	s.SwtpmPid = 0
 	s.state = clhNotReady
 }
 
@@ -247,6 +263,7 @@ type cloudHypervisor struct {
 	vmconfig        chclient.VmConfig
 	console         console.Console
 	virtiofsDaemon  VirtiofsDaemon
+	swtpm           *swtpm
 	ctx             context.Context
 	APIClient       clhClient
 	netDevices      *[]chclient.NetConfig
@@ -257,6 +274,9 @@ type cloudHypervisor struct {
 	config          HypervisorConfig
 	stopped         int32
 	mu              sync.Mutex
+	// stopping is set while StopVM runs, the daemons exiting then are
+	// expected to.
+	stopping int32
 }
 
 var clhKernelParams = []Param{
@@ -385,6 +405,53 @@ func (clh *cloudHypervisor) stopVirtiofsDaemon(ctx context.Context) (err error)
 	return nil
 }
 
+func (clh *cloudHypervisor) setupSwtpm(ctx context.Context) error {
+	clh.Logger().WithField("function", "setupSwtpm").Info("Starting swtpm")
+
+	pid, err := clh.swtpm.Start(ctx, func() {
+		clh.onSwtpmQuit(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	clh.state.SwtpmPid = pid
+
+	return nil
+}
+
+// onSwtpmQuit stops the VM when swtpm exits while the VM is running. swtpm
+// also exits when the VM is stopped, or when cloud-hypervisor itself exits,
+// which is left to the caller of StopVM.
+func (clh *cloudHypervisor) onSwtpmQuit(ctx context.Context) {
+	if atomic.LoadInt32(&clh.stopping) != 0 || atomic.LoadInt32(&clh.stopped) != 0 {
+		clh.Logger().Info("swtpm exited on VM shutdown")
+		return
+	}
+
+	if pid := clh.GetPids()[0]; pid == 0 || syscall.Kill(pid, 0) != nil {
+		clh.Logger().Info("swtpm exited after cloud-hypervisor")
+		return
+	}
+
+	clh.Logger().Warn("swtpm exited while the VM is running, stopping the VM")
+	if err := clh.StopVM(ctx, false); err != nil {
+		clh.Logger().WithError(err).Error("failed to stop the VM after swtpm exited")
+	}
+}
+
+func (clh *cloudHypervisor) stopSwtpm(ctx context.Context) error {
+	if clh.swtpm == nil || clh.state.SwtpmPid == 0 {
+		return nil
+	}
+
+	if err := clh.swtpm.Stop(ctx); err != nil {
+		return err
+	}
+	clh.state.SwtpmPid = 0
+
+	return nil
+}
+
 func (clh *cloudHypervisor) loadVirtiofsDaemon(sharedPath string) (VirtiofsDaemon, error) {
 	virtiofsdSocketPath, err := clh.virtioFsSocketPath(clh.id)
 	if err != nil {
@@ -466,6 +533,13 @@ func (clh *cloudHypervisor) CreateVM(ctx context.Context, id string, network Net
 			return err
 		}
 		clh.virtiofsDaemon = virtiofsDaemon
+		clh.APIClient = clh.newAPIClient()
+
+		if clh.config.EnableVTPM {
+			if clh.swtpm, err = newSwtpm(clh.config, clh.id, clh.state.SwtpmPid); err != nil {
+				return err
+			}
+		}
 
 		return nil
 	}
@@ -504,6 +578,10 @@ func (clh *cloudHypervisor) CreateVM(ctx context.Context, id string, network Net
 	// Set initial amount of cpu's for the virtual machine
 	clh.vmconfig.Cpus = chclient.NewCpusConfig(int32(clh.config.NumVCPUs), int32(clh.config.DefaultMaxVCPUs))
 
+	if len(clh.config.NUMANodes) > 0 {
+		clh.setupNUMATopology()
+	}
+
 	// First take the default parameters defined by this driver
 	params := commonNvdimmKernelRootParams
 	if clh.config.ConfidentialGuest {
@@ -608,30 +686,21 @@ func (clh *cloudHypervisor) CreateVM(ctx context.Context, id string, network Net
 		return err
 	}
 	clh.state.apiSocket = apiSocketPath
-
-	cfg := chclient.NewConfiguration()
-	cfg.HTTPClient = &http.Client{
-		Transport: &http.Transport{
-			DialContext: func(ctx context.Context, network, path string) (net.Conn, error) {
-				addr, err := net.ResolveUnixAddr("unix", clh.state.apiSocket)
-				if err != nil {
-					return nil, err
-				}
-
-				return net.DialUnix("unix", nil, addr)
-			},
-		},
-	}
-
-	clh.APIClient = &clhClientApi{
-		ApiInternal: chclient.NewAPIClient(cfg).DefaultApi,
-	}
+	clh.APIClient = clh.newAPIClient()
 
 	clh.virtiofsDaemon, err = clh.createVirtiofsDaemon(filepath.Join(GetSharePath(clh.id)))
 	if err != nil {
 		return err
 	}
 
+	if clh.config.EnableVTPM {
+		clh.swtpm, err = newSwtpm(clh.config, clh.id, 0)
+		if err != nil {
+			return err
+		}
+		clh.vmconfig.Tpm = chclient.NewTpmConfig(clh.swtpm.socketPath)
+	}
+
 	if clh.config.SGXEPCSize > 0 {
 		epcSection := chclient.NewSgxEpcConfig("kata-epc", clh.config.SGXEPCSize)
 		epcSection.Prefault = func(b bool) *bool { return &b }(true)
@@ -687,13 +756,26 @@ func (clh *cloudHypervisor) StartVM(ctx context.Context, timeout int) error {
 		}
 	}()
 
+	if clh.swtpm != nil {
+		if err = clh.setupSwtpm(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				if shutdownErr := clh.stopSwtpm(ctx); shutdownErr != nil {
+					clh.Logger().WithError(shutdownErr).Warn("error shutting down swtpm")
+				}
+			}
+		}()
+	}
+
 	pid, err := clh.launchClh()
 	if err != nil {
 		return fmt.Errorf("failed to launch cloud-hypervisor: %q", err)
 	}
 	clh.state.PID = pid
 
-	if err := clh.bootVM(ctx); err != nil {
+	if err = clh.bootVM(ctx); err != nil {
 		return err
 	}
 
@@ -886,7 +968,35 @@ func (clh *cloudHypervisor) hotPlugVFIODevice(device *config.VFIODev) error {
 	return err
 }
 
+// hotplugAddVDPADevice hotplugs the vhost-vdpa device at path, registered as id,
+// and returns its PCI path in the guest.
+func (clh *cloudHypervisor) hotplugAddVDPADevice(id, path string, numQueues int32) (types.PciPath, error) {
+	cl := clh.client()
+	ctx, cancel := context.WithTimeout(context.Background(), clhHotPlugAPITimeout*time.Second)
+	defer cancel()
+
+	// Create the clh vdpa config via the constructor to ensure default values are properly assigned
+	clhVdpa := *chclient.NewVdpaConfig(path, numQueues)
+	clhVdpa.SetId(id)
+	pciInfo, _, err := cl.VmAddVdpaPut(ctx, clhVdpa)
+	if err != nil {
+		return types.PciPath{}, fmt.Errorf("Failed to hotplug vdpa device %s %s", path, openAPIClientError(err))
+	}
+	clh.devicesIds[id] = pciInfo.GetId()
+
+	return clhPciInfoToPath(pciInfo)
+}
+
 func (clh *cloudHypervisor) hotplugAddNetDevice(e Endpoint) error {
+	if ep, ok := e.(*VDPAEndpoint); ok {
+		pciPath, err := clh.hotplugAddVDPADevice(ep.netdevID(), ep.VhostVDPAPath, clhVdpaNetNumQueues)
+		if err != nil {
+			return err
+		}
+		ep.SetPciPath(pciPath)
+		return nil
+	}
+
 	err := clh.addNet(e)
 	if err != nil {
 		return err
@@ -909,6 +1019,15 @@ func (clh *cloudHypervisor) HotplugAddDevice(ctx context.Context, devInfo interf
 	case NetDev:
 		device := devInfo.(Endpoint)
 		return nil, clh.hotplugAddNetDevice(device)
+	case VDPADev:
+		device := devInfo.(*config.VDPADev)
+		numQueues := int32(1)
+		if device.Type == config.VDPANet {
+			numQueues = clhVdpaNetNumQueues
+		}
+		pciPath, err := clh.hotplugAddVDPADevice(device.ID, device.HostPath, numQueues)
+		device.PCIPath = pciPath
+		return nil, err
 	default:
 		return nil, fmt.Errorf("cannot hotplug device: unsupported device type '%v'", devType)
 	}
@@ -926,6 +1045,14 @@ func (clh *cloudHypervisor) HotplugRemoveDevice(ctx context.Context, devInfo int
 		deviceID = clhDriveIndexToID(devInfo.(*config.BlockDrive).Index)
 	case VfioDev:
 		deviceID = devInfo.(*config.VFIODev).ID
+	case VDPADev:
+		deviceID = devInfo.(*config.VDPADev).ID
+	case NetDev:
+		ep, ok := devInfo.(*VDPAEndpoint)
+		if !ok {
+			return nil, fmt.Errorf("Could not hot remove device: unsupported network endpoint: %v", devInfo)
+		}
+		deviceID = ep.netdevID()
 	default:
 		clh.Logger().WithFields(log.Fields{"devInfo": devInfo,
 			"deviceType": devType}).Error("HotplugRemoveDevice: unsupported device")
@@ -978,11 +1105,22 @@ func (clh *cloudHypervisor) ResizeMemory(ctx context.Context, reqMemMB uint32, m
 		maxHotplugSize = utils.MemUnit(*info.Config.Memory.HotplugSize) * utils.Byte
 	}
 
+	currentMem := utils.MemUnit(info.Config.Memory.Size) * utils.Byte
+
+	// With guest NUMA nodes, the memory is split into zones and hotplug
+	// happens on the first zone only.
+	zones := info.Config.Memory.GetZones()
+	if len(zones) > 0 {
+		maxHotplugSize = utils.MemUnit(zones[0].GetHotplugSize()) * utils.Byte
+		for _, zone := range zones {
+			currentMem += utils.MemUnit(zone.Size+zone.GetHotpluggedSize()) * utils.Byte
+		}
+	}
+
 	if reqMemMB > uint32(maxHotplugSize.ToMiB()) {
 		reqMemMB = uint32(maxHotplugSize.ToMiB())
 	}
 
-	currentMem := utils.MemUnit(info.Config.Memory.Size) * utils.Byte
 	newMem := utils.MemUnit(reqMemMB) * utils.MiB
 
 	// Early Check to verify if boot memory is the same as requested
@@ -1017,11 +1155,19 @@ func (clh *cloudHypervisor) ResizeMemory(ctx context.Context, reqMemMB uint32, m
 	ctx, cancelResize := context.WithTimeout(ctx, clh.getClhAPITimeout()*time.Second)
 	defer cancelResize()
 
-	resize := *chclient.NewVmResize()
-	// OpenApi does not support uint64, convert to int64
-	resize.DesiredRam = func(i int64) *int64 { return &i }(int64(newMem.ToBytes()))
 	clh.Logger().WithFields(log.Fields{"current-memory": currentMem, "new-memory": newMem}).Debug("updating VM memory")
-	if _, err = cl.VmResizePut(ctx, resize); err != nil {
+	if len(zones) > 0 {
+		resizeZone := *chclient.NewVmResizeZone()
+		resizeZone.SetId(zones[0].Id)
+		resizeZone.SetDesiredRam(zones[0].Size + zones[0].GetHotpluggedSize() + int64(hotplugSize.ToBytes()))
+		_, err = cl.VmResizeZonePut(ctx, resizeZone)
+	} else {
+		resize := *chclient.NewVmResize()
+		// OpenApi does not support uint64, convert to int64
+		resize.DesiredRam = func(i int64) *int64 { return &i }(int64(newMem.ToBytes()))
+		_, err = cl.VmResizePut(ctx, resize)
+	}
+	if err != nil {
 		clh.Logger().WithError(err).WithFields(log.Fields{"current-memory": currentMem, "new-memory": newMem}).Warnf("failed to update memory %s", openAPIClientError(err))
 		err = fmt.Errorf("Failed to resize memory from %d to %d: %s", currentMem, newMem, openAPIClientError(err))
 		return uint32(currentMem.ToMiB()), MemoryDevice{}, openAPIClientError(err)
@@ -1114,6 +1260,9 @@ func (clh *cloudHypervisor) StopVM(ctx context.Context, waitOnly bool) (err erro
 		return nil
 	}
 
+	atomic.StoreInt32(&clh.stopping, 1)
+	defer atomic.StoreInt32(&clh.stopping, 0)
+
 	return clh.terminate(ctx, waitOnly)
 }
 
@@ -1129,6 +1278,7 @@ func (clh *cloudHypervisor) Save() (s hv.HypervisorState) {
 	s.Pid = clh.state.PID
 	s.Type = string(ClhHypervisor)
 	s.VirtiofsDaemonPid = clh.state.VirtiofsDaemonPid
+	s.SwtpmPid = clh.state.SwtpmPid
 	s.APISocket = clh.state.apiSocket
 	return
 }
@@ -1136,6 +1286,7 @@ func (clh *cloudHypervisor) Save() (s hv.HypervisorState) {
 func (clh *cloudHypervisor) Load(s hv.HypervisorState) {
 	clh.state.PID = s.Pid
 	clh.state.VirtiofsDaemonPid = s.VirtiofsDaemonPid
+	clh.state.SwtpmPid = s.SwtpmPid
 	clh.state.apiSocket = s.APISocket
 }
 
@@ -1237,8 +1388,12 @@ func (clh *cloudHypervisor) terminate(ctx context.Context, waitOnly bool) (err e
 		}
 	}
 
-	if err = utils.WaitLocalProcess(pid, uint(clh.getClhStopSandboxTimeout()), syscall.Signal(0), clh.Logger()); err != nil {
-		return err
+	// Cloud Hypervisor is not running when it failed to start, but the
+	// daemons started before it still have to be stopped.
+	if pidRunning {
+		if err = utils.WaitLocalProcess(pid, uint(clh.getClhStopSandboxTimeout()), syscall.Signal(0), clh.Logger()); err != nil {
+			return err
+		}
 	}
 
 	clh.Logger().Debug("stop virtiofsDaemon")
@@ -1247,6 +1402,12 @@ func (clh *cloudHypervisor) terminate(ctx context.Context, waitOnly bool) (err e
 		clh.Logger().WithError(err).Error("failed to stop virtiofsDaemon")
 	}
 
+	clh.Logger().Debug("stop swtpm")
+
+	if err = clh.stopSwtpm(ctx); err != nil {
+		clh.Logger().WithError(err).Error("failed to stop swtpm")
+	}
+
 	return
 }
 
@@ -1444,6 +1605,28 @@ func (clh *cloudHypervisor) isClhRunning(timeout uint) (bool, error) {
 
 }
 
+// newAPIClient returns a client of the HTTP API of Cloud Hypervisor,
+// listening on the API socket of the state.
+func (clh *cloudHypervisor) newAPIClient() clhClient {
+	cfg := chclient.NewConfiguration()
+	cfg.HTTPClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, path string) (net.Conn, error) {
+				addr, err := net.ResolveUnixAddr("unix", clh.state.apiSocket)
+				if err != nil {
+					return nil, err
+				}
+
+				return net.DialUnix("unix", nil, addr)
+			},
+		},
+	}
+
+	return &clhClientApi{
+		ApiInternal: chclient.NewAPIClient(cfg).DefaultApi,
+	}
+}
+
 func (clh *cloudHypervisor) client() clhClient {
 	return clh.APIClient
 }
@@ -1518,6 +1701,44 @@ func (clh *cloudHypervisor) bootVM(ctx context.Context) error {
 	return nil
 }
 
+// setupNUMATopology splits the VM memory into one memory zone per guest
+// NUMA node, each bound to its host NUMA node. Memory hotplug is carried
+// by the first zone since Cloud Hypervisor does not allow resizing the
+// global memory when zones are in use.
+func (clh *cloudHypervisor) setupNUMATopology() {
+	hotplugSize := clh.vmconfig.Memory.HotplugSize
+
+	// The VM memory is entirely described by its zones
+	clh.vmconfig.Memory.Size = 0
+	clh.vmconfig.Memory.HotplugSize = nil
+
+	var zones []chclient.MemoryZoneConfig
+	var numa []chclient.NumaConfig
+	for i, n := range clh.config.NUMANodes {
+		zone := chclient.NewMemoryZoneConfig(fmt.Sprintf("mem%d", i), int64((utils.MemUnit(n.MemorySize) * utils.MiB).ToBytes()))
+		zone.SetShared(true)
+		zone.SetHugepages(clh.config.HugePages)
+		zone.SetHostNumaNode(int32(n.HostNode))
+		if i == 0 && hotplugSize != nil {
+			zone.SetHotplugSize(*hotplugSize)
+		}
+		zones = append(zones, *zone)
+
+		var cpus []int32
+		for _, cpu := range n.GuestCPUs.ToSlice() {
+			cpus = append(cpus, int32(cpu))
+		}
+
+		node := chclient.NewNumaConfig(int32(i))
+		node.SetCpus(cpus)
+		node.SetMemoryZones([]string{zone.Id})
+		numa = append(numa, *node)
+	}
+
+	clh.vmconfig.Memory.SetZones(zones)
+	clh.vmconfig.SetNuma(numa)
+}
+
 func (clh *cloudHypervisor) addVSock(cid int64, path string) {
 	clh.Logger().WithFields(log.Fields{
 		"path": path,
@@ -1576,6 +1797,10 @@ func (clh *cloudHypervisor) getDiskRateLimiterConfig() *chclient.RateLimiterConf
 func (clh *cloudHypervisor) addNet(e Endpoint) error {
 	clh.Logger().WithField("endpoint-type", e).Debugf("Adding Endpoint of type %v", e)
 
+	if ep, ok := e.(*VDPAEndpoint); ok {
+		return clh.addVDPANet(ep)
+	}
+
 	mac := e.HardwareAddr()
 	netPair := e.NetworkPair()
 	if netPair == nil {
@@ -1606,6 +1831,27 @@ func (clh *cloudHypervisor) addNet(e Endpoint) error {
 	return nil
 }
 
+// addVDPANet adds a vhost-vdpa backed network device to the VM configuration.
+func (clh *cloudHypervisor) addVDPANet(e *VDPAEndpoint) error {
+	id := e.netdevID()
+
+	vdpa := chclient.NewVdpaConfig(e.VhostVDPAPath, clhVdpaNetNumQueues)
+	vdpa.SetId(id)
+
+	if clh.vmconfig.Vdpa != nil {
+		*clh.vmconfig.Vdpa = append(*clh.vmconfig.Vdpa, *vdpa)
+	} else {
+		clh.vmconfig.Vdpa = &[]chclient.VdpaConfig{*vdpa}
+	}
+
+	// cold plugged devices keep the ID they were given
+	clh.devicesIds[id] = id
+
+	clh.Logger().Infof("Storing the Cloud Hypervisor vdpa configuration: %+v", vdpa)
+
+	return nil
+}
+
 // Add shared Volume using virtiofs
 func (clh *cloudHypervisor) addVolume(volume types.Volume) error {
 	if clh.config.SharedFS != config.VirtioFS && clh.config.SharedFS != config.VirtioFSNydus {
@@ -1718,3 +1964,19 @@ func (clh *cloudHypervisor) vmInfo() (chclient.VmInfo, error) {
 func (clh *cloudHypervisor) IsRateLimiterBuiltin() bool {
 	return true
 }
+
+func (clh *cloudHypervisor) UpdateBlockRateLimiter(ctx context.Context, drive *config.BlockDrive, rateLimiter *config.BlockRateLimiter) error {
+	return errors.New("cloud-hypervisor does not support updating block rate limiters")
+}
+
+func (clh *cloudHypervisor) ResizeBlockDevice(ctx context.Context, drive *config.BlockDrive, size uint64) error {
+	return errors.New("cloud-hypervisor does not support resizing block devices")
+}
+
+func (clh *cloudHypervisor) ResizeBalloon(ctx context.Context, memMB uint32) error {
+	return errors.New("cloud-hypervisor does not support memory ballooning")
+}
+
+func (clh *cloudHypervisor) SnapshotBlockDevice(ctx context.Context, drive *config.BlockDrive, snapshotPath string) error {
+	return errors.New("cloud-hypervisor does not support block device snapshots")
+}
@@ -14,11 +14,13 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
 	chclient "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cloud-hypervisor/client"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cpuset"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 	"github.com/pkg/errors"
@@ -70,7 +72,8 @@ func newClhConfig() (HypervisorConfig, error) {
 }
 
 type clhClientMock struct {
-	vmInfo chclient.VmInfo
+	resizedZone chclient.VmResizeZone
+	vmInfo      chclient.VmInfo
 }
 
 func (c *clhClientMock) VmmPingGet(ctx context.Context) (chclient.VmmPingResponse, *http.Response, error) {
@@ -101,6 +104,12 @@ func (c *clhClientMock) VmResizePut(ctx context.Context, vmResize chclient.VmRes
 	return nil, nil
 }
 
+//nolint:golint
+func (c *clhClientMock) VmResizeZonePut(ctx context.Context, vmResizeZone chclient.VmResizeZone) (*http.Response, error) {
+	c.resizedZone = vmResizeZone
+	return nil, nil
+}
+
 //nolint:golint
 func (c *clhClientMock) VmAddDevicePut(ctx context.Context, deviceConfig chclient.DeviceConfig) (chclient.PciDeviceInfo, *http.Response, error) {
 	return chclient.PciDeviceInfo{}, nil, nil
@@ -111,6 +120,11 @@ func (c *clhClientMock) VmAddDiskPut(ctx context.Context, diskConfig chclient.Di
 	return chclient.PciDeviceInfo{Bdf: "0000:00:0a.0"}, nil, nil
 }
 
+//nolint:golint
+func (c *clhClientMock) VmAddVdpaPut(ctx context.Context, vdpaConfig chclient.VdpaConfig) (chclient.PciDeviceInfo, *http.Response, error) {
+	return chclient.PciDeviceInfo{Id: vdpaConfig.GetId(), Bdf: "0000:00:0b.0"}, nil, nil
+}
+
 //nolint:golint
 func (c *clhClientMock) VmRemoveDevicePut(ctx context.Context, vmRemoveDevice chclient.VmRemoveDevice) (*http.Response, error) {
 	return nil, nil
@@ -504,6 +518,95 @@ func TestClhCreateVM(t *testing.T) {
 	assert.Exactly(clhConfig, clh.config)
 }
 
+func TestClhCreateVMRestore(t *testing.T) {
+	assert := assert.New(t)
+
+	clhConfig, err := newClhConfig()
+	assert.NoError(err)
+
+	store, err := persist.GetDriver()
+	assert.NoError(err)
+
+	clhConfig.VMStorePath = store.RunVMStoragePath()
+	clhConfig.RunStorePath = store.RunStoragePath()
+
+	network, err := NewNetwork()
+	assert.NoError(err)
+
+	// A sandbox loaded from its state talks to the running VMM.
+	clh := &cloudHypervisor{}
+	clh.state.PID = 1
+	clh.state.apiSocket = filepath.Join(t.TempDir(), "clh-api.sock")
+
+	assert.NoError(clh.CreateVM(context.Background(), "testSandbox", network, &clhConfig))
+	assert.NotNil(clh.APIClient)
+}
+
+func TestClhStopVMNotStarted(t *testing.T) {
+	assert := assert.New(t)
+
+	clhConfig, err := newClhConfig()
+	assert.NoError(err)
+	clhConfig.VMStorePath = t.TempDir()
+	clhConfig.RunStorePath = t.TempDir()
+
+	clh := &cloudHypervisor{
+		id:             "testSandbox",
+		config:         clhConfig,
+		virtiofsDaemon: &virtiofsdMock{},
+	}
+	clh.state.VirtiofsDaemonPid = 100
+
+	// Cloud Hypervisor failed to start, virtiofsd still has to be stopped.
+	assert.NoError(clh.StopVM(context.Background(), false))
+	assert.Zero(clh.state.VirtiofsDaemonPid)
+}
+
+func TestClhOnSwtpmQuit(t *testing.T) {
+	assert := assert.New(t)
+
+	clh := &cloudHypervisor{}
+
+	// Cloud Hypervisor is not running
+	clh.onSwtpmQuit(context.Background())
+	assert.Zero(atomic.LoadInt32(&clh.stopped))
+
+	// the VM is being stopped
+	clh.state.PID = os.Getpid()
+	atomic.StoreInt32(&clh.stopping, 1)
+	clh.onSwtpmQuit(context.Background())
+	assert.Zero(atomic.LoadInt32(&clh.stopped))
+}
+
+func TestClhCreateVMWithVTPM(t *testing.T) {
+	assert := assert.New(t)
+
+	clhConfig, err := newClhConfig()
+	assert.NoError(err)
+
+	store, err := persist.GetDriver()
+	assert.NoError(err)
+
+	clhConfig.VMStorePath = store.RunVMStoragePath()
+	clhConfig.RunStorePath = store.RunStoragePath()
+	clhConfig.EnableVTPM = true
+
+	network, err := NewNetwork()
+	assert.NoError(err)
+
+	clh := &cloudHypervisor{
+		config: clhConfig,
+	}
+
+	err = clh.CreateVM(context.Background(), "testSandbox", network, &clhConfig)
+	assert.NoError(err)
+
+	assert.NotNil(clh.swtpm)
+	assert.Equal(defaultSwtpmPath, clh.swtpm.path)
+	assert.NotNil(clh.vmconfig.Tpm)
+	assert.Equal(clh.swtpm.socketPath, clh.vmconfig.Tpm.Socket)
+}
+
 func TestCloudHypervisorStartSandbox(t *testing.T) {
 	assert := assert.New(t)
 	clhConfig, err := newClhConfig()
@@ -586,6 +689,66 @@ func TestCloudHypervisorResizeMemory(t *testing.T) {
 	}
 }
 
+func TestCloudHypervisorResizeMemoryZones(t *testing.T) {
+	assert := assert.New(t)
+	clhConfig, err := newClhConfig()
+	assert.NoError(err)
+
+	zone0 := chclient.NewMemoryZoneConfig("mem0", int64(1*utils.GiB.ToBytes()))
+	zone0.SetHotplugSize(int64(40 * utils.GiB.ToBytes()))
+	zone1 := chclient.NewMemoryZoneConfig("mem1", int64(1*utils.GiB.ToBytes()))
+
+	mockClient := &clhClientMock{}
+	mockClient.vmInfo.Config = *chclient.NewVmConfig(*chclient.NewPayloadConfig())
+	mockClient.vmInfo.Config.Memory = chclient.NewMemoryConfig(0)
+	mockClient.vmInfo.Config.Memory.SetZones([]chclient.MemoryZoneConfig{*zone0, *zone1})
+
+	clh := cloudHypervisor{
+		APIClient: mockClient,
+		config:    clhConfig,
+	}
+
+	newMem, memDev, err := clh.ResizeMemory(context.Background(), 2048+128, 128, false)
+	assert.NoError(err)
+	assert.Equal(uint32(2048+128), newMem)
+	assert.Equal(MemoryDevice{SizeMB: 128}, memDev)
+	assert.Equal("mem0", mockClient.resizedZone.GetId())
+	assert.Equal(int64((1024+128)*utils.MiB.ToBytes()), mockClient.resizedZone.GetDesiredRam())
+}
+
+func TestCloudHypervisorSetupNUMATopology(t *testing.T) {
+	assert := assert.New(t)
+	clhConfig, err := newClhConfig()
+	assert.NoError(err)
+
+	clhConfig.NUMANodes = []NUMANode{
+		{HostNode: 0, MemorySize: 1024, GuestCPUs: cpuset.NewCPUSet(0, 2)},
+		{HostNode: 1, MemorySize: 512, GuestCPUs: cpuset.NewCPUSet(1, 3)},
+	}
+
+	clh := cloudHypervisor{config: clhConfig}
+	clh.vmconfig = *chclient.NewVmConfig(*chclient.NewPayloadConfig())
+	clh.vmconfig.Memory = chclient.NewMemoryConfig(int64(1536 * utils.MiB.ToBytes()))
+	clh.vmconfig.Memory.SetHotplugSize(int64(4 * utils.GiB.ToBytes()))
+
+	clh.setupNUMATopology()
+
+	assert.Equal(int64(0), clh.vmconfig.Memory.Size)
+	assert.Nil(clh.vmconfig.Memory.HotplugSize)
+
+	zones := clh.vmconfig.Memory.GetZones()
+	assert.Len(zones, 2)
+	assert.Equal(int64(1024*utils.MiB.ToBytes()), zones[0].Size)
+	assert.Equal(int64(4*utils.GiB.ToBytes()), zones[0].GetHotplugSize())
+	assert.Equal(int32(1), zones[1].GetHostNumaNode())
+	assert.Nil(zones[1].HotplugSize)
+
+	numa := clh.vmconfig.GetNuma()
+	assert.Len(numa, 2)
+	assert.Equal([]int32{1, 3}, numa[1].GetCpus())
+	assert.Equal([]string{"mem1"}, numa[1].GetMemoryZones())
+}
+
 func TestCloudHypervisorHotplugAddBlockDevice(t *testing.T) {
 	assert := assert.New(t)
 
@@ -609,6 +772,53 @@ func TestCloudHypervisorHotplugAddBlockDevice(t *testing.T) {
 	assert.Error(err, "Hotplug block device not using 'virtio-blk' expected error")
 }
 
+func TestCloudHypervisorHotplugVDPA(t *testing.T) {
+	assert := assert.New(t)
+
+	clhConfig, err := newClhConfig()
+	assert.NoError(err)
+
+	clh := &cloudHypervisor{}
+	clh.config = clhConfig
+	clh.APIClient = &clhClientMock{}
+	clh.devicesIds = make(map[string]string)
+
+	vdpaDev := &config.VDPADev{ID: "vdpa-dev", HostPath: "/dev/vhost-vdpa-0"}
+	_, err = clh.HotplugAddDevice(context.Background(), vdpaDev, VDPADev)
+	assert.NoError(err)
+	assert.Equal("0b", vdpaDev.PCIPath.String())
+	assert.Contains(clh.devicesIds, vdpaDev.ID)
+
+	ep := &VDPAEndpoint{IfaceName: "vdpa0", VhostVDPAPath: "/dev/vhost-vdpa-1", EndpointType: VDPAEndpointType}
+	_, err = clh.HotplugAddDevice(context.Background(), ep, NetDev)
+	assert.NoError(err)
+	assert.Equal("0b", ep.PciPath().String())
+	assert.Contains(clh.devicesIds, ep.netdevID())
+
+	_, err = clh.HotplugRemoveDevice(context.Background(), vdpaDev, VDPADev)
+	assert.NoError(err)
+	_, err = clh.HotplugRemoveDevice(context.Background(), ep, NetDev)
+	assert.NoError(err)
+	assert.Empty(clh.devicesIds)
+}
+
+func TestCloudHypervisorAddNetVDPA(t *testing.T) {
+	assert := assert.New(t)
+
+	clh := &cloudHypervisor{}
+	clh.devicesIds = make(map[string]string)
+
+	ep := &VDPAEndpoint{IfaceName: "vdpa0", VhostVDPAPath: "/dev/vhost-vdpa-0", EndpointType: VDPAEndpointType}
+	err := clh.AddDevice(context.Background(), ep, NetDev)
+	assert.NoError(err)
+
+	assert.Nil(clh.netDevices)
+	assert.NotNil(clh.vmconfig.Vdpa)
+	assert.Len(*clh.vmconfig.Vdpa, 1)
+	assert.Equal(ep.VhostVDPAPath, (*clh.vmconfig.Vdpa)[0].Path)
+	assert.Equal(ep.netdevID(), (*clh.vmconfig.Vdpa)[0].GetId())
+}
+
 func TestCloudHypervisorHotplugRemoveDevice(t *testing.T) {
 	assert := assert.New(t)
 
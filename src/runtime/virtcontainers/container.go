@@ -331,6 +331,14 @@ type Container struct {
 
 	mounts []Mount
 
+	// guestHookMounts are the guest hooks shared with the guest for
+	// this container.
+	guestHookMounts []Mount
+
+	// guestHooks are the guest hooks delivered to the guest for this
+	// container.
+	guestHooks []guestHook
+
 	devices []ContainerDevice
 
 	state types.ContainerState
@@ -564,6 +572,21 @@ func (c *Container) unmountHostMounts(ctx context.Context) error {
 		}
 	}
 
+	for _, m := range c.mounts {
+		if filepath.Dir(m.Source) == nfsVolumesPath(c.sandboxID) {
+			if err := unmountNFSVolume(m.Source); err != nil {
+				return err
+			}
+		}
+	}
+
+	for len(c.guestHookMounts) > 0 {
+		if err := unmountFunc(c.guestHookMounts[0]); err != nil {
+			return err
+		}
+		c.guestHookMounts = c.guestHookMounts[1:]
+	}
+
 	return nil
 }
 
@@ -598,6 +621,11 @@ func (c *Container) createBlockDevices(ctx context.Context) error {
 		return nil
 	}
 
+	rateLimiter, err := c.diskRateLimiter()
+	if err != nil {
+		return err
+	}
+
 	// iterate all mounts and create block device if it's block based.
 	for i := range c.mounts {
 		if len(c.mounts[i].BlockDeviceID) > 0 {
@@ -634,6 +662,13 @@ func (c *Container) createBlockDevices(ctx context.Context) error {
 				}
 			}
 
+			if mntInfo.Type() == volume.NFSVolumeType {
+				if err := c.mountNFSVolume(&c.mounts[i], mntInfo, readonly); err != nil {
+					return err
+				}
+				continue
+			}
+
 			c.mounts[i].Source = mntInfo.Device
 			c.mounts[i].Type = mntInfo.FsType
 			c.mounts[i].Options = mntInfo.Options
@@ -668,9 +703,21 @@ func (c *Container) createBlockDevices(ctx context.Context) error {
 		var di *config.DeviceInfo
 		var err error
 
+		volumeType := ""
+		if mntInfo != nil {
+			volumeType = mntInfo.Type()
+		}
+
 		// Check if mount is a block device file. If it is, the block device will be attached to the host
 		// instead of passing this as a shared mount.
-		if stat.Mode&unix.S_IFBLK == unix.S_IFBLK {
+		if volumeType == volume.FileVolumeType || volumeType == volume.VhostUserBlkVolumeType {
+			if di, err = directVolumeDeviceInfo(volumeType, c.mounts[i], stat); err != nil {
+				return err
+			}
+			if volumeType == volume.FileVolumeType {
+				di.RateLimiter = rateLimiter
+			}
+		} else if stat.Mode&unix.S_IFBLK == unix.S_IFBLK {
 			di = &config.DeviceInfo{
 				HostPath:      c.mounts[i].Source,
 				ContainerPath: c.mounts[i].Destination,
@@ -678,6 +725,7 @@ func (c *Container) createBlockDevices(ctx context.Context) error {
 				Major:         int64(unix.Major(uint64(stat.Rdev))),
 				Minor:         int64(unix.Minor(uint64(stat.Rdev))),
 				ReadOnly:      c.mounts[i].ReadOnly,
+				RateLimiter:   rateLimiter,
 			}
 			// Check whether source can be used as a pmem device
 		} else if di, err = config.PmemDeviceInfo(c.mounts[i].Source, c.mounts[i].Destination); err != nil {
@@ -704,6 +752,56 @@ func (c *Container) createBlockDevices(ctx context.Context) error {
 	return nil
 }
 
+// mountNFSVolume mounts the NFS export of a direct assigned volume on the
+// host, and points the volume mount to it, so that the export is shared
+// with the guest like any other bind mount.
+func (c *Container) mountNFSVolume(m *Mount, mntInfo *volume.MountInfo, readonly bool) error {
+	target := nfsVolumeHostPath(c.sandboxID, c.id, mntInfo.Device)
+	if err := os.MkdirAll(target, DirMode); err != nil {
+		return err
+	}
+
+	if err := mountNFS(mntInfo.Device, target, mntInfo.NFSFsType(), mntInfo.Options); err != nil {
+		os.Remove(target)
+		return err
+	}
+
+	m.Source = target
+	m.ReadOnly = m.ReadOnly || readonly
+
+	return nil
+}
+
+// directVolumeDeviceInfo returns the information of the device backing a
+// direct assigned volume which is not a host block device.
+func directVolumeDeviceInfo(volumeType string, m Mount, stat unix.Stat_t) (*config.DeviceInfo, error) {
+	di := &config.DeviceInfo{
+		HostPath:      m.Source,
+		ContainerPath: m.Destination,
+		DevType:       "b",
+		Minor:         -1,
+		ReadOnly:      m.ReadOnly,
+		BackingFile:   true,
+	}
+
+	switch volumeType {
+	case volume.FileVolumeType:
+		if stat.Mode&unix.S_IFMT != unix.S_IFREG {
+			return nil, fmt.Errorf("direct volume %q is not a regular file", m.Source)
+		}
+		di.Major = -1
+	case volume.VhostUserBlkVolumeType:
+		if stat.Mode&unix.S_IFMT != unix.S_IFSOCK {
+			return nil, fmt.Errorf("direct volume %q is not a socket", m.Source)
+		}
+		di.Major = config.VhostUserBlkMajor
+	default:
+		return nil, fmt.Errorf("unsupported direct volume type %q", volumeType)
+	}
+
+	return di, nil
+}
+
 func (c *Container) initConfigResourcesMemory() {
 	ociSpec := c.GetPatchedOCISpec()
 	c.config.Resources.Memory = &specs.LinuxMemory{}
@@ -784,6 +882,35 @@ func newContainer(ctx context.Context, sandbox *Sandbox, contConfig *ContainerCo
 	return c, nil
 }
 
+// diskRateLimiter returns the I/O limits requested through annotations for
+// the block device volumes of the container, nil if there are none.
+func (c *Container) diskRateLimiter() (*config.BlockRateLimiter, error) {
+	var rateLimiter config.BlockRateLimiter
+
+	for annotation, value := range map[string]*int64{
+		vcAnnotations.ContainerResourcesDiskRateLimiterBwMaxRate:       &rateLimiter.BwMaxRate,
+		vcAnnotations.ContainerResourcesDiskRateLimiterBwOneTimeBurst:  &rateLimiter.BwOneTimeBurst,
+		vcAnnotations.ContainerResourcesDiskRateLimiterOpsMaxRate:      &rateLimiter.OpsMaxRate,
+		vcAnnotations.ContainerResourcesDiskRateLimiterOpsOneTimeBurst: &rateLimiter.OpsOneTimeBurst,
+	} {
+		str, ok := c.config.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseUint(str, 0, 63)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid container configuration Annotations %s %v", annotation, err)
+		}
+		*value = int64(v)
+	}
+
+	if rateLimiter.IsEmpty() {
+		return nil, nil
+	}
+
+	return &rateLimiter, nil
+}
+
 func (c *Container) createMounts(ctx context.Context) error {
 	// Create block devices for newly created container
 	return c.createBlockDevices(ctx)
@@ -881,6 +1008,7 @@ func (c *Container) create(ctx context.Context) (err error) {
 	c.getSystemMountInfo()
 
 	process, err := c.sandbox.agent.createContainer(ctx, c.sandbox, c)
+	c.publishGuestHookStageRun(guestPrestart, err)
 	if err != nil {
 		return err
 	}
@@ -951,7 +1079,9 @@ func (c *Container) start(ctx context.Context) error {
 		return err
 	}
 
-	if err := c.sandbox.agent.startContainer(ctx, c.sandbox, c); err != nil {
+	err := c.sandbox.agent.startContainer(ctx, c.sandbox, c)
+	c.publishGuestHookStageRun(guestPoststart, err)
+	if err != nil {
 		c.Logger().WithError(err).Error("Failed to start container")
 
 		if err := c.stop(ctx, true); err != nil {
@@ -1002,7 +1132,9 @@ func (c *Container) stop(ctx context.Context, force bool) error {
 		}
 	}()
 
-	if err := c.sandbox.agent.stopContainer(ctx, c.sandbox, *c); err != nil && !force {
+	err := c.sandbox.agent.stopContainer(ctx, c.sandbox, *c)
+	c.publishGuestHookStageRun(guestPoststop, err)
+	if err != nil && !force {
 		return err
 	}
 
@@ -1119,6 +1251,13 @@ func (c *Container) stats(ctx context.Context) (*ContainerStats, error) {
 	return c.sandbox.agent.statsContainer(ctx, c.sandbox, *c)
 }
 
+func (c *Container) processes(ctx context.Context) ([]*grpc.GuestProcess, error) {
+	if err := c.checkSandboxRunning("processes"); err != nil {
+		return nil, err
+	}
+	return c.sandbox.agent.listProcesses(ctx, c)
+}
+
 func (c *Container) update(ctx context.Context, resources specs.LinuxResources) error {
 	if err := c.checkSandboxRunning("update"); err != nil {
 		return err
@@ -1203,7 +1342,7 @@ func (c *Container) resume(ctx context.Context) error {
 }
 
 // hotplugDrive will attempt to hotplug the container rootfs if it is backed by a
-// block device
+// block device or by a filesystem image file
 func (c *Container) hotplugDrive(ctx context.Context) error {
 	var dev device
 	var err error
@@ -1216,9 +1355,14 @@ func (c *Container) hotplugDrive(ctx context.Context) error {
 		if c.sandbox.config.ServiceOffload && c.rootFs.Source == "" {
 			return nil
 		}
-		dev, err = getDeviceForPath(c.rootFs.Source)
 		// there is no "rootfs" dir on block device backed rootfs
 		c.rootfsSuffix = ""
+
+		if IsImageFileRootfs(c.rootFs.Type, c.rootFs.Source) {
+			return c.plugImageFile(ctx)
+		}
+
+		dev, err = getDeviceForPath(c.rootFs.Source)
 	} else {
 		dev, err = getDeviceForPath(c.rootFs.Target)
 	}
@@ -1284,30 +1428,71 @@ func (c *Container) plugDevice(ctx context.Context, devicePath string) error {
 	}
 
 	if c.checkBlockDeviceSupport(ctx) && stat.Mode&unix.S_IFBLK == unix.S_IFBLK {
-		b, err := c.sandbox.devManager.NewDevice(config.DeviceInfo{
+		return c.attachRootfsDevice(ctx, config.DeviceInfo{
 			HostPath:      devicePath,
 			ContainerPath: filepath.Join(kataGuestSharedDir(), c.id),
 			DevType:       "b",
 			Major:         int64(unix.Major(uint64(stat.Rdev))),
 			Minor:         int64(unix.Minor(uint64(stat.Rdev))),
 		})
-		if err != nil {
-			return fmt.Errorf("device manager failed to create rootfs device for %q: %v", devicePath, err)
-		}
+	}
+	return nil
+}
 
-		c.state.BlockDeviceID = b.DeviceID()
+// plugImageFile attaches a filesystem image file rootfs as a file backed
+// block device, without a loop device on the host.
+func (c *Container) plugImageFile(ctx context.Context) error {
+	imagePath, err := filepath.EvalSymlinks(c.rootFs.Source)
+	if err != nil {
+		return err
+	}
 
-		// attach rootfs device
-		if err := c.sandbox.devManager.AttachDevice(ctx, b.DeviceID(), c.sandbox); err != nil {
-			return err
+	readOnly := c.rootFs.Type == "erofs"
+	for _, o := range c.rootFs.Options {
+		if o == "ro" {
+			readOnly = true
 		}
 	}
-	return nil
+
+	c.Logger().WithFields(logrus.Fields{
+		"image-path": imagePath,
+		"fs-type":    c.rootFs.Type,
+		"read-only":  readOnly,
+	}).Info("Image file rootfs detected")
+
+	if err := c.attachRootfsDevice(ctx, config.DeviceInfo{
+		HostPath:      imagePath,
+		ContainerPath: filepath.Join(kataGuestSharedDir(), c.id),
+		DevType:       "b",
+		Major:         -1,
+		Minor:         -1,
+		ReadOnly:      readOnly,
+		BackingFile:   true,
+	}); err != nil {
+		return err
+	}
+
+	return c.setStateFstype(c.rootFs.Type)
+}
+
+// attachRootfsDevice creates and attaches the block device backing the
+// container rootfs. The device ID is saved before the device is attached,
+// so that removeDrive detaches it whatever fails next.
+func (c *Container) attachRootfsDevice(ctx context.Context, di config.DeviceInfo) error {
+	b, err := c.sandbox.devManager.NewDevice(di)
+	if err != nil {
+		return fmt.Errorf("device manager failed to create rootfs device for %q: %v", di.HostPath, err)
+	}
+
+	c.state.BlockDeviceID = b.DeviceID()
+
+	// attach rootfs device
+	return c.sandbox.devManager.AttachDevice(ctx, b.DeviceID(), c.sandbox)
 }
 
 // isDriveUsed checks if a drive has been used for container rootfs
 func (c *Container) isDriveUsed() bool {
-	return !(c.state.Fstype == "")
+	return c.state.BlockDeviceID != ""
 }
 
 func (c *Container) removeDrive(ctx context.Context) (err error) {
@@ -1331,6 +1516,8 @@ func (c *Container) removeDrive(ctx context.Context) (err error) {
 				return err
 			}
 		}
+
+		c.state.BlockDeviceID = ""
 	}
 
 	return nil
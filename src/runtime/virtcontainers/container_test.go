@@ -7,6 +7,8 @@ package virtcontainers
 
 import (
 	"context"
+	"errors"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,10 +20,13 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/drivers"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/manager"
+	volume "github.com/kata-containers/kata-containers/src/runtime/pkg/direct-volume"
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
 )
 
 func TestGetAnnotations(t *testing.T) {
@@ -360,6 +365,53 @@ func TestContainerAddDriveDir(t *testing.T) {
 	assert.NotEmpty(container.state.Fstype)
 }
 
+func TestContainerPlugImageFileRootfs(t *testing.T) {
+	assert := assert.New(t)
+
+	image := filepath.Join(t.TempDir(), "rootfs.erofs")
+	assert.NoError(os.WriteFile(image, []byte("erofs"), 0600))
+
+	sandbox := &Sandbox{
+		ctx:        context.Background(),
+		id:         testSandboxID,
+		devManager: manager.NewDeviceManager(config.VirtioBlock, false, "", nil),
+		hypervisor: &mockHypervisor{},
+		agent:      &mockAgent{},
+		config:     &SandboxConfig{},
+		state: types.SandboxState{
+			BlockIndexMap: make(map[int]struct{}),
+		},
+	}
+
+	container := Container{
+		sandbox:      sandbox,
+		id:           "100",
+		rootFs:       RootFs{Source: image, Type: "erofs"},
+		rootfsSuffix: "rootfs",
+	}
+
+	err := container.hotplugDrive(sandbox.ctx)
+	assert.NoError(err)
+	assert.Empty(container.rootfsSuffix)
+	assert.Equal("erofs", container.state.Fstype)
+	assert.NotEmpty(container.state.BlockDeviceID)
+
+	device := sandbox.devManager.GetDeviceByID(container.state.BlockDeviceID)
+	assert.NotNil(device)
+	drive, ok := device.GetDeviceInfo().(*config.BlockDrive)
+	assert.True(ok)
+	assert.Equal(image, drive.File)
+	assert.True(drive.ReadOnly)
+	assert.Len(sandbox.state.BlockIndexMap, 1)
+
+	// the drive is detached and released when the container is deleted
+	err = container.removeDrive(sandbox.ctx)
+	assert.NoError(err)
+	assert.Empty(container.state.BlockDeviceID)
+	assert.Nil(sandbox.devManager.GetDeviceByID(device.DeviceID()))
+	assert.Empty(sandbox.state.BlockIndexMap)
+}
+
 func TestContainerRootfsPath(t *testing.T) {
 
 	testRawFile, loopDev, fakeRootfs, err := testSetupFakeRootfs(t)
@@ -716,3 +768,143 @@ func TestConfigValid(t *testing.T) {
 	result = config.valid()
 	assert.True(result)
 }
+
+func TestContainerDiskRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &Container{
+		config: &ContainerConfig{
+			Annotations: map[string]string{},
+		},
+	}
+
+	rateLimiter, err := c.diskRateLimiter()
+	assert.NoError(err)
+	assert.Nil(rateLimiter)
+
+	c.config.Annotations[vcAnnotations.ContainerResourcesDiskRateLimiterBwMaxRate] = "1000000"
+	c.config.Annotations[vcAnnotations.ContainerResourcesDiskRateLimiterOpsOneTimeBurst] = "50"
+	rateLimiter, err = c.diskRateLimiter()
+	assert.NoError(err)
+	assert.Equal(&config.BlockRateLimiter{BwMaxRate: 1000000, OpsOneTimeBurst: 50}, rateLimiter)
+
+	c.config.Annotations[vcAnnotations.ContainerResourcesDiskRateLimiterOpsMaxRate] = "-1"
+	_, err = c.diskRateLimiter()
+	assert.Error(err)
+}
+
+func TestDirectVolumeDeviceInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	image := filepath.Join(dir, "volume.img")
+	assert.NoError(os.WriteFile(image, nil, 0600))
+
+	socket := filepath.Join(dir, "vhost-blk.sock")
+	l, err := net.Listen("unix", socket)
+	assert.NoError(err)
+	defer l.Close()
+
+	statMount := func(m Mount) unix.Stat_t {
+		var stat unix.Stat_t
+		assert.NoError(unix.Stat(m.Source, &stat))
+		return stat
+	}
+
+	m := Mount{Source: image, Destination: "/data", ReadOnly: true}
+	di, err := directVolumeDeviceInfo(volume.FileVolumeType, m, statMount(m))
+	assert.NoError(err)
+	assert.Equal(&config.DeviceInfo{
+		HostPath:      image,
+		ContainerPath: "/data",
+		DevType:       "b",
+		Major:         -1,
+		Minor:         -1,
+		ReadOnly:      true,
+		BackingFile:   true,
+	}, di)
+
+	_, err = directVolumeDeviceInfo(volume.VhostUserBlkVolumeType, m, statMount(m))
+	assert.Error(err)
+
+	m = Mount{Source: socket, Destination: "/data"}
+	di, err = directVolumeDeviceInfo(volume.VhostUserBlkVolumeType, m, statMount(m))
+	assert.NoError(err)
+	assert.Equal(int64(config.VhostUserBlkMajor), di.Major)
+	assert.True(di.BackingFile)
+
+	_, err = directVolumeDeviceInfo(volume.FileVolumeType, m, statMount(m))
+	assert.Error(err)
+
+	_, err = directVolumeDeviceInfo(volume.BlockVolumeType, m, statMount(m))
+	assert.Error(err)
+}
+
+func TestContainerMountNFSVolume(t *testing.T) {
+	assert := assert.New(t)
+
+	kataHostSharedDirSaved := kataHostSharedDir
+	testHostDir := t.TempDir()
+	kataHostSharedDir = func() string {
+		return testHostDir
+	}
+	defer func() {
+		kataHostSharedDir = kataHostSharedDirSaved
+	}()
+
+	mountNFSSaved := mountNFS
+	var mounted []string
+	mountNFS = func(export, target, fsType string, options []string) error {
+		mounted = append(mounted, export, target, fsType, strings.Join(options, ","))
+		return nil
+	}
+	defer func() {
+		mountNFS = mountNFSSaved
+	}()
+
+	sandbox := &Sandbox{
+		id:         "nfs-sandbox",
+		hypervisor: &mockHypervisor{},
+		containers: map[string]*Container{},
+	}
+	c := &Container{
+		id:        "nfs-container",
+		sandboxID: sandbox.id,
+		sandbox:   sandbox,
+		mounts: []Mount{
+			{Source: "/var/lib/kubelet/volume", Destination: "/data", Type: "bind", Options: []string{"rbind"}},
+		},
+	}
+	sandbox.containers[c.id] = c
+
+	mntInfo := &volume.MountInfo{
+		VolumeType: volume.NFSVolumeType,
+		Device:     "nfs.example.com:/exports/volume",
+		Options:    []string{"ro", "vers=4.1"},
+	}
+	assert.NoError(c.mountNFSVolume(&c.mounts[0], mntInfo, true))
+
+	target := nfsVolumeHostPath(sandbox.id, c.id, mntInfo.Device)
+	assert.Equal([]string{mntInfo.Device, target, "nfs", "ro,vers=4.1"}, mounted)
+	assert.DirExists(target)
+	assert.Equal(Mount{Source: target, Destination: "/data", Type: "bind", Options: []string{"rbind"}, ReadOnly: true}, c.mounts[0])
+
+	// the export is only reachable in the guest once shared
+	_, ok := sandbox.nfsVolumeGuestPath(mntInfo.Device)
+	assert.False(ok)
+
+	c.mounts[0].HostPath = filepath.Join(getMountPath(sandbox.id), "nfs-container-0123-data")
+	guestPath, ok := sandbox.nfsVolumeGuestPath(mntInfo.Device)
+	assert.True(ok)
+	assert.Equal(filepath.Join(kataGuestSharedDir(), "nfs-container-0123-data"), guestPath)
+
+	assert.Error(sandbox.ResizeGuestVolume(context.Background(), mntInfo.Device, 4096))
+
+	mountNFS = func(export, target, fsType string, options []string) error {
+		return errors.New("mount failed")
+	}
+	m := Mount{Source: "/var/lib/kubelet/other", Destination: "/other", Type: "bind"}
+	mntInfo.Device = "nfs.example.com:/exports/other"
+	assert.Error(c.mountNFSVolume(&m, mntInfo, false))
+	assert.NoDirExists(nfsVolumeHostPath(sandbox.id, c.id, mntInfo.Device))
+}
@@ -42,6 +42,14 @@ func TestMacvtapEndpointTypeSet(t *testing.T) {
 	testEndpointTypeSet(t, "macvtap", MacvtapEndpointType)
 }
 
+func TestVDPAEndpointTypeSet(t *testing.T) {
+	testEndpointTypeSet(t, "vdpa", VDPAEndpointType)
+}
+
+func TestPasstEndpointTypeSet(t *testing.T) {
+	testEndpointTypeSet(t, "passt", PasstEndpointType)
+}
+
 func TestEndpointTypeSetFailure(t *testing.T) {
 	var endpointType EndpointType
 
@@ -78,6 +86,16 @@ func TestMacvtapEndpointTypeString(t *testing.T) {
 	testEndpointTypeString(t, &endpointType, string(MacvtapEndpointType))
 }
 
+func TestVDPAEndpointTypeString(t *testing.T) {
+	endpointType := VDPAEndpointType
+	testEndpointTypeString(t, &endpointType, string(VDPAEndpointType))
+}
+
+func TestPasstEndpointTypeString(t *testing.T) {
+	endpointType := PasstEndpointType
+	testEndpointTypeString(t, &endpointType, string(PasstEndpointType))
+}
+
 func TestIncorrectEndpointTypeString(t *testing.T) {
 	var endpointType EndpointType
 	testEndpointTypeString(t, &endpointType, "")
@@ -0,0 +1,154 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of a sandbox event.
+type EventType string
+
+const (
+	// EventSandboxStarted is published once all the containers of the
+	// sandbox have been started.
+	EventSandboxStarted EventType = "sandbox-started"
+
+	// EventSandboxStopped is published once the sandbox VM is stopped.
+	EventSandboxStopped EventType = "sandbox-stopped"
+
+	// EventGuestOOM is published when the guest reports an out of memory
+	// event for a container.
+	EventGuestOOM EventType = "guest-oom"
+
+	// EventHypervisorCrashed is published when the hypervisor process is
+	// found dead by the sandbox monitor.
+	EventHypervisorCrashed EventType = "hypervisor-crashed"
+
+	// EventAgentUnreachable is published when the agent does not answer the
+	// sandbox monitor anymore.
+	EventAgentUnreachable EventType = "agent-unreachable"
+
+	// EventDeviceHotplugged is published with the result of a device
+	// hotplug.
+	EventDeviceHotplugged EventType = "device-hotplugged"
+
+	// EventDeviceHotunplugged is published with the result of a device
+	// hot unplug.
+	EventDeviceHotunplugged EventType = "device-hotunplugged"
+
+	// EventVCPUsResized is published with the result of a vCPU resize.
+	EventVCPUsResized EventType = "vcpus-resized"
+
+	// EventMemoryResized is published with the result of a memory resize.
+	EventMemoryResized EventType = "memory-resized"
+
+	// EventGuestHookShared is published with the result of the delivery
+	// of a guest hook to the guest, for a container.
+	EventGuestHookShared EventType = "guest-hook-shared"
+
+	// EventGuestHookStageRun is published with the result of the agent
+	// operation running the guest hooks of a stage, for a container.
+	EventGuestHookStageRun EventType = "guest-hook-stage-run"
+)
+
+// eventChannelSize is the number of events buffered for each subscriber.
+// Events are dropped for subscribers that do not keep up.
+const eventChannelSize = 64
+
+// Event is a structured sandbox event.
+type Event struct {
+	Timestamp   time.Time         `json:"timestamp"`
+	Data        map[string]string `json:"data,omitempty"`
+	Type        EventType         `json:"type"`
+	SandboxID   string            `json:"sandbox"`
+	ContainerID string            `json:"container,omitempty"`
+	// Error is set when the event reports a failed operation.
+	Error string `json:"error,omitempty"`
+}
+
+// eventBus dispatches the events of a sandbox to its subscribers.
+// A nil eventBus drops all the events.
+type eventBus struct {
+	subscribers map[chan Event]struct{}
+	sync.Mutex
+	closed bool
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// subscribe returns a channel receiving the events published from now on,
+// and a function to call to stop receiving them. The channel is closed
+// when unsubscribing or when the bus is closed.
+func (b *eventBus) subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventChannelSize)
+
+	if b == nil {
+		close(ch)
+		return ch, func() {}
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	b.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		b.Lock()
+		defer b.Unlock()
+
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// publish sends e to all the subscribers without blocking.
+func (b *eventBus) publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+			virtLog.WithField("event", e.Type).WithField("sandbox", e.SandboxID).
+				Warn("event subscriber is too slow, dropping event")
+		}
+	}
+}
+
+// close closes all the subscriber channels. Nothing can be published or
+// subscribed to afterwards.
+func (b *eventBus) close() {
+	if b == nil {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	for ch := range b.subscribers {
+		close(ch)
+	}
+	b.subscribers = nil
+	b.closed = true
+}
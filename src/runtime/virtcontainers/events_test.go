@@ -0,0 +1,124 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBus(t *testing.T) {
+	assert := assert.New(t)
+
+	b := newEventBus()
+
+	ch1, unsubscribe1 := b.subscribe()
+	ch2, unsubscribe2 := b.subscribe()
+
+	b.publish(Event{Type: EventSandboxStarted})
+	assert.Equal(EventSandboxStarted, (<-ch1).Type)
+	assert.Equal(EventSandboxStarted, (<-ch2).Type)
+
+	unsubscribe1()
+	unsubscribe1()
+	_, ok := <-ch1
+	assert.False(ok)
+
+	b.publish(Event{Type: EventSandboxStopped})
+	assert.Equal(EventSandboxStopped, (<-ch2).Type)
+
+	// A slow subscriber does not block the publisher
+	for i := 0; i < eventChannelSize+1; i++ {
+		b.publish(Event{Type: EventGuestOOM})
+	}
+	assert.Len(ch2, eventChannelSize)
+
+	b.close()
+	unsubscribe2()
+
+	ch3, _ := b.subscribe()
+	_, ok = <-ch3
+	assert.False(ok)
+
+	// publishing after close is a no-op
+	b.publish(Event{Type: EventSandboxStopped})
+}
+
+func TestEventBusNil(t *testing.T) {
+	var b *eventBus
+
+	b.publish(Event{Type: EventSandboxStarted})
+	b.close()
+
+	ch, unsubscribe := b.subscribe()
+	defer unsubscribe()
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func TestSandboxPublishEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		id:     "sb",
+		events: newEventBus(),
+	}
+
+	events, unsubscribe := s.SubscribeEvents()
+	defer unsubscribe()
+
+	s.publishEvent(EventGuestOOM, "c1", nil, nil)
+	e := <-events
+	assert.Equal(EventGuestOOM, e.Type)
+	assert.Equal("sb", e.SandboxID)
+	assert.Equal("c1", e.ContainerID)
+	assert.Empty(e.Error)
+	assert.False(e.Timestamp.IsZero())
+
+	device := drivers.NewBlockDevice(&config.DeviceInfo{ID: "dev1", HostPath: "/dev/vdb"})
+	s.publishDeviceEvent(EventDeviceHotplugged, device, config.DeviceBlock, errors.New("hotplug failed"))
+	e = <-events
+	assert.Equal(EventDeviceHotplugged, e.Type)
+	assert.Equal("hotplug failed", e.Error)
+	assert.Equal("dev1", e.Data["device"])
+	assert.Equal("/dev/vdb", e.Data["host-path"])
+
+	// generic devices are not hotplugged
+	s.publishDeviceEvent(EventDeviceHotplugged, device, config.DeviceGeneric, nil)
+	assert.Len(events, 0)
+}
+
+func TestSandboxHotplugPublishesEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		id:         "sb",
+		events:     newEventBus(),
+		hypervisor: &mockHypervisor{},
+	}
+
+	events, unsubscribe := s.SubscribeEvents()
+	defer unsubscribe()
+
+	device := drivers.NewBlockDevice(&config.DeviceInfo{ID: "dev1", HostPath: "/dev/vdb"})
+	device.BlockDrive = &config.BlockDrive{ID: "drive1"}
+
+	err := s.HotplugAddDevice(context.Background(), device, config.DeviceBlock)
+	assert.NoError(err)
+	e := <-events
+	assert.Equal(EventDeviceHotplugged, e.Type)
+	assert.Empty(e.Error)
+
+	err = s.HotplugRemoveDevice(context.Background(), device, config.DeviceBlock)
+	assert.NoError(err)
+	e = <-events
+	assert.Equal(EventDeviceHotunplugged, e.Type)
+}
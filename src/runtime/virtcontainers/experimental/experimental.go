@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 )
 
 const (
@@ -51,6 +52,38 @@ func Get(name string) *Feature {
 	return nil
 }
 
+// Supported returns all the registered features, sorted by name
+func Supported() []Feature {
+	features := make([]Feature, 0, len(supportedFeatures))
+	for _, f := range supportedFeatures {
+		features = append(features, f)
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].Name < features[j].Name
+	})
+	return features
+}
+
+// Enabled returns true if the feature with requested name is one of features
+func Enabled(features []Feature, name string) bool {
+	for _, f := range features {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error if the feature with requested name is not one of
+// features, for the code gated by the feature to fail the same way
+// everywhere
+func Check(features []Feature, name string) error {
+	if !Enabled(features, name) {
+		return fmt.Errorf("experimental feature %q is not enabled", name)
+	}
+	return nil
+}
+
 func validateFeature(feature Feature) error {
 	if len(feature.Name) == 0 ||
 		len(feature.Description) == 0 ||
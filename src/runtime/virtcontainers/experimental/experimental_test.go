@@ -19,12 +19,14 @@ func TestExperimental(t *testing.T) {
 	}
 	assert.Nil(t, Get(f.Name))
 
+	registered := len(supportedFeatures)
 	err := Register(f)
 	assert.Nil(t, err)
+	defer delete(supportedFeatures, f.Name)
 
 	err = Register(f)
 	assert.NotNil(t, err)
-	assert.Equal(t, len(supportedFeatures), 1)
+	assert.Equal(t, len(supportedFeatures), registered+1)
 
 	assert.NotNil(t, Get(f.Name))
 }
@@ -58,3 +60,31 @@ func TestValidateFeature(t *testing.T) {
 		}
 	}
 }
+
+func TestRuntimeFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	supported := Supported()
+	assert.Len(supported, len(runtimeFeatures))
+
+	for i, f := range supported {
+		assert.NoError(validateFeature(f))
+		assert.Equal(f, *Get(f.Name))
+		if i > 0 {
+			assert.True(supported[i-1].Name < f.Name)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	assert := assert.New(t)
+
+	features := []Feature{*Get(SandboxFreeze)}
+
+	assert.True(Enabled(features, SandboxFreeze))
+	assert.False(Enabled(features, VolumeSnapshot))
+	assert.False(Enabled(nil, SandboxFreeze))
+
+	assert.NoError(Check(features, SandboxFreeze))
+	assert.EqualError(Check(features, VolumeSnapshot), `experimental feature "volume_snapshot" is not enabled`)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package experimental
+
+// The experimental features of the runtime. A feature is enabled by its
+// name, in the experimental list of the configuration file or, when
+// enable_annotations allows it, in the
+// io.katacontainers.config.runtime.experimental annotation of a pod.
+const (
+	// UnprivilegedSandbox lets an unprivileged user, e.g., a rootless
+	// containerd, create sandboxes.
+	UnprivilegedSandbox = "unprivileged_sandbox"
+
+	// SandboxFreeze lets a running sandbox be frozen and thawed.
+	SandboxFreeze = "sandbox_freeze"
+
+	// VolumeSnapshot lets the block device volumes, the block device
+	// container rootfs and the guest image of a running sandbox be
+	// snapshotted.
+	VolumeSnapshot = "volume_snapshot"
+)
+
+var runtimeFeatures = []Feature{
+	{
+		Name:        UnprivilegedSandbox,
+		Description: "run sandboxes of unprivileged users in user namespaces, with passt networking",
+		ExpRelease:  "3.3",
+	},
+	{
+		Name:        SandboxFreeze,
+		Description: "freeze the vCPUs of idle sandboxes and optionally reclaim their memory",
+		ExpRelease:  "3.3",
+	},
+	{
+		Name:        VolumeSnapshot,
+		Description: "snapshot the block device volumes, rootfs and guest image of running sandboxes",
+		ExpRelease:  "3.3",
+	},
+}
+
+func init() {
+	for _, f := range runtimeFeatures {
+		if err := Register(f); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -19,6 +19,13 @@ type Factory interface {
 	// GetVMStatus returns the status of the paused VM created by the base factory.
 	GetVMStatus() []*pb.GrpcVMStatus
 
+	// HasPools returns true if the base factory can return VMs for another
+	// config than its own.
+	HasPools() bool
+
+	// GetPoolStatus returns the status of the VM pools of the base factory.
+	GetPoolStatus() []*pb.GrpcPoolStatus
+
 	// GetVM gets a new VM from the factory.
 	GetVM(ctx context.Context, config VMConfig) (*VM, error)
 
@@ -10,6 +10,7 @@ import (
 
 	pb "github.com/kata-containers/kata-containers/src/runtime/protocols/cache"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cpuset"
 )
 
 // FactoryBase is vm factory's internal base factory interfaces.
@@ -30,3 +31,46 @@ type FactoryBase interface {
 	// CloseFactory closes the base factory.
 	CloseFactory(ctx context.Context)
 }
+
+// PoolFactoryBase is implemented by the base factories that keep pools of
+// VMs for several VM configurations.
+type PoolFactoryBase interface {
+	FactoryBase
+
+	// HasPools returns true if GetBaseVM can return VMs for another
+	// configuration than Config(). Only the CPU and memory of the
+	// returned VMs are always the ones of Config().
+	HasPools() bool
+
+	// GetPoolStatus returns the status of the VM pools.
+	GetPoolStatus() []*pb.GrpcPoolStatus
+}
+
+// ResetHypervisorConfig clears the settings of config that do not change
+// the VM booted by a base factory: the CPU and memory the factory hotplugs
+// after getting the VM, the paths of the VM instance and the settings only
+// applied once the VM is assigned to a sandbox.
+func ResetHypervisorConfig(config *vc.VMConfig) {
+	config.HypervisorConfig.NumVCPUs = 0
+	config.HypervisorConfig.MemorySize = 0
+	config.HypervisorConfig.BootToBeTemplate = false
+	config.HypervisorConfig.BootFromTemplate = false
+	config.HypervisorConfig.MemoryPath = ""
+	config.HypervisorConfig.DevicesStatePath = ""
+	config.HypervisorConfig.SharedPath = ""
+	config.HypervisorConfig.VMStorePath = ""
+	config.HypervisorConfig.RunStorePath = ""
+	config.HypervisorConfig.SandboxName = ""
+	config.HypervisorConfig.SandboxNamespace = ""
+
+	// the vCPUs are pinned to the host CPUs of the guest NUMA nodes
+	// when the sandbox starts, the guest nodes are set at boot
+	if len(config.HypervisorConfig.NUMANodes) > 0 {
+		nodes := make([]vc.NUMANode, len(config.HypervisorConfig.NUMANodes))
+		for i, n := range config.HypervisorConfig.NUMANodes {
+			n.HostCPUs = cpuset.CPUSet{}
+			nodes[i] = n
+		}
+		config.HypervisorConfig.NUMANodes = nodes
+	}
+}
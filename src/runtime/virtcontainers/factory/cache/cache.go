@@ -9,84 +9,107 @@ package cache
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	pb "github.com/kata-containers/kata-containers/src/runtime/protocols/cache"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory/base"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory/direct"
+	"github.com/sirupsen/logrus"
 )
 
+var cacheLog = logrus.WithField("source", "virtcontainers/factory/cache")
+
+// resizeInterval is the period at which the pools are resized when
+// nothing else wakes the cache up.
+var resizeInterval = 10 * time.Second
+
+// Policy controls how the cache factory sizes its VM pools.
+type Policy struct {
+	// MinVMs is the number of VMs the default pool keeps ready at all
+	// times.
+	MinVMs uint
+
+	// MaxVMs is the number of VMs a pool can grow to when VMs are
+	// requested faster than they are created. It is raised to MinVMs
+	// when lower.
+	MaxVMs uint
+
+	// MaxPools is the maximum number of pools, the default one
+	// included. 0 only allows the default pool.
+	MaxPools uint
+
+	// MemoryBudget is the memory, in MiB, all the cached VMs can use.
+	// 0 means no limit.
+	MemoryBudget uint64
+
+	// IdleTimeout is the time after which a cached VM that was not
+	// requested is destroyed, unless its pool is at its minimum size.
+	// 0 means never.
+	IdleTimeout time.Duration
+}
+
 type cache struct {
 	base base.FactoryBase
 
-	cacheCh chan *vc.VM
-	closed  chan<- int
+	pools map[string]*pool
+
+	wakeup chan struct{}
+	closed chan struct{}
 
-	vmm map[*vc.VM]interface{}
+	defaultKey string
+
+	policy Policy
 
 	wg        sync.WaitGroup
 	closeOnce sync.Once
 
-	vmmLock sync.RWMutex
+	sync.Mutex
+	isClosed bool
 }
 
-// New creates a new cached vm factory.
+// New creates a new cached vm factory keeping count VMs ready.
 func New(ctx context.Context, count uint, b base.FactoryBase) base.FactoryBase {
-	if count < 1 {
+	return NewWithPolicy(ctx, Policy{MinVMs: count}, b)
+}
+
+// NewWithPolicy creates a new cached vm factory sized according to policy.
+// VMs with the base factory config are cached in the default pool, VMs with
+// another config are cached in pools created on demand.
+func NewWithPolicy(ctx context.Context, policy Policy, b base.FactoryBase) base.FactoryBase {
+	if policy.MaxVMs < policy.MinVMs {
+		policy.MaxVMs = policy.MinVMs
+	}
+	if policy.MaxVMs < 1 {
 		return b
 	}
-
-	cacheCh := make(chan *vc.VM)
-	closed := make(chan int, count)
-	c := cache{
-		base:    b,
-		cacheCh: cacheCh,
-		closed:  closed,
-		vmm:     make(map[*vc.VM]interface{}),
-	}
-	for i := 0; i < int(count); i++ {
-		c.wg.Add(1)
-		go func() {
-			for {
-				vm, err := b.GetBaseVM(ctx, c.Config())
-				if err != nil {
-					c.wg.Done()
-					c.CloseFactory(ctx)
-					return
-				}
-				c.addToVmm(vm)
-
-				select {
-				case cacheCh <- vm:
-					// Because vm will not be relased or changed
-					// by cacheServer.GetBaseVM or removeFromVmm.
-					// So removeFromVmm can be called after vm send to cacheCh.
-					c.removeFromVmm(vm)
-				case <-closed:
-					c.removeFromVmm(vm)
-					vm.Stop(ctx)
-					vm.Disconnect(ctx)
-					c.wg.Done()
-					return
-				}
-			}
-		}()
+	if policy.MaxPools < 1 {
+		policy.MaxPools = 1
 	}
-	return &c
-}
 
-func (c *cache) addToVmm(vm *vc.VM) {
-	c.vmmLock.Lock()
-	defer c.vmmLock.Unlock()
-
-	c.vmm[vm] = nil
-}
+	c := &cache{
+		base:       b,
+		pools:      make(map[string]*pool),
+		wakeup:     make(chan struct{}, 1),
+		closed:     make(chan struct{}),
+		defaultKey: poolKey(b.Config()),
+		policy:     policy,
+	}
+	c.pools[c.defaultKey] = &pool{
+		id:       defaultPoolID,
+		config:   b.Config(),
+		base:     b,
+		min:      policy.MinVMs,
+		target:   policy.MinVMs,
+		lastUsed: time.Now(),
+	}
 
-func (c *cache) removeFromVmm(vm *vc.VM) {
-	c.vmmLock.Lock()
-	defer c.vmmLock.Unlock()
+	c.wg.Add(1)
+	go c.run(ctx)
 
-	delete(c.vmm, vm)
+	return c
 }
 
 // Config returns cache vm factory's base factory config.
@@ -94,37 +117,271 @@ func (c *cache) Config() vc.VMConfig {
 	return c.base.Config()
 }
 
+// HasPools returns true if the cache can keep VMs for other configs than
+// the default one.
+func (c *cache) HasPools() bool {
+	return c.policy.MaxPools > 1
+}
+
 // GetVMStatus returns the status of the cached VMs.
 func (c *cache) GetVMStatus() []*pb.GrpcVMStatus {
 	vs := []*pb.GrpcVMStatus{}
 
-	c.vmmLock.RLock()
-	defer c.vmmLock.RUnlock()
+	c.Lock()
+	defer c.Unlock()
 
-	for vm := range c.vmm {
-		vs = append(vs, vm.GetVMStatus())
+	for _, p := range c.sortedPools() {
+		for _, r := range p.ready {
+			vs = append(vs, r.vm.GetVMStatus())
+		}
 	}
 
 	return vs
 }
 
-// GetBaseVM returns a base VM from cache factory's base factory.
+// GetPoolStatus returns the status of the VM pools, the default one first.
+func (c *cache) GetPoolStatus() []*pb.GrpcPoolStatus {
+	ps := []*pb.GrpcPoolStatus{}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, p := range c.sortedPools() {
+		ps = append(ps, p.status())
+	}
+
+	return ps
+}
+
+// GetBaseVM returns a cached VM for config, or creates one from the
+// base factory of its pool if none is ready.
 func (c *cache) GetBaseVM(ctx context.Context, config vc.VMConfig) (*vc.VM, error) {
-	vm, ok := <-c.cacheCh
-	if ok {
+	c.Lock()
+
+	if c.isClosed {
+		c.Unlock()
+		return nil, fmt.Errorf("cache factory is closed")
+	}
+
+	p := c.getPool(config)
+	if p == nil {
+		c.Unlock()
+		cacheLog.WithField("max-pools", c.policy.MaxPools).Info("too many pools, create VM directly")
+		config = c.poolConfig(config)
+		return direct.New(ctx, config).GetBaseVM(ctx, config)
+	}
+
+	p.lastUsed = time.Now()
+
+	if len(p.ready) > 0 {
+		vm := p.ready[0].vm
+		p.ready = p.ready[1:]
+		p.hits++
+		c.Unlock()
+
+		c.wake()
 		return vm, nil
 	}
-	return nil, fmt.Errorf("cache factory is closed")
+
+	p.misses++
+	if p.target < c.policy.MaxVMs {
+		p.target++
+	}
+	b, poolConfig := p.base, p.config
+	c.Unlock()
+
+	c.wake()
+	return b.GetBaseVM(ctx, poolConfig)
 }
 
 // CloseFactory closes the cache factory.
 func (c *cache) CloseFactory(ctx context.Context) {
 	c.closeOnce.Do(func() {
-		for len(c.closed) < cap(c.closed) { // send sufficient closed signal
-			c.closed <- 0
-		}
+		c.Lock()
+		c.isClosed = true
+		c.Unlock()
+
+		close(c.closed)
 		c.wg.Wait()
-		close(c.cacheCh)
-		c.base.CloseFactory(ctx)
+
+		for _, p := range c.pools {
+			for _, r := range p.ready {
+				stopVM(ctx, r.vm)
+			}
+			p.ready = nil
+			p.base.CloseFactory(ctx)
+		}
 	})
 }
+
+// getPool returns the pool of config, creating it if the policy allows it.
+// It must be called with the cache locked.
+func (c *cache) getPool(config vc.VMConfig) *pool {
+	key := poolKey(config)
+	if p, ok := c.pools[key]; ok {
+		return p
+	}
+
+	if uint(len(c.pools)) >= c.policy.MaxPools {
+		return nil
+	}
+
+	config = c.poolConfig(config)
+	p := &pool{
+		id:       key[:poolIDLength],
+		config:   config,
+		base:     direct.New(context.Background(), config),
+		lastUsed: time.Now(),
+	}
+	c.pools[key] = p
+
+	cacheLog.WithField("pool", p.id).Info("new VM pool")
+
+	return p
+}
+
+// poolConfig returns the config used to create the VMs of the pool of
+// config. All the pools create VMs with the CPU and memory of the default
+// pool, which the factory then hotplugs to the requested sizes.
+func (c *cache) poolConfig(config vc.VMConfig) vc.VMConfig {
+	defaultConfig := c.base.Config().HypervisorConfig
+	hConfig := &config.HypervisorConfig
+
+	hConfig.NumVCPUs = defaultConfig.NumVCPUs
+	hConfig.MemorySize = defaultConfig.MemorySize
+	hConfig.BootToBeTemplate = false
+	hConfig.BootFromTemplate = false
+	hConfig.MemoryPath = ""
+	hConfig.DevicesStatePath = ""
+	hConfig.SharedPath = defaultConfig.SharedPath
+	hConfig.VMStorePath = defaultConfig.VMStorePath
+	hConfig.RunStorePath = defaultConfig.RunStorePath
+	hConfig.SandboxName = ""
+	hConfig.SandboxNamespace = ""
+
+	return config
+}
+
+// sortedPools returns the pools, the default one first and the others
+// sorted by id. It must be called with the cache locked.
+func (c *cache) sortedPools() []*pool {
+	pools := make([]*pool, 0, len(c.pools))
+	for key, p := range c.pools {
+		if key != c.defaultKey {
+			pools = append(pools, p)
+		}
+	}
+
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].id < pools[j].id
+	})
+
+	return append([]*pool{c.pools[c.defaultKey]}, pools...)
+}
+
+// wake makes the cache resize its pools without waiting for the next tick.
+func (c *cache) wake() {
+	select {
+	case c.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (c *cache) run(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(resizeInterval)
+	defer ticker.Stop()
+
+	for {
+		c.resize(ctx)
+
+		select {
+		case <-c.closed:
+			return
+		case <-c.wakeup:
+		case <-ticker.C:
+		}
+	}
+}
+
+// resize expires the idle VMs, removes the unused pools and starts the VMs
+// needed to bring the pools to their target size within the memory budget.
+func (c *cache) resize(ctx context.Context) {
+	var expired []*vc.VM
+
+	defer func() {
+		for _, vm := range expired {
+			stopVM(ctx, vm)
+		}
+	}()
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.isClosed {
+		return
+	}
+
+	now := time.Now()
+	timeout := c.policy.IdleTimeout
+
+	var used uint64
+	for key, p := range c.pools {
+		if timeout > 0 {
+			expired = append(expired, p.expire(now, timeout)...)
+
+			if key != c.defaultKey && p.unused() && now.Sub(p.lastUsed) > timeout {
+				cacheLog.WithField("pool", p.id).Info("remove idle VM pool")
+				delete(c.pools, key)
+				continue
+			}
+		}
+
+		used += uint64(p.size()) * p.memory()
+	}
+
+	for _, p := range c.sortedPools() {
+		for p.size() < p.target {
+			if c.policy.MemoryBudget > 0 && used+p.memory() > c.policy.MemoryBudget {
+				break
+			}
+			used += p.memory()
+
+			p.starting++
+			c.wg.Add(1)
+			go c.startVM(ctx, p)
+		}
+	}
+}
+
+// startVM adds a new VM to the ready VMs of p.
+func (c *cache) startVM(ctx context.Context, p *pool) {
+	defer c.wg.Done()
+
+	vm, err := p.base.GetBaseVM(ctx, p.config)
+
+	c.Lock()
+	p.starting--
+	closed := c.isClosed
+	if err == nil && !closed {
+		p.ready = append(p.ready, readyVM{vm: vm, since: time.Now()})
+	}
+	c.Unlock()
+
+	if err != nil {
+		cacheLog.WithError(err).WithField("pool", p.id).Error("failed to create cached VM")
+		return
+	}
+
+	if closed {
+		stopVM(ctx, vm)
+	}
+}
+
+func stopVM(ctx context.Context, vm *vc.VM) {
+	if err := vm.Stop(ctx); err != nil {
+		cacheLog.WithError(err).Warn("failed to stop cached VM")
+	}
+	vm.Disconnect(ctx)
+}
@@ -8,6 +8,7 @@ package cache
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -47,3 +48,208 @@ func TestTemplateFactory(t *testing.T) {
 	// CloseFactory
 	f.CloseFactory(ctx)
 }
+
+func TestCachePools(t *testing.T) {
+	assert := assert.New(t)
+
+	testDir := t.TempDir()
+	otherDir := t.TempDir()
+
+	vmConfig := vc.VMConfig{
+		HypervisorType: vc.MockHypervisor,
+		HypervisorConfig: vc.HypervisorConfig{
+			KernelPath: testDir,
+			ImagePath:  testDir,
+		},
+	}
+	otherConfig := vmConfig
+	otherConfig.HypervisorConfig.KernelPath = otherDir
+
+	ctx := vc.WithNewAgentFunc(context.Background(), vc.NewMockAgent)
+
+	f := NewWithPolicy(ctx, Policy{MinVMs: 1, MaxVMs: 2, MaxPools: 2}, direct.New(ctx, vmConfig))
+	defer f.CloseFactory(ctx)
+
+	pf, ok := f.(*cache)
+	assert.True(ok)
+	assert.True(pf.HasPools())
+
+	// a VM for another config is created on demand in a new pool
+	vm, err := f.GetBaseVM(ctx, otherConfig)
+	assert.NoError(err)
+	assert.NoError(vm.Stop(ctx))
+
+	status := pf.GetPoolStatus()
+	assert.Len(status, 2)
+	assert.Equal(defaultPoolID, status[0].Id)
+	assert.Equal(otherDir, status[1].Kernel)
+	assert.Equal(uint64(1), status[1].Misses)
+	assert.Equal(uint32(1), status[1].Target)
+
+	// the pool is then refilled
+	assert.Eventually(func() bool {
+		return pf.GetPoolStatus()[1].Ready == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	vm, err = f.GetBaseVM(ctx, otherConfig)
+	assert.NoError(err)
+	assert.NoError(vm.Stop(ctx))
+	assert.Equal(uint64(1), pf.GetPoolStatus()[1].Hits)
+
+	// no pool is created over the limit
+	thirdConfig := vmConfig
+	thirdConfig.HypervisorConfig.ImagePath = otherDir
+
+	vm, err = f.GetBaseVM(ctx, thirdConfig)
+	assert.NoError(err)
+	assert.NoError(vm.Stop(ctx))
+	assert.Len(pf.GetPoolStatus(), 2)
+}
+
+func TestCacheSinglePool(t *testing.T) {
+	assert := assert.New(t)
+
+	testDir := t.TempDir()
+
+	vmConfig := vc.VMConfig{
+		HypervisorType: vc.MockHypervisor,
+		HypervisorConfig: vc.HypervisorConfig{
+			KernelPath: testDir,
+			ImagePath:  testDir,
+		},
+	}
+
+	ctx := vc.WithNewAgentFunc(context.Background(), vc.NewMockAgent)
+
+	f := NewWithPolicy(ctx, Policy{MinVMs: 1}, direct.New(ctx, vmConfig))
+	defer f.CloseFactory(ctx)
+
+	pf, ok := f.(*cache)
+	assert.True(ok)
+	assert.False(pf.HasPools())
+	assert.Len(pf.GetPoolStatus(), 1)
+}
+
+func TestCachePoolSharedBySandboxes(t *testing.T) {
+	assert := assert.New(t)
+
+	testDir := t.TempDir()
+	otherDir := t.TempDir()
+
+	vmConfig := vc.VMConfig{
+		HypervisorType: vc.MockHypervisor,
+		HypervisorConfig: vc.HypervisorConfig{
+			KernelPath: testDir,
+			ImagePath:  testDir,
+		},
+	}
+
+	ctx := vc.WithNewAgentFunc(context.Background(), vc.NewMockAgent)
+
+	f := NewWithPolicy(ctx, Policy{MinVMs: 1, MaxVMs: 2, MaxPools: 2}, direct.New(ctx, vmConfig))
+	defer f.CloseFactory(ctx)
+
+	pf := f.(*cache)
+
+	sandboxConfig := func(name string) vc.VMConfig {
+		config := vmConfig
+		config.HypervisorConfig.KernelPath = otherDir
+		config.HypervisorConfig.SandboxName = name
+		config.HypervisorConfig.SandboxNamespace = "default"
+		return config
+	}
+
+	vm, err := f.GetBaseVM(ctx, sandboxConfig("first"))
+	assert.NoError(err)
+	assert.NoError(vm.Stop(ctx))
+
+	assert.Eventually(func() bool {
+		return pf.GetPoolStatus()[1].Ready == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// another sandbox with the same VM config gets the VM of the pool
+	vm, err = f.GetBaseVM(ctx, sandboxConfig("second"))
+	assert.NoError(err)
+	assert.NoError(vm.Stop(ctx))
+
+	status := pf.GetPoolStatus()
+	assert.Len(status, 2)
+	assert.Equal(uint64(1), status[1].Hits)
+	assert.Equal(uint64(1), status[1].Misses)
+}
+
+func TestCacheMemoryBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	testDir := t.TempDir()
+
+	vmConfig := vc.VMConfig{
+		HypervisorType: vc.MockHypervisor,
+		HypervisorConfig: vc.HypervisorConfig{
+			KernelPath: testDir,
+			ImagePath:  testDir,
+			MemorySize: 128,
+		},
+	}
+
+	ctx := vc.WithNewAgentFunc(context.Background(), vc.NewMockAgent)
+
+	f := NewWithPolicy(ctx, Policy{MinVMs: 2, MemoryBudget: 200}, direct.New(ctx, vmConfig))
+	defer f.CloseFactory(ctx)
+
+	c := f.(*cache)
+	c.resize(ctx)
+
+	c.Lock()
+	assert.Equal(uint(1), c.pools[c.defaultKey].size())
+	c.Unlock()
+}
+
+func TestPoolExpire(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	p := &pool{
+		ready: []readyVM{
+			{vm: &vc.VM{}, since: now.Add(-3 * time.Minute)},
+			{vm: &vc.VM{}, since: now.Add(-2 * time.Minute)},
+			{vm: &vc.VM{}, since: now},
+		},
+		min:    1,
+		target: 3,
+	}
+
+	expired := p.expire(now, time.Minute)
+	assert.Len(expired, 2)
+	assert.Len(p.ready, 1)
+	assert.Equal(uint(1), p.target)
+	assert.Equal(uint64(2), p.expired)
+
+	// the pool never goes below its minimum size
+	p.ready[0].since = now.Add(-time.Hour)
+	assert.Empty(p.expire(now, time.Minute))
+	assert.False(p.unused())
+}
+
+func TestPoolKey(t *testing.T) {
+	assert := assert.New(t)
+
+	config := vc.VMConfig{
+		HypervisorType: vc.MockHypervisor,
+		HypervisorConfig: vc.HypervisorConfig{
+			KernelPath: "/kernel",
+			ImagePath:  "/image",
+			NumVCPUs:   1,
+			MemorySize: 128,
+		},
+	}
+
+	resized := config
+	resized.HypervisorConfig.NumVCPUs = 4
+	resized.HypervisorConfig.MemorySize = 2048
+	assert.Equal(poolKey(config), poolKey(resized))
+
+	other := config
+	other.HypervisorConfig.KernelPath = "/other-kernel"
+	assert.NotEqual(poolKey(config), poolKey(other))
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	pb "github.com/kata-containers/kata-containers/src/runtime/protocols/cache"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory/base"
+)
+
+const (
+	defaultPoolID = "default"
+	poolIDLength  = 12
+)
+
+type readyVM struct {
+	since time.Time
+	vm    *vc.VM
+}
+
+// pool holds the cached VMs of a VM config.
+type pool struct {
+	lastUsed time.Time
+
+	base   base.FactoryBase
+	id     string
+	config vc.VMConfig
+
+	// ready VMs, the oldest first
+	ready []readyVM
+
+	hits    uint64
+	misses  uint64
+	expired uint64
+
+	min      uint
+	target   uint
+	starting uint
+}
+
+// poolKey returns the key of the pool caching the VMs for config. Only the
+// settings changing the VMs booted for the pool are part of the key.
+func poolKey(config vc.VMConfig) string {
+	base.ResetHypervisorConfig(&config)
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		// cannot happen, a VMConfig is always marshalled to be sent
+		// to the VM cache clients.
+		panic(err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// size returns the number of VMs of the pool, the ones being started
+// included.
+func (p *pool) size() uint {
+	return uint(len(p.ready)) + p.starting
+}
+
+// memory returns the memory, in MiB, used by a VM of the pool.
+func (p *pool) memory() uint64 {
+	return uint64(p.config.HypervisorConfig.MemorySize)
+}
+
+// unused returns true if the pool holds no VM and does not need any.
+func (p *pool) unused() bool {
+	return p.target == 0 && p.size() == 0
+}
+
+// expire removes the VMs that have been ready for longer than timeout, as
+// long as the pool is over its minimum size, and shrinks the pool target
+// accordingly. It returns the removed VMs.
+func (p *pool) expire(now time.Time, timeout time.Duration) []*vc.VM {
+	var expired []*vc.VM
+
+	for len(p.ready) > 0 && p.size() > p.min && now.Sub(p.ready[0].since) > timeout {
+		expired = append(expired, p.ready[0].vm)
+		p.ready = p.ready[1:]
+		p.expired++
+
+		if p.target > p.min {
+			p.target--
+		}
+	}
+
+	return expired
+}
+
+func (p *pool) status() *pb.GrpcPoolStatus {
+	hConfig := p.config.HypervisorConfig
+
+	image := hConfig.ImagePath
+	if image == "" {
+		image = hConfig.InitrdPath
+	}
+
+	return &pb.GrpcPoolStatus{
+		Id:         p.id,
+		Hypervisor: string(p.config.HypervisorType),
+		Kernel:     hConfig.KernelPath,
+		Image:      image,
+		Ready:      uint32(len(p.ready)),
+		Target:     uint32(p.target),
+		Hits:       p.hits,
+		Misses:     p.misses,
+		Expired:    p.expired,
+	}
+}
@@ -8,6 +8,7 @@ package factory
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
 	pb "github.com/kata-containers/kata-containers/src/runtime/protocols/cache"
@@ -35,10 +36,31 @@ type Config struct {
 	TemplatePath    string
 	VMCacheEndpoint string
 
+	// TemplateStorePath is the path of the on disk store of templates.
+	// Templates are not persisted if empty.
+	TemplateStorePath string
+
 	VMConfig vc.VMConfig
 
+	// CacheIdleTimeout is the time after which an unused cached VM is
+	// destroyed. 0 means never.
+	CacheIdleTimeout time.Duration
+
+	// CacheMemoryBudget is the memory, in MiB, all the cached VMs can
+	// use. 0 means no limit.
+	CacheMemoryBudget uint64
+
+	// Cache is the number of VMs the cache keeps ready for VMConfig.
 	Cache uint
 
+	// CacheMax is the number of VMs a pool of the cache can grow to on
+	// demand. It defaults to Cache.
+	CacheMax uint
+
+	// CacheMaxPools is the number of VM configs the cache keeps VMs for.
+	// 0 only caches VMs for VMConfig.
+	CacheMaxPools uint
+
 	Template bool
 	VMCache  bool
 }
@@ -71,12 +93,12 @@ func NewFactory(ctx context.Context, config Config, fetchOnly bool) (vc.Factory,
 	} else {
 		if config.Template {
 			if fetchOnly {
-				b, err = template.Fetch(config.VMConfig, config.TemplatePath)
+				b, err = template.Fetch(config.VMConfig, config.TemplatePath, config.TemplateStorePath)
 				if err != nil {
 					return nil, err
 				}
 			} else {
-				b, err = template.New(ctx, config.VMConfig, config.TemplatePath)
+				b, err = template.New(ctx, config.VMConfig, config.TemplatePath, config.TemplateStorePath)
 				if err != nil {
 					return nil, err
 				}
@@ -86,7 +108,13 @@ func NewFactory(ctx context.Context, config Config, fetchOnly bool) (vc.Factory,
 		}
 
 		if config.Cache > 0 {
-			b = cache.New(ctx, config.Cache, b)
+			b = cache.NewWithPolicy(ctx, cache.Policy{
+				MinVMs:       config.Cache,
+				MaxVMs:       config.CacheMax,
+				MaxPools:     config.CacheMaxPools,
+				MemoryBudget: config.CacheMemoryBudget,
+				IdleTimeout:  config.CacheIdleTimeout,
+			}, b)
 		}
 	}
 
@@ -106,18 +134,6 @@ func (f *factory) log() *logrus.Entry {
 	return factoryLogger.WithField("subsystem", "factory")
 }
 
-func resetHypervisorConfig(config *vc.VMConfig) {
-	config.HypervisorConfig.NumVCPUs = 0
-	config.HypervisorConfig.MemorySize = 0
-	config.HypervisorConfig.BootToBeTemplate = false
-	config.HypervisorConfig.BootFromTemplate = false
-	config.HypervisorConfig.MemoryPath = ""
-	config.HypervisorConfig.DevicesStatePath = ""
-	config.HypervisorConfig.SharedPath = ""
-	config.HypervisorConfig.VMStorePath = ""
-	config.HypervisorConfig.RunStorePath = ""
-}
-
 // It's important that baseConfig and newConfig are passed by value!
 func checkVMConfig(baseConfig, newConfig vc.VMConfig) error {
 	if baseConfig.HypervisorType != newConfig.HypervisorType {
@@ -125,8 +141,8 @@ func checkVMConfig(baseConfig, newConfig vc.VMConfig) error {
 	}
 
 	// check hypervisor config details
-	resetHypervisorConfig(&baseConfig)
-	resetHypervisorConfig(&newConfig)
+	base.ResetHypervisorConfig(&baseConfig)
+	base.ResetHypervisorConfig(&newConfig)
 
 	if !utils.DeepCompare(baseConfig, newConfig) {
 		return fmt.Errorf("hypervisor config does not match, base: %+v. new: %+v", baseConfig, newConfig)
@@ -141,6 +157,13 @@ func (f *factory) checkConfig(config vc.VMConfig) error {
 	return checkVMConfig(baseConfig, config)
 }
 
+// HasPools returns true if the base factory can return VMs for another
+// config than its own.
+func (f *factory) HasPools() bool {
+	pfb, ok := f.base.(base.PoolFactoryBase)
+	return ok && pfb.HasPools()
+}
+
 // GetVM returns a working blank VM created by the factory.
 func (f *factory) GetVM(ctx context.Context, config vc.VMConfig) (*vc.VM, error) {
 	span, ctx := katatrace.Trace(ctx, f.log(), "GetVM", factoryTracingTags)
@@ -153,7 +176,7 @@ func (f *factory) GetVM(ctx context.Context, config vc.VMConfig) (*vc.VM, error)
 	}
 
 	err := f.checkConfig(config)
-	if err != nil {
+	if err != nil && !f.HasPools() {
 		f.log().WithError(err).Info("fallback to direct factory vm")
 		return direct.New(ctx, config).GetBaseVM(ctx, config)
 	}
@@ -228,6 +251,15 @@ func (f *factory) GetVMStatus() []*pb.GrpcVMStatus {
 	return f.base.GetVMStatus()
 }
 
+// GetPoolStatus returns the status of the VM pools of the base factory,
+// if it has any.
+func (f *factory) GetPoolStatus() []*pb.GrpcPoolStatus {
+	if !f.HasPools() {
+		return nil
+	}
+	return f.base.(base.PoolFactoryBase).GetPoolStatus()
+}
+
 // GetBaseVM returns a paused VM created by the base factory.
 func (f *factory) GetBaseVM(ctx context.Context, config vc.VMConfig) (*vc.VM, error) {
 	return f.base.GetBaseVM(ctx, config)
@@ -148,6 +148,11 @@ func TestCheckVMConfig(t *testing.T) {
 	}
 	err = checkVMConfig(config1, config2)
 	assert.Nil(err)
+
+	// the sandbox of the VM does not matter
+	config2.HypervisorConfig.SandboxName = "sandbox"
+	err = checkVMConfig(config1, config2)
+	assert.Nil(err)
 }
 
 func TestFactoryGetVM(t *testing.T) {
@@ -232,6 +237,19 @@ func TestFactoryGetVM(t *testing.T) {
 	err = vm.Stop(ctx)
 	assert.Nil(err)
 
+	// a single pool cache factory falls back to the direct factory for
+	// another VM config
+	assert.False(f.HasPools())
+	assert.Nil(f.GetPoolStatus())
+
+	otherConfig := vmConfig
+	otherConfig.HypervisorConfig.KernelPath = t.TempDir()
+	vm, err = f.GetVM(ctx, otherConfig)
+	assert.Nil(err)
+
+	err = vm.Stop(ctx)
+	assert.Nil(err)
+
 	f.CloseFactory(ctx)
 
 	// cache factory over template factory
@@ -268,6 +286,22 @@ func TestFactoryGetVM(t *testing.T) {
 	assert.Nil(err)
 
 	f.CloseFactory(ctx)
+
+	// cache factory with a pool per VM config
+	f, err = NewFactory(ctx, Config{Cache: 1, CacheMaxPools: 2, VMConfig: vmConfig}, false)
+	assert.Nil(err)
+	assert.True(f.HasPools())
+
+	otherConfig.HypervisorConfig.KernelPath = t.TempDir()
+	vm, err = f.GetVM(ctx, otherConfig)
+	assert.Nil(err)
+
+	err = vm.Stop(ctx)
+	assert.Nil(err)
+
+	assert.Len(f.GetPoolStatus(), 2)
+
+	f.CloseFactory(ctx)
 }
 
 func TestDeepCompare(t *testing.T) {
@@ -22,6 +22,8 @@ import (
 type grpccache struct {
 	conn   *grpc.ClientConn
 	config *vc.VMConfig
+	// the cache server keeps pools of VMs for several VM configs
+	pools bool
 }
 
 // New returns a new direct vm factory.
@@ -41,7 +43,7 @@ func New(ctx context.Context, endpoint string) (base.FactoryBase, error) {
 		return nil, errors.Wrapf(err, "failed to convert JSON to VMConfig")
 	}
 
-	return &grpccache{conn: conn, config: config}, nil
+	return &grpccache{conn: conn, config: config, pools: jConfig.Pools}, nil
 }
 
 // Config returns the direct factory's configuration.
@@ -52,17 +54,44 @@ func (g *grpccache) Config() vc.VMConfig {
 // GetBaseVM create a new VM directly.
 func (g *grpccache) GetBaseVM(ctx context.Context, config vc.VMConfig) (*vc.VM, error) {
 	defer g.conn.Close()
-	gVM, err := pb.NewCacheServiceClient(g.conn).GetBaseVM(ctx, &types.Empty{})
+
+	// Servers without pools only return VMs for their own config, and
+	// ignore the request content.
+	vmConfig := *g.config
+	req := &pb.GrpcVMConfig{}
+	if g.pools {
+		vmConfig = config
+		vmConfig.HypervisorConfig.NumVCPUs = g.config.HypervisorConfig.NumVCPUs
+		vmConfig.HypervisorConfig.MemorySize = g.config.HypervisorConfig.MemorySize
+
+		var err error
+		req, err = vmConfig.ToGrpc()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert VMConfig to JSON")
+		}
+	}
+
+	gVM, err := pb.NewCacheServiceClient(g.conn).GetBaseVM(ctx, req)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to GetBaseVM")
 	}
-	return vc.NewVMFromGrpc(ctx, gVM, *g.config)
+	return vc.NewVMFromGrpc(ctx, gVM, vmConfig)
 }
 
 // CloseFactory closes the direct vm factory.
 func (g *grpccache) CloseFactory(ctx context.Context) {
 }
 
+// HasPools returns true if the cache server keeps VMs for several configs.
+func (g *grpccache) HasPools() bool {
+	return g.pools
+}
+
+// GetPoolStatus is not supported
+func (g *grpccache) GetPoolStatus() []*pb.GrpcPoolStatus {
+	panic("ERROR: package grpccache does not support GetPoolStatus")
+}
+
 // GetVMStatus is not supported
 func (g *grpccache) GetVMStatus() []*pb.GrpcVMStatus {
 	panic("ERROR: package grpccache does not support GetVMStatus")
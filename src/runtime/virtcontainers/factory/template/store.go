@@ -0,0 +1,540 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+)
+
+// templateFormatVersion is the version of the on disk format of the
+// templates. Templates of another version are stale.
+const templateFormatVersion = 1
+
+const (
+	metadataFile    = "metadata.json"
+	memoryFile      = "memory"
+	deviceStateFile = "state"
+)
+
+// templateFiles are the files of a template VM.
+var templateFiles = []string{memoryFile, deviceStateFile}
+
+// Metadata describes a template VM saved in a template store.
+type Metadata struct {
+	// Created is the creation time of the template.
+	Created time.Time `json:"created"`
+
+	// Checksums are the sha256 sums of the template files.
+	Checksums map[string]string `json:"checksums"`
+
+	// Assets are the sha256 sums of the hypervisor, the kernel and the
+	// guest image the template was created for, the assets are not hashed
+	// again while they look unchanged.
+	Assets []AssetDigest `json:"assets,omitempty"`
+
+	// ID identifies the template, it is the key of the VM config the
+	// template was created for, see ConfigKey.
+	ID string `json:"id"`
+
+	Hypervisor vc.HypervisorType `json:"hypervisor"`
+	Kernel     string            `json:"kernel"`
+	Initrd     string            `json:"initrd,omitempty"`
+	Image      string            `json:"image,omitempty"`
+
+	// Version is the format version of the template.
+	Version int `json:"version"`
+
+	// MemorySize is the memory of the template VM, in MiB.
+	MemorySize uint32 `json:"memory_size"`
+}
+
+// AssetDigest is the sha256 sum of an asset, and the version of the asset
+// file it was computed for.
+type AssetDigest struct {
+	Path    string `json:"path"`
+	SHA256  string `json:"sha256"`
+	ModTime int64  `json:"mod_time"`
+	Dev     uint64 `json:"dev"`
+	Inode   uint64 `json:"inode"`
+	Size    int64  `json:"size"`
+}
+
+// sameAssets returns true if both templates were created for the same
+// hypervisor, kernel and guest image paths.
+func (m *Metadata) sameAssets(other *Metadata) bool {
+	return m.Hypervisor == other.Hypervisor && m.Kernel == other.Kernel &&
+		m.Initrd == other.Initrd && m.Image == other.Image
+}
+
+// ConfigKey returns the key identifying the template of a VM config. It
+// changes whenever the content of the hypervisor, the kernel or the guest
+// image, or the VM config itself changes.
+func ConfigKey(config vc.VMConfig) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "version %d\n", templateFormatVersion)
+
+	for _, asset := range configAssets(config) {
+		fmt.Fprintf(h, "asset %s\n", asset)
+		if asset == "" {
+			continue
+		}
+		digest, err := assetDigest(asset)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "sha256 %s\n", digest)
+	}
+
+	// the paths of the VM instances are not part of the key
+	config.HypervisorConfig.BootToBeTemplate = false
+	config.HypervisorConfig.BootFromTemplate = false
+	config.HypervisorConfig.MemoryPath = ""
+	config.HypervisorConfig.DevicesStatePath = ""
+	config.HypervisorConfig.SharedPath = ""
+	config.HypervisorConfig.VMStorePath = ""
+	config.HypervisorConfig.RunStorePath = ""
+
+	data, err := json.Marshal(&config)
+	if err != nil {
+		return "", err
+	}
+	h.Write(data)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// configAssets returns the paths of the assets of config which are part of
+// its key.
+func configAssets(config vc.VMConfig) []string {
+	hc := config.HypervisorConfig
+	return []string{hc.HypervisorPath, hc.KernelPath, hc.InitrdPath, hc.ImagePath}
+}
+
+// assetFile identifies the version of an asset file hashed in
+// assetDigests.
+type assetFile struct {
+	modTime int64
+	dev     uint64
+	inode   uint64
+	size    int64
+}
+
+type assetDigestEntry struct {
+	digest string
+	file   assetFile
+}
+
+var (
+	// assetDigests caches the sha256 sums of the assets, the hypervisor,
+	// the kernel and the guest image are not hashed again for every VM.
+	assetDigests      = make(map[string]assetDigestEntry)
+	assetDigestsMutex sync.Mutex
+)
+
+func statAsset(path string) (assetFile, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return assetFile{}, err
+	}
+
+	file := assetFile{modTime: fi.ModTime().UnixNano(), size: fi.Size()}
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		file.dev = uint64(st.Dev)
+		file.inode = st.Ino
+	}
+
+	return file, nil
+}
+
+// assetDigest returns the sha256 sum of the asset in path. The sum is
+// cached until the modification time, the size or the inode of the asset
+// change.
+func assetDigest(path string) (string, error) {
+	file, err := statAsset(path)
+	if err != nil {
+		return "", err
+	}
+
+	assetDigestsMutex.Lock()
+	entry, ok := assetDigests[path]
+	assetDigestsMutex.Unlock()
+	if ok && entry.file == file {
+		return entry.digest, nil
+	}
+
+	h := sha256.New()
+	if err := hashFile(h, path); err != nil {
+		return "", err
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+
+	// only cache the sum if the asset did not change while it was hashed
+	if after, err := statAsset(path); err == nil && after == file {
+		assetDigestsMutex.Lock()
+		assetDigests[path] = assetDigestEntry{digest: digest, file: file}
+		assetDigestsMutex.Unlock()
+	}
+
+	return digest, nil
+}
+
+// loadAssetDigests caches the sums of the assets recorded in the template
+// md. They are only used while the assets look unchanged.
+func loadAssetDigests(md *Metadata) {
+	assetDigestsMutex.Lock()
+	defer assetDigestsMutex.Unlock()
+
+	for _, a := range md.Assets {
+		if _, ok := assetDigests[a.Path]; ok {
+			continue
+		}
+		assetDigests[a.Path] = assetDigestEntry{
+			digest: a.SHA256,
+			file:   assetFile{modTime: a.ModTime, dev: a.Dev, inode: a.Inode, size: a.Size},
+		}
+	}
+}
+
+// cachedAssetDigests returns the cached sums of the assets of config.
+func cachedAssetDigests(config vc.VMConfig) []AssetDigest {
+	assetDigestsMutex.Lock()
+	defer assetDigestsMutex.Unlock()
+
+	var assets []AssetDigest
+	for _, path := range configAssets(config) {
+		entry, ok := assetDigests[path]
+		if path == "" || !ok {
+			continue
+		}
+		assets = append(assets, AssetDigest{
+			Path:    path,
+			SHA256:  entry.digest,
+			ModTime: entry.file.modTime,
+			Dev:     entry.file.dev,
+			Inode:   entry.file.inode,
+			Size:    entry.file.size,
+		})
+	}
+
+	return assets
+}
+
+func newMetadata(config vc.VMConfig) (*Metadata, error) {
+	id, err := ConfigKey(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Metadata{
+		Assets:     cachedAssetDigests(config),
+		Version:    templateFormatVersion,
+		ID:         id,
+		Created:    time.Now().UTC(),
+		Hypervisor: config.HypervisorType,
+		Kernel:     config.HypervisorConfig.KernelPath,
+		Initrd:     config.HypervisorConfig.InitrdPath,
+		Image:      config.HypervisorConfig.ImagePath,
+		MemorySize: config.HypervisorConfig.MemorySize,
+	}, nil
+}
+
+func hashFile(h hash.Hash, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(h, f)
+	return err
+}
+
+func readMetadata(dir string) (*Metadata, error) {
+	data, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var md Metadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, err
+	}
+
+	return &md, nil
+}
+
+func writeMetadata(dir string, md *Metadata) error {
+	data, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, metadataFile), data, 0600)
+}
+
+// copyTemplateFiles copies the template files from src to dst, and returns
+// their checksums.
+func copyTemplateFiles(src, dst string) (map[string]string, error) {
+	checksums := make(map[string]string)
+	for _, name := range templateFiles {
+		sum, err := copyFile(filepath.Join(src, name), filepath.Join(dst, name))
+		if err != nil {
+			return nil, err
+		}
+		checksums[name] = sum
+	}
+
+	return checksums, nil
+}
+
+func copyFile(src, dst string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, h), in); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), out.Close()
+}
+
+func verifyChecksums(md *Metadata, checksums map[string]string) error {
+	for _, name := range templateFiles {
+		if md.Checksums[name] == "" {
+			return fmt.Errorf("template %s has no checksum for %s", md.ID, name)
+		}
+		if md.Checksums[name] != checksums[name] {
+			return fmt.Errorf("template %s is corrupted: checksum mismatch for %s", md.ID, name)
+		}
+	}
+
+	return nil
+}
+
+// Store is an on disk store of template VMs, keyed by the key of the VM
+// config they were created for. Unlike the template used by the factory,
+// which lives in a tmpfs, the templates of a store survive reboots and
+// can be shipped pre-built to the nodes.
+type Store struct {
+	path string
+}
+
+// NewStore returns the template store at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+func (s *Store) dir(id string) string {
+	return filepath.Join(s.path, id)
+}
+
+// checkID checks that id is a template key, and not a path.
+func checkID(id string) error {
+	if _, err := hex.DecodeString(id); err != nil || len(id) != 2*sha256.Size {
+		return fmt.Errorf("invalid template id %q", id)
+	}
+	return nil
+}
+
+// List returns the metadata of the templates of the store, ordered by
+// creation time.
+func (s *Store) List() ([]*Metadata, error) {
+	entries, err := os.ReadDir(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var templates []*Metadata
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		md, err := s.Get(entry.Name())
+		if err != nil {
+			// partially written or foreign directory
+			templateLog.WithError(err).WithField("template", entry.Name()).Debug("skipping invalid template")
+			continue
+		}
+		templates = append(templates, md)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Created.Before(templates[j].Created)
+	})
+
+	return templates, nil
+}
+
+// Get returns the metadata of the template id.
+func (s *Store) Get(id string) (*Metadata, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	md, err := readMetadata(s.dir(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if md.ID != id {
+		return nil, fmt.Errorf("template %s has an invalid id %s", id, md.ID)
+	}
+
+	return md, nil
+}
+
+// Verify checks the checksums of the files of the template id.
+func (s *Store) Verify(id string) error {
+	md, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+
+	checksums := make(map[string]string)
+	for _, name := range templateFiles {
+		h := sha256.New()
+		if err := hashFile(h, filepath.Join(s.dir(id), name)); err != nil {
+			return err
+		}
+		checksums[name] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	return verifyChecksums(md, checksums)
+}
+
+// Delete removes the template id from the store.
+func (s *Store) Delete(id string) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(s.dir(id)); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(s.dir(id))
+}
+
+// save saves the template in statePath to the store, and removes the
+// templates it supersedes.
+func (s *Store) save(statePath string, md *Metadata) error {
+	if _, err := s.Get(md.ID); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.path, 0700); err != nil {
+		return err
+	}
+
+	// write the template to a temporary directory first, a partially
+	// written template must never be used
+	tmp, err := os.MkdirTemp(s.path, ".tmp-"+md.ID)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	saved := *md
+	if saved.Checksums, err = copyTemplateFiles(statePath, tmp); err != nil {
+		return err
+	}
+
+	if err := writeMetadata(tmp, &saved); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, s.dir(md.ID)); err != nil {
+		return err
+	}
+
+	s.prune(&saved)
+
+	return nil
+}
+
+// prune removes the templates superseded by the template md: the templates
+// of another format version, and those created for the same assets but for
+// a different content of them or a different config.
+func (s *Store) prune(md *Metadata) {
+	templates, err := s.List()
+	if err != nil {
+		templateLog.WithError(err).Warn("failed to list templates")
+		return
+	}
+
+	for _, t := range templates {
+		if t.ID == md.ID || (t.Version == md.Version && !t.sameAssets(md)) {
+			continue
+		}
+
+		templateLog.WithField("template", t.ID).Info("removing stale template")
+		if err := s.Delete(t.ID); err != nil {
+			templateLog.WithError(err).WithField("template", t.ID).Warn("failed to remove stale template")
+		}
+	}
+}
+
+// loadAssetDigests caches the sums of the assets recorded in the templates
+// of the store.
+func (s *Store) loadAssetDigests() {
+	templates, err := s.List()
+	if err != nil {
+		return
+	}
+
+	for _, md := range templates {
+		loadAssetDigests(md)
+	}
+}
+
+// install copies the template id to statePath, verifying its checksums.
+func (s *Store) install(id, statePath string) (*Metadata, error) {
+	md, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if md.Version != templateFormatVersion {
+		return nil, fmt.Errorf("template %s has unsupported version %d", id, md.Version)
+	}
+
+	checksums, err := copyTemplateFiles(s.dir(id), statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyChecksums(md, checksums); err != nil {
+		return nil, err
+	}
+
+	return md, nil
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+)
+
+func newTestTemplateConfig(t *testing.T) vc.VMConfig {
+	dir := t.TempDir()
+
+	kernel := filepath.Join(dir, "vmlinux")
+	initrd := filepath.Join(dir, "initrd")
+	assert.NoError(t, os.WriteFile(kernel, []byte("kernel"), 0600))
+	assert.NoError(t, os.WriteFile(initrd, []byte("initrd"), 0600))
+
+	return vc.VMConfig{
+		HypervisorType: vc.MockHypervisor,
+		HypervisorConfig: vc.HypervisorConfig{
+			KernelPath: kernel,
+			InitrdPath: initrd,
+			MemorySize: 2048,
+		},
+	}
+}
+
+// newTestTemplateVM writes fake template VM files for config to statePath.
+func newTestTemplateVM(t *testing.T, statePath string) {
+	assert.NoError(t, os.MkdirAll(statePath, 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(statePath, memoryFile), []byte("memory"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(statePath, deviceStateFile), []byte("state"), 0600))
+}
+
+func TestConfigKey(t *testing.T) {
+	assert := assert.New(t)
+
+	config := newTestTemplateConfig(t)
+
+	key, err := ConfigKey(config)
+	assert.NoError(err)
+	assert.NoError(checkID(key))
+
+	// the paths of the VM instances do not change the key
+	instance := config
+	instance.HypervisorConfig.MemoryPath = "/run/vc/vm/template/memory"
+	instance.HypervisorConfig.VMStorePath = "/run/vc/vm"
+	other, err := ConfigKey(instance)
+	assert.NoError(err)
+	assert.Equal(key, other)
+
+	// the config does
+	changed := config
+	changed.HypervisorConfig.MemorySize = 4096
+	other, err = ConfigKey(changed)
+	assert.NoError(err)
+	assert.NotEqual(key, other)
+
+	// and so does the content of the assets
+	assert.NoError(os.WriteFile(config.HypervisorConfig.KernelPath, []byte("new kernel"), 0600))
+	other, err = ConfigKey(config)
+	assert.NoError(err)
+	assert.NotEqual(key, other)
+
+	assert.NoError(os.Remove(config.HypervisorConfig.InitrdPath))
+	_, err = ConfigKey(config)
+	assert.Error(err)
+}
+
+func TestConfigKeyCachedDigest(t *testing.T) {
+	assert := assert.New(t)
+
+	config := newTestTemplateConfig(t)
+	kernel := config.HypervisorConfig.KernelPath
+
+	key, err := ConfigKey(config)
+	assert.NoError(err)
+
+	fi, err := os.Stat(kernel)
+	assert.NoError(err)
+
+	// the asset is not hashed again while it looks unchanged
+	assert.NoError(os.WriteFile(kernel, []byte("KERNEL"), 0600))
+	assert.NoError(os.Chtimes(kernel, fi.ModTime(), fi.ModTime()))
+	other, err := ConfigKey(config)
+	assert.NoError(err)
+	assert.Equal(key, other)
+
+	// but it is once it is modified
+	mtime := fi.ModTime().Add(time.Second)
+	assert.NoError(os.Chtimes(kernel, mtime, mtime))
+	other, err = ConfigKey(config)
+	assert.NoError(err)
+	assert.NotEqual(key, other)
+
+	// or replaced
+	replaced := kernel + ".new"
+	assert.NoError(os.WriteFile(replaced, []byte("kernel"), 0600))
+	assert.NoError(os.Chtimes(replaced, fi.ModTime(), fi.ModTime()))
+	assert.NoError(os.Rename(replaced, kernel))
+	other, err = ConfigKey(config)
+	assert.NoError(err)
+	assert.Equal(key, other)
+}
+
+func TestConfigKeyStoredDigest(t *testing.T) {
+	assert := assert.New(t)
+
+	config := newTestTemplateConfig(t)
+	kernel := config.HypervisorConfig.KernelPath
+
+	md, err := newMetadata(config)
+	assert.NoError(err)
+	assert.Len(md.Assets, 2)
+	assert.Equal(kernel, md.Assets[0].Path)
+
+	fi, err := os.Stat(kernel)
+	assert.NoError(err)
+	assert.NoError(os.WriteFile(kernel, []byte("KERNEL"), 0600))
+	assert.NoError(os.Chtimes(kernel, fi.ModTime(), fi.ModTime()))
+
+	// a new process hashes the assets
+	assetDigests = make(map[string]assetDigestEntry)
+	key, err := ConfigKey(config)
+	assert.NoError(err)
+	assert.NotEqual(md.ID, key)
+
+	// unless their sums are recorded in the template
+	assetDigests = make(map[string]assetDigestEntry)
+	loadAssetDigests(md)
+	key, err = ConfigKey(config)
+	assert.NoError(err)
+	assert.Equal(md.ID, key)
+}
+
+func TestStore(t *testing.T) {
+	assert := assert.New(t)
+
+	config := newTestTemplateConfig(t)
+	statePath := filepath.Join(t.TempDir(), "template")
+	newTestTemplateVM(t, statePath)
+
+	store := NewStore(filepath.Join(t.TempDir(), "templates"))
+
+	templates, err := store.List()
+	assert.NoError(err)
+	assert.Empty(templates)
+
+	tt := &template{statePath: statePath, storePath: store.path, config: config}
+	assert.False(tt.current())
+
+	md, err := tt.save()
+	assert.NoError(err)
+	assert.Equal(templateFormatVersion, md.Version)
+	assert.Equal(config.HypervisorConfig.KernelPath, md.Kernel)
+	assert.Equal(config.HypervisorConfig.InitrdPath, md.Initrd)
+	assert.True(tt.current())
+
+	// saving again is a no-op
+	again, err := Save(config, statePath, store.path)
+	assert.NoError(err)
+	assert.Equal(md.ID, again.ID)
+
+	templates, err = store.List()
+	assert.NoError(err)
+	assert.Len(templates, 1)
+	assert.Equal(md.ID, templates[0].ID)
+	assert.Len(templates[0].Checksums, len(templateFiles))
+
+	assert.NoError(store.Verify(md.ID))
+
+	// install verifies the checksums
+	installPath := t.TempDir()
+	installed, err := store.install(md.ID, installPath)
+	assert.NoError(err)
+	assert.Equal(md.ID, installed.ID)
+	data, err := os.ReadFile(filepath.Join(installPath, memoryFile))
+	assert.NoError(err)
+	assert.Equal("memory", string(data))
+
+	assert.NoError(os.WriteFile(filepath.Join(store.dir(md.ID), memoryFile), []byte("corrupted"), 0600))
+	assert.Error(store.Verify(md.ID))
+	_, err = store.install(md.ID, installPath)
+	assert.Error(err)
+
+	// ids are not paths
+	assert.Error(store.Delete("../templates"))
+	_, err = store.Get("foo")
+	assert.Error(err)
+
+	assert.NoError(store.Delete(md.ID))
+	assert.Error(store.Delete(md.ID))
+	templates, err = store.List()
+	assert.NoError(err)
+	assert.Empty(templates)
+}
+
+func TestStorePrune(t *testing.T) {
+	assert := assert.New(t)
+
+	config := newTestTemplateConfig(t)
+	store := NewStore(filepath.Join(t.TempDir(), "templates"))
+
+	save := func(config vc.VMConfig) *Metadata {
+		statePath := filepath.Join(t.TempDir(), "template")
+		newTestTemplateVM(t, statePath)
+		md, err := Save(config, statePath, store.path)
+		assert.NoError(err)
+		return md
+	}
+
+	old := save(config)
+
+	// a template for other assets is kept
+	other := newTestTemplateConfig(t)
+	kept := save(other)
+
+	// a template for new content of the same assets supersedes the old one
+	assert.NoError(os.WriteFile(config.HypervisorConfig.KernelPath, []byte("new kernel"), 0600))
+	updated := save(config)
+
+	templates, err := store.List()
+	assert.NoError(err)
+
+	var ids []string
+	for _, t := range templates {
+		ids = append(ids, t.ID)
+	}
+	assert.ElementsMatch([]string{kept.ID, updated.ID}, ids)
+	assert.NotContains(ids, old.ID)
+}
+
+func TestSaveStaleTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	config := newTestTemplateConfig(t)
+	statePath := filepath.Join(t.TempDir(), "template")
+	newTestTemplateVM(t, statePath)
+	storePath := filepath.Join(t.TempDir(), "templates")
+
+	_, err := Save(config, statePath, storePath)
+	assert.NoError(err)
+
+	// the template in statePath was created for the old config
+	config.HypervisorConfig.MemorySize = 4096
+	_, err = Save(config, statePath, storePath)
+	assert.Error(err)
+
+	tt := &template{statePath: statePath, storePath: storePath, config: config}
+	assert.False(tt.current())
+
+	// without a template store, templates are not keyed
+	tt.storePath = ""
+	assert.True(tt.current())
+
+	_, err = Save(config, t.TempDir(), storePath)
+	assert.Error(err)
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"syscall"
 	"time"
 
@@ -21,32 +22,52 @@ import (
 
 type template struct {
 	statePath string
+	storePath string
 	config    vc.VMConfig
 }
 
 var templateWaitForAgent = 2 * time.Second
 var templateLog = logrus.WithField("source", "virtcontainers/factory/template")
 
-// Fetch finds and returns a pre-built template factory.
-// TODO: save template metadata and fetch from storage.
-func Fetch(config vc.VMConfig, templatePath string) (base.FactoryBase, error) {
-	t := &template{templatePath, config}
+// Fetch finds and returns a pre-built template factory. With a template
+// store, the template is installed from the store when it is missing or
+// stale in templatePath.
+func Fetch(config vc.VMConfig, templatePath, storePath string) (base.FactoryBase, error) {
+	t := &template{templatePath, storePath, config}
 
 	err := t.checkTemplateVM()
-	if err != nil {
+	if err == nil && t.current() {
+		return t, nil
+	}
+
+	if storePath == "" {
+		return nil, err
+	}
+
+	if err == nil {
+		t.Logger().Info("removing stale template VM")
+		t.close()
+	}
+
+	if err = t.installTemplateVM(); err != nil {
 		return nil, err
 	}
 
 	return t, nil
 }
 
-// New creates a new VM template factory.
-func New(ctx context.Context, config vc.VMConfig, templatePath string) (base.FactoryBase, error) {
-	t := &template{templatePath, config}
+// New creates a new VM template factory. With a template store, the new
+// template is saved to the store.
+func New(ctx context.Context, config vc.VMConfig, templatePath, storePath string) (base.FactoryBase, error) {
+	t := &template{templatePath, storePath, config}
 
 	err := t.checkTemplateVM()
 	if err == nil {
-		return nil, fmt.Errorf("There is already a VM template in %s", templatePath)
+		if t.current() {
+			return nil, fmt.Errorf("There is already a VM template in %s", templatePath)
+		}
+		t.Logger().Info("removing stale template VM")
+		t.close()
 	}
 
 	err = t.prepareTemplateFiles()
@@ -64,9 +85,110 @@ func New(ctx context.Context, config vc.VMConfig, templatePath string) (base.Fac
 		return nil, err
 	}
 
+	if storePath != "" {
+		// the template can be used even if it cannot be saved
+		if _, err := t.save(); err != nil {
+			t.Logger().WithError(err).Warn("failed to save template VM to the template store")
+		}
+	}
+
 	return t, nil
 }
 
+// Save saves the template VM in templatePath, created for config, to the
+// template store in storePath.
+func Save(config vc.VMConfig, templatePath, storePath string) (*Metadata, error) {
+	t := &template{templatePath, storePath, config}
+
+	if err := t.checkTemplateVM(); err != nil {
+		return nil, err
+	}
+
+	return t.save()
+}
+
+func (t *template) save() (*Metadata, error) {
+	md, err := newMetadata(t.config)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := readMetadata(t.statePath)
+	if os.IsNotExist(err) {
+		// the template was just created
+		if err := writeMetadata(t.statePath, md); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if current.ID != md.ID {
+		return nil, fmt.Errorf("the VM template in %s was not created for this configuration", t.statePath)
+	} else {
+		md = current
+	}
+
+	if err := NewStore(t.storePath).save(t.statePath, md); err != nil {
+		return nil, err
+	}
+
+	return md, nil
+}
+
+// current returns true if the template in statePath was created for the
+// config of the factory. Templates are only keyed with a template store.
+func (t *template) current() bool {
+	if t.storePath == "" {
+		return true
+	}
+
+	md, err := readMetadata(t.statePath)
+	if err != nil {
+		return false
+	}
+	loadAssetDigests(md)
+
+	id, err := ConfigKey(t.config)
+	if err != nil {
+		t.Logger().WithError(err).Warn("failed to compute template key")
+		return false
+	}
+
+	return md.ID == id
+}
+
+// installTemplateVM installs the template of the config of the factory
+// from the template store.
+func (t *template) installTemplateVM() error {
+	store := NewStore(t.storePath)
+	store.loadAssetDigests()
+
+	id, err := ConfigKey(t.config)
+	if err != nil {
+		return err
+	}
+
+	if _, err := store.Get(id); err != nil {
+		return err
+	}
+
+	if err := t.prepareTemplateFiles(); err != nil {
+		return err
+	}
+
+	md, err := store.install(id, t.statePath)
+	if err == nil {
+		err = writeMetadata(t.statePath, md)
+	}
+	if err != nil {
+		t.close()
+		return err
+	}
+
+	t.Logger().WithField("template", id).Info("template VM installed from the template store")
+
+	return nil
+}
+
 // Config returns template factory's configuration.
 func (t *template) Config() vc.VMConfig {
 	return t.config
@@ -109,7 +231,7 @@ func (t *template) prepareTemplateFiles() error {
 		t.close()
 		return err
 	}
-	f, err := os.Create(t.statePath + "/memory")
+	f, err := os.Create(filepath.Join(t.statePath, memoryFile))
 	if err != nil {
 		t.close()
 		return err
@@ -124,8 +246,8 @@ func (t *template) createTemplateVM(ctx context.Context) error {
 	config := t.config
 	config.HypervisorConfig.BootToBeTemplate = true
 	config.HypervisorConfig.BootFromTemplate = false
-	config.HypervisorConfig.MemoryPath = t.statePath + "/memory"
-	config.HypervisorConfig.DevicesStatePath = t.statePath + "/state"
+	config.HypervisorConfig.MemoryPath = filepath.Join(t.statePath, memoryFile)
+	config.HypervisorConfig.DevicesStatePath = filepath.Join(t.statePath, deviceStateFile)
 
 	vm, err := vc.NewVM(ctx, config)
 	if err != nil {
@@ -161,8 +283,8 @@ func (t *template) createFromTemplateVM(ctx context.Context, c vc.VMConfig) (*vc
 	config := t.config
 	config.HypervisorConfig.BootToBeTemplate = false
 	config.HypervisorConfig.BootFromTemplate = true
-	config.HypervisorConfig.MemoryPath = t.statePath + "/memory"
-	config.HypervisorConfig.DevicesStatePath = t.statePath + "/state"
+	config.HypervisorConfig.MemoryPath = filepath.Join(t.statePath, memoryFile)
+	config.HypervisorConfig.DevicesStatePath = filepath.Join(t.statePath, deviceStateFile)
 	config.HypervisorConfig.SharedPath = c.HypervisorConfig.SharedPath
 	config.HypervisorConfig.VMStorePath = c.HypervisorConfig.VMStorePath
 	config.HypervisorConfig.RunStorePath = c.HypervisorConfig.RunStorePath
@@ -171,12 +293,12 @@ func (t *template) createFromTemplateVM(ctx context.Context, c vc.VMConfig) (*vc
 }
 
 func (t *template) checkTemplateVM() error {
-	_, err := os.Stat(t.statePath + "/memory")
+	_, err := os.Stat(filepath.Join(t.statePath, memoryFile))
 	if err != nil {
 		return err
 	}
 
-	_, err = os.Stat(t.statePath + "/state")
+	_, err = os.Stat(filepath.Join(t.statePath, deviceStateFile))
 	return err
 }
 
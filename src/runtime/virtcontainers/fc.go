@@ -68,8 +68,8 @@ const (
 	fcKernel             = "vmlinux"
 	fcRootfs             = "rootfs"
 	fcStopSandboxTimeout = 15
-	// This indicates the number of block devices that can be attached to the
-	// firecracker guest VM.
+	// This indicates the default number of block devices that can be attached to the
+	// firecracker guest VM at the same time, see drive_pool_size in the configuration file.
 	// We attach a pool of placeholder drives before the guest has started, and then
 	// patch the replace placeholder drives with drives with actual contents.
 	fcDiskPoolSize           = 8
@@ -440,6 +440,10 @@ func (fc *firecracker) fcEnd(ctx context.Context, waitOnly bool) (err error) {
 	}()
 
 	pid := fc.info.PID
+	if pid == 0 {
+		fc.Logger().Info("Firecracker was not started")
+		return nil
+	}
 
 	shutdownSignal := syscall.SIGTERM
 
@@ -816,11 +820,22 @@ func fcDriveIndexToID(i int) string {
 	return "drive_" + strconv.Itoa(i)
 }
 
+// drivePoolSize returns the number of placeholder drives of the VM. The pool
+// is created before the VM boots and cannot grow afterwards: the drives of
+// the removed block devices are reused.
+func (fc *firecracker) drivePoolSize() int {
+	if fc.config.DrivePoolSize == 0 {
+		return fcDiskPoolSize
+	}
+
+	return int(fc.config.DrivePoolSize)
+}
+
 func (fc *firecracker) createDiskPool(ctx context.Context) error {
 	span, _ := katatrace.Trace(ctx, fc.Logger(), "createDiskPool", fcTracingTags, map[string]string{"sandbox_id": fc.id})
 	defer span.End()
 
-	for i := 0; i < fcDiskPoolSize; i++ {
+	for i := 0; i < fc.drivePoolSize(); i++ {
 		driveID := fcDriveIndexToID(i)
 		isReadOnly := false
 		isRootDevice := false
@@ -1057,6 +1072,10 @@ func (fc *firecracker) AddDevice(ctx context.Context, devInfo interface{}, devTy
 	switch v := devInfo.(type) {
 	case Endpoint:
 		fc.Logger().WithField("device-type-endpoint", devInfo).Info("Adding device")
+		// Firecracker only opens tap interfaces
+		if v.NetworkPair() == nil {
+			return fmt.Errorf("firecracker does not support %s endpoints", v.Type())
+		}
 		fc.fcAddNetDevice(ctx, v)
 	case config.BlockDrive:
 		fc.Logger().WithField("device-type-blockdrive", devInfo).Info("Adding device")
@@ -1082,6 +1101,13 @@ func (fc *firecracker) hotplugBlockDevice(ctx context.Context, drive config.Bloc
 	var err error
 	driveID := fcDriveIndexToID(drive.Index)
 
+	// Firecracker cannot add drives to a running VM, only the drives of
+	// the pool can be replaced.
+	if drive.Index >= fc.drivePoolSize() {
+		return nil, fmt.Errorf("firecracker drive pool exhausted: block device %s needs drive %d but the pool has %d drives, increase drive_pool_size",
+			drive.File, drive.Index, fc.drivePoolSize())
+	}
+
 	if op == AddDevice {
 		//The drive placeholder has to exist prior to Update
 		path, err = fc.fcJailResource(drive.File, driveID)
@@ -1102,7 +1128,16 @@ func (fc *firecracker) hotplugBlockDevice(ctx context.Context, drive config.Bloc
 		}
 	}
 
-	return nil, fc.fcUpdateBlockDrive(ctx, path, driveID)
+	if err := fc.fcUpdateBlockDrive(ctx, path, driveID); err != nil {
+		if op == AddDevice {
+			// Firecracker still uses the drive of the pool, the
+			// device is not needed in the jail anymore.
+			fc.umountResource(driveID)
+		}
+		return nil, err
+	}
+
+	return nil, nil
 }
 
 // hotplugAddDevice supported in Firecracker VMM
@@ -1279,3 +1314,19 @@ func (fc *firecracker) GenerateSocket(id string) (interface{}, error) {
 func (fc *firecracker) IsRateLimiterBuiltin() bool {
 	return true
 }
+
+func (fc *firecracker) UpdateBlockRateLimiter(ctx context.Context, drive *config.BlockDrive, rateLimiter *config.BlockRateLimiter) error {
+	return errors.New("firecracker does not support updating block rate limiters")
+}
+
+func (fc *firecracker) ResizeBlockDevice(ctx context.Context, drive *config.BlockDrive, size uint64) error {
+	return errors.New("firecracker does not support resizing block devices")
+}
+
+func (fc *firecracker) ResizeBalloon(ctx context.Context, memMB uint32) error {
+	return errors.New("firecracker does not support memory ballooning")
+}
+
+func (fc *firecracker) SnapshotBlockDevice(ctx context.Context, drive *config.BlockDrive, snapshotPath string) error {
+	return errors.New("firecracker does not support block device snapshots")
+}
@@ -8,13 +8,32 @@
 package virtcontainers
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
+	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/stretchr/testify/assert"
 )
 
+// newFcConfig returns a Firecracker configuration: a kernel and a rootfs
+// image on virtio-mmio drives, no shared filesystem.
+func newFcConfig() HypervisorConfig {
+	return HypervisorConfig{
+		KernelPath:        filepath.Join(testDir, testKernel),
+		ImagePath:         filepath.Join(testDir, testImage),
+		HypervisorPath:    filepath.Join(testDir, testHypervisor),
+		NumVCPUs:          defaultVCPUs,
+		MemorySize:        defaultMemSzMiB,
+		DefaultMaxVCPUs:   defaultMaxVCPUs,
+		BlockDeviceDriver: config.VirtioMmio,
+	}
+}
+
 func TestFCGenerateSocket(t *testing.T) {
 	assert := assert.New(t)
 
@@ -81,3 +100,51 @@ func TestFcSetConfig(t *testing.T) {
 
 	assert.Equal(fc.config, config)
 }
+
+func TestFcDrivePoolSize(t *testing.T) {
+	assert := assert.New(t)
+
+	fc := firecracker{}
+	assert.Equal(fcDiskPoolSize, fc.drivePoolSize())
+
+	fc.config.DrivePoolSize = 16
+	assert.Equal(16, fc.drivePoolSize())
+
+	// block devices cannot be plugged beyond the pool
+	_, err := fc.hotplugBlockDevice(context.Background(), config.BlockDrive{File: "/dev/null", Index: 16}, AddDevice)
+	assert.Error(err)
+	assert.Contains(err.Error(), "drive_pool_size")
+}
+
+func TestFcStopVMNotStarted(t *testing.T) {
+	fc := &firecracker{}
+	assert.NoError(t, fc.StopVM(context.Background(), false))
+}
+
+func TestFcHotplugBlockDeviceUpdateError(t *testing.T) {
+	// The drives are jailed with bind mounts.
+	if tc.NotValid(ktu.NeedRoot()) {
+		t.Skip(testDisabledAsNonRoot)
+	}
+
+	assert := assert.New(t)
+
+	fc := &firecracker{jailerRoot: t.TempDir()}
+	// nothing listens on the API socket, the drive update fails
+	fc.socketPath = filepath.Join(fc.jailerRoot, "run", fcSocket)
+
+	driveID := fcDriveIndexToID(1)
+	_, err := fc.createJailedDrive(driveID)
+	assert.NoError(err)
+
+	disk := filepath.Join(t.TempDir(), "disk.img")
+	assert.NoError(os.WriteFile(disk, nil, 0600))
+
+	_, err = fc.hotplugBlockDevice(context.Background(), config.BlockDrive{File: disk, Index: 1}, AddDevice)
+	assert.Error(err)
+
+	// the device is not left mounted in the jail
+	mounts, err := os.ReadFile("/proc/self/mountinfo")
+	assert.NoError(err)
+	assert.NotContains(string(mounts), filepath.Join(fc.jailerRoot, driveID))
+}
@@ -217,6 +217,10 @@ func (f *FilesystemShare) Cleanup(ctx context.Context) error {
 		f.Logger().WithError(err).Errorf("failed to unmount vm mount path %s", path)
 		return err
 	}
+	if err = unmountNFSVolumes(f.sandbox.ID()); err != nil {
+		f.Logger().WithError(err).Errorf("failed to unmount the nfs volumes")
+		return err
+	}
 	if err = os.RemoveAll(getSandboxPath(f.sandbox.ID())); err != nil {
 		f.Logger().WithError(err).Errorf("failed to Cleanup vm path %s", getSandboxPath(f.sandbox.ID()))
 		return err
@@ -458,6 +462,10 @@ func (f *FilesystemShare) ShareRootFilesystem(ctx context.Context, c *Container)
 			rootfsStorage.Options = []string{"nouuid"}
 		}
 
+		if blockDrive.ReadOnly {
+			rootfsStorage.Options = append(rootfsStorage.Options, "ro")
+		}
+
 		// Ensure container mount destination exists
 		// TODO: remove dependency on shared fs path. shared fs is just one kind of storage source.
 		// we should not always use shared fs path for all kinds of storage. Instead, all storage
@@ -0,0 +1,255 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+// GuestHookDeliveryFailurePolicy tells what happens to a container when one
+// of the guest hooks cannot be delivered to the guest. It does not apply to
+// the failure of a hook run by the agent, which the agent handles.
+type GuestHookDeliveryFailurePolicy string
+
+const (
+	// GuestHookFail fails the creation of the container.
+	GuestHookFail GuestHookDeliveryFailurePolicy = "fail"
+
+	// GuestHookIgnore creates the container without the hook.
+	GuestHookIgnore GuestHookDeliveryFailurePolicy = "ignore"
+)
+
+// Set sets the policy from its name, the empty name being the default
+// "fail" policy.
+func (p *GuestHookDeliveryFailurePolicy) Set(name string) error {
+	switch GuestHookDeliveryFailurePolicy(name) {
+	case "", GuestHookFail:
+		*p = GuestHookFail
+	case GuestHookIgnore:
+		*p = GuestHookIgnore
+	default:
+		return fmt.Errorf("unknown guest hook delivery failure policy %q", name)
+	}
+
+	return nil
+}
+
+// GuestHooksConfig describes the OCI hooks the agent runs inside the
+// guest for every container of a sandbox.
+type GuestHooksConfig struct {
+	// Hooks are the prestart, poststart and poststop hooks. Their paths
+	// are host paths: the executables are shared with the guest, or
+	// copied into it when the hypervisor cannot share files.
+	Hooks *specs.Hooks
+
+	// DeliveryFailurePolicy tells what happens to a container when one of
+	// the hooks cannot be delivered to the guest.
+	DeliveryFailurePolicy GuestHookDeliveryFailurePolicy
+
+	// Timeout is the timeout in seconds of the hooks which do not set
+	// one. The agent default applies when it is zero.
+	Timeout int
+
+	// OCIHooks runs the prestart, poststart and poststop hooks of the OCI
+	// spec of each container in the guest, after Hooks, rather than on
+	// the host.
+	OCIHooks bool
+}
+
+// guestHook is a guest hook delivered to the guest for a container.
+type guestHook struct {
+	stage     string
+	hostPath  string
+	guestPath string
+}
+
+// valid checks the guest hooks only use the stages the agent runs.
+func (conf GuestHooksConfig) valid() error {
+	if conf.Timeout < 0 {
+		return fmt.Errorf("invalid guest hook timeout %d", conf.Timeout)
+	}
+
+	if conf.Hooks == nil {
+		return nil
+	}
+
+	if len(conf.Hooks.CreateRuntime) > 0 || len(conf.Hooks.CreateContainer) > 0 || len(conf.Hooks.StartContainer) > 0 {
+		return fmt.Errorf("guest hooks only support the prestart, poststart and poststop stages")
+	}
+
+	for _, stage := range [][]specs.Hook{conf.Hooks.Prestart, conf.Hooks.Poststart, conf.Hooks.Poststop} {
+		for _, h := range stage {
+			if h.Path == "" {
+				return fmt.Errorf("guest hook without a path")
+			}
+			if h.Timeout != nil && *h.Timeout < 0 {
+				return fmt.Errorf("invalid timeout %d for guest hook %s", *h.Timeout, h.Path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// guestHookStage is a stage of the guest hooks, with the agent operation
+// running them.
+type guestHookStage struct {
+	name  string
+	hooks func(*specs.Hooks) []specs.Hook
+	guest func(*grpc.Hooks) *[]grpc.Hook
+}
+
+var (
+	guestPrestart = guestHookStage{
+		name:  "prestart",
+		hooks: func(h *specs.Hooks) []specs.Hook { return h.Prestart },
+		guest: func(h *grpc.Hooks) *[]grpc.Hook { return &h.Prestart },
+	}
+	guestPoststart = guestHookStage{
+		name:  "poststart",
+		hooks: func(h *specs.Hooks) []specs.Hook { return h.Poststart },
+		guest: func(h *grpc.Hooks) *[]grpc.Hook { return &h.Poststart },
+	}
+	guestPoststop = guestHookStage{
+		name:  "poststop",
+		hooks: func(h *specs.Hooks) []specs.Hook { return h.Poststop },
+		guest: func(h *grpc.Hooks) *[]grpc.Hook { return &h.Poststop },
+	}
+)
+
+// containerGuestHooks returns the guest hooks of the container: the ones
+// of the sandbox, then the ones of the container OCI spec if they are run
+// in the guest.
+func (c *Container) containerGuestHooks() []*specs.Hooks {
+	conf := c.sandbox.config.GuestHooks
+
+	var hooks []*specs.Hooks
+	if conf.Hooks != nil {
+		hooks = append(hooks, conf.Hooks)
+	}
+	if conf.OCIHooks && c.config != nil && c.config.CustomSpec != nil && c.config.CustomSpec.Hooks != nil {
+		hooks = append(hooks, c.config.CustomSpec.Hooks)
+	}
+
+	return hooks
+}
+
+// shareGuestHooks shares the guest hooks of the container with the guest
+// and returns them with their guest paths, for the agent to run them
+// with the container. The result of each hook delivery is published as
+// an EventGuestHookShared event.
+func (c *Container) shareGuestHooks(ctx context.Context) (*grpc.Hooks, error) {
+	conf := c.sandbox.config.GuestHooks
+	containerHooks := c.containerGuestHooks()
+	if len(containerHooks) == 0 {
+		return nil, nil
+	}
+
+	c.guestHooks = nil
+	hooks := &grpc.Hooks{}
+	for _, stage := range []guestHookStage{guestPrestart, guestPoststart, guestPoststop} {
+		guestHooks := stage.guest(hooks)
+
+		var stageHooks []specs.Hook
+		for _, h := range containerHooks {
+			stageHooks = append(stageHooks, stage.hooks(h)...)
+		}
+
+		for _, h := range stageHooks {
+			guestPath, err := c.shareGuestHook(ctx, h.Path)
+			c.sandbox.publishEvent(EventGuestHookShared, c.id, err, map[string]string{
+				"stage":      stage.name,
+				"host-path":  h.Path,
+				"guest-path": guestPath,
+			})
+
+			if err != nil {
+				if conf.DeliveryFailurePolicy != GuestHookIgnore {
+					return nil, fmt.Errorf("could not share %s guest hook %s: %v", stage.name, h.Path, err)
+				}
+
+				c.Logger().WithError(err).WithFields(logrus.Fields{
+					"stage": stage.name,
+					"hook":  h.Path,
+				}).Warn("Ignoring guest hook which could not be shared")
+				continue
+			}
+
+			timeout := int64(conf.Timeout)
+			if h.Timeout != nil {
+				timeout = int64(*h.Timeout)
+			}
+
+			*guestHooks = append(*guestHooks, grpc.Hook{
+				Path:    guestPath,
+				Args:    h.Args,
+				Env:     h.Env,
+				Timeout: timeout,
+			})
+			c.guestHooks = append(c.guestHooks, guestHook{
+				stage:     stage.name,
+				hostPath:  h.Path,
+				guestPath: guestPath,
+			})
+		}
+	}
+
+	return hooks, nil
+}
+
+// shareGuestHook shares the hook executable at path with the guest and
+// returns its guest path.
+func (c *Container) shareGuestHook(ctx context.Context, path string) (string, error) {
+	m := Mount{
+		Source:      path,
+		Destination: path,
+		Type:        "bind",
+		ReadOnly:    true,
+	}
+
+	shared, err := c.sandbox.fsShare.ShareFile(ctx, c, &m)
+	if err != nil {
+		return "", err
+	}
+	if shared == nil {
+		return "", fmt.Errorf("%s is not a regular file", path)
+	}
+
+	if m.HostPath != "" {
+		c.guestHookMounts = append(c.guestHookMounts, m)
+	}
+
+	return shared.guestPath, nil
+}
+
+// publishGuestHookStageRun publishes the result of the agent operation
+// which ran the guest hooks of stage as an EventGuestHookStageRun event,
+// when the container has hooks for the stage. The agent runs all the hooks
+// of a stage and does not report the status of a single hook, which it logs
+// in the guest.
+func (c *Container) publishGuestHookStageRun(stage guestHookStage, err error) {
+	var hostPaths []string
+	for _, h := range c.guestHooks {
+		if h.stage == stage.name {
+			hostPaths = append(hostPaths, h.hostPath)
+		}
+	}
+
+	if len(hostPaths) == 0 {
+		return
+	}
+
+	c.sandbox.publishEvent(EventGuestHookStageRun, c.id, err, map[string]string{
+		"stage": stage.name,
+		"hooks": strings.Join(hostPaths, ","),
+	})
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeFilesystemSharer shares files by pretending to bind mount them in
+// the shared directory, and fails to share the fail path.
+type fakeFilesystemSharer struct {
+	FilesystemSharer
+	fail     string
+	unshared []string
+}
+
+func (f *fakeFilesystemSharer) ShareFile(ctx context.Context, c *Container, m *Mount) (*SharedFile, error) {
+	if m.Source == f.fail {
+		return nil, errors.New("share failed")
+	}
+
+	m.HostPath = filepath.Join("/shared", c.id, filepath.Base(m.Source))
+	return &SharedFile{
+		guestPath: filepath.Join(kataGuestSharedDir(), c.id, filepath.Base(m.Source)),
+	}, nil
+}
+
+func (f *fakeFilesystemSharer) UnshareFile(ctx context.Context, c *Container, m *Mount) error {
+	f.unshared = append(f.unshared, m.HostPath)
+	return nil
+}
+
+func newGuestHooksContainer(conf GuestHooksConfig, sharer FilesystemSharer) *Container {
+	return &Container{
+		id: "ctr",
+		sandbox: &Sandbox{
+			id:      "sandbox",
+			config:  &SandboxConfig{GuestHooks: conf},
+			fsShare: sharer,
+			events:  newEventBus(),
+		},
+	}
+}
+
+func TestGuestHookDeliveryFailurePolicySet(t *testing.T) {
+	assert := assert.New(t)
+
+	var p GuestHookDeliveryFailurePolicy
+	assert.NoError(p.Set(""))
+	assert.Equal(GuestHookFail, p)
+	assert.NoError(p.Set("ignore"))
+	assert.Equal(GuestHookIgnore, p)
+	assert.Error(p.Set("retry"))
+}
+
+func TestGuestHooksConfigValid(t *testing.T) {
+	assert := assert.New(t)
+	timeout := -1
+
+	assert.NoError(GuestHooksConfig{}.valid())
+	assert.NoError(GuestHooksConfig{Hooks: &specs.Hooks{Prestart: []specs.Hook{{Path: "/bin/hook"}}}}.valid())
+	assert.Error(GuestHooksConfig{Timeout: -1}.valid())
+	assert.Error(GuestHooksConfig{Hooks: &specs.Hooks{CreateContainer: []specs.Hook{{Path: "/bin/hook"}}}}.valid())
+	assert.Error(GuestHooksConfig{Hooks: &specs.Hooks{Poststop: []specs.Hook{{}}}}.valid())
+	assert.Error(GuestHooksConfig{Hooks: &specs.Hooks{Poststart: []specs.Hook{{Path: "/bin/hook", Timeout: &timeout}}}}.valid())
+}
+
+func TestContainerShareGuestHooks(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	timeout := 3
+
+	sharer := &fakeFilesystemSharer{}
+	c := newGuestHooksContainer(GuestHooksConfig{
+		Hooks: &specs.Hooks{
+			Prestart: []specs.Hook{
+				{Path: "/usr/libexec/gpu-setup", Args: []string{"gpu-setup", "--all"}, Env: []string{"A=b"}},
+			},
+			Poststop: []specs.Hook{
+				{Path: "/usr/libexec/monitor", Timeout: &timeout},
+			},
+		},
+		Timeout: 10,
+	}, sharer)
+
+	events, unsubscribe := c.sandbox.SubscribeEvents()
+	defer unsubscribe()
+
+	hooks, err := c.shareGuestHooks(ctx)
+	assert.NoError(err)
+	assert.Equal(&grpc.Hooks{
+		Prestart: []grpc.Hook{{
+			Path:    filepath.Join(kataGuestSharedDir(), "ctr", "gpu-setup"),
+			Args:    []string{"gpu-setup", "--all"},
+			Env:     []string{"A=b"},
+			Timeout: 10,
+		}},
+		Poststop: []grpc.Hook{{
+			Path:    filepath.Join(kataGuestSharedDir(), "ctr", "monitor"),
+			Timeout: 3,
+		}},
+	}, hooks)
+
+	for _, stage := range []string{"prestart", "poststop"} {
+		e := <-events
+		assert.Equal(EventGuestHookShared, e.Type)
+		assert.Equal("ctr", e.ContainerID)
+		assert.Equal(stage, e.Data["stage"])
+		assert.Empty(e.Error)
+	}
+
+	c.publishGuestHookStageRun(guestPrestart, errors.New("hook failed"))
+	c.publishGuestHookStageRun(guestPoststart, nil)
+	e := <-events
+	assert.Equal(EventGuestHookStageRun, e.Type)
+	assert.Equal("prestart", e.Data["stage"])
+	assert.Equal("/usr/libexec/gpu-setup", e.Data["hooks"])
+	assert.Equal("hook failed", e.Error)
+	assert.Empty(events, "no event for the stages without hooks")
+
+	// The shared hooks are saved with the container, for them to go
+	// away with the container mounts after a restart.
+	s := &Sandbox{containers: map[string]*Container{"ctr": c}}
+	cs := make(map[string]persistapi.ContainerState)
+	s.dumpGuestHooks(cs)
+	restored := &Container{id: "ctr", sandbox: c.sandbox}
+	restored.loadContGuestHooks(cs["ctr"])
+	assert.Equal(c.guestHookMounts, restored.guestHookMounts)
+	assert.Equal(c.guestHooks, restored.guestHooks)
+	c = restored
+
+	assert.NoError(c.unmountHostMounts(ctx))
+	assert.Equal([]string{"/shared/ctr/gpu-setup", "/shared/ctr/monitor"}, sharer.unshared)
+	assert.Empty(c.guestHookMounts)
+}
+
+func TestContainerShareGuestOCIHooks(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	c := newGuestHooksContainer(GuestHooksConfig{
+		Hooks: &specs.Hooks{
+			Prestart: []specs.Hook{{Path: "/usr/libexec/gpu-setup"}},
+		},
+	}, &fakeFilesystemSharer{})
+	c.config = &ContainerConfig{
+		CustomSpec: &specs.Spec{
+			Hooks: &specs.Hooks{
+				Prestart:      []specs.Hook{{Path: "/usr/libexec/oci-prestart"}},
+				CreateRuntime: []specs.Hook{{Path: "/usr/libexec/oci-runtime"}},
+			},
+		},
+	}
+
+	// The hooks of the OCI spec are run on the host by default.
+	hooks, err := c.shareGuestHooks(ctx)
+	assert.NoError(err)
+	assert.Len(hooks.Prestart, 1)
+
+	// They follow the ones of the sandbox when they are run in the
+	// guest, except for the stages the agent does not run.
+	c.sandbox.config.GuestHooks.OCIHooks = true
+	hooks, err = c.shareGuestHooks(ctx)
+	assert.NoError(err)
+	assert.Equal([]grpc.Hook{
+		{Path: filepath.Join(kataGuestSharedDir(), "ctr", "gpu-setup")},
+		{Path: filepath.Join(kataGuestSharedDir(), "ctr", "oci-prestart")},
+	}, hooks.Prestart)
+	assert.Len(c.guestHooks, 2)
+
+	c.sandbox.config.GuestHooks.Hooks = nil
+	hooks, err = c.shareGuestHooks(ctx)
+	assert.NoError(err)
+	assert.Len(hooks.Prestart, 1)
+}
+
+func TestContainerShareGuestHooksDeliveryFailurePolicy(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	conf := GuestHooksConfig{
+		Hooks: &specs.Hooks{
+			Prestart: []specs.Hook{
+				{Path: "/usr/libexec/missing"},
+				{Path: "/usr/libexec/gpu-setup"},
+			},
+		},
+	}
+	sharer := &fakeFilesystemSharer{fail: "/usr/libexec/missing"}
+
+	_, err := newGuestHooksContainer(conf, sharer).shareGuestHooks(ctx)
+	assert.Error(err)
+
+	conf.DeliveryFailurePolicy = GuestHookIgnore
+	c := newGuestHooksContainer(conf, sharer)
+	events, unsubscribe := c.sandbox.SubscribeEvents()
+	defer unsubscribe()
+
+	hooks, err := c.shareGuestHooks(ctx)
+	assert.NoError(err)
+	assert.Len(hooks.Prestart, 1)
+	assert.Equal(filepath.Join(kataGuestSharedDir(), "ctr", "gpu-setup"), hooks.Prestart[0].Path)
+
+	e := <-events
+	assert.Equal("/usr/libexec/missing", e.Data["host-path"])
+	assert.Equal("share failed", e.Error)
+}
+
+func TestContainerShareNoGuestHooks(t *testing.T) {
+	c := newGuestHooksContainer(GuestHooksConfig{}, &fakeFilesystemSharer{})
+
+	hooks, err := c.shareGuestHooks(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, hooks)
+}
@@ -79,6 +79,10 @@ const (
 	// MinHypervisorMemory is the minimum memory required for a VM.
 	MinHypervisorMemory = 256
 
+	// MaxDrivePoolSize is the maximum number of placeholder drives
+	// attached to a VM, see DrivePoolSize.
+	MaxDrivePoolSize = 64
+
 	defaultMsize9p = 8192
 
 	defaultDisableGuestSeLinux = true
@@ -152,6 +156,9 @@ const (
 	// HybridVirtioVsockDev is a hybrid virtio-vsock device supported
 	// only on certain hypervisors, like firecracker.
 	HybridVirtioVsockDev
+
+	// VDPADev is a vhost-vdpa device type
+	VDPADev
 )
 
 type MemoryDevice struct {
@@ -264,6 +271,7 @@ type Param struct {
 type HypervisorConfig struct {
 	customAssets                   map[types.AssetType]*types.Asset
 	SeccompSandbox                 string
+	QMPRecordPath                  string
 	KernelPath                     string
 	ImagePath                      string
 	InitrdPath                     string
@@ -301,6 +309,8 @@ type HypervisorConfig struct {
 	RemoteHypervisorSocket         string
 	SandboxName                    string
 	SandboxNamespace               string
+	SwtpmPath                      string
+	AssetTrustStore                string
 	JailerPathList                 []string
 	EntropySourceList              []string
 	VirtioFSDaemonList             []string
@@ -314,6 +324,7 @@ type HypervisorConfig struct {
 	Groups                         []uint32
 	HypervisorPathList             []string
 	HypervisorParams               []Param
+	NUMANodes                      []NUMANode
 	DiskRateLimiterBwOneTimeBurst  int64
 	DiskRateLimiterOpsMaxRate      int64
 	DiskRateLimiterOpsOneTimeBurst int64
@@ -340,6 +351,8 @@ type HypervisorConfig struct {
 	PCIeRootPort                   uint32
 	NumVCPUs                       uint32
 	RemoteHypervisorTimeout        uint32
+	FreezeMemory                   uint32
+	DrivePoolSize                  uint32
 	IOMMUPlatform                  bool
 	EnableIOThreads                bool
 	Debug                          bool
@@ -364,11 +377,15 @@ type HypervisorConfig struct {
 	EnableVhostUserStore           bool
 	GuestSwap                      bool
 	Rootless                       bool
+	UserNamespace                  bool
 	DisableSeccomp                 bool
 	DisableSeLinux                 bool
 	DisableGuestSeLinux            bool
 	LegacySerial                   bool
 	EnableVCPUsPinning             bool
+	EnableNUMA                     bool
+	EnableVTPM                     bool
+	EnableJSONSyntax               bool
 }
 
 // vcpu mapping from vcpu number to thread number
@@ -436,25 +453,61 @@ func (conf *HypervisorConfig) assetPath(t types.AssetType) (string, error) {
 
 	// We could not find a custom asset for the given type, let's
 	// fall back to the configured ones.
+	p := conf.assetPathField(t)
+	if p == nil {
+		return "", fmt.Errorf("Unknown asset type %v", t)
+	}
+
+	return *p, nil
+}
+
+// assetPathField returns the configured path of the asset type t.
+func (conf *HypervisorConfig) assetPathField(t types.AssetType) *string {
 	switch t {
 	case types.KernelAsset:
-		return conf.KernelPath, nil
+		return &conf.KernelPath
 	case types.ImageAsset:
-		return conf.ImagePath, nil
+		return &conf.ImagePath
 	case types.InitrdAsset:
-		return conf.InitrdPath, nil
+		return &conf.InitrdPath
 	case types.HypervisorAsset:
-		return conf.HypervisorPath, nil
+		return &conf.HypervisorPath
 	case types.HypervisorCtlAsset:
-		return conf.HypervisorCtlPath, nil
+		return &conf.HypervisorCtlPath
 	case types.JailerAsset:
-		return conf.JailerPath, nil
+		return &conf.JailerPath
 	case types.FirmwareAsset:
-		return conf.FirmwarePath, nil
+		return &conf.FirmwarePath
 	case types.FirmwareVolumeAsset:
-		return conf.FirmwareVolumePath, nil
+		return &conf.FirmwareVolumePath
 	default:
-		return "", fmt.Errorf("Unknown asset type %v", t)
+		return nil
+	}
+}
+
+// AddCustomAssets adds the custom assets set by the annotations.
+func (conf *HypervisorConfig) AddCustomAssets(annotations map[string]string) error {
+	for _, t := range types.AssetTypes() {
+		a, err := types.NewAsset(annotations, t)
+		if err != nil {
+			return err
+		}
+
+		if err := conf.AddCustomAsset(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ApplyCustomAssets sets the configured asset paths to the ones of the
+// custom assets, which the hypervisor uses instead.
+func (conf *HypervisorConfig) ApplyCustomAssets() {
+	for t, a := range conf.customAssets {
+		if p := conf.assetPathField(t); p != nil {
+			*p = a.Path()
+		}
 	}
 }
 
@@ -463,6 +516,63 @@ func (conf *HypervisorConfig) isCustomAsset(t types.AssetType) bool {
 	return ok
 }
 
+// VerifyAssets verifies the signatures of the assets of the hypervisor
+// against the asset trust store. It returns no verification when no trust
+// store is configured.
+func (conf *HypervisorConfig) VerifyAssets() ([]types.AssetVerification, error) {
+	if conf.AssetTrustStore == "" {
+		return nil, nil
+	}
+
+	store, err := types.LoadTrustStore(conf.AssetTrustStore)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifications []types.AssetVerification
+	for _, t := range types.AssetTypes() {
+		path, err := conf.assetPath(t)
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			continue
+		}
+
+		verifications = append(verifications, store.Verify(t, path))
+	}
+
+	return verifications, nil
+}
+
+func (conf *HypervisorConfig) verifyAssets() error {
+	verifications, err := conf.VerifyAssets()
+	if err != nil {
+		return err
+	}
+
+	for _, v := range verifications {
+		if v.Err != nil {
+			return v.Err
+		}
+
+		hvLogger.WithFields(logrus.Fields{
+			"asset":         v.Type,
+			"path":          v.Path,
+			"verified-path": v.VerifiedPath,
+			"key":           v.Key,
+			"cached":        v.Cached,
+		}).Debug("Verified asset signature")
+
+		// The hypervisor uses the verified copy of the asset, which
+		// cannot be replaced.
+		delete(conf.customAssets, v.Type)
+		*conf.assetPathField(v.Type) = v.VerifiedPath
+	}
+
+	return nil
+}
+
 // KernelAssetPath returns the guest kernel path
 func (conf *HypervisorConfig) KernelAssetPath() (string, error) {
 	return conf.assetPath(types.KernelAsset)
@@ -769,4 +879,20 @@ type Hypervisor interface {
 
 	// check if hypervisor supports built-in rate limiter.
 	IsRateLimiterBuiltin() bool
+
+	// UpdateBlockRateLimiter changes the I/O limits of a block device
+	// already attached to the VM.
+	UpdateBlockRateLimiter(ctx context.Context, drive *config.BlockDrive, rateLimiter *config.BlockRateLimiter) error
+
+	// ResizeBlockDevice notifies the VM that the file backing a block
+	// device already attached to the VM was resized to size bytes.
+	ResizeBlockDevice(ctx context.Context, drive *config.BlockDrive, size uint64) error
+
+	// ResizeBalloon resizes the memory balloon of the VM so that the
+	// guest is left with memMB MiB of memory.
+	ResizeBalloon(ctx context.Context, memMB uint32) error
+
+	// SnapshotBlockDevice copies a crash-consistent snapshot of a block
+	// device attached to the VM to snapshotPath, while the VM runs.
+	SnapshotBlockDevice(ctx context.Context, drive *config.BlockDrive, snapshotPath string) error
 }
@@ -0,0 +1,436 @@
+//go:build linux
+
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
+	hv "github.com/kata-containers/kata-containers/src/runtime/pkg/hypervisors"
+	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+// conformancePid is the pid of a VMM pretending to be running. It is the
+// maximum pid_max, no process ever gets it.
+const conformancePid = 1 << 22
+
+// conformanceDriver plugs a Hypervisor implementation into the conformance
+// suite. The VMM binaries of the configuration are fake ones which cannot
+// be executed, the VMM APIs are served by fake backends.
+type conformanceDriver struct {
+	name string
+	// new returns a hypervisor which is not created yet.
+	new func(id string) Hypervisor
+	// config returns the configuration to create the hypervisor with.
+	config func() HypervisorConfig
+	// run sets the state of h as if its VMM was running.
+	run func(t *testing.T, h Hypervisor)
+	// backend starts a fake VMM failing every request behind the API
+	// socket of h. It is nil when the driver does not talk to the VMM
+	// to hotplug devices.
+	backend func(t *testing.T, h Hypervisor) *fakeVMM
+	// firstDrive is the index of the first drive which can be hotplugged.
+	firstDrive int
+	// startFails is false when StartVM does not launch any VMM.
+	startFails bool
+}
+
+var conformanceDrivers = []conformanceDriver{
+	{
+		name: "qemu",
+		new:  func(id string) Hypervisor { return &qemu{} },
+		config: func() HypervisorConfig {
+			return newQemuConfig()
+		},
+		run: func(t *testing.T, h Hypervisor) {
+			q := h.(*qemu)
+			assert.NoError(t, os.MkdirAll(filepath.Dir(q.qemuConfig.PidFile), DirMode))
+			assert.NoError(t, os.WriteFile(q.qemuConfig.PidFile, []byte(strconv.Itoa(conformancePid)), 0600))
+			q.state.HotpluggedMemory = 256
+			q.state.HotpluggedVCPUs = []hv.CPUDevice{{ID: "cpu-1"}}
+		},
+		backend: func(t *testing.T, h Hypervisor) *fakeVMM {
+			return startFakeQMP(t, h.(*qemu).qmpMonitorCh.path)
+		},
+		startFails: true,
+	},
+	{
+		name: "clh",
+		new:  func(id string) Hypervisor { return &cloudHypervisor{} },
+		config: func() HypervisorConfig {
+			conf, _ := newClhConfig()
+			return conf
+		},
+		run: func(t *testing.T, h Hypervisor) {
+			h.(*cloudHypervisor).state.PID = conformancePid
+		},
+		backend: func(t *testing.T, h Hypervisor) *fakeVMM {
+			return startFakeHTTPVMM(t, h.(*cloudHypervisor).state.apiSocket)
+		},
+		startFails: true,
+	},
+	{
+		name: "fc",
+		new:  func(id string) Hypervisor { return &firecracker{} },
+		config: func() HypervisorConfig {
+			return newFcConfig()
+		},
+		run: func(t *testing.T, h Hypervisor) {
+			h.(*firecracker).info.PID = conformancePid
+		},
+		backend: func(t *testing.T, h Hypervisor) *fakeVMM {
+			// The drives are jailed with bind mounts.
+			if tc.NotValid(ktu.NeedRoot()) {
+				t.Skip(testDisabledAsNonRoot)
+			}
+
+			fc := h.(*firecracker)
+
+			// The drives of the pool are created when the VM starts.
+			assert.NoError(t, os.MkdirAll(fc.jailerRoot, DirMode))
+			_, err := fc.createJailedDrive(fcDriveIndexToID(0))
+			assert.NoError(t, err)
+
+			return startFakeHTTPVMM(t, fc.socketPath)
+		},
+		startFails: true,
+	},
+	{
+		name: "acrn",
+		new: func(id string) Hypervisor {
+			store, _ := persist.GetDriver()
+			return &Acrn{
+				store: store,
+				sandbox: &Sandbox{
+					ctx:   context.Background(),
+					id:    id,
+					state: types.SandboxState{BlockIndexMap: make(map[int]struct{})},
+				},
+			}
+		},
+		config: func() HypervisorConfig {
+			return newAcrnConfig()
+		},
+		run: func(t *testing.T, h Hypervisor) {
+			h.(*Acrn).state.PID = conformancePid
+		},
+		backend: func(t *testing.T, h Hypervisor) *fakeVMM {
+			a := h.(*Acrn)
+			a.acrnConfig.CtlPath = filepath.Join(t.TempDir(), "acrnctl")
+
+			return writeFakeAcrnctl(t, a.acrnConfig.CtlPath, 1)
+		},
+		// Index 0 is the VM rootfs.
+		firstDrive: 1,
+		startFails: true,
+	},
+	{
+		name: "mock",
+		new:  func(id string) Hypervisor { return &mockHypervisor{} },
+		config: func() HypervisorConfig {
+			return newQemuConfig()
+		},
+		run: func(t *testing.T, h Hypervisor) {},
+	},
+}
+
+// fakeVMM is a fake VMM backend listening on a unix socket. It records the
+// requests it gets and fails all of them.
+type fakeVMM struct {
+	sync.Mutex
+	received []string
+	// log is the file a fake VMM binary records its command lines to.
+	log string
+}
+
+func (f *fakeVMM) record(request string) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.received = append(f.received, request)
+}
+
+func (f *fakeVMM) requests() []string {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.log != "" {
+		out, err := os.ReadFile(f.log)
+		if err != nil {
+			return nil
+		}
+		return strings.Split(strings.TrimSpace(string(out)), "\n")
+	}
+
+	return append([]string{}, f.received...)
+}
+
+func listenFakeVMM(t *testing.T, socket string) net.Listener {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(socket), DirMode))
+
+	l, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	return l
+}
+
+// startFakeQMP starts a fake QEMU monitor. The capabilities negotiation
+// succeeds, any other command fails.
+func startFakeQMP(t *testing.T, socket string) *fakeVMM {
+	return startFakeQMPWithReply(t, socket, func(w io.Writer, command string, args map[string]interface{}) {
+		fmt.Fprintf(w, `{"error": {"class": "GenericError", "desc": "fake QMP: %s failed"}}`+"\n", command)
+	})
+}
+
+// startFakeQMPWithReply starts a fake QEMU monitor. The capabilities
+// negotiation succeeds, the replies to the other commands, and the events
+// they cause, are written by reply.
+func startFakeQMPWithReply(t *testing.T, socket string, reply func(w io.Writer, command string, args map[string]interface{})) *fakeVMM {
+	f := &fakeVMM{}
+	l := listenFakeVMM(t, socket)
+
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+
+		fmt.Fprintln(conn, `{"QMP": {"version": {"qemu": {"micro": 0, "minor": 2, "major": 7}, "package": ""}, "capabilities": []}}`)
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var cmd struct {
+				Execute   string                 `json:"execute"`
+				Arguments map[string]interface{} `json:"arguments"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &cmd); err != nil {
+				return
+			}
+
+			if cmd.Execute == "qmp_capabilities" {
+				fmt.Fprintln(conn, `{"return": {}}`)
+				continue
+			}
+
+			f.record(cmd.Execute)
+			reply(conn, cmd.Execute, cmd.Arguments)
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+
+	return f
+}
+
+// startFakeHTTPVMM starts a fake VMM HTTP API, as served by Cloud
+// Hypervisor and Firecracker, answering all the requests with a client
+// error.
+func startFakeHTTPVMM(t *testing.T, socket string) *fakeVMM {
+	f := &fakeVMM{}
+	l := listenFakeVMM(t, socket)
+
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f.record(r.Method + " " + r.URL.Path)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"fault_message": "fake VMM: %s %s failed"}`, r.Method, r.URL.Path)
+		}),
+	}
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+
+	return f
+}
+
+func conformanceConfig(t *testing.T, d conformanceDriver, id string) HypervisorConfig {
+	store, err := persist.GetDriver()
+	assert.NoError(t, err)
+
+	conf := d.config()
+
+	// The fake assets are empty files, the VMM binaries cannot be run.
+	for _, path := range []string{conf.KernelPath, conf.ImagePath, conf.InitrdPath, conf.HypervisorPath, conf.HypervisorCtlPath} {
+		if path == "" {
+			continue
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0600)
+		assert.NoError(t, err)
+		f.Close()
+	}
+
+	conf.VMStorePath = store.RunVMStoragePath()
+	conf.RunStorePath = store.RunStoragePath()
+	conf.DisableSeLinux = true
+
+	// The sandbox creates its directory before the hypervisor.
+	dir := filepath.Join(conf.RunStorePath, id)
+	assert.NoError(t, os.MkdirAll(dir, DirMode))
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+		os.RemoveAll(filepath.Join(conf.VMStorePath, id))
+	})
+
+	return conf
+}
+
+// createConformanceHypervisor returns a created hypervisor of the driver.
+// When state is not nil, it is loaded before the creation, as when a
+// sandbox is restored.
+func createConformanceHypervisor(t *testing.T, d conformanceDriver, id string, conf HypervisorConfig, state *hv.HypervisorState) Hypervisor {
+	ctx := context.Background()
+
+	h := d.new(id)
+	if state != nil {
+		h.Load(*state)
+	}
+
+	network, err := NewNetwork()
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.CreateVM(ctx, id, network, &conf))
+	t.Cleanup(func() { h.Cleanup(ctx) })
+
+	return h
+}
+
+func testConformanceStopAfterFailedStart(t *testing.T, d conformanceDriver, id string) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	h := createConformanceHypervisor(t, d, id, conformanceConfig(t, d, id), nil)
+
+	err := h.StartVM(ctx, 1)
+	if d.startFails {
+		assert.Error(err)
+	}
+
+	assert.NoError(h.StopVM(ctx, false))
+	assert.NoError(h.StopVM(ctx, false))
+}
+
+func testConformanceCleanup(t *testing.T, d conformanceDriver, id string) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	h := createConformanceHypervisor(t, d, id, conformanceConfig(t, d, id), nil)
+
+	assert.NoError(h.Cleanup(ctx))
+	assert.NoError(h.Cleanup(ctx))
+}
+
+func testConformanceSaveLoad(t *testing.T, d conformanceDriver, id string) {
+	conf := conformanceConfig(t, d, id)
+
+	h := createConformanceHypervisor(t, d, id, conf, nil)
+	d.run(t, h)
+
+	saved := h.Save()
+	restored := createConformanceHypervisor(t, d, id, conf, &saved)
+
+	assert.Equal(t, saved, restored.Save())
+}
+
+func testConformanceHotplugUnsupportedDevice(t *testing.T, d conformanceDriver, id string) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	h := createConformanceHypervisor(t, d, id, conformanceConfig(t, d, id), nil)
+
+	_, err := h.HotplugAddDevice(ctx, &MemoryDevice{SizeMB: 128}, FsDev)
+	assert.Error(err)
+
+	_, err = h.HotplugRemoveDevice(ctx, &MemoryDevice{SizeMB: 128}, FsDev)
+	assert.Error(err)
+}
+
+func testConformanceHotplugBackendError(t *testing.T, d conformanceDriver, h Hypervisor) {
+	assert := assert.New(t)
+
+	if d.backend == nil {
+		t.Skipf("%s does not hotplug devices through the VMM API", d.name)
+	}
+
+	vmm := d.backend(t, h)
+
+	disk := filepath.Join(t.TempDir(), "disk.img")
+	assert.NoError(os.WriteFile(disk, nil, 0600))
+
+	drive := &config.BlockDrive{
+		File:   disk,
+		Format: "raw",
+		ID:     "conformance-drive",
+		Index:  d.firstDrive,
+	}
+
+	_, err := h.HotplugAddDevice(context.Background(), drive, BlockDev)
+	assert.Error(err)
+	assert.NotEmpty(vmm.requests(), "the VMM was not asked to hotplug the drive")
+}
+
+func TestHypervisorConformance(t *testing.T) {
+	scenarios := []struct {
+		name string
+		run  func(t *testing.T, d conformanceDriver, id string)
+	}{
+		{"StopAfterFailedStart", testConformanceStopAfterFailedStart},
+		{"Cleanup", testConformanceCleanup},
+		{"SaveLoad", testConformanceSaveLoad},
+		{"HotplugUnsupportedDevice", testConformanceHotplugUnsupportedDevice},
+		{"HotplugBackendError", func(t *testing.T, d conformanceDriver, id string) {
+			h := createConformanceHypervisor(t, d, id, conformanceConfig(t, d, id), nil)
+			testConformanceHotplugBackendError(t, d, h)
+		}},
+		{"HotplugBackendErrorAfterRestore", func(t *testing.T, d conformanceDriver, id string) {
+			conf := conformanceConfig(t, d, id)
+
+			h := createConformanceHypervisor(t, d, id, conf, nil)
+			d.run(t, h)
+
+			saved := h.Save()
+			testConformanceHotplugBackendError(t, d, createConformanceHypervisor(t, d, id, conf, &saved))
+		}},
+	}
+
+	for _, d := range conformanceDrivers {
+		for i, s := range scenarios {
+			d := d
+			s := s
+			// Keep the ids short, ACRN limits the VM names to 15
+			// characters.
+			id := fmt.Sprintf("conf-%s-%d", d.name, i)
+
+			t.Run(d.name+"/"+s.name, func(t *testing.T) {
+				s.run(t, d, id)
+			})
+		}
+	}
+}
@@ -6,11 +6,19 @@
 package virtcontainers
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -353,3 +361,90 @@ func TestAssetPath(t *testing.T) {
 		assert.Equal(expected, p, msg)
 	}
 }
+
+func TestHypervisorConfigApplyCustomAssets(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := HypervisorConfig{
+		KernelPath: "/usr/share/kata-containers/vmlinux",
+		ImagePath:  "/usr/share/kata-containers/kata.img",
+	}
+
+	assert.NoError(cfg.AddCustomAssets(map[string]string{
+		annotations.KernelPath: "/opt/kata/vmlinux",
+	}))
+	assert.True(cfg.isCustomAsset(types.KernelAsset))
+	assert.False(cfg.isCustomAsset(types.ImageAsset))
+
+	cfg.ApplyCustomAssets()
+	assert.Equal("/opt/kata/vmlinux", cfg.KernelPath)
+	assert.Equal("/usr/share/kata-containers/kata.img", cfg.ImagePath)
+
+	assert.Error(cfg.AddCustomAssets(map[string]string{
+		annotations.KernelPath: "vmlinux",
+	}))
+}
+
+func TestHypervisorConfigVerifyAssets(t *testing.T) {
+	assert := assert.New(t)
+
+	orgCacheDir := types.AssetVerificationCacheDir
+	defer func() {
+		types.AssetVerificationCacheDir = orgCacheDir
+	}()
+	types.AssetVerificationCacheDir = t.TempDir()
+
+	dir := t.TempDir()
+	cfg := HypervisorConfig{
+		KernelPath: filepath.Join(dir, "vmlinux"),
+		ImagePath:  filepath.Join(dir, "image"),
+	}
+	assert.NoError(os.WriteFile(cfg.KernelPath, []byte("kernel"), 0600))
+	assert.NoError(os.WriteFile(cfg.ImagePath, []byte("image"), 0600))
+
+	// nothing is verified without a trust store
+	verifications, err := cfg.VerifyAssets()
+	assert.NoError(err)
+	assert.Empty(verifications)
+	assert.NoError(cfg.verifyAssets())
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(err)
+
+	cfg.AssetTrustStore = filepath.Join(dir, "trust")
+	assert.NoError(os.Mkdir(cfg.AssetTrustStore, 0700))
+	assert.NoError(os.WriteFile(filepath.Join(cfg.AssetTrustStore, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte("kernel")))
+	assert.NoError(os.WriteFile(cfg.KernelPath+types.SignatureSuffix, []byte(sig), 0600))
+
+	// the image is not signed
+	verifications, err = cfg.VerifyAssets()
+	assert.NoError(err)
+	assert.Len(verifications, 2)
+	assert.Error(cfg.verifyAssets())
+
+	for _, v := range verifications {
+		switch v.Type {
+		case types.KernelAsset:
+			assert.NoError(v.Err)
+			assert.Equal("key.pem", v.Key)
+		case types.ImageAsset:
+			assert.Error(v.Err)
+		}
+	}
+
+	sig = base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte("image")))
+	assert.NoError(os.WriteFile(cfg.ImagePath+types.SignatureSuffix, []byte(sig), 0600))
+	assert.NoError(cfg.verifyAssets())
+
+	// the hypervisor uses the verified copies of the assets
+	for path, content := range map[string]string{cfg.KernelPath: "kernel", cfg.ImagePath: "image"} {
+		assert.True(strings.HasPrefix(path, types.AssetVerificationCacheDir), path)
+		data, err := os.ReadFile(path)
+		assert.NoError(err)
+		assert.Equal(content, string(data))
+	}
+}
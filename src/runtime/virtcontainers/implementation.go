@@ -41,3 +41,8 @@ func (impl *VCImpl) CreateSandbox(ctx context.Context, sandboxConfig SandboxConf
 func (impl *VCImpl) CleanupContainer(ctx context.Context, sandboxID, containerID string, force bool) error {
 	return CleanupContainer(ctx, sandboxID, containerID, force)
 }
+
+// RecoverSandbox implements the VC function of the same name.
+func (impl *VCImpl) RecoverSandbox(ctx context.Context, sandboxID string) (VCSandbox, error) {
+	return RecoverSandbox(ctx, sandboxID)
+}
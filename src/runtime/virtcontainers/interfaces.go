@@ -14,6 +14,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/image"
 	pbTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
@@ -26,6 +27,7 @@ type VC interface {
 
 	CreateSandbox(ctx context.Context, sandboxConfig SandboxConfig, hookFunc func(context.Context) error) (VCSandbox, error)
 	CleanupContainer(ctx context.Context, sandboxID, containerID string, force bool) error
+	RecoverSandbox(ctx context.Context, sandboxID string) (VCSandbox, error)
 }
 
 // VCSandbox is the Sandbox interface
@@ -54,8 +56,11 @@ type VCSandbox interface {
 	KillContainer(ctx context.Context, containerID string, signal syscall.Signal, all bool) error
 	StatusContainer(containerID string) (ContainerStatus, error)
 	StatsContainer(ctx context.Context, containerID string) (ContainerStats, error)
+	ListProcesses(ctx context.Context, containerID string) ([]*grpc.GuestProcess, error)
 	PauseContainer(ctx context.Context, containerID string) error
 	ResumeContainer(ctx context.Context, containerID string) error
+	Freeze(ctx context.Context, reclaimMemory bool) error
+	Thaw(ctx context.Context) error
 	EnterContainer(ctx context.Context, containerID string, cmd types.Cmd) (VCContainer, *Process, error)
 	UpdateContainer(ctx context.Context, containerID string, resources specs.LinuxResources) error
 	WaitProcess(ctx context.Context, containerID, processID string) (int32, error)
@@ -72,6 +77,7 @@ type VCSandbox interface {
 	ListRoutes(ctx context.Context) ([]*pbTypes.Route, error)
 
 	GetOOMEvent(ctx context.Context) (string, error)
+	SubscribeEvents() (<-chan Event, func())
 	GetHypervisorPid() (int, error)
 
 	UpdateRuntimeMetrics() error
@@ -80,6 +86,8 @@ type VCSandbox interface {
 
 	GuestVolumeStats(ctx context.Context, volumePath string) ([]byte, error)
 	ResizeGuestVolume(ctx context.Context, volumePath string, size uint64) error
+	UpdateVolumeRateLimiter(ctx context.Context, volumePath string, rateLimiter *config.BlockRateLimiter) error
+	SnapshotVolume(ctx context.Context, volumePath, snapshotPath string) error
 
 	// Image management inside Sandbox
 	image.ImageService
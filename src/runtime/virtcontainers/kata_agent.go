@@ -139,6 +139,7 @@ const (
 	grpcStatsContainerRequest    = "grpc.StatsContainerRequest"
 	grpcPauseContainerRequest    = "grpc.PauseContainerRequest"
 	grpcResumeContainerRequest   = "grpc.ResumeContainerRequest"
+	grpcListProcessesRequest     = "grpc.ListProcessesRequest"
 	grpcPullImageRequest         = "grpc.PullImageRequest"
 	grpcReseedRandomDevRequest   = "grpc.ReseedRandomDevRequest"
 	grpcGuestDetailsRequest      = "grpc.GuestDetailsRequest"
@@ -1103,6 +1104,29 @@ func (k *kataAgent) appendVhostUserBlkDevice(dev ContainerDevice, device api.Dev
 	return kataDevice
 }
 
+func (k *kataAgent) appendVDPADevice(dev ContainerDevice, device api.Device, c *Container) *grpc.Device {
+	d, ok := device.GetDeviceInfo().(*config.VDPADev)
+	if !ok || d == nil {
+		k.Logger().WithField("device", device).Error("malformed vdpa device")
+		return nil
+	}
+
+	// The generic vhost-vdpa device exposes the virtio device of the
+	// vDPA parent. The guest kernel takes care of the network devices,
+	// only the block ones are handed to the agent.
+	if d.Type == config.VDPANet {
+		return nil
+	}
+
+	kataDevice := &grpc.Device{
+		ContainerPath: dev.ContainerPath,
+		Type:          kataBlkDevType,
+		Id:            d.PCIPath.String(),
+	}
+
+	return kataDevice
+}
+
 func (k *kataAgent) appendVfioDevice(dev ContainerDevice, device api.Device, c *Container) *grpc.Device {
 	devList, ok := device.GetDeviceInfo().([]*config.VFIODev)
 	if !ok || devList == nil {
@@ -1160,6 +1184,8 @@ func (k *kataAgent) appendDevices(deviceList []*grpc.Device, c *Container) []*gr
 			kataDevice = k.appendVhostUserBlkDevice(dev, device, c)
 		case config.DeviceVFIO:
 			kataDevice = k.appendVfioDevice(dev, device, c)
+		case config.DeviceVDPA:
+			kataDevice = k.appendVDPADevice(dev, device, c)
 		}
 
 		if kataDevice == nil || kataDevice.Type == "" {
@@ -1311,6 +1337,12 @@ func (k *kataAgent) createContainer(ctx context.Context, sandbox *Sandbox, c *Co
 		return nil, err
 	}
 
+	// The host hooks are gone from the spec, the agent only runs the
+	// guest hooks of the sandbox.
+	if grpcSpec.Hooks, err = c.shareGuestHooks(ctx); err != nil {
+		return nil, err
+	}
+
 	req := &grpc.CreateContainerRequest{
 		ContainerId:  c.id,
 		ExecId:       c.id,
@@ -1562,6 +1594,12 @@ func (k *kataAgent) handleVhostUserBlkVolume(c *Container, m Mount, device api.D
 		vol.Fstype = m.Type
 		vol.Options = m.Options
 	}
+	if m.FSGroup != nil {
+		vol.FsGroup = &grpc.FSGroup{
+			GroupId:           uint32(*m.FSGroup),
+			GroupChangePolicy: getFSGroupChangePolicy(m.FSGroupChangePolicy),
+		}
+	}
 
 	return vol, nil
 }
@@ -1821,6 +1859,24 @@ func (k *kataAgent) statsContainer(ctx context.Context, sandbox *Sandbox, c Cont
 	return containerStats, nil
 }
 
+func (k *kataAgent) listProcesses(ctx context.Context, c *Container) ([]*grpc.GuestProcess, error) {
+	req := &grpc.ListProcessesRequest{
+		ContainerId: c.id,
+	}
+
+	resp, err := k.sendReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	processes, ok := resp.(*grpc.ListProcessesResponse)
+	if !ok {
+		return nil, fmt.Errorf("irregular response process list")
+	}
+
+	return processes.Processes, nil
+}
+
 func (k *kataAgent) connect(ctx context.Context) error {
 	if k.dead {
 		return errors.New("Dead agent")
@@ -1993,6 +2049,9 @@ func (k *kataAgent) installReqFunc(c *kataclient.AgentClient) {
 	k.reqHandlers[grpcStatsContainerRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
 		return k.client.AgentServiceClient.StatsContainer(ctx, req.(*grpc.StatsContainerRequest))
 	}
+	k.reqHandlers[grpcListProcessesRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return k.client.AgentServiceClient.ListProcesses(ctx, req.(*grpc.ListProcessesRequest))
+	}
 	k.reqHandlers[grpcPauseContainerRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
 		return k.client.AgentServiceClient.PauseContainer(ctx, req.(*grpc.PauseContainerRequest))
 	}
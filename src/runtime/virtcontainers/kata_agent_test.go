@@ -164,6 +164,9 @@ func TestKataAgentSendReq(t *testing.T) {
 	_, err = k.statsContainer(ctx, sandbox, Container{})
 	assert.Nil(err)
 
+	_, err = k.listProcesses(ctx, container)
+	assert.Nil(err)
+
 	err = k.check(ctx)
 	assert.Nil(err)
 
@@ -571,6 +574,53 @@ func TestAppendVhostUserBlkDevices(t *testing.T) {
 		updatedDevList, expected)
 }
 
+func TestAppendVDPADevices(t *testing.T) {
+	k := kataAgent{}
+
+	id := "test-append-vdpa"
+	ctrDevices := []api.Device{
+		&drivers.VDPADevice{
+			GenericDevice: &drivers.GenericDevice{
+				ID: id,
+			},
+			VDPADev: &config.VDPADev{
+				HostPath: "/dev/vhost-vdpa-0",
+				Type:     config.VDPABlock,
+				PCIPath:  testPCIPath,
+			},
+		},
+	}
+
+	c := &Container{
+		sandbox: &Sandbox{
+			devManager: manager.NewDeviceManager("virtio-blk", false, "", ctrDevices),
+			config:     &SandboxConfig{},
+		},
+	}
+	c.devices = append(c.devices, ContainerDevice{
+		ID:            id,
+		ContainerPath: testBlockDeviceCtrPath,
+	})
+
+	devList := []*pb.Device{}
+	expected := []*pb.Device{
+		{
+			Type:          kataBlkDevType,
+			ContainerPath: testBlockDeviceCtrPath,
+			Id:            testPCIPath.String(),
+		},
+	}
+	updatedDevList := k.appendDevices(devList, c)
+	assert.True(t, reflect.DeepEqual(updatedDevList, expected),
+		"Device lists didn't match: got %+v, expecting %+v",
+		updatedDevList, expected)
+
+	// the network devices are not handed to the agent
+	ctrDevices[0].(*drivers.VDPADevice).VDPADev.Type = config.VDPANet
+	updatedDevList = k.appendDevices([]*pb.Device{}, c)
+	assert.Empty(t, updatedDevList)
+}
+
 func TestConstrainGRPCSpec(t *testing.T) {
 	assert := assert.New(t)
 	expectedCgroupPath := "system.slice:foo:bar"
@@ -132,6 +132,11 @@ func (n *mockAgent) statsContainer(ctx context.Context, sandbox *Sandbox, c Cont
 	return &ContainerStats{}, nil
 }
 
+// listProcesses is the Noop agent process lister. It does nothing.
+func (n *mockAgent) listProcesses(ctx context.Context, c *Container) ([]*grpc.GuestProcess, error) {
+	return []*grpc.GuestProcess{}, nil
+}
+
 // waitProcess is the Noop agent process waiter. It does nothing.
 func (n *mockAgent) waitProcess(ctx context.Context, c *Container, processID string) (int32, error) {
 	return 0, nil
@@ -8,8 +8,10 @@ package virtcontainers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	hv "github.com/kata-containers/kata-containers/src/runtime/pkg/hypervisors"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 )
@@ -24,6 +26,7 @@ type mockHypervisor struct {
 func (m *mockHypervisor) Capabilities(ctx context.Context) types.Capabilities {
 	caps := types.Capabilities{}
 	caps.SetFsSharingSupport()
+	caps.SetBlockDeviceResizeSupport()
 	return caps
 }
 
@@ -79,8 +82,10 @@ func (m *mockHypervisor) HotplugAddDevice(ctx context.Context, devInfo interface
 	case MemoryDev:
 		memdev := devInfo.(*MemoryDevice)
 		return memdev.SizeMB, nil
+	case BlockDev, NetDev, VfioDev, VhostuserDev, VDPADev:
+		return nil, nil
 	}
-	return nil, nil
+	return nil, fmt.Errorf("mockHypervisor: cannot hotplug device type '%v'", devType)
 }
 
 func (m *mockHypervisor) HotplugRemoveDevice(ctx context.Context, devInfo interface{}, devType DeviceType) (interface{}, error) {
@@ -89,8 +94,10 @@ func (m *mockHypervisor) HotplugRemoveDevice(ctx context.Context, devInfo interf
 		return devInfo.(uint32), nil
 	case MemoryDev:
 		return 0, nil
+	case BlockDev, NetDev, VfioDev, VhostuserDev, VDPADev:
+		return nil, nil
 	}
-	return nil, nil
+	return nil, fmt.Errorf("mockHypervisor: cannot hot remove device type '%v'", devType)
 }
 
 func (m *mockHypervisor) GetVMConsole(ctx context.Context, sandboxID string) (string, string, error) {
@@ -159,3 +166,20 @@ func (m *mockHypervisor) GenerateSocket(id string) (interface{}, error) {
 func (m *mockHypervisor) IsRateLimiterBuiltin() bool {
 	return false
 }
+
+func (m *mockHypervisor) UpdateBlockRateLimiter(ctx context.Context, drive *config.BlockDrive, rateLimiter *config.BlockRateLimiter) error {
+	drive.RateLimiter = rateLimiter
+	return nil
+}
+
+func (m *mockHypervisor) ResizeBlockDevice(ctx context.Context, drive *config.BlockDrive, size uint64) error {
+	return nil
+}
+
+func (m *mockHypervisor) ResizeBalloon(ctx context.Context, memMB uint32) error {
+	return nil
+}
+
+func (m *mockHypervisor) SnapshotBlockDevice(ctx context.Context, drive *config.BlockDrive, snapshotPath string) error {
+	return nil
+}
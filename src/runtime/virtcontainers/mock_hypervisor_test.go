@@ -55,6 +55,23 @@ func TestMockHypervisorAddDevice(t *testing.T) {
 	assert.NoError(t, m.AddDevice(context.Background(), nil, ImgDev))
 }
 
+func TestMockHypervisorHotplugDevice(t *testing.T) {
+	var m *mockHypervisor
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	_, err := m.HotplugAddDevice(ctx, nil, BlockDev)
+	assert.NoError(err)
+	_, err = m.HotplugRemoveDevice(ctx, nil, BlockDev)
+	assert.NoError(err)
+
+	// like the real hypervisors, unsupported device types are rejected
+	_, err = m.HotplugAddDevice(ctx, nil, FsDev)
+	assert.Error(err)
+	_, err = m.HotplugRemoveDevice(ctx, nil, FsDev)
+	assert.Error(err)
+}
+
 func TestMockHypervisorGetSandboxConsole(t *testing.T) {
 	var m *mockHypervisor
 
@@ -29,6 +29,9 @@ type monitor struct {
 	checkInterval time.Duration
 	sync.Mutex
 	running bool
+	// frozen is set while the vCPUs of the sandbox are stopped by a
+	// freeze, during which the agent cannot answer the health checks.
+	frozen bool
 }
 
 func newMonitor(s *Sandbox) *monitor {
@@ -136,16 +139,44 @@ func (m *monitor) stop() {
 	}
 }
 
+// setFrozen suspends the agent health checks while the sandbox is frozen,
+// and resumes them once it is thawed.
+func (m *monitor) setFrozen(frozen bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.frozen = frozen
+}
+
+func (m *monitor) isFrozen() bool {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.frozen
+}
+
 func (m *monitor) watchAgent(ctx context.Context) {
+	if m.isFrozen() {
+		return
+	}
+
 	err := m.sandbox.agent.check(ctx)
 	if err != nil {
+		// the sandbox may have been frozen during the check
+		if m.isFrozen() {
+			return
+		}
+
 		// TODO: define and export error types
-		m.notify(ctx, errors.Wrapf(err, "failed to ping agent"))
+		err = errors.Wrapf(err, "failed to ping agent")
+		m.sandbox.publishEvent(EventAgentUnreachable, "", err, nil)
+		m.notify(ctx, err)
 	}
 }
 
 func (m *monitor) watchHypervisor(ctx context.Context) error {
 	if err := m.sandbox.hypervisor.Check(); err != nil {
+		m.sandbox.publishEvent(EventHypervisorCrashed, "", err, nil)
 		m.notify(ctx, errors.Wrapf(err, "failed to ping hypervisor process"))
 		return err
 	}
@@ -8,8 +8,12 @@ package virtcontainers
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,3 +63,68 @@ func TestMonitorClosedChannel(t *testing.T) {
 
 	m.stop()
 }
+
+// frozenAgent fails the health checks while the vCPUs of the VM are
+// stopped, as the agent does once the check request times out.
+type frozenAgent struct {
+	mockAgent
+	paused atomic.Bool
+	dead   atomic.Bool
+}
+
+func (a *frozenAgent) check(ctx context.Context) error {
+	if a.paused.Load() {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+func (a *frozenAgent) markDead(ctx context.Context) {
+	a.dead.Store(true)
+}
+
+func TestMonitorFrozenSandbox(t *testing.T) {
+	contConfig := newTestContainerConfigNoop("505")
+	hConfig := newHypervisorConfig(nil, nil)
+	assert := assert.New(t)
+
+	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, hConfig, NetworkConfig{}, []ContainerConfig{contConfig}, nil)
+	assert.NoError(err)
+	defer cleanUp()
+
+	agent := &frozenAgent{}
+	s.agent = agent
+	s.config.Experimental = []exp.Feature{*exp.Get(exp.SandboxFreeze)}
+	assert.NoError(s.setSandboxState(types.StateRunning))
+
+	checkInterval := 10 * time.Millisecond
+	s.monitor = newMonitor(s)
+	s.monitor.checkInterval = checkInterval
+	ch, err := s.Monitor(context.Background())
+	assert.NoError(err)
+	defer s.monitor.stop()
+
+	assert.NoError(s.Freeze(context.Background(), false))
+	agent.paused.Store(true)
+
+	// Stay frozen for many more checks than it takes the monitor to give
+	// up on the agent.
+	time.Sleep(50 * checkInterval)
+	assert.False(agent.dead.Load(), "frozen sandbox marked dead")
+	assert.Empty(ch)
+
+	agent.paused.Store(false)
+	assert.NoError(s.Thaw(context.Background()))
+	assert.Equal(types.StateRunning, s.state.State)
+	assert.False(agent.dead.Load())
+
+	// The health checks are back once the sandbox is thawed.
+	agent.paused.Store(true)
+	select {
+	case err := <-ch:
+		assert.ErrorIs(err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		assert.Fail("no health check failure after thaw")
+	}
+	assert.True(agent.dead.Load())
+}
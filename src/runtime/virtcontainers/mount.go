@@ -7,8 +7,10 @@ package virtcontainers
 
 import (
 	"context"
+	b64 "encoding/base64"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"syscall"
@@ -206,6 +208,34 @@ func getDeviceForPath(path string) (device, error) {
 	return dev, nil
 }
 
+// imageFileFsTypes are the filesystems of the image files which can be
+// plugged as the block device of a container rootfs.
+var imageFileFsTypes = []string{"erofs", "ext4", "xfs"}
+
+// IsImageFileRootfs returns true if the rootfs of a container is a
+// filesystem image file, which can be plugged in the VM as a block device
+// instead of being loop mounted on the host.
+func IsImageFileRootfs(fsType, source string) bool {
+	supported := false
+	for _, t := range imageFileFsTypes {
+		if t == fsType {
+			supported = true
+			break
+		}
+	}
+
+	if !supported || source == "" {
+		return false
+	}
+
+	fi, err := os.Stat(source)
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode().IsRegular()
+}
+
 var blockFormatTemplate = "/sys/dev/block/%d:%d/dm"
 
 var checkStorageDriver = isDeviceMapper
@@ -586,3 +616,62 @@ func isWatchableMount(path string) bool {
 
 	return false
 }
+
+// nfsVolumesPath returns the host directory where the NFS direct assigned
+// volumes of a sandbox are mounted.
+func nfsVolumesPath(sandboxID string) string {
+	return filepath.Join(getSandboxPath(sandboxID), "nfs")
+}
+
+// nfsVolumeHostPath returns the host mount point of the NFS export of a
+// direct assigned volume of a container.
+func nfsVolumeHostPath(sandboxID, containerID, export string) string {
+	return filepath.Join(nfsVolumesPath(sandboxID), fmt.Sprintf("%s-%s", containerID, b64.RawURLEncoding.EncodeToString([]byte(export))))
+}
+
+// mountNFS mounts a NFS export with the mount helper of the host, which
+// resolves the server address and negotiates the protocol version.
+var mountNFS = func(export, target, fsType string, options []string) error {
+	args := []string{"-t", fsType}
+	if len(options) > 0 {
+		args = append(args, "-o", strings.Join(options, ","))
+	}
+	args = append(args, export, target)
+
+	if out, err := exec.Command("mount", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("mount %s on %s failed: %v: %s", export, target, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// unmountNFSVolume unmounts the NFS export mounted on target and removes
+// the mount point.
+func unmountNFSVolume(target string) error {
+	if err := syscall.Unmount(target, syscall.MNT_DETACH|UmountNoFollow); err != nil && err != syscall.EINVAL && err != syscall.ENOENT {
+		return fmt.Errorf("Could not unmount nfs volume %s: %v", target, err)
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// unmountNFSVolumes unmounts all the NFS direct assigned volumes of a
+// sandbox, so that removing the sandbox directory never reaches into an
+// export.
+func unmountNFSVolumes(sandboxID string) error {
+	entries, err := os.ReadDir(nfsVolumesPath(sandboxID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := unmountNFSVolume(filepath.Join(nfsVolumesPath(sandboxID), entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -223,6 +223,21 @@ func TestIsDeviceMapper(t *testing.T) {
 	assert.True(isDM)
 }
 
+func TestIsImageFileRootfs(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	image := filepath.Join(dir, "rootfs.img")
+	assert.NoError(os.WriteFile(image, []byte("ext4"), 0600))
+
+	assert.True(IsImageFileRootfs("ext4", image))
+	assert.True(IsImageFileRootfs("erofs", image))
+	assert.False(IsImageFileRootfs("overlay", image))
+	assert.False(IsImageFileRootfs("ext4", dir))
+	assert.False(IsImageFileRootfs("ext4", filepath.Join(dir, "missing")))
+	assert.False(IsImageFileRootfs("ext4", ""))
+}
+
 func TestIsDockerVolume(t *testing.T) {
 	assert := assert.New(t)
 	path := "/var/lib/docker/volumes/00da1347c7cf4f15db35f/_data"
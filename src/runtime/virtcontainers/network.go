@@ -123,6 +123,11 @@ const (
 	// NetXConnectNoneModel can be used when the VM is in the host network namespace
 	NetXConnectNoneModel
 
+	// NetXConnectPasstModel connects the network interface to the VM through
+	// passt, a userspace network stack translating the guest traffic to host
+	// sockets. It needs no privileges and is used by rootless sandboxes.
+	NetXConnectPasstModel
+
 	// NetXConnectInvalidModel is the last item to Check valid values by IsValid()
 	NetXConnectInvalidModel
 )
@@ -140,6 +145,8 @@ const (
 	tcFilterNetModelStr = "tcfilter"
 
 	noneNetModelStr = "none"
+
+	passtNetModelStr = "passt"
 )
 
 // GetModel returns the string value of a NetInterworkingModel
@@ -153,6 +160,8 @@ func (n *NetInterworkingModel) GetModel() string {
 		return tcFilterNetModelStr
 	case NetXConnectNoneModel:
 		return noneNetModelStr
+	case NetXConnectPasstModel:
+		return passtNetModelStr
 	}
 	return "unknown"
 }
@@ -172,6 +181,9 @@ func (n *NetInterworkingModel) SetModel(modelName string) error {
 	case noneNetModelStr:
 		*n = NetXConnectNoneModel
 		return nil
+	case passtNetModelStr:
+		*n = NetXConnectPasstModel
+		return nil
 	}
 	return fmt.Errorf("Unknown type %s", modelName)
 }
@@ -193,6 +205,8 @@ type DNSInfo struct {
 type NetworkConfig struct {
 	NetworkID         string
 	InterworkingModel NetInterworkingModel
+	// PasstPath is the passt binary used by the passt interworking model.
+	PasstPath         string
 	NetworkCreated    bool
 	DisableNewNetwork bool
 }
@@ -12,6 +12,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"time"
@@ -39,6 +40,7 @@ type LinuxNetwork struct {
 	eps               []Endpoint
 	interworkingModel NetInterworkingModel
 	netNSCreated      bool
+	passtPath         string
 }
 
 // NewNetwork creates a new Linux Network from a NetworkConfig.
@@ -66,6 +68,7 @@ func NewNetwork(configs ...*NetworkConfig) (Network, error) {
 		[]Endpoint{},
 		config.InterworkingModel,
 		config.NetworkCreated,
+		config.PasstPath,
 	}, nil
 }
 
@@ -92,6 +95,10 @@ func LoadNetwork(netInfo persistapi.NetworkInfo) Network {
 			ep = &TapEndpoint{}
 		case IPVlanEndpointType:
 			ep = &IPVlanEndpoint{}
+		case VDPAEndpointType:
+			ep = &VDPAEndpoint{}
+		case PasstEndpointType:
+			ep = &PasstEndpoint{}
 		default:
 			networkLogger().WithField("endpoint-type", e.Type).Error("unknown endpoint type")
 			continue
@@ -114,6 +121,14 @@ func (n *LinuxNetwork) addSingleEndpoint(ctx context.Context, s *Sandbox, netInf
 	// an appropriate EndPoint based on interface type
 	// This should be a switch
 
+	// Check if interface is backed by a vhost-vdpa device. This takes
+	// precedence over the physical interface check, as the parent of a
+	// vDPA device is usually a PCI VF.
+	vdpaPath, err := vhostVDPAPath(netInfo)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if interface is a physical interface. Do not create
 	// tap interface/bridge if it is.
 	isPhysical, err := isPhysicalIface(netInfo.Iface.Name)
@@ -121,7 +136,10 @@ func (n *LinuxNetwork) addSingleEndpoint(ctx context.Context, s *Sandbox, netInf
 		return nil, err
 	}
 
-	if isPhysical {
+	if vdpaPath != "" {
+		networkLogger().WithField("interface", netInfo.Iface.Name).Info("vDPA network interface found")
+		endpoint, err = createVDPAEndpoint(netInfo, vdpaPath)
+	} else if isPhysical {
 		networkLogger().WithField("interface", netInfo.Iface.Name).Info("Physical network interface found")
 		endpoint, err = createPhysicalEndpoint(netInfo)
 	} else {
@@ -137,6 +155,11 @@ func (n *LinuxNetwork) addSingleEndpoint(ctx context.Context, s *Sandbox, netInf
 		if socketPath != "" {
 			networkLogger().WithField("interface", netInfo.Iface.Name).Info("VhostUser network interface found")
 			endpoint, err = createVhostUserEndpoint(netInfo, socketPath)
+		} else if n.interworkingModel == NetXConnectPasstModel {
+			// passt connects any interface configured in the network
+			// namespace, whatever its type.
+			networkLogger().WithField("interface", netInfo.Iface.Name).Info("passt network interface")
+			endpoint, err = createPasstEndpoint(netInfo, n.passtPath, filepath.Join(s.config.HypervisorConfig.VMStorePath, s.id))
 		} else if netInfo.Iface.Type == "macvlan" {
 			networkLogger().Infof("macvlan interface found")
 			endpoint, err = createMacvlanNetworkEndpoint(idx, netInfo.Iface.Name, n.interworkingModel)
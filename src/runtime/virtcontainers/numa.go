@@ -0,0 +1,352 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cpuset"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// sysNodePath is the sysfs directory describing the host NUMA nodes.
+// It is a variable so that unit tests can point it at a fake tree.
+var sysNodePath = "/sys/devices/system/node"
+
+// runtimeCPUs returns the CPUs the runtime is allowed to run on, in the
+// Linux list format. It is a variable so that unit tests can fake it.
+var runtimeCPUs = func() (string, error) {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &set); err != nil {
+		return "", fmt.Errorf("unable to get the CPU affinity of the runtime: %v", err)
+	}
+
+	builder := cpuset.NewBuilder()
+	for cpu := 0; cpu < len(set)*int(unsafe.Sizeof(set[0]))*8; cpu++ {
+		if set.IsSet(cpu) {
+			builder.Add(cpu)
+		}
+	}
+
+	return builder.Result().String(), nil
+}
+
+// NUMANode describes a guest NUMA node and the host resources backing it.
+type NUMANode struct {
+	// GuestCPUs is the set of guest vCPU indexes assigned to the node,
+	// including the ones that can only be hot-plugged.
+	GuestCPUs cpuset.CPUSet
+
+	// HostCPUs is the subset of the sandbox CPU set located on HostNode.
+	HostCPUs cpuset.CPUSet
+
+	// HostNode is the host NUMA node the guest node memory is bound to.
+	HostNode int
+
+	// MemorySize is the boot memory of the guest node, in MiB.
+	MemorySize uint32
+}
+
+// hostNUMANodes returns the CPUs of each host NUMA node, indexed by node id.
+func hostNUMANodes() (map[int]cpuset.CPUSet, error) {
+	entries, err := os.ReadDir(sysNodePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read host NUMA nodes: %v", err)
+	}
+
+	nodes := make(map[int]cpuset.CPUSet)
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), "node") {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimPrefix(e.Name(), "node"))
+		if err != nil {
+			continue
+		}
+
+		cpuList, err := os.ReadFile(filepath.Join(sysNodePath, e.Name(), "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+
+		cpus, err := cpuset.Parse(strings.TrimSpace(string(cpuList)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse cpulist of host NUMA node %d: %v", id, err)
+		}
+		nodes[id] = cpus
+	}
+
+	return nodes, nil
+}
+
+// splitProportionally splits total into len(weights) parts proportional
+// to weights, handing the rounding leftovers to the largest remainders.
+func splitProportionally(total uint32, weights []int) []uint32 {
+	parts := make([]uint32, len(weights))
+
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+	if sum == 0 {
+		return parts
+	}
+
+	type remainder struct {
+		index int
+		value uint64
+	}
+	remainders := make([]remainder, len(weights))
+
+	var assigned uint32
+	for i, w := range weights {
+		share := uint64(total) * uint64(w)
+		parts[i] = uint32(share / uint64(sum))
+		remainders[i] = remainder{i, share % uint64(sum)}
+		assigned += parts[i]
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value > remainders[j].value
+	})
+	for i := 0; assigned < total; i++ {
+		parts[remainders[i%len(remainders)].index]++
+		assigned++
+	}
+
+	return parts
+}
+
+// newNUMATopology maps the sandbox CPU and memory sets onto guest NUMA nodes.
+// One guest node is created for every host node that holds CPUs of the
+// sandbox CPU set (and, when memset is not empty, whose memory is allowed).
+// The boot vCPUs, the hot-pluggable vCPUs and the boot memory are spread
+// across the guest nodes in proportion to the number of host CPUs each one
+// owns. A nil topology is returned when the CPU set is empty, in which case
+// the guest keeps a flat topology.
+func newNUMATopology(cpus, mems string, numVCPUs, maxVCPUs, memoryMB uint32) ([]NUMANode, error) {
+	cpuSet, err := cpuset.Parse(cpus)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse sandbox CPU set %q: %v", cpus, err)
+	}
+	if cpuSet.IsEmpty() {
+		return nil, nil
+	}
+
+	memSet, err := cpuset.Parse(mems)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse sandbox memory nodes %q: %v", mems, err)
+	}
+
+	hostNodes, err := hostNUMANodes()
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []NUMANode
+	var weights []int
+	for id, hostCPUs := range hostNodes {
+		if !memSet.IsEmpty() && !memSet.Contains(id) {
+			continue
+		}
+
+		sandboxCPUs := hostCPUs.Intersection(cpuSet)
+		if sandboxCPUs.IsEmpty() {
+			continue
+		}
+
+		nodes = append(nodes, NUMANode{
+			HostNode: id,
+			HostCPUs: sandboxCPUs,
+		})
+	}
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no host NUMA node matches CPU set %q and memory nodes %q", cpus, mems)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].HostNode < nodes[j].HostNode
+	})
+	for _, n := range nodes {
+		weights = append(weights, n.HostCPUs.Size())
+	}
+
+	if maxVCPUs < numVCPUs {
+		maxVCPUs = numVCPUs
+	}
+
+	bootCPUs := splitProportionally(numVCPUs, weights)
+	hotplugCPUs := splitProportionally(maxVCPUs-numVCPUs, weights)
+	memory := splitProportionally(memoryMB, weights)
+
+	// Boot vCPUs are numbered first, then the hot-pluggable ones, so that
+	// every guest node gets its share of the vCPUs online at boot time.
+	nextBootCPU := 0
+	nextHotplugCPU := int(numVCPUs)
+	for i := range nodes {
+		builder := cpuset.NewBuilder()
+		for j := uint32(0); j < bootCPUs[i]; j++ {
+			builder.Add(nextBootCPU)
+			nextBootCPU++
+		}
+		for j := uint32(0); j < hotplugCPUs[i]; j++ {
+			builder.Add(nextHotplugCPU)
+			nextHotplugCPU++
+		}
+
+		nodes[i].GuestCPUs = builder.Result()
+		nodes[i].MemorySize = memory[i]
+	}
+
+	return nodes, nil
+}
+
+// assignVCPUs assigns a distinct host CPU of cpus to each vCPU of vcpus,
+// which must not outnumber cpus. The vCPUs of a guest NUMA node get the free
+// CPUs of the host node backing it first, the other vCPUs get the remaining
+// CPUs in order.
+func assignVCPUs(vcpus, cpus []int, nodes []NUMANode) map[int]int {
+	vcpus = append([]int(nil), vcpus...)
+	sort.Ints(vcpus)
+
+	free := make(map[int]bool, len(cpus))
+	for _, cpu := range cpus {
+		free[cpu] = true
+	}
+
+	assignment := make(map[int]int, len(vcpus))
+	take := func(vcpu, cpu int) {
+		assignment[vcpu] = cpu
+		free[cpu] = false
+	}
+
+	for _, n := range nodes {
+		hostCPUs := n.HostCPUs.ToSlice()
+		for _, vcpu := range vcpus {
+			if !n.GuestCPUs.Contains(vcpu) {
+				continue
+			}
+			for _, cpu := range hostCPUs {
+				if free[cpu] {
+					take(vcpu, cpu)
+					break
+				}
+			}
+		}
+	}
+
+	next := 0
+	for _, vcpu := range vcpus {
+		if _, ok := assignment[vcpu]; ok {
+			continue
+		}
+		for next < len(cpus) && !free[cpus[next]] {
+			next++
+		}
+		if next == len(cpus) {
+			break
+		}
+		take(vcpu, cpus[next])
+	}
+
+	return assignment
+}
+
+// setupNUMATopology computes the guest NUMA topology out of the sandbox
+// CPU and memory sets, and stores it in the sandbox hypervisor configuration.
+func (s *Sandbox) setupNUMATopology() error {
+	hconf := &s.config.HypervisorConfig
+
+	if s.factory != nil || hconf.BootToBeTemplate || hconf.BootFromTemplate {
+		s.Logger().Warn("NUMA topology is not supported with VM factory or templating, using a flat topology")
+		return nil
+	}
+
+	cpus, mems, err := s.getSandboxCPUSet()
+	if err != nil {
+		return err
+	}
+
+	// The sandbox CPU set is usually empty when the VM is created, the
+	// CPU set of the pause container not being set: the guest topology
+	// then follows the host nodes of the CPUs the runtime can run on.
+	if cpus == "" {
+		if cpus, err = runtimeCPUs(); err != nil {
+			return err
+		}
+		s.Logger().WithField("cpus", cpus).Info("Empty sandbox CPU set, using the CPUs of the runtime for the guest NUMA topology")
+	}
+
+	nodes, err := newNUMATopology(cpus, mems, hconf.NumVCPUs, hconf.DefaultMaxVCPUs, hconf.MemorySize)
+	if err != nil {
+		return err
+	}
+
+	for i, n := range nodes {
+		s.Logger().WithFields(logrus.Fields{
+			"guest-node":  i,
+			"host-node":   n.HostNode,
+			"guest-cpus":  n.GuestCPUs.String(),
+			"host-cpus":   n.HostCPUs.String(),
+			"memory-size": n.MemorySize,
+		}).Info("Guest NUMA node")
+	}
+
+	hconf.NUMANodes = nodes
+	return nil
+}
+
+// dumpNUMANodes returns the guest NUMA topology to save, the CPU sets being
+// saved in the Linux list format.
+func dumpNUMANodes(nodes []NUMANode) []persistapi.NUMANode {
+	var saved []persistapi.NUMANode
+	for _, n := range nodes {
+		saved = append(saved, persistapi.NUMANode{
+			GuestCPUs:  n.GuestCPUs.String(),
+			HostCPUs:   n.HostCPUs.String(),
+			HostNode:   n.HostNode,
+			MemorySize: n.MemorySize,
+		})
+	}
+
+	return saved
+}
+
+// loadNUMANodes returns the saved guest NUMA topology.
+func loadNUMANodes(saved []persistapi.NUMANode) ([]NUMANode, error) {
+	var nodes []NUMANode
+	for _, n := range saved {
+		guestCPUs, err := cpuset.Parse(n.GuestCPUs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the guest CPUs of NUMA node %d: %v", len(nodes), err)
+		}
+
+		hostCPUs, err := cpuset.Parse(n.HostCPUs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the host CPUs of NUMA node %d: %v", len(nodes), err)
+		}
+
+		nodes = append(nodes, NUMANode{
+			GuestCPUs:  guestCPUs,
+			HostCPUs:   hostCPUs,
+			HostNode:   n.HostNode,
+			MemorySize: n.MemorySize,
+		})
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cpuset"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupFakeNUMANodes(t *testing.T, nodes map[string]string) {
+	dir := t.TempDir()
+	for node, cpuList := range nodes {
+		nodeDir := filepath.Join(dir, node)
+		assert.NoError(t, os.MkdirAll(nodeDir, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(nodeDir, "cpulist"), []byte(cpuList+"\n"), 0644))
+	}
+
+	savedSysNodePath := sysNodePath
+	sysNodePath = dir
+	t.Cleanup(func() {
+		sysNodePath = savedSysNodePath
+	})
+}
+
+func TestSplitProportionally(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]uint32{2, 2}, splitProportionally(4, []int{1, 1}))
+	assert.Equal([]uint32{3, 1}, splitProportionally(4, []int{3, 1}))
+	assert.Equal([]uint32{2, 1, 1}, splitProportionally(4, []int{1, 1, 1}))
+	assert.Equal([]uint32{683, 341}, splitProportionally(1024, []int{2, 1}))
+	assert.Equal([]uint32{0, 0}, splitProportionally(0, []int{1, 1}))
+	assert.Equal([]uint32{0, 0}, splitProportionally(4, []int{0, 0}))
+}
+
+func TestNewNUMATopology(t *testing.T) {
+	assert := assert.New(t)
+
+	setupFakeNUMANodes(t, map[string]string{
+		"node0":  "0-3",
+		"node1":  "4-7",
+		"node2":  "8-11",
+		"online": "0-2",
+	})
+
+	// No CPU set, flat topology
+	nodes, err := newNUMATopology("", "", 2, 4, 2048)
+	assert.NoError(err)
+	assert.Nil(nodes)
+
+	// CPU set spanning two host nodes
+	nodes, err = newNUMATopology("2-5", "", 4, 8, 2048)
+	assert.NoError(err)
+	assert.Len(nodes, 2)
+
+	assert.Equal(0, nodes[0].HostNode)
+	assert.Equal(cpuset.NewCPUSet(2, 3), nodes[0].HostCPUs)
+	assert.Equal(cpuset.NewCPUSet(0, 1, 4, 5), nodes[0].GuestCPUs)
+	assert.Equal(uint32(1024), nodes[0].MemorySize)
+
+	assert.Equal(1, nodes[1].HostNode)
+	assert.Equal(cpuset.NewCPUSet(4, 5), nodes[1].HostCPUs)
+	assert.Equal(cpuset.NewCPUSet(2, 3, 6, 7), nodes[1].GuestCPUs)
+	assert.Equal(uint32(1024), nodes[1].MemorySize)
+
+	// Memory nodes restrict the guest nodes
+	nodes, err = newNUMATopology("0-11", "1-2", 3, 3, 3072)
+	assert.NoError(err)
+	assert.Len(nodes, 2)
+	assert.Equal(1, nodes[0].HostNode)
+	assert.Equal(2, nodes[1].HostNode)
+	assert.Equal(uint32(1536), nodes[1].MemorySize)
+	assert.Equal(3, nodes[0].GuestCPUs.Size()+nodes[1].GuestCPUs.Size())
+
+	// No host node matching
+	_, err = newNUMATopology("0-3", "2", 1, 1, 1024)
+	assert.Error(err)
+
+	// Invalid sets
+	_, err = newNUMATopology("foo", "", 1, 1, 1024)
+	assert.Error(err)
+	_, err = newNUMATopology("0", "bar", 1, 1, 1024)
+	assert.Error(err)
+}
+
+func TestAssignVCPUs(t *testing.T) {
+	assert := assert.New(t)
+
+	nodes := []NUMANode{
+		{HostCPUs: cpuset.NewCPUSet(2, 3), GuestCPUs: cpuset.NewCPUSet(0, 1, 4)},
+		{HostCPUs: cpuset.NewCPUSet(8), GuestCPUs: cpuset.NewCPUSet(2, 3)},
+	}
+
+	// vCPUs 4 and 3 find their host nodes full and get the other CPUs
+	assignment := assignVCPUs([]int{4, 3, 2, 1, 0}, []int{2, 3, 5, 8, 9}, nodes)
+	assert.Equal(map[int]int{0: 2, 1: 3, 2: 8, 3: 5, 4: 9}, assignment)
+
+	// CPUs of the host nodes out of the CPU set are not used
+	assignment = assignVCPUs([]int{0, 1}, []int{3, 4}, nodes)
+	assert.Equal(map[int]int{0: 3, 1: 4}, assignment)
+
+	assignment = assignVCPUs([]int{1, 0}, []int{6, 7}, nil)
+	assert.Equal(map[int]int{0: 6, 1: 7}, assignment)
+}
+
+func TestNUMANodesPersist(t *testing.T) {
+	assert := assert.New(t)
+
+	nodes := []NUMANode{
+		{GuestCPUs: cpuset.NewCPUSet(0, 1, 2), HostCPUs: cpuset.NewCPUSet(0, 1, 4), HostNode: 0, MemorySize: 1024},
+		{GuestCPUs: cpuset.NewCPUSet(3), HostCPUs: cpuset.NewCPUSet(8), HostNode: 1, MemorySize: 1024},
+	}
+
+	saved := dumpNUMANodes(nodes)
+	assert.Equal("0-2", saved[0].GuestCPUs)
+	assert.Equal("0-1,4", saved[0].HostCPUs)
+
+	loaded, err := loadNUMANodes(saved)
+	assert.NoError(err)
+	assert.Len(loaded, 2)
+	for i := range nodes {
+		assert.True(nodes[i].GuestCPUs.Equals(loaded[i].GuestCPUs))
+		assert.True(nodes[i].HostCPUs.Equals(loaded[i].HostCPUs))
+		assert.Equal(nodes[i].HostNode, loaded[i].HostNode)
+		assert.Equal(nodes[i].MemorySize, loaded[i].MemorySize)
+	}
+
+	saved[1].HostCPUs = "foo"
+	_, err = loadNUMANodes(saved)
+	assert.Error(err)
+}
+
+func TestRuntimeCPUs(t *testing.T) {
+	cpus, err := runtimeCPUs()
+	assert.NoError(t, err)
+
+	set, err := cpuset.Parse(cpus)
+	assert.NoError(t, err)
+	assert.False(t, set.IsEmpty())
+}
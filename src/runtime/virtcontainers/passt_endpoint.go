@@ -0,0 +1,356 @@
+//go:build linux
+
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
+	vcTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+	"golang.org/x/sys/unix"
+)
+
+// passtBinary is the passt binary looked up in PATH when none is configured.
+const passtBinary = "passt"
+
+var passtTrace = getNetworkTrace(PasstEndpointType)
+
+var (
+	// passtStartTimeout is how long passt is given to create its socket.
+	passtStartTimeout = 5 * time.Second
+
+	// passtPollInterval is how often the socket of passt is looked for.
+	passtPollInterval = 10 * time.Millisecond
+)
+
+// PasstEndpoint represents a network interface of the sandbox network
+// namespace connected to the VM through passt. passt is a userspace network
+// stack translating the layer 2 traffic of the guest, received on a UNIX
+// socket, to the sockets of the network namespace. It needs no privilege,
+// neither on the host nor in the network namespace, and is used by rootless
+// sandboxes.
+type PasstEndpoint struct {
+	// MAC address of the interface
+	HardAddr  string
+	IfaceName string
+	// SocketPath is the UNIX socket passt listens on for the hypervisor.
+	SocketPath string
+	// PasstPath is the passt binary. passt is looked up in PATH if empty.
+	PasstPath string
+	// PID is the process ID of passt.
+	PID                int
+	EndpointProperties NetworkInfo
+	EndpointType       EndpointType
+	PCIPath            vcTypes.PciPath
+}
+
+// Properties returns the properties of the interface.
+func (endpoint *PasstEndpoint) Properties() NetworkInfo {
+	return endpoint.EndpointProperties
+}
+
+// Name returns name of the interface.
+func (endpoint *PasstEndpoint) Name() string {
+	return endpoint.IfaceName
+}
+
+// HardwareAddr returns the mac address of the network interface.
+func (endpoint *PasstEndpoint) HardwareAddr() string {
+	return endpoint.HardAddr
+}
+
+// Type indentifies the endpoint as a passt endpoint.
+func (endpoint *PasstEndpoint) Type() EndpointType {
+	return endpoint.EndpointType
+}
+
+// SetProperties sets the properties of the endpoint.
+func (endpoint *PasstEndpoint) SetProperties(properties NetworkInfo) {
+	endpoint.EndpointProperties = properties
+}
+
+// PciPath returns the PCI path of the endpoint.
+func (endpoint *PasstEndpoint) PciPath() vcTypes.PciPath {
+	return endpoint.PCIPath
+}
+
+// SetPciPath sets the PCI path of the endpoint.
+func (endpoint *PasstEndpoint) SetPciPath(pciPath vcTypes.PciPath) {
+	endpoint.PCIPath = pciPath
+}
+
+// NetworkPair returns the network pair of the endpoint.
+func (endpoint *PasstEndpoint) NetworkPair() *NetworkInterfacePair {
+	return nil
+}
+
+// netdevID returns the identifier of the endpoint in the hypervisor.
+func (endpoint *PasstEndpoint) netdevID() string {
+	return "passt-" + endpoint.IfaceName
+}
+
+// Attach for passt endpoint starts passt and adds the network device
+// connected to it to the hypervisor.
+func (endpoint *PasstEndpoint) Attach(ctx context.Context, s *Sandbox) error {
+	span, ctx := passtTrace(ctx, "Attach", endpoint)
+	defer span.End()
+
+	if err := endpoint.startPasst(s.hypervisor.HypervisorConfig().UserNamespace); err != nil {
+		networkLogger().WithError(err).Error("Error starting passt")
+		return err
+	}
+
+	if err := s.hypervisor.AddDevice(ctx, endpoint, NetDev); err != nil {
+		endpoint.stopPasst()
+		return err
+	}
+
+	return nil
+}
+
+// Detach for passt endpoint stops passt. Unlike the other endpoints, this
+// is needed even if the network namespace was not created by
+// virtcontainers, passt runs on the host.
+func (endpoint *PasstEndpoint) Detach(ctx context.Context, netNsCreated bool, netNsPath string) error {
+	span, _ := passtTrace(ctx, "Detach", endpoint)
+	defer span.End()
+
+	endpoint.stopPasst()
+
+	return nil
+}
+
+// HotAttach for passt endpoint starts passt and hotplugs the network
+// device connected to it.
+func (endpoint *PasstEndpoint) HotAttach(ctx context.Context, h Hypervisor) error {
+	networkLogger().Info("Hot attaching passt endpoint")
+
+	span, ctx := passtTrace(ctx, "HotAttach", endpoint)
+	defer span.End()
+
+	if err := endpoint.startPasst(h.HypervisorConfig().UserNamespace); err != nil {
+		networkLogger().WithError(err).Error("Error starting passt")
+		return err
+	}
+
+	if _, err := h.HotplugAddDevice(ctx, endpoint, NetDev); err != nil {
+		networkLogger().WithError(err).Error("Error attach passt ep")
+		endpoint.stopPasst()
+		return err
+	}
+	return nil
+}
+
+// HotDetach for passt endpoint hot unplugs the network device and stops
+// passt.
+func (endpoint *PasstEndpoint) HotDetach(ctx context.Context, h Hypervisor, netNsCreated bool, netNsPath string) error {
+	networkLogger().Info("Hot detaching passt endpoint")
+
+	span, ctx := passtTrace(ctx, "HotDetach", endpoint)
+	defer span.End()
+
+	if _, err := h.HotplugRemoveDevice(ctx, endpoint, NetDev); err != nil {
+		networkLogger().WithError(err).Error("Error detach passt ep")
+		return err
+	}
+
+	endpoint.stopPasst()
+
+	return nil
+}
+
+// startPasst starts passt, in a user namespace if userNamespace is set.
+// This must be called in the network namespace of the sandbox, passt
+// translates the traffic of the guest to the sockets of the network
+// namespace it runs in.
+func (endpoint *PasstEndpoint) startPasst(userNamespace bool) error {
+	path := endpoint.PasstPath
+	if path == "" {
+		var err error
+		if path, err = exec.LookPath(passtBinary); err != nil {
+			return fmt.Errorf("passt not found, it is needed by the passt network model: %v", err)
+		}
+	}
+
+	if err := utils.MkdirAllWithInheritedOwner(filepath.Dir(endpoint.SocketPath), DirMode); err != nil {
+		return err
+	}
+
+	// A socket left by a previous passt would be taken as the one of
+	// this passt.
+	if err := os.Remove(endpoint.SocketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	args := []string{
+		// supervised by the runtime
+		"--foreground",
+		"--quiet",
+		"--socket", endpoint.SocketPath,
+		// the interface the addresses and routes are copied from
+		"--interface", endpoint.IfaceName,
+	}
+
+	cmd := exec.Command(path, args...)
+	if userNamespace {
+		cmd.SysProcAttr = rootless.UserNamespaceSysProcAttr()
+	}
+
+	networkLogger().WithField("path", path).WithField("args", strings.Join(args, " ")).Info("Starting passt")
+
+	if err := utils.StartCmd(cmd); err != nil {
+		return err
+	}
+
+	go func() {
+		cmd.Process.Wait()
+		networkLogger().WithField("interface", endpoint.IfaceName).Info("passt quits")
+	}()
+
+	endpoint.PID = cmd.Process.Pid
+
+	// passt creates its socket once it is ready for the hypervisor to
+	// connect.
+	for start := time.Now(); time.Since(start) < passtStartTimeout; time.Sleep(passtPollInterval) {
+		if _, err := os.Stat(endpoint.SocketPath); err == nil {
+			return nil
+		}
+	}
+
+	endpoint.stopPasst()
+
+	return fmt.Errorf("passt did not create its socket %s in %v", endpoint.SocketPath, passtStartTimeout)
+}
+
+// stopPasst stops passt, if it runs.
+func (endpoint *PasstEndpoint) stopPasst() {
+	if endpoint.PID == 0 {
+		return
+	}
+
+	if err := killPasst(endpoint.PID, endpoint.SocketPath); err != nil {
+		networkLogger().WithError(err).WithField("pid", endpoint.PID).Warn("kill passt failed")
+		return
+	}
+	endpoint.PID = 0
+
+	if err := os.Remove(endpoint.SocketPath); err != nil && !os.IsNotExist(err) {
+		networkLogger().WithError(err).WithField("path", endpoint.SocketPath).Warn("removing passt socket failed")
+	}
+}
+
+// killPasst kills the passt process pid, listening on socketPath. The PID
+// is persisted and may have been reused since passt quit: the process is
+// pinned with a pidfd, then checked to be this passt through its command
+// line before being signalled. Its executable is not checked, passt may
+// re-exec itself, e.g., as passt.avx2.
+func killPasst(pid int, socketPath string) error {
+	pidfd, err := unix.PidfdOpen(pid, 0)
+	switch err {
+	case nil:
+		defer unix.Close(pidfd)
+	case unix.ESRCH:
+		return nil
+	case unix.ENOSYS:
+		// Kernels older than 5.3, the check below is racy.
+		pidfd = -1
+	default:
+		return err
+	}
+
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Contains(cmdline, []byte("\x00--socket\x00"+socketPath+"\x00")) {
+		networkLogger().WithField("pid", pid).Warn("Process is not passt any more, not killing it")
+		return nil
+	}
+
+	if pidfd < 0 {
+		err = syscall.Kill(pid, syscall.SIGKILL)
+	} else {
+		err = unix.PidfdSendSignal(pidfd, unix.SIGKILL, nil, 0)
+	}
+	if err == unix.ESRCH {
+		return nil
+	}
+
+	return err
+}
+
+// Create a passt endpoint, passt listening on a socket in socketDir.
+func createPasstEndpoint(netInfo NetworkInfo, passtPath, socketDir string) (*PasstEndpoint, error) {
+	passtEndpoint := &PasstEndpoint{
+		HardAddr:     netInfo.Iface.HardwareAddr.String(),
+		IfaceName:    netInfo.Iface.Name,
+		SocketPath:   filepath.Join(socketDir, fmt.Sprintf("passt-%s.sock", netInfo.Iface.Name)),
+		PasstPath:    passtPath,
+		EndpointType: PasstEndpointType,
+	}
+	return passtEndpoint, nil
+}
+
+func (endpoint *PasstEndpoint) save() persistapi.NetworkEndpoint {
+	return persistapi.NetworkEndpoint{
+		Type: string(endpoint.Type()),
+		Passt: &persistapi.PasstEndpoint{
+			IfaceName:  endpoint.IfaceName,
+			HardAddr:   endpoint.HardAddr,
+			SocketPath: endpoint.SocketPath,
+			PasstPath:  endpoint.PasstPath,
+			PID:        endpoint.PID,
+			PCIPath:    endpoint.PCIPath,
+		},
+	}
+}
+
+func (endpoint *PasstEndpoint) load(s persistapi.NetworkEndpoint) {
+	endpoint.EndpointType = PasstEndpointType
+
+	if s.Passt != nil {
+		endpoint.IfaceName = s.Passt.IfaceName
+		endpoint.HardAddr = s.Passt.HardAddr
+		endpoint.SocketPath = s.Passt.SocketPath
+		endpoint.PasstPath = s.Passt.PasstPath
+		endpoint.PID = s.Passt.PID
+		endpoint.PCIPath = s.Passt.PCIPath
+	}
+}
+
+// unsupported
+func (endpoint *PasstEndpoint) GetRxRateLimiter() bool {
+	return false
+}
+
+func (endpoint *PasstEndpoint) SetRxRateLimiter() error {
+	return fmt.Errorf("rx rate limiter is unsupported for passt endpoint")
+}
+
+// unsupported
+func (endpoint *PasstEndpoint) GetTxRateLimiter() bool {
+	return false
+}
+
+func (endpoint *PasstEndpoint) SetTxRateLimiter() error {
+	return fmt.Errorf("tx rate limiter is unsupported for passt endpoint")
+}
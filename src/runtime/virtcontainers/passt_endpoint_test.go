@@ -0,0 +1,221 @@
+//go:build linux
+
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+)
+
+// fakePasst is a passt creating its socket and running until killed.
+const fakePasst = `#!/bin/sh
+while [ $# -gt 0 ]; do
+	[ "$1" = "--socket" ] && touch "$2"
+	shift
+done
+# like passt, keep the command line the runtime checks before killing it
+while :; do sleep 1; done
+`
+
+// startRealCmd lets passt be started, rather than faked as the test
+// process, until the returned function is called.
+func startRealCmd() func() {
+	savedStartCmd := utils.StartCmd
+	utils.StartCmd = func(c *exec.Cmd) error {
+		return c.Start()
+	}
+	return func() {
+		utils.StartCmd = savedStartCmd
+	}
+}
+
+func TestCreatePasstEndpoint(t *testing.T) {
+	macAddr := net.HardwareAddr{0x02, 0x00, 0xCA, 0xFE, 0x00, 0x48}
+	assert := assert.New(t)
+
+	netinfo := NetworkInfo{
+		Iface: NetlinkIface{
+			LinkAttrs: netlink.LinkAttrs{
+				HardwareAddr: macAddr,
+				Name:         "eth0",
+			},
+			Type: "veth",
+		},
+	}
+
+	expected := &PasstEndpoint{
+		HardAddr:     macAddr.String(),
+		IfaceName:    "eth0",
+		SocketPath:   "/run/vc/vm/sandbox/passt-eth0.sock",
+		PasstPath:    "/usr/bin/passt",
+		EndpointType: PasstEndpointType,
+	}
+
+	result, err := createPasstEndpoint(netinfo, "/usr/bin/passt", "/run/vc/vm/sandbox")
+	assert.NoError(err)
+	assert.Exactly(expected, result)
+	assert.Equal("passt-eth0", result.netdevID())
+}
+
+func TestPasstEndpointAttach(t *testing.T) {
+	assert := assert.New(t)
+	defer startRealCmd()()
+
+	dir := t.TempDir()
+	passtPath := filepath.Join(dir, "passt")
+	assert.NoError(os.WriteFile(passtPath, []byte(fakePasst), 0700))
+
+	p := &PasstEndpoint{
+		HardAddr:     "02:00:ca:fe:00:48",
+		IfaceName:    "eth0",
+		SocketPath:   filepath.Join(dir, "sandbox", "passt-eth0.sock"),
+		PasstPath:    passtPath,
+		EndpointType: PasstEndpointType,
+	}
+
+	s := &Sandbox{
+		hypervisor: &mockHypervisor{},
+	}
+
+	assert.NoError(p.Attach(context.Background(), s))
+	assert.NotZero(p.PID)
+	assert.FileExists(p.SocketPath)
+
+	pid := p.PID
+	assert.NoError(p.Detach(context.Background(), false, ""))
+	assert.Zero(p.PID)
+	assert.NoFileExists(p.SocketPath)
+	assert.Eventually(func() bool {
+		return syscall.Kill(pid, 0) == syscall.ESRCH
+	}, 5*time.Second, 10*time.Millisecond)
+
+	h := &mockHypervisor{}
+	assert.NoError(p.HotAttach(context.Background(), h))
+	assert.NotZero(p.PID)
+	assert.NoError(p.HotDetach(context.Background(), h, false, ""))
+	assert.Zero(p.PID)
+}
+
+func TestPasstEndpointAttachUserNamespace(t *testing.T) {
+	assert := assert.New(t)
+	defer startRealCmd()()
+
+	dir := t.TempDir()
+	passtPath := filepath.Join(dir, "passt")
+	assert.NoError(os.WriteFile(passtPath, []byte(fakePasst), 0700))
+
+	p := &PasstEndpoint{
+		IfaceName:    "eth0",
+		SocketPath:   filepath.Join(dir, "passt-eth0.sock"),
+		PasstPath:    passtPath,
+		EndpointType: PasstEndpointType,
+	}
+
+	s := &Sandbox{
+		hypervisor: &mockHypervisor{
+			config: HypervisorConfig{UserNamespace: true},
+		},
+	}
+
+	if err := p.Attach(context.Background(), s); err != nil {
+		t.Skipf("cannot create a user namespace: %v", err)
+	}
+	defer p.Detach(context.Background(), false, "")
+
+	// passt runs in its own user namespace
+	userNS, err := os.Readlink("/proc/self/ns/user")
+	assert.NoError(err)
+	passtUserNS, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/user", p.PID))
+	assert.NoError(err)
+	assert.NotEqual(userNS, passtUserNS)
+}
+
+func TestPasstEndpointAttachFailure(t *testing.T) {
+	assert := assert.New(t)
+	defer startRealCmd()()
+
+	savedPasstStartTimeout := passtStartTimeout
+	passtStartTimeout = 100 * time.Millisecond
+	defer func() {
+		passtStartTimeout = savedPasstStartTimeout
+	}()
+
+	dir := t.TempDir()
+	passtPath := filepath.Join(dir, "passt")
+	assert.NoError(os.WriteFile(passtPath, []byte("#!/bin/sh\nexec sleep 60\n"), 0700))
+
+	p := &PasstEndpoint{
+		IfaceName:    "eth0",
+		SocketPath:   filepath.Join(dir, "passt-eth0.sock"),
+		PasstPath:    passtPath,
+		EndpointType: PasstEndpointType,
+	}
+
+	// passt never creates its socket
+	err := p.Attach(context.Background(), &Sandbox{hypervisor: &mockHypervisor{}})
+	assert.Error(err)
+	assert.Zero(p.PID)
+
+	p.PasstPath = filepath.Join(dir, "missing")
+	assert.Error(p.Attach(context.Background(), &Sandbox{hypervisor: &mockHypervisor{}}))
+}
+
+func TestPasstEndpointStopReusedPID(t *testing.T) {
+	assert := assert.New(t)
+	defer startRealCmd()()
+
+	// The saved PID now belongs to another process.
+	cmd := exec.Command("sleep", "60")
+	assert.NoError(cmd.Start())
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	p := &PasstEndpoint{
+		IfaceName:    "eth0",
+		SocketPath:   filepath.Join(t.TempDir(), "passt-eth0.sock"),
+		PID:          cmd.Process.Pid,
+		EndpointType: PasstEndpointType,
+	}
+
+	assert.NoError(p.Detach(context.Background(), false, ""))
+	assert.Zero(p.PID)
+	assert.NoError(syscall.Kill(cmd.Process.Pid, 0))
+}
+
+func TestPasstEndpointSaveLoad(t *testing.T) {
+	assert := assert.New(t)
+	p := &PasstEndpoint{
+		HardAddr:     "02:00:ca:fe:00:48",
+		IfaceName:    "eth0",
+		SocketPath:   "/run/vc/vm/sandbox/passt-eth0.sock",
+		PasstPath:    "/usr/bin/passt",
+		PID:          1234,
+		EndpointType: PasstEndpointType,
+		PCIPath:      testPCIPath,
+	}
+
+	saved := p.save()
+	assert.Equal(string(PasstEndpointType), saved.Type)
+
+	loaded := &PasstEndpoint{}
+	loaded.load(saved)
+	assert.Exactly(p, loaded)
+}
@@ -37,6 +37,7 @@ func (s *Sandbox) dumpState(ss *persistapi.SandboxState, cs map[string]persistap
 	ss.State = string(s.state.State)
 	ss.SandboxCgroupPath = s.state.SandboxCgroupPath
 	ss.OverheadCgroupPath = s.state.OverheadCgroupPath
+	ss.FrozenContainers = s.state.FrozenContainers
 
 	for id, cont := range s.containers {
 		state := persistapi.ContainerState{}
@@ -128,6 +129,32 @@ func (s *Sandbox) dumpProcess(cs map[string]persistapi.ContainerState) {
 	}
 }
 
+func (s *Sandbox) dumpGuestHooks(cs map[string]persistapi.ContainerState) {
+	for id, cont := range s.containers {
+		state := cs[id]
+
+		for _, m := range cont.guestHookMounts {
+			state.GuestHookMounts = append(state.GuestHookMounts, persistapi.Mount{
+				Source:      m.Source,
+				Destination: m.Destination,
+				Type:        m.Type,
+				HostPath:    m.HostPath,
+				ReadOnly:    m.ReadOnly,
+			})
+		}
+
+		for _, h := range cont.guestHooks {
+			state.GuestHooks = append(state.GuestHooks, persistapi.GuestHook{
+				Stage:     h.stage,
+				HostPath:  h.hostPath,
+				GuestPath: h.guestPath,
+			})
+		}
+
+		cs[id] = state
+	}
+}
+
 func (s *Sandbox) dumpMounts(cs map[string]persistapi.ContainerState) {
 	for id, cont := range s.containers {
 		state := persistapi.ContainerState{}
@@ -190,6 +217,12 @@ func (s *Sandbox) dumpConfig(ss *persistapi.SandboxState) {
 		SandboxCgroupOnly:   sconfig.SandboxCgroupOnly,
 		DisableGuestSeccomp: sconfig.DisableGuestSeccomp,
 		GuestSeLinuxLabel:   sconfig.GuestSeLinuxLabel,
+		GuestHooks: persistapi.GuestHooksConfig{
+			Hooks:                 sconfig.GuestHooks.Hooks,
+			DeliveryFailurePolicy: string(sconfig.GuestHooks.DeliveryFailurePolicy),
+			Timeout:               sconfig.GuestHooks.Timeout,
+			OCIHooks:              sconfig.GuestHooks.OCIHooks,
+		},
 	}
 
 	ss.Config.SandboxBindMounts = append(ss.Config.SandboxBindMounts, sconfig.SandboxBindMounts...)
@@ -228,6 +261,7 @@ func (s *Sandbox) dumpConfig(ss *persistapi.SandboxState) {
 		EntropySourceList:       sconfig.HypervisorConfig.EntropySourceList,
 		SharedFS:                sconfig.HypervisorConfig.SharedFS,
 		VirtioFSDaemon:          sconfig.HypervisorConfig.VirtioFSDaemon,
+		SwtpmPath:               sconfig.HypervisorConfig.SwtpmPath,
 		VirtioFSDaemonList:      sconfig.HypervisorConfig.VirtioFSDaemonList,
 		VirtioFSCache:           sconfig.HypervisorConfig.VirtioFSCache,
 		VirtioFSExtraArgs:       sconfig.HypervisorConfig.VirtioFSExtraArgs[:],
@@ -245,11 +279,18 @@ func (s *Sandbox) dumpConfig(ss *persistapi.SandboxState) {
 		DisableImageNvdimm:      sconfig.HypervisorConfig.DisableImageNvdimm,
 		HotplugVFIOOnRootBus:    sconfig.HypervisorConfig.HotplugVFIOOnRootBus,
 		PCIeRootPort:            sconfig.HypervisorConfig.PCIeRootPort,
+		FreezeMemory:            sconfig.HypervisorConfig.FreezeMemory,
+		DrivePoolSize:           sconfig.HypervisorConfig.DrivePoolSize,
 		BootToBeTemplate:        sconfig.HypervisorConfig.BootToBeTemplate,
 		BootFromTemplate:        sconfig.HypervisorConfig.BootFromTemplate,
 		DisableVhostNet:         sconfig.HypervisorConfig.DisableVhostNet,
 		EnableVhostUserStore:    sconfig.HypervisorConfig.EnableVhostUserStore,
+		EnableNUMA:              sconfig.HypervisorConfig.EnableNUMA,
+		NUMANodes:               dumpNUMANodes(sconfig.HypervisorConfig.NUMANodes),
+		EnableVTPM:              sconfig.HypervisorConfig.EnableVTPM,
+		EnableJSONSyntax:        sconfig.HypervisorConfig.EnableJSONSyntax,
 		SeccompSandbox:          sconfig.HypervisorConfig.SeccompSandbox,
+		QMPRecordPath:           sconfig.HypervisorConfig.QMPRecordPath,
 		VhostUserStorePath:      sconfig.HypervisorConfig.VhostUserStorePath,
 		VhostUserStorePathList:  sconfig.HypervisorConfig.VhostUserStorePathList,
 		GuestHookPath:           sconfig.HypervisorConfig.GuestHookPath,
@@ -286,6 +327,7 @@ func (s *Sandbox) Save() error {
 	s.dumpDevices(&ss, cs)
 	s.dumpProcess(cs)
 	s.dumpMounts(cs)
+	s.dumpGuestHooks(cs)
 	s.dumpAgent(&ss)
 	s.dumpNetwork(&ss)
 	s.dumpConfig(&ss)
@@ -305,6 +347,7 @@ func (s *Sandbox) loadState(ss persistapi.SandboxState) {
 	s.state.SandboxCgroupPath = ss.SandboxCgroupPath
 	s.state.OverheadCgroupPath = ss.OverheadCgroupPath
 	s.state.GuestMemoryHotplugProbe = ss.GuestMemoryHotplugProbe
+	s.state.FrozenContainers = ss.FrozenContainers
 }
 
 func (c *Container) loadContState(cs persistapi.ContainerState) {
@@ -357,6 +400,28 @@ func (c *Container) loadContMounts(cs persistapi.ContainerState) {
 	}
 }
 
+func (c *Container) loadContGuestHooks(cs persistapi.ContainerState) {
+	c.guestHookMounts = nil
+	for _, m := range cs.GuestHookMounts {
+		c.guestHookMounts = append(c.guestHookMounts, Mount{
+			Source:      m.Source,
+			Destination: m.Destination,
+			Type:        m.Type,
+			HostPath:    m.HostPath,
+			ReadOnly:    m.ReadOnly,
+		})
+	}
+
+	c.guestHooks = nil
+	for _, h := range cs.GuestHooks {
+		c.guestHooks = append(c.guestHooks, guestHook{
+			stage:     h.Stage,
+			hostPath:  h.HostPath,
+			guestPath: h.GuestPath,
+		})
+	}
+}
+
 func (c *Container) loadContProcess(cs persistapi.ContainerState) {
 	c.process = Process{
 		Token:     cs.Process.Token,
@@ -400,6 +465,7 @@ func (c *Container) Restore() error {
 	c.loadContDevices(cs)
 	c.loadContProcess(cs)
 	c.loadContMounts(cs)
+	c.loadContGuestHooks(cs)
 	return nil
 }
 
@@ -431,6 +497,12 @@ func loadSandboxConfig(id string) (*SandboxConfig, error) {
 		SandboxCgroupOnly:   savedConf.SandboxCgroupOnly,
 		DisableGuestSeccomp: savedConf.DisableGuestSeccomp,
 		GuestSeLinuxLabel:   savedConf.GuestSeLinuxLabel,
+		GuestHooks: GuestHooksConfig{
+			Hooks:                 savedConf.GuestHooks.Hooks,
+			DeliveryFailurePolicy: GuestHookDeliveryFailurePolicy(savedConf.GuestHooks.DeliveryFailurePolicy),
+			Timeout:               savedConf.GuestHooks.Timeout,
+			OCIHooks:              savedConf.GuestHooks.OCIHooks,
+		},
 	}
 	sconfig.SandboxBindMounts = append(sconfig.SandboxBindMounts, savedConf.SandboxBindMounts...)
 
@@ -469,6 +541,7 @@ func loadSandboxConfig(id string) (*SandboxConfig, error) {
 		EntropySourceList:       hconf.EntropySourceList,
 		SharedFS:                hconf.SharedFS,
 		VirtioFSDaemon:          hconf.VirtioFSDaemon,
+		SwtpmPath:               hconf.SwtpmPath,
 		VirtioFSDaemonList:      hconf.VirtioFSDaemonList,
 		VirtioFSCache:           hconf.VirtioFSCache,
 		VirtioFSExtraArgs:       hconf.VirtioFSExtraArgs[:],
@@ -486,10 +559,16 @@ func loadSandboxConfig(id string) (*SandboxConfig, error) {
 		DisableImageNvdimm:      hconf.DisableImageNvdimm,
 		HotplugVFIOOnRootBus:    hconf.HotplugVFIOOnRootBus,
 		PCIeRootPort:            hconf.PCIeRootPort,
+		FreezeMemory:            hconf.FreezeMemory,
+		DrivePoolSize:           hconf.DrivePoolSize,
 		BootToBeTemplate:        hconf.BootToBeTemplate,
 		BootFromTemplate:        hconf.BootFromTemplate,
 		DisableVhostNet:         hconf.DisableVhostNet,
 		EnableVhostUserStore:    hconf.EnableVhostUserStore,
+		EnableNUMA:              hconf.EnableNUMA,
+		EnableVTPM:              hconf.EnableVTPM,
+		EnableJSONSyntax:        hconf.EnableJSONSyntax,
+		QMPRecordPath:           hconf.QMPRecordPath,
 		VhostUserStorePath:      hconf.VhostUserStorePath,
 		VhostUserStorePathList:  hconf.VhostUserStorePathList,
 		GuestHookPath:           hconf.GuestHookPath,
@@ -500,6 +579,12 @@ func loadSandboxConfig(id string) (*SandboxConfig, error) {
 		EnableAnnotations:       hconf.EnableAnnotations,
 	}
 
+	numaNodes, err := loadNUMANodes(hconf.NUMANodes)
+	if err != nil {
+		return nil, err
+	}
+	sconfig.HypervisorConfig.NUMANodes = numaNodes
+
 	sconfig.AgentConfig = KataAgentConfig{
 		LongLiveConn: savedConf.KataAgentConfig.LongLiveConn,
 	}
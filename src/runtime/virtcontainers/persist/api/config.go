@@ -70,6 +70,9 @@ type HypervisorConfig struct {
 	// VirtioFSDaemon is the virtio-fs vhost-user daemon path
 	VirtioFSDaemon string
 
+	// SwtpmPath is the path of the swtpm binary backing the vTPM
+	SwtpmPath string
+
 	// VirtioFSCache cache mode for fs version cache or "none"
 	VirtioFSCache string
 
@@ -83,6 +86,9 @@ type HypervisorConfig struct {
 	// SeccompSandbox is the qemu function which enables the seccomp feature
 	SeccompSandbox string
 
+	// QMPRecordPath is the file the QMP sessions with qemu are recorded to
+	QMPRecordPath string
+
 	// GuestHookPath is the path within the VM that will be used for 'drop-in' hooks
 	GuestHookPath string
 
@@ -134,6 +140,15 @@ type HypervisorConfig struct {
 	// The PCIe Root Port device is used to hot-plug the PCIe device
 	PCIeRootPort uint32
 
+	// FreezeMemory is the memory, in MiB, the guest of a sandbox frozen
+	// with memory reclaim is ballooned down to. 0 disables the balloon.
+	FreezeMemory uint32
+
+	// DrivePoolSize is the number of placeholder drives attached to a
+	// Firecracker VM before it boots, for the block devices plugged later.
+	// The pool does not grow once the VM has booted.
+	DrivePoolSize uint32
+
 	// NumVCPUs specifies default number of vCPUs for the VM.
 	NumVCPUs uint32
 
@@ -209,6 +224,21 @@ type HypervisorConfig struct {
 
 	// EnableVhostUserStore is used to indicate if host supports vhost-user-blk/scsi
 	EnableVhostUserStore bool
+
+	// EnableNUMA is used to indicate if the guest NUMA topology should
+	// follow the host NUMA nodes of the sandbox CPU set.
+	EnableNUMA bool
+
+	// NUMANodes is the guest NUMA topology the VM was started with.
+	NUMANodes []NUMANode
+
+	// EnableVTPM is used to indicate if a virtual TPM should be attached
+	// to the guest.
+	EnableVTPM bool
+
+	// EnableJSONSyntax is used to indicate if the QEMU -device, -object
+	// and -blockdev parameters are written in JSON.
+	EnableJSONSyntax bool
 }
 
 // KataAgentConfig is a structure storing information needed
@@ -240,6 +270,15 @@ type ContainerConfig struct {
 	RootFs    string
 }
 
+// GuestHooksConfig is the configuration of the hooks run inside the guest.
+// Refs: virtcontainers/guest_hooks.go:GuestHooksConfig
+type GuestHooksConfig struct {
+	Hooks                 *specs.Hooks `json:",omitempty"`
+	DeliveryFailurePolicy string
+	Timeout               int
+	OCIHooks              bool
+}
+
 // SandboxConfig is a sandbox configuration.
 // Refs: virtcontainers/sandbox.go:SandboxConfig
 type SandboxConfig struct {
@@ -263,6 +302,8 @@ type SandboxConfig struct {
 	// Experimental enables experimental features
 	Experimental []string
 
+	GuestHooks GuestHooksConfig
+
 	// Information for fields not saved:
 	// * Annotation: this is kind of casual data, we don't need casual data in persist file,
 	// if you know this data needs to persist, please gives it a specific field
@@ -289,3 +330,12 @@ type SandboxConfig struct {
 
 	DisableGuestSeccomp bool
 }
+
+// NUMANode describes a guest NUMA node, the CPU sets being saved in the
+// Linux list format.
+type NUMANode struct {
+	GuestCPUs  string
+	HostCPUs   string
+	HostNode   int
+	MemorySize uint32
+}
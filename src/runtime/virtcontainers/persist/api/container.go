@@ -108,4 +108,17 @@ type ContainerState struct {
 
 	// Mounts is mount info from OCI spec
 	Mounts []Mount
+
+	// GuestHookMounts are the guest hooks shared with the guest
+	GuestHookMounts []Mount
+
+	// GuestHooks are the guest hooks delivered to the guest
+	GuestHooks []GuestHook
+}
+
+// GuestHook describes a guest hook delivered to the guest for a container.
+type GuestHook struct {
+	Stage     string
+	HostPath  string
+	GuestPath string
 }
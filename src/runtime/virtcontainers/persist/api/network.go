@@ -79,6 +79,22 @@ type VhostUserEndpoint struct {
 	PCIPath   vcTypes.PciPath
 }
 
+type VDPAEndpoint struct {
+	IfaceName     string
+	HardAddr      string
+	VhostVDPAPath string
+	PCIPath       vcTypes.PciPath
+}
+
+type PasstEndpoint struct {
+	IfaceName  string
+	HardAddr   string
+	SocketPath string
+	PasstPath  string
+	PID        int
+	PCIPath    vcTypes.PciPath
+}
+
 // NetworkEndpoint contains network interface information
 type NetworkEndpoint struct {
 	// One and only one of these below are not nil according to Type.
@@ -90,6 +106,8 @@ type NetworkEndpoint struct {
 	Tap       *TapEndpoint       `json:",omitempty"`
 	IPVlan    *IPVlanEndpoint    `json:",omitempty"`
 	Tuntap    *TuntapEndpoint    `json:",omitempty"`
+	VDPA      *VDPAEndpoint      `json:",omitempty"`
+	Passt     *PasstEndpoint     `json:",omitempty"`
 
 	Type string
 }
@@ -62,4 +62,7 @@ type SandboxState struct {
 
 	// GuestMemoryHotplugProbe determines whether guest kernel supports memory hotplug probe interface
 	GuestMemoryHotplugProbe bool
+
+	// FrozenContainers are the containers paused by the sandbox freeze
+	FrozenContainers []string
 }
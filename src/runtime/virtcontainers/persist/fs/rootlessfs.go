@@ -11,11 +11,9 @@ import (
 	"path/filepath"
 
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
 )
 
-// default xdg runtime directory just in case XDG_RUNTIME_DIR is not set
-var defaultXdgRuntimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
-
 type RootlessFS struct {
 	// inherit from FS. Overwrite if needed.
 	*FS
@@ -34,10 +32,11 @@ func RootlessInit() (persistapi.PersistDriver, error) {
 
 	// XDG_RUNTIME_DIR defines the base directory relative to
 	// which user-specific non-essential runtime files are stored.
-	rootlessDir := os.Getenv("XDG_RUNTIME_DIR")
-	if rootlessDir == "" {
-		rootlessDir = defaultXdgRuntimeDir
-		fsLog.WithField("default-runtime-dir", defaultXdgRuntimeDir).
+	// When it is not set, the default runtime directory of the user on
+	// the host is used, even in a user namespace where the user is root.
+	rootlessDir := rootless.GetRootlessDir()
+	if os.Getenv("XDG_RUNTIME_DIR") == "" {
+		fsLog.WithField("default-runtime-dir", rootlessDir).
 			Warnf("XDG_RUNTIME_DIR variable is not set. Using default runtime directory")
 	}
 
@@ -0,0 +1,28 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package fs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootlessInit(t *testing.T) {
+	assert := assert.New(t)
+
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+
+	driver, err := RootlessInit()
+	assert.NoError(err)
+
+	rootlessFS, ok := driver.(*RootlessFS)
+	assert.True(ok)
+	assert.Equal(filepath.Join(runtimeDir, "run", StoragePathSuffix, sandboxPathSuffix), rootlessFS.RunStoragePath())
+	assert.Equal(filepath.Join(runtimeDir, "run", StoragePathSuffix, vmPathSuffix), rootlessFS.RunVMStoragePath())
+}
@@ -993,6 +993,136 @@ func (m *StatsContainerResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StatsContainerResponse proto.InternalMessageInfo
 
+type ListProcessesRequest struct {
+	ContainerId          string   `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListProcessesRequest) Reset()      { *m = ListProcessesRequest{} }
+func (*ListProcessesRequest) ProtoMessage() {}
+func (*ListProcessesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ce9a559fda969, []int{23}
+}
+func (m *ListProcessesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ListProcessesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ListProcessesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ListProcessesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProcessesRequest.Merge(m, src)
+}
+func (m *ListProcessesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ListProcessesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProcessesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProcessesRequest proto.InternalMessageInfo
+
+type GuestProcess struct {
+	Pid  int32 `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Ppid int32 `protobuf:"varint,2,opt,name=ppid,proto3" json:"ppid,omitempty"`
+	// Empty unless the process was started by ExecProcess.
+	ExecId string   `protobuf:"bytes,3,opt,name=exec_id,json=execId,proto3" json:"exec_id,omitempty"`
+	Args   []string `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
+	Uid    uint32   `protobuf:"varint,5,opt,name=uid,proto3" json:"uid,omitempty"`
+	User   string   `protobuf:"bytes,6,opt,name=user,proto3" json:"user,omitempty"`
+	State  string   `protobuf:"bytes,7,opt,name=state,proto3" json:"state,omitempty"`
+	// User and system CPU time, in nanoseconds.
+	CpuTime uint64 `protobuf:"varint,8,opt,name=cpu_time,json=cpuTime,proto3" json:"cpu_time,omitempty"`
+	// Resident set size, in bytes.
+	Rss uint64 `protobuf:"varint,9,opt,name=rss,proto3" json:"rss,omitempty"`
+	// Start time, in nanoseconds since the guest boot.
+	StartTime            uint64   `protobuf:"varint,10,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GuestProcess) Reset()      { *m = GuestProcess{} }
+func (*GuestProcess) ProtoMessage() {}
+func (*GuestProcess) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ce9a559fda969, []int{24}
+}
+func (m *GuestProcess) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GuestProcess) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GuestProcess.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GuestProcess) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GuestProcess.Merge(m, src)
+}
+func (m *GuestProcess) XXX_Size() int {
+	return m.Size()
+}
+func (m *GuestProcess) XXX_DiscardUnknown() {
+	xxx_messageInfo_GuestProcess.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GuestProcess proto.InternalMessageInfo
+
+type ListProcessesResponse struct {
+	Processes            []*GuestProcess `protobuf:"bytes,1,rep,name=processes,proto3" json:"processes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ListProcessesResponse) Reset()      { *m = ListProcessesResponse{} }
+func (*ListProcessesResponse) ProtoMessage() {}
+func (*ListProcessesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_712ce9a559fda969, []int{25}
+}
+func (m *ListProcessesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ListProcessesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ListProcessesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ListProcessesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProcessesResponse.Merge(m, src)
+}
+func (m *ListProcessesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ListProcessesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProcessesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProcessesResponse proto.InternalMessageInfo
+
 type WriteStreamRequest struct {
 	ContainerId          string   `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
 	ExecId               string   `protobuf:"bytes,2,opt,name=exec_id,json=execId,proto3" json:"exec_id,omitempty"`
@@ -1005,7 +1135,7 @@ type WriteStreamRequest struct {
 func (m *WriteStreamRequest) Reset()      { *m = WriteStreamRequest{} }
 func (*WriteStreamRequest) ProtoMessage() {}
 func (*WriteStreamRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{23}
+	return fileDescriptor_712ce9a559fda969, []int{26}
 }
 func (m *WriteStreamRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1044,7 +1174,7 @@ type WriteStreamResponse struct {
 func (m *WriteStreamResponse) Reset()      { *m = WriteStreamResponse{} }
 func (*WriteStreamResponse) ProtoMessage() {}
 func (*WriteStreamResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{24}
+	return fileDescriptor_712ce9a559fda969, []int{27}
 }
 func (m *WriteStreamResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1085,7 +1215,7 @@ type ReadStreamRequest struct {
 func (m *ReadStreamRequest) Reset()      { *m = ReadStreamRequest{} }
 func (*ReadStreamRequest) ProtoMessage() {}
 func (*ReadStreamRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{25}
+	return fileDescriptor_712ce9a559fda969, []int{28}
 }
 func (m *ReadStreamRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1124,7 +1254,7 @@ type ReadStreamResponse struct {
 func (m *ReadStreamResponse) Reset()      { *m = ReadStreamResponse{} }
 func (*ReadStreamResponse) ProtoMessage() {}
 func (*ReadStreamResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{26}
+	return fileDescriptor_712ce9a559fda969, []int{29}
 }
 func (m *ReadStreamResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1164,7 +1294,7 @@ type CloseStdinRequest struct {
 func (m *CloseStdinRequest) Reset()      { *m = CloseStdinRequest{} }
 func (*CloseStdinRequest) ProtoMessage() {}
 func (*CloseStdinRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{27}
+	return fileDescriptor_712ce9a559fda969, []int{30}
 }
 func (m *CloseStdinRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1206,7 +1336,7 @@ type TtyWinResizeRequest struct {
 func (m *TtyWinResizeRequest) Reset()      { *m = TtyWinResizeRequest{} }
 func (*TtyWinResizeRequest) ProtoMessage() {}
 func (*TtyWinResizeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{28}
+	return fileDescriptor_712ce9a559fda969, []int{31}
 }
 func (m *TtyWinResizeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1249,7 +1379,7 @@ type KernelModule struct {
 func (m *KernelModule) Reset()      { *m = KernelModule{} }
 func (*KernelModule) ProtoMessage() {}
 func (*KernelModule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{29}
+	return fileDescriptor_712ce9a559fda969, []int{32}
 }
 func (m *KernelModule) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1304,7 +1434,7 @@ type CreateSandboxRequest struct {
 func (m *CreateSandboxRequest) Reset()      { *m = CreateSandboxRequest{} }
 func (*CreateSandboxRequest) ProtoMessage() {}
 func (*CreateSandboxRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{30}
+	return fileDescriptor_712ce9a559fda969, []int{33}
 }
 func (m *CreateSandboxRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1342,7 +1472,7 @@ type DestroySandboxRequest struct {
 func (m *DestroySandboxRequest) Reset()      { *m = DestroySandboxRequest{} }
 func (*DestroySandboxRequest) ProtoMessage() {}
 func (*DestroySandboxRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{31}
+	return fileDescriptor_712ce9a559fda969, []int{34}
 }
 func (m *DestroySandboxRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1381,7 +1511,7 @@ type Interfaces struct {
 func (m *Interfaces) Reset()      { *m = Interfaces{} }
 func (*Interfaces) ProtoMessage() {}
 func (*Interfaces) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{32}
+	return fileDescriptor_712ce9a559fda969, []int{35}
 }
 func (m *Interfaces) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1420,7 +1550,7 @@ type Routes struct {
 func (m *Routes) Reset()      { *m = Routes{} }
 func (*Routes) ProtoMessage() {}
 func (*Routes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{33}
+	return fileDescriptor_712ce9a559fda969, []int{36}
 }
 func (m *Routes) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1459,7 +1589,7 @@ type UpdateInterfaceRequest struct {
 func (m *UpdateInterfaceRequest) Reset()      { *m = UpdateInterfaceRequest{} }
 func (*UpdateInterfaceRequest) ProtoMessage() {}
 func (*UpdateInterfaceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{34}
+	return fileDescriptor_712ce9a559fda969, []int{37}
 }
 func (m *UpdateInterfaceRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1498,7 +1628,7 @@ type UpdateRoutesRequest struct {
 func (m *UpdateRoutesRequest) Reset()      { *m = UpdateRoutesRequest{} }
 func (*UpdateRoutesRequest) ProtoMessage() {}
 func (*UpdateRoutesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{35}
+	return fileDescriptor_712ce9a559fda969, []int{38}
 }
 func (m *UpdateRoutesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1536,7 +1666,7 @@ type ListInterfacesRequest struct {
 func (m *ListInterfacesRequest) Reset()      { *m = ListInterfacesRequest{} }
 func (*ListInterfacesRequest) ProtoMessage() {}
 func (*ListInterfacesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{36}
+	return fileDescriptor_712ce9a559fda969, []int{39}
 }
 func (m *ListInterfacesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1574,7 +1704,7 @@ type ListRoutesRequest struct {
 func (m *ListRoutesRequest) Reset()      { *m = ListRoutesRequest{} }
 func (*ListRoutesRequest) ProtoMessage() {}
 func (*ListRoutesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{37}
+	return fileDescriptor_712ce9a559fda969, []int{40}
 }
 func (m *ListRoutesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1613,7 +1743,7 @@ type ARPNeighbors struct {
 func (m *ARPNeighbors) Reset()      { *m = ARPNeighbors{} }
 func (*ARPNeighbors) ProtoMessage() {}
 func (*ARPNeighbors) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{38}
+	return fileDescriptor_712ce9a559fda969, []int{41}
 }
 func (m *ARPNeighbors) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1652,7 +1782,7 @@ type AddARPNeighborsRequest struct {
 func (m *AddARPNeighborsRequest) Reset()      { *m = AddARPNeighborsRequest{} }
 func (*AddARPNeighborsRequest) ProtoMessage() {}
 func (*AddARPNeighborsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{39}
+	return fileDescriptor_712ce9a559fda969, []int{42}
 }
 func (m *AddARPNeighborsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1691,7 +1821,7 @@ type GetIPTablesRequest struct {
 func (m *GetIPTablesRequest) Reset()      { *m = GetIPTablesRequest{} }
 func (*GetIPTablesRequest) ProtoMessage() {}
 func (*GetIPTablesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{40}
+	return fileDescriptor_712ce9a559fda969, []int{43}
 }
 func (m *GetIPTablesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1731,7 +1861,7 @@ type GetIPTablesResponse struct {
 func (m *GetIPTablesResponse) Reset()      { *m = GetIPTablesResponse{} }
 func (*GetIPTablesResponse) ProtoMessage() {}
 func (*GetIPTablesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{41}
+	return fileDescriptor_712ce9a559fda969, []int{44}
 }
 func (m *GetIPTablesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1773,7 +1903,7 @@ type SetIPTablesRequest struct {
 func (m *SetIPTablesRequest) Reset()      { *m = SetIPTablesRequest{} }
 func (*SetIPTablesRequest) ProtoMessage() {}
 func (*SetIPTablesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{42}
+	return fileDescriptor_712ce9a559fda969, []int{45}
 }
 func (m *SetIPTablesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1813,7 +1943,7 @@ type SetIPTablesResponse struct {
 func (m *SetIPTablesResponse) Reset()      { *m = SetIPTablesResponse{} }
 func (*SetIPTablesResponse) ProtoMessage() {}
 func (*SetIPTablesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{43}
+	return fileDescriptor_712ce9a559fda969, []int{46}
 }
 func (m *SetIPTablesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1859,7 +1989,7 @@ type OnlineCPUMemRequest struct {
 func (m *OnlineCPUMemRequest) Reset()      { *m = OnlineCPUMemRequest{} }
 func (*OnlineCPUMemRequest) ProtoMessage() {}
 func (*OnlineCPUMemRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{44}
+	return fileDescriptor_712ce9a559fda969, []int{47}
 }
 func (m *OnlineCPUMemRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1899,7 +2029,7 @@ type ReseedRandomDevRequest struct {
 func (m *ReseedRandomDevRequest) Reset()      { *m = ReseedRandomDevRequest{} }
 func (*ReseedRandomDevRequest) ProtoMessage() {}
 func (*ReseedRandomDevRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{45}
+	return fileDescriptor_712ce9a559fda969, []int{48}
 }
 func (m *ReseedRandomDevRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1949,7 +2079,7 @@ type AgentDetails struct {
 func (m *AgentDetails) Reset()      { *m = AgentDetails{} }
 func (*AgentDetails) ProtoMessage() {}
 func (*AgentDetails) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{46}
+	return fileDescriptor_712ce9a559fda969, []int{49}
 }
 func (m *AgentDetails) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1995,7 +2125,7 @@ type GuestDetailsRequest struct {
 func (m *GuestDetailsRequest) Reset()      { *m = GuestDetailsRequest{} }
 func (*GuestDetailsRequest) ProtoMessage() {}
 func (*GuestDetailsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{47}
+	return fileDescriptor_712ce9a559fda969, []int{50}
 }
 func (m *GuestDetailsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2037,7 +2167,7 @@ type GuestDetailsResponse struct {
 func (m *GuestDetailsResponse) Reset()      { *m = GuestDetailsResponse{} }
 func (*GuestDetailsResponse) ProtoMessage() {}
 func (*GuestDetailsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{48}
+	return fileDescriptor_712ce9a559fda969, []int{51}
 }
 func (m *GuestDetailsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2078,7 +2208,7 @@ type MemHotplugByProbeRequest struct {
 func (m *MemHotplugByProbeRequest) Reset()      { *m = MemHotplugByProbeRequest{} }
 func (*MemHotplugByProbeRequest) ProtoMessage() {}
 func (*MemHotplugByProbeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{49}
+	return fileDescriptor_712ce9a559fda969, []int{52}
 }
 func (m *MemHotplugByProbeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2120,7 +2250,7 @@ type SetGuestDateTimeRequest struct {
 func (m *SetGuestDateTimeRequest) Reset()      { *m = SetGuestDateTimeRequest{} }
 func (*SetGuestDateTimeRequest) ProtoMessage() {}
 func (*SetGuestDateTimeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{50}
+	return fileDescriptor_712ce9a559fda969, []int{53}
 }
 func (m *SetGuestDateTimeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2166,7 +2296,7 @@ type FSGroup struct {
 func (m *FSGroup) Reset()      { *m = FSGroup{} }
 func (*FSGroup) ProtoMessage() {}
 func (*FSGroup) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{51}
+	return fileDescriptor_712ce9a559fda969, []int{54}
 }
 func (m *FSGroup) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2234,7 +2364,7 @@ type Storage struct {
 func (m *Storage) Reset()      { *m = Storage{} }
 func (*Storage) ProtoMessage() {}
 func (*Storage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{52}
+	return fileDescriptor_712ce9a559fda969, []int{55}
 }
 func (m *Storage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2305,7 +2435,7 @@ type Device struct {
 func (m *Device) Reset()      { *m = Device{} }
 func (*Device) ProtoMessage() {}
 func (*Device) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{53}
+	return fileDescriptor_712ce9a559fda969, []int{56}
 }
 func (m *Device) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2346,7 +2476,7 @@ type StringUser struct {
 func (m *StringUser) Reset()      { *m = StringUser{} }
 func (*StringUser) ProtoMessage() {}
 func (*StringUser) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{54}
+	return fileDescriptor_712ce9a559fda969, []int{57}
 }
 func (m *StringUser) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2403,7 +2533,7 @@ type CopyFileRequest struct {
 func (m *CopyFileRequest) Reset()      { *m = CopyFileRequest{} }
 func (*CopyFileRequest) ProtoMessage() {}
 func (*CopyFileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{55}
+	return fileDescriptor_712ce9a559fda969, []int{58}
 }
 func (m *CopyFileRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2441,7 +2571,7 @@ type GetOOMEventRequest struct {
 func (m *GetOOMEventRequest) Reset()      { *m = GetOOMEventRequest{} }
 func (*GetOOMEventRequest) ProtoMessage() {}
 func (*GetOOMEventRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{56}
+	return fileDescriptor_712ce9a559fda969, []int{59}
 }
 func (m *GetOOMEventRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2480,7 +2610,7 @@ type OOMEvent struct {
 func (m *OOMEvent) Reset()      { *m = OOMEvent{} }
 func (*OOMEvent) ProtoMessage() {}
 func (*OOMEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{57}
+	return fileDescriptor_712ce9a559fda969, []int{60}
 }
 func (m *OOMEvent) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2519,7 +2649,7 @@ type AddSwapRequest struct {
 func (m *AddSwapRequest) Reset()      { *m = AddSwapRequest{} }
 func (*AddSwapRequest) ProtoMessage() {}
 func (*AddSwapRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{58}
+	return fileDescriptor_712ce9a559fda969, []int{61}
 }
 func (m *AddSwapRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2557,7 +2687,7 @@ type GetMetricsRequest struct {
 func (m *GetMetricsRequest) Reset()      { *m = GetMetricsRequest{} }
 func (*GetMetricsRequest) ProtoMessage() {}
 func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{59}
+	return fileDescriptor_712ce9a559fda969, []int{62}
 }
 func (m *GetMetricsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2596,7 +2726,7 @@ type Metrics struct {
 func (m *Metrics) Reset()      { *m = Metrics{} }
 func (*Metrics) ProtoMessage() {}
 func (*Metrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{60}
+	return fileDescriptor_712ce9a559fda969, []int{63}
 }
 func (m *Metrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2636,7 +2766,7 @@ type VolumeStatsRequest struct {
 func (m *VolumeStatsRequest) Reset()      { *m = VolumeStatsRequest{} }
 func (*VolumeStatsRequest) ProtoMessage() {}
 func (*VolumeStatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{61}
+	return fileDescriptor_712ce9a559fda969, []int{64}
 }
 func (m *VolumeStatsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2677,7 +2807,7 @@ type ResizeVolumeRequest struct {
 func (m *ResizeVolumeRequest) Reset()      { *m = ResizeVolumeRequest{} }
 func (*ResizeVolumeRequest) ProtoMessage() {}
 func (*ResizeVolumeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_712ce9a559fda969, []int{62}
+	return fileDescriptor_712ce9a559fda969, []int{65}
 }
 func (m *ResizeVolumeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2732,6 +2862,9 @@ func init() {
 	proto.RegisterMapType((map[string]*HugetlbStats)(nil), "grpc.CgroupStats.HugetlbStatsEntry")
 	proto.RegisterType((*NetworkStats)(nil), "grpc.NetworkStats")
 	proto.RegisterType((*StatsContainerResponse)(nil), "grpc.StatsContainerResponse")
+	proto.RegisterType((*ListProcessesRequest)(nil), "grpc.ListProcessesRequest")
+	proto.RegisterType((*GuestProcess)(nil), "grpc.GuestProcess")
+	proto.RegisterType((*ListProcessesResponse)(nil), "grpc.ListProcessesResponse")
 	proto.RegisterType((*WriteStreamRequest)(nil), "grpc.WriteStreamRequest")
 	proto.RegisterType((*WriteStreamResponse)(nil), "grpc.WriteStreamResponse")
 	proto.RegisterType((*ReadStreamRequest)(nil), "grpc.ReadStreamRequest")
@@ -2779,208 +2912,217 @@ func init() {
 }
 
 var fileDescriptor_712ce9a559fda969 = []byte{
-	// 3207 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x3a, 0x4b, 0x73, 0x1b, 0xc7,
-	0xd1, 0xc6, 0x83, 0x04, 0xd0, 0x78, 0x11, 0x4b, 0x8a, 0x82, 0x60, 0x9b, 0x96, 0x57, 0xb6, 0x4c,
-	0xd9, 0x9f, 0x48, 0x5b, 0x76, 0x59, 0x7e, 0x94, 0x3f, 0x7d, 0x24, 0x45, 0x93, 0xb4, 0x4d, 0x0b,
-	0xdf, 0x42, 0x8c, 0x53, 0x49, 0x25, 0x5b, 0xcb, 0xdd, 0x21, 0x38, 0x26, 0x76, 0x67, 0x3d, 0x3b,
-	0x0b, 0x91, 0x4e, 0x55, 0x2a, 0xa7, 0xe4, 0x96, 0x63, 0x6e, 0xf9, 0x03, 0xa9, 0xfc, 0x83, 0x5c,
-	0x73, 0x50, 0xe5, 0x94, 0x63, 0x2e, 0x49, 0xc5, 0xfa, 0x09, 0xf9, 0x05, 0xa9, 0x79, 0xed, 0x03,
-	0x0f, 0xda, 0x61, 0xa9, 0x2a, 0x17, 0xd4, 0x76, 0x4f, 0x4f, 0xbf, 0xa6, 0xa7, 0xa7, 0x7b, 0x06,
-	0xd0, 0x1f, 0x62, 0x76, 0x1a, 0x1f, 0x6f, 0xb8, 0xc4, 0xdf, 0x3c, 0x73, 0x98, 0x73, 0xd7, 0x25,
-	0x01, 0x73, 0x70, 0x80, 0x68, 0x34, 0x05, 0x47, 0xd4, 0xdd, 0x1c, 0xe1, 0xe3, 0x68, 0x33, 0xa4,
-	0x84, 0x11, 0x97, 0x8c, 0xd4, 0x57, 0xb4, 0xe9, 0x0c, 0x51, 0xc0, 0x36, 0x04, 0x60, 0x94, 0x87,
-	0x34, 0x74, 0x7b, 0x35, 0xe2, 0x62, 0x89, 0xe8, 0xd5, 0xdc, 0x48, 0x7f, 0xd6, 0xd9, 0x45, 0x88,
-	0x22, 0x05, 0xbc, 0x38, 0x24, 0x64, 0x38, 0x42, 0x92, 0xc7, 0x71, 0x7c, 0xb2, 0x89, 0xfc, 0x90,
-	0x5d, 0xc8, 0x41, 0xf3, 0xf7, 0x45, 0x58, 0xdd, 0xa1, 0xc8, 0x61, 0x68, 0x47, 0x2b, 0x60, 0xa1,
-	0x6f, 0x62, 0x14, 0x31, 0xe3, 0x55, 0x68, 0x24, 0x4a, 0xd9, 0xd8, 0xeb, 0x16, 0x6e, 0x16, 0xd6,
-	0x6b, 0x56, 0x3d, 0xc1, 0x1d, 0x78, 0xc6, 0x75, 0xa8, 0xa0, 0x73, 0xe4, 0xf2, 0xd1, 0xa2, 0x18,
-	0x5d, 0xe4, 0xe0, 0x81, 0x67, 0xbc, 0x03, 0xf5, 0x88, 0x51, 0x1c, 0x0c, 0xed, 0x38, 0x42, 0xb4,
-	0x5b, 0xba, 0x59, 0x58, 0xaf, 0xdf, 0x5b, 0xda, 0xe0, 0x2a, 0x6f, 0x0c, 0xc4, 0xc0, 0x51, 0x84,
-	0xa8, 0x05, 0x51, 0xf2, 0x6d, 0xdc, 0x86, 0x8a, 0x87, 0xc6, 0xd8, 0x45, 0x51, 0xb7, 0x7c, 0xb3,
-	0xb4, 0x5e, 0xbf, 0xd7, 0x90, 0xe4, 0x0f, 0x05, 0xd2, 0xd2, 0x83, 0xc6, 0x1d, 0xa8, 0x46, 0x8c,
-	0x50, 0x67, 0x88, 0xa2, 0xee, 0x82, 0x20, 0x6c, 0x6a, 0xbe, 0x02, 0x6b, 0x25, 0xc3, 0xc6, 0x4b,
-	0x50, 0x7a, 0xb4, 0x73, 0xd0, 0x5d, 0x14, 0xd2, 0x41, 0x51, 0x85, 0xc8, 0xb5, 0x38, 0xda, 0xb8,
-	0x05, 0xcd, 0xc8, 0x09, 0xbc, 0x63, 0x72, 0x6e, 0x87, 0xd8, 0x0b, 0xa2, 0x6e, 0xe5, 0x66, 0x61,
-	0xbd, 0x6a, 0x35, 0x14, 0xb2, 0xcf, 0x71, 0xe6, 0x47, 0x70, 0x6d, 0xc0, 0x1c, 0xca, 0xae, 0xe0,
-	0x1d, 0xf3, 0x08, 0x56, 0x2d, 0xe4, 0x93, 0xf1, 0x95, 0x5c, 0xdb, 0x85, 0x0a, 0xc3, 0x3e, 0x22,
-	0x31, 0x13, 0xae, 0x6d, 0x5a, 0x1a, 0x34, 0xff, 0x58, 0x00, 0x63, 0xf7, 0x1c, 0xb9, 0x7d, 0x4a,
-	0x5c, 0x14, 0x45, 0xff, 0xa5, 0xe5, 0x7a, 0x03, 0x2a, 0xa1, 0x54, 0xa0, 0x5b, 0x16, 0xe4, 0x6a,
-	0x15, 0xb4, 0x56, 0x7a, 0xd4, 0xfc, 0x1a, 0x56, 0x06, 0x78, 0x18, 0x38, 0xa3, 0xe7, 0xa8, 0xef,
-	0x2a, 0x2c, 0x46, 0x82, 0xa7, 0x50, 0xb5, 0x69, 0x29, 0xc8, 0xec, 0x83, 0xf1, 0x95, 0x83, 0xd9,
-	0xf3, 0x93, 0x64, 0xde, 0x85, 0xe5, 0x1c, 0xc7, 0x28, 0x24, 0x41, 0x84, 0x84, 0x02, 0xcc, 0x61,
-	0x71, 0x24, 0x98, 0x2d, 0x58, 0x0a, 0x32, 0x09, 0xac, 0x1e, 0x85, 0xde, 0x15, 0x77, 0xd3, 0x3d,
-	0xa8, 0x51, 0x14, 0x91, 0x98, 0xf2, 0x3d, 0x50, 0x14, 0x4e, 0x5d, 0x91, 0x4e, 0xfd, 0x02, 0x07,
-	0xf1, 0xb9, 0xa5, 0xc7, 0xac, 0x94, 0x4c, 0xc5, 0x27, 0x8b, 0xae, 0x12, 0x9f, 0x1f, 0xc1, 0xb5,
-	0xbe, 0x13, 0x47, 0x57, 0xd1, 0xd5, 0xfc, 0x98, 0xc7, 0x76, 0x14, 0xfb, 0x57, 0x9a, 0xfc, 0x87,
-	0x02, 0x54, 0x77, 0xc2, 0xf8, 0x28, 0x72, 0x86, 0xc8, 0x78, 0x05, 0xea, 0x8c, 0x30, 0x67, 0x64,
-	0xc7, 0x1c, 0x14, 0xe4, 0x65, 0x0b, 0x04, 0x4a, 0x12, 0xbc, 0x0a, 0x8d, 0x10, 0x51, 0x37, 0x8c,
-	0x15, 0x45, 0xf1, 0x66, 0x69, 0xbd, 0x6c, 0xd5, 0x25, 0x4e, 0x92, 0x6c, 0xc0, 0xb2, 0x18, 0xb3,
-	0x71, 0x60, 0x9f, 0x21, 0x1a, 0xa0, 0x91, 0x4f, 0x3c, 0x24, 0x82, 0xa3, 0x6c, 0x75, 0xc4, 0xd0,
-	0x41, 0xf0, 0x79, 0x32, 0x60, 0xbc, 0x09, 0x9d, 0x84, 0x9e, 0x47, 0xbc, 0xa0, 0x2e, 0x0b, 0xea,
-	0xb6, 0xa2, 0x3e, 0x52, 0x68, 0xf3, 0x97, 0xd0, 0x7a, 0x7c, 0x4a, 0x09, 0x63, 0x23, 0x1c, 0x0c,
-	0x1f, 0x3a, 0xcc, 0xe1, 0x5b, 0x33, 0x44, 0x14, 0x13, 0x2f, 0x52, 0xda, 0x6a, 0xd0, 0x78, 0x0b,
-	0x3a, 0x4c, 0xd2, 0x22, 0xcf, 0xd6, 0x34, 0x45, 0x41, 0xb3, 0x94, 0x0c, 0xf4, 0x15, 0xf1, 0xeb,
-	0xd0, 0x4a, 0x89, 0xf9, 0xe6, 0x56, 0xfa, 0x36, 0x13, 0xec, 0x63, 0xec, 0x23, 0x73, 0x2c, 0x7c,
-	0x25, 0x16, 0xd9, 0x78, 0x0b, 0x6a, 0xa9, 0x1f, 0x0a, 0x22, 0x42, 0x5a, 0x32, 0x42, 0xb4, 0x3b,
-	0xad, 0x6a, 0xe2, 0x94, 0x4f, 0xa0, 0xcd, 0x12, 0xc5, 0x6d, 0xcf, 0x61, 0x4e, 0x3e, 0xa8, 0xf2,
-	0x56, 0x59, 0x2d, 0x96, 0x83, 0xcd, 0x8f, 0xa1, 0xd6, 0xc7, 0x5e, 0x24, 0x05, 0x77, 0xa1, 0xe2,
-	0xc6, 0x94, 0xa2, 0x80, 0x69, 0x93, 0x15, 0x68, 0xac, 0xc0, 0xc2, 0x08, 0xfb, 0x98, 0x29, 0x33,
-	0x25, 0x60, 0x12, 0x80, 0x43, 0xe4, 0x13, 0x7a, 0x21, 0x1c, 0xb6, 0x02, 0x0b, 0xd9, 0xc5, 0x95,
-	0x80, 0xf1, 0x22, 0xd4, 0x7c, 0xe7, 0x3c, 0x59, 0x54, 0x3e, 0x52, 0xf5, 0x9d, 0x73, 0xa9, 0x7c,
-	0x17, 0x2a, 0x27, 0x0e, 0x1e, 0xb9, 0x01, 0x53, 0x5e, 0xd1, 0x60, 0x2a, 0xb0, 0x9c, 0x15, 0xf8,
-	0xe7, 0x22, 0xd4, 0xa5, 0x44, 0xa9, 0xf0, 0x0a, 0x2c, 0xb8, 0x8e, 0x7b, 0x9a, 0x88, 0x14, 0x80,
-	0x71, 0x5b, 0x2b, 0x52, 0xcc, 0x66, 0xb8, 0x54, 0x53, 0xad, 0xda, 0x26, 0x40, 0xf4, 0xc4, 0x09,
-	0x95, 0x6e, 0xa5, 0x39, 0xc4, 0x35, 0x4e, 0x23, 0xd5, 0x7d, 0x17, 0x1a, 0x32, 0xee, 0xd4, 0x94,
-	0xf2, 0x9c, 0x29, 0x75, 0x49, 0x25, 0x27, 0xdd, 0x82, 0x66, 0x1c, 0x21, 0xfb, 0x14, 0x23, 0xea,
-	0x50, 0xf7, 0xf4, 0xa2, 0xbb, 0x20, 0x0f, 0xa0, 0x38, 0x42, 0xfb, 0x1a, 0x67, 0xdc, 0x83, 0x05,
-	0x9e, 0x5b, 0xa2, 0xee, 0xa2, 0x38, 0xeb, 0x5e, 0xca, 0xb2, 0x14, 0xa6, 0x6e, 0x88, 0xdf, 0xdd,
-	0x80, 0xd1, 0x0b, 0x4b, 0x92, 0xf6, 0x3e, 0x00, 0x48, 0x91, 0xc6, 0x12, 0x94, 0xce, 0xd0, 0x85,
-	0xda, 0x87, 0xfc, 0x93, 0x3b, 0x67, 0xec, 0x8c, 0x62, 0xed, 0x75, 0x09, 0x7c, 0x54, 0xfc, 0xa0,
-	0x60, 0xba, 0xd0, 0xde, 0x1e, 0x9d, 0x61, 0x92, 0x99, 0xbe, 0x02, 0x0b, 0xbe, 0xf3, 0x35, 0xa1,
-	0xda, 0x93, 0x02, 0x10, 0x58, 0x1c, 0x10, 0xaa, 0x59, 0x08, 0xc0, 0x68, 0x41, 0x91, 0x84, 0xc2,
-	0x5f, 0x35, 0xab, 0x48, 0xc2, 0x54, 0x50, 0x39, 0x23, 0xc8, 0xfc, 0x47, 0x19, 0x20, 0x95, 0x62,
-	0x58, 0xd0, 0xc3, 0xc4, 0x8e, 0x10, 0xe5, 0xe7, 0xbb, 0x7d, 0x7c, 0xc1, 0x50, 0x64, 0x53, 0xe4,
-	0xc6, 0x34, 0xc2, 0x63, 0xbe, 0x7e, 0xdc, 0xec, 0x6b, 0xd2, 0xec, 0x09, 0xdd, 0xac, 0xeb, 0x98,
-	0x0c, 0xe4, 0xbc, 0x6d, 0x3e, 0xcd, 0xd2, 0xb3, 0x8c, 0x03, 0xb8, 0x96, 0xf2, 0xf4, 0x32, 0xec,
-	0x8a, 0x97, 0xb1, 0x5b, 0x4e, 0xd8, 0x79, 0x29, 0xab, 0x5d, 0x58, 0xc6, 0xc4, 0xfe, 0x26, 0x46,
-	0x71, 0x8e, 0x51, 0xe9, 0x32, 0x46, 0x1d, 0x4c, 0xfe, 0x5f, 0x4c, 0x48, 0xd9, 0xf4, 0xe1, 0x46,
-	0xc6, 0x4a, 0xbe, 0xdd, 0x33, 0xcc, 0xca, 0x97, 0x31, 0x5b, 0x4d, 0xb4, 0xe2, 0xf9, 0x20, 0xe5,
-	0xf8, 0x19, 0xac, 0x62, 0x62, 0x3f, 0x71, 0x30, 0x9b, 0x64, 0xb7, 0xf0, 0x3d, 0x46, 0xf2, 0x13,
-	0x2d, 0xcf, 0x4b, 0x1a, 0xe9, 0x23, 0x3a, 0xcc, 0x19, 0xb9, 0xf8, 0x3d, 0x46, 0x1e, 0x8a, 0x09,
-	0x29, 0x9b, 0x2d, 0xe8, 0x60, 0x32, 0xa9, 0x4d, 0xe5, 0x32, 0x26, 0x6d, 0x4c, 0xf2, 0x9a, 0x6c,
-	0x43, 0x27, 0x42, 0x2e, 0x23, 0x34, 0x1b, 0x04, 0xd5, 0xcb, 0x58, 0x2c, 0x29, 0xfa, 0x84, 0x87,
-	0xf9, 0x53, 0x68, 0xec, 0xc7, 0x43, 0xc4, 0x46, 0xc7, 0x49, 0x32, 0x78, 0x6e, 0xf9, 0xc7, 0xfc,
-	0x57, 0x11, 0xea, 0x3b, 0x43, 0x4a, 0xe2, 0x30, 0x97, 0x93, 0xe5, 0x26, 0x9d, 0xcc, 0xc9, 0x82,
-	0x44, 0xe4, 0x64, 0x49, 0xfc, 0x1e, 0x34, 0x7c, 0xb1, 0x75, 0x15, 0xbd, 0xcc, 0x43, 0x9d, 0xa9,
-	0x4d, 0x6d, 0xd5, 0xfd, 0x4c, 0x32, 0xdb, 0x00, 0x08, 0xb1, 0x17, 0xa9, 0x39, 0x32, 0x1d, 0xb5,
-	0x55, 0xb9, 0xa5, 0x53, 0xb4, 0x55, 0x0b, 0x93, 0x6c, 0xfd, 0x0e, 0xd4, 0x8f, 0xb9, 0x93, 0xd4,
-	0x84, 0x5c, 0x32, 0x4a, 0xbd, 0x67, 0xc1, 0x71, 0xba, 0x09, 0xf7, 0xa1, 0x79, 0x2a, 0x5d, 0xa6,
-	0x26, 0xc9, 0x18, 0xba, 0xa5, 0x2c, 0x49, 0xed, 0xdd, 0xc8, 0x7a, 0x56, 0x2e, 0x40, 0xe3, 0x34,
-	0x83, 0xea, 0x0d, 0xa0, 0x33, 0x45, 0x32, 0x23, 0x07, 0xad, 0x67, 0x73, 0x50, 0xfd, 0x9e, 0x21,
-	0x05, 0x65, 0x67, 0x66, 0xf3, 0xd2, 0x6f, 0x8b, 0xd0, 0xf8, 0x12, 0xb1, 0x27, 0x84, 0x9e, 0x49,
-	0x7d, 0x0d, 0x28, 0x07, 0x8e, 0x8f, 0x14, 0x47, 0xf1, 0x6d, 0xdc, 0x80, 0x2a, 0x3d, 0x97, 0x09,
-	0x44, 0xad, 0x67, 0x85, 0x9e, 0x8b, 0xc4, 0x60, 0xbc, 0x0c, 0x40, 0xcf, 0xed, 0xd0, 0x71, 0xcf,
-	0x90, 0xf2, 0x60, 0xd9, 0xaa, 0xd1, 0xf3, 0xbe, 0x44, 0xf0, 0x50, 0xa0, 0xe7, 0x36, 0xa2, 0x94,
-	0xd0, 0x48, 0xe5, 0xaa, 0x2a, 0x3d, 0xdf, 0x15, 0xb0, 0x9a, 0xeb, 0x51, 0x12, 0x86, 0xc8, 0x13,
-	0x39, 0x5a, 0xcc, 0x7d, 0x28, 0x11, 0x5c, 0x2a, 0xd3, 0x52, 0x17, 0xa5, 0x54, 0x96, 0x4a, 0x65,
-	0xa9, 0xd4, 0x8a, 0x9c, 0xc9, 0xb2, 0x52, 0x59, 0x22, 0xb5, 0x2a, 0xa5, 0xb2, 0x8c, 0x54, 0x96,
-	0x4a, 0xad, 0xe9, 0xb9, 0x4a, 0xaa, 0xf9, 0x9b, 0x02, 0xac, 0x4e, 0x16, 0x7e, 0xaa, 0x36, 0x7d,
-	0x0f, 0x1a, 0xae, 0x58, 0xaf, 0x5c, 0x4c, 0x76, 0xa6, 0x56, 0xd2, 0xaa, 0xbb, 0x99, 0x30, 0xbe,
-	0x0f, 0xcd, 0x40, 0x3a, 0x38, 0x09, 0xcd, 0x52, 0xba, 0x2e, 0x59, 0xdf, 0x5b, 0x8d, 0x20, 0x03,
-	0x99, 0x1e, 0x18, 0x5f, 0x51, 0xcc, 0xd0, 0x80, 0x51, 0xe4, 0xf8, 0xcf, 0xa3, 0xba, 0x37, 0xa0,
-	0x2c, 0xaa, 0x15, 0xbe, 0x4c, 0x0d, 0x4b, 0x7c, 0x9b, 0x6f, 0xc0, 0x72, 0x4e, 0x8a, 0xb2, 0x75,
-	0x09, 0x4a, 0x23, 0x14, 0x08, 0xee, 0x4d, 0x8b, 0x7f, 0x9a, 0x0e, 0x74, 0x2c, 0xe4, 0x78, 0xcf,
-	0x4f, 0x1b, 0x25, 0xa2, 0x94, 0x8a, 0x58, 0x07, 0x23, 0x2b, 0x42, 0xa9, 0xa2, 0xb5, 0x2e, 0x64,
-	0xb4, 0x7e, 0x04, 0x9d, 0x9d, 0x11, 0x89, 0xd0, 0x80, 0x79, 0x38, 0x78, 0x1e, 0xed, 0xc8, 0x2f,
-	0x60, 0xf9, 0x31, 0xbb, 0xf8, 0x8a, 0x33, 0x8b, 0xf0, 0xb7, 0xe8, 0x39, 0xd9, 0x47, 0xc9, 0x13,
-	0x6d, 0x1f, 0x25, 0x4f, 0x78, 0x73, 0xe3, 0x92, 0x51, 0xec, 0x07, 0x62, 0x2b, 0x34, 0x2d, 0x05,
-	0x99, 0xdb, 0xd0, 0x90, 0x35, 0xf4, 0x21, 0xf1, 0xe2, 0x11, 0x9a, 0xb9, 0x07, 0xd7, 0x00, 0x42,
-	0x87, 0x3a, 0x3e, 0x62, 0x88, 0xca, 0x18, 0xaa, 0x59, 0x19, 0x8c, 0xf9, 0xbb, 0x22, 0xac, 0xc8,
-	0xfb, 0x86, 0x81, 0x6c, 0xb3, 0xb5, 0x09, 0x3d, 0xa8, 0x9e, 0x92, 0x88, 0x65, 0x18, 0x26, 0x30,
-	0x57, 0x91, 0xf7, 0xe7, 0x92, 0x1b, 0xff, 0xcc, 0x5d, 0x02, 0x94, 0x2e, 0xbf, 0x04, 0x98, 0x6a,
-	0xf3, 0xcb, 0xd3, 0x6d, 0x3e, 0xdf, 0x6d, 0x9a, 0x08, 0xcb, 0x3d, 0x5e, 0xb3, 0x6a, 0x0a, 0x73,
-	0xe0, 0x19, 0xb7, 0xa1, 0x3d, 0xe4, 0x5a, 0xda, 0xa7, 0x84, 0x9c, 0xd9, 0xa1, 0xc3, 0x4e, 0xc5,
-	0x56, 0xaf, 0x59, 0x4d, 0x81, 0xde, 0x27, 0xe4, 0xac, 0xef, 0xb0, 0x53, 0xe3, 0x43, 0x68, 0xa9,
-	0x32, 0xd0, 0x17, 0x2e, 0x8a, 0xd4, 0xe1, 0xa7, 0x76, 0x51, 0xd6, 0x7b, 0x56, 0xf3, 0x2c, 0x03,
-	0x45, 0xe6, 0x75, 0xb8, 0xf6, 0x10, 0x45, 0x8c, 0x92, 0x8b, 0xbc, 0x63, 0xcc, 0xff, 0x05, 0x38,
-	0x08, 0x18, 0xa2, 0x27, 0x8e, 0x8b, 0x22, 0xe3, 0xed, 0x2c, 0xa4, 0x8a, 0xa3, 0xa5, 0x0d, 0x79,
-	0xdd, 0x93, 0x0c, 0x58, 0x19, 0x1a, 0x73, 0x03, 0x16, 0x2d, 0x12, 0xf3, 0x74, 0xf4, 0x9a, 0xfe,
-	0x52, 0xf3, 0x1a, 0x6a, 0x9e, 0x40, 0x5a, 0x6a, 0xcc, 0xdc, 0xd7, 0x2d, 0x6c, 0xca, 0x4e, 0x2d,
-	0xd1, 0x06, 0xd4, 0xb0, 0xc6, 0xa9, 0xac, 0x32, 0x2d, 0x3a, 0x25, 0x31, 0x3f, 0x86, 0x65, 0xc9,
-	0x49, 0x72, 0xd6, 0x6c, 0x5e, 0x83, 0x45, 0xaa, 0xd5, 0x28, 0xa4, 0xf7, 0x3c, 0x8a, 0x48, 0x8d,
-	0x71, 0x7f, 0x7c, 0x81, 0x23, 0x96, 0x1a, 0xa2, 0xfd, 0xb1, 0x0c, 0x1d, 0x3e, 0x90, 0xe3, 0x69,
-	0x7e, 0x0a, 0x8d, 0x2d, 0xab, 0xff, 0x25, 0xc2, 0xc3, 0xd3, 0x63, 0x9e, 0x3d, 0xdf, 0xcf, 0xc3,
-	0xca, 0x60, 0x43, 0x69, 0x9b, 0x19, 0xb2, 0x72, 0x74, 0xe6, 0x67, 0xb0, 0xba, 0xe5, 0x79, 0x59,
-	0x94, 0xd6, 0xfa, 0x6d, 0xa8, 0x05, 0x19, 0x76, 0x99, 0x33, 0x2b, 0x47, 0x9d, 0x12, 0x99, 0x77,
-	0xc1, 0xd8, 0x43, 0xec, 0xa0, 0xff, 0xd8, 0x39, 0x1e, 0xa5, 0xd6, 0x5f, 0x87, 0x0a, 0x8e, 0x6c,
-	0x1c, 0x8e, 0xdf, 0x17, 0x5c, 0xaa, 0xd6, 0x22, 0x8e, 0x0e, 0xc2, 0xf1, 0xfb, 0xe6, 0x1d, 0x58,
-	0xce, 0x91, 0x5f, 0x92, 0x56, 0xb6, 0xc0, 0x18, 0xfc, 0x70, 0xce, 0x09, 0x8b, 0x62, 0x86, 0xc5,
-	0x1d, 0x58, 0x1e, 0xfc, 0x40, 0x69, 0x3f, 0x83, 0xe5, 0x47, 0xc1, 0x08, 0x07, 0x68, 0xa7, 0x7f,
-	0x74, 0x88, 0x92, 0x9c, 0x6a, 0x40, 0x99, 0xd7, 0x9e, 0x4a, 0x96, 0xf8, 0xe6, 0x2a, 0x04, 0xc7,
-	0xb6, 0x1b, 0xc6, 0x91, 0xba, 0xb4, 0x5a, 0x0c, 0x8e, 0x77, 0xc2, 0x38, 0xe2, 0x87, 0x24, 0x2f,
-	0x92, 0x48, 0x30, 0xba, 0x10, 0x99, 0xa6, 0x6a, 0x55, 0xdc, 0x30, 0x7e, 0x14, 0x8c, 0x2e, 0xcc,
-	0xff, 0x11, 0x37, 0x09, 0x08, 0x79, 0x96, 0x13, 0x78, 0xc4, 0x7f, 0x88, 0xc6, 0x19, 0x09, 0x53,
-	0x7a, 0x3f, 0x2d, 0x40, 0x63, 0x6b, 0x88, 0x02, 0xf6, 0x10, 0x31, 0x07, 0x8f, 0x44, 0x67, 0x3a,
-	0x46, 0x34, 0xc2, 0x24, 0x50, 0x69, 0x43, 0x83, 0xc6, 0x2b, 0x50, 0xc7, 0x01, 0x66, 0xb6, 0xe7,
-	0x20, 0x9f, 0x04, 0x82, 0x4b, 0xd5, 0x02, 0x8e, 0x7a, 0x28, 0x30, 0xc6, 0x1b, 0xd0, 0x96, 0x97,
-	0x8a, 0xf6, 0xa9, 0x13, 0x78, 0x23, 0x9e, 0xb0, 0x4a, 0x22, 0xc5, 0xb4, 0x24, 0x7a, 0x5f, 0x61,
-	0x8d, 0x3b, 0xb0, 0xa4, 0xd2, 0x49, 0x4a, 0x59, 0x16, 0x94, 0x6d, 0x85, 0xcf, 0x91, 0xc6, 0x61,
-	0x48, 0x28, 0x8b, 0xec, 0x08, 0xb9, 0x2e, 0xf1, 0x43, 0xd5, 0xd6, 0xb5, 0x35, 0x7e, 0x20, 0xd1,
-	0xe6, 0x10, 0x96, 0xf7, 0xb8, 0x9d, 0xca, 0x92, 0x74, 0x7b, 0xb4, 0x7c, 0xe4, 0xdb, 0xc7, 0x23,
-	0xe2, 0x9e, 0xd9, 0x3c, 0xc9, 0x2b, 0x0f, 0xf3, 0xc2, 0x71, 0x9b, 0x23, 0x07, 0xf8, 0x5b, 0x71,
-	0x83, 0xc1, 0xa9, 0x4e, 0x09, 0x0b, 0x47, 0xf1, 0xd0, 0x0e, 0x29, 0x39, 0x46, 0xca, 0xc4, 0xb6,
-	0x8f, 0xfc, 0x7d, 0x89, 0xef, 0x73, 0xb4, 0xf9, 0xa7, 0x02, 0xac, 0xe4, 0x25, 0xa9, 0xd5, 0xde,
-	0x84, 0x95, 0xbc, 0x28, 0x55, 0xc6, 0xc8, 0x32, 0xb9, 0x93, 0x15, 0x28, 0x0b, 0x9a, 0xfb, 0xd0,
-	0x14, 0x57, 0xd0, 0xb6, 0x27, 0x39, 0xe5, 0x8b, 0xb7, 0xec, 0xba, 0x58, 0x0d, 0x27, 0xbb, 0x4a,
-	0x1f, 0xc2, 0x0d, 0x65, 0xbe, 0x3d, 0xad, 0xb6, 0x0c, 0x88, 0x55, 0x45, 0x70, 0x38, 0xa1, 0xfd,
-	0x17, 0xd0, 0x4d, 0x51, 0xdb, 0x17, 0x02, 0x99, 0x6e, 0xca, 0xe5, 0x09, 0x63, 0xb7, 0x3c, 0x8f,
-	0x8a, 0xdd, 0x5e, 0xb6, 0x66, 0x0d, 0x99, 0x0f, 0xe0, 0xfa, 0x00, 0x31, 0xe9, 0x0d, 0x87, 0xa9,
-	0x8e, 0x4a, 0x32, 0x5b, 0x82, 0xd2, 0x00, 0xb9, 0xc2, 0xf8, 0x92, 0xc5, 0x3f, 0x79, 0x00, 0x1e,
-	0x45, 0xc8, 0x15, 0x56, 0x96, 0x2c, 0xf1, 0x6d, 0x86, 0x50, 0xf9, 0x74, 0xb0, 0xc7, 0xeb, 0x26,
-	0x1e, 0xd4, 0xb2, 0xce, 0x52, 0x67, 0x6a, 0xd3, 0xaa, 0x08, 0xf8, 0xc0, 0x33, 0x3e, 0x83, 0x65,
-	0x39, 0xe4, 0x9e, 0x3a, 0xc1, 0x10, 0xd9, 0x21, 0x19, 0x61, 0x57, 0x86, 0x7e, 0xeb, 0x5e, 0x4f,
-	0xa5, 0x21, 0xc5, 0x67, 0x47, 0x90, 0xf4, 0x05, 0x85, 0xd5, 0x19, 0x4e, 0xa2, 0xcc, 0xbf, 0x17,
-	0xa0, 0xa2, 0x8e, 0x35, 0x7e, 0x34, 0x7b, 0x14, 0x8f, 0x11, 0x55, 0xc1, 0xae, 0x20, 0xe3, 0x75,
-	0x68, 0xc9, 0x2f, 0x9b, 0x84, 0x0c, 0x93, 0xe4, 0xb0, 0x6c, 0x4a, 0xec, 0x23, 0x89, 0x14, 0xd7,
-	0x96, 0xe2, 0xe2, 0x50, 0xf5, 0xe8, 0x0a, 0xe2, 0xf8, 0x93, 0x88, 0x2b, 0x25, 0x0e, 0xc7, 0x9a,
-	0xa5, 0x20, 0xbe, 0xb9, 0x34, 0xbf, 0x05, 0xc1, 0x4f, 0x83, 0x7c, 0x73, 0xf9, 0x24, 0x0e, 0x98,
-	0x1d, 0x12, 0x1c, 0x30, 0x75, 0x1a, 0x82, 0x40, 0xf5, 0x39, 0xc6, 0x58, 0x87, 0xea, 0x49, 0x64,
-	0x0b, 0x6b, 0x44, 0xe5, 0x9b, 0x9c, 0xd0, 0xca, 0x6a, 0xab, 0x72, 0x12, 0x89, 0x0f, 0xf3, 0xd7,
-	0x05, 0x58, 0x94, 0x97, 0xfc, 0x46, 0x0b, 0x8a, 0x49, 0xf5, 0x52, 0xc4, 0xa2, 0x12, 0x14, 0x5a,
-	0xc9, 0x8a, 0x45, 0x7c, 0xf3, 0x1c, 0x33, 0xf6, 0xe5, 0x19, 0xac, 0x8c, 0x18, 0xfb, 0xe2, 0xf0,
-	0x7d, 0x1d, 0x5a, 0x69, 0x11, 0x24, 0xc6, 0xa5, 0x31, 0xcd, 0x04, 0x2b, 0xc8, 0xe6, 0xda, 0x64,
-	0xfe, 0x18, 0x20, 0xbd, 0xec, 0xe6, 0xe1, 0x10, 0x27, 0xca, 0xf0, 0x4f, 0x8e, 0x19, 0x26, 0xe5,
-	0x13, 0xff, 0x34, 0x6e, 0x43, 0xcb, 0xf1, 0x3c, 0xcc, 0xa7, 0x3b, 0xa3, 0x3d, 0xec, 0x25, 0x09,
-	0x24, 0x8f, 0x35, 0xff, 0x52, 0x80, 0xf6, 0x0e, 0x09, 0x2f, 0x3e, 0xc5, 0x23, 0x94, 0xc9, 0x6e,
-	0x42, 0x49, 0x55, 0x3d, 0xf1, 0x6f, 0xde, 0x11, 0x9c, 0xe0, 0x11, 0x92, 0xdb, 0x5e, 0x46, 0x5d,
-	0x95, 0x23, 0xc4, 0x96, 0xd7, 0x83, 0xc9, 0xd5, 0x66, 0x53, 0x0e, 0x1e, 0x12, 0x4f, 0xf4, 0x3e,
-	0x1e, 0xa6, 0x76, 0x72, 0x91, 0xd9, 0xb4, 0x2a, 0x1e, 0xa6, 0x62, 0x48, 0x19, 0xb2, 0x20, 0x2e,
-	0xaa, 0xb3, 0x86, 0x2c, 0x4a, 0x0c, 0x37, 0x64, 0x15, 0x16, 0xc9, 0xc9, 0x49, 0x84, 0x98, 0x58,
-	0xab, 0x92, 0xa5, 0xa0, 0x24, 0x05, 0x57, 0x33, 0x29, 0x78, 0x45, 0x9c, 0x6b, 0x8f, 0x1e, 0x1d,
-	0xee, 0x8e, 0x51, 0xc0, 0xf4, 0x09, 0x7c, 0x17, 0xaa, 0x1a, 0xf5, 0x43, 0xae, 0x80, 0xdf, 0x84,
-	0xd6, 0x96, 0xe7, 0x0d, 0x9e, 0x38, 0xa1, 0xf6, 0x47, 0x17, 0x2a, 0xfd, 0x9d, 0x83, 0xbe, 0x74,
-	0x49, 0x89, 0x1b, 0xa0, 0x40, 0x7e, 0xe2, 0xef, 0x21, 0x76, 0x88, 0x18, 0xc5, 0x6e, 0x72, 0xe2,
-	0xdf, 0x82, 0x8a, 0xc2, 0xf0, 0x99, 0xbe, 0xfc, 0xd4, 0x47, 0x80, 0x02, 0xcd, 0xff, 0x03, 0xe3,
-	0x47, 0xbc, 0x76, 0x45, 0xb2, 0x71, 0x51, 0x92, 0xde, 0x84, 0xce, 0x58, 0x60, 0x6d, 0x59, 0xd4,
-	0x65, 0x96, 0xa1, 0x2d, 0x07, 0x44, 0x7e, 0x10, 0xb2, 0x8f, 0x60, 0x59, 0x96, 0xda, 0x92, 0xcf,
-	0x15, 0x58, 0x70, 0x1f, 0x26, 0xeb, 0x59, 0xb6, 0xc4, 0xf7, 0xbd, 0xa7, 0x86, 0x3a, 0xc6, 0xd4,
-	0xcd, 0x8e, 0xb1, 0x07, 0xed, 0x89, 0x67, 0x38, 0x43, 0x5d, 0xf5, 0xcd, 0x7e, 0x9d, 0xeb, 0xad,
-	0x6e, 0xc8, 0x67, 0xbd, 0x0d, 0xfd, 0xac, 0xb7, 0xb1, 0xeb, 0x87, 0xec, 0xc2, 0xd8, 0x85, 0x56,
-	0xfe, 0xc1, 0xca, 0x78, 0x51, 0x57, 0xc6, 0x33, 0x9e, 0xb1, 0xe6, 0xb2, 0xd9, 0x83, 0xf6, 0xc4,
-	0xdb, 0x95, 0xd6, 0x67, 0xf6, 0x93, 0xd6, 0x5c, 0x46, 0x0f, 0xa0, 0x9e, 0x79, 0xac, 0x32, 0xba,
-	0x92, 0xc9, 0xf4, 0xfb, 0xd5, 0x5c, 0x06, 0x3b, 0xd0, 0xcc, 0xbd, 0x1f, 0x19, 0x3d, 0x65, 0xcf,
-	0x8c, 0x47, 0xa5, 0xb9, 0x4c, 0xb6, 0xa1, 0x9e, 0x79, 0xc6, 0xd1, 0x5a, 0x4c, 0xbf, 0x15, 0xf5,
-	0x6e, 0xcc, 0x18, 0x51, 0xa7, 0xe5, 0x1e, 0xb4, 0x27, 0xde, 0x76, 0xb4, 0x4b, 0x66, 0x3f, 0xf9,
-	0xcc, 0x55, 0xe6, 0x73, 0xb1, 0x44, 0x99, 0xd6, 0x3d, 0xb3, 0x44, 0xd3, 0x2f, 0x39, 0xbd, 0x97,
-	0x66, 0x0f, 0x2a, 0xad, 0x76, 0xa1, 0x95, 0x7f, 0xc4, 0xd1, 0xcc, 0x66, 0x3e, 0xed, 0x5c, 0xbe,
-	0xde, 0xb9, 0xf7, 0x9c, 0x74, 0xbd, 0x67, 0x3d, 0xf3, 0xcc, 0x65, 0xb4, 0x05, 0xa0, 0x1a, 0x75,
-	0x0f, 0x07, 0x89, 0xa3, 0xa7, 0x2e, 0x08, 0x12, 0x47, 0xcf, 0x68, 0xea, 0x1f, 0x00, 0xc8, 0xfe,
-	0xda, 0x23, 0x31, 0x33, 0xae, 0x6b, 0x35, 0x26, 0x9a, 0xfa, 0x5e, 0x77, 0x7a, 0x60, 0x8a, 0x01,
-	0xa2, 0xf4, 0x2a, 0x0c, 0x3e, 0x01, 0x48, 0xfb, 0x76, 0xcd, 0x60, 0xaa, 0x93, 0xbf, 0xc4, 0x07,
-	0x8d, 0x6c, 0x97, 0x6e, 0x28, 0x5b, 0x67, 0x74, 0xee, 0x97, 0xb0, 0x68, 0x4f, 0x74, 0x61, 0xf9,
-	0x60, 0x9b, 0x6c, 0xce, 0x7a, 0x53, 0x9d, 0x98, 0x71, 0x1f, 0x1a, 0xd9, 0xf6, 0x4b, 0x6b, 0x31,
-	0xa3, 0x25, 0xeb, 0xe5, 0x5a, 0x30, 0xe3, 0x01, 0xb4, 0xf2, 0xad, 0x97, 0x0e, 0xa9, 0x99, 0x0d,
-	0x59, 0x4f, 0x5d, 0x2c, 0x66, 0xc8, 0xdf, 0x05, 0x48, 0x5b, 0x34, 0xed, 0xbe, 0xa9, 0xa6, 0x6d,
-	0x42, 0xea, 0x1e, 0xb4, 0x27, 0x5a, 0x2f, 0x6d, 0xf1, 0xec, 0x8e, 0xec, 0xb2, 0xbd, 0x9e, 0x69,
-	0xa4, 0x74, 0x08, 0x4e, 0xb7, 0x62, 0x3a, 0x04, 0x67, 0x75, 0x5d, 0xdb, 0x50, 0x1f, 0x4c, 0xf3,
-	0x18, 0xcc, 0xe5, 0x31, 0xab, 0x97, 0x7a, 0x0f, 0x20, 0x3d, 0xb6, 0xb4, 0x17, 0xa6, 0x0e, 0xb2,
-	0x5e, 0x53, 0x5f, 0xfe, 0x4a, 0xba, 0x1d, 0x68, 0xe6, 0xee, 0x47, 0x74, 0xba, 0x9b, 0x75, 0x69,
-	0x72, 0xd9, 0x21, 0x90, 0xbf, 0x4c, 0xd0, 0x2b, 0x38, 0xf3, 0x8a, 0xe1, 0xb2, 0x38, 0xce, 0x76,
-	0x7e, 0x3a, 0x82, 0x66, 0x74, 0x83, 0xdf, 0x93, 0x57, 0xb2, 0xdd, 0x5d, 0x26, 0xaf, 0xcc, 0x68,
-	0xfa, 0xe6, 0x32, 0xda, 0x87, 0xf6, 0x9e, 0x2e, 0xdc, 0x55, 0x53, 0xa1, 0xd7, 0x6f, 0xba, 0x89,
-	0xea, 0xf5, 0x66, 0x0d, 0xa9, 0x75, 0xf9, 0x1c, 0x3a, 0x53, 0x0d, 0x85, 0xb1, 0x96, 0x5c, 0xc1,
-	0xcf, 0xec, 0x34, 0xe6, 0xaa, 0x75, 0x00, 0x4b, 0x93, 0xfd, 0x84, 0xf1, 0x72, 0x12, 0x13, 0xb3,
-	0xfa, 0x8c, 0xb9, 0xac, 0x3e, 0x84, 0xaa, 0xae, 0x11, 0x0d, 0xf5, 0xd4, 0x31, 0x51, 0x33, 0xce,
-	0x9d, 0x7a, 0x5f, 0x84, 0x7c, 0x52, 0x7f, 0xa5, 0x21, 0x3f, 0x51, 0xa5, 0xf5, 0xd4, 0xcb, 0x44,
-	0x42, 0x79, 0x1f, 0x2a, 0xaa, 0x0c, 0x33, 0x56, 0x92, 0xcd, 0x96, 0xa9, 0xca, 0x2e, 0x8b, 0xb0,
-	0x3d, 0xc4, 0x32, 0xc5, 0x95, 0x16, 0x3a, 0x5d, 0x6f, 0xe9, 0x3d, 0x92, 0x1b, 0x51, 0x6b, 0xb1,
-	0x05, 0x8d, 0x6c, 0x79, 0xa5, 0x97, 0x74, 0x46, 0xc9, 0x35, 0x4f, 0x93, 0xed, 0xf3, 0xa7, 0xdf,
-	0xad, 0xbd, 0xf0, 0xb7, 0xef, 0xd6, 0x5e, 0xf8, 0xd5, 0xb3, 0xb5, 0xc2, 0xd3, 0x67, 0x6b, 0x85,
-	0xbf, 0x3e, 0x5b, 0x2b, 0xfc, 0xf3, 0xd9, 0x5a, 0xe1, 0x27, 0x3f, 0xff, 0x0f, 0xff, 0x73, 0x45,
-	0xe3, 0x80, 0x61, 0x1f, 0x6d, 0x8e, 0x31, 0x65, 0x99, 0xa1, 0xf0, 0x6c, 0x28, 0xff, 0x78, 0x95,
-	0xf9, 0x3f, 0x16, 0xd7, 0xf2, 0x78, 0x51, 0xc0, 0xef, 0xfe, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x77,
-	0xb9, 0x27, 0x67, 0xdc, 0x25, 0x00, 0x00,
+	// 3358 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x3a, 0x4d, 0x73, 0x1b, 0x47,
+	0x76, 0x06, 0x01, 0x12, 0xc0, 0xc3, 0x17, 0x31, 0xa0, 0x28, 0x10, 0xb2, 0x19, 0x79, 0x64, 0xcb,
+	0x94, 0x1d, 0x91, 0x8e, 0xec, 0xb2, 0x2c, 0xbb, 0x1c, 0x85, 0xa4, 0x68, 0x92, 0xb6, 0x69, 0x21,
+	0x03, 0x31, 0x4e, 0x25, 0x95, 0x4c, 0x0d, 0x67, 0x9a, 0x60, 0x9b, 0xc0, 0xf4, 0xb8, 0xbb, 0x87,
+	0x22, 0x9d, 0xaa, 0x54, 0x4e, 0xc9, 0x2d, 0x87, 0x3d, 0xec, 0x6d, 0xff, 0xc0, 0xd6, 0xfe, 0x83,
+	0xbd, 0xee, 0xc1, 0xb5, 0xa7, 0x3d, 0xee, 0x65, 0xb7, 0xd6, 0xba, 0xed, 0x75, 0x7f, 0xc1, 0x56,
+	0x7f, 0xcd, 0x07, 0x00, 0xd2, 0x36, 0x4b, 0x55, 0x7b, 0x41, 0xf5, 0x7b, 0xfd, 0xe6, 0x7d, 0x75,
+	0xf7, 0xeb, 0xf7, 0x5e, 0x03, 0xfa, 0x43, 0xcc, 0x4f, 0xe2, 0xa3, 0x75, 0x9f, 0x8c, 0x37, 0x4e,
+	0x3d, 0xee, 0xdd, 0xf7, 0x49, 0xc8, 0x3d, 0x1c, 0x22, 0xca, 0xa6, 0x60, 0x46, 0xfd, 0x8d, 0x11,
+	0x3e, 0x62, 0x1b, 0x11, 0x25, 0x9c, 0xf8, 0x64, 0xa4, 0x47, 0x6c, 0xc3, 0x1b, 0xa2, 0x90, 0xaf,
+	0x4b, 0xc0, 0x2a, 0x0d, 0x69, 0xe4, 0xf7, 0xaa, 0xc4, 0xc7, 0x0a, 0xd1, 0xab, 0xfa, 0xcc, 0x0c,
+	0x6b, 0xfc, 0x22, 0x42, 0x4c, 0x03, 0xb7, 0x86, 0x84, 0x0c, 0x47, 0x48, 0xf1, 0x38, 0x8a, 0x8f,
+	0x37, 0xd0, 0x38, 0xe2, 0x17, 0x6a, 0xd2, 0xfe, 0xc5, 0x1c, 0x2c, 0x6f, 0x53, 0xe4, 0x71, 0xb4,
+	0x6d, 0x14, 0x70, 0xd0, 0x37, 0x31, 0x62, 0xdc, 0x7a, 0x1d, 0xea, 0x89, 0x52, 0x2e, 0x0e, 0xba,
+	0x85, 0xdb, 0x85, 0xb5, 0xaa, 0x53, 0x4b, 0x70, 0xfb, 0x81, 0x75, 0x13, 0xca, 0xe8, 0x1c, 0xf9,
+	0x62, 0x76, 0x4e, 0xce, 0x2e, 0x08, 0x70, 0x3f, 0xb0, 0xfe, 0x01, 0x6a, 0x8c, 0x53, 0x1c, 0x0e,
+	0xdd, 0x98, 0x21, 0xda, 0x2d, 0xde, 0x2e, 0xac, 0xd5, 0x1e, 0x2c, 0xae, 0x0b, 0x95, 0xd7, 0x07,
+	0x72, 0xe2, 0x90, 0x21, 0xea, 0x00, 0x4b, 0xc6, 0xd6, 0x5d, 0x28, 0x07, 0xe8, 0x0c, 0xfb, 0x88,
+	0x75, 0x4b, 0xb7, 0x8b, 0x6b, 0xb5, 0x07, 0x75, 0x45, 0xfe, 0x44, 0x22, 0x1d, 0x33, 0x69, 0xdd,
+	0x83, 0x0a, 0xe3, 0x84, 0x7a, 0x43, 0xc4, 0xba, 0xf3, 0x92, 0xb0, 0x61, 0xf8, 0x4a, 0xac, 0x93,
+	0x4c, 0x5b, 0xaf, 0x42, 0xf1, 0xe9, 0xf6, 0x7e, 0x77, 0x41, 0x4a, 0x07, 0x4d, 0x15, 0x21, 0xdf,
+	0x11, 0x68, 0xeb, 0x0e, 0x34, 0x98, 0x17, 0x06, 0x47, 0xe4, 0xdc, 0x8d, 0x70, 0x10, 0xb2, 0x6e,
+	0xf9, 0x76, 0x61, 0xad, 0xe2, 0xd4, 0x35, 0xb2, 0x2f, 0x70, 0xf6, 0x47, 0x70, 0x63, 0xc0, 0x3d,
+	0xca, 0xaf, 0xe1, 0x1d, 0xfb, 0x10, 0x96, 0x1d, 0x34, 0x26, 0x67, 0xd7, 0x72, 0x6d, 0x17, 0xca,
+	0x1c, 0x8f, 0x11, 0x89, 0xb9, 0x74, 0x6d, 0xc3, 0x31, 0xa0, 0xfd, 0xab, 0x02, 0x58, 0x3b, 0xe7,
+	0xc8, 0xef, 0x53, 0xe2, 0x23, 0xc6, 0xfe, 0x46, 0xcb, 0xf5, 0x16, 0x94, 0x23, 0xa5, 0x40, 0xb7,
+	0x74, 0xbb, 0x90, 0xae, 0x82, 0xd1, 0xca, 0xcc, 0xda, 0x5f, 0xc3, 0xd2, 0x00, 0x0f, 0x43, 0x6f,
+	0xf4, 0x12, 0xf5, 0x5d, 0x86, 0x05, 0x26, 0x79, 0x4a, 0x55, 0x1b, 0x8e, 0x86, 0xec, 0x3e, 0x58,
+	0x5f, 0x79, 0x98, 0xbf, 0x3c, 0x49, 0xf6, 0x7d, 0xe8, 0xe4, 0x38, 0xb2, 0x88, 0x84, 0x0c, 0x49,
+	0x05, 0xb8, 0xc7, 0x63, 0x26, 0x99, 0xcd, 0x3b, 0x1a, 0xb2, 0x09, 0x2c, 0x1f, 0x46, 0xc1, 0x35,
+	0x4f, 0xd3, 0x03, 0xa8, 0x52, 0xc4, 0x48, 0x4c, 0xc5, 0x19, 0x98, 0x93, 0x4e, 0x5d, 0x52, 0x4e,
+	0xfd, 0x02, 0x87, 0xf1, 0xb9, 0x63, 0xe6, 0x9c, 0x94, 0x4c, 0xef, 0x4f, 0xce, 0xae, 0xb3, 0x3f,
+	0x3f, 0x82, 0x1b, 0x7d, 0x2f, 0x66, 0xd7, 0xd1, 0xd5, 0xfe, 0x58, 0xec, 0x6d, 0x16, 0x8f, 0xaf,
+	0xf5, 0xf1, 0x2f, 0x0b, 0x50, 0xd9, 0x8e, 0xe2, 0x43, 0xe6, 0x0d, 0x91, 0xf5, 0x77, 0x50, 0xe3,
+	0x84, 0x7b, 0x23, 0x37, 0x16, 0xa0, 0x24, 0x2f, 0x39, 0x20, 0x51, 0x8a, 0xe0, 0x75, 0xa8, 0x47,
+	0x88, 0xfa, 0x51, 0xac, 0x29, 0xe6, 0x6e, 0x17, 0xd7, 0x4a, 0x4e, 0x4d, 0xe1, 0x14, 0xc9, 0x3a,
+	0x74, 0xe4, 0x9c, 0x8b, 0x43, 0xf7, 0x14, 0xd1, 0x10, 0x8d, 0xc6, 0x24, 0x40, 0x72, 0x73, 0x94,
+	0x9c, 0xb6, 0x9c, 0xda, 0x0f, 0x3f, 0x4f, 0x26, 0xac, 0xb7, 0xa1, 0x9d, 0xd0, 0x8b, 0x1d, 0x2f,
+	0xa9, 0x4b, 0x92, 0xba, 0xa5, 0xa9, 0x0f, 0x35, 0xda, 0xfe, 0x6f, 0x68, 0x3e, 0x3b, 0xa1, 0x84,
+	0xf3, 0x11, 0x0e, 0x87, 0x4f, 0x3c, 0xee, 0x89, 0xa3, 0x19, 0x21, 0x8a, 0x49, 0xc0, 0xb4, 0xb6,
+	0x06, 0xb4, 0xde, 0x81, 0x36, 0x57, 0xb4, 0x28, 0x70, 0x0d, 0xcd, 0x9c, 0xa4, 0x59, 0x4c, 0x26,
+	0xfa, 0x9a, 0xf8, 0x4d, 0x68, 0xa6, 0xc4, 0xe2, 0x70, 0x6b, 0x7d, 0x1b, 0x09, 0xf6, 0x19, 0x1e,
+	0x23, 0xfb, 0x4c, 0xfa, 0x4a, 0x2e, 0xb2, 0xf5, 0x0e, 0x54, 0x53, 0x3f, 0x14, 0xe4, 0x0e, 0x69,
+	0xaa, 0x1d, 0x62, 0xdc, 0xe9, 0x54, 0x12, 0xa7, 0x7c, 0x02, 0x2d, 0x9e, 0x28, 0xee, 0x06, 0x1e,
+	0xf7, 0xf2, 0x9b, 0x2a, 0x6f, 0x95, 0xd3, 0xe4, 0x39, 0xd8, 0xfe, 0x18, 0xaa, 0x7d, 0x1c, 0x30,
+	0x25, 0xb8, 0x0b, 0x65, 0x3f, 0xa6, 0x14, 0x85, 0xdc, 0x98, 0xac, 0x41, 0x6b, 0x09, 0xe6, 0x47,
+	0x78, 0x8c, 0xb9, 0x36, 0x53, 0x01, 0x36, 0x01, 0x38, 0x40, 0x63, 0x42, 0x2f, 0xa4, 0xc3, 0x96,
+	0x60, 0x3e, 0xbb, 0xb8, 0x0a, 0xb0, 0x6e, 0x41, 0x75, 0xec, 0x9d, 0x27, 0x8b, 0x2a, 0x66, 0x2a,
+	0x63, 0xef, 0x5c, 0x29, 0xdf, 0x85, 0xf2, 0xb1, 0x87, 0x47, 0x7e, 0xc8, 0xb5, 0x57, 0x0c, 0x98,
+	0x0a, 0x2c, 0x65, 0x05, 0xfe, 0x66, 0x0e, 0x6a, 0x4a, 0xa2, 0x52, 0x78, 0x09, 0xe6, 0x7d, 0xcf,
+	0x3f, 0x49, 0x44, 0x4a, 0xc0, 0xba, 0x0b, 0xf3, 0xa9, 0xb8, 0x24, 0xc2, 0xa5, 0x9a, 0x1a, 0xd5,
+	0x36, 0x00, 0xd8, 0x73, 0x2f, 0xd2, 0xba, 0x15, 0x2f, 0x21, 0xae, 0x0a, 0x1a, 0xa5, 0xee, 0x7b,
+	0x50, 0x57, 0xfb, 0x4e, 0x7f, 0x52, 0xba, 0xe4, 0x93, 0x9a, 0xa2, 0x52, 0x1f, 0xdd, 0x81, 0x46,
+	0xcc, 0x90, 0x7b, 0x82, 0x11, 0xf5, 0xa8, 0x7f, 0x72, 0xd1, 0x9d, 0x57, 0x17, 0x50, 0xcc, 0xd0,
+	0x9e, 0xc1, 0x59, 0x0f, 0x60, 0x5e, 0xc4, 0x16, 0xd6, 0x5d, 0x90, 0x77, 0xdd, 0xab, 0x59, 0x96,
+	0xd2, 0xd4, 0x75, 0xf9, 0xbb, 0x13, 0x72, 0x7a, 0xe1, 0x28, 0xd2, 0xde, 0x87, 0x00, 0x29, 0xd2,
+	0x5a, 0x84, 0xe2, 0x29, 0xba, 0xd0, 0xe7, 0x50, 0x0c, 0x85, 0x73, 0xce, 0xbc, 0x51, 0x6c, 0xbc,
+	0xae, 0x80, 0x8f, 0xe6, 0x3e, 0x2c, 0xd8, 0x3e, 0xb4, 0xb6, 0x46, 0xa7, 0x98, 0x64, 0x3e, 0x5f,
+	0x82, 0xf9, 0xb1, 0xf7, 0x35, 0xa1, 0xc6, 0x93, 0x12, 0x90, 0x58, 0x1c, 0x12, 0x6a, 0x58, 0x48,
+	0xc0, 0x6a, 0xc2, 0x1c, 0x89, 0xa4, 0xbf, 0xaa, 0xce, 0x1c, 0x89, 0x52, 0x41, 0xa5, 0x8c, 0x20,
+	0xfb, 0x8f, 0x25, 0x80, 0x54, 0x8a, 0xe5, 0x40, 0x0f, 0x13, 0x97, 0x21, 0x2a, 0xee, 0x77, 0xf7,
+	0xe8, 0x82, 0x23, 0xe6, 0x52, 0xe4, 0xc7, 0x94, 0xe1, 0x33, 0xb1, 0x7e, 0xc2, 0xec, 0x1b, 0xca,
+	0xec, 0x09, 0xdd, 0x9c, 0x9b, 0x98, 0x0c, 0xd4, 0x77, 0x5b, 0xe2, 0x33, 0xc7, 0x7c, 0x65, 0xed,
+	0xc3, 0x8d, 0x94, 0x67, 0x90, 0x61, 0x37, 0x77, 0x15, 0xbb, 0x4e, 0xc2, 0x2e, 0x48, 0x59, 0xed,
+	0x40, 0x07, 0x13, 0xf7, 0x9b, 0x18, 0xc5, 0x39, 0x46, 0xc5, 0xab, 0x18, 0xb5, 0x31, 0xf9, 0x67,
+	0xf9, 0x41, 0xca, 0xa6, 0x0f, 0x2b, 0x19, 0x2b, 0xc5, 0x71, 0xcf, 0x30, 0x2b, 0x5d, 0xc5, 0x6c,
+	0x39, 0xd1, 0x4a, 0xc4, 0x83, 0x94, 0xe3, 0x67, 0xb0, 0x8c, 0x89, 0xfb, 0xdc, 0xc3, 0x7c, 0x92,
+	0xdd, 0xfc, 0x0f, 0x18, 0x29, 0x6e, 0xb4, 0x3c, 0x2f, 0x65, 0xe4, 0x18, 0xd1, 0x61, 0xce, 0xc8,
+	0x85, 0x1f, 0x30, 0xf2, 0x40, 0x7e, 0x90, 0xb2, 0xd9, 0x84, 0x36, 0x26, 0x93, 0xda, 0x94, 0xaf,
+	0x62, 0xd2, 0xc2, 0x24, 0xaf, 0xc9, 0x16, 0xb4, 0x19, 0xf2, 0x39, 0xa1, 0xd9, 0x4d, 0x50, 0xb9,
+	0x8a, 0xc5, 0xa2, 0xa6, 0x4f, 0x78, 0xd8, 0xff, 0x0e, 0xf5, 0xbd, 0x78, 0x88, 0xf8, 0xe8, 0x28,
+	0x09, 0x06, 0x2f, 0x2d, 0xfe, 0xd8, 0x7f, 0x99, 0x83, 0xda, 0xf6, 0x90, 0x92, 0x38, 0xca, 0xc5,
+	0x64, 0x75, 0x48, 0x27, 0x63, 0xb2, 0x24, 0x91, 0x31, 0x59, 0x11, 0xbf, 0x0f, 0xf5, 0xb1, 0x3c,
+	0xba, 0x9a, 0x5e, 0xc5, 0xa1, 0xf6, 0xd4, 0xa1, 0x76, 0x6a, 0xe3, 0x14, 0xb0, 0xd6, 0x01, 0x22,
+	0x1c, 0x30, 0xfd, 0x8d, 0x0a, 0x47, 0x2d, 0x9d, 0x6e, 0x99, 0x10, 0xed, 0x54, 0x23, 0x33, 0x14,
+	0xe9, 0xdc, 0x91, 0x70, 0x92, 0xfe, 0x20, 0x17, 0x8c, 0x52, 0xef, 0x39, 0x70, 0x94, 0x8c, 0xad,
+	0x3d, 0x68, 0x9c, 0x28, 0x97, 0xe9, 0x8f, 0xd4, 0x1e, 0xba, 0xa3, 0x2d, 0x49, 0xed, 0x5d, 0xcf,
+	0x7a, 0x56, 0x2d, 0x40, 0xfd, 0x24, 0x83, 0xea, 0x0d, 0xa0, 0x3d, 0x45, 0x32, 0x23, 0x06, 0xad,
+	0x65, 0x63, 0x50, 0xed, 0x81, 0xa5, 0x04, 0x65, 0xbf, 0xcc, 0xc6, 0xa5, 0xff, 0x9f, 0x83, 0xfa,
+	0x97, 0x88, 0x3f, 0x27, 0xf4, 0x54, 0xe9, 0x6b, 0x41, 0x29, 0xf4, 0xc6, 0x48, 0x73, 0x94, 0x63,
+	0x6b, 0x05, 0x2a, 0xf4, 0x5c, 0x05, 0x10, 0xbd, 0x9e, 0x65, 0x7a, 0x2e, 0x03, 0x83, 0xf5, 0x1a,
+	0x00, 0x3d, 0x77, 0x23, 0xcf, 0x3f, 0x45, 0xda, 0x83, 0x25, 0xa7, 0x4a, 0xcf, 0xfb, 0x0a, 0x21,
+	0xb6, 0x02, 0x3d, 0x77, 0x11, 0xa5, 0x84, 0x32, 0x1d, 0xab, 0x2a, 0xf4, 0x7c, 0x47, 0xc2, 0xfa,
+	0xdb, 0x80, 0x92, 0x28, 0x42, 0x41, 0x77, 0xde, 0x7c, 0xfb, 0x44, 0x21, 0x84, 0x54, 0x6e, 0xa4,
+	0x2e, 0x28, 0xa9, 0x3c, 0x95, 0xca, 0x53, 0xa9, 0x65, 0xf5, 0x25, 0xcf, 0x4a, 0xe5, 0x89, 0xd4,
+	0x8a, 0x92, 0xca, 0x33, 0x52, 0x79, 0x2a, 0xb5, 0x6a, 0xbe, 0xd5, 0x52, 0xed, 0xff, 0x2b, 0xc0,
+	0xf2, 0x64, 0xe2, 0xa7, 0x73, 0xd3, 0xf7, 0xa1, 0xee, 0xcb, 0xf5, 0xca, 0xed, 0xc9, 0xf6, 0xd4,
+	0x4a, 0x3a, 0x35, 0x3f, 0x05, 0xac, 0x87, 0xd0, 0x08, 0x95, 0x83, 0x93, 0xad, 0x59, 0x4c, 0xd7,
+	0x25, 0xeb, 0x7b, 0xa7, 0x1e, 0x66, 0x20, 0xfb, 0x11, 0x2c, 0x7d, 0x81, 0x99, 0xc9, 0x90, 0xd1,
+	0x4f, 0xc8, 0xba, 0xed, 0x3f, 0x17, 0xa0, 0xbe, 0x1b, 0xa3, 0xe4, 0x63, 0xb1, 0x4d, 0x22, 0x4d,
+	0x3a, 0xef, 0x88, 0xa1, 0x58, 0xe7, 0x28, 0xd2, 0x59, 0xf9, 0xbc, 0x23, 0xc7, 0xd9, 0x64, 0xbd,
+	0x98, 0x2b, 0x0b, 0x2c, 0x28, 0x79, 0x74, 0xa8, 0xea, 0xc7, 0xaa, 0x23, 0xc7, 0x82, 0x65, 0x8c,
+	0xd5, 0xb2, 0x35, 0x1c, 0x31, 0x14, 0x54, 0xb2, 0xca, 0x59, 0x50, 0x5b, 0x47, 0x8c, 0x45, 0x84,
+	0x10, 0x56, 0x23, 0xb9, 0x48, 0x55, 0x75, 0x8f, 0xca, 0x0d, 0x25, 0x8e, 0xb6, 0xcc, 0xcd, 0x2a,
+	0x3a, 0xed, 0x89, 0x62, 0x11, 0xaf, 0x04, 0x5b, 0xca, 0x98, 0x5e, 0x17, 0x31, 0x14, 0x0b, 0xc6,
+	0xb8, 0x47, 0x55, 0x30, 0xee, 0x82, 0x5a, 0x30, 0x89, 0x91, 0x69, 0xdc, 0x3e, 0xdc, 0x98, 0x70,
+	0x93, 0x5e, 0xae, 0x77, 0xa1, 0x1a, 0x19, 0x64, 0xb7, 0x90, 0x75, 0x7a, 0xd6, 0x35, 0x4e, 0x4a,
+	0x64, 0x07, 0x60, 0x7d, 0x45, 0x31, 0x47, 0x03, 0x4e, 0x91, 0x37, 0x7e, 0x19, 0xf5, 0x94, 0x05,
+	0x25, 0x99, 0x1f, 0x0a, 0x77, 0xd6, 0x1d, 0x39, 0xb6, 0xdf, 0x82, 0x4e, 0x4e, 0x8a, 0x56, 0x77,
+	0x11, 0x8a, 0x23, 0x14, 0x4a, 0xee, 0x0d, 0x47, 0x0c, 0x6d, 0x0f, 0xda, 0x0e, 0xf2, 0x82, 0x97,
+	0xa7, 0x8d, 0x16, 0x51, 0x4c, 0x45, 0xac, 0x81, 0x95, 0x15, 0xa1, 0x55, 0x31, 0x5a, 0x17, 0x32,
+	0x5a, 0x3f, 0x85, 0xf6, 0xf6, 0x88, 0x30, 0x34, 0xe0, 0x01, 0x0e, 0x5f, 0x46, 0x01, 0xf8, 0x5f,
+	0xd0, 0x79, 0xc6, 0x2f, 0xbe, 0x12, 0xcc, 0x18, 0xfe, 0x16, 0xbd, 0x24, 0xfb, 0x28, 0x79, 0x6e,
+	0xec, 0xa3, 0xe4, 0xb9, 0x28, 0x27, 0x7d, 0x32, 0x8a, 0xc7, 0xa1, 0x0c, 0x3e, 0x0d, 0x47, 0x43,
+	0xf6, 0x16, 0xd4, 0x55, 0xd5, 0x72, 0x40, 0x82, 0x78, 0x84, 0x66, 0x46, 0xbd, 0x55, 0x80, 0xc8,
+	0xa3, 0xde, 0x18, 0x71, 0x44, 0xd5, 0xa9, 0xad, 0x3a, 0x19, 0x8c, 0xfd, 0xf3, 0x39, 0x58, 0x52,
+	0x1d, 0x9e, 0x81, 0x6a, 0x6c, 0x18, 0x13, 0x7a, 0x50, 0x39, 0x21, 0x8c, 0x67, 0x18, 0x26, 0xb0,
+	0x50, 0x31, 0x08, 0x0d, 0x37, 0x31, 0xcc, 0xb5, 0x5d, 0x8a, 0x57, 0xb7, 0x5d, 0xa6, 0x1a, 0x2b,
+	0xa5, 0xe9, 0xc6, 0x8a, 0x3c, 0x2e, 0x9a, 0x48, 0x1f, 0xcf, 0xaa, 0x53, 0xd5, 0x98, 0xfd, 0xc0,
+	0xba, 0x0b, 0xad, 0xa1, 0xd0, 0xd2, 0x3d, 0x21, 0xe4, 0xd4, 0x8d, 0x3c, 0x7e, 0xa2, 0xcf, 0x6b,
+	0x43, 0xa2, 0xf7, 0x08, 0x39, 0xed, 0x7b, 0xfc, 0xc4, 0x7a, 0x04, 0x4d, 0x9d, 0x78, 0x8f, 0xa5,
+	0x8b, 0x58, 0xb7, 0x9c, 0x3d, 0x42, 0x59, 0xef, 0x39, 0x8d, 0xd3, 0x0c, 0xc4, 0xec, 0x9b, 0x70,
+	0xe3, 0x09, 0x62, 0x9c, 0x92, 0x8b, 0xbc, 0x63, 0xec, 0x7f, 0x04, 0xd8, 0x0f, 0x39, 0xa2, 0xc7,
+	0x9e, 0x8f, 0x98, 0xf5, 0x6e, 0x16, 0xd2, 0x07, 0x74, 0x71, 0x5d, 0x35, 0xd8, 0x92, 0x09, 0x27,
+	0x43, 0x63, 0xaf, 0xc3, 0x82, 0x43, 0x62, 0x71, 0x01, 0xbc, 0x61, 0x46, 0xfa, 0xbb, 0xba, 0xfe,
+	0x4e, 0x22, 0x1d, 0x3d, 0x67, 0xef, 0x99, 0xa6, 0x41, 0xca, 0x4e, 0x2f, 0xd1, 0x3a, 0x54, 0xb1,
+	0xc1, 0xe9, 0x38, 0x3e, 0x2d, 0x3a, 0x25, 0xb1, 0x3f, 0x86, 0x8e, 0xe2, 0xa4, 0x38, 0x1b, 0x36,
+	0x6f, 0xc0, 0x02, 0x35, 0x6a, 0x14, 0xd2, 0xce, 0x9a, 0x26, 0xd2, 0x73, 0xf6, 0x4d, 0x15, 0xa1,
+	0x52, 0x43, 0x8c, 0x3f, 0x3a, 0xd0, 0x16, 0x13, 0x39, 0x9e, 0xf6, 0xa7, 0x50, 0xdf, 0x74, 0xfa,
+	0x5f, 0x22, 0x3c, 0x3c, 0x39, 0x12, 0xf7, 0xd5, 0x07, 0x79, 0x38, 0x89, 0x64, 0x4a, 0xdb, 0xcc,
+	0x94, 0x93, 0xa3, 0xb3, 0x3f, 0x83, 0xe5, 0xcd, 0x20, 0xc8, 0xa2, 0x8c, 0xd6, 0xef, 0x42, 0x35,
+	0xcc, 0xb0, 0xcb, 0x64, 0x09, 0x39, 0xea, 0x94, 0xc8, 0xbe, 0x0f, 0xd6, 0x2e, 0xe2, 0xfb, 0xfd,
+	0x67, 0xde, 0xd1, 0x28, 0xb5, 0xfe, 0x26, 0x94, 0x31, 0x73, 0x71, 0x74, 0xf6, 0x81, 0xe4, 0x52,
+	0x71, 0x16, 0x30, 0xdb, 0x8f, 0xce, 0x3e, 0xb0, 0xef, 0x41, 0x27, 0x47, 0x7e, 0x45, 0x58, 0xd9,
+	0x04, 0x6b, 0xf0, 0xe3, 0x39, 0x27, 0x2c, 0xe6, 0x32, 0x2c, 0xee, 0x41, 0x67, 0xf0, 0x23, 0xa5,
+	0xfd, 0x07, 0x74, 0x9e, 0x86, 0x23, 0x1c, 0xa2, 0xed, 0xfe, 0xe1, 0x01, 0x4a, 0x62, 0xaa, 0x05,
+	0x25, 0x91, 0xed, 0x6b, 0x59, 0x72, 0x2c, 0x54, 0x08, 0x8f, 0x5c, 0x3f, 0x8a, 0x99, 0x6e, 0x13,
+	0x2e, 0x84, 0x47, 0xdb, 0x51, 0xcc, 0xcc, 0xdd, 0x45, 0xc2, 0xd1, 0x85, 0x8c, 0x34, 0x15, 0x79,
+	0x77, 0x3d, 0x0d, 0x47, 0x17, 0xf6, 0xdf, 0xcb, 0xde, 0x0d, 0x42, 0x81, 0xe3, 0x85, 0x01, 0x19,
+	0x3f, 0x41, 0x67, 0x19, 0x09, 0x53, 0x7a, 0x7f, 0x57, 0x80, 0xfa, 0xe6, 0x10, 0x85, 0xfc, 0x09,
+	0xe2, 0x1e, 0x1e, 0xc9, 0x5e, 0xc0, 0x19, 0xa2, 0x0c, 0x93, 0x50, 0x87, 0x0d, 0x03, 0x8a, 0x56,
+	0x0e, 0x0e, 0x31, 0x77, 0x03, 0x0f, 0x8d, 0x49, 0x28, 0xb9, 0x54, 0x1c, 0x10, 0xa8, 0x27, 0x12,
+	0x63, 0xbd, 0x05, 0x2d, 0xd5, 0xc6, 0x75, 0x4f, 0xbc, 0x30, 0x18, 0x21, 0xaa, 0x62, 0x49, 0xd5,
+	0x69, 0x2a, 0xf4, 0x9e, 0xc6, 0x5a, 0xf7, 0x60, 0x51, 0x87, 0x93, 0x94, 0x52, 0xdd, 0xea, 0x2d,
+	0x8d, 0xcf, 0x91, 0xc6, 0x51, 0x44, 0x28, 0x67, 0x2e, 0x43, 0xbe, 0x4f, 0xc6, 0x91, 0x2e, 0xa4,
+	0x5b, 0x06, 0x3f, 0x50, 0x68, 0x7b, 0x08, 0x1d, 0x79, 0xa7, 0x6a, 0x4b, 0xd2, 0xe3, 0xd1, 0x1c,
+	0xa3, 0xb1, 0x7b, 0x34, 0x22, 0xfe, 0xa9, 0x2b, 0x82, 0xbc, 0xf6, 0xb0, 0x48, 0xd5, 0xb7, 0x04,
+	0x72, 0x80, 0xbf, 0x95, 0x3d, 0x23, 0x41, 0x75, 0x42, 0x78, 0x34, 0x8a, 0x87, 0x6e, 0x44, 0xc9,
+	0x11, 0xd2, 0x26, 0xb6, 0xc6, 0x68, 0xbc, 0xa7, 0xf0, 0x7d, 0x81, 0xb6, 0x7f, 0x5d, 0x80, 0xa5,
+	0xbc, 0x24, 0xbd, 0xda, 0x1b, 0xb0, 0x94, 0x17, 0xa5, 0x13, 0x47, 0x55, 0x98, 0xb4, 0xb3, 0x02,
+	0x55, 0x0a, 0xf9, 0x10, 0x1a, 0xb2, 0xe9, 0xef, 0x06, 0x8a, 0x53, 0x3e, 0x5d, 0xce, 0xae, 0x8b,
+	0x53, 0xf7, 0x32, 0x90, 0xf5, 0x08, 0x56, 0xb4, 0xf9, 0xee, 0xb4, 0xda, 0x6a, 0x43, 0x2c, 0x6b,
+	0x82, 0x83, 0x09, 0xed, 0xbf, 0x80, 0x6e, 0x8a, 0xda, 0xba, 0x90, 0xc8, 0xf4, 0x50, 0x76, 0x26,
+	0x8c, 0xdd, 0x0c, 0x02, 0x2a, 0x4f, 0x7b, 0xc9, 0x99, 0x35, 0x65, 0x3f, 0x86, 0x9b, 0x03, 0xc4,
+	0x95, 0x37, 0x3c, 0xae, 0x6b, 0x58, 0xc5, 0x6c, 0x11, 0x8a, 0x03, 0xe4, 0x4b, 0xe3, 0x8b, 0x8e,
+	0x18, 0x8a, 0x0d, 0x78, 0xc8, 0x90, 0x2f, 0xad, 0x2c, 0x3a, 0x72, 0x6c, 0x47, 0x50, 0xfe, 0x74,
+	0xb0, 0x2b, 0x32, 0x55, 0xb1, 0xa9, 0x55, 0x66, 0xab, 0xef, 0xd4, 0x86, 0x53, 0x96, 0xf0, 0x7e,
+	0x60, 0x7d, 0x06, 0x1d, 0x35, 0xe5, 0x9f, 0x78, 0xe1, 0x10, 0xb9, 0x11, 0x19, 0x61, 0x5f, 0x6d,
+	0xfd, 0xe6, 0x83, 0x9e, 0x0e, 0x43, 0x9a, 0xcf, 0xb6, 0x24, 0xe9, 0x4b, 0x0a, 0xa7, 0x3d, 0x9c,
+	0x44, 0xd9, 0x7f, 0x28, 0x40, 0x59, 0x5f, 0x6b, 0xe2, 0x6a, 0x0e, 0x28, 0x3e, 0x43, 0x54, 0x6f,
+	0x76, 0x0d, 0x89, 0xee, 0x9d, 0x1a, 0xb9, 0x24, 0xe2, 0x98, 0x24, 0x97, 0x65, 0x43, 0x61, 0x9f,
+	0x2a, 0xa4, 0xf8, 0x5c, 0xb5, 0x6a, 0x4d, 0xaa, 0xaa, 0x20, 0x81, 0x3f, 0x66, 0x42, 0x29, 0x79,
+	0x39, 0x56, 0x1d, 0x0d, 0x89, 0xc3, 0x65, 0xf8, 0xcd, 0x4b, 0x7e, 0x06, 0x14, 0x87, 0x6b, 0x4c,
+	0xe2, 0x90, 0xbb, 0x11, 0xc1, 0x21, 0xd7, 0xb7, 0x21, 0x48, 0x54, 0x5f, 0x60, 0xac, 0x35, 0xa8,
+	0x1c, 0x33, 0x57, 0x5a, 0x23, 0xd3, 0xd8, 0xe4, 0x86, 0xd6, 0x56, 0x3b, 0xe5, 0x63, 0x26, 0x07,
+	0xf6, 0xff, 0x16, 0x60, 0x41, 0x3d, 0xab, 0x88, 0x8e, 0x4d, 0x92, 0xbd, 0xcc, 0xa9, 0xe4, 0x58,
+	0x6a, 0xa5, 0x32, 0x16, 0x39, 0x16, 0x31, 0xe6, 0x6c, 0xac, 0xee, 0x60, 0x6d, 0xc4, 0xd9, 0x58,
+	0x5e, 0xbe, 0x6f, 0x42, 0x33, 0x4d, 0x82, 0xe4, 0xbc, 0x32, 0xa6, 0x91, 0x60, 0x25, 0xd9, 0xa5,
+	0x36, 0xd9, 0xff, 0x2a, 0x1a, 0x55, 0xc9, 0x93, 0x82, 0x4e, 0xd5, 0x75, 0x91, 0x28, 0x52, 0xf5,
+	0x45, 0x28, 0x0e, 0x93, 0xf4, 0x49, 0x0c, 0xad, 0xbb, 0xd0, 0xf4, 0x82, 0x00, 0x8b, 0xcf, 0xbd,
+	0xd1, 0x2e, 0x0e, 0x92, 0x00, 0x92, 0xc7, 0xda, 0xbf, 0x2d, 0x40, 0x6b, 0x9b, 0x44, 0x17, 0x9f,
+	0xe2, 0x11, 0xca, 0x44, 0x37, 0xa9, 0xa4, 0xce, 0x9e, 0xc4, 0x58, 0xd4, 0x60, 0xc7, 0x78, 0x84,
+	0xd4, 0xb1, 0x57, 0xbb, 0xae, 0x22, 0x10, 0xf2, 0xc8, 0x9b, 0xc9, 0xa4, 0x99, 0xdc, 0x50, 0x93,
+	0x07, 0xa2, 0x87, 0xbc, 0x02, 0x95, 0x00, 0x53, 0x37, 0x69, 0x1d, 0x37, 0x9c, 0x72, 0x80, 0xa9,
+	0x9c, 0xca, 0xd4, 0x1c, 0xf3, 0x39, 0x43, 0x16, 0x14, 0x46, 0x18, 0xb2, 0x0c, 0x0b, 0xe4, 0xf8,
+	0x98, 0x21, 0x2e, 0xd7, 0xaa, 0xe8, 0x68, 0x28, 0x09, 0xc1, 0x95, 0x4c, 0x08, 0x5e, 0x92, 0xf7,
+	0xda, 0xd3, 0xa7, 0x07, 0x3b, 0x67, 0x28, 0xe4, 0xe6, 0x06, 0xbe, 0x0f, 0x15, 0x83, 0xfa, 0x31,
+	0xc5, 0xd6, 0xdb, 0xd0, 0xdc, 0x0c, 0x82, 0xc1, 0x73, 0x2f, 0x32, 0xfe, 0xe8, 0x42, 0xb9, 0xbf,
+	0xbd, 0xdf, 0x57, 0x2e, 0x29, 0x0a, 0x03, 0x34, 0x28, 0x6e, 0xfc, 0x5d, 0xc4, 0x0f, 0x10, 0xa7,
+	0xd8, 0x4f, 0x6e, 0xfc, 0x3b, 0x50, 0xd6, 0x18, 0xf1, 0xe5, 0x58, 0x0d, 0xcd, 0x15, 0xa0, 0x41,
+	0xfb, 0x9f, 0xc0, 0xfa, 0x17, 0x91, 0xbb, 0x22, 0x55, 0x2a, 0x6a, 0x49, 0x6f, 0x43, 0xfb, 0x4c,
+	0x62, 0x5d, 0x95, 0xd4, 0x65, 0x96, 0xa1, 0xa5, 0x26, 0x54, 0xad, 0x23, 0x64, 0x1f, 0x42, 0x47,
+	0xa5, 0xda, 0x8a, 0xcf, 0x35, 0x58, 0x08, 0x1f, 0x26, 0xeb, 0x59, 0x72, 0xe4, 0xf8, 0xc1, 0xcf,
+	0x3a, 0xfa, 0x1a, 0xd3, 0xbd, 0x34, 0x6b, 0x17, 0x5a, 0x13, 0x0f, 0x9f, 0x96, 0x6e, 0xae, 0xce,
+	0x7e, 0x0f, 0xed, 0x2d, 0xaf, 0xab, 0x87, 0xd4, 0x75, 0xf3, 0x90, 0xba, 0xbe, 0x23, 0x1e, 0x52,
+	0xad, 0x1d, 0x68, 0xe6, 0x9f, 0x08, 0xad, 0x5b, 0x26, 0x33, 0x9e, 0xf1, 0x70, 0x78, 0x29, 0x9b,
+	0x5d, 0x68, 0x4d, 0xbc, 0x16, 0x1a, 0x7d, 0x66, 0x3f, 0x22, 0x5e, 0xca, 0xe8, 0x31, 0xd4, 0x32,
+	0xcf, 0x83, 0x56, 0x57, 0x31, 0x99, 0x7e, 0x31, 0xbc, 0x94, 0xc1, 0x36, 0x34, 0x72, 0x2f, 0x76,
+	0x56, 0x4f, 0xdb, 0x33, 0xe3, 0x19, 0xef, 0x52, 0x26, 0x5b, 0x50, 0xcb, 0x3c, 0x9c, 0x19, 0x2d,
+	0xa6, 0x5f, 0xe7, 0x7a, 0x2b, 0x33, 0x66, 0xf4, 0x6d, 0xb9, 0x0b, 0xad, 0x89, 0xd7, 0x34, 0xe3,
+	0x92, 0xd9, 0x8f, 0x6c, 0x97, 0x2a, 0xf3, 0x39, 0x34, 0xf3, 0xcd, 0x92, 0xcc, 0x12, 0x4d, 0xbf,
+	0x9d, 0xf5, 0x5e, 0x9d, 0x3d, 0xa9, 0xb5, 0xda, 0x81, 0x66, 0xfe, 0xd9, 0xcc, 0x30, 0x9b, 0xf9,
+	0x98, 0x76, 0xf5, 0x7a, 0xe7, 0x5e, 0xd0, 0xd2, 0xf5, 0x9e, 0xf5, 0xb0, 0x76, 0x29, 0xa3, 0x3d,
+	0x68, 0xe4, 0x3a, 0x0b, 0x66, 0xb9, 0x66, 0x75, 0x65, 0x7a, 0xb7, 0x66, 0xce, 0x69, 0xcb, 0x36,
+	0x01, 0x74, 0xc9, 0x1f, 0xe0, 0x30, 0x59, 0xb2, 0xa9, 0x56, 0x43, 0x6f, 0x65, 0xc6, 0x8c, 0x66,
+	0xf1, 0x18, 0x40, 0x55, 0xea, 0x01, 0x89, 0xb9, 0x75, 0xd3, 0x18, 0x34, 0xd1, 0x1e, 0xe8, 0x75,
+	0xa7, 0x27, 0xa6, 0x18, 0x20, 0x4a, 0xaf, 0xc3, 0xe0, 0x13, 0x80, 0xb4, 0x03, 0x60, 0x18, 0x4c,
+	0xf5, 0x04, 0x2e, 0xf5, 0xe6, 0x26, 0xd4, 0xb3, 0xf5, 0xbe, 0xa5, 0x6d, 0x9d, 0xd1, 0x03, 0xb8,
+	0x82, 0x45, 0x6b, 0xa2, 0x9e, 0xcb, 0x6f, 0xdb, 0xc9, 0x32, 0xaf, 0x37, 0x55, 0xd3, 0x59, 0x0f,
+	0xa1, 0x9e, 0x2d, 0xe4, 0x8c, 0x16, 0x33, 0x8a, 0xbb, 0x5e, 0xae, 0x98, 0xb3, 0x1e, 0x43, 0x33,
+	0x5f, 0xc4, 0x59, 0x99, 0x15, 0x9f, 0x2a, 0xed, 0x7a, 0xba, 0x29, 0x9c, 0x21, 0x7f, 0x0f, 0x20,
+	0x2d, 0xf6, 0x8c, 0xfb, 0xa6, 0xca, 0xbf, 0x09, 0xa9, 0xbb, 0xd0, 0x9a, 0x28, 0xe2, 0x8c, 0xc5,
+	0xb3, 0x6b, 0xbb, 0xab, 0xa2, 0x46, 0xa6, 0x24, 0x33, 0x5b, 0x70, 0xba, 0xa8, 0xeb, 0xad, 0xcc,
+	0x98, 0xd1, 0x1b, 0x60, 0x0b, 0x6a, 0x83, 0x69, 0x1e, 0x83, 0x4b, 0x79, 0xcc, 0xaa, 0xca, 0xde,
+	0x07, 0x48, 0x2f, 0x40, 0xe3, 0x85, 0xa9, 0x2b, 0xb1, 0xd7, 0x30, 0x8d, 0x7b, 0x45, 0xb7, 0x0d,
+	0x8d, 0x5c, 0xa7, 0xc5, 0x9c, 0xc4, 0x59, 0xed, 0x97, 0xab, 0xae, 0x93, 0x7c, 0x5b, 0xc2, 0xac,
+	0xe0, 0xcc, 0x66, 0xc5, 0x55, 0xfb, 0x38, 0x5b, 0x43, 0x9a, 0x1d, 0x34, 0xa3, 0xae, 0xfc, 0x81,
+	0x08, 0x95, 0xad, 0x13, 0x33, 0x11, 0x6a, 0x46, 0xf9, 0x78, 0x45, 0x84, 0x6a, 0xed, 0x9a, 0x12,
+	0x40, 0x97, 0x27, 0x2b, 0x99, 0x16, 0x67, 0xbe, 0x1c, 0xeb, 0xf5, 0x66, 0x4d, 0xe9, 0x75, 0xf9,
+	0x1c, 0xda, 0x53, 0xa5, 0x89, 0xb5, 0x9a, 0x3c, 0x9f, 0xcc, 0xac, 0x59, 0x2e, 0x55, 0x6b, 0x1f,
+	0x16, 0x27, 0x2b, 0x13, 0xeb, 0xb5, 0x64, 0x4f, 0xcc, 0xaa, 0x58, 0x2e, 0x65, 0xf5, 0x08, 0x2a,
+	0x26, 0xdb, 0xb4, 0xf4, 0x33, 0xd5, 0x44, 0xf6, 0x79, 0xe9, 0xa7, 0x0f, 0xe5, 0x96, 0x4f, 0x32,
+	0xb9, 0x74, 0xcb, 0x4f, 0xe4, 0x7b, 0x3d, 0xfd, 0xaa, 0x94, 0x50, 0x3e, 0x84, 0xb2, 0x4e, 0xe8,
+	0xac, 0xa5, 0xe4, 0xb0, 0x65, 0xf2, 0xbb, 0xab, 0x76, 0xd8, 0x2e, 0xe2, 0x99, 0x34, 0xcd, 0x08,
+	0x9d, 0xce, 0xdc, 0x7a, 0x2b, 0x33, 0x66, 0x92, 0xdb, 0xa2, 0x9e, 0x4d, 0xd4, 0xcc, 0x92, 0xce,
+	0x48, 0xde, 0x2e, 0xd3, 0x64, 0xeb, 0xfc, 0xbb, 0xef, 0x57, 0x5f, 0xf9, 0xfd, 0xf7, 0xab, 0xaf,
+	0xfc, 0xcf, 0x8b, 0xd5, 0xc2, 0x77, 0x2f, 0x56, 0x0b, 0xbf, 0x7b, 0xb1, 0x5a, 0xf8, 0xd3, 0x8b,
+	0xd5, 0xc2, 0xbf, 0xfd, 0xe7, 0x4f, 0xfc, 0xbf, 0x1c, 0x8d, 0x43, 0xd1, 0x77, 0xdf, 0x38, 0xc3,
+	0x94, 0x67, 0xa6, 0xa2, 0xd3, 0xa1, 0xfa, 0xd3, 0x5c, 0xe6, 0xbf, 0x74, 0x42, 0xcb, 0xa3, 0x05,
+	0x09, 0xbf, 0xf7, 0xd7, 0x01, 0x00, 0x2b, 0x87, 0x27, 0x57, 0x98, 0x27, 0x00, 0x00,
 }
 
 func (m *CreateContainerRequest) Marshal() (dAtA []byte, err error) {
@@ -4281,7 +4423,7 @@ func (m *StatsContainerResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
-func (m *WriteStreamRequest) Marshal() (dAtA []byte, err error) {
+func (m *ListProcessesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4291,12 +4433,12 @@ func (m *WriteStreamRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *WriteStreamRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *ListProcessesRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *WriteStreamRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ListProcessesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -4305,20 +4447,6 @@ func (m *WriteStreamRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Data) > 0 {
-		i -= len(m.Data)
-		copy(dAtA[i:], m.Data)
-		i = encodeVarintAgent(dAtA, i, uint64(len(m.Data)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.ExecId) > 0 {
-		i -= len(m.ExecId)
-		copy(dAtA[i:], m.ExecId)
-		i = encodeVarintAgent(dAtA, i, uint64(len(m.ExecId)))
-		i--
-		dAtA[i] = 0x12
-	}
 	if len(m.ContainerId) > 0 {
 		i -= len(m.ContainerId)
 		copy(dAtA[i:], m.ContainerId)
@@ -4329,7 +4457,7 @@ func (m *WriteStreamRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *WriteStreamResponse) Marshal() (dAtA []byte, err error) {
+func (m *GuestProcess) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4339,12 +4467,12 @@ func (m *WriteStreamResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *WriteStreamResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *GuestProcess) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *WriteStreamResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *GuestProcess) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -4353,61 +4481,70 @@ func (m *WriteStreamResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if m.Len != 0 {
-		i = encodeVarintAgent(dAtA, i, uint64(m.Len))
+	if m.StartTime != 0 {
+		i = encodeVarintAgent(dAtA, i, uint64(m.StartTime))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0x50
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *ReadStreamRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if m.Rss != 0 {
+		i = encodeVarintAgent(dAtA, i, uint64(m.Rss))
+		i--
+		dAtA[i] = 0x48
 	}
-	return dAtA[:n], nil
-}
-
-func (m *ReadStreamRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *ReadStreamRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i -= len(m.XXX_unrecognized)
-		copy(dAtA[i:], m.XXX_unrecognized)
+	if m.CpuTime != 0 {
+		i = encodeVarintAgent(dAtA, i, uint64(m.CpuTime))
+		i--
+		dAtA[i] = 0x40
 	}
-	if m.Len != 0 {
-		i = encodeVarintAgent(dAtA, i, uint64(m.Len))
+	if len(m.State) > 0 {
+		i -= len(m.State)
+		copy(dAtA[i:], m.State)
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.State)))
 		i--
-		dAtA[i] = 0x18
+		dAtA[i] = 0x3a
+	}
+	if len(m.User) > 0 {
+		i -= len(m.User)
+		copy(dAtA[i:], m.User)
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.User)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Uid != 0 {
+		i = encodeVarintAgent(dAtA, i, uint64(m.Uid))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Args) > 0 {
+		for iNdEx := len(m.Args) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Args[iNdEx])
+			copy(dAtA[i:], m.Args[iNdEx])
+			i = encodeVarintAgent(dAtA, i, uint64(len(m.Args[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
 	}
 	if len(m.ExecId) > 0 {
 		i -= len(m.ExecId)
 		copy(dAtA[i:], m.ExecId)
 		i = encodeVarintAgent(dAtA, i, uint64(len(m.ExecId)))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x1a
 	}
-	if len(m.ContainerId) > 0 {
-		i -= len(m.ContainerId)
-		copy(dAtA[i:], m.ContainerId)
-		i = encodeVarintAgent(dAtA, i, uint64(len(m.ContainerId)))
+	if m.Ppid != 0 {
+		i = encodeVarintAgent(dAtA, i, uint64(m.Ppid))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x10
+	}
+	if m.Pid != 0 {
+		i = encodeVarintAgent(dAtA, i, uint64(m.Pid))
+		i--
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *ReadStreamResponse) Marshal() (dAtA []byte, err error) {
+func (m *ListProcessesResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4417,12 +4554,12 @@ func (m *ReadStreamResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ReadStreamResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *ListProcessesResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ReadStreamResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ListProcessesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -4431,17 +4568,24 @@ func (m *ReadStreamResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Data) > 0 {
-		i -= len(m.Data)
-		copy(dAtA[i:], m.Data)
-		i = encodeVarintAgent(dAtA, i, uint64(len(m.Data)))
-		i--
-		dAtA[i] = 0xa
+	if len(m.Processes) > 0 {
+		for iNdEx := len(m.Processes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Processes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintAgent(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *CloseStdinRequest) Marshal() (dAtA []byte, err error) {
+func (m *WriteStreamRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4451,12 +4595,12 @@ func (m *CloseStdinRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *CloseStdinRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *WriteStreamRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *CloseStdinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *WriteStreamRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -4465,8 +4609,168 @@ func (m *CloseStdinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.ExecId) > 0 {
-		i -= len(m.ExecId)
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ExecId) > 0 {
+		i -= len(m.ExecId)
+		copy(dAtA[i:], m.ExecId)
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.ExecId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ContainerId) > 0 {
+		i -= len(m.ContainerId)
+		copy(dAtA[i:], m.ContainerId)
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.ContainerId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WriteStreamResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WriteStreamResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WriteStreamResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Len != 0 {
+		i = encodeVarintAgent(dAtA, i, uint64(m.Len))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ReadStreamRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReadStreamRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReadStreamRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Len != 0 {
+		i = encodeVarintAgent(dAtA, i, uint64(m.Len))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.ExecId) > 0 {
+		i -= len(m.ExecId)
+		copy(dAtA[i:], m.ExecId)
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.ExecId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ContainerId) > 0 {
+		i -= len(m.ContainerId)
+		copy(dAtA[i:], m.ContainerId)
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.ContainerId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ReadStreamResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReadStreamResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReadStreamResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintAgent(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CloseStdinRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CloseStdinRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CloseStdinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.ExecId) > 0 {
+		i -= len(m.ExecId)
 		copy(dAtA[i:], m.ExecId)
 		i = encodeVarintAgent(dAtA, i, uint64(len(m.ExecId)))
 		i--
@@ -6630,6 +6934,88 @@ func (m *StatsContainerResponse) Size() (n int) {
 	return n
 }
 
+func (m *ListProcessesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ContainerId)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *GuestProcess) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Pid != 0 {
+		n += 1 + sovAgent(uint64(m.Pid))
+	}
+	if m.Ppid != 0 {
+		n += 1 + sovAgent(uint64(m.Ppid))
+	}
+	l = len(m.ExecId)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			l = len(s)
+			n += 1 + l + sovAgent(uint64(l))
+		}
+	}
+	if m.Uid != 0 {
+		n += 1 + sovAgent(uint64(m.Uid))
+	}
+	l = len(m.User)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	l = len(m.State)
+	if l > 0 {
+		n += 1 + l + sovAgent(uint64(l))
+	}
+	if m.CpuTime != 0 {
+		n += 1 + sovAgent(uint64(m.CpuTime))
+	}
+	if m.Rss != 0 {
+		n += 1 + sovAgent(uint64(m.Rss))
+	}
+	if m.StartTime != 0 {
+		n += 1 + sovAgent(uint64(m.StartTime))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ListProcessesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Processes) > 0 {
+		for _, e := range m.Processes {
+			l = e.Size()
+			n += 1 + l + sovAgent(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *WriteStreamRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -7831,6 +8217,53 @@ func (this *StatsContainerResponse) String() string {
 	}, "")
 	return s
 }
+func (this *ListProcessesRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ListProcessesRequest{`,
+		`ContainerId:` + fmt.Sprintf("%v", this.ContainerId) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GuestProcess) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GuestProcess{`,
+		`Pid:` + fmt.Sprintf("%v", this.Pid) + `,`,
+		`Ppid:` + fmt.Sprintf("%v", this.Ppid) + `,`,
+		`ExecId:` + fmt.Sprintf("%v", this.ExecId) + `,`,
+		`Args:` + fmt.Sprintf("%v", this.Args) + `,`,
+		`Uid:` + fmt.Sprintf("%v", this.Uid) + `,`,
+		`User:` + fmt.Sprintf("%v", this.User) + `,`,
+		`State:` + fmt.Sprintf("%v", this.State) + `,`,
+		`CpuTime:` + fmt.Sprintf("%v", this.CpuTime) + `,`,
+		`Rss:` + fmt.Sprintf("%v", this.Rss) + `,`,
+		`StartTime:` + fmt.Sprintf("%v", this.StartTime) + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ListProcessesResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForProcesses := "[]*GuestProcess{"
+	for _, f := range this.Processes {
+		repeatedStringForProcesses += strings.Replace(f.String(), "GuestProcess", "GuestProcess", 1) + ","
+	}
+	repeatedStringForProcesses += "}"
+	s := strings.Join([]string{`&ListProcessesResponse{`,
+		`Processes:` + repeatedStringForProcesses + `,`,
+		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *WriteStreamRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -8358,6 +8791,7 @@ type AgentServiceService interface {
 	StatsContainer(ctx context.Context, req *StatsContainerRequest) (*StatsContainerResponse, error)
 	PauseContainer(ctx context.Context, req *PauseContainerRequest) (*types.Empty, error)
 	ResumeContainer(ctx context.Context, req *ResumeContainerRequest) (*types.Empty, error)
+	ListProcesses(ctx context.Context, req *ListProcessesRequest) (*ListProcessesResponse, error)
 	WriteStdin(ctx context.Context, req *WriteStreamRequest) (*WriteStreamResponse, error)
 	ReadStdout(ctx context.Context, req *ReadStreamRequest) (*ReadStreamResponse, error)
 	ReadStderr(ctx context.Context, req *ReadStreamRequest) (*ReadStreamResponse, error)
@@ -8457,12 +8891,19 @@ func RegisterAgentServiceService(srv *github_com_containerd_ttrpc.Server, svc Ag
 			}
 			return svc.ResumeContainer(ctx, &req)
 		},
-		"WriteStdin": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
-			var req WriteStreamRequest
+		"ListProcesses": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req ListProcessesRequest
 			if err := unmarshal(&req); err != nil {
 				return nil, err
 			}
-			return svc.WriteStdin(ctx, &req)
+			return svc.ListProcesses(ctx, &req)
+		},
+		"WriteStdin": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+			var req WriteStreamRequest
+			if err := unmarshal(&req); err != nil {
+				return nil, err
+			}
+			return svc.WriteStdin(ctx, &req)
 		},
 		"ReadStdout": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
 			var req ReadStreamRequest
@@ -8725,6 +9166,14 @@ func (c *agentServiceClient) ResumeContainer(ctx context.Context, req *ResumeCon
 	return &resp, nil
 }
 
+func (c *agentServiceClient) ListProcesses(ctx context.Context, req *ListProcessesRequest) (*ListProcessesResponse, error) {
+	var resp ListProcessesResponse
+	if err := c.client.Call(ctx, "grpc.AgentService", "ListProcesses", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 func (c *agentServiceClient) WriteStdin(ctx context.Context, req *WriteStreamRequest) (*WriteStreamResponse, error) {
 	var resp WriteStreamResponse
 	if err := c.client.Call(ctx, "grpc.AgentService", "WriteStdin", req, &resp); err != nil {
@@ -12451,6 +12900,467 @@ func (m *StatsContainerResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ListProcessesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAgent
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ListProcessesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ListProcessesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContainerId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContainerId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAgent(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GuestProcess) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAgent
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GuestProcess: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GuestProcess: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pid", wireType)
+			}
+			m.Pid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Pid |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ppid", wireType)
+			}
+			m.Ppid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Ppid |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExecId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExecId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uid", wireType)
+			}
+			m.Uid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Uid |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.User = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.State = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuTime", wireType)
+			}
+			m.CpuTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CpuTime |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rss", wireType)
+			}
+			m.Rss = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rss |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTime", wireType)
+			}
+			m.StartTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTime |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAgent(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ListProcessesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAgent
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ListProcessesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ListProcessesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Processes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Processes = append(m.Processes, &GuestProcess{})
+			if err := m.Processes[len(m.Processes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAgent(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *WriteStreamRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
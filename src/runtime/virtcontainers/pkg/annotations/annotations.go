@@ -146,6 +146,12 @@ const (
 	// EnableVCPUsPinning is a sandbox annotation that controls bundling between vCPU threads and CPUs
 	EnableVCPUsPinning = kataAnnotationsPrefix + "enable_vcpus_pinning"
 
+	// EnableNUMA is a sandbox annotation that maps the sandbox CPU set onto guest NUMA nodes
+	EnableNUMA = kataAnnotHypervisorPrefix + "enable_numa"
+
+	// EnableVTPM is a sandbox annotation that attaches a swtpm backed virtual TPM to the guest
+	EnableVTPM = kataAnnotHypervisorPrefix + "enable_vtpm"
+
 	//
 	//	Memory related annotations
 	//
@@ -209,6 +215,10 @@ const (
 	// BlockDeviceAIO specifies I/O mechanism to be used with VirtioBlock for qemu
 	BlockDeviceAIO = kataAnnotHypervisorPrefix + "block_device_aio"
 
+	// DrivePoolSize is a sandbox annotation that specifies the number of drives which can be
+	// plugged in a Firecracker VM at the same time, fixed when the VM boots.
+	DrivePoolSize = kataAnnotHypervisorPrefix + "drive_pool_size"
+
 	// DisableBlockDeviceUse  is a sandbox annotation that disallows a block device from being used.
 	DisableBlockDeviceUse = kataAnnotHypervisorPrefix + "disable_block_device_use"
 
@@ -244,6 +254,8 @@ const (
 const (
 	kataAnnotRuntimePrefix = kataConfAnnotationsPrefix + "runtime."
 
+	KataAnnotationRuntimePrefix = kataAnnotRuntimePrefix
+
 	// DisableGuestSeccomp is a sandbox annotation that determines if seccomp should be applied inside guest.
 	DisableGuestSeccomp = kataAnnotRuntimePrefix + "disable_guest_seccomp"
 
@@ -256,7 +268,9 @@ const (
 	// EnablePprof is a sandbox annotation that determines if pprof enabled.
 	EnablePprof = kataAnnotRuntimePrefix + "enable_pprof"
 
-	// Experimental is a sandbox annotation that determines if experimental features enabled.
+	// Experimental is a sandbox annotation that determines if experimental features enabled,
+	// on top of the ones of the configuration. It is only honoured when "experimental"
+	// matches enable_annotations.
 	Experimental = kataAnnotRuntimePrefix + "experimental"
 
 	// InterNetworkModel is a sandbox annotaion that determines how the VM should be connected to the
@@ -269,12 +283,29 @@ const (
 	// VfioMode is a sandbox annotation to specify how attached VFIO devices should be treated
 	// Overrides the runtime.vfio_mode parameter in the global configuration.toml
 	VfioMode = kataAnnotRuntimePrefix + "vfio_mode"
+
+	// GuestHooks is a sandbox annotation with the OCI hooks, in the JSON format of the
+	// "hooks" of the OCI spec, to run inside the guest for every container of the sandbox.
+	// It is only honoured when "guest_hooks" matches enable_annotations, and every hook path
+	// must match runtime.valid_guest_hook_paths.
+	GuestHooks = kataAnnotRuntimePrefix + "guest_hooks"
+
+	// GuestHookTimeout is a sandbox annotation with the timeout in seconds of the guest hooks
+	// which do not set one. Overrides the runtime.guest_hook_timeout parameter.
+	GuestHookTimeout = kataAnnotRuntimePrefix + "guest_hook_timeout"
+
+	// GuestHookDeliveryFailurePolicy is a sandbox annotation telling what happens to a container when
+	// a guest hook cannot be delivered to the guest. Overrides the
+	// runtime.guest_hook_delivery_failure_policy parameter.
+	GuestHookDeliveryFailurePolicy = kataAnnotRuntimePrefix + "guest_hook_delivery_failure_policy"
 )
 
 // Agent related annotations
 const (
 	kataAnnotAgentPrefix = kataConfAnnotationsPrefix + "agent."
 
+	KataAnnotationAgentPrefix = kataAnnotAgentPrefix
+
 	// KernelModules is the annotation key for passing the list of kernel
 	// modules and their parameters that will be loaded in the guest kernel.
 	// Semicolon separated list of kernel modules and their parameters.
@@ -306,6 +337,22 @@ const (
 
 	// ContainerResourcesSwapInBytes is a container annotation to specify the Resources.Memory.Swap
 	ContainerResourcesSwapInBytes = kataAnnotContainerResourcePrefix + "swap_in_bytes"
+
+	// ContainerResourcesDiskRateLimiterBwMaxRate is a container annotation to specify the
+	// bandwidth limit, in bits/sec, of the block device volumes of the container
+	ContainerResourcesDiskRateLimiterBwMaxRate = kataAnnotContainerResourcePrefix + "disk_rate_limiter_bw_max_rate"
+
+	// ContainerResourcesDiskRateLimiterBwOneTimeBurst is a container annotation to specify the
+	// bandwidth burst, in bits, of the block device volumes of the container
+	ContainerResourcesDiskRateLimiterBwOneTimeBurst = kataAnnotContainerResourcePrefix + "disk_rate_limiter_bw_one_time_burst"
+
+	// ContainerResourcesDiskRateLimiterOpsMaxRate is a container annotation to specify the
+	// operations limit, in ops/sec, of the block device volumes of the container
+	ContainerResourcesDiskRateLimiterOpsMaxRate = kataAnnotContainerResourcePrefix + "disk_rate_limiter_ops_max_rate"
+
+	// ContainerResourcesDiskRateLimiterOpsOneTimeBurst is a container annotation to specify the
+	// operations burst of the block device volumes of the container
+	ContainerResourcesDiskRateLimiterOpsOneTimeBurst = kataAnnotContainerResourcePrefix + "disk_rate_limiter_ops_one_time_burst"
 )
 
 const (
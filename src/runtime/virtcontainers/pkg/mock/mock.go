@@ -233,6 +233,10 @@ func (p *HybridVSockTTRPCMockImp) AddSwap(ctx context.Context, req *pb.AddSwapRe
 	return &gpb.Empty{}, nil
 }
 
+func (p *HybridVSockTTRPCMockImp) ListProcesses(ctx context.Context, req *pb.ListProcessesRequest) (*pb.ListProcessesResponse, error) {
+	return &pb.ListProcessesResponse{}, nil
+}
+
 func (p *HybridVSockTTRPCMockImp) GetVolumeStats(ctx context.Context, req *pb.VolumeStatsRequest) (*pb.VolumeStatsResponse, error) {
 	return &pb.VolumeStatsResponse{}, nil
 }
@@ -20,8 +20,11 @@
 package rootless
 
 import (
+	"bufio"
 	"context"
+	"fmt"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/opencontainers/runc/libcontainer/userns"
@@ -36,9 +39,9 @@ var (
 	// lock for the initRootless and isRootless variables
 	rLock sync.Mutex
 
-	// XDG_RUNTIME_DIR defines the base directory relative to
-	// which user-specific non-essential runtime files are stored.
-	rootlessDir = os.Getenv("XDG_RUNTIME_DIR")
+	// uidMapPath is the user ID mapping of the user namespace of the
+	// process.
+	uidMapPath = "/proc/self/uid_map"
 
 	rootlessLog = logrus.WithFields(logrus.Fields{
 		"source": "rootless",
@@ -46,6 +49,9 @@ var (
 
 	// IsRootless is declared this way for mocking in unit tests
 	IsRootless = isRootlessFunc
+
+	// IsUnprivileged is declared this way for mocking in unit tests
+	IsUnprivileged = isUnprivilegedFunc
 )
 
 func SetRootless(rootless bool) {
@@ -79,8 +85,50 @@ func isRootlessFunc() bool {
 	return *isRootless
 }
 
+// isUnprivilegedFunc states whether kata is being ran by an unprivileged
+// user, either a non-root user or the root user of a user namespace, e.g.,
+// the one of a rootless containerd. Unlike isRootlessFunc, this cannot be
+// overridden by the configuration.
+func isUnprivilegedFunc() bool {
+	return os.Geteuid() != 0 || userns.RunningInUserNS()
+}
+
+// hostUID returns the ID of the user running kata on the host. This is the
+// user the current user is mapped to, when running in a user namespace.
+func hostUID() int {
+	uid := os.Getuid()
+
+	f, err := os.Open(uidMapPath)
+	if err != nil {
+		return uid
+	}
+	defer f.Close()
+
+	// Each line of the mapping is "<id in namespace> <id outside> <count>".
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var inside, outside, count int
+		if _, err := fmt.Sscanf(strings.TrimSpace(scanner.Text()), "%d %d %d", &inside, &outside, &count); err != nil {
+			continue
+		}
+
+		if uid >= inside && uid < inside+count {
+			return outside + uid - inside
+		}
+	}
+
+	return uid
+}
+
 // GetRootlessDir returns the path to the location for rootless
-// container and sandbox storage
+// container and sandbox storage. This is XDG_RUNTIME_DIR, the base
+// directory relative to which user-specific non-essential runtime files
+// are stored, or the default runtime directory of the user on the host,
+// /run/user/<uid>, when it is not set.
 func GetRootlessDir() string {
-	return rootlessDir
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+
+	return fmt.Sprintf("/run/user/%d", hostUID())
 }
@@ -28,6 +28,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 
@@ -143,3 +144,23 @@ func getCurrentThreadNetNSPath() string {
 	// use the thread's net namespace since the thread is switching around
 	return fmt.Sprintf("/proc/%d/task/%d/ns/net", os.Getpid(), unix.Gettid())
 }
+
+// UserNamespaceSysProcAttr returns the attributes for a process to be run in
+// a new user namespace, as root of the namespace. The root user and group of
+// the namespace are mapped to the effective user and group running kata,
+// for the process to have no more privileges on the host than kata itself.
+//
+// The supplementary groups are kept, e.g., for the process to access
+// /dev/kvm through the kvm group, but cannot be changed in the namespace.
+func UserNamespaceSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER,
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Geteuid(), Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getegid(), Size: 1},
+		},
+		GidMappingsEnableSetgroups: false,
+	}
+}
@@ -6,7 +6,10 @@
 package rootless
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
 
 	"github.com/opencontainers/runc/libcontainer/userns"
@@ -34,3 +37,36 @@ func TestIsRootless(t *testing.T) {
 
 	isRootless = nil
 }
+
+func TestGetRootlessDir(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	assert.Equal("/run/user/1000", GetRootlessDir())
+
+	savedUIDMapPath := uidMapPath
+	defer func() {
+		uidMapPath = savedUIDMapPath
+	}()
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+
+	// Outside of a user namespace, the user IDs are not mapped.
+	uidMapPath = filepath.Join(t.TempDir(), "uid_map")
+	assert.Equal(fmt.Sprintf("/run/user/%d", os.Getuid()), GetRootlessDir())
+
+	// In a user namespace, the user is the one it is mapped to.
+	err := os.WriteFile(uidMapPath, []byte(fmt.Sprintf("%10d %10d %10d\n", os.Getuid(), 1000, 1)), 0600)
+	assert.NoError(err)
+	assert.Equal("/run/user/1000", GetRootlessDir())
+}
+
+func TestUserNamespaceSysProcAttr(t *testing.T) {
+	assert := assert.New(t)
+
+	attr := UserNamespaceSysProcAttr()
+	assert.NotZero(attr.Cloneflags & syscall.CLONE_NEWUSER)
+	assert.Equal([]syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Geteuid(), Size: 1}}, attr.UidMappings)
+	assert.Equal([]syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getegid(), Size: 1}}, attr.GidMappings)
+	assert.False(attr.GidMappingsEnableSetgroups)
+}
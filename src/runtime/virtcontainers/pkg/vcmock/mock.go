@@ -56,3 +56,11 @@ func (m *VCMock) CleanupContainer(ctx context.Context, sandboxID, containerID st
 	}
 	return fmt.Errorf("%s: %s (%+v): sandboxID: %v", mockErrorPrefix, getSelf(), m, sandboxID)
 }
+
+// RecoverSandbox implements the VC function of the same name.
+func (m *VCMock) RecoverSandbox(ctx context.Context, sandboxID string) (vc.VCSandbox, error) {
+	if m.RecoverSandboxFunc != nil {
+		return m.RecoverSandboxFunc(ctx, sandboxID)
+	}
+	return nil, fmt.Errorf("%s: %s (%+v): sandboxID: %v", mockErrorPrefix, getSelf(), m, sandboxID)
+}
@@ -197,6 +197,33 @@ func TestVCMockCleanupContainer(t *testing.T) {
 	assert.True(IsMockError(err))
 }
 
+func TestVCMockRecoverSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &VCMock{}
+	assert.Nil(m.RecoverSandboxFunc)
+
+	ctx := context.Background()
+	_, err := m.RecoverSandbox(ctx, testSandboxID)
+	assert.Error(err)
+	assert.True(IsMockError(err))
+
+	m.RecoverSandboxFunc = func(ctx context.Context, sandboxID string) (vc.VCSandbox, error) {
+		return &Sandbox{MockID: sandboxID}, nil
+	}
+
+	sandbox, err := m.RecoverSandbox(ctx, testSandboxID)
+	assert.NoError(err)
+	assert.Equal(testSandboxID, sandbox.ID())
+
+	// reset
+	m.RecoverSandboxFunc = nil
+
+	_, err = m.RecoverSandbox(ctx, testSandboxID)
+	assert.Error(err)
+	assert.True(IsMockError(err))
+}
+
 func TestVCMockForceCleanupContainer(t *testing.T) {
 	assert := assert.New(t)
 
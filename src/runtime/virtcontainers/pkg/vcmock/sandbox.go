@@ -16,6 +16,7 @@ import (
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/image"
 	pbTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
@@ -137,6 +138,14 @@ func (s *Sandbox) StatsContainer(ctx context.Context, contID string) (vc.Contain
 	return vc.ContainerStats{}, nil
 }
 
+// ListProcesses implements the VCSandbox function of the same name.
+func (s *Sandbox) ListProcesses(ctx context.Context, contID string) ([]*grpc.GuestProcess, error) {
+	if s.ListProcessesFunc != nil {
+		return s.ListProcessesFunc(contID)
+	}
+	return nil, nil
+}
+
 // PauseContainer implements the VCSandbox function of the same name.
 func (s *Sandbox) PauseContainer(ctx context.Context, contID string) error {
 	return nil
@@ -147,6 +156,22 @@ func (s *Sandbox) ResumeContainer(ctx context.Context, contID string) error {
 	return nil
 }
 
+// Freeze implements the VCSandbox function of the same name.
+func (s *Sandbox) Freeze(ctx context.Context, reclaimMemory bool) error {
+	if s.FreezeFunc != nil {
+		return s.FreezeFunc(reclaimMemory)
+	}
+	return nil
+}
+
+// Thaw implements the VCSandbox function of the same name.
+func (s *Sandbox) Thaw(ctx context.Context) error {
+	if s.ThawFunc != nil {
+		return s.ThawFunc()
+	}
+	return nil
+}
+
 // Status implements the VCSandbox function of the same name.
 func (s *Sandbox) Status() vc.SandboxStatus {
 	return vc.SandboxStatus{}
@@ -221,6 +246,16 @@ func (s *Sandbox) GetOOMEvent(ctx context.Context) (string, error) {
 	return "", nil
 }
 
+// SubscribeEvents implements the VCSandbox function of the same name.
+func (s *Sandbox) SubscribeEvents() (<-chan vc.Event, func()) {
+	if s.SubscribeEventsFunc != nil {
+		return s.SubscribeEventsFunc()
+	}
+	ch := make(chan vc.Event)
+	close(ch)
+	return ch, func() {}
+}
+
 // UpdateRuntimeMetrics implements the VCSandbox function of the same name.
 func (s *Sandbox) UpdateRuntimeMetrics() error {
 	if s.UpdateRuntimeMetricsFunc != nil {
@@ -262,6 +297,12 @@ func (s *Sandbox) GuestVolumeStats(ctx context.Context, path string) ([]byte, er
 func (s *Sandbox) ResizeGuestVolume(ctx context.Context, path string, size uint64) error {
 	return nil
 }
+func (s *Sandbox) UpdateVolumeRateLimiter(ctx context.Context, path string, rateLimiter *config.BlockRateLimiter) error {
+	return nil
+}
+func (s *Sandbox) SnapshotVolume(ctx context.Context, path, snapshotPath string) error {
+	return nil
+}
 
 func (s *Sandbox) PullImage(ctx context.Context, req *image.PullImageReq) (*image.PullImageResp, error) {
 	return nil, nil
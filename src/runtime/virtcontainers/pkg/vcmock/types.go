@@ -14,6 +14,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	pbTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
@@ -48,8 +49,11 @@ type Sandbox struct {
 	KillContainerFunc        func(contID string, signal syscall.Signal, all bool) error
 	StatusContainerFunc      func(contID string) (vc.ContainerStatus, error)
 	StatsContainerFunc       func(contID string) (vc.ContainerStats, error)
+	ListProcessesFunc        func(contID string) ([]*grpc.GuestProcess, error)
 	PauseContainerFunc       func(contID string) error
 	ResumeContainerFunc      func(contID string) error
+	FreezeFunc               func(reclaimMemory bool) error
+	ThawFunc                 func() error
 	StatusFunc               func() vc.SandboxStatus
 	EnterContainerFunc       func(containerID string, cmd types.Cmd) (vc.VCContainer, *vc.Process, error)
 	MonitorFunc              func() (chan error, error)
@@ -68,6 +72,7 @@ type Sandbox struct {
 	GetAgentMetricsFunc      func() (string, error)
 	StatsFunc                func() (vc.SandboxStats, error)
 	GetAgentURLFunc          func() (string, error)
+	SubscribeEventsFunc      func() (<-chan vc.Event, func())
 }
 
 // Container is a fake Container type used for testing
@@ -90,4 +95,5 @@ type VCMock struct {
 
 	CreateSandboxFunc    func(ctx context.Context, sandboxConfig vc.SandboxConfig, hookFunc func(context.Context) error) (vc.VCSandbox, error)
 	CleanupContainerFunc func(ctx context.Context, sandboxID, containerID string, force bool) error
+	RecoverSandboxFunc   func(ctx context.Context, sandboxID string) (vc.VCSandbox, error)
 }
@@ -10,6 +10,7 @@ package virtcontainers
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -78,8 +79,13 @@ type QemuState struct {
 	HotpluggedVCPUs      []hv.CPUDevice
 	HotpluggedMemory     int
 	VirtiofsDaemonPid    int
+	SwtpmPid             int
 	PCIeRootPort         int
 	HotplugVFIOOnRootBus bool
+	// VirtioMem is set when the memory is resized with the virtio-mem
+	// device rather than with DIMMs. It is nil in the states saved before
+	// it was recorded, see virtioMem.
+	VirtioMem *bool
 }
 
 // qemu is an Hypervisor interface implementation for the Linux qemu hypervisor.
@@ -87,6 +93,7 @@ type QemuState struct {
 type qemu struct {
 	arch           qemuArch
 	virtiofsDaemon VirtiofsDaemon
+	swtpm          *swtpm
 	ctx            context.Context
 	id             string
 	qemuConfig     govmmQemu.Config
@@ -101,6 +108,12 @@ type qemu struct {
 	memoryDumpFlag sync.Mutex
 	stopped        int32
 	mu             sync.Mutex
+	// stopping is set while StopVM runs, the daemons exiting then are
+	// expected to.
+	stopping int32
+	// qemuCapabilities are the features supported by the QEMU binary,
+	// nil until they are probed.
+	qemuCapabilities *govmmQemu.Capabilities
 }
 
 const (
@@ -117,13 +130,46 @@ const (
 
 	scsiControllerID         = "scsi0"
 	rngID                    = "rng0"
+	balloonID                = "balloon0"
 	fallbackFileBackedMemDir = "/dev/shm"
 
 	qemuStopSandboxTimeoutSecs = 15
 
+	qemuBalloonTimeoutSecs = 10
+
+	qemuDeviceDelTimeoutSecs = 30
+
+	// qemuBlockJobPollInterval is how often the progress of the block
+	// jobs is checked.
+	qemuBlockJobPollInterval = 500 * time.Millisecond
+
+	// maxQemuNodeNameSize is the maximum length of a QEMU block node name.
+	maxQemuNodeNameSize = 31
+
 	qomPathPrefix = "/machine/peripheral/"
 )
 
+// qemuCapabilitiesDir is the directory where the capabilities of the QEMU
+// binaries are cached, so that a binary is only probed once.
+var qemuCapabilitiesDir = "/run/kata-containers/qemu-capabilities"
+
+// qemuProbedDevices are the device drivers whose properties are probed.
+var qemuProbedDevices = []string{"virtio-blk-pci", "virtio-net-pci", "vhost-user-fs-pci"}
+
+const (
+	virtioMemDriver    = "virtio-mem-pci"
+	virtioMemID        = "virtiomem0"
+	virtioMemBackendID = "virtiomem"
+)
+
+// virtioMemResizeTimeout is how long the guest is waited for to plug or
+// unplug the memory requested through virtio-mem, and virtioMemPollInterval
+// how often the memory plugged is checked meanwhile.
+var (
+	virtioMemResizeTimeout = 10 * time.Second
+	virtioMemPollInterval  = 500 * time.Millisecond
+)
+
 // agnostic list of kernel parameters
 var defaultKernelParameters = []Param{
 	{"panic", "1"},
@@ -194,7 +240,11 @@ func (q *qemu) Capabilities(ctx context.Context) types.Capabilities {
 	span, _ := katatrace.Trace(ctx, q.Logger(), "Capabilities", qemuTracingTags, map[string]string{"sandbox_id": q.id})
 	defer span.End()
 
-	return q.arch.capabilities()
+	caps := q.arch.capabilities()
+	if q.config.BlockDeviceDriver != config.Nvdimm {
+		caps.SetBlockDeviceResizeSupport()
+	}
+	return caps
 }
 
 func (q *qemu) HypervisorConfig() HypervisorConfig {
@@ -306,7 +356,29 @@ func (q *qemu) memoryTopology() (govmmQemu.Memory, error) {
 	hostMemMb := q.config.DefaultMaxMemorySize
 	memMb := uint64(q.config.MemorySize)
 
-	return q.arch.memoryTopology(memMb, hostMemMb, uint8(q.config.MemSlots)), nil
+	memory := q.arch.memoryTopology(memMb, hostMemMb, uint8(q.config.MemSlots))
+	memory.NUMANodes = q.numaTopology()
+
+	return memory, nil
+}
+
+// numaTopology converts the sandbox guest NUMA nodes into their qemu
+// representation, binding each node memory to its host NUMA node.
+func (q *qemu) numaTopology() []govmmQemu.NUMANode {
+	var nodes []govmmQemu.NUMANode
+
+	for _, n := range q.config.NUMANodes {
+		node := govmmQemu.NUMANode{
+			Size:      fmt.Sprintf("%dM", n.MemorySize),
+			HostNodes: strconv.Itoa(n.HostNode),
+		}
+		if !n.GuestCPUs.IsEmpty() {
+			node.CPUs = strings.Split(n.GuestCPUs.String(), ",")
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes
 }
 
 func (q *qemu) qmpSocketPath(id string) (string, error) {
@@ -400,6 +472,13 @@ func (q *qemu) buildDevices(ctx context.Context, initrdPath string) ([]govmmQemu
 		devices, _ = q.arch.appendPVPanicDevice(devices)
 	}
 
+	if q.config.FreezeMemory > 0 {
+		devices, err = q.arch.appendBalloonDevice(ctx, devices)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	var ioThread *govmmQemu.IOThread
 	if q.config.BlockDeviceDriver == config.VirtioSCSI {
 		return q.arch.appendSCSIController(ctx, devices, q.config.EnableIOThreads)
@@ -483,11 +562,12 @@ func (q *qemu) createVirtiofsDaemon(sharedPath string) (VirtiofsDaemon, error) {
 
 	// default use virtiofsd
 	return &virtiofsd{
-		path:       q.config.VirtioFSDaemon,
-		sourcePath: sharedPath,
-		socketPath: virtiofsdSocketPath,
-		extraArgs:  q.config.VirtioFSExtraArgs,
-		cache:      q.config.VirtioFSCache,
+		path:          q.config.VirtioFSDaemon,
+		sourcePath:    sharedPath,
+		socketPath:    virtiofsdSocketPath,
+		extraArgs:     q.config.VirtioFSExtraArgs,
+		cache:         q.config.VirtioFSCache,
+		userNamespace: q.config.UserNamespace,
 	}, nil
 }
 
@@ -641,6 +721,9 @@ func (q *qemu) CreateVM(ctx context.Context, id string, network Network, hypervi
 		PFlash:         pflash,
 		PidFile:        filepath.Join(q.config.VMStorePath, q.id, "pid"),
 	}
+	if q.config.UserNamespace {
+		qemuConfig.SysProcAttr = rootless.UserNamespaceSysProcAttr()
+	}
 	if q.arch.guestProtection() == sevProtection {
 		sevConfig := sev.GuestPreAttestationConfig{
 			Proxy:         q.config.GuestPreAttestationProxy,
@@ -701,6 +784,23 @@ func (q *qemu) CreateVM(ctx context.Context, id string, network Network, hypervi
 		qemuConfig.Devices = q.arch.appendPCIeRootPortDevice(qemuConfig.Devices, hypervisorConfig.PCIeRootPort, memSize32bit, memSize64bit)
 	}
 
+	if q.config.EnableVTPM {
+		q.swtpm, err = newSwtpm(q.config, q.id, q.state.SwtpmPid)
+		if err != nil {
+			return err
+		}
+
+		qemuConfig.Devices, err = q.arch.appendTPMDevice(qemuConfig.Devices, q.swtpm.socketPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The syntax of the command line only depends on the configuration,
+	// never on the capabilities probed by a previous sandbox.
+	qemuConfig.JSONSyntax = q.config.EnableJSONSyntax
+	q.loadQemuCapabilities(qemuPath)
+
 	q.qemuConfig = qemuConfig
 
 	q.virtiofsDaemon, err = q.createVirtiofsDaemon(hypervisorConfig.SharedPath)
@@ -760,6 +860,51 @@ func (q *qemu) stopVirtiofsDaemon(ctx context.Context) (err error) {
 	return nil
 }
 
+func (q *qemu) setupSwtpm(ctx context.Context) error {
+	pid, err := q.swtpm.Start(ctx, func() {
+		q.onSwtpmQuit(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	q.state.SwtpmPid = pid
+
+	return nil
+}
+
+// onSwtpmQuit stops the VM when swtpm exits while the VM is running. swtpm
+// also exits once QEMU disconnects from it, on teardown or when QEMU itself
+// exits, which is left to the caller of StopVM.
+func (q *qemu) onSwtpmQuit(ctx context.Context) {
+	if atomic.LoadInt32(&q.stopping) != 0 || atomic.LoadInt32(&q.stopped) != 0 {
+		q.Logger().Info("swtpm exited on VM shutdown")
+		return
+	}
+
+	if pid := q.GetPids()[0]; pid == 0 || syscall.Kill(pid, 0) != nil {
+		q.Logger().Info("swtpm exited after QEMU")
+		return
+	}
+
+	q.Logger().Warn("swtpm exited while the VM is running, stopping the VM")
+	if err := q.StopVM(ctx, false); err != nil {
+		q.Logger().WithError(err).Error("failed to stop the VM after swtpm exited")
+	}
+}
+
+func (q *qemu) stopSwtpm(ctx context.Context) error {
+	if q.swtpm == nil || q.state.SwtpmPid == 0 {
+		return nil
+	}
+
+	if err := q.swtpm.Stop(ctx); err != nil {
+		return err
+	}
+	q.state.SwtpmPid = 0
+
+	return nil
+}
+
 func (q *qemu) getMemArgs() (bool, string, string, error) {
 	share := false
 	target := ""
@@ -793,10 +938,17 @@ func (q *qemu) getMemArgs() (bool, string, string, error) {
 }
 
 func (q *qemu) setupVirtioMem(ctx context.Context) error {
+	if q.qemuCapabilities != nil && !q.qemuCapabilities.HasDevice(virtioMemDriver) {
+		q.Logger().Warnf("%s is not supported, memory is resized with DIMMs", virtioMemDriver)
+		virtioMem := false
+		q.state.VirtioMem = &virtioMem
+		return nil
+	}
+
 	// backend memory size must be multiple of 4Mib
 	sizeMB := (int(q.config.DefaultMaxMemorySize) - int(q.config.MemorySize)) >> 2 << 2
 
-	share, target, memoryBack, err := q.getMemArgs()
+	share, target, _, err := q.getMemArgs()
 	if err != nil {
 		return err
 	}
@@ -816,20 +968,102 @@ func (q *qemu) setupVirtioMem(ctx context.Context) error {
 		}
 	}()
 
-	err = q.qmpMonitorCh.qmp.ExecMemdevAdd(q.qmpMonitorCh.ctx, memoryBack, "virtiomem", target, sizeMB, share, "virtio-mem-pci", "virtiomem0", addr, bridge.ID)
+	dev := govmmQemu.VirtioMemDevice{
+		ID:        virtioMemID,
+		MemDev:    virtioMemBackendID,
+		MemPath:   target,
+		Share:     share,
+		Size:      uint64(sizeMB) << utils.MibToBytesShift,
+		Bus:       bridge.ID,
+		Addr:      addr,
+		Transport: govmmQemu.TransportPCI,
+	}
+
+	err = q.qmpMonitorCh.qmp.ExecuteVirtioMemDeviceAdd(q.qmpMonitorCh.ctx, dev)
 	if err == nil {
-		q.Logger().Infof("Setup %dMB virtio-mem-pci success", sizeMB)
+		q.Logger().Infof("Setup %dMB %s success", sizeMB, virtioMemDriver)
+		virtioMem := true
+		q.state.VirtioMem = &virtioMem
 	} else {
 		help := ""
 		if strings.Contains(err.Error(), "Cannot allocate memory") {
 			help = ".  Please use command \"echo 1 > /proc/sys/vm/overcommit_memory\" handle it."
 		}
-		err = fmt.Errorf("Add %dMB virtio-mem-pci fail %s%s", sizeMB, err.Error(), help)
+		err = fmt.Errorf("Add %dMB %s fail %s%s", sizeMB, virtioMemDriver, err.Error(), help)
 	}
 
 	return err
 }
 
+// virtioMem returns true if the memory is resized with the virtio-mem
+// device. The VMs restored from a state which does not tell were set up
+// as configured.
+func (q *qemu) virtioMem() bool {
+	if q.state.VirtioMem == nil {
+		return q.config.VirtioMem
+	}
+
+	return *q.state.VirtioMem
+}
+
+// resizeVirtioMem requests the guest to resize its memory to reqMemMB with
+// the virtio-mem device, and waits for a while for the guest to plug or
+// unplug the memory. The memory plugged by then is returned.
+func (q *qemu) resizeVirtioMem(ctx context.Context, reqMemMB uint32) (uint32, error) {
+	qmp := q.qmpMonitorCh.qmp
+
+	info, err := qmp.ExecuteQueryVirtioMem(q.qmpMonitorCh.ctx, virtioMemID)
+	if err != nil {
+		return q.GetTotalMemoryMB(ctx), err
+	}
+
+	var size uint64
+	if reqMemMB > q.config.MemorySize {
+		size = uint64(reqMemMB-q.config.MemorySize) << utils.MibToBytesShift
+	}
+
+	// The memory is plugged by blocks, the requested size is rounded up
+	// to the next block.
+	if info.BlockSize != 0 {
+		size = (size + info.BlockSize - 1) / info.BlockSize * info.BlockSize
+	}
+	if size > info.MaxSize {
+		size = info.MaxSize
+	}
+
+	q.Logger().WithField("hotplug", "memory").Debugf("resize virtio-mem memory from %dMB to %dMB",
+		info.Size>>utils.MibToBytesShift, size>>utils.MibToBytesShift)
+
+	if err := qmp.ExecuteVirtioMemSetRequestedSize(q.qmpMonitorCh.ctx, virtioMemID, size); err != nil {
+		return q.GetTotalMemoryMB(ctx), err
+	}
+
+	waitCtx, cancel := context.WithTimeout(q.qmpMonitorCh.ctx, virtioMemResizeTimeout)
+	defer cancel()
+
+	info, err = qmp.WaitForVirtioMemSize(waitCtx, virtioMemID, size, virtioMemPollInterval)
+	if err != nil && (err != context.DeadlineExceeded || info.ID == "") {
+		return q.GetTotalMemoryMB(ctx), err
+	}
+
+	q.state.HotpluggedMemory = int(info.Size >> utils.MibToBytesShift)
+	currentMemory := q.GetTotalMemoryMB(ctx)
+
+	// The guest keeps plugging the memory requested in the background, a
+	// slow guest is not an error.
+	if info.Size < size {
+		q.Logger().WithField("hotplug", "memory").Warnf("guest only plugged %dMB of the %dMB of memory requested yet",
+			info.Size>>utils.MibToBytesShift, size>>utils.MibToBytesShift)
+	}
+	// The guest may be unable to unplug the memory it is using, which is
+	// not an error either: the memory is unplugged once released.
+	if info.Size > size {
+		q.Logger().WithField("hotplug", "memory").Warnf("guest memory only shrunk to %dMB, %dMB requested", currentMemory, reqMemMB)
+	}
+
+	return currentMemory, nil
+}
+
 func (q *qemu) AttestVM(ctx context.Context) error {
 	if q.arch.guestProtection() != sevProtection {
 		return nil
@@ -950,6 +1184,19 @@ func (q *qemu) StartVM(ctx context.Context, timeout int) error {
 
 	}
 
+	if q.swtpm != nil {
+		if err = q.setupSwtpm(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				if shutdownErr := q.stopSwtpm(ctx); shutdownErr != nil {
+					q.Logger().WithError(shutdownErr).Warn("failed to stop swtpm")
+				}
+			}
+		}()
+	}
+
 	var strErr string
 	strErr, err = govmmQemu.LaunchQemu(q.qemuConfig, newQMPLogger())
 	if err != nil {
@@ -1048,9 +1295,73 @@ func (q *qemu) waitVM(ctx context.Context, timeout int) error {
 		return err
 	}
 
+	q.probeQemuCapabilities()
+
 	return nil
 }
 
+// qemuCapabilitiesPath returns the file caching the capabilities of the
+// QEMU binary.
+func qemuCapabilitiesPath(binary string) string {
+	hash := sha256.Sum256([]byte(binary))
+	return filepath.Join(qemuCapabilitiesDir, hex.EncodeToString(hash[:])+".json")
+}
+
+// loadQemuCapabilities loads the capabilities of the QEMU binary, when they
+// were probed by a previous sandbox.
+func (q *qemu) loadQemuCapabilities(binary string) {
+	caps, err := govmmQemu.LoadCapabilities(qemuCapabilitiesPath(binary), binary)
+	if err != nil {
+		q.Logger().WithError(err).WithField("qemu", binary).Warn("failed to load the QEMU capabilities")
+		return
+	}
+
+	q.qemuCapabilities = caps
+}
+
+// probeQemuCapabilities probes the capabilities of the running QEMU and
+// caches them for the next sandboxes, unless they are already known. A
+// failure is not an error, the features are then used as configured.
+func (q *qemu) probeQemuCapabilities() {
+	if q.qemuCapabilities != nil {
+		return
+	}
+
+	caps, err := govmmQemu.ProbeCapabilities(q.qmpMonitorCh.ctx, q.qmpMonitorCh.qmp, qemuProbedDevices)
+	if err != nil {
+		q.Logger().WithError(err).Warn("failed to probe the QEMU capabilities")
+		return
+	}
+	q.qemuCapabilities = caps
+
+	binary := q.qemuConfig.Path
+	if err := caps.Save(qemuCapabilitiesPath(binary), binary); err != nil {
+		q.Logger().WithError(err).WithField("qemu", binary).Warn("failed to cache the QEMU capabilities")
+	}
+}
+
+// checkStreamNetdev checks that QEMU supports the stream netdevs the passt
+// endpoints are connected to, when its capabilities are known.
+func (q *qemu) checkStreamNetdev() error {
+	if q.qemuCapabilities == nil || q.qemuCapabilities.HasNetdev(string(govmmQemu.STREAM)) {
+		return nil
+	}
+
+	return fmt.Errorf("QEMU %s does not support stream netdevs, the passt network model requires QEMU 7.2 or later", q.qemuConfig.Path)
+}
+
+// blockDeviceAIO returns the AIO mode of the hotplugged block devices, the
+// configured one unless QEMU is known not to support it.
+func (q *qemu) blockDeviceAIO() govmmQemu.BlockDeviceAIO {
+	aio := govmmQemu.BlockDeviceAIO(q.config.BlockDeviceAIO)
+	if aio == "" || q.qemuCapabilities == nil || q.qemuCapabilities.HasBlockdevOptionValue("file", "aio", string(aio)) {
+		return aio
+	}
+
+	q.Logger().WithField("aio", aio).Warn("QEMU does not support the block device AIO mode, falling back to threads")
+	return govmmQemu.Threads
+}
+
 // StopVM will stop the Sandbox's VM.
 func (q *qemu) StopVM(ctx context.Context, waitOnly bool) (err error) {
 	q.mu.Lock()
@@ -1064,6 +1375,9 @@ func (q *qemu) StopVM(ctx context.Context, waitOnly bool) (err error) {
 		return nil
 	}
 
+	atomic.StoreInt32(&q.stopping, 1)
+	defer atomic.StoreInt32(&q.stopping, 0)
+
 	defer func() {
 		q.cleanupVM()
 		if err == nil {
@@ -1084,6 +1398,31 @@ func (q *qemu) StopVM(ctx context.Context, waitOnly bool) (err error) {
 		}
 	}
 
+	// QEMU writes its pid file once started and removes it when it exits.
+	// Without it, there is no QMP socket to send QUIT to and only the
+	// daemons started along with QEMU are left to stop.
+	if _, err := os.Stat(q.qemuConfig.PidFile); err == nil {
+		if err := q.stopQemu(waitOnly); err != nil {
+			return err
+		}
+	} else {
+		q.Logger().Info("QEMU was not started")
+	}
+
+	if q.config.SharedFS == config.VirtioFS || q.config.SharedFS == config.VirtioFSNydus {
+		if err := q.stopVirtiofsDaemon(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := q.stopSwtpm(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (q *qemu) stopQemu(waitOnly bool) error {
 	if err := q.qmpSetup(); err != nil {
 		return err
 	}
@@ -1094,24 +1433,12 @@ func (q *qemu) StopVM(ctx context.Context, waitOnly bool) (err error) {
 			return errors.New("cannot determine QEMU PID")
 		}
 
-		pid := pids[0]
-
-		err := utils.WaitLocalProcess(pid, qemuStopSandboxTimeoutSecs, syscall.Signal(0), q.Logger())
-		if err != nil {
-			return err
-		}
-	} else {
-		err := q.qmpMonitorCh.qmp.ExecuteQuit(q.qmpMonitorCh.ctx)
-		if err != nil {
-			q.Logger().WithError(err).Error("Fail to execute qmp QUIT")
-			return err
-		}
+		return utils.WaitLocalProcess(pids[0], qemuStopSandboxTimeoutSecs, syscall.Signal(0), q.Logger())
 	}
 
-	if q.config.SharedFS == config.VirtioFS || q.config.SharedFS == config.VirtioFSNydus {
-		if err := q.stopVirtiofsDaemon(ctx); err != nil {
-			return err
-		}
+	if err := q.qmpMonitorCh.qmp.ExecuteQuit(q.qmpMonitorCh.ctx); err != nil {
+		q.Logger().WithError(err).Error("Fail to execute qmp QUIT")
+		return err
 	}
 
 	return nil
@@ -1147,7 +1474,8 @@ func (q *qemu) cleanupVM() error {
 		}
 	}
 
-	if rootless.IsRootless() {
+	// No user is created for a VMM run in a user namespace.
+	if rootless.IsRootless() && !q.config.UserNamespace {
 		if _, err := user.Lookup(q.config.User); err != nil {
 			q.Logger().WithError(err).WithFields(
 				logrus.Fields{
@@ -1196,12 +1524,15 @@ func (q *qemu) qmpSetup() error {
 		return nil
 	}
 
+	// The events are delivered on an unbuffered channel rather than
+	// through a subscription, which drops the events once its buffer is
+	// full: a GUEST_PANICKED event must not be missed.
 	events := make(chan govmmQemu.QMPEvent)
-	go q.loopQMPEvent(events)
 
 	cfg := govmmQemu.QMPConfig{
-		Logger:  newQMPLogger(),
-		EventCh: events,
+		Logger:     newQMPLogger(),
+		EventCh:    events,
+		RecordPath: q.config.QMPRecordPath,
 	}
 
 	// Auto-closed by QMPStart().
@@ -1213,6 +1544,10 @@ func (q *qemu) qmpSetup() error {
 		return err
 	}
 
+	// The events channel is closed with the connection to QMP, the QMP
+	// loop waits for this goroutine to receive the first events.
+	go q.loopQMPEvent(events)
+
 	err = qmp.ExecuteQMPCapabilities(q.qmpMonitorCh.ctx)
 	if err != nil {
 		qmp.Shutdown()
@@ -1225,16 +1560,41 @@ func (q *qemu) qmpSetup() error {
 	return nil
 }
 
-func (q *qemu) loopQMPEvent(event chan govmmQemu.QMPEvent) {
-	for e := range event {
+func (q *qemu) loopQMPEvent(events chan govmmQemu.QMPEvent) {
+	for e := range events {
 		q.Logger().WithField("event", e).Debug("got QMP event")
-		if e.Name == "GUEST_PANICKED" {
+		if e.Name == govmmQemu.EventGuestPanicked {
+			var panicked govmmQemu.GuestPanickedEvent
+			if err := e.Decode(&panicked); err != nil {
+				q.Logger().WithError(err).Warn("failed to decode GUEST_PANICKED event")
+			}
+			q.Logger().WithField("action", panicked.Action).Error("guest panicked")
 			go q.handleGuestPanic()
 		}
 	}
 	q.Logger().Infof("QMP event channel closed")
 }
 
+// deviceDel unplugs the device devID and waits for the guest to release it,
+// as notified by the DEVICE_DELETED event of the device.
+func (q *qemu) deviceDel(devID string) error {
+	deleted := q.qmpMonitorCh.qmp.SubscribeEvents(govmmQemu.DeviceDeletedFilter(devID))
+	defer deleted.Close()
+
+	if err := q.qmpMonitorCh.qmp.ExecuteDeviceDelNoWait(q.qmpMonitorCh.ctx, devID); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(q.qmpMonitorCh.ctx, qemuDeviceDelTimeoutSecs*time.Second)
+	defer cancel()
+
+	if _, err := deleted.Wait(ctx); err != nil {
+		return fmt.Errorf("device %s was not released by the guest: %w", devID, err)
+	}
+
+	return nil
+}
+
 func (q *qemu) handleGuestPanic() {
 	if err := q.dumpGuestMemory(q.config.GuestMemoryDumpPath); err != nil {
 		q.Logger().WithError(err).Error("failed to dump guest memory")
@@ -1365,25 +1725,11 @@ func (q *qemu) qmpShutdown() {
 func (q *qemu) hotplugAddBlockDevice(ctx context.Context, drive *config.BlockDrive, op Operation, devID string) (err error) {
 	// drive can be a pmem device, in which case it's used as backing file for a nvdimm device
 	if q.config.BlockDeviceDriver == config.Nvdimm || drive.Pmem {
-		var blocksize int64
-		file, err := os.Open(drive.File)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
-
-		st, err := file.Stat()
+		blocksize, err := blockDeviceSize(drive.File)
 		if err != nil {
 			return fmt.Errorf("failed to get information from nvdimm device %v: %v", drive.File, err)
 		}
 
-		// regular files do not support syscall BLKGETSIZE64
-		if st.Mode().IsRegular() {
-			blocksize = st.Size()
-		} else if _, _, err := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), unix.BLKGETSIZE64, uintptr(unsafe.Pointer(&blocksize))); err != 0 {
-			return err
-		}
-
 		if err = q.qmpMonitorCh.qmp.ExecuteNVDIMMDeviceAdd(q.qmpMonitorCh.ctx, drive.ID, drive.File, blocksize, &drive.Pmem); err != nil {
 			q.Logger().WithError(err).Errorf("Failed to add NVDIMM device %s", drive.File)
 			return err
@@ -1397,15 +1743,45 @@ func (q *qemu) hotplugAddBlockDevice(ctx context.Context, drive *config.BlockDri
 		ID:       drive.ID,
 		File:     drive.File,
 		ReadOnly: drive.ReadOnly,
-		AIO:      govmmQemu.BlockDeviceAIO(q.config.BlockDeviceAIO),
+		AIO:      q.blockDeviceAIO(),
+	}
+
+	// Only the drives with I/O limits get their own throttle group, the
+	// limits of the others are set on the guest device if they are
+	// changed later on through UpdateBlockRateLimiter.
+	if limits := qemuThrottleLimits(q.blockRateLimiter(drive)); limits != (govmmQemu.ThrottleLimits{}) {
+		throttleGroup := throttleGroupID(drive.ID)
+		if err = q.qmpMonitorCh.qmp.ExecuteThrottleGroupAdd(q.qmpMonitorCh.ctx, throttleGroup, limits); err != nil {
+			return err
+		}
+
+		defer func() {
+			if err != nil {
+				q.qmpMonitorCh.qmp.ExecuteObjectDel(q.qmpMonitorCh.ctx, throttleGroup)
+				drive.ThrottleGroup = ""
+			}
+		}()
+
+		qblkDevice.ThrottleGroup = throttleGroup
+		qblkDevice.ThrottledNodeName = throttledNodeID(drive.ID)
+		drive.ThrottleGroup = throttleGroup
 	}
 
 	if drive.Swap {
 		err = q.qmpMonitorCh.qmp.ExecuteBlockdevAddWithDriverCache(q.qmpMonitorCh.ctx, "file", &qblkDevice, false, false)
-	} else if q.config.BlockDeviceCacheSet {
-		err = q.qmpMonitorCh.qmp.ExecuteBlockdevAddWithCache(q.qmpMonitorCh.ctx, &qblkDevice, q.config.BlockDeviceCacheDirect, q.config.BlockDeviceCacheNoflush)
 	} else {
-		err = q.qmpMonitorCh.qmp.ExecuteBlockdevAdd(q.qmpMonitorCh.ctx, &qblkDevice)
+		// image files, direct assigned file volumes for instance,
+		// need the file driver rather than the host_device one.
+		driver := "host_device"
+		if st, serr := os.Stat(drive.File); serr == nil && st.Mode().IsRegular() {
+			driver = "file"
+		}
+
+		if q.config.BlockDeviceCacheSet {
+			err = q.qmpMonitorCh.qmp.ExecuteBlockdevAddWithDriverCache(q.qmpMonitorCh.ctx, driver, &qblkDevice, q.config.BlockDeviceCacheDirect, q.config.BlockDeviceCacheNoflush)
+		} else {
+			err = q.qmpMonitorCh.qmp.ExecuteBlockdevAddWithDriver(q.qmpMonitorCh.ctx, driver, &qblkDevice)
+		}
 	}
 	if err != nil {
 		return err
@@ -1586,11 +1962,134 @@ func (q *qemu) hotplugBlockDevice(ctx context.Context, drive *config.BlockDrive,
 		}
 	}
 
-	if err := q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, devID); err != nil {
+	if err := q.deviceDel(devID); err != nil {
+		return err
+	}
+
+	if err := q.qmpMonitorCh.qmp.ExecuteBlockdevDel(q.qmpMonitorCh.ctx, drive.ID); err != nil {
 		return err
 	}
 
-	return q.qmpMonitorCh.qmp.ExecuteBlockdevDel(q.qmpMonitorCh.ctx, drive.ID)
+	// The throttle group can only be removed once no block node uses it
+	// anymore. Failing to remove it only leaks an empty object in QEMU.
+	if drive.ThrottleGroup != "" {
+		if err := q.qmpMonitorCh.qmp.ExecuteObjectDel(q.qmpMonitorCh.ctx, drive.ThrottleGroup); err != nil {
+			q.Logger().WithError(err).WithField("drive", drive.ID).Warn("Failed to remove throttle group")
+		}
+		drive.ThrottleGroup = ""
+	}
+
+	return nil
+}
+
+// throttleGroupID returns the identifier of the QEMU throttle group
+// limiting the I/O of the drive driveID.
+func throttleGroupID(driveID string) string {
+	return "throttle-" + driveID
+}
+
+// throttledNodeID returns the node name of the block node of the drive
+// driveID, under its throttle filter. Snapshots and block jobs act on this
+// node so that the I/O limits of the drive keep applying.
+func throttledNodeID(driveID string) string {
+	return utils.MakeNameID("fmt", driveID, maxQemuNodeNameSize)
+}
+
+// blockDeviceSize returns the size in bytes of the file or block device
+// path.
+func blockDeviceSize(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	st, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	// regular files do not support syscall BLKGETSIZE64
+	if st.Mode().IsRegular() {
+		return st.Size(), nil
+	}
+
+	var size int64
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), unix.BLKGETSIZE64, uintptr(unsafe.Pointer(&size))); errno != 0 {
+		return 0, errno
+	}
+
+	return size, nil
+}
+
+// blockRateLimiter returns the I/O limits to apply to drive, the ones set
+// for the drive itself if any, the sandbox wide ones otherwise.
+func (q *qemu) blockRateLimiter(drive *config.BlockDrive) *config.BlockRateLimiter {
+	if drive.RateLimiter != nil {
+		return drive.RateLimiter
+	}
+
+	return &config.BlockRateLimiter{
+		BwMaxRate:       q.config.DiskRateLimiterBwMaxRate,
+		BwOneTimeBurst:  q.config.DiskRateLimiterBwOneTimeBurst,
+		OpsMaxRate:      q.config.DiskRateLimiterOpsMaxRate,
+		OpsOneTimeBurst: q.config.DiskRateLimiterOpsOneTimeBurst,
+	}
+}
+
+// addColdPlugBlockDevice appends the block device of drive to the command
+// line. The device gets the same id as the hotplugged ones, and its I/O is
+// limited the same way, the throttle group being recorded in drive.
+func (q *qemu) addColdPlugBlockDevice(ctx context.Context, drive *config.BlockDrive) error {
+	devices, err := q.arch.appendBlockDevice(ctx, q.qemuConfig.Devices, *drive)
+	if err != nil {
+		return err
+	}
+
+	last := len(devices) - 1
+	if blkdev, ok := devices[last].(govmmQemu.BlockDevice); ok {
+		blkdev.DevID = "virtio-" + drive.ID
+
+		if limits := qemuThrottleLimits(q.blockRateLimiter(drive)); limits != (govmmQemu.ThrottleLimits{}) {
+			blkdev.ThrottleGroup = throttleGroupID(drive.ID)
+			blkdev.ThrottledNodeName = throttledNodeID(drive.ID)
+			blkdev.ThrottleLimits = limits
+			drive.ThrottleGroup = blkdev.ThrottleGroup
+		}
+
+		devices[last] = blkdev
+	}
+
+	q.qemuConfig.Devices = devices
+	return nil
+}
+
+// qemuThrottleLimits converts rl into QEMU throttle group limits. The
+// bandwidth is converted from bits to bytes the same way Cloud Hypervisor
+// does it. QEMU has no one time burst, the burst is approximated by raising
+// the max rate for one second.
+func qemuThrottleLimits(rl *config.BlockRateLimiter) govmmQemu.ThrottleLimits {
+	var limits govmmQemu.ThrottleLimits
+
+	if rl.IsEmpty() {
+		return limits
+	}
+
+	if rl.BwMaxRate > 0 {
+		limits.BpsTotal = int64(utils.RevertBytes(uint64(rl.BwMaxRate / 8)))
+		if rl.BwOneTimeBurst > 0 {
+			limits.BpsTotalMax = int64(utils.RevertBytes(uint64((rl.BwMaxRate + rl.BwOneTimeBurst) / 8)))
+		}
+	}
+
+	if rl.OpsMaxRate > 0 {
+		limits.IopsTotal = rl.OpsMaxRate
+		if rl.OpsOneTimeBurst > 0 {
+			limits.IopsTotalMax = rl.OpsMaxRate + rl.OpsOneTimeBurst
+		}
+	}
+
+	return limits
 }
 
 func (q *qemu) hotplugVhostUserDevice(ctx context.Context, vAttr *config.VhostUserDeviceAttrs, op Operation) error {
@@ -1617,7 +2116,7 @@ func (q *qemu) hotplugVhostUserDevice(ctx context.Context, vAttr *config.VhostUs
 			}
 		}
 
-		if err := q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, devID); err != nil {
+		if err := q.deviceDel(devID); err != nil {
 			return err
 		}
 
@@ -1779,10 +2278,60 @@ func (q *qemu) hotplugVFIODevice(ctx context.Context, device *config.VFIODev, op
 			}
 		}
 
-		return q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, devID)
+		return q.deviceDel(devID)
 	}
 }
 
+func (q *qemu) hotplugVDPADevice(ctx context.Context, device *config.VDPADev, op Operation) (err error) {
+	if err = q.qmpSetup(); err != nil {
+		return err
+	}
+
+	devID := device.ID
+
+	if op == AddDevice {
+		q.Logger().WithFields(logrus.Fields{
+			"dev-id":    devID,
+			"host-path": device.HostPath,
+		}).Info("Start hot-plug vDPA device")
+
+		addr, bridge, err := q.arch.addDeviceToBridge(ctx, devID, types.PCI)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err != nil {
+				q.arch.removeDeviceFromBridge(devID)
+			}
+		}()
+
+		bridgeSlot, err := types.PciSlotFromInt(bridge.Addr)
+		if err != nil {
+			return err
+		}
+		devSlot, err := types.PciSlotFromString(addr)
+		if err != nil {
+			return err
+		}
+		device.PCIPath, err = types.PciPathFromSlots(bridgeSlot, devSlot)
+		if err != nil {
+			return err
+		}
+
+		err = q.qmpMonitorCh.qmp.ExecutePCIVhostVDPADeviceAdd(q.qmpMonitorCh.ctx, devID, device.HostPath, addr, bridge.ID)
+		return err
+	}
+
+	q.Logger().WithField("dev-id", devID).Info("Start hot-unplug vDPA device")
+
+	if err := q.arch.removeDeviceFromBridge(devID); err != nil {
+		return err
+	}
+
+	return q.deviceDel(devID)
+}
+
 func (q *qemu) hotAddNetDevice(name, hardAddr string, VMFds, VhostFds []*os.File) error {
 	var (
 		VMFdNames    []string
@@ -1811,6 +2360,9 @@ func (q *qemu) hotplugNetDevice(ctx context.Context, endpoint Endpoint, op Opera
 		return err
 	}
 	var tap TapInterface
+	var vhostVDPAPath string
+	var passtSocketPath string
+	queues := int(q.config.NumVCPUs)
 
 	switch endpoint.Type() {
 	case VethEndpointType:
@@ -1819,13 +2371,43 @@ func (q *qemu) hotplugNetDevice(ctx context.Context, endpoint Endpoint, op Opera
 	case TapEndpointType:
 		drive := endpoint.(*TapEndpoint)
 		tap = drive.TapInterface
+	case VDPAEndpointType:
+		drive := endpoint.(*VDPAEndpoint)
+		// there is no tap interface, the netdev is named after the endpoint
+		tap = TapInterface{
+			ID:   drive.netdevID(),
+			Name: drive.netdevID(),
+		}
+		vhostVDPAPath = drive.VhostVDPAPath
+		// the number of queues is set by the vDPA device
+		queues = 0
+	case PasstEndpointType:
+		if err = q.checkStreamNetdev(); err != nil {
+			return err
+		}
+		drive := endpoint.(*PasstEndpoint)
+		// there is no tap interface, the netdev is named after the endpoint
+		tap = TapInterface{
+			ID:   drive.netdevID(),
+			Name: drive.netdevID(),
+		}
+		passtSocketPath = drive.SocketPath
+		// passt serves a single queue
+		queues = 0
 	default:
 		return fmt.Errorf("this endpoint is not supported")
 	}
 
 	devID := "virtio-" + tap.ID
 	if op == AddDevice {
-		if err = q.hotAddNetDevice(tap.Name, endpoint.HardwareAddr(), tap.VMFds, tap.VhostFds); err != nil {
+		if vhostVDPAPath != "" {
+			err = q.qmpMonitorCh.qmp.ExecuteNetdevAddByVhostVDPA(q.qmpMonitorCh.ctx, tap.Name, vhostVDPAPath)
+		} else if passtSocketPath != "" {
+			err = q.qmpMonitorCh.qmp.ExecuteNetdevAddByStream(q.qmpMonitorCh.ctx, tap.Name, passtSocketPath)
+		} else {
+			err = q.hotAddNetDevice(tap.Name, endpoint.HardwareAddr(), tap.VMFds, tap.VhostFds)
+		}
+		if err != nil {
 			return err
 		}
 
@@ -1864,9 +2446,9 @@ func (q *qemu) hotplugNetDevice(ctx context.Context, endpoint Endpoint, op Opera
 		}
 		if machine.Type == QemuCCWVirtio {
 			devNoHotplug := fmt.Sprintf("fe.%x.%x", bridge.Addr, addr)
-			return q.qmpMonitorCh.qmp.ExecuteNetCCWDeviceAdd(q.qmpMonitorCh.ctx, tap.Name, devID, endpoint.HardwareAddr(), devNoHotplug, int(q.config.NumVCPUs))
+			return q.qmpMonitorCh.qmp.ExecuteNetCCWDeviceAdd(q.qmpMonitorCh.ctx, tap.Name, devID, endpoint.HardwareAddr(), devNoHotplug, queues)
 		}
-		return q.qmpMonitorCh.qmp.ExecuteNetPCIDeviceAdd(q.qmpMonitorCh.ctx, tap.Name, devID, endpoint.HardwareAddr(), addr, bridge.ID, romFile, int(q.config.NumVCPUs), defaultDisableModern)
+		return q.qmpMonitorCh.qmp.ExecuteNetPCIDeviceAdd(q.qmpMonitorCh.ctx, tap.Name, devID, endpoint.HardwareAddr(), addr, bridge.ID, romFile, queues, defaultDisableModern)
 
 	}
 
@@ -1874,7 +2456,7 @@ func (q *qemu) hotplugNetDevice(ctx context.Context, endpoint Endpoint, op Opera
 		return err
 	}
 
-	if err := q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, devID); err != nil {
+	if err := q.deviceDel(devID); err != nil {
 		return err
 	}
 
@@ -1901,6 +2483,9 @@ func (q *qemu) hotplugDevice(ctx context.Context, devInfo interface{}, devType D
 	case VhostuserDev:
 		vAttr := devInfo.(*config.VhostUserDeviceAttrs)
 		return nil, q.hotplugVhostUserDevice(ctx, vAttr, op)
+	case VDPADev:
+		device := devInfo.(*config.VDPADev)
+		return nil, q.hotplugVDPADevice(ctx, device, op)
 	default:
 		return nil, fmt.Errorf("cannot hotplug device: unsupported device type '%v'", devType)
 	}
@@ -2027,7 +2612,7 @@ func (q *qemu) hotplugRemoveCPUs(amount uint32) (uint32, error) {
 	for i := uint32(0); i < amount; i++ {
 		// get the last vCPUs and try to remove it
 		cpu := q.state.HotpluggedVCPUs[len(q.state.HotpluggedVCPUs)-1]
-		if err := q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, cpu.ID); err != nil {
+		if err := q.deviceDel(cpu.ID); err != nil {
 			return i, fmt.Errorf("failed to hotunplug CPUs, only %d CPUs were hotunplugged: %v", i, err)
 		}
 
@@ -2181,9 +2766,14 @@ func (q *qemu) AddDevice(ctx context.Context, devInfo interface{}, devType Devic
 		q.fds = append(q.fds, v.VhostFd)
 		q.qemuConfig.Devices, err = q.arch.appendVSock(ctx, q.qemuConfig.Devices, v)
 	case Endpoint:
+		if v.Type() == PasstEndpointType {
+			if err = q.checkStreamNetdev(); err != nil {
+				return err
+			}
+		}
 		q.qemuConfig.Devices, err = q.arch.appendNetwork(ctx, q.qemuConfig.Devices, v)
-	case config.BlockDrive:
-		q.qemuConfig.Devices, err = q.arch.appendBlockDevice(ctx, q.qemuConfig.Devices, v)
+	case *config.BlockDrive:
+		err = q.addColdPlugBlockDevice(ctx, v)
 	case config.VhostUserDeviceAttrs:
 		q.qemuConfig.Devices, err = q.arch.appendVhostUserDevice(ctx, q.qemuConfig.Devices, v)
 	case config.VFIODev:
@@ -2283,7 +2873,9 @@ func (q *qemu) GetTotalMemoryMB(ctx context.Context) uint32 {
 // Additionally, the unplug has not small granularly it has to be
 // the memory to remove has to be at least the size of one slot.
 // To return memory back we are resizing the VM memory balloon.
-// A longer term solution is evaluate solutions like virtio-mem
+//
+// When the virtio-mem device is set up, the memory is rather plugged and
+// unplugged by the guest, by blocks of a few MB, see resizeVirtioMem.
 func (q *qemu) ResizeMemory(ctx context.Context, reqMemMB uint32, memoryBlockSizeMB uint32, probe bool) (uint32, MemoryDevice, error) {
 
 	currentMemory := q.GetTotalMemoryMB(ctx)
@@ -2291,16 +2883,10 @@ func (q *qemu) ResizeMemory(ctx context.Context, reqMemMB uint32, memoryBlockSiz
 		return 0, MemoryDevice{}, err
 	}
 	var addMemDevice MemoryDevice
-	if q.config.VirtioMem && currentMemory != reqMemMB {
+	if q.virtioMem() && currentMemory != reqMemMB {
 		q.Logger().WithField("hotplug", "memory").Debugf("resize memory from %dMB to %dMB", currentMemory, reqMemMB)
-		sizeByte := uint64(reqMemMB - q.config.MemorySize)
-		sizeByte = sizeByte * 1024 * 1024
-		err := q.qmpMonitorCh.qmp.ExecQomSet(q.qmpMonitorCh.ctx, "virtiomem0", "requested-size", sizeByte)
-		if err != nil {
-			return 0, MemoryDevice{}, err
-		}
-		q.state.HotpluggedMemory = int(sizeByte / 1024 / 1024)
-		return reqMemMB, MemoryDevice{}, nil
+		newMemory, err := q.resizeVirtioMem(ctx, reqMemMB)
+		return newMemory, MemoryDevice{}, err
 	}
 
 	switch {
@@ -2588,6 +3174,9 @@ func (q *qemu) GetPids() []int {
 	if q.state.VirtiofsDaemonPid != 0 {
 		pids = append(pids, q.state.VirtiofsDaemonPid)
 	}
+	if q.state.SwtpmPid != 0 {
+		pids = append(pids, q.state.SwtpmPid)
+	}
 
 	return pids
 }
@@ -2663,11 +3252,13 @@ func (q *qemu) Save() (s hv.HypervisorState) {
 		s.Pid = pids[0]
 	}
 	s.VirtiofsDaemonPid = q.state.VirtiofsDaemonPid
+	s.SwtpmPid = q.state.SwtpmPid
 	s.Type = string(QemuHypervisor)
 	s.UUID = q.state.UUID
 	s.HotpluggedMemory = q.state.HotpluggedMemory
 	s.HotplugVFIOOnRootBus = q.state.HotplugVFIOOnRootBus
 	s.PCIeRootPort = q.state.PCIeRootPort
+	s.VirtioMem = q.state.VirtioMem
 
 	for _, bridge := range q.arch.getBridges() {
 		s.Bridges = append(s.Bridges, hv.Bridge{
@@ -2691,7 +3282,9 @@ func (q *qemu) Load(s hv.HypervisorState) {
 	q.state.HotpluggedMemory = s.HotpluggedMemory
 	q.state.HotplugVFIOOnRootBus = s.HotplugVFIOOnRootBus
 	q.state.VirtiofsDaemonPid = s.VirtiofsDaemonPid
+	q.state.SwtpmPid = s.SwtpmPid
 	q.state.PCIeRootPort = s.PCIeRootPort
+	q.state.VirtioMem = s.VirtioMem
 
 	for _, bridge := range s.Bridges {
 		q.state.Bridges = append(q.state.Bridges, types.NewBridge(types.Type(bridge.Type), bridge.ID, bridge.DeviceAddr, bridge.Addr))
@@ -2735,3 +3328,199 @@ func (q *qemu) GenerateSocket(id string) (interface{}, error) {
 func (q *qemu) IsRateLimiterBuiltin() bool {
 	return false
 }
+
+func (q *qemu) UpdateBlockRateLimiter(ctx context.Context, drive *config.BlockDrive, rateLimiter *config.BlockRateLimiter) error {
+	span, _ := katatrace.Trace(ctx, q.Logger(), "UpdateBlockRateLimiter", qemuTracingTags, map[string]string{"sandbox_id": q.id})
+	defer span.End()
+
+	if q.config.BlockDeviceDriver == config.Nvdimm || drive.Pmem {
+		return fmt.Errorf("cannot limit the I/O of the nvdimm device %s", drive.ID)
+	}
+
+	if err := q.qmpSetup(); err != nil {
+		return err
+	}
+
+	limits := qemuThrottleLimits(rateLimiter)
+	if drive.ThrottleGroup != "" {
+		if err := q.qmpMonitorCh.qmp.ExecuteThrottleGroupSet(q.qmpMonitorCh.ctx, drive.ThrottleGroup, limits); err != nil {
+			return err
+		}
+	} else if err := q.qmpMonitorCh.qmp.ExecuteBlockSetIOThrottle(q.qmpMonitorCh.ctx, "virtio-"+drive.ID, limits); err != nil {
+		return err
+	}
+
+	drive.RateLimiter = rateLimiter
+	return nil
+}
+
+func (q *qemu) ResizeBlockDevice(ctx context.Context, drive *config.BlockDrive, size uint64) error {
+	span, _ := katatrace.Trace(ctx, q.Logger(), "ResizeBlockDevice", qemuTracingTags, map[string]string{"sandbox_id": q.id})
+	defer span.End()
+
+	if q.config.BlockDeviceDriver == config.Nvdimm || drive.Pmem {
+		return fmt.Errorf("cannot resize the nvdimm device %s", drive.ID)
+	}
+
+	if err := q.qmpSetup(); err != nil {
+		return err
+	}
+
+	return q.qmpMonitorCh.qmp.ExecuteBlockResize(q.qmpMonitorCh.ctx, drive.ID, int64(size))
+}
+
+func (q *qemu) ResizeBalloon(ctx context.Context, memMB uint32) error {
+	span, _ := katatrace.Trace(ctx, q.Logger(), "ResizeBalloon", qemuTracingTags, map[string]string{"sandbox_id": q.id})
+	defer span.End()
+
+	if q.config.FreezeMemory == 0 {
+		return fmt.Errorf("the VM has no memory balloon, freeze_memory is not set")
+	}
+
+	if err := q.qmpSetup(); err != nil {
+		return err
+	}
+
+	target := int64(memMB) << utils.MibToBytesShift
+	if err := q.qmpMonitorCh.qmp.ExecuteBalloon(q.qmpMonitorCh.ctx, uint64(target)); err != nil {
+		return err
+	}
+
+	// the guest inflates the balloon asynchronously, and it can only do
+	// so while its vCPUs are running: wait for it before returning.
+	timeout := time.After(qemuBalloonTimeoutSecs * time.Second)
+	for {
+		info, err := q.qmpMonitorCh.qmp.ExecuteQueryBalloon(q.qmpMonitorCh.ctx)
+		if err != nil {
+			return err
+		}
+		if info.Actual <= target {
+			return nil
+		}
+
+		select {
+		case <-timeout:
+			q.Logger().WithFields(logrus.Fields{
+				"target-mb": memMB,
+				"actual-mb": info.Actual >> utils.MibToBytesShift,
+			}).Warn("memory balloon did not reach its target")
+			return nil
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// SnapshotBlockDevice copies a crash-consistent snapshot of the drive to
+// snapshotPath while the VM runs. The block node opening the drive file is
+// looked up in QEMU, so that any drive can be snapshotted, the guest image
+// or a drive hotplugged by an older runtime included, then a backup job
+// copies it as it was when the job started.
+func (q *qemu) SnapshotBlockDevice(ctx context.Context, drive *config.BlockDrive, snapshotPath string) error {
+	span, _ := katatrace.Trace(ctx, q.Logger(), "SnapshotBlockDevice", qemuTracingTags, map[string]string{"sandbox_id": q.id})
+	defer span.End()
+
+	if drive.Pmem {
+		return fmt.Errorf("cannot snapshot the nvdimm device %s", drive.File)
+	}
+
+	if _, err := os.Stat(snapshotPath); err == nil {
+		return fmt.Errorf("snapshot %s already exists", snapshotPath)
+	}
+
+	size, err := blockDeviceSize(drive.File)
+	if err != nil {
+		return err
+	}
+
+	if err := q.qmpSetup(); err != nil {
+		return err
+	}
+
+	qmp := q.qmpMonitorCh.qmp
+	qmpCtx := q.qmpMonitorCh.ctx
+
+	node, err := q.blockNodeByFile(drive.File)
+	if err != nil {
+		return err
+	}
+
+	target := utils.MakeNameID("snap", node, maxQemuNodeNameSize)
+
+	if err := q.runBlockJob("create-"+target, func() error {
+		return qmp.ExecuteBlockdevCreateFile(qmpCtx, "create-"+target, snapshotPath, size)
+	}); err != nil {
+		os.Remove(snapshotPath)
+		return err
+	}
+
+	if err := qmp.ExecuteBlockdevAddFile(qmpCtx, target, snapshotPath); err != nil {
+		os.Remove(snapshotPath)
+		return err
+	}
+
+	backupJob := "backup-" + target
+	backupErr := q.runBlockJob(backupJob, func() error {
+		return qmp.ExecuteBlockdevBackup(qmpCtx, backupJob, node, target, govmmQemu.BlockJobSyncFull, 0)
+	})
+
+	if err := qmp.ExecuteBlockdevDel(qmpCtx, target); err != nil {
+		q.Logger().WithError(err).WithField("drive", drive.File).Warn("Failed to remove snapshot target")
+	}
+
+	if backupErr != nil {
+		os.Remove(snapshotPath)
+	}
+
+	return backupErr
+}
+
+// blockNodeByFile returns the top block node of the block device opening
+// the host file or device path.
+func (q *qemu) blockNodeByFile(path string) (string, error) {
+	blocks, err := q.qmpMonitorCh.qmp.ExecuteQueryBlock(q.qmpMonitorCh.ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, block := range blocks {
+		if block.Inserted != nil && block.Inserted.File == path {
+			if block.Inserted.NodeName != "" {
+				return block.Inserted.NodeName, nil
+			}
+			// drives added with -drive may only have a block
+			// backend name
+			if block.Device != "" {
+				return block.Device, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no block device opens %s", path)
+}
+
+// runBlockJob starts the block job jobID with start, waits for it to
+// conclude then dismisses it.
+func (q *qemu) runBlockJob(jobID string, start func() error) error {
+	if err := start(); err != nil {
+		return err
+	}
+
+	_, err := q.waitBlockJob(jobID, govmmQemu.JobStatusConcluded)
+	if dismissErr := q.qmpMonitorCh.qmp.ExecuteJobDismiss(q.qmpMonitorCh.ctx, jobID); err == nil {
+		err = dismissErr
+	}
+
+	return err
+}
+
+// waitBlockJob waits for the block job jobID to reach status, logging its
+// progress.
+func (q *qemu) waitBlockJob(jobID, status string) (govmmQemu.JobInfo, error) {
+	return q.qmpMonitorCh.qmp.WaitForJobStatus(q.qmpMonitorCh.ctx, jobID, status, qemuBlockJobPollInterval, func(job govmmQemu.JobInfo) {
+		q.Logger().WithFields(logrus.Fields{
+			"job":      job.ID,
+			"status":   job.Status,
+			"progress": fmt.Sprintf("%.0f%%", job.Progress()*100),
+		}).Debug("block job progress")
+	})
+}
@@ -143,6 +143,7 @@ func newQemuArch(config HypervisorConfig) (qemuArch, error) {
 			dax:                  true,
 			protection:           noneProtection,
 			legacySerial:         config.LegacySerial,
+			tpmDriver:            govmmQemu.TPMCRB,
 		},
 		vmFactory: factory,
 		snpGuest:  config.SevSnpGuest,
@@ -117,6 +117,9 @@ type qemuArch interface {
 	// appendRNGDevice appends a RNG device to devices
 	appendRNGDevice(ctx context.Context, devices []govmmQemu.Device, rngDevice config.RNGDev) ([]govmmQemu.Device, error)
 
+	// appendTPMDevice appends a TPM device backed by the emulator listening on socketPath
+	appendTPMDevice(devices []govmmQemu.Device, socketPath string) ([]govmmQemu.Device, error)
+
 	// addDeviceToBridge adds devices to the bus
 	addDeviceToBridge(ctx context.Context, ID string, t types.Type) (string, types.Bridge, error)
 
@@ -156,6 +159,9 @@ type qemuArch interface {
 	// append pvpanic device
 	appendPVPanicDevice(devices []govmmQemu.Device) ([]govmmQemu.Device, error)
 
+	// appendBalloonDevice appends a memory balloon device to devices
+	appendBalloonDevice(ctx context.Context, devices []govmmQemu.Device) ([]govmmQemu.Device, error)
+
 	// append protection device.
 	// This implementation is architecture specific, some archs may need
 	// a firmware, returns a string containing the path to the firmware that should
@@ -186,6 +192,7 @@ type qemuArchBase struct {
 	kernelParams         []Param
 	Bridges              []types.Bridge
 	PFlash               []string
+	tpmDriver            govmmQemu.DeviceDriver
 	memoryOffset         uint64
 	networkIndex         int
 	// Exclude from lint checking for it is ultimately only used in architecture-specific code
@@ -630,6 +637,24 @@ func genericNetwork(endpoint Endpoint, vhost, nestedRun bool, index int) (govmmQ
 			FDs:           netPair.VMFds,
 			VhostFDs:      netPair.VhostFds,
 		}
+	case *VDPAEndpoint:
+		d = govmmQemu.NetDevice{
+			Type:          govmmQemu.VHOSTVDPA,
+			Driver:        govmmQemu.VirtioNet,
+			ID:            fmt.Sprintf("network-%d", index),
+			VhostDev:      ep.VhostVDPAPath,
+			MACAddress:    ep.HardwareAddr(),
+			DisableModern: nestedRun,
+		}
+	case *PasstEndpoint:
+		d = govmmQemu.NetDevice{
+			Type:          govmmQemu.STREAM,
+			Driver:        govmmQemu.VirtioNet,
+			ID:            fmt.Sprintf("network-%d", index),
+			SocketPath:    ep.SocketPath,
+			MACAddress:    ep.HardwareAddr(),
+			DisableModern: nestedRun,
+		}
 	default:
 		return govmmQemu.NetDevice{}, fmt.Errorf("Unknown type for endpoint")
 	}
@@ -735,6 +760,22 @@ func (q *qemuArchBase) appendRNGDevice(_ context.Context, devices []govmmQemu.De
 	return devices, nil
 }
 
+func (q *qemuArchBase) appendTPMDevice(devices []govmmQemu.Device, socketPath string) ([]govmmQemu.Device, error) {
+	if q.tpmDriver == "" {
+		return devices, fmt.Errorf("vTPM is not supported on machine type %s", q.qemuMachine.Type)
+	}
+
+	devices = append(devices,
+		govmmQemu.TPMDevice{
+			ID:     vTPMID,
+			Path:   socketPath,
+			Driver: q.tpmDriver,
+		},
+	)
+
+	return devices, nil
+}
+
 func (q *qemuArchBase) handleImagePath(config HypervisorConfig) {
 	if config.ImagePath != "" {
 		kernelRootParams := commonVirtioblkKernelRootParams
@@ -883,6 +924,19 @@ func (q *qemuArchBase) appendPVPanicDevice(devices []govmmQemu.Device) ([]govmmQ
 	return devices, nil
 }
 
+// appendBalloonDevice appends a memory balloon device
+func (q *qemuArchBase) appendBalloonDevice(_ context.Context, devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
+	devices = append(devices,
+		govmmQemu.BalloonDevice{
+			ID:            balloonID,
+			DeflateOnOOM:  true,
+			DisableModern: q.nestedRun,
+		},
+	)
+
+	return devices, nil
+}
+
 func (q *qemuArchBase) getPFlash() ([]string, error) {
 	return q.PFlash, nil
 }
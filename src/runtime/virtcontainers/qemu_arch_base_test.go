@@ -492,6 +492,26 @@ func TestQemuArchBaseAppendVFIODeviceWithVendorDeviceID(t *testing.T) {
 	testQemuArchBaseAppend(t, vfDevice, expectedOut)
 }
 
+func TestQemuArchBaseAppendTPMDevice(t *testing.T) {
+	assert := assert.New(t)
+	qemuArchBase := newQemuArchBase()
+
+	devices, err := qemuArchBase.appendTPMDevice(nil, "/tmp/swtpm.sock")
+	assert.Error(err)
+	assert.Empty(devices)
+
+	qemuArchBase.tpmDriver = govmmQemu.TPMCRB
+	devices, err = qemuArchBase.appendTPMDevice(nil, "/tmp/swtpm.sock")
+	assert.NoError(err)
+	assert.Equal([]govmmQemu.Device{
+		govmmQemu.TPMDevice{
+			ID:     vTPMID,
+			Path:   "/tmp/swtpm.sock",
+			Driver: govmmQemu.TPMCRB,
+		},
+	}, devices)
+}
+
 func TestQemuArchBaseAppendSCSIController(t *testing.T) {
 	var devices []govmmQemu.Device
 	assert := assert.New(t)
@@ -548,6 +568,20 @@ func TestQemuArchBaseAppendNetwork(t *testing.T) {
 		},
 	}
 
+	vdpaEp := &VDPAEndpoint{
+		VhostVDPAPath: "/dev/vhost-vdpa-0",
+		HardAddr:      macAddr.String(),
+		IfaceName:     "vdpa0",
+		EndpointType:  VDPAEndpointType,
+	}
+
+	passtEp := &PasstEndpoint{
+		SocketPath:   "/run/vc/vm/sandbox/passt-eth0.sock",
+		HardAddr:     macAddr.String(),
+		IfaceName:    "eth0",
+		EndpointType: PasstEndpointType,
+	}
+
 	expectedOut := []govmmQemu.Device{
 		govmmQemu.NetDevice{
 			Type:       networkModelToQemuType(macvlanEp.NetPair.NetInterworkingModel),
@@ -571,12 +605,30 @@ func TestQemuArchBaseAppendNetwork(t *testing.T) {
 			FDs:        macvtapEp.VMFds,
 			VhostFDs:   macvtapEp.VhostFds,
 		},
+		govmmQemu.NetDevice{
+			Type:       govmmQemu.VHOSTVDPA,
+			Driver:     govmmQemu.VirtioNet,
+			ID:         fmt.Sprintf("network-%d", 2),
+			VhostDev:   vdpaEp.VhostVDPAPath,
+			MACAddress: vdpaEp.HardAddr,
+		},
+		govmmQemu.NetDevice{
+			Type:       govmmQemu.STREAM,
+			Driver:     govmmQemu.VirtioNet,
+			ID:         fmt.Sprintf("network-%d", 3),
+			SocketPath: passtEp.SocketPath,
+			MACAddress: passtEp.HardAddr,
+		},
 	}
 
 	devices, err = qemuArchBase.appendNetwork(context.Background(), devices, macvlanEp)
 	assert.NoError(err)
 	devices, err = qemuArchBase.appendNetwork(context.Background(), devices, macvtapEp)
 	assert.NoError(err)
+	devices, err = qemuArchBase.appendNetwork(context.Background(), devices, vdpaEp)
+	assert.NoError(err)
+	devices, err = qemuArchBase.appendNetwork(context.Background(), devices, passtEp)
+	assert.NoError(err)
 	assert.Equal(expectedOut, devices)
 }
 
@@ -599,3 +651,19 @@ func TestQemuArchBaseAppendIOMMU(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(expectedOut, devices)
 }
+
+func TestQemuArchBaseAppendBalloonDevice(t *testing.T) {
+	assert := assert.New(t)
+	qemuArchBase := newQemuArchBase()
+
+	expectedOut := []govmmQemu.Device{
+		govmmQemu.BalloonDevice{
+			ID:           balloonID,
+			DeflateOnOOM: true,
+		},
+	}
+
+	devices, err := qemuArchBase.appendBalloonDevice(context.Background(), nil)
+	assert.NoError(err)
+	assert.Equal(expectedOut, devices)
+}
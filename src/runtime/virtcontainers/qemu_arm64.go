@@ -62,6 +62,7 @@ func newQemuArch(config HypervisorConfig) (qemuArch, error) {
 			dax:                  true,
 			protection:           noneProtection,
 			legacySerial:         config.LegacySerial,
+			tpmDriver:            govmmQemu.TPMTISDevice,
 		},
 	}
 
@@ -74,6 +74,7 @@ func newQemuArch(config HypervisorConfig) (qemuArch, error) {
 			kernelParams:         kernelParams,
 			protection:           noneProtection,
 			legacySerial:         config.LegacySerial,
+			tpmDriver:            govmmQemu.TPMSpapr,
 		},
 	}
 
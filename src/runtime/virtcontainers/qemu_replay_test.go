@@ -0,0 +1,207 @@
+//go:build linux
+
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
+	govmmQemu "github.com/kata-containers/kata-containers/src/runtime/pkg/govmm/qemu"
+)
+
+// The QMP sessions of testdata/qmp follow the conversations of QEMU 7.2.
+// New sessions are recorded from a real QEMU by setting qmp_record_path, then
+// the arguments changing from one run to another are replaced by
+// govmmQemu.QMPRecordWildcard.
+
+// startQemuReplay returns a running QEMU driver talking to a QEMU monitor
+// replaying the recording testdata/qmp/name.
+func startQemuReplay(t *testing.T, name string) (*qemu, *govmmQemu.QMPReplay) {
+	d := conformanceDrivers[0]
+	id := "replay-" + name
+
+	conf := conformanceConfig(t, d, id)
+	conf.BlockDeviceAIO = config.AIOThreads
+
+	h := createConformanceHypervisor(t, d, id, conf, nil)
+	d.run(t, h)
+
+	q := h.(*qemu)
+	q.state.HotpluggedVCPUs = nil
+
+	replay, err := govmmQemu.StartQMPReplay(q.qmpMonitorCh.path, filepath.Join("testdata", "qmp", name+".jsonl"))
+	assert.NoError(t, err)
+
+	return q, replay
+}
+
+func TestQemuReplayHotplugBlock(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	q, replay := startQemuReplay(t, "hotplug-block")
+
+	disk := filepath.Join(t.TempDir(), "disk.img")
+	assert.NoError(os.WriteFile(disk, nil, 0600))
+	drive := &config.BlockDrive{
+		File:   disk,
+		Format: "raw",
+		ID:     "drive-replay",
+	}
+
+	// the drive has no limits, it gets no throttle group
+	_, err := q.HotplugAddDevice(ctx, drive, BlockDev)
+	assert.NoError(err)
+	assert.Empty(drive.ThrottleGroup)
+
+	rateLimiter := &config.BlockRateLimiter{OpsMaxRate: 200}
+	assert.NoError(q.UpdateBlockRateLimiter(ctx, drive, rateLimiter))
+	assert.Equal(rateLimiter, drive.RateLimiter)
+
+	_, err = q.HotplugRemoveDevice(ctx, drive, BlockDev)
+	assert.NoError(err)
+
+	assert.NoError(replay.Close())
+}
+
+func TestQemuReplayHotplugBlockThrottled(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	q, replay := startQemuReplay(t, "hotplug-block-throttled")
+
+	disk := filepath.Join(t.TempDir(), "disk.img")
+	assert.NoError(os.WriteFile(disk, nil, 0600))
+	drive := &config.BlockDrive{
+		File:        disk,
+		Format:      "raw",
+		ID:          "drive-replay",
+		RateLimiter: &config.BlockRateLimiter{OpsMaxRate: 100},
+	}
+
+	_, err := q.HotplugAddDevice(ctx, drive, BlockDev)
+	assert.NoError(err)
+	assert.Equal("throttle-drive-replay", drive.ThrottleGroup)
+
+	assert.NoError(q.UpdateBlockRateLimiter(ctx, drive, &config.BlockRateLimiter{OpsMaxRate: 200}))
+
+	_, err = q.HotplugRemoveDevice(ctx, drive, BlockDev)
+	assert.NoError(err)
+	assert.Empty(drive.ThrottleGroup)
+
+	assert.NoError(replay.Close())
+}
+
+func TestQemuReplayResizeVCPUs(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	q, replay := startQemuReplay(t, "resize-vcpus")
+
+	current, added, err := q.ResizeVCPUs(ctx, 2)
+	assert.NoError(err)
+	assert.Equal(uint32(1), current)
+	assert.Equal(uint32(2), added)
+	assert.Len(q.state.HotpluggedVCPUs, 1)
+
+	current, removed, err := q.ResizeVCPUs(ctx, 1)
+	assert.NoError(err)
+	assert.Equal(uint32(2), current)
+	assert.Equal(uint32(1), removed)
+	assert.Empty(q.state.HotpluggedVCPUs)
+
+	assert.NoError(replay.Close())
+}
+
+func TestQemuReplayShutdown(t *testing.T) {
+	assert := assert.New(t)
+
+	q, replay := startQemuReplay(t, "shutdown")
+
+	assert.NoError(q.StopVM(context.Background(), false))
+
+	assert.NoError(replay.Close())
+}
+
+func TestQemuReplayResizeVirtioMem(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// The guest never unplugs all the memory requested in the recording.
+	savedTimeout, savedInterval := virtioMemResizeTimeout, virtioMemPollInterval
+	virtioMemResizeTimeout, virtioMemPollInterval = 100*time.Millisecond, time.Hour
+	defer func() {
+		virtioMemResizeTimeout, virtioMemPollInterval = savedTimeout, savedInterval
+	}()
+
+	q, replay := startQemuReplay(t, "resize-virtio-mem")
+	q.config.MemorySize = 2048
+	q.config.VirtioMem = true
+
+	// The requested memory is rounded up to the 2MB blocks of the device.
+	memory, _, err := q.ResizeMemory(ctx, 3073, 128, false)
+	assert.NoError(err)
+	assert.Equal(uint32(3074), memory)
+	assert.Equal(1026, q.state.HotpluggedMemory)
+
+	memory, _, err = q.ResizeMemory(ctx, 2048, 128, false)
+	assert.NoError(err)
+	assert.Equal(uint32(2560), memory)
+	assert.Equal(512, q.state.HotpluggedMemory)
+
+	assert.NoError(replay.Close())
+}
+
+func TestQemuReplayResizeVirtioMemSlowGuest(t *testing.T) {
+	assert := assert.New(t)
+
+	savedTimeout, savedInterval := virtioMemResizeTimeout, virtioMemPollInterval
+	virtioMemResizeTimeout, virtioMemPollInterval = 100*time.Millisecond, time.Hour
+	defer func() {
+		virtioMemResizeTimeout, virtioMemPollInterval = savedTimeout, savedInterval
+	}()
+
+	q, replay := startQemuReplay(t, "resize-virtio-mem-slow")
+	q.config.MemorySize = 2048
+	q.config.VirtioMem = true
+
+	// The guest only plugged half of the memory requested before the
+	// timeout, and plugs the rest in the background.
+	memory, _, err := q.ResizeMemory(context.Background(), 3072, 128, false)
+	assert.NoError(err)
+	assert.Equal(uint32(2560), memory)
+	assert.Equal(512, q.state.HotpluggedMemory)
+
+	assert.NoError(replay.Close())
+}
+
+func TestQemuReplaySnapshotBlock(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	q, replay := startQemuReplay(t, "snapshot-block")
+
+	// the drive was hotplugged by an older runtime: its node is found by
+	// file in QEMU, not guessed from the drive. Any existing file will do
+	// as QEMU is replayed.
+	drive := &config.BlockDrive{
+		File:   filepath.Join("testdata", "qmp", "snapshot-block.jsonl"),
+		Format: "raw",
+		ID:     "drive-replay",
+	}
+
+	assert.NoError(q.SnapshotBlockDevice(ctx, drive, filepath.Join(t.TempDir(), "snapshot.img")))
+
+	assert.NoError(replay.Close())
+}
@@ -296,6 +296,27 @@ func (q *qemuS390x) appendVSock(ctx context.Context, devices []govmmQemu.Device,
 
 }
 
+func (q *qemuS390x) appendBalloonDevice(ctx context.Context, devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
+	addr, b, err := q.addDeviceToBridge(ctx, balloonID, types.CCW)
+	if err != nil {
+		return devices, fmt.Errorf("Failed to append balloon device: %v", err)
+	}
+	devno, err := b.AddressFormatCCW(addr)
+	if err != nil {
+		return devices, fmt.Errorf("Failed to append balloon device: %v", err)
+	}
+
+	devices = append(devices,
+		govmmQemu.BalloonDevice{
+			ID:           balloonID,
+			DeflateOnOOM: true,
+			DevNo:        devno,
+		},
+	)
+
+	return devices, nil
+}
+
 func (q *qemuS390x) appendIOMMU(devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
 	return devices, fmt.Errorf("S390x does not support appending a vIOMMU")
 }
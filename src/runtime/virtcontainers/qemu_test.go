@@ -10,14 +10,20 @@ package virtcontainers
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/govmm"
 	govmmQemu "github.com/kata-containers/kata-containers/src/runtime/pkg/govmm/qemu"
+	hv "github.com/kata-containers/kata-containers/src/runtime/pkg/hypervisors"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cpuset"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 	"github.com/pbnjay/memory"
@@ -112,6 +118,119 @@ func TestQemuCreateVM(t *testing.T) {
 	assert.Exactly(qemuConfig, q.config)
 }
 
+// qemuTestCapabilities returns the capabilities of a QEMU supporting the
+// JSON syntax, and the aio modes of the file block nodes aio.
+func qemuTestCapabilities(aio ...string) *govmmQemu.Capabilities {
+	return &govmmQemu.Capabilities{
+		Schema: []govmmQemu.SchemaInfo{
+			{MetaType: "command", Name: "device_add", ArgType: "0", Features: []string{"json-cli"}},
+			{MetaType: "command", Name: "object-add", ArgType: "1"},
+			{MetaType: "command", Name: "blockdev-add", ArgType: "2"},
+			{MetaType: "object", Name: "1", Tag: "qom-type"},
+			{MetaType: "object", Name: "2", Tag: "driver", Variants: []govmmQemu.SchemaVariant{{Case: "file", Type: "3"}}},
+			{MetaType: "object", Name: "3", Members: []govmmQemu.SchemaMember{{Name: "aio", Type: "4"}}},
+			{MetaType: "enum", Name: "4", Values: aio},
+		},
+	}
+}
+
+func TestQemuCreateVMJSONSyntax(t *testing.T) {
+	qemuConfig := newQemuConfig()
+	assert := assert.New(t)
+
+	savedDir := qemuCapabilitiesDir
+	qemuCapabilitiesDir = t.TempDir()
+	defer func() { qemuCapabilitiesDir = savedDir }()
+
+	store, err := persist.GetDriver()
+	assert.NoError(err)
+	q := &qemu{
+		config: HypervisorConfig{
+			VMStorePath:  store.RunVMStoragePath(),
+			RunStorePath: store.RunStoragePath(),
+		},
+	}
+
+	testQemuPath := filepath.Join(testDir, testHypervisor)
+	_, err = os.Create(testQemuPath)
+	assert.NoError(err)
+
+	parentDir := filepath.Join(store.RunStoragePath(), "testSandbox")
+	assert.NoError(os.MkdirAll(parentDir, DirMode))
+	defer os.RemoveAll(parentDir)
+
+	// The capabilities probed by a previous sandbox do not change the
+	// syntax of the command line.
+	assert.NoError(qemuTestCapabilities().Save(qemuCapabilitiesPath(testQemuPath), testQemuPath))
+
+	network, err := NewNetwork()
+	assert.NoError(err)
+	err = q.CreateVM(context.Background(), "testSandbox", network, &qemuConfig)
+	assert.NoError(err)
+	assert.False(q.qemuConfig.JSONSyntax)
+
+	qemuConfig.EnableJSONSyntax = true
+	err = q.CreateVM(context.Background(), "testSandbox", network, &qemuConfig)
+	assert.NoError(err)
+	assert.True(q.qemuConfig.JSONSyntax)
+}
+
+func TestQemuBlockDeviceAIO(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		config: HypervisorConfig{
+			BlockDeviceAIO: config.AIOIOUring,
+		},
+	}
+
+	// The configured mode is used until QEMU is probed.
+	assert.Equal(govmmQemu.IOUring, q.blockDeviceAIO())
+
+	q.qemuCapabilities = qemuTestCapabilities("threads", "native", "io_uring")
+	assert.Equal(govmmQemu.IOUring, q.blockDeviceAIO())
+
+	q.qemuCapabilities = qemuTestCapabilities("threads", "native")
+	assert.Equal(govmmQemu.Threads, q.blockDeviceAIO())
+}
+
+// Checks that the memory is resized with DIMMs when QEMU has no virtio-mem
+// device.
+func TestQemuSetupVirtioMemUnsupported(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		config: HypervisorConfig{
+			VirtioMem:            true,
+			MemorySize:           2048,
+			DefaultMaxMemorySize: 4096,
+		},
+		qemuCapabilities: qemuTestCapabilities(),
+	}
+
+	assert.NoError(q.setupVirtioMem(context.Background()))
+	assert.False(q.virtioMem())
+}
+
+func TestQemuVirtioMemState(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{config: HypervisorConfig{VirtioMem: true}}
+
+	// The states saved before virtio-mem was recorded follow the
+	// configuration the VM was booted with.
+	q.Load(hv.HypervisorState{})
+	assert.Nil(q.state.VirtioMem)
+	assert.True(q.virtioMem())
+
+	q.config.VirtioMem = false
+	assert.False(q.virtioMem())
+
+	virtioMem := true
+	q.Load(hv.HypervisorState{VirtioMem: &virtioMem})
+	assert.True(q.virtioMem())
+}
+
 func TestQemuCreateVMMissingParentDirFail(t *testing.T) {
 	qemuConfig := newQemuConfig()
 	assert := assert.New(t)
@@ -199,6 +318,29 @@ func TestQemuMemoryTopology(t *testing.T) {
 	assert.Exactly(memory, expectedOut)
 }
 
+func TestQemuNUMATopology(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		arch: &qemuArchBase{},
+		config: HypervisorConfig{
+			MemorySize: 3072,
+			MemSlots:   8,
+			NUMANodes: []NUMANode{
+				{HostNode: 0, MemorySize: 2048, GuestCPUs: cpuset.NewCPUSet(0, 1, 4, 5)},
+				{HostNode: 3, MemorySize: 1024},
+			},
+		},
+	}
+
+	memory, err := q.memoryTopology()
+	assert.NoError(err)
+	assert.Equal([]govmmQemu.NUMANode{
+		{Size: "2048M", HostNodes: "0", CPUs: []string{"0-1", "4-5"}},
+		{Size: "1024M", HostNodes: "3"},
+	}, memory.NUMANodes)
+}
+
 func TestQemuKnobs(t *testing.T) {
 	assert := assert.New(t)
 
@@ -258,6 +400,43 @@ func TestQemuAddDeviceFsDev(t *testing.T) {
 	testQemuAddDevice(t, volume, FsDev, expectedOut)
 }
 
+func TestQemuAddDeviceBlockThrottled(t *testing.T) {
+	assert := assert.New(t)
+	q := &qemu{
+		ctx:  context.Background(),
+		arch: &qemuArchBase{},
+	}
+
+	drive := &config.BlockDrive{
+		File:   "/dev/loop0",
+		Format: "raw",
+		ID:     "drive",
+	}
+
+	// the drives without limits are not throttled
+	assert.NoError(q.AddDevice(context.Background(), drive, BlockDev))
+	assert.Len(q.qemuConfig.Devices, 1)
+	blkdev := q.qemuConfig.Devices[0].(govmmQemu.BlockDevice)
+	assert.Equal("virtio-"+drive.ID, blkdev.DevID)
+	assert.Empty(blkdev.ThrottleGroup)
+	assert.Empty(drive.ThrottleGroup)
+
+	// the others get a throttle group, as the hotplugged ones
+	q.config.DiskRateLimiterOpsMaxRate = 100
+	assert.NoError(q.AddDevice(context.Background(), drive, BlockDev))
+	assert.Len(q.qemuConfig.Devices, 2)
+
+	blkdev = q.qemuConfig.Devices[1].(govmmQemu.BlockDevice)
+	assert.Equal("virtio-"+drive.ID, blkdev.DevID)
+	assert.Equal(throttleGroupID(drive.ID), blkdev.ThrottleGroup)
+	assert.Equal(throttledNodeID(drive.ID), blkdev.ThrottledNodeName)
+	assert.Equal(govmmQemu.ThrottleLimits{IopsTotal: 100}, blkdev.ThrottleLimits)
+
+	// the group is recorded in the drive, for the updates and the unplug
+	// to use it
+	assert.Equal(throttleGroupID(drive.ID), drive.ThrottleGroup)
+}
+
 func TestQemuAddDeviceVhostUserBlk(t *testing.T) {
 	socketPath := "/test/socket/path"
 	devID := "testDevID"
@@ -279,6 +458,27 @@ func TestQemuAddDeviceVhostUserBlk(t *testing.T) {
 	testQemuAddDevice(t, vDevice, VhostuserDev, expectedOut)
 }
 
+func TestQemuAddDevicePasstNoStreamNetdev(t *testing.T) {
+	assert := assert.New(t)
+	q := &qemu{
+		ctx:              context.Background(),
+		arch:             &qemuArchBase{},
+		qemuCapabilities: qemuTestCapabilities(),
+	}
+
+	endpoint := &PasstEndpoint{
+		IfaceName:    "eth0",
+		SocketPath:   "/run/vc/vm/sandbox/passt-eth0.sock",
+		EndpointType: PasstEndpointType,
+	}
+
+	// QEMU is known not to support the stream netdevs
+	err := q.AddDevice(context.Background(), endpoint, NetDev)
+	assert.Error(err)
+	assert.Contains(err.Error(), "7.2")
+	assert.Empty(q.qemuConfig.Devices)
+}
+
 func TestQemuAddDeviceSerialPortDev(t *testing.T) {
 	deviceID := "channelTest"
 	id := "charchTest"
@@ -441,6 +641,179 @@ func TestQMPSetupShutdown(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestQemuDeviceDel(t *testing.T) {
+	assert := assert.New(t)
+
+	socket := filepath.Join(t.TempDir(), qmpSocket)
+	startFakeQMPWithReply(t, socket, func(w io.Writer, command string, args map[string]interface{}) {
+		if command != "device_del" || args["id"] != "virtio-dev" {
+			fmt.Fprintf(w, `{"error": {"class": "DeviceNotFound", "desc": "Device '%v' not found"}}`+"\n", args["id"])
+			return
+		}
+
+		// The guest releases the device after QEMU replied to device_del.
+		fmt.Fprintln(w, `{"return": {}}`)
+		fmt.Fprintln(w, `{"event": "DEVICE_DELETED", "data": {"device": "other-dev"}, "timestamp": {"seconds": 1, "microseconds": 0}}`)
+		fmt.Fprintln(w, `{"event": "DEVICE_DELETED", "data": {"device": "virtio-dev"}, "timestamp": {"seconds": 1, "microseconds": 0}}`)
+	})
+
+	q := &qemu{
+		config: newQemuConfig(),
+	}
+	q.qmpMonitorCh.ctx = context.Background()
+	q.qmpMonitorCh.path = socket
+
+	assert.NoError(q.qmpSetup())
+	defer q.qmpShutdown()
+
+	assert.NoError(q.deviceDel("virtio-dev"))
+	assert.Error(q.deviceDel("missing-dev"))
+}
+
+func TestQemuHotplugAddBlockDeviceFileCache(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	disk := filepath.Join(dir, "disk.img")
+	assert.NoError(os.WriteFile(disk, nil, 0600))
+
+	for _, cacheSet := range []bool{false, true} {
+		blockdevs := make(chan map[string]interface{}, 1)
+		socket := filepath.Join(dir, fmt.Sprintf("%v-%s", cacheSet, qmpSocket))
+		startFakeQMPWithReply(t, socket, func(w io.Writer, command string, args map[string]interface{}) {
+			if command == "blockdev-add" {
+				blockdevs <- args
+			}
+			fmt.Fprintln(w, `{"return": {}}`)
+		})
+
+		q := &qemu{
+			config: newQemuConfig(),
+		}
+		q.config.BlockDeviceDriver = config.VirtioSCSI
+		q.config.BlockDeviceCacheSet = cacheSet
+		q.config.BlockDeviceCacheDirect = true
+		q.qmpMonitorCh.ctx = context.Background()
+		q.qmpMonitorCh.path = socket
+		assert.NoError(q.qmpSetup())
+
+		drive := &config.BlockDrive{
+			ID:   "drive",
+			File: disk,
+		}
+		assert.NoError(q.hotplugAddBlockDevice(context.Background(), drive, AddDevice, "virtio-drive"))
+		q.qmpShutdown()
+
+		blockdev := <-blockdevs
+		assert.Equal("file", blockdev["file"].(map[string]interface{})["driver"])
+		if cacheSet {
+			assert.Equal(map[string]interface{}{"direct": true, "no-flush": false}, blockdev["cache"])
+		} else {
+			assert.NotContains(blockdev, "cache")
+		}
+	}
+}
+
+func TestQemuQMPRecordPath(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	socket := filepath.Join(dir, qmpSocket)
+	startFakeQMPWithReply(t, socket, func(w io.Writer, command string, args map[string]interface{}) {
+		fmt.Fprintln(w, `{"return": {}}`)
+	})
+
+	q := &qemu{
+		config: newQemuConfig(),
+	}
+	q.config.QMPRecordPath = filepath.Join(dir, "qmp.jsonl")
+	q.qmpMonitorCh.ctx = context.Background()
+	q.qmpMonitorCh.path = socket
+
+	assert.NoError(q.qmpSetup())
+	assert.NoError(q.qmpMonitorCh.qmp.ExecuteStop(q.qmpMonitorCh.ctx))
+	q.qmpShutdown()
+
+	recording, err := os.ReadFile(q.config.QMPRecordPath)
+	assert.NoError(err)
+	assert.Contains(string(recording), `"qmp_capabilities"`)
+	assert.Contains(string(recording), `"stop"`)
+}
+
+func TestQemuGuestPanickedAfterEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	socket := filepath.Join(dir, qmpSocket)
+	vmm := startFakeQMPWithReply(t, socket, func(w io.Writer, command string, args map[string]interface{}) {
+		fmt.Fprintln(w, `{"return": {}}`)
+		if command != "stop" {
+			return
+		}
+
+		// More events than an event subscription buffers, before the
+		// guest panics.
+		for i := 0; i < 100; i++ {
+			fmt.Fprintln(w, `{"event": "RTC_CHANGE", "data": {"offset": 0}, "timestamp": {"seconds": 1, "microseconds": 0}}`)
+		}
+		fmt.Fprintln(w, `{"event": "GUEST_PANICKED", "data": {"action": "pause"}, "timestamp": {"seconds": 1, "microseconds": 0}}`)
+	})
+
+	q := &qemu{
+		id:     "panicked",
+		config: newQemuConfig(),
+	}
+	q.config.MemorySize = 1
+	q.config.RunStorePath = dir
+	q.config.GuestMemoryDumpPath = filepath.Join(dir, "dump")
+	q.qmpMonitorCh.ctx = context.Background()
+	q.qmpMonitorCh.path = socket
+
+	assert.NoError(q.qmpSetup())
+	defer q.qmpShutdown()
+
+	assert.NoError(q.qmpMonitorCh.qmp.ExecuteStop(q.qmpMonitorCh.ctx))
+
+	// The guest memory is dumped once the panic is handled.
+	assert.Eventually(func() bool {
+		for _, request := range vmm.requests() {
+			if request == "dump-guest-memory" {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestQemuHotplugRemoveBlockDeviceThrottled(t *testing.T) {
+	assert := assert.New(t)
+
+	socket := filepath.Join(t.TempDir(), qmpSocket)
+	vmm := startFakeQMPWithReply(t, socket, func(w io.Writer, command string, args map[string]interface{}) {
+		fmt.Fprintln(w, `{"return": {}}`)
+		if command == "device_del" {
+			fmt.Fprintf(w, `{"event": "DEVICE_DELETED", "data": {"device": "%v"}, "timestamp": {"seconds": 1, "microseconds": 0}}`+"\n", args["id"])
+		}
+	})
+
+	q := &qemu{
+		config: newQemuConfig(),
+	}
+	q.qmpMonitorCh.ctx = context.Background()
+	q.qmpMonitorCh.path = socket
+	defer q.qmpShutdown()
+
+	drive := &config.BlockDrive{
+		ID:            "drive",
+		Swap:          true,
+		ThrottleGroup: throttleGroupID("drive"),
+	}
+
+	assert.NoError(q.hotplugBlockDevice(context.Background(), drive, RemoveDevice))
+	assert.Equal([]string{"device_del", "blockdev-del", "object-del"}, vmm.requests())
+	assert.Empty(drive.ThrottleGroup)
+}
+
 func TestQemuCleanup(t *testing.T) {
 	assert := assert.New(t)
 
@@ -623,6 +996,43 @@ func TestQemuGetpids(t *testing.T) {
 	assert.True(pids[1] == 200)
 }
 
+func TestQemuStopVMNotStarted(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		id:             "qemu-not-started",
+		config:         newQemuConfig(),
+		virtiofsDaemon: &virtiofsdMock{},
+	}
+	q.config.VMStorePath = t.TempDir()
+	q.config.SharedFS = config.VirtioFS
+	q.qemuConfig.PidFile = filepath.Join(q.config.VMStorePath, q.id, "pid")
+	q.state.VirtiofsDaemonPid = 100
+
+	// QEMU failed to start, there is no QMP socket to send QUIT to but
+	// virtiofsd still has to be stopped.
+	assert.NoError(q.StopVM(context.Background(), false))
+	assert.Zero(q.state.VirtiofsDaemonPid)
+	assert.Equal(int32(1), atomic.LoadInt32(&q.stopped))
+}
+
+func TestQemuOnSwtpmQuit(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{}
+	q.qemuConfig.PidFile = filepath.Join(t.TempDir(), "pid")
+
+	// QEMU is not running
+	q.onSwtpmQuit(context.Background())
+	assert.Zero(atomic.LoadInt32(&q.stopped))
+
+	// the VM is being stopped
+	assert.NoError(os.WriteFile(q.qemuConfig.PidFile, []byte(strconv.Itoa(os.Getpid())), 0600))
+	atomic.StoreInt32(&q.stopping, 1)
+	q.onSwtpmQuit(context.Background())
+	assert.Zero(atomic.LoadInt32(&q.stopped))
+}
+
 func TestQemuSetConfig(t *testing.T) {
 	assert := assert.New(t)
 
@@ -636,3 +1046,97 @@ func TestQemuSetConfig(t *testing.T) {
 
 	assert.Equal(q.config, config)
 }
+
+func TestQemuThrottleLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(govmmQemu.ThrottleLimits{}, qemuThrottleLimits(nil))
+	assert.Equal(govmmQemu.ThrottleLimits{}, qemuThrottleLimits(&config.BlockRateLimiter{BwOneTimeBurst: 8000}))
+
+	limits := qemuThrottleLimits(&config.BlockRateLimiter{
+		BwMaxRate:  8000,
+		OpsMaxRate: 100,
+	})
+	assert.Equal(govmmQemu.ThrottleLimits{BpsTotal: 1024, IopsTotal: 100}, limits)
+
+	limits = qemuThrottleLimits(&config.BlockRateLimiter{
+		BwMaxRate:       8000,
+		BwOneTimeBurst:  8000,
+		OpsMaxRate:      100,
+		OpsOneTimeBurst: 50,
+	})
+	assert.Equal(govmmQemu.ThrottleLimits{BpsTotal: 1024, BpsTotalMax: 2048, IopsTotal: 100, IopsTotalMax: 150}, limits)
+}
+
+func TestQemuBlockRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		config: HypervisorConfig{
+			DiskRateLimiterBwMaxRate:  8000,
+			DiskRateLimiterOpsMaxRate: 100,
+		},
+	}
+
+	drive := &config.BlockDrive{ID: "drive"}
+	assert.Equal(&config.BlockRateLimiter{BwMaxRate: 8000, OpsMaxRate: 100}, q.blockRateLimiter(drive))
+
+	drive.RateLimiter = &config.BlockRateLimiter{OpsMaxRate: 10}
+	assert.Equal(drive.RateLimiter, q.blockRateLimiter(drive))
+
+	assert.Equal("throttle-drive", throttleGroupID(drive.ID))
+}
+
+func TestQemuUpdateBlockRateLimiterNvdimm(t *testing.T) {
+	q := &qemu{
+		config: HypervisorConfig{
+			BlockDeviceDriver: config.Nvdimm,
+		},
+	}
+
+	err := q.UpdateBlockRateLimiter(context.Background(), &config.BlockDrive{ID: "drive"}, &config.BlockRateLimiter{OpsMaxRate: 10})
+	assert.Error(t, err)
+}
+
+func TestQemuResizeBlockDeviceNvdimm(t *testing.T) {
+	q := &qemu{
+		config: HypervisorConfig{
+			BlockDeviceDriver: config.Nvdimm,
+		},
+	}
+
+	err := q.ResizeBlockDevice(context.Background(), &config.BlockDrive{ID: "drive"}, 1<<30)
+	assert.Error(t, err)
+}
+
+func TestQemuSnapshotBlockDevice(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		config: HypervisorConfig{
+			BlockDeviceDriver: config.VirtioBlock,
+		},
+	}
+
+	err := q.SnapshotBlockDevice(context.Background(), &config.BlockDrive{ID: "drive", Pmem: true}, "/tmp/snapshot")
+	assert.Error(err)
+
+	dir := t.TempDir()
+	snapshotPath := filepath.Join(dir, "snapshot")
+	assert.NoError(os.WriteFile(snapshotPath, nil, 0600))
+
+	err = q.SnapshotBlockDevice(context.Background(), &config.BlockDrive{ID: "drive", File: filepath.Join(dir, "volume")}, snapshotPath)
+	assert.Error(err)
+
+	assert.Equal("fmt-drive", throttledNodeID("drive"))
+	assert.Len(throttledNodeID("drive-0123456789abcdef0123456789"), maxQemuNodeNameSize)
+}
+
+func TestQemuResizeBalloonDisabled(t *testing.T) {
+	q := &qemu{
+		config: HypervisorConfig{},
+	}
+
+	err := q.ResizeBalloon(context.Background(), 256)
+	assert.Error(t, err)
+}
@@ -12,6 +12,7 @@ import (
 
 	cri "github.com/containerd/containerd/pkg/cri/annotations"
 	"github.com/containerd/ttrpc"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/pkg/hypervisors"
 	pb "github.com/kata-containers/kata-containers/src/runtime/protocols/hypervisor"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
@@ -296,3 +297,19 @@ func (rh *remoteHypervisor) IsRateLimiterBuiltin() bool {
 	// TODO
 	return true
 }
+
+func (rh *remoteHypervisor) UpdateBlockRateLimiter(ctx context.Context, drive *config.BlockDrive, rateLimiter *config.BlockRateLimiter) error {
+	return notImplemented("UpdateBlockRateLimiter")
+}
+
+func (rh *remoteHypervisor) ResizeBlockDevice(ctx context.Context, drive *config.BlockDrive, size uint64) error {
+	return notImplemented("ResizeBlockDevice")
+}
+
+func (rh *remoteHypervisor) ResizeBalloon(ctx context.Context, memMB uint32) error {
+	return notImplemented("ResizeBalloon")
+}
+
+func (rh *remoteHypervisor) SnapshotBlockDevice(ctx context.Context, drive *config.BlockDrive, snapshotPath string) error {
+	return notImplemented("SnapshotBlockDevice")
+}
@@ -17,8 +17,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"syscall"
+	"time"
 
 	v1 "github.com/containerd/cgroups/stats/v1"
 	v2 "github.com/containerd/cgroups/v2/stats"
@@ -93,6 +95,7 @@ const (
 
 var (
 	errSandboxNotRunning = errors.New("Sandbox not running")
+	errSandboxFrozen     = errors.New("Sandbox frozen, thaw it first")
 )
 
 // HypervisorPidKey is the context key for hypervisor pid
@@ -146,6 +149,9 @@ type SandboxConfig struct {
 	SandboxBindMounts []string
 	// Experimental features enabled
 	Experimental []exp.Feature
+	// GuestHooks are the OCI hooks run inside the guest for every
+	// container of the sandbox.
+	GuestHooks GuestHooksConfig
 	// Containers describe the list of containers within a Sandbox.
 	// This list can be empty and populated by adding containers
 	// to the Sandbox a posteriori.
@@ -186,6 +192,12 @@ func (sandboxConfig *SandboxConfig) valid() bool {
 			return false
 		}
 	}
+
+	if err := sandboxConfig.GuestHooks.valid(); err != nil {
+		virtLog.WithError(err).Error("invalid guest hooks")
+		return false
+	}
+
 	return true
 }
 
@@ -204,6 +216,7 @@ type Sandbox struct {
 	volumes     []types.Volume
 
 	monitor         *monitor
+	events          *eventBus
 	config          *SandboxConfig
 	annotationsLock *sync.RWMutex
 	wg              *sync.WaitGroup
@@ -323,6 +336,26 @@ func (s *Sandbox) Release(ctx context.Context) error {
 	return s.agent.disconnect(ctx)
 }
 
+// reconnect checks that a sandbox loaded from its persisted state is still
+// running, and reconnects to its hypervisor and to its agent.
+func (s *Sandbox) reconnect(ctx context.Context) error {
+	if s.state.State != types.StateRunning {
+		return fmt.Errorf("cannot recover sandbox %s in state %q", s.id, s.state.State)
+	}
+
+	if err := s.hypervisor.Check(); err != nil {
+		return fmt.Errorf("hypervisor of sandbox %s is not running: %v", s.id, err)
+	}
+
+	if err := s.agent.check(ctx); err != nil {
+		return fmt.Errorf("agent of sandbox %s is not reachable: %v", s.id, err)
+	}
+
+	s.Logger().Info("sandbox recovered")
+
+	return nil
+}
+
 // Status gets the status of the sandbox
 func (s *Sandbox) Status() SandboxStatus {
 	var contStatusList []ContainerStatus
@@ -384,6 +417,11 @@ func (s *Sandbox) WaitProcess(ctx context.Context, containerID, processID string
 // SignalProcess sends a signal to a process of a container when all is false.
 // When all is true, it sends the signal to all processes of a container.
 func (s *Sandbox) SignalProcess(ctx context.Context, containerID, processID string, signal syscall.Signal, all bool) error {
+	// The agent cannot deliver the signal while the sandbox is frozen.
+	if err := s.thawIfFrozen(ctx); err != nil {
+		return err
+	}
+
 	if s.state.State != types.StateRunning {
 		return errSandboxNotRunning
 	}
@@ -428,15 +466,8 @@ func createAssets(ctx context.Context, sandboxConfig *SandboxConfig) error {
 	span, _ := katatrace.Trace(ctx, nil, "createAssets", sandboxTracingTags, map[string]string{"sandbox_id": sandboxConfig.ID})
 	defer span.End()
 
-	for _, name := range types.AssetTypes() {
-		a, err := types.NewAsset(sandboxConfig.Annotations, name)
-		if err != nil {
-			return err
-		}
-
-		if err := sandboxConfig.HypervisorConfig.AddCustomAsset(a); err != nil {
-			return err
-		}
+	if err := sandboxConfig.HypervisorConfig.AddCustomAssets(sandboxConfig.Annotations); err != nil {
+		return err
 	}
 
 	_, imageErr := sandboxConfig.HypervisorConfig.assetPath(types.ImageAsset)
@@ -446,7 +477,7 @@ func createAssets(ctx context.Context, sandboxConfig *SandboxConfig) error {
 		return fmt.Errorf("%s and %s cannot be both set", types.ImageAsset, types.InitrdAsset)
 	}
 
-	return nil
+	return sandboxConfig.HypervisorConfig.verifyAssets()
 }
 
 func (s *Sandbox) getAndStoreGuestDetails(ctx context.Context) error {
@@ -557,6 +588,7 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 		swapDeviceNum:   0,
 		swapSizeBytes:   0,
 		swapDevices:     []*config.BlockDrive{},
+		events:          newEventBus(),
 	}
 
 	fsShare, err := NewFilesystemShare(s)
@@ -601,6 +633,12 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 		return nil, err
 	}
 
+	if sandboxConfig.HypervisorConfig.EnableNUMA {
+		if err := s.setupNUMATopology(); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(sandboxConfig.Containers) > 0 {
 		// These values are required by remove hypervisor
 		for _, a := range []string{cri.SandboxName, crio.SandboxName} {
@@ -835,6 +873,8 @@ func (s *Sandbox) Delete(ctx context.Context) error {
 		s.Logger().WithError(err).Error("failed to cleanup share files")
 	}
 
+	s.events.close()
+
 	return s.store.Destroy(s.id)
 }
 
@@ -925,6 +965,10 @@ func (s *Sandbox) generateNetInfo(inf *pbTypes.Interface) (NetworkInfo, error) {
 
 // AddInterface adds new nic to the sandbox.
 func (s *Sandbox) AddInterface(ctx context.Context, inf *pbTypes.Interface) (*pbTypes.Interface, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	netInfo, err := s.generateNetInfo(inf)
 	if err != nil {
 		return nil, err
@@ -963,6 +1007,10 @@ func (s *Sandbox) AddInterface(ctx context.Context, inf *pbTypes.Interface) (*pb
 
 // RemoveInterface removes a nic of the sandbox.
 func (s *Sandbox) RemoveInterface(ctx context.Context, inf *pbTypes.Interface) (*pbTypes.Interface, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	for _, endpoint := range s.network.Endpoints() {
 		if endpoint.HardwareAddr() == inf.HwAddr {
 			s.Logger().WithField("endpoint-type", endpoint.Type()).Info("Hot detaching endpoint")
@@ -982,16 +1030,28 @@ func (s *Sandbox) RemoveInterface(ctx context.Context, inf *pbTypes.Interface) (
 
 // ListInterfaces lists all nics and their configurations in the sandbox.
 func (s *Sandbox) ListInterfaces(ctx context.Context) ([]*pbTypes.Interface, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	return s.agent.listInterfaces(ctx)
 }
 
 // UpdateRoutes updates the sandbox route table (e.g. for portmapping support).
 func (s *Sandbox) UpdateRoutes(ctx context.Context, routes []*pbTypes.Route) ([]*pbTypes.Route, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	return s.agent.updateRoutes(ctx, routes)
 }
 
 // ListRoutes lists all routes and their configurations in the sandbox.
 func (s *Sandbox) ListRoutes(ctx context.Context) ([]*pbTypes.Route, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	return s.agent.listRoutes(ctx)
 }
 
@@ -1329,6 +1389,10 @@ func (s *Sandbox) addContainer(c *Container) error {
 // This should be called only when the sandbox is already created.
 // It will add new container config to sandbox.config.Containers
 func (s *Sandbox) CreateContainer(ctx context.Context, contConfig ContainerConfig) (VCContainer, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	// Update sandbox config to include the new container's config
 	s.config.Containers = append(s.config.Containers, contConfig)
 
@@ -1397,6 +1461,10 @@ func (s *Sandbox) CreateContainer(ctx context.Context, contConfig ContainerConfi
 
 // StartContainer starts a container in the sandbox
 func (s *Sandbox) StartContainer(ctx context.Context, containerID string) (VCContainer, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	// Fetch the container.
 	c, err := s.findContainer(containerID)
 	if err != nil {
@@ -1429,6 +1497,10 @@ func (s *Sandbox) StartContainer(ctx context.Context, containerID string) (VCCon
 
 // StopContainer stops a container in the sandbox
 func (s *Sandbox) StopContainer(ctx context.Context, containerID string, force bool) (VCContainer, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	// Fetch the container.
 	c, err := s.findContainer(containerID)
 	if err != nil {
@@ -1454,6 +1526,11 @@ func (s *Sandbox) KillContainer(ctx context.Context, containerID string, signal
 		return err
 	}
 
+	// The agent cannot deliver the signal while the sandbox is frozen.
+	if err := s.thawIfFrozen(ctx); err != nil {
+		return err
+	}
+
 	// Send a signal to the process.
 	err = c.kill(ctx, signal, all)
 
@@ -1468,6 +1545,10 @@ func (s *Sandbox) KillContainer(ctx context.Context, containerID string, signal
 
 // DeleteContainer deletes a container from the sandbox
 func (s *Sandbox) DeleteContainer(ctx context.Context, containerID string) (VCContainer, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	if containerID == "" {
 		return nil, types.ErrNeedContainerID
 	}
@@ -1534,6 +1615,10 @@ func (s *Sandbox) StatusContainer(containerID string) (ContainerStatus, error) {
 // EnterContainer is the virtcontainers container command execution entry point.
 // EnterContainer enters an already running container and runs a given command.
 func (s *Sandbox) EnterContainer(ctx context.Context, containerID string, cmd types.Cmd) (VCContainer, *Process, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, nil, err
+	}
+
 	// Fetch the container.
 	c, err := s.findContainer(containerID)
 	if err != nil {
@@ -1551,6 +1636,10 @@ func (s *Sandbox) EnterContainer(ctx context.Context, containerID string, cmd ty
 
 // UpdateContainer update a running container.
 func (s *Sandbox) UpdateContainer(ctx context.Context, containerID string, resources specs.LinuxResources) error {
+	if err := s.checkNotFrozen(); err != nil {
+		return err
+	}
+
 	// Fetch the container.
 	c, err := s.findContainer(containerID)
 	if err != nil {
@@ -1577,6 +1666,10 @@ func (s *Sandbox) UpdateContainer(ctx context.Context, containerID string, resou
 
 // StatsContainer return the stats of a running container
 func (s *Sandbox) StatsContainer(ctx context.Context, containerID string) (ContainerStats, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return ContainerStats{}, err
+	}
+
 	// Fetch the container.
 	c, err := s.findContainer(containerID)
 	if err != nil {
@@ -1590,6 +1683,20 @@ func (s *Sandbox) StatsContainer(ctx context.Context, containerID string) (Conta
 	return *stats, nil
 }
 
+// ListProcesses returns the processes running in the container containerID.
+func (s *Sandbox) ListProcesses(ctx context.Context, containerID string) ([]*grpc.GuestProcess, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
+	c, err := s.findContainer(containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.processes(ctx)
+}
+
 // Stats returns the stats of a running sandbox
 func (s *Sandbox) Stats(ctx context.Context) (SandboxStats, error) {
 
@@ -1621,6 +1728,10 @@ func (s *Sandbox) Stats(ctx context.Context) (SandboxStats, error) {
 
 // PauseContainer pauses a running container.
 func (s *Sandbox) PauseContainer(ctx context.Context, containerID string) error {
+	if err := s.checkNotFrozen(); err != nil {
+		return err
+	}
+
 	// Fetch the container.
 	c, err := s.findContainer(containerID)
 	if err != nil {
@@ -1640,6 +1751,10 @@ func (s *Sandbox) PauseContainer(ctx context.Context, containerID string) error
 
 // ResumeContainer resumes a paused container.
 func (s *Sandbox) ResumeContainer(ctx context.Context, containerID string) error {
+	if err := s.checkNotFrozen(); err != nil {
+		return err
+	}
+
 	// Fetch the container.
 	c, err := s.findContainer(containerID)
 	if err != nil {
@@ -1657,6 +1772,175 @@ func (s *Sandbox) ResumeContainer(ctx context.Context, containerID string) error
 	return nil
 }
 
+// Freeze pauses the running containers of the sandbox, then stops the vCPUs
+// of the VM so that the sandbox releases the host CPUs until it is thawed.
+// If reclaimMemory is true, the guest memory is ballooned down to the
+// freeze_memory of the hypervisor before the vCPUs are stopped.
+func (s *Sandbox) Freeze(ctx context.Context, reclaimMemory bool) error {
+	span, ctx := katatrace.Trace(ctx, s.Logger(), "Freeze", sandboxTracingTags, map[string]string{"sandbox_id": s.id})
+	defer span.End()
+
+	if err := exp.Check(s.config.Experimental, exp.SandboxFreeze); err != nil {
+		return err
+	}
+
+	if s.state.State != types.StateRunning {
+		return fmt.Errorf("Sandbox not running, impossible to freeze it")
+	}
+
+	if reclaimMemory && s.config.HypervisorConfig.FreezeMemory == 0 {
+		return fmt.Errorf("Cannot reclaim the memory of the sandbox, freeze_memory is not set")
+	}
+
+	var frozen []string
+	rollback := func() {
+		for _, id := range frozen {
+			if err := s.containers[id].resume(ctx); err != nil {
+				s.Logger().WithError(err).WithField("container", id).Warn("failed to resume container")
+			}
+		}
+	}
+
+	for id, c := range s.containers {
+		if c.state.State != types.StateRunning {
+			continue
+		}
+
+		if err := c.pause(ctx); err != nil {
+			rollback()
+			return err
+		}
+		frozen = append(frozen, id)
+	}
+
+	// the guest inflates the balloon, which requires its vCPUs
+	if reclaimMemory {
+		if err := s.hypervisor.ResizeBalloon(ctx, s.config.HypervisorConfig.FreezeMemory); err != nil {
+			rollback()
+			return err
+		}
+	}
+
+	// the agent cannot answer the monitor while the vCPUs are stopped
+	s.setMonitorFrozen(true)
+
+	if err := s.hypervisor.PauseVM(ctx); err != nil {
+		s.setMonitorFrozen(false)
+		s.deflateBalloon(ctx)
+		rollback()
+		return err
+	}
+
+	if err := s.setSandboxState(types.StatePaused); err != nil {
+		if resumeErr := s.hypervisor.ResumeVM(ctx); resumeErr != nil {
+			s.Logger().WithError(resumeErr).Warn("failed to resume the VM")
+		}
+		s.setMonitorFrozen(false)
+		s.deflateBalloon(ctx)
+		rollback()
+		return err
+	}
+	s.state.FrozenContainers = frozen
+
+	return s.storeSandbox(ctx)
+}
+
+// Thaw restarts the vCPUs of a frozen sandbox, gives its memory back to the
+// guest and resumes the containers paused by Freeze.
+func (s *Sandbox) Thaw(ctx context.Context) error {
+	span, ctx := katatrace.Trace(ctx, s.Logger(), "Thaw", sandboxTracingTags, map[string]string{"sandbox_id": s.id})
+	defer span.End()
+
+	if s.state.State != types.StatePaused {
+		return fmt.Errorf("Sandbox not frozen, impossible to thaw it")
+	}
+
+	if err := s.hypervisor.ResumeVM(ctx); err != nil {
+		return err
+	}
+
+	s.setMonitorFrozen(false)
+	s.deflateBalloon(ctx)
+
+	if err := s.setSandboxState(types.StateRunning); err != nil {
+		return err
+	}
+
+	var resumeErr error
+	for _, id := range s.state.FrozenContainers {
+		c, ok := s.containers[id]
+		if !ok || c.state.State != types.StatePaused {
+			// deleted or resumed since
+			continue
+		}
+
+		if err := c.resume(ctx); err != nil {
+			s.Logger().WithError(err).WithField("container", id).Warn("failed to resume container")
+			if resumeErr == nil {
+				resumeErr = err
+			}
+		}
+	}
+	s.state.FrozenContainers = nil
+
+	if err := s.storeSandbox(ctx); err != nil {
+		return err
+	}
+
+	return resumeErr
+}
+
+// checkNotFrozen fails the operations which need the agent while the vCPUs
+// of the sandbox are stopped by Freeze.
+func (s *Sandbox) checkNotFrozen() error {
+	if s.state.State == types.StatePaused {
+		return errSandboxFrozen
+	}
+
+	return nil
+}
+
+// thawIfFrozen thaws the sandbox if it is frozen, for the operations which
+// cannot wait for the sandbox to be thawed, like stopping it.
+func (s *Sandbox) thawIfFrozen(ctx context.Context) error {
+	if s.state.State != types.StatePaused {
+		return nil
+	}
+
+	s.Logger().Info("Thawing the frozen sandbox")
+
+	// Thaw fails after restarting the vCPUs if a container could not be
+	// resumed, which does not matter here.
+	if err := s.Thaw(ctx); err != nil && s.state.State == types.StatePaused {
+		return err
+	}
+
+	return nil
+}
+
+// setMonitorFrozen suspends or resumes the agent health checks of the
+// sandbox monitor, if it is running.
+func (s *Sandbox) setMonitorFrozen(frozen bool) {
+	s.Lock()
+	m := s.monitor
+	s.Unlock()
+
+	if m != nil {
+		m.setFrozen(frozen)
+	}
+}
+
+// deflateBalloon gives back to the guest the memory reclaimed by Freeze.
+func (s *Sandbox) deflateBalloon(ctx context.Context) {
+	if s.config.HypervisorConfig.FreezeMemory == 0 {
+		return
+	}
+
+	if err := s.hypervisor.ResizeBalloon(ctx, s.hypervisor.GetTotalMemoryMB(ctx)); err != nil {
+		s.Logger().WithError(err).Warn("failed to deflate the memory balloon")
+	}
+}
+
 // createContainers registers all containers, create the
 // containers in the guest.
 func (s *Sandbox) createContainers(ctx context.Context) error {
@@ -1729,6 +2013,7 @@ func (s *Sandbox) Start(ctx context.Context) error {
 	}
 
 	s.Logger().Info("Sandbox is started")
+	s.publishEvent(EventSandboxStarted, "", nil, nil)
 
 	return nil
 }
@@ -1749,6 +2034,11 @@ func (s *Sandbox) Stop(ctx context.Context, force bool) error {
 		return err
 	}
 
+	// The containers are stopped through the agent, which needs the vCPUs.
+	if err := s.thawIfFrozen(ctx); err != nil && !force {
+		return err
+	}
+
 	for _, c := range s.containers {
 		if err := c.stop(ctx, force); err != nil {
 			return err
@@ -1785,6 +2075,8 @@ func (s *Sandbox) Stop(ctx context.Context, force bool) error {
 
 	s.cleanSwap(ctx)
 
+	s.publishEvent(EventSandboxStopped, "", nil, nil)
+
 	return nil
 }
 
@@ -1838,10 +2130,14 @@ func (s *Sandbox) unsetSandboxBlockIndex(index int) error {
 
 // HotplugAddDevice is used for add a device to sandbox
 // Sandbox implement DeviceReceiver interface from device/api/interface.go
-func (s *Sandbox) HotplugAddDevice(ctx context.Context, device api.Device, devType config.DeviceType) error {
+func (s *Sandbox) HotplugAddDevice(ctx context.Context, device api.Device, devType config.DeviceType) (err error) {
 	span, ctx := katatrace.Trace(ctx, s.Logger(), "HotplugAddDevice", sandboxTracingTags, map[string]string{"sandbox_id": s.id})
 	defer span.End()
 
+	defer func() {
+		s.publishDeviceEvent(EventDeviceHotplugged, device, devType, err)
+	}()
+
 	if s.sandboxController != nil {
 		if err := s.sandboxController.AddDevice(device.GetHostPath()); err != nil {
 			s.Logger().WithError(err).WithField("device", device).
@@ -1883,6 +2179,13 @@ func (s *Sandbox) HotplugAddDevice(ctx context.Context, device api.Device, devTy
 		}
 		_, err := s.hypervisor.HotplugAddDevice(ctx, vhostUserBlkDevice.VhostUserDeviceAttrs, VhostuserDev)
 		return err
+	case config.DeviceVDPA:
+		vdpaDev, ok := device.GetDeviceInfo().(*config.VDPADev)
+		if !ok {
+			return fmt.Errorf("device type mismatch, expect device type to be %s", devType)
+		}
+		_, err := s.hypervisor.HotplugAddDevice(ctx, vdpaDev, VDPADev)
+		return err
 	case config.DeviceGeneric:
 		// TODO: what?
 		return nil
@@ -1892,7 +2195,11 @@ func (s *Sandbox) HotplugAddDevice(ctx context.Context, device api.Device, devTy
 
 // HotplugRemoveDevice is used for removing a device from sandbox
 // Sandbox implement DeviceReceiver interface from device/api/interface.go
-func (s *Sandbox) HotplugRemoveDevice(ctx context.Context, device api.Device, devType config.DeviceType) error {
+func (s *Sandbox) HotplugRemoveDevice(ctx context.Context, device api.Device, devType config.DeviceType) (err error) {
+	defer func() {
+		s.publishDeviceEvent(EventDeviceHotunplugged, device, devType, err)
+	}()
+
 	defer func() {
 		if s.sandboxController != nil {
 			if err := s.sandboxController.RemoveDevice(device.GetHostPath()); err != nil {
@@ -1941,6 +2248,13 @@ func (s *Sandbox) HotplugRemoveDevice(ctx context.Context, device api.Device, de
 		}
 		_, err := s.hypervisor.HotplugRemoveDevice(ctx, vhostUserDeviceAttrs, VhostuserDev)
 		return err
+	case config.DeviceVDPA:
+		vdpaDev, ok := device.GetDeviceInfo().(*config.VDPADev)
+		if !ok {
+			return fmt.Errorf("device type mismatch, expect device type to be %s", devType)
+		}
+		_, err := s.hypervisor.HotplugRemoveDevice(ctx, vdpaDev, VDPADev)
+		return err
 	case config.DeviceGeneric:
 		// TODO: what?
 		return nil
@@ -2057,6 +2371,12 @@ func (s *Sandbox) updateResources(ctx context.Context) error {
 	// Update VCPUs
 	s.Logger().WithField("cpus-sandbox", sandboxVCPUs).Debugf("Request to hypervisor to update vCPUs")
 	oldCPUs, newCPUs, err := s.hypervisor.ResizeVCPUs(ctx, sandboxVCPUs)
+	if err != nil || oldCPUs != newCPUs {
+		s.publishEvent(EventVCPUsResized, "", err, map[string]string{
+			"requested": strconv.FormatUint(uint64(sandboxVCPUs), 10),
+			"vcpus":     strconv.FormatUint(uint64(newCPUs), 10),
+		})
+	}
 	if err != nil {
 		return err
 	}
@@ -2109,7 +2429,14 @@ func (s *Sandbox) updateResources(ctx context.Context) error {
 		}
 
 		// Add the memory to the guest and online the memory:
-		if err := s.updateMemory(ctx, newMemoryMB); err != nil {
+		err := s.updateMemory(ctx, newMemoryMB)
+		if err != nil || newMemoryMB != currentMemoryMB {
+			s.publishEvent(EventMemoryResized, "", err, map[string]string{
+				"requested-mb": strconv.FormatUint(uint64(finalMemoryMB), 10),
+				"memory-mb":    strconv.FormatUint(uint64(newMemoryMB), 10),
+			})
+		}
+		if err != nil {
 			return err
 		}
 
@@ -2370,7 +2697,47 @@ func (s *Sandbox) GetPatchedOCISpec() *specs.Spec {
 }
 
 func (s *Sandbox) GetOOMEvent(ctx context.Context) (string, error) {
-	return s.agent.getOOMEvent(ctx)
+	containerID, err := s.agent.getOOMEvent(ctx)
+	if err == nil {
+		s.publishEvent(EventGuestOOM, containerID, nil, nil)
+	}
+	return containerID, err
+}
+
+// SubscribeEvents returns a channel receiving the sandbox events, and a
+// function to call once done with it. The channel is closed when the
+// sandbox is deleted.
+func (s *Sandbox) SubscribeEvents() (<-chan Event, func()) {
+	return s.events.subscribe()
+}
+
+// publishEvent publishes an event of type eventType to the subscribers
+// of the sandbox events.
+func (s *Sandbox) publishEvent(eventType EventType, containerID string, err error, data map[string]string) {
+	e := Event{
+		Type:        eventType,
+		SandboxID:   s.id,
+		ContainerID: containerID,
+		Timestamp:   time.Now(),
+		Data:        data,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	s.events.publish(e)
+}
+
+func (s *Sandbox) publishDeviceEvent(eventType EventType, device api.Device, devType config.DeviceType, err error) {
+	if devType == config.DeviceGeneric {
+		return
+	}
+
+	s.publishEvent(eventType, "", err, map[string]string{
+		"device":    device.DeviceID(),
+		"type":      string(devType),
+		"host-path": device.GetHostPath(),
+	})
 }
 
 func (s *Sandbox) GetAgentURL() (string, error) {
@@ -2379,16 +2746,32 @@ func (s *Sandbox) GetAgentURL() (string, error) {
 
 // GetIPTables will obtain the iptables from the guest
 func (s *Sandbox) GetIPTables(ctx context.Context, isIPv6 bool) ([]byte, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	return s.agent.getIPTables(ctx, isIPv6)
 }
 
 // SetIPTables will set the iptables in the guest
 func (s *Sandbox) SetIPTables(ctx context.Context, isIPv6 bool, data []byte) error {
+	if err := s.checkNotFrozen(); err != nil {
+		return err
+	}
+
 	return s.agent.setIPTables(ctx, isIPv6, data)
 }
 
 // GuestVolumeStats return the filesystem stat of a given volume in the guest.
 func (s *Sandbox) GuestVolumeStats(ctx context.Context, volumePath string) ([]byte, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
+	if guestPath, ok := s.nfsVolumeGuestPath(volumePath); ok {
+		return s.agent.getGuestVolumeStats(ctx, guestPath)
+	}
+
 	guestMountPath, err := s.guestMountPath(volumePath)
 	if err != nil {
 		return nil, err
@@ -2398,14 +2781,171 @@ func (s *Sandbox) GuestVolumeStats(ctx context.Context, volumePath string) ([]by
 
 // ResizeGuestVolume resizes a volume in the guest.
 func (s *Sandbox) ResizeGuestVolume(ctx context.Context, volumePath string, size uint64) error {
+	if err := s.checkNotFrozen(); err != nil {
+		return err
+	}
+
 	// TODO: https://github.com/kata-containers/kata-containers/issues/3694.
+	if _, ok := s.nfsVolumeGuestPath(volumePath); ok {
+		return fmt.Errorf("nfs volume %s can only be resized on the NFS server", volumePath)
+	}
+
 	guestMountPath, err := s.guestMountPath(volumePath)
 	if err != nil {
 		return err
 	}
+
+	// The host block devices and the vhost-user-blk backends are resized
+	// out of band, only the image files of file volumes are resized here.
+	if err := s.resizeVolumeFile(ctx, volumePath, size); err != nil {
+		return err
+	}
+
 	return s.agent.resizeGuestVolume(ctx, guestMountPath, size)
 }
 
+// resizeVolumeFile grows the image file backing a block device volume to
+// size bytes and notifies the VM of the new capacity of the device. It is a
+// no-op for volumes not backed by an image file.
+func (s *Sandbox) resizeVolumeFile(ctx context.Context, volumePath string, size uint64) error {
+	st, err := os.Stat(volumePath)
+	if err != nil {
+		return err
+	}
+	if !st.Mode().IsRegular() {
+		return nil
+	}
+
+	for _, c := range s.containers {
+		for _, m := range c.mounts {
+			if volumePath != m.Source || m.BlockDeviceID == "" {
+				continue
+			}
+
+			device := s.devManager.GetDeviceByID(m.BlockDeviceID)
+			if device == nil {
+				return fmt.Errorf("device %s not found for volume %s", m.BlockDeviceID, volumePath)
+			}
+
+			drive, ok := device.GetDeviceInfo().(*config.BlockDrive)
+			if !ok || drive == nil {
+				return fmt.Errorf("volume %s is not attached as a block device", volumePath)
+			}
+
+			// Do not touch the file if the VM cannot be told about it.
+			caps := s.hypervisor.Capabilities(ctx)
+			if !caps.IsBlockDeviceResizeSupported() {
+				return fmt.Errorf("%s does not support resizing the file volume %s", s.config.HypervisorType, volumePath)
+			}
+
+			if size < uint64(st.Size()) {
+				return fmt.Errorf("cannot shrink volume %s from %d to %d bytes", volumePath, st.Size(), size)
+			}
+			if err := os.Truncate(volumePath, int64(size)); err != nil {
+				return err
+			}
+
+			if err := s.hypervisor.ResizeBlockDevice(ctx, drive, size); err != nil {
+				// The guest did not see the new size, the file can
+				// safely go back to its previous size.
+				if terr := os.Truncate(volumePath, st.Size()); terr != nil {
+					s.Logger().WithError(terr).WithField("volume", volumePath).Warn("failed to restore the volume file size")
+				}
+				return err
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// nfsVolumeGuestPath returns the guest path of the NFS export of a direct
+// assigned volume, if the export is shared with the guest.
+func (s *Sandbox) nfsVolumeGuestPath(export string) (string, bool) {
+	for _, c := range s.containers {
+		for _, m := range c.mounts {
+			if m.HostPath != "" && m.Source == nfsVolumeHostPath(s.id, c.id, export) {
+				return filepath.Join(kataGuestSharedDir(), filepath.Base(m.HostPath)), true
+			}
+		}
+	}
+	return "", false
+}
+
+// UpdateVolumeRateLimiter changes the I/O limits of a block device volume.
+func (s *Sandbox) UpdateVolumeRateLimiter(ctx context.Context, volumePath string, rateLimiter *config.BlockRateLimiter) error {
+	for _, c := range s.containers {
+		for _, m := range c.mounts {
+			if volumePath != m.Source || m.BlockDeviceID == "" {
+				continue
+			}
+
+			device := s.devManager.GetDeviceByID(m.BlockDeviceID)
+			if device == nil {
+				return fmt.Errorf("device %s not found for volume %s", m.BlockDeviceID, volumePath)
+			}
+
+			drive, ok := device.GetDeviceInfo().(*config.BlockDrive)
+			if !ok || drive == nil {
+				return fmt.Errorf("volume %s is not attached as a block device", volumePath)
+			}
+
+			if err := s.hypervisor.UpdateBlockRateLimiter(ctx, drive, rateLimiter); err != nil {
+				return err
+			}
+
+			// the limits are kept in the drive, to be restored along
+			// with the sandbox
+			return s.Save()
+		}
+	}
+	return fmt.Errorf("block device mount %s not found in sandbox", volumePath)
+}
+
+// SnapshotVolume copies a crash-consistent snapshot of a block device
+// volume, of a block device container rootfs or of the guest image to
+// snapshotPath, without stopping the sandbox.
+func (s *Sandbox) SnapshotVolume(ctx context.Context, volumePath, snapshotPath string) error {
+	span, ctx := katatrace.Trace(ctx, s.Logger(), "SnapshotVolume", sandboxTracingTags, map[string]string{"sandbox_id": s.id})
+	defer span.End()
+
+	if err := exp.Check(s.config.Experimental, exp.VolumeSnapshot); err != nil {
+		return err
+	}
+
+	if imagePath, err := s.config.HypervisorConfig.ImageAssetPath(); err == nil && imagePath != "" && imagePath == volumePath {
+		return s.hypervisor.SnapshotBlockDevice(ctx, &config.BlockDrive{File: imagePath, ReadOnly: true}, snapshotPath)
+	}
+
+	for _, c := range s.containers {
+		var deviceIDs []string
+		for _, m := range c.mounts {
+			if volumePath == m.Source && m.BlockDeviceID != "" {
+				deviceIDs = append(deviceIDs, m.BlockDeviceID)
+			}
+		}
+		if c.rootFs.Source == volumePath && c.state.BlockDeviceID != "" {
+			deviceIDs = append(deviceIDs, c.state.BlockDeviceID)
+		}
+
+		for _, id := range deviceIDs {
+			device := s.devManager.GetDeviceByID(id)
+			if device == nil {
+				return fmt.Errorf("device %s not found for volume %s", id, volumePath)
+			}
+
+			drive, ok := device.GetDeviceInfo().(*config.BlockDrive)
+			if !ok || drive == nil {
+				return fmt.Errorf("volume %s is not attached as a block device", volumePath)
+			}
+
+			return s.hypervisor.SnapshotBlockDevice(ctx, drive, snapshotPath)
+		}
+	}
+	return fmt.Errorf("block device %s not found in sandbox", volumePath)
+}
+
 func (s *Sandbox) guestMountPath(volumePath string) (string, error) {
 	// verify the device even exists
 	if _, err := os.Stat(volumePath); err != nil {
@@ -2552,17 +3092,22 @@ func (s *Sandbox) checkVCPUsPinning(ctx context.Context) error {
 	}
 
 	// if equal, we can now start vCPU threads pinning
-	i := 0
-	for _, tid := range vCPUThreadsMap.vcpus {
+	// vCPUs of a guest NUMA node are kept on the host node backing it
+	var vcpus []int
+	for vcpu := range vCPUThreadsMap.vcpus {
+		vcpus = append(vcpus, vcpu)
+	}
+	assignment := assignVCPUs(vcpus, cpuSetSlice, s.config.HypervisorConfig.NUMANodes)
+	for vcpu, tid := range vCPUThreadsMap.vcpus {
+		cpu := assignment[vcpu]
 		unixCPUSet := unix.CPUSet{}
-		unixCPUSet.Set(cpuSetSlice[i])
+		unixCPUSet.Set(cpu)
 		if err := unix.SchedSetaffinity(tid, &unixCPUSet); err != nil {
 			if err := s.resetVCPUsPinning(ctx, vCPUThreadsMap, cpuSetSlice); err != nil {
 				return err
 			}
-			return fmt.Errorf("failed to set vcpu thread %d affinity to cpu %d: %v", tid, cpuSetSlice[i], err)
+			return fmt.Errorf("failed to set vcpu thread %d affinity to cpu %d: %v", tid, cpu, err)
 		}
-		i++
 	}
 	s.isVCPUsPinningOn = true
 	return nil
@@ -2584,5 +3129,9 @@ func (s *Sandbox) resetVCPUsPinning(ctx context.Context, vCPUThreadsMap VcpuThre
 
 // PullImage pulls an image on a sandbox.
 func (s *Sandbox) PullImage(ctx context.Context, req *image.PullImageReq) (*image.PullImageResp, error) {
+	if err := s.checkNotFrozen(); err != nil {
+		return nil, err
+	}
+
 	return s.agent.PullImage(ctx, req)
 }
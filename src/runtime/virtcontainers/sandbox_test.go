@@ -16,11 +16,13 @@ import (
 	"syscall"
 	"testing"
 
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/api"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/drivers"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/device/manager"
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/fs"
 
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
@@ -1679,3 +1681,289 @@ func TestSandboxHugepageLimit(t *testing.T) {
 	err = s.updateResources(context.Background())
 	assert.NoError(t, err)
 }
+
+func TestSandboxUpdateVolumeRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	dm := manager.NewDeviceManager(config.VirtioBlock, false, "", nil)
+	device, err := dm.NewDevice(config.DeviceInfo{
+		HostPath:      "/dev/vdb",
+		ContainerPath: "/data",
+		DevType:       "b",
+		Major:         252,
+		Minor:         16,
+	})
+	assert.NoError(err)
+
+	c := &Container{
+		id: "100",
+		mounts: []Mount{
+			{
+				Source:        "/dev/vdb",
+				Destination:   "/data",
+				Type:          "bind",
+				BlockDeviceID: device.DeviceID(),
+			},
+		},
+	}
+
+	network, err := NewNetwork()
+	assert.NoError(err)
+
+	sandbox := &Sandbox{
+		id:         "100",
+		containers: map[string]*Container{c.id: c},
+		hypervisor: &mockHypervisor{},
+		devManager: dm,
+		network:    network,
+		ctx:        context.Background(),
+		config:     &SandboxConfig{},
+	}
+	c.sandbox = sandbox
+
+	sandbox.store, err = persist.GetDriver()
+	assert.NoError(err)
+	defer sandbox.store.Destroy(sandbox.id)
+
+	rateLimiter := &config.BlockRateLimiter{OpsMaxRate: 100}
+
+	err = sandbox.UpdateVolumeRateLimiter(context.Background(), "/dev/vdc", rateLimiter)
+	assert.Error(err)
+
+	// Not attached yet
+	err = sandbox.UpdateVolumeRateLimiter(context.Background(), "/dev/vdb", rateLimiter)
+	assert.Error(err)
+
+	err = device.Attach(context.Background(), &api.MockDeviceReceiver{})
+	assert.NoError(err)
+
+	err = sandbox.UpdateVolumeRateLimiter(context.Background(), "/dev/vdb", rateLimiter)
+	assert.NoError(err)
+	assert.Equal(rateLimiter, device.GetDeviceInfo().(*config.BlockDrive).RateLimiter)
+
+	// the new limits are saved with the sandbox
+	ss, _, err := sandbox.store.FromDisk(sandbox.id)
+	assert.NoError(err)
+	assert.Len(ss.Devices, 1)
+	assert.Equal(rateLimiter, ss.Devices[0].BlockDrive.RateLimiter)
+}
+
+func TestSandboxResizeVolumeFile(t *testing.T) {
+	assert := assert.New(t)
+
+	image := filepath.Join(t.TempDir(), "volume.img")
+	assert.NoError(os.WriteFile(image, make([]byte, 1024), 0600))
+
+	dm := manager.NewDeviceManager(config.VirtioBlock, false, "", nil)
+	device, err := dm.NewDevice(config.DeviceInfo{
+		HostPath:      image,
+		ContainerPath: "/data",
+		DevType:       "b",
+		Major:         -1,
+		Minor:         -1,
+		BackingFile:   true,
+	})
+	assert.NoError(err)
+	assert.NoError(device.Attach(context.Background(), &api.MockDeviceReceiver{}))
+
+	c := &Container{
+		id: "100",
+		mounts: []Mount{
+			{
+				Source:        image,
+				Destination:   "/data",
+				Type:          "ext4",
+				BlockDeviceID: device.DeviceID(),
+			},
+		},
+	}
+
+	sandbox := &Sandbox{
+		id:         "100",
+		containers: map[string]*Container{c.id: c},
+		hypervisor: &mockHypervisor{},
+		devManager: dm,
+		ctx:        context.Background(),
+		config:     &SandboxConfig{},
+	}
+	c.sandbox = sandbox
+
+	err = sandbox.resizeVolumeFile(context.Background(), image, 4096)
+	assert.NoError(err)
+	st, err := os.Stat(image)
+	assert.NoError(err)
+	assert.Equal(int64(4096), st.Size())
+
+	// image files can only grow
+	err = sandbox.resizeVolumeFile(context.Background(), image, 2048)
+	assert.Error(err)
+
+	// host devices are left alone
+	err = sandbox.resizeVolumeFile(context.Background(), t.TempDir(), 2048)
+	assert.NoError(err)
+
+	// the file is not touched when the hypervisor cannot resize the device
+	sandbox.hypervisor = &firecracker{}
+	err = sandbox.resizeVolumeFile(context.Background(), image, 8192)
+	assert.Error(err)
+	st, err = os.Stat(image)
+	assert.NoError(err)
+	assert.Equal(int64(4096), st.Size())
+}
+
+func TestSandboxFreezeThaw(t *testing.T) {
+	assert := assert.New(t)
+
+	configDir, err := writeContainerConfig(t)
+	assert.NoError(err)
+
+	var contConfigs []ContainerConfig
+	for _, id := range []string{"running", "paused"} {
+		contConfig := newTestContainerConfigNoop(id)
+		contConfig.Annotations[vcAnnotations.BundlePathKey] = configDir
+		contConfigs = append(contConfigs, contConfig)
+	}
+
+	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, newHypervisorConfig(nil, nil), NetworkConfig{}, contConfigs, nil)
+	assert.NoError(err)
+	defer cleanUp()
+
+	// freezing is an experimental feature
+	assert.NoError(s.setSandboxState(types.StateRunning))
+	assert.EqualError(s.Freeze(context.Background(), false), `experimental feature "sandbox_freeze" is not enabled`)
+	s.config.Experimental = []exp.Feature{*exp.Get(exp.SandboxFreeze)}
+	assert.NoError(s.setSandboxState(types.StateReady))
+
+	// frozen sandboxes must be running
+	assert.Error(s.Freeze(context.Background(), false))
+
+	assert.NoError(s.setSandboxState(types.StateRunning))
+	running := s.containers["running"]
+	paused := s.containers["paused"]
+	assert.NoError(running.setContainerState(types.StateRunning))
+	assert.NoError(paused.setContainerState(types.StatePaused))
+
+	// the memory can only be reclaimed with a balloon
+	assert.Error(s.Freeze(context.Background(), true))
+	assert.Equal(types.StateRunning, running.state.State)
+
+	assert.NoError(s.Freeze(context.Background(), false))
+	assert.Equal(types.StatePaused, s.state.State)
+	assert.Equal(types.StatePaused, running.state.State)
+	assert.Equal([]string{"running"}, s.state.FrozenContainers)
+
+	assert.Error(s.Freeze(context.Background(), false))
+
+	// the frozen containers are persisted
+	s2, err := fetchSandbox(context.Background(), s.ID())
+	assert.NoError(err)
+	assert.Equal([]string{"running"}, s2.state.FrozenContainers)
+
+	assert.NoError(s.Thaw(context.Background()))
+	assert.Equal(types.StateRunning, s.state.State)
+	assert.Equal(types.StateRunning, running.state.State)
+	// containers paused before the freeze stay paused
+	assert.Equal(types.StatePaused, paused.state.State)
+	assert.Empty(s.state.FrozenContainers)
+
+	assert.Error(s.Thaw(context.Background()))
+
+	// the operations which need the agent are rejected while frozen
+	assert.NoError(s.Freeze(context.Background(), false))
+	_, _, err = s.EnterContainer(context.Background(), "running", types.Cmd{})
+	assert.Equal(errSandboxFrozen, err)
+	_, err = s.ListInterfaces(context.Background())
+	assert.Equal(errSandboxFrozen, err)
+	assert.Equal(types.StatePaused, s.state.State)
+
+	// but killing a container thaws the sandbox
+	assert.NoError(s.KillContainer(context.Background(), "running", syscall.SIGTERM, false))
+	assert.Equal(types.StateRunning, s.state.State)
+	assert.Equal(types.StateRunning, running.state.State)
+
+	s.config.HypervisorConfig.FreezeMemory = 128
+	assert.NoError(s.Freeze(context.Background(), true))
+	assert.NoError(s.Thaw(context.Background()))
+
+	// and so does stopping it
+	assert.NoError(s.Freeze(context.Background(), false))
+	assert.NoError(s.Stop(context.Background(), false))
+	assert.Equal(types.StateStopped, s.state.State)
+}
+
+func TestSandboxSnapshotVolume(t *testing.T) {
+	assert := assert.New(t)
+
+	dm := manager.NewDeviceManager(config.VirtioBlock, false, "", nil)
+	device, err := dm.NewDevice(config.DeviceInfo{
+		HostPath:      "/dev/vdb",
+		ContainerPath: "/data",
+		DevType:       "b",
+		Major:         252,
+		Minor:         16,
+	})
+	assert.NoError(err)
+
+	c := &Container{
+		id: "100",
+		mounts: []Mount{
+			{
+				Source:        "/dev/vdb",
+				Destination:   "/data",
+				Type:          "bind",
+				BlockDeviceID: device.DeviceID(),
+			},
+		},
+	}
+
+	sandbox := &Sandbox{
+		id:         "100",
+		containers: map[string]*Container{c.id: c},
+		hypervisor: &mockHypervisor{},
+		devManager: dm,
+		ctx:        context.Background(),
+		config:     &SandboxConfig{},
+	}
+	c.sandbox = sandbox
+
+	// snapshots are an experimental feature
+	err = sandbox.SnapshotVolume(context.Background(), "/dev/vdb", "/tmp/snapshot")
+	assert.EqualError(err, `experimental feature "volume_snapshot" is not enabled`)
+	sandbox.config.Experimental = []exp.Feature{*exp.Get(exp.VolumeSnapshot)}
+
+	err = sandbox.SnapshotVolume(context.Background(), "/dev/vdc", "/tmp/snapshot")
+	assert.Error(err)
+
+	// Not attached yet
+	err = sandbox.SnapshotVolume(context.Background(), "/dev/vdb", "/tmp/snapshot")
+	assert.Error(err)
+
+	err = device.Attach(context.Background(), &api.MockDeviceReceiver{})
+	assert.NoError(err)
+
+	err = sandbox.SnapshotVolume(context.Background(), "/dev/vdb", "/tmp/snapshot")
+	assert.NoError(err)
+
+	// block device container rootfs
+	rootfs, err := dm.NewDevice(config.DeviceInfo{
+		HostPath:      "/dev/vdd",
+		ContainerPath: "/",
+		DevType:       "b",
+		Major:         252,
+		Minor:         48,
+	})
+	assert.NoError(err)
+	assert.NoError(rootfs.Attach(context.Background(), &api.MockDeviceReceiver{}))
+	c.rootFs.Source = "/dev/vdd"
+	c.state.BlockDeviceID = rootfs.DeviceID()
+
+	err = sandbox.SnapshotVolume(context.Background(), "/dev/vdd", "/tmp/snapshot")
+	assert.NoError(err)
+
+	// guest image
+	err = sandbox.SnapshotVolume(context.Background(), "/usr/share/kata-containers/kata-containers.img", "/tmp/snapshot")
+	assert.Error(err)
+	sandbox.config.HypervisorConfig.ImagePath = "/usr/share/kata-containers/kata-containers.img"
+	err = sandbox.SnapshotVolume(context.Background(), "/usr/share/kata-containers/kata-containers.img", "/tmp/snapshot")
+	assert.NoError(err)
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultSwtpmPath is the swtpm binary used when none is configured.
+	defaultSwtpmPath = "/usr/bin/swtpm"
+
+	// swtpmSocket is the name of the swtpm control socket.
+	swtpmSocket = "swtpm.sock"
+
+	// swtpmStateDir is the name of the directory holding the vTPM state,
+	// relative to the VM store path of the sandbox.
+	swtpmStateDir = "tpm"
+
+	// vTPMID is the ID of the vTPM device in the hypervisor.
+	vTPMID = "tpm0"
+)
+
+// swtpmTracingTags defines tags for the trace span
+var swtpmTracingTags = map[string]string{
+	"source":    "runtime",
+	"package":   "virtcontainers",
+	"subsystem": "swtpm",
+}
+
+var (
+	errSwtpmPathEmpty       = errors.New("swtpm path is empty")
+	errSwtpmSocketPathEmpty = errors.New("swtpm socket path is empty")
+	errSwtpmStatePathEmpty  = errors.New("swtpm state path is empty")
+)
+
+// swtpm supervises the software TPM emulator backing the vTPM of a sandbox.
+type swtpm struct {
+	// path to the swtpm binary
+	path string
+	// socketPath is the control socket the hypervisor connects to
+	socketPath string
+	// statePath is the directory holding the persistent vTPM state
+	statePath string
+	// debug enables swtpm logging in the state directory
+	debug bool
+	// PID process ID of swtpm process
+	PID int
+}
+
+// Open the control socket on behalf of swtpm and
+// return the file descriptor to be used by swtpm.
+func (s *swtpm) getSocketFD() (*os.File, error) {
+	if _, err := os.Stat(filepath.Dir(s.socketPath)); err != nil {
+		return nil, errors.Errorf("Socket directory does not exist %s", filepath.Dir(s.socketPath))
+	}
+
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: s.socketPath, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+
+	// swtpm runs as root while the hypervisor can run as non-root.
+	if err := utils.ChownToParent(s.socketPath); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	// no longer needed since fd is a dup
+	defer listener.Close()
+
+	listener.SetUnlinkOnClose(false)
+
+	return listener.File()
+}
+
+// Start the swtpm process
+func (s *swtpm) Start(ctx context.Context, onQuit onQuitFunc) (int, error) {
+	span, _ := katatrace.Trace(ctx, s.Logger(), "Start", swtpmTracingTags)
+	defer span.End()
+
+	if err := s.valid(); err != nil {
+		return 0, err
+	}
+
+	if err := utils.MkdirAllWithInheritedOwner(s.statePath, DirMode); err != nil {
+		return 0, fmt.Errorf("failed to create vTPM state directory %s: %v", s.statePath, err)
+	}
+
+	cmd := exec.Command(s.path)
+
+	socketFD, err := s.getSocketFD()
+	if err != nil {
+		return 0, err
+	}
+	defer socketFD.Close()
+
+	cmd.ExtraFiles = append(cmd.ExtraFiles, socketFD)
+
+	// Extra FDs for swtpm start from 3
+	socketFdNumber := 2 + uint(len(cmd.ExtraFiles))
+	args := s.args(socketFdNumber)
+	cmd.Args = append(cmd.Args, args...)
+
+	s.Logger().WithField("path", s.path).Info()
+	s.Logger().WithField("args", strings.Join(args, " ")).Info()
+
+	if err = utils.StartCmd(cmd); err != nil {
+		return 0, err
+	}
+
+	go func() {
+		cmd.Process.Wait()
+		s.Logger().Info("swtpm quits")
+		if onQuit != nil {
+			onQuit()
+		}
+	}()
+
+	s.PID = cmd.Process.Pid
+
+	return cmd.Process.Pid, nil
+}
+
+// Stop the swtpm process. The vTPM state is kept, it is removed
+// along with the VM store path of the sandbox.
+func (s *swtpm) Stop(ctx context.Context) error {
+	span, _ := katatrace.Trace(ctx, s.Logger(), "Stop", swtpmTracingTags)
+	defer span.End()
+
+	if s.PID == 0 {
+		s.Logger().WithField("invalid-swtpm-pid", s.PID).Warn("cannot kill swtpm")
+		return nil
+	}
+
+	if err := syscall.Kill(s.PID, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+		s.Logger().WithError(err).WithField("pid", s.PID).Warn("kill swtpm failed")
+		return nil
+	}
+	s.PID = 0
+
+	if err := os.Remove(s.socketPath); err != nil && !os.IsNotExist(err) {
+		s.Logger().WithError(err).WithField("path", s.socketPath).Warn("removing swtpm socket failed")
+	}
+
+	return nil
+}
+
+func (s *swtpm) args(fdSocketNumber uint) []string {
+	args := []string{
+		"socket",
+		// emulate a TPM 2.0
+		"--tpm2",
+		// persistent vTPM state
+		"--tpmstate", fmt.Sprintf("dir=%s,mode=0600", s.statePath),
+		// fd number of the control socket
+		"--ctrl", fmt.Sprintf("type=unixio,fd=%d", fdSocketNumber),
+		// exit once the hypervisor disconnects
+		"--terminate",
+	}
+
+	if s.debug {
+		args = append(args, "--log", fmt.Sprintf("file=%s,level=20", filepath.Join(s.statePath, "swtpm.log")))
+	}
+
+	return args
+}
+
+func (s *swtpm) valid() error {
+	if s.path == "" {
+		return errSwtpmPathEmpty
+	}
+
+	if s.socketPath == "" {
+		return errSwtpmSocketPathEmpty
+	}
+
+	if s.statePath == "" {
+		return errSwtpmStatePathEmpty
+	}
+
+	return nil
+}
+
+func (s *swtpm) Logger() *log.Entry {
+	return hvLogger.WithField("subsystem", "swtpm")
+}
+
+// newSwtpm returns the swtpm instance backing the vTPM of the VM id.
+func newSwtpm(config HypervisorConfig, id string, pid int) (*swtpm, error) {
+	socketPath, err := utils.BuildSocketPath(config.VMStorePath, id, swtpmSocket)
+	if err != nil {
+		return nil, err
+	}
+
+	path := config.SwtpmPath
+	if path == "" {
+		path = defaultSwtpmPath
+	}
+
+	return &swtpm{
+		path:       path,
+		socketPath: socketPath,
+		statePath:  filepath.Join(config.VMStorePath, id, swtpmStateDir),
+		debug:      config.Debug,
+		PID:        pid,
+	}, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwtpmValid(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &swtpm{}
+	assert.Equal(errSwtpmPathEmpty, s.valid())
+
+	s.path = "/usr/bin/swtpm"
+	assert.Equal(errSwtpmSocketPathEmpty, s.valid())
+
+	s.socketPath = "/tmp/swtpm.sock"
+	assert.Equal(errSwtpmStatePathEmpty, s.valid())
+
+	s.statePath = "/tmp/tpm"
+	assert.NoError(s.valid())
+}
+
+func TestSwtpmArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &swtpm{
+		path:       "/usr/bin/swtpm",
+		socketPath: "/tmp/swtpm.sock",
+		statePath:  "/tmp/tpm",
+	}
+
+	expected := "socket --tpm2 --tpmstate dir=/tmp/tpm,mode=0600 --ctrl type=unixio,fd=3 --terminate"
+	assert.Equal(expected, strings.Join(s.args(3), " "))
+
+	s.debug = true
+	expected += " --log file=/tmp/tpm/swtpm.log,level=20"
+	assert.Equal(expected, strings.Join(s.args(3), " "))
+}
+
+func TestSwtpmStart(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &swtpm{}
+	_, err := s.Start(context.Background(), nil)
+	assert.Error(err)
+
+	dir := t.TempDir()
+	s = &swtpm{
+		path:       "/usr/bin/swtpm-path",
+		socketPath: "/tmp/path/to/swtpm/swtpm.sock",
+		statePath:  filepath.Join(dir, "tpm"),
+	}
+	_, err = s.Start(context.Background(), nil)
+	assert.Error(err)
+	assert.DirExists(s.statePath)
+}
+
+func TestSwtpmStop(t *testing.T) {
+	s := &swtpm{}
+	assert.NoError(t, s.Stop(context.Background()))
+}
+
+func TestNewSwtpm(t *testing.T) {
+	assert := assert.New(t)
+
+	config := HypervisorConfig{
+		VMStorePath: t.TempDir(),
+		Debug:       true,
+	}
+
+	s, err := newSwtpm(config, "foo", 42)
+	assert.NoError(err)
+	assert.Equal(defaultSwtpmPath, s.path)
+	assert.Equal(filepath.Join(config.VMStorePath, "foo", swtpmSocket), s.socketPath)
+	assert.Equal(filepath.Join(config.VMStorePath, "foo", swtpmStateDir), s.statePath)
+	assert.Equal(42, s.PID)
+	assert.True(s.debug)
+
+	config.SwtpmPath = "/opt/swtpm"
+	s, err = newSwtpm(config, "foo", 0)
+	assert.NoError(err)
+	assert.Equal("/opt/swtpm", s.path)
+}
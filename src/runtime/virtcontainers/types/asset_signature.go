@@ -0,0 +1,536 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// SignatureSuffix is the suffix of the detached signature of an
+	// asset, base64 encoded, as made by `cosign sign-blob` or
+	// `openssl dgst -sha256 -sign`.
+	SignatureSuffix = ".sig"
+
+	// MinisignSuffix is the suffix of the detached minisign signature
+	// of an asset.
+	MinisignSuffix = ".minisig"
+
+	minisignAlgorithm         = "Ed"
+	minisignPrehashAlgorithm  = "ED"
+	minisignKeyIDLen          = 8
+	minisignUntrustedComment  = "untrusted comment:"
+	minisignTrustedComment    = "trusted comment:"
+	minisignPublicKeyLen      = 2 + minisignKeyIDLen + ed25519.PublicKeySize
+	minisignSignatureBlockLen = 2 + minisignKeyIDLen + ed25519.SignatureSize
+)
+
+// AssetVerificationCacheDir is the directory where the hashes of the
+// verified assets are recorded, so that the signature of an asset is only
+// verified once. The verified copies of the assets are kept in its
+// verifiedAssetsSubdir subdirectory.
+var AssetVerificationCacheDir = "/run/kata-containers/asset-verification"
+
+// verifiedAssetsSubdir is the subdirectory of AssetVerificationCacheDir
+// where the assets are copied to be verified. The copies are named after
+// their SHA-512 hash.
+const verifiedAssetsSubdir = "assets"
+
+// verifiedAssets maps the SHA-512 hash of the verified assets to the
+// fingerprint of the key which verified them.
+var verifiedAssets sync.Map
+
+// TrustedKey is a public key of a trust store.
+type TrustedKey struct {
+	key crypto.PublicKey
+	// Name is the name of the key file in the trust store.
+	Name string
+	// Fingerprint is the hex encoded SHA-256 hash of the key.
+	Fingerprint string
+	// minisignKeyID is only set for minisign keys.
+	minisignKeyID []byte
+}
+
+// TrustStore is a directory of public keys the signatures of the assets
+// are verified against. A key is either a PEM encoded PKIX public key
+// (ECDSA, Ed25519 or RSA) or a minisign public key.
+type TrustStore struct {
+	Path string
+	Keys []TrustedKey
+}
+
+// AssetVerification is the result of the signature verification of an
+// asset.
+type AssetVerification struct {
+	// Err is set when the asset could not be verified.
+	Err  error
+	Type AssetType
+	Path string
+	// VerifiedPath is the path of the copy of the asset which was
+	// verified. The hypervisor must use it rather than Path.
+	VerifiedPath string
+	// Hash is the hex encoded SHA-512 hash of the asset.
+	Hash string
+	// Key is the name of the trusted key which verified the asset.
+	Key string
+	// Cached is true when the asset was verified earlier.
+	Cached bool
+}
+
+// LoadTrustStore loads the public keys of the trust store at path.
+func LoadTrustStore(path string) (*TrustStore, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust store: %w", err)
+	}
+
+	store := &TrustStore{Path: path}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := parseTrustedKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s in trust store %s: %w", entry.Name(), path, err)
+		}
+		key.Name = entry.Name()
+
+		store.Keys = append(store.Keys, key)
+	}
+
+	if len(store.Keys) == 0 {
+		return nil, fmt.Errorf("trust store %s has no keys", path)
+	}
+
+	return store, nil
+}
+
+func parseTrustedKey(data []byte) (TrustedKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != "PUBLIC KEY" {
+			return TrustedKey{}, fmt.Errorf("unexpected PEM block %q", block.Type)
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return TrustedKey{}, err
+		}
+
+		switch key.(type) {
+		case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		default:
+			return TrustedKey{}, fmt.Errorf("unsupported public key type %T", key)
+		}
+
+		return TrustedKey{
+			key:         key,
+			Fingerprint: fingerprint(block.Bytes),
+		}, nil
+	}
+
+	lines, err := minisignLines(data)
+	if err != nil {
+		return TrustedKey{}, err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil {
+		return TrustedKey{}, fmt.Errorf("not a PEM or minisign public key: %w", err)
+	}
+
+	if len(raw) != minisignPublicKeyLen || string(raw[:2]) != minisignAlgorithm {
+		return TrustedKey{}, errors.New("invalid minisign public key")
+	}
+
+	return TrustedKey{
+		key:           ed25519.PublicKey(raw[2+minisignKeyIDLen:]),
+		Fingerprint:   fingerprint(raw),
+		minisignKeyID: raw[2 : 2+minisignKeyIDLen],
+	}, nil
+}
+
+// minisignLines returns the lines of a minisign file without their
+// untrusted comments.
+func minisignLines(data []byte) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, minisignUntrustedComment) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("empty minisign file")
+	}
+
+	return lines, nil
+}
+
+func fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *TrustStore) keyByFingerprint(fingerprint string) (TrustedKey, bool) {
+	for _, k := range s.Keys {
+		if k.Fingerprint == fingerprint {
+			return k, true
+		}
+	}
+
+	return TrustedKey{}, false
+}
+
+// Verify verifies the detached signature of the asset at path. The
+// signature is looked for next to the asset, in a file with the
+// SignatureSuffix or the MinisignSuffix suffix.
+//
+// The result is cached by the hash of the asset: an asset with the same
+// content is not verified again as long as the key which verified it is
+// still in the trust store.
+//
+// The asset is copied to a directory only root can write to and the copy is
+// verified, so that an asset replaced after its verification is not
+// launched: the hypervisor must use the VerifiedPath of the result.
+func (s *TrustStore) Verify(t AssetType, path string) AssetVerification {
+	v := AssetVerification{
+		Type: t,
+		Path: path,
+	}
+
+	asset, err := openAsset(path)
+	if err != nil {
+		v.Err = err
+		return v
+	}
+	defer asset.Close()
+
+	v.Hash = hex.EncodeToString(asset.sha512)
+	v.VerifiedPath = asset.Name()
+
+	if fp, ok := cachedVerification(v.Hash); ok {
+		if k, ok := s.keyByFingerprint(fp); ok {
+			v.Key = k.Name
+			v.Cached = true
+			return v
+		}
+	}
+
+	var key TrustedKey
+	key, v.Err = s.verifySignature(asset)
+	if v.Err != nil {
+		v.Err = fmt.Errorf("failed to verify the signature of %s %s: %w", t, path, v.Err)
+		return v
+	}
+
+	v.Key = key.Name
+	cacheVerification(v.Hash, key.Fingerprint)
+
+	return v
+}
+
+// asset is the verified copy of an asset, opened for verification.
+type asset struct {
+	*os.File
+	// path is the path of the asset the copy was made from.
+	path   string
+	sha256 []byte
+	sha512 []byte
+	// mapped is the content of the copy, mapped by content.
+	mapped []byte
+}
+
+// openAsset opens the verified copy of the asset at path, and makes it
+// first if there is no copy of the current content of the asset. The copy
+// is named after the hash of its own content, so it cannot differ from the
+// content which is verified, whatever happens to the asset in between.
+func openAsset(path string) (*asset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dir := filepath.Join(AssetVerificationCacheDir, verifiedAssetsSubdir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	sum256, sum512, err := hashAsset(f, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	copyPath := filepath.Join(dir, hex.EncodeToString(sum512))
+	if _, err := os.Stat(copyPath); os.IsNotExist(err) {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if copyPath, sum256, sum512, err = copyAsset(f, dir); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	c, err := os.Open(copyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &asset{
+		File:   c,
+		path:   path,
+		sha256: sum256,
+		sha512: sum512,
+	}, nil
+}
+
+// hashAsset computes the hashes of the asset read from r while writing it
+// to w, in a single pass.
+func hashAsset(r io.Reader, w io.Writer) ([]byte, []byte, error) {
+	h256 := sha256.New()
+	h512 := sha512.New()
+	if _, err := io.Copy(io.MultiWriter(w, h256, h512), r); err != nil {
+		return nil, nil, err
+	}
+
+	return h256.Sum(nil), h512.Sum(nil), nil
+}
+
+// copyAsset copies the asset read from r to dir, named after its SHA-512
+// hash, and returns the path and the hashes of the copy.
+func copyAsset(r io.Reader, dir string) (string, []byte, []byte, error) {
+	tmp, err := os.CreateTemp(dir, ".asset-")
+	if err != nil {
+		return "", nil, nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	sum256, sum512, err := hashAsset(r, tmp)
+	if err == nil {
+		err = tmp.Chmod(0444)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	path := filepath.Join(dir, hex.EncodeToString(sum512))
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", nil, nil, err
+	}
+
+	return path, sum256, sum512, nil
+}
+
+// content returns the content of the copy of the asset. Only Ed25519
+// signatures need it, as they are computed over the whole message rather
+// than a digest: the copy is mapped rather than read, it cannot change as
+// only root can write to it.
+func (a *asset) content() ([]byte, error) {
+	if a.mapped != nil {
+		return a.mapped, nil
+	}
+
+	info, err := a.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return []byte{}, nil
+	}
+
+	a.mapped, err = unix.Mmap(int(a.Fd()), 0, int(info.Size()), unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map %s: %w", a.Name(), err)
+	}
+
+	return a.mapped, nil
+}
+
+// Close unmaps the content of the copy of the asset, if it was mapped, and
+// closes it.
+func (a *asset) Close() error {
+	if a.mapped != nil {
+		if err := unix.Munmap(a.mapped); err != nil {
+			return err
+		}
+		a.mapped = nil
+	}
+
+	return a.File.Close()
+}
+
+func (s *TrustStore) verifySignature(a *asset) (TrustedKey, error) {
+	if sig, err := os.ReadFile(a.path + MinisignSuffix); err == nil {
+		return s.verifyMinisign(a, sig)
+	} else if !os.IsNotExist(err) {
+		return TrustedKey{}, err
+	}
+
+	encoded, err := os.ReadFile(a.path + SignatureSuffix)
+	if os.IsNotExist(err) {
+		return TrustedKey{}, fmt.Errorf("no %s or %s signature", SignatureSuffix, MinisignSuffix)
+	} else if err != nil {
+		return TrustedKey{}, err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return TrustedKey{}, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	var content []byte
+	for _, k := range s.Keys {
+		var ok bool
+
+		switch key := k.key.(type) {
+		case *ecdsa.PublicKey:
+			ok = ecdsa.VerifyASN1(key, a.sha256, sig)
+		case ed25519.PublicKey:
+			if k.minisignKeyID != nil {
+				break
+			}
+			if content == nil {
+				if content, err = a.content(); err != nil {
+					return TrustedKey{}, err
+				}
+			}
+			ok = ed25519.Verify(key, content, sig)
+		case *rsa.PublicKey:
+			ok = rsa.VerifyPKCS1v15(key, crypto.SHA256, a.sha256, sig) == nil
+		}
+
+		if ok {
+			return k, nil
+		}
+	}
+
+	return TrustedKey{}, fmt.Errorf("no key of trust store %s matches the signature", s.Path)
+}
+
+func (s *TrustStore) verifyMinisign(a *asset, data []byte) (TrustedKey, error) {
+	lines, err := minisignLines(data)
+	if err != nil {
+		return TrustedKey{}, err
+	}
+
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], minisignTrustedComment) {
+		return TrustedKey{}, errors.New("invalid minisign signature")
+	}
+
+	block, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(block) != minisignSignatureBlockLen {
+		return TrustedKey{}, errors.New("invalid minisign signature")
+	}
+
+	globalSig, err := base64.StdEncoding.DecodeString(lines[2])
+	if err != nil {
+		return TrustedKey{}, errors.New("invalid minisign global signature")
+	}
+
+	switch string(block[:2]) {
+	case minisignAlgorithm:
+	case minisignPrehashAlgorithm:
+		return TrustedKey{}, errors.New("prehashed minisign signatures are not supported, sign the asset with `minisign -S -l`")
+	default:
+		return TrustedKey{}, fmt.Errorf("unknown minisign signature algorithm %q", block[:2])
+	}
+
+	keyID := block[2 : 2+minisignKeyIDLen]
+	sig := block[2+minisignKeyIDLen:]
+
+	trustedComment := strings.TrimSpace(strings.TrimPrefix(lines[1], minisignTrustedComment))
+
+	for _, k := range s.Keys {
+		if !bytes.Equal(k.minisignKeyID, keyID) {
+			continue
+		}
+
+		content, err := a.content()
+		if err != nil {
+			return TrustedKey{}, err
+		}
+
+		key := k.key.(ed25519.PublicKey)
+		if !ed25519.Verify(key, content, sig) {
+			return TrustedKey{}, fmt.Errorf("invalid signature for key %s", k.Name)
+		}
+
+		if !ed25519.Verify(key, append(append([]byte{}, sig...), trustedComment...), globalSig) {
+			return TrustedKey{}, fmt.Errorf("invalid trusted comment signature for key %s", k.Name)
+		}
+
+		return k, nil
+	}
+
+	return TrustedKey{}, fmt.Errorf("key %X of the signature is not in trust store %s", keyID, s.Path)
+}
+
+func cachedVerification(hash string) (string, bool) {
+	if fp, ok := verifiedAssets.Load(hash); ok {
+		return fp.(string), true
+	}
+
+	data, err := os.ReadFile(filepath.Join(AssetVerificationCacheDir, hash))
+	if err != nil {
+		return "", false
+	}
+
+	fp := strings.TrimSpace(string(data))
+	verifiedAssets.Store(hash, fp)
+
+	return fp, true
+}
+
+// cacheVerification records the verification of an asset. Failing to
+// write the cache is not an error, the asset is verified again next time.
+func cacheVerification(hash, fingerprint string) {
+	verifiedAssets.Store(hash, fingerprint)
+
+	if err := os.MkdirAll(AssetVerificationCacheDir, 0755); err != nil {
+		return
+	}
+
+	_ = os.WriteFile(filepath.Join(AssetVerificationCacheDir, hash), []byte(fingerprint), 0600)
+}
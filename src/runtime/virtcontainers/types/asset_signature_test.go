@@ -0,0 +1,276 @@
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package types
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAssetVerificationCache(t *testing.T) {
+	orgCacheDir := AssetVerificationCacheDir
+	t.Cleanup(func() {
+		AssetVerificationCacheDir = orgCacheDir
+		verifiedAssets = sync.Map{}
+	})
+
+	AssetVerificationCacheDir = filepath.Join(t.TempDir(), "cache")
+	verifiedAssets = sync.Map{}
+}
+
+func writePEMKey(t *testing.T, dir, name string, key crypto.PublicKey) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	assert.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0600))
+}
+
+func writeAsset(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "vmlinux")
+	assert.NoError(t, os.WriteFile(path, assetContent, 0600))
+	return path
+}
+
+func writeSignature(t *testing.T, path string, sig []byte) {
+	assert.NoError(t, os.WriteFile(path+SignatureSuffix, []byte(base64.StdEncoding.EncodeToString(sig)+"\n"), 0600))
+}
+
+func TestLoadTrustStore(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	_, err := LoadTrustStore(dir)
+	assert.Error(err)
+
+	_, err = LoadTrustStore(filepath.Join(dir, "missing"))
+	assert.Error(err)
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	writePEMKey(t, dir, "ed25519.pem", pub)
+
+	store, err := LoadTrustStore(dir)
+	assert.NoError(err)
+	assert.Len(store.Keys, 1)
+	assert.Equal("ed25519.pem", store.Keys[0].Name)
+	assert.Len(store.Keys[0].Fingerprint, 64)
+
+	assert.NoError(os.WriteFile(filepath.Join(dir, "garbage"), []byte("not a key"), 0600))
+	_, err = LoadTrustStore(dir)
+	assert.Error(err)
+}
+
+func TestTrustStoreVerify(t *testing.T) {
+	assert := assert.New(t)
+	setupAssetVerificationCache(t)
+
+	storeDir := t.TempDir()
+	assetDir := t.TempDir()
+	path := writeAsset(t, assetDir)
+	digest := sha256.Sum256(assetContent)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+	writePEMKey(t, storeDir, "ecdsa.pem", &ecdsaKey.PublicKey)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	writePEMKey(t, storeDir, "rsa.pem", &rsaKey.PublicKey)
+
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	writePEMKey(t, storeDir, "ed25519.pem", edPub)
+
+	store, err := LoadTrustStore(storeDir)
+	assert.NoError(err)
+
+	// no signature
+	v := store.Verify(KernelAsset, path)
+	assert.Error(v.Err)
+	assert.Equal(assetContentHash, v.Hash)
+
+	ecdsaSig, err := ecdsa.SignASN1(rand.Reader, ecdsaKey, digest[:])
+	assert.NoError(err)
+	rsaSig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	assert.NoError(err)
+
+	for name, sig := range map[string][]byte{
+		"ecdsa.pem":   ecdsaSig,
+		"rsa.pem":     rsaSig,
+		"ed25519.pem": ed25519.Sign(edKey, assetContent),
+	} {
+		verifiedAssets = sync.Map{}
+		assert.NoError(os.RemoveAll(AssetVerificationCacheDir))
+
+		writeSignature(t, path, sig)
+
+		v = store.Verify(KernelAsset, path)
+		assert.NoError(v.Err, name)
+		assert.Equal(name, v.Key)
+		assert.False(v.Cached)
+	}
+
+	// a signature from an untrusted key
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	writeSignature(t, path, ed25519.Sign(otherKey, assetContent))
+
+	verifiedAssets = sync.Map{}
+	assert.NoError(os.RemoveAll(AssetVerificationCacheDir))
+	v = store.Verify(KernelAsset, path)
+	assert.Error(v.Err)
+
+	// a tampered asset
+	writeSignature(t, path, ed25519.Sign(edKey, assetContent))
+	assert.NoError(os.WriteFile(path, []byte("tampered"), 0600))
+	v = store.Verify(KernelAsset, path)
+	assert.Error(v.Err)
+}
+
+func TestTrustStoreVerifyCache(t *testing.T) {
+	assert := assert.New(t)
+	setupAssetVerificationCache(t)
+
+	storeDir := t.TempDir()
+	path := writeAsset(t, t.TempDir())
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	writePEMKey(t, storeDir, "ed25519.pem", pub)
+	writeSignature(t, path, ed25519.Sign(key, assetContent))
+
+	store, err := LoadTrustStore(storeDir)
+	assert.NoError(err)
+
+	v := store.Verify(KernelAsset, path)
+	assert.NoError(v.Err)
+	assert.False(v.Cached)
+
+	// the signature is not needed anymore once the asset is verified
+	assert.NoError(os.Remove(path + SignatureSuffix))
+
+	v = store.Verify(KernelAsset, path)
+	assert.NoError(v.Err)
+	assert.True(v.Cached)
+
+	// the on-disk cache is shared with the other processes
+	verifiedAssets = sync.Map{}
+	v = store.Verify(KernelAsset, path)
+	assert.NoError(v.Err)
+	assert.True(v.Cached)
+
+	// the cache is ignored when the key is not trusted anymore
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	assert.NoError(os.Remove(filepath.Join(storeDir, "ed25519.pem")))
+	writePEMKey(t, storeDir, "other.pem", otherPub)
+
+	store, err = LoadTrustStore(storeDir)
+	assert.NoError(err)
+
+	v = store.Verify(KernelAsset, path)
+	assert.Error(v.Err)
+}
+
+func TestTrustStoreVerifyCopy(t *testing.T) {
+	assert := assert.New(t)
+	setupAssetVerificationCache(t)
+
+	storeDir := t.TempDir()
+	path := writeAsset(t, t.TempDir())
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	writePEMKey(t, storeDir, "ed25519.pem", pub)
+	writeSignature(t, path, ed25519.Sign(key, assetContent))
+
+	store, err := LoadTrustStore(storeDir)
+	assert.NoError(err)
+
+	v := store.Verify(KernelAsset, path)
+	assert.NoError(v.Err)
+	assert.Equal(filepath.Join(AssetVerificationCacheDir, verifiedAssetsSubdir, assetContentHash), v.VerifiedPath)
+
+	// the verified copy is read-only and keeps the verified content when
+	// the asset is replaced
+	info, err := os.Stat(v.VerifiedPath)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0444), info.Mode().Perm())
+
+	assert.NoError(os.WriteFile(path, []byte("tampered"), 0600))
+	content, err := os.ReadFile(v.VerifiedPath)
+	assert.NoError(err)
+	assert.Equal(assetContent, content)
+
+	// and the replaced asset gets its own copy, which is not verified
+	v = store.Verify(KernelAsset, path)
+	assert.Error(v.Err)
+	assert.NotEqual(assetContentHash, v.Hash)
+	assert.Equal(filepath.Join(AssetVerificationCacheDir, verifiedAssetsSubdir, v.Hash), v.VerifiedPath)
+}
+
+func TestTrustStoreVerifyMinisign(t *testing.T) {
+	assert := assert.New(t)
+	setupAssetVerificationCache(t)
+
+	storeDir := t.TempDir()
+	path := writeAsset(t, t.TempDir())
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	keyID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	pubKey := append(append([]byte(minisignAlgorithm), keyID...), pub...)
+	assert.NoError(os.WriteFile(filepath.Join(storeDir, "minisign.pub"),
+		[]byte(fmt.Sprintf("untrusted comment: minisign public key\n%s\n", base64.StdEncoding.EncodeToString(pubKey))), 0600))
+
+	store, err := LoadTrustStore(storeDir)
+	assert.NoError(err)
+
+	writeMinisig := func(algorithm, trustedComment string) {
+		sig := ed25519.Sign(key, assetContent)
+		block := append(append([]byte(algorithm), keyID...), sig...)
+		globalSig := ed25519.Sign(key, append(append([]byte{}, sig...), "timestamp:1"...))
+
+		data := fmt.Sprintf("untrusted comment: signature\n%s\ntrusted comment: %s\n%s\n",
+			base64.StdEncoding.EncodeToString(block), trustedComment, base64.StdEncoding.EncodeToString(globalSig))
+		assert.NoError(os.WriteFile(path+MinisignSuffix, []byte(data), 0600))
+	}
+
+	writeMinisig(minisignAlgorithm, "timestamp:1")
+	v := store.Verify(ImageAsset, path)
+	assert.NoError(v.Err)
+	assert.Equal("minisign.pub", v.Key)
+
+	verifiedAssets = sync.Map{}
+	assert.NoError(os.RemoveAll(AssetVerificationCacheDir))
+
+	// a tampered trusted comment
+	writeMinisig(minisignAlgorithm, "timestamp:2")
+	v = store.Verify(ImageAsset, path)
+	assert.Error(v.Err)
+
+	// prehashed signatures are not supported
+	writeMinisig(minisignPrehashAlgorithm, "timestamp:1")
+	v = store.Verify(ImageAsset, path)
+	assert.Error(v.Err)
+}
@@ -10,6 +10,7 @@ const (
 	blockDeviceHotplugSupport
 	multiQueueSupport
 	fsSharingSupported
+	blockDeviceResizeSupport
 )
 
 // Capabilities describe a virtcontainers hypervisor capabilities
@@ -57,3 +58,14 @@ func (caps *Capabilities) IsFsSharingSupported() bool {
 func (caps *Capabilities) SetFsSharingSupport() {
 	caps.flags |= fsSharingSupported
 }
+
+// IsBlockDeviceResizeSupported tells if an hypervisor can notify the VM that
+// the file backing an attached block device was resized.
+func (caps *Capabilities) IsBlockDeviceResizeSupported() bool {
+	return caps.flags&blockDeviceResizeSupport != 0
+}
+
+// SetBlockDeviceResizeSupport sets the block device resizing capability to true.
+func (caps *Capabilities) SetBlockDeviceResizeSupport() {
+	caps.flags |= blockDeviceResizeSupport
+}
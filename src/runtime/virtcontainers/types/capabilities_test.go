@@ -43,3 +43,11 @@ func TestMultiQueueCapability(t *testing.T) {
 	caps.SetMultiQueueSupport()
 	assert.True(caps.IsMultiQueueSupported())
 }
+
+func TestBlockDeviceResizeCapability(t *testing.T) {
+	var caps Capabilities
+
+	assert.False(t, caps.IsBlockDeviceResizeSupported())
+	caps.SetBlockDeviceResizeSupport()
+	assert.True(t, caps.IsBlockDeviceResizeSupported())
+}
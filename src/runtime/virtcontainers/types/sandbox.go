@@ -72,6 +72,10 @@ type SandboxState struct {
 
 	// GuestMemoryHotplugProbe determines whether guest kernel supports memory hotplug probe interface
 	GuestMemoryHotplugProbe bool `json:"guestMemoryHotplugProbe"`
+
+	// FrozenContainers are the containers paused by the sandbox freeze,
+	// which are resumed when the sandbox is thawed.
+	FrozenContainers []string `json:"frozenContainers,omitempty"`
 }
 
 // Valid checks that the sandbox state is valid.
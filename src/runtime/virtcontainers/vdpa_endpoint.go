@@ -0,0 +1,238 @@
+//go:build linux
+
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	vcTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+	"github.com/safchain/ethtool"
+)
+
+const vhostVDPADevicePattern = "vhost-vdpa-*"
+
+var vdpaTrace = getNetworkTrace(VDPAEndpointType)
+
+// sysVDPADevicesPath is where the vdpa bus exposes the vDPA devices. A vDPA
+// device bound to the vhost_vdpa driver has a vhost-vdpa-N child entry.
+var sysVDPADevicesPath = "/sys/bus/vdpa/devices"
+
+// ifaceBusInfo returns the bus address of the device of an interface of
+// the current network namespace. We use ethtool here to not rely on device
+// sysfs inside the network namespace.
+var ifaceBusInfo = func(ifaceName string) (string, error) {
+	ethHandle, err := ethtool.NewEthtool()
+	if err != nil {
+		return "", err
+	}
+	defer ethHandle.Close()
+
+	return ethHandle.BusInfo(ifaceName)
+}
+
+// VDPAEndpoint represents a network interface backed by a vDPA device
+// bound to the vhost_vdpa driver on the host.
+type VDPAEndpoint struct {
+	// Path to the vhost-vdpa character device on the host system
+	VhostVDPAPath string
+	// MAC address of the interface
+	HardAddr           string
+	IfaceName          string
+	EndpointProperties NetworkInfo
+	EndpointType       EndpointType
+	PCIPath            vcTypes.PciPath
+}
+
+// Properties returns the properties of the interface.
+func (endpoint *VDPAEndpoint) Properties() NetworkInfo {
+	return endpoint.EndpointProperties
+}
+
+// Name returns name of the interface.
+func (endpoint *VDPAEndpoint) Name() string {
+	return endpoint.IfaceName
+}
+
+// HardwareAddr returns the mac address of the vDPA network interface.
+func (endpoint *VDPAEndpoint) HardwareAddr() string {
+	return endpoint.HardAddr
+}
+
+// Type indentifies the endpoint as a vDPA endpoint.
+func (endpoint *VDPAEndpoint) Type() EndpointType {
+	return endpoint.EndpointType
+}
+
+// SetProperties sets the properties of the endpoint.
+func (endpoint *VDPAEndpoint) SetProperties(properties NetworkInfo) {
+	endpoint.EndpointProperties = properties
+}
+
+// PciPath returns the PCI path of the endpoint.
+func (endpoint *VDPAEndpoint) PciPath() vcTypes.PciPath {
+	return endpoint.PCIPath
+}
+
+// SetPciPath sets the PCI path of the endpoint.
+func (endpoint *VDPAEndpoint) SetPciPath(pciPath vcTypes.PciPath) {
+	endpoint.PCIPath = pciPath
+}
+
+// NetworkPair returns the network pair of the endpoint.
+func (endpoint *VDPAEndpoint) NetworkPair() *NetworkInterfacePair {
+	return nil
+}
+
+// netdevID returns the identifier of the endpoint in the hypervisor.
+func (endpoint *VDPAEndpoint) netdevID() string {
+	return "vdpa-" + endpoint.IfaceName
+}
+
+// Attach for vDPA endpoint adds the vhost-vdpa device to the hypervisor.
+func (endpoint *VDPAEndpoint) Attach(ctx context.Context, s *Sandbox) error {
+	span, ctx := vdpaTrace(ctx, "Attach", endpoint)
+	defer span.End()
+
+	return s.hypervisor.AddDevice(ctx, endpoint, NetDev)
+}
+
+// Detach for vDPA endpoint. The vhost-vdpa device is released by the
+// hypervisor, there is nothing to tear down on the host.
+func (endpoint *VDPAEndpoint) Detach(ctx context.Context, netNsCreated bool, netNsPath string) error {
+	return nil
+}
+
+// HotAttach for vDPA endpoint hotplugs the vhost-vdpa device.
+func (endpoint *VDPAEndpoint) HotAttach(ctx context.Context, h Hypervisor) error {
+	networkLogger().Info("Hot attaching vdpa endpoint")
+
+	span, ctx := vdpaTrace(ctx, "HotAttach", endpoint)
+	defer span.End()
+
+	if _, err := h.HotplugAddDevice(ctx, endpoint, NetDev); err != nil {
+		networkLogger().WithError(err).Error("Error attach vdpa ep")
+		return err
+	}
+	return nil
+}
+
+// HotDetach for vDPA endpoint hot unplugs the vhost-vdpa device.
+func (endpoint *VDPAEndpoint) HotDetach(ctx context.Context, h Hypervisor, netNsCreated bool, netNsPath string) error {
+	networkLogger().Info("Hot detaching vdpa endpoint")
+
+	span, ctx := vdpaTrace(ctx, "HotDetach", endpoint)
+	defer span.End()
+
+	if _, err := h.HotplugRemoveDevice(ctx, endpoint, NetDev); err != nil {
+		networkLogger().WithError(err).Error("Error detach vdpa ep")
+		return err
+	}
+	return nil
+}
+
+// Create a vDPA endpoint
+func createVDPAEndpoint(netInfo NetworkInfo, vhostVDPAPath string) (*VDPAEndpoint, error) {
+	vdpaEndpoint := &VDPAEndpoint{
+		VhostVDPAPath: vhostVDPAPath,
+		HardAddr:      netInfo.Iface.HardwareAddr.String(),
+		IfaceName:     netInfo.Iface.Name,
+		EndpointType:  VDPAEndpointType,
+	}
+	return vdpaEndpoint, nil
+}
+
+// findVhostVDPADevice returns the vhost-vdpa character device of the
+// vDPA device found at vdpaDevicePattern, if any.
+func findVhostVDPADevice(vdpaDevicePattern string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(vdpaDevicePattern, vhostVDPADevicePattern))
+	if err != nil {
+		return "", err
+	}
+
+	if len(matches) == 0 {
+		return "", nil
+	}
+
+	return filepath.Join("/dev", filepath.Base(matches[0])), nil
+}
+
+// vhostVDPAPath checks if an interface is backed by a vDPA device bound to
+// vhost_vdpa, and if it is it returns the path to the vhost-vdpa device.
+//
+// Two layouts are supported:
+//   - a dummy placeholder interface named after the vDPA device, as used
+//     with the vdpa_sim_net simulator,
+//   - a PCI function, typically a VF, which is the parent of the vDPA device.
+func vhostVDPAPath(netInfo NetworkInfo) (string, error) {
+	if netInfo.Iface.Name == "lo" {
+		return "", nil
+	}
+
+	path, err := findVhostVDPADevice(filepath.Join(sysVDPADevicesPath, netInfo.Iface.Name))
+	if err != nil || path != "" {
+		return path, err
+	}
+
+	// The device of the interface, if any, is its parent on its bus.
+	bdf, err := ifaceBusInfo(netInfo.Iface.Name)
+	if err != nil {
+		return "", nil
+	}
+
+	// Check for a pci bus format
+	if len(strings.Split(bdf, ":")) != 3 {
+		return "", nil
+	}
+
+	return findVhostVDPADevice(filepath.Join(sysPCIDevicesPath, bdf, "vdpa*"))
+}
+
+func (endpoint *VDPAEndpoint) save() persistapi.NetworkEndpoint {
+	return persistapi.NetworkEndpoint{
+		Type: string(endpoint.Type()),
+		VDPA: &persistapi.VDPAEndpoint{
+			IfaceName:     endpoint.IfaceName,
+			HardAddr:      endpoint.HardAddr,
+			VhostVDPAPath: endpoint.VhostVDPAPath,
+			PCIPath:       endpoint.PCIPath,
+		},
+	}
+}
+
+func (endpoint *VDPAEndpoint) load(s persistapi.NetworkEndpoint) {
+	endpoint.EndpointType = VDPAEndpointType
+
+	if s.VDPA != nil {
+		endpoint.IfaceName = s.VDPA.IfaceName
+		endpoint.HardAddr = s.VDPA.HardAddr
+		endpoint.VhostVDPAPath = s.VDPA.VhostVDPAPath
+		endpoint.PCIPath = s.VDPA.PCIPath
+	}
+}
+
+// unsupported
+func (endpoint *VDPAEndpoint) GetRxRateLimiter() bool {
+	return false
+}
+
+func (endpoint *VDPAEndpoint) SetRxRateLimiter() error {
+	return fmt.Errorf("rx rate limiter is unsupported for vdpa endpoint")
+}
+
+// unsupported
+func (endpoint *VDPAEndpoint) GetTxRateLimiter() bool {
+	return false
+}
+
+func (endpoint *VDPAEndpoint) SetTxRateLimiter() error {
+	return fmt.Errorf("tx rate limiter is unsupported for vdpa endpoint")
+}
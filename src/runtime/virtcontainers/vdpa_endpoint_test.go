@@ -0,0 +1,158 @@
+//go:build linux
+
+// Copyright (c) 2026 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+)
+
+func TestVhostVDPAPath(t *testing.T) {
+	assert := assert.New(t)
+
+	savedSysVDPADevicesPath := sysVDPADevicesPath
+	sysVDPADevicesPath = t.TempDir()
+	defer func() {
+		sysVDPADevicesPath = savedSysVDPADevicesPath
+	}()
+
+	// vdpa_sim_net device bound to vhost_vdpa, with a placeholder interface
+	err := os.MkdirAll(filepath.Join(sysVDPADevicesPath, "vdpa0", "vhost-vdpa-3"), 0755)
+	assert.NoError(err)
+
+	// vDPA device bound to virtio_vdpa
+	err = os.MkdirAll(filepath.Join(sysVDPADevicesPath, "vdpa1", "virtio1"), 0755)
+	assert.NoError(err)
+
+	netinfo := NetworkInfo{
+		Iface: NetlinkIface{
+			LinkAttrs: netlink.LinkAttrs{
+				Name: "vdpa0",
+			},
+		},
+	}
+
+	path, err := vhostVDPAPath(netinfo)
+	assert.NoError(err)
+	assert.Equal("/dev/vhost-vdpa-3", path)
+
+	netinfo.Iface.Name = "vdpa1"
+	path, _ = vhostVDPAPath(netinfo)
+	assert.Empty(path)
+
+	// vDPA device of a PCI VF, found through the bus info of the interface
+	savedIfaceBusInfo := ifaceBusInfo
+	savedSysPCIDevicesPath := sysPCIDevicesPath
+	ifaceBusInfo = func(ifaceName string) (string, error) {
+		switch ifaceName {
+		case "eth1":
+			return "0000:3b:00.2", nil
+		case "veth0":
+			return "N/A", nil
+		}
+		return "", fmt.Errorf("no such interface %s", ifaceName)
+	}
+	sysPCIDevicesPath = t.TempDir()
+	defer func() {
+		ifaceBusInfo = savedIfaceBusInfo
+		sysPCIDevicesPath = savedSysPCIDevicesPath
+	}()
+
+	err = os.MkdirAll(filepath.Join(sysPCIDevicesPath, "0000:3b:00.2", "vdpa2", "vhost-vdpa-4"), 0755)
+	assert.NoError(err)
+
+	netinfo.Iface.Name = "eth1"
+	path, err = vhostVDPAPath(netinfo)
+	assert.NoError(err)
+	assert.Equal("/dev/vhost-vdpa-4", path)
+
+	// virtual interfaces have no PCI device
+	netinfo.Iface.Name = "veth0"
+	path, err = vhostVDPAPath(netinfo)
+	assert.NoError(err)
+	assert.Empty(path)
+
+	netinfo.Iface.Name = "lo"
+	path, err = vhostVDPAPath(netinfo)
+	assert.NoError(err)
+	assert.Empty(path)
+}
+
+func TestCreateVDPAEndpoint(t *testing.T) {
+	macAddr := net.HardwareAddr{0x02, 0x00, 0xCA, 0xFE, 0x00, 0x48}
+	ifcName := "vdpa0"
+	vhostVDPAPath := "/dev/vhost-vdpa-0"
+	assert := assert.New(t)
+
+	netinfo := NetworkInfo{
+		Iface: NetlinkIface{
+			LinkAttrs: netlink.LinkAttrs{
+				HardwareAddr: macAddr,
+				Name:         ifcName,
+			},
+		},
+	}
+
+	expected := &VDPAEndpoint{
+		VhostVDPAPath: vhostVDPAPath,
+		HardAddr:      macAddr.String(),
+		IfaceName:     ifcName,
+		EndpointType:  VDPAEndpointType,
+	}
+
+	result, err := createVDPAEndpoint(netinfo, vhostVDPAPath)
+	assert.NoError(err)
+	assert.Exactly(result, expected)
+	assert.Equal("vdpa-vdpa0", result.netdevID())
+}
+
+func TestVDPAEndpointAttach(t *testing.T) {
+	assert := assert.New(t)
+	v := &VDPAEndpoint{
+		VhostVDPAPath: "/dev/vhost-vdpa-0",
+		HardAddr:      "mac-addr",
+		EndpointType:  VDPAEndpointType,
+	}
+
+	s := &Sandbox{
+		hypervisor: &mockHypervisor{},
+	}
+
+	err := v.Attach(context.Background(), s)
+	assert.NoError(err)
+
+	h := &mockHypervisor{}
+	assert.NoError(v.HotAttach(context.Background(), h))
+	assert.NoError(v.HotDetach(context.Background(), h, true, ""))
+	assert.NoError(v.Detach(context.Background(), true, ""))
+}
+
+func TestVDPAEndpointSaveLoad(t *testing.T) {
+	assert := assert.New(t)
+	v := &VDPAEndpoint{
+		VhostVDPAPath: "/dev/vhost-vdpa-0",
+		HardAddr:      "02:00:ca:fe:00:48",
+		IfaceName:     "vdpa0",
+		EndpointType:  VDPAEndpointType,
+		PCIPath:       testPCIPath,
+	}
+
+	saved := v.save()
+	assert.Equal(string(VDPAEndpointType), saved.Type)
+
+	loaded := &VDPAEndpoint{}
+	loaded.load(saved)
+	assert.Exactly(v, loaded)
+}
@@ -16,6 +16,7 @@ import (
 	"syscall"
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -69,6 +70,8 @@ type virtiofsd struct {
 	sourcePath string
 	// extraArgs list of extra args to append to virtiofsd command
 	extraArgs []string
+	// userNamespace runs virtiofsd as root of a new user namespace
+	userNamespace bool
 	// PID process ID of virtiosd process
 	PID int
 }
@@ -112,6 +115,9 @@ func (v *virtiofsd) Start(ctx context.Context, onQuit onQuitFunc) (int, error) {
 	}
 
 	cmd := exec.Command(v.path)
+	if v.userNamespace {
+		cmd.SysProcAttr = rootless.UserNamespaceSysProcAttr()
+	}
 
 	socketFD, err := v.getSocketFD()
 	if err != nil {
@@ -99,6 +99,10 @@ func NewVM(ctx context.Context, config VMConfig) (*VM, error) {
 		return nil, err
 	}
 
+	if err = config.HypervisorConfig.verifyAssets(); err != nil {
+		return nil, err
+	}
+
 	id := uuid.Generate().String()
 
 	virtLog.WithField("vm", id).WithField("config", config).Info("create new vm")
@@ -190,6 +190,11 @@ static AGENT_CMDS: &[AgentCmd] = &[
         st: ServiceType::Agent,
         fp: agent_cmd_sandbox_list_interfaces,
     },
+    AgentCmd {
+        name: "ListProcesses",
+        st: ServiceType::Agent,
+        fp: agent_cmd_container_list_processes,
+    },
     AgentCmd {
         name: "ListRoutes",
         st: ServiceType::Agent,
@@ -1154,6 +1159,37 @@ fn agent_cmd_container_stats(
     Ok(())
 }
 
+fn agent_cmd_container_list_processes(
+    ctx: &Context,
+    client: &AgentServiceClient,
+    _health: &HealthClient,
+    _image: &ImageClient,
+    options: &mut Options,
+    args: &str,
+) -> Result<()> {
+    let mut req: ListProcessesRequest = utils::make_request(args)?;
+
+    let ctx = clone_context(ctx);
+
+    run_if_auto_values!(ctx, || -> Result<()> {
+        let cid = utils::get_option("cid", options, args)?;
+
+        req.set_container_id(cid);
+        Ok(())
+    });
+
+    debug!(sl!(), "sending request"; "request" => format!("{:?}", req));
+
+    let reply = client
+        .list_processes(ctx, &req)
+        .map_err(|e| anyhow!("{:?}", e).context(ERR_API_FAILED))?;
+
+    info!(sl!(), "response received";
+        "response" => format!("{:?}", reply));
+
+    Ok(())
+}
+
 fn agent_cmd_container_pause(
     ctx: &Context,
     client: &AgentServiceClient,